@@ -0,0 +1,289 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+// This file implements a reader and writer for the Go benchmark
+// data format described at https://go.dev/design/14313-benchmark-format,
+// which is what modern versions of 'go test -bench' produce.
+//
+// Unlike ParseLine, which recognizes only a fixed set of units, the
+// Reader records every (value, unit) pair on a result line, along
+// with the configuration lines in effect when the result was read.
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Result is a single benchmark result line together with the
+// configuration in effect at the point it was read.
+type Result struct {
+	Config []Config // configuration lines in effect, in order of first appearance
+	Name   Name     // full benchmark name, including the "Benchmark" prefix
+	Iters  int      // number of iterations
+	Values []Value  // measurements, in the order they appeared
+	Line   int      // 1-based line number in the input
+}
+
+// A Config is a single "key: value" configuration line.
+type Config struct {
+	Key   string
+	Value string
+}
+
+// A Value is a single measurement on a result line, such as "19.6 ns/op".
+type Value struct {
+	Value float64
+	Unit  string
+}
+
+// A Name is the full name of a benchmark, such as
+// "BenchmarkDecode/text=digits/level=speed/size=1e4-8".
+type Name string
+
+// Base returns the base name of the benchmark, without the
+// "Benchmark" prefix, sub-benchmark parts, or GOMAXPROCS suffix.
+// For example, the base of "BenchmarkDecode/size=1e4-8" is "Decode".
+func (n Name) Base() string {
+	base, _, _ := n.Parts()
+	return base
+}
+
+// Parts splits the name into its base name (without the "Benchmark"
+// prefix), the slash-separated sub-benchmark components, and the
+// GOMAXPROCS suffix, if any (for example "-8"), which is attached to
+// the final component by the testing package.
+func (n Name) Parts() (base string, sub []string, procs string) {
+	s := strings.TrimPrefix(string(n), "Benchmark")
+	if i := strings.LastIndexByte(s, '-'); i >= 0 && i > strings.LastIndexByte(s, '/') {
+		if _, err := strconv.Atoi(s[i+1:]); err == nil && i+1 < len(s) {
+			s, procs = s[:i], s[i:]
+		}
+	}
+	parts := strings.Split(s, "/")
+	return parts[0], parts[1:], procs
+}
+
+// Value returns the value of the measurement with the given unit.
+// It reports false if the result has no such measurement.
+func (r *Result) Value(unit string) (float64, bool) {
+	for _, v := range r.Values {
+		if v.Unit == unit {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+// GetConfig returns the value of the configuration key in effect
+// for the result, or "" if the key was not set.
+func (r *Result) GetConfig(key string) string {
+	for _, c := range r.Config {
+		if c.Key == key {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// String returns the result formatted as a benchmark result line,
+// without its configuration.
+func (r *Result) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%s %d", r.Name, r.Iters)
+	for _, v := range r.Values {
+		fmt.Fprintf(&buf, " %s %s", strconv.FormatFloat(v.Value, 'f', -1, 64), v.Unit)
+	}
+	return buf.String()
+}
+
+// A Reader reads benchmark results in the Go benchmark data format.
+// Lines that are neither configuration lines nor result lines are
+// ignored.
+type Reader struct {
+	scan   *bufio.Scanner
+	line   int
+	config []Config
+	result *Result
+	err    error
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scan: bufio.NewScanner(r)}
+}
+
+// Next advances the reader to the next result, which is then
+// available through the Result method. It returns false when there
+// are no more results, either because the input is exhausted or an
+// error occurred; in the latter case Err reports the error.
+func (r *Reader) Next() bool {
+	for r.scan.Scan() {
+		r.line++
+		line := r.scan.Text()
+		if key, value, ok := parseConfigLine(line); ok {
+			r.setConfig(key, value)
+			continue
+		}
+		if res, err := parseResultLine(line); err == nil {
+			res.Config = r.config
+			res.Line = r.line
+			r.result = res
+			return true
+		}
+	}
+	r.err = r.scan.Err()
+	r.result = nil
+	return false
+}
+
+// Result returns the most recent result read by Next.
+// The caller must not modify the result's Config slice.
+func (r *Reader) Result() *Result {
+	return r.result
+}
+
+// Err returns the first I/O error encountered by Next, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// setConfig records a configuration line. Configuration slices
+// previously returned in results are never mutated, so results may
+// share them.
+func (r *Reader) setConfig(key, value string) {
+	config := make([]Config, 0, len(r.config)+1)
+	found := false
+	for _, c := range r.config {
+		if c.Key == key {
+			c.Value = value
+			found = true
+		}
+		// An empty value deletes the key.
+		if c.Value != "" {
+			config = append(config, c)
+		}
+	}
+	if !found && value != "" {
+		config = append(config, Config{key, value})
+	}
+	r.config = config
+}
+
+// parseConfigLine reports whether line is a configuration line of
+// the form "key: value", where key begins with a lower-case letter
+// and contains no space or upper-case characters.
+func parseConfigLine(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok || key == "" {
+		return "", "", false
+	}
+	if r, _ := utf8.DecodeRuneInString(key); !unicode.IsLower(r) {
+		return "", "", false
+	}
+	for _, r := range key {
+		if unicode.IsSpace(r) || unicode.IsUpper(r) {
+			return "", "", false
+		}
+	}
+	// The value may be empty, but otherwise must be separated
+	// from the colon by white space.
+	if value != "" && !strings.HasPrefix(value, " ") && !strings.HasPrefix(value, "\t") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// parseResultLine parses a benchmark result line.
+func parseResultLine(line string) (*Result, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("two fields required, have %d", len(fields))
+	}
+	name := fields[0]
+	if !strings.HasPrefix(name, "Benchmark") {
+		return nil, fmt.Errorf(`first field does not start with "Benchmark"`)
+	}
+	// The name must be "Benchmark" or continue with a non-lower-case character.
+	if r, _ := utf8.DecodeRuneInString(name[len("Benchmark"):]); unicode.IsLower(r) {
+		return nil, fmt.Errorf("invalid benchmark name %q", name)
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("missing unit for value %q", fields[len(fields)-1])
+	}
+	res := &Result{Name: Name(name), Iters: n}
+	for i := 2; i < len(fields); i += 2 {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %v", fields[i], err)
+		}
+		res.Values = append(res.Values, Value{Value: f, Unit: fields[i+1]})
+	}
+	return res, nil
+}
+
+// A Writer writes benchmark results in the Go benchmark data format.
+// Before each result it emits configuration lines for any keys
+// whose values differ from those last written.
+type Writer struct {
+	w      io.Writer
+	config []Config
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes a result, preceded by any changed configuration.
+func (w *Writer) Write(r *Result) error {
+	var config strings.Builder
+	// Delete keys that are no longer present.
+	for _, old := range w.config {
+		if !hasConfig(r.Config, old.Key) {
+			fmt.Fprintf(&config, "%s:\n", old.Key)
+		}
+	}
+	// Add or update keys whose values changed.
+	for _, c := range r.Config {
+		if prev, ok := lookupConfig(w.config, c.Key); !ok || prev != c.Value {
+			fmt.Fprintf(&config, "%s: %s\n", c.Key, c.Value)
+		}
+	}
+	var buf strings.Builder
+	if config.Len() > 0 && w.config != nil {
+		// Separate configuration blocks from preceding results.
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(config.String())
+	buf.WriteString(r.String())
+	buf.WriteByte('\n')
+	w.config = append(w.config[:0:0], r.Config...)
+	_, err := io.WriteString(w.w, buf.String())
+	return err
+}
+
+func hasConfig(config []Config, key string) bool {
+	_, ok := lookupConfig(config, key)
+	return ok
+}
+
+func lookupConfig(config []Config, key string) (string, bool) {
+	for _, c := range config {
+		if c.Key == key {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
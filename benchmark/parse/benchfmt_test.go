@@ -0,0 +1,129 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	in := `goos: linux
+goarch: amd64
+pkg: example.com/enc
+cpu: Intel(R) Xeon(R) CPU @ 2.20GHz
+BenchmarkEncrypt-8   	100000000	        19.6 ns/op	 817.77 MB/s
+PASS
+BenchmarkDecode/text=digits/size=1e4-8 	 5000	  3000 ns/op	  12 B/op	  1 allocs/op	  0.5 ratio
+Benchmarkbogus 1 2 ns/op
+pkg: example.com/dec
+cpu:
+BenchmarkOdd 10 1 ns/op 2
+ok  	example.com/enc	1.234s
+BenchmarkAgain 10 1 ns/op
+`
+	r := NewReader(strings.NewReader(in))
+	var got []*Result
+	for r.Next() {
+		got = append(got, r.Result())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	config1 := []Config{
+		{"goos", "linux"},
+		{"goarch", "amd64"},
+		{"pkg", "example.com/enc"},
+		{"cpu", "Intel(R) Xeon(R) CPU @ 2.20GHz"},
+	}
+	config2 := []Config{
+		{"goos", "linux"},
+		{"goarch", "amd64"},
+		{"pkg", "example.com/dec"},
+	}
+	want := []*Result{
+		{
+			Config: config1,
+			Name:   "BenchmarkEncrypt-8",
+			Iters:  100000000,
+			Values: []Value{{19.6, "ns/op"}, {817.77, "MB/s"}},
+			Line:   5,
+		},
+		{
+			Config: config1,
+			Name:   "BenchmarkDecode/text=digits/size=1e4-8",
+			Iters:  5000,
+			Values: []Value{{3000, "ns/op"}, {12, "B/op"}, {1, "allocs/op"}, {0.5, "ratio"}},
+			Line:   7,
+		},
+		{
+			Config: config2,
+			Name:   "BenchmarkAgain",
+			Iters:  10,
+			Values: []Value{{1, "ns/op"}},
+			Line:   13,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader results:\ngot  %+v\nwant %+v", got, want)
+	}
+	if v, ok := got[1].Value("ratio"); !ok || v != 0.5 {
+		t.Errorf("Value(ratio) = %v, %v, want 0.5, true", v, ok)
+	}
+	if v := got[2].GetConfig("cpu"); v != "" {
+		t.Errorf("GetConfig(cpu) = %q, want deleted", v)
+	}
+}
+
+func TestName(t *testing.T) {
+	for _, test := range []struct {
+		name  Name
+		base  string
+		sub   []string
+		procs string
+	}{
+		{"BenchmarkEncrypt", "Encrypt", []string{}, ""},
+		{"BenchmarkEncrypt-8", "Encrypt", []string{}, "-8"},
+		{"BenchmarkDecode/text=digits/size=1e4-8", "Decode", []string{"text=digits", "size=1e4"}, "-8"},
+		{"BenchmarkA-B/c", "A-B", []string{"c"}, ""},
+	} {
+		base, sub, procs := test.name.Parts()
+		if base != test.base || !reflect.DeepEqual(sub, test.sub) || procs != test.procs {
+			t.Errorf("%s.Parts() = %q, %q, %q, want %q, %q, %q",
+				test.name, base, sub, procs, test.base, test.sub, test.procs)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	in := `goos: linux
+pkg: a
+BenchmarkX 1 2 ns/op 3 widgets/op
+BenchmarkY 4 5.5 ns/op
+pkg: b
+BenchmarkZ 6 7 ns/op
+`
+	want := `goos: linux
+pkg: a
+BenchmarkX 1 2 ns/op 3 widgets/op
+BenchmarkY 4 5.5 ns/op
+
+pkg: b
+BenchmarkZ 6 7 ns/op
+`
+	var out strings.Builder
+	r := NewReader(strings.NewReader(in))
+	w := NewWriter(&out)
+	for r.Next() {
+		if err := w.Write(r.Result()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := out.String(); got != want {
+		t.Errorf("Writer output:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -4,6 +4,11 @@
 
 // Package parse provides support for parsing benchmark results as
 // generated by 'go test -bench'.
+//
+// ParseLine and ParseSet recognize only the classic measurements
+// (ns/op, MB/s, B/op, allocs/op). The Reader and Writer types
+// support the full Go benchmark data format, including custom units,
+// configuration lines, and sub-benchmark names.
 package parse // import "golang.org/x/tools/benchmark/parse"
 
 import (
@@ -46,6 +46,8 @@ var (
 	whyLiveFlag   = flag.String("whylive", "", "show a path from main to the named function")
 	formatFlag    = flag.String("f", "", "format output records using template")
 	jsonFlag      = flag.Bool("json", false, "output JSON records")
+	baselineFlag  = flag.String("baseline", "", "report only dead functions not recorded in this JSON baseline file")
+	writeBaseline = flag.Bool("write-baseline", false, "write the dead functions to the -baseline file instead of reporting them")
 	cpuProfile    = flag.String("cpuprofile", "", "write CPU profile to this file")
 	memProfile    = flag.String("memprofile", "", "write memory profile to this file")
 )
@@ -110,6 +112,24 @@ func main() {
 			log.Fatalf("invalid -f: %v", err)
 		}
 	}
+	if *writeBaseline {
+		if *baselineFlag == "" {
+			log.Fatalf("-write-baseline requires -baseline=file")
+		}
+		if *whyLiveFlag != "" {
+			log.Fatalf("you cannot specify both -write-baseline and -whylive")
+		}
+	}
+
+	// Read the baseline, if any, unless we are about to replace it.
+	var baseline map[string]bool
+	if *baselineFlag != "" && !*writeBaseline {
+		var err error
+		baseline, err = readBaseline(*baselineFlag)
+		if err != nil {
+			log.Fatalf("-baseline: %v", err)
+		}
+	}
 
 	// Load, parse, and type-check the complete program(s).
 	cfg := &packages.Config{
@@ -325,8 +345,14 @@ func main() {
 				continue
 			}
 
+			// Skip functions that were already dead in the baseline.
+			name := prettyName(fn, false)
+			if baseline[pkgpath+"."+name] {
+				continue
+			}
+
 			functions = append(functions, jsonFunction{
-				Name:      prettyName(fn, false),
+				Name:      name,
 				Position:  toJSONPosition(posn),
 				Generated: gen,
 			})
@@ -340,6 +366,17 @@ func main() {
 		}
 	}
 
+	if *writeBaseline {
+		data, err := json.MarshalIndent(packages, "", "\t")
+		if err != nil {
+			log.Fatalf("internal error: %v", err)
+		}
+		if err := os.WriteFile(*baselineFlag, append(data, '\n'), 0666); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Default line-oriented format: "a/b/c.go:1:2: unreachable func: T.f"
 	format := `{{range .Funcs}}{{printf "%s: unreachable func: %s\n" .Position .Name}}{{end}}`
 	if *formatFlag != "" {
@@ -388,6 +425,31 @@ func prettyName(fn *ssa.Function, qualified bool) string {
 	return buf.String()
 }
 
+// readBaseline reads a baseline file in the format produced by the
+// -json (or -write-baseline) flag and returns the set of
+// package-qualified names of the functions it records as dead, such
+// as "example.com/p.T.f".
+//
+// Functions are identified by name, not position, so that the
+// baseline remains valid as unrelated edits move declarations around.
+func readBaseline(filename string) (map[string]bool, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var packages []jsonPackage
+	if err := json.Unmarshal(data, &packages); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	dead := make(map[string]bool)
+	for _, pkg := range packages {
+		for _, fn := range pkg.Funcs {
+			dead[pkg.Path+"."+fn.Name] = true
+		}
+	}
+	return dead, nil
+}
+
 // printObjects formats an array of objects, either as JSON or using a
 // template, following the manner of 'go list (-json|-f=template)'.
 func printObjects(format string, objects []any) {
@@ -55,6 +55,26 @@ Consider running the tool once for each configuration of interest.
 Consider using a line-oriented output format (see below) to make it
 easier to compute the intersection of results across all runs.
 
+# Baselines
+
+In a large existing program, the number of dead functions may be too
+great to eliminate all at once. A baseline file allows a project to
+adopt the tool gradually, by reporting only functions that have become
+dead since the baseline was recorded. The -write-baseline flag causes
+the tool to record the current set of dead functions in the file named
+by the -baseline flag, instead of reporting them:
+
+	$ deadcode -baseline=deadcode.json -write-baseline ./...
+
+Subsequent runs with the same -baseline flag report only dead functions
+that do not appear in the file:
+
+	$ deadcode -baseline=deadcode.json ./...
+
+The baseline file uses the same format as the output of the -json flag.
+Functions are identified by package path and name, not position, so the
+baseline is unaffected by edits that merely move declarations.
+
 # Output
 
 The command supports three output formats.
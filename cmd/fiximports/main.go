@@ -64,6 +64,27 @@
 //
 // To see the changes fiximports would make without applying them, use
 // the -n flag.
+//
+// # Modules
+//
+// Import comments have no effect in module mode; a module that moves
+// instead changes its module path, and its clients must update both
+// their imports and their go.mod files. With the -module flag,
+// fiximports rewrites the imports of the packages of the main module
+// (by default, all of them) according to the -replace flag and then
+// runs "go mod tidy" to add requirements on the new modules and drop
+// those on the old ones. For example, this command moves a module
+// from example.com/old to example.com/new:
+//
+//	$ fiximports -module -replace 'example.com/old=example.com/new,example.com/old/...=example.com/new/...'
+//
+// The -deprecated flag additionally consults the deprecation notices
+// of the modules required by the main module (see "go help
+// modules"). A notice that names a successor module, such as
+// "Deprecated: use example.com/new instead.", causes imports of the
+// deprecated module and its packages to be rewritten to the
+// successor. Unlike the rest of fiximports, this requires access to
+// the module proxy.
 package main
 
 import (
@@ -84,6 +105,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"golang.org/x/mod/module"
 )
 
 // flags
@@ -93,6 +116,8 @@ var (
 		"a comma-separated list of domains from which packages should not be imported")
 	replaceFlag = flag.String("replace", "",
 		"a comma-separated list of noncanonical=canonical pairs of package paths.  If both items in a pair end with '...', they are treated as path prefixes.")
+	moduleFlag     = flag.Bool("module", false, "module mode: rewrite the packages of the main module and update its go.mod file")
+	deprecatedFlag = flag.Bool("deprecated", false, "in module mode, replace deprecated modules by the successors named in their deprecation notices")
 )
 
 // seams for testing
@@ -104,10 +129,13 @@ var (
 const usage = `fiximports: rewrite import paths to use canonical package names.
 
 Usage: fiximports [-n] package...
+       fiximports -module [-deprecated] [-n] [package...]
 
 The package... arguments specify a list of packages
 in the style of the go tool; see "go help packages".
 Hint: use "all" or "..." to match the entire workspace.
+In module mode, the default is "./...", the packages of
+the main module.
 
 For details, see https://pkg.go.dev/golang.org/x/tools/cmd/fiximports
 
@@ -115,15 +143,31 @@ Flags:
   -n:	       dry run: show changes, but don't apply them
   -baddomains  a comma-separated list of domains from which packages
                should not be imported
+  -replace     a comma-separated list of noncanonical=canonical pairs
+               of package paths; pairs ending in '...' match prefixes
+  -module      module mode: rewrite the packages of the main module,
+               then run "go mod tidy"
+  -deprecated  in module mode, also replace each deprecated module by
+               the successor named in its deprecation notice
 `
 
 func main() {
 	flag.Parse()
 
+	if *moduleFlag {
+		if !fixModule(flag.Args()...) {
+			os.Exit(1)
+		}
+		return
+	}
 	if len(flag.Args()) == 0 {
 		fmt.Fprint(stderr, usage)
 		os.Exit(1)
 	}
+	if *deprecatedFlag {
+		fmt.Fprintf(stderr, "importfix: -deprecated requires -module\n")
+		os.Exit(1)
+	}
 	if !fiximports(flag.Args()...) {
 		os.Exit(1)
 	}
@@ -170,26 +214,9 @@ func fiximports(packages ...string) bool {
 	canonical := make(map[string]canonicalName)
 	domains := strings.Split(*badDomains, ",")
 
-	type replaceItem struct {
-		old, new    string
-		matchPrefix bool
-	}
-	var replace []replaceItem
-	for _, pair := range strings.Split(*replaceFlag, ",") {
-		if pair == "" {
-			continue
-		}
-		words := strings.Split(pair, "=")
-		if len(words) != 2 {
-			fmt.Fprintf(stderr, "importfix: -replace: %q is not of the form \"canonical=noncanonical\".\n", pair)
-			return false
-		}
-		replace = append(replace, replaceItem{
-			old: strings.TrimSuffix(words[0], "..."),
-			new: strings.TrimSuffix(words[1], "..."),
-			matchPrefix: strings.HasSuffix(words[0], "...") &&
-				strings.HasSuffix(words[1], "..."),
-		})
+	replace, ok := parseReplaceFlag()
+	if !ok {
+		return false
 	}
 
 	// Find non-canonical packages and populate importedBy graph.
@@ -224,19 +251,7 @@ func fiximports(packages ...string) bool {
 			}
 		} else {
 			// Is package matched by a -replace item?
-			var newPath string
-			for _, item := range replace {
-				if item.matchPrefix {
-					if strings.HasPrefix(p.ImportPath, item.old) {
-						newPath = item.new + p.ImportPath[len(item.old):]
-						break
-					}
-				} else if p.ImportPath == item.old {
-					newPath = item.new
-					break
-				}
-			}
-			if newPath != "" {
+			if newPath := replacement(replace, p.ImportPath); newPath != "" {
 				newName := packageName[newPath]
 				if newName == "" {
 					newName = filepath.Base(newPath) // a guess
@@ -249,22 +264,15 @@ func fiximports(packages ...string) bool {
 			}
 
 			// Is package matched by a -baddomains item?
-			for _, domain := range domains {
-				slash := strings.Index(p.ImportPath, "/")
-				if slash < 0 {
-					continue // no slash: standard package
-				}
-				if p.ImportPath[:slash] == domain {
-					// Package comes from bad domain and has no import comment.
-					// Report an error each time this package is imported.
-					canonical[p.ImportPath] = canonicalName{}
-
-					// TODO(adonovan): should we make an HTTP request to
-					// see if there's an HTTP redirect, a "go-import" meta tag,
-					// or an import comment in the latest revision?
-					// It would duplicate a lot of logic from "go get".
-				}
-				break
+			if fromBadDomain(domains, p.ImportPath) {
+				// Package comes from bad domain and has no import comment.
+				// Report an error each time this package is imported.
+				canonical[p.ImportPath] = canonicalName{}
+
+				// TODO(adonovan): should we make an HTTP request to
+				// see if there's an HTTP redirect, a "go-import" meta tag,
+				// or an import comment in the latest revision?
+				// It would duplicate a lot of logic from "go get".
 			}
 		}
 	}
@@ -299,7 +307,6 @@ func fiximports(packages ...string) bool {
 	}
 
 	// Rewrite selected client packages.
-	ok := true
 	for client := range clients {
 		if !rewritePackage(client, canonical) {
 			ok = false
@@ -347,6 +354,185 @@ func fiximports(packages ...string) bool {
 	return ok
 }
 
+// fixModule is the module-mode counterpart of fiximports: it fixes
+// imports in the specified packages of the main module, then tidies
+// the module's requirements.
+// Invariant: a false result implies an error was already printed.
+func fixModule(packages ...string) bool {
+	replace, ok := parseReplaceFlag()
+	if !ok {
+		return false
+	}
+	if *deprecatedFlag {
+		mods, err := listModules()
+		if err != nil {
+			fmt.Fprintf(stderr, "importfix: %v\n", err)
+			return false
+		}
+		for _, m := range mods {
+			if m.Main || m.Deprecated == "" {
+				continue
+			}
+			if succ := successor(m.Deprecated); succ != "" && succ != m.Path {
+				replace = append(replace,
+					replaceItem{old: m.Path, new: succ},
+					replaceItem{old: m.Path + "/", new: succ + "/", matchPrefix: true})
+			}
+		}
+	}
+
+	// "all" means something else in module mode;
+	// treat it, like "...", as the whole main module.
+	if len(packages) == 0 || len(packages) == 1 && (packages[0] == "all" || packages[0] == "...") {
+		packages = []string{"./..."}
+	}
+	pkgs, err := list(append([]string{"-deps"}, packages...)...)
+	if err != nil {
+		fmt.Fprintf(stderr, "importfix: %v\n", err)
+		return false
+	}
+
+	// packageName maps each package's path to its name.
+	packageName := make(map[string]string)
+	for _, p := range pkgs {
+		packageName[p.ImportPath] = p.Name
+	}
+
+	// canonical maps each non-canonical path imported by the
+	// selected packages to its canonical path and name, as in
+	// fiximports. Import comments are ignored in module mode.
+	canonical := make(map[string]canonicalName)
+	domains := strings.Split(*badDomains, ",")
+	var clients []*listPackage
+	for _, p := range pkgs {
+		if p.DepOnly || p.Module == nil || !p.Module.Main {
+			continue // not selected, or not in the main module
+		}
+		if p.Error != nil {
+			fmt.Fprintln(stderr, p.Error)
+		}
+		isClient := false
+		for _, imps := range [][]string{p.Imports, p.TestImports, p.XTestImports} {
+			for _, imp := range imps {
+				if _, ok := canonical[imp]; ok {
+					isClient = true
+				} else if newPath := replacement(replace, imp); newPath != "" {
+					// Assume the package keeps its name.
+					name := packageName[imp]
+					if name == "" {
+						name = packageName[newPath]
+					}
+					if name == "" {
+						name = path.Base(newPath) // a guess
+					}
+					canonical[imp] = canonicalName{path: newPath, name: name}
+					isClient = true
+				} else if fromBadDomain(domains, imp) {
+					canonical[imp] = canonicalName{}
+					isClient = true
+				}
+			}
+		}
+		if isClient {
+			clients = append(clients, p)
+		}
+	}
+
+	// Rewrite client packages.
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ImportPath < clients[j].ImportPath
+	})
+	for _, client := range clients {
+		if !rewritePackage(client, canonical) {
+			ok = false
+		}
+	}
+
+	// Update the requirements of go.mod to match the new imports.
+	if len(clients) > 0 && !*dryrun {
+		if err := runGo("mod", "tidy"); err != nil {
+			fmt.Fprintf(stderr, "importfix: go mod tidy: %v\n", err)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// A replaceItem is an element of the -replace flag.
+type replaceItem struct {
+	old, new    string
+	matchPrefix bool
+}
+
+// parseReplaceFlag parses the -replace flag.
+// Invariant: a false result implies an error was already printed.
+func parseReplaceFlag() ([]replaceItem, bool) {
+	var replace []replaceItem
+	for _, pair := range strings.Split(*replaceFlag, ",") {
+		if pair == "" {
+			continue
+		}
+		words := strings.Split(pair, "=")
+		if len(words) != 2 {
+			fmt.Fprintf(stderr, "importfix: -replace: %q is not of the form \"canonical=noncanonical\".\n", pair)
+			return nil, false
+		}
+		replace = append(replace, replaceItem{
+			old: strings.TrimSuffix(words[0], "..."),
+			new: strings.TrimSuffix(words[1], "..."),
+			matchPrefix: strings.HasSuffix(words[0], "...") &&
+				strings.HasSuffix(words[1], "..."),
+		})
+	}
+	return replace, true
+}
+
+// replacement returns the path that replaces importPath according to
+// the first matching item of replace, or "" if none matches.
+func replacement(replace []replaceItem, importPath string) string {
+	for _, item := range replace {
+		if item.matchPrefix {
+			if strings.HasPrefix(importPath, item.old) {
+				return item.new + importPath[len(item.old):]
+			}
+		} else if importPath == item.old {
+			return item.new
+		}
+	}
+	return ""
+}
+
+// fromBadDomain reports whether importPath belongs to one of domains.
+func fromBadDomain(domains []string, importPath string) bool {
+	slash := strings.Index(importPath, "/")
+	if slash < 0 {
+		return false // no slash: standard package
+	}
+	for _, domain := range domains {
+		if importPath[:slash] == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// successor returns the path of the module recommended by a module
+// deprecation notice, such as "example.com/new" for "Use
+// example.com/new instead.", or "" if it names no module.
+// The first word that is a valid module path containing a slash wins.
+func successor(deprecation string) string {
+	for _, word := range strings.Fields(deprecation) {
+		word = strings.Trim(word, "\"'`()[]<>,;:")
+		word = strings.TrimRight(word, ".")
+		word = strings.TrimPrefix(word, "https://")
+		if strings.Contains(word, "/") && module.CheckPath(word) == nil {
+			return word
+		}
+	}
+	return ""
+}
+
 // Invariant: false result => error already printed.
 func rewritePackage(client *listPackage, canonical map[string]canonicalName) bool {
 	ok := true
@@ -461,9 +647,19 @@ type listPackage struct {
 	TestImports   []string
 	XTestImports  []string
 	ImportComment string
+	DepOnly       bool          // package is only a dependency, not explicitly listed
+	Module        *listModule   // module containing package, if any
 	Error         *packageError // error loading package
 }
 
+// listModule corresponds to the output of go list -m -json,
+// but only the fields we need.
+type listModule struct {
+	Path       string
+	Main       bool
+	Deprecated string // deprecation message, if any (with -u)
+}
+
 // A packageError describes an error loading information about a package.
 type packageError struct {
 	ImportStack []string // shortest path from package named on command line to this one
@@ -502,6 +698,39 @@ func list(args ...string) ([]*listPackage, error) {
 	return pkgs, nil
 }
 
+// listModules runs 'go list -m -u -json all' and returns the
+// metadata for the main module and its dependencies.
+func listModules() ([]*listModule, error) {
+	cmd := exec.Command("go", "list", "-m", "-u", "-json", "all")
+	cmd.Stdout = new(bytes.Buffer)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(cmd.Stdout.(io.Reader))
+	var mods []*listModule
+	for {
+		var m listModule
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		mods = append(mods, &m)
+	}
+	return mods, nil
+}
+
+// runGo runs the go command with the specified arguments,
+// sending its output to stderr.
+func runGo(args ...string) error {
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
 // cwd contains the current working directory of the tool.
 //
 // It is initialized directly so that its value will be set for any other
@@ -265,3 +265,115 @@ func TestDryRun(t *testing.T) {
 		t.Fatalf("fiximports failed: %s", stderr)
 	}
 }
+
+// TestModule tests the -module flag, which rewrites the imports of
+// the main module and updates its go.mod file.
+func TestModule(t *testing.T) {
+	testenv.NeedsTool(t, "go")
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.21
+
+require old.example/lib v0.0.0-00010101000000-000000000000
+
+replace old.example/lib => ./old
+
+replace new.example/lib => ./new
+`,
+		"app.go": `package app
+
+import "old.example/lib"
+
+var _ = lib.F
+`,
+		"sub/sub.go": `package sub
+
+import _ "old.example/lib/sub"
+`,
+		"old/go.mod":     "module old.example/lib\n\ngo 1.21\n",
+		"old/lib.go":     "package lib\n\nfunc F() {}\n",
+		"old/sub/sub.go": "package sub\n",
+		"new/go.mod":     "module new.example/lib\n\ngo 1.21\n",
+		"new/lib.go":     "package lib\n\nfunc F() {}\n",
+		"new/sub/sub.go": "package sub\n",
+	} {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("GO111MODULE", "on")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOMODCACHE", t.TempDir())
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	*moduleFlag = true
+	*replaceFlag = "old.example/lib=new.example/lib,old.example/lib/...=new.example/lib/..."
+	defer func() {
+		stderr = os.Stderr
+		writeFile = os.WriteFile
+		*moduleFlag = false
+		*replaceFlag = ""
+	}()
+	stderr = new(bytes.Buffer)
+	writeFile = os.WriteFile
+
+	if !fixModule() {
+		t.Fatalf("fixModule failed: %s", stderr)
+	}
+
+	wantStderr := `
+example.com/app
+	fixed: old.example/lib -> new.example/lib
+example.com/app/sub
+	fixed: old.example/lib/sub -> new.example/lib/sub
+go: found new.example/lib in new.example/lib v0.0.0-00010101000000-000000000000
+go: found new.example/lib/sub in new.example/lib v0.0.0-00010101000000-000000000000
+`
+	if got := strings.TrimSpace(stderr.(*bytes.Buffer).String()); got != strings.TrimSpace(wantStderr) {
+		t.Errorf("stderr: got <<\n%s\n>>, want <<%s>>", got, wantStderr)
+	}
+	for name, want := range map[string]string{
+		"app.go":     `import "new.example/lib"`,
+		"sub/sub.go": `import _ "new.example/lib/sub"`,
+		"go.mod":     "require new.example/lib v0.0.0-00010101000000-000000000000\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s does not contain %q:\n%s", name, want, data)
+		}
+		if strings.Contains(string(data), "require old.example/lib") {
+			t.Errorf("%s still requires old.example/lib:\n%s", name, data)
+		}
+	}
+}
+
+func TestSuccessor(t *testing.T) {
+	for _, test := range []struct {
+		deprecation, want string
+	}{
+		{"Use example.com/new instead.", "example.com/new"},
+		{"moved to github.com/org/repo/v2", "github.com/org/repo/v2"},
+		{"see https://example.com/new/lib.", "example.com/new/lib"},
+		{"(replaced by `example.com/x/y`)", "example.com/x/y"},
+		{"no longer maintained", ""},
+		{"use and/or abuse", ""},
+	} {
+		if got := successor(test.deprecation); got != test.want {
+			t.Errorf("successor(%q) = %q, want %q", test.deprecation, got, test.want)
+		}
+	}
+}
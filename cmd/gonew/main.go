@@ -6,13 +6,43 @@
 //
 // Usage:
 //
-//	gonew srcmod[@version] [dstmod [dir]]
+//	gonew [-var name=value]... [-steps list] srcmod[@version] [dstmod [dir]]
 //
 // Gonew makes a copy of the srcmod module, changing its module path to dstmod.
 // It writes that new module to a new directory named by dir.
 // If dir already exists, it must be an empty directory.
 // If dir is omitted, gonew uses ./elem where elem is the final path element of dstmod.
 //
+// # Template manifests
+//
+// A template module may contain a gonew.json manifest file in its root
+// directory, which declares template parameters and post-processing
+// steps. The manifest itself is not copied. For example:
+//
+//	{
+//		"vars": [
+//			{"name": "project", "prompt": "Project name"},
+//			{"name": "license", "prompt": "License", "default": "BSD-3-Clause", "choices": ["BSD-3-Clause", "MIT"]},
+//			{"name": "ci", "prompt": "CI provider", "default": "github", "choices": ["github", "gitlab", "none"]}
+//		],
+//		"steps": [
+//			{"name": "tidy", "doc": "run go mod tidy", "run": ["go", "mod", "tidy"]},
+//			{"name": "git", "doc": "create a git repository", "run": ["git", "init"]}
+//		]
+//	}
+//
+// Gonew replaces each occurrence of {{gonew.name}} in the names and
+// contents of the template's files, and in the commands of steps, by
+// the value of the parameter name. The -var flag sets the value of a
+// parameter. If standard input is a terminal, gonew prompts for the
+// values of declared parameters not set by -var; otherwise it uses
+// their defaults. A declared parameter with no default must be set.
+//
+// Since steps are commands chosen by the template's author, gonew runs
+// them only if requested by the -steps flag, a comma-separated list of
+// step names, or "all". Steps are run in the order the manifest
+// declares them, in the new module's directory.
+//
 // This command is highly experimental and subject to change.
 //
 // # Example
@@ -33,28 +63,29 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
-	"go/parser"
-	"go/token"
-	"io/fs"
+	"io"
 	"log"
 	"os"
-	"os/exec"
-	"path"
-	"path/filepath"
-	"strconv"
 	"strings"
 
-	"golang.org/x/mod/modfile"
-	"golang.org/x/mod/module"
-	"golang.org/x/tools/internal/edit"
+	"golang.org/x/tools/internal/gonew"
+)
+
+var (
+	vars  = make(varsFlag)
+	steps = flag.String("steps", "", "comma-separated list of template `steps` to run, or \"all\"")
 )
 
+func init() {
+	flag.Var(vars, "var", "set template parameter, in the form `name=value` (repeatable)")
+}
+
 func usage() {
-	fmt.Fprintf(os.Stderr, "usage: gonew srcmod[@version] [dstmod [dir]]\n")
+	fmt.Fprintf(os.Stderr, "usage: gonew [-var name=value]... [-steps list] srcmod[@version] [dstmod [dir]]\n")
 	fmt.Fprintf(os.Stderr, "See https://pkg.go.dev/golang.org/x/tools/cmd/gonew.\n")
 	os.Exit(2)
 }
@@ -70,164 +101,77 @@ func main() {
 		usage()
 	}
 
-	srcMod := args[0]
-	srcModVers := srcMod
-	if !strings.Contains(srcModVers, "@") {
-		srcModVers += "@latest"
+	cfg := &gonew.Config{
+		Template: args[0],
+		Vars:     vars,
 	}
-	srcMod, _, _ = strings.Cut(srcMod, "@")
-	if err := module.CheckPath(srcMod); err != nil {
-		log.Fatalf("invalid source module name: %v", err)
+	if *steps != "" {
+		cfg.Steps = strings.Split(*steps, ",")
+	}
+	if isTerminal(os.Stdin) {
+		cfg.Prompt = prompter(bufio.NewReader(os.Stdin))
 	}
-
-	dstMod := srcMod
 	if len(args) >= 2 {
-		dstMod = args[1]
-		if err := module.CheckPath(dstMod); err != nil {
-			log.Fatalf("invalid destination module name: %v", err)
-		}
+		cfg.Module = args[1]
 	}
-
-	var dir string
 	if len(args) == 3 {
-		dir = args[2]
-	} else {
-		dir = "." + string(filepath.Separator) + path.Base(dstMod)
-	}
-
-	// Dir must not exist or must be an empty directory.
-	de, err := os.ReadDir(dir)
-	if err == nil && len(de) > 0 {
-		log.Fatalf("target directory %s exists and is non-empty", dir)
-	}
-	needMkdir := err != nil
-
-	var stdout, stderr bytes.Buffer
-	cmd := exec.Command("go", "mod", "download", "-json", srcModVers)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		log.Fatalf("go mod download -json %s: %v\n%s%s", srcModVers, err, stderr.Bytes(), stdout.Bytes())
-	}
-
-	var info struct {
-		Dir string
+		cfg.Dir = args[2]
 	}
-	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
-		log.Fatalf("go mod download -json %s: invalid JSON output: %v\n%s%s", srcMod, err, stderr.Bytes(), stdout.Bytes())
+	dir, err := gonew.Create(context.Background(), cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	if needMkdir {
-		if err := os.MkdirAll(dir, 0777); err != nil {
-			log.Fatal(err)
-		}
+	dstMod := cfg.Module
+	if dstMod == "" {
+		dstMod, _, _ = strings.Cut(cfg.Template, "@")
 	}
-
-	// Copy from module cache into new directory, making edits as needed.
-	filepath.WalkDir(info.Dir, func(src string, d fs.DirEntry, err error) error {
-		if err != nil {
-			log.Fatal(err)
-		}
-		rel, err := filepath.Rel(info.Dir, src)
-		if err != nil {
-			log.Fatal(err)
-		}
-		dst := filepath.Join(dir, rel)
-		if d.IsDir() {
-			if err := os.MkdirAll(dst, 0777); err != nil {
-				log.Fatal(err)
-			}
-			return nil
-		}
-
-		data, err := os.ReadFile(src)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		isRoot := !strings.Contains(rel, string(filepath.Separator))
-		if strings.HasSuffix(rel, ".go") {
-			data = fixGo(data, rel, srcMod, dstMod, isRoot)
-		}
-		if rel == "go.mod" {
-			data = fixGoMod(data, srcMod, dstMod)
-		}
-
-		if err := os.WriteFile(dst, data, 0666); err != nil {
-			log.Fatal(err)
-		}
-		return nil
-	})
-
 	log.Printf("initialized %s in %s", dstMod, dir)
 }
 
-// fixGo rewrites the Go source in data to replace srcMod with dstMod.
-// isRoot indicates whether the file is in the root directory of the module,
-// in which case we also update the package name.
-func fixGo(data []byte, file string, srcMod, dstMod string, isRoot bool) []byte {
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, file, data, parser.ImportsOnly)
-	if err != nil {
-		log.Fatalf("parsing source module:\n%s", err)
-	}
+// varsFlag is the type of the repeatable -var flag.
+type varsFlag map[string]string
 
-	buf := edit.NewBuffer(data)
-	at := func(p token.Pos) int {
-		return fset.File(p).Offset(p)
+func (v varsFlag) String() string { return "" }
+
+func (v varsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid template parameter %q, want name=value", s)
 	}
+	v[name] = value
+	return nil
+}
 
-	srcName := path.Base(srcMod)
-	dstName := path.Base(dstMod)
-	if isRoot {
-		if name := f.Name.Name; name == srcName || name == srcName+"_test" {
-			dname := dstName + strings.TrimPrefix(name, srcName)
-			if !token.IsIdentifier(dname) {
-				log.Fatalf("%s: cannot rename package %s to package %s: invalid package name", file, name, dname)
-			}
-			buf.Replace(at(f.Name.Pos()), at(f.Name.End()), dname)
-		}
+// isTerminal reports whether f is (probably) a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
 	}
+	// The null device is also a character device.
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(fi, null)
+}
 
-	for _, spec := range f.Imports {
-		path, err := strconv.Unquote(spec.Path.Value)
-		if err != nil {
-			continue
+// prompter returns a function that asks the user on standard error
+// for the value of a template parameter, and reads it from in.
+func prompter(in *bufio.Reader) func(v *gonew.Var) (string, error) {
+	return func(v *gonew.Var) (string, error) {
+		question := v.Prompt
+		if question == "" {
+			question = v.Name
 		}
-		if path == srcMod {
-			if srcName != dstName && spec.Name == nil {
-				// Add package rename because source code uses original name.
-				// The renaming looks strange, but template authors are unlikely to
-				// create a template where the root package is imported by packages
-				// in subdirectories, and the renaming at least keeps the code working.
-				// A more sophisticated approach would be to rename the uses of
-				// the package identifier in the file too, but then you have to worry about
-				// name collisions, and given how unlikely this is, it doesn't seem worth
-				// trying to clean up the file that way.
-				buf.Insert(at(spec.Path.Pos()), srcName+" ")
-			}
-			// Change import path to dstMod
-			buf.Replace(at(spec.Path.Pos()), at(spec.Path.End()), strconv.Quote(dstMod))
+		if len(v.Choices) > 0 {
+			question += " (" + strings.Join(v.Choices, ", ") + ")"
 		}
-		if strings.HasPrefix(path, srcMod+"/") {
-			// Change import path to begin with dstMod
-			buf.Replace(at(spec.Path.Pos()), at(spec.Path.End()), strconv.Quote(strings.Replace(path, srcMod, dstMod, 1)))
+		if v.Default != "" {
+			question += " [" + v.Default + "]"
 		}
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("reading value of %s: %v", v.Name, err)
+		}
+		return strings.TrimSpace(line), nil // empty => default
 	}
-	return buf.Bytes()
-}
-
-// fixGoMod rewrites the go.mod content in data to replace srcMod with dstMod
-// in the module path.
-func fixGoMod(data []byte, srcMod, dstMod string) []byte {
-	f, err := modfile.ParseLax("go.mod", data, nil)
-	if err != nil {
-		log.Fatalf("parsing source module:\n%s", err)
-	}
-	f.AddModuleStmt(dstMod)
-	new, err := f.Format()
-	if err != nil {
-		return data
-	}
-	return new
 }
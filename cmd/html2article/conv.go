@@ -4,10 +4,15 @@
 
 // This program takes an HTML file and outputs a corresponding article file in
 // present format. See: golang.org/x/tools/present
+//
+// If the -assets flag names a directory, images referred to by the HTML
+// file by relative paths (resolved against the -base directory) or data
+// URLs are saved in that directory, and the article refers to the copies.
 package main // import "golang.org/x/tools/cmd/html2article"
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"flag"
 	"fmt"
@@ -15,6 +20,8 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -22,6 +29,11 @@ import (
 	"golang.org/x/net/html/atom"
 )
 
+var (
+	assetDir = flag.String("assets", "", "directory in which to save images, or empty to leave image links unchanged")
+	baseDir  = flag.String("base", ".", "directory against which relative image paths are resolved")
+)
+
 func main() {
 	flag.Parse()
 
@@ -46,8 +58,19 @@ func convert(w io.Writer, r io.Reader) error {
 	if body == nil {
 		return errors.New("couldn't find body")
 	}
+
+	// The first h1 heading, or else the document title, is the title
+	// of the article.
+	title := "Title"
+	if h1 := find(body, isTag(atom.H1)); h1 != nil {
+		title = strings.TrimSpace(plainText(h1))
+		h1.Parent.RemoveChild(h1)
+	} else if t := find(root, isTag(atom.Title)); t != nil && strings.TrimSpace(plainText(t)) != "" {
+		title = strings.TrimSpace(plainText(t))
+	}
+
 	article := limitNewlineRuns(makeHeadings(strings.TrimSpace(text(body))))
-	_, err = fmt.Fprintf(w, "Title\n\n%s", article)
+	_, err = fmt.Fprintf(w, "%s\n\n%s", title, article)
 	return err
 }
 
@@ -111,7 +134,7 @@ func makeHeadings(body string) string {
 	buf := new(bytes.Buffer)
 	lines := strings.Split(body, "\n")
 	for i, s := range lines {
-		if i == 0 && !isBoldTitle(s) {
+		if i == 0 && !isBoldTitle(s) && !strings.HasPrefix(s, "* ") {
 			buf.WriteString("* Introduction\n\n")
 		}
 		if isBoldTitle(s) {
@@ -194,8 +217,28 @@ func text(n *html.Node) string {
 			unwrap(&buf, childText(n))
 			buf.WriteByte('\n')
 		case atom.Pre:
-			indent(&buf, childText(n))
+			// Code is preformatted: ignore its markup, and
+			// any language class of a nested code element.
+			indent(&buf, strings.TrimRight(plainText(n), "\n"))
 			buf.WriteByte('\n')
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			// The first h1 is the title (see convert), so h2 is a
+			// section. Present has three levels of section heading.
+			level := min(max(int(a.String()[1]-'1'), 1), 3)
+			fmt.Fprintf(&buf, "\n%s %s\n\n", strings.Repeat("*", level), strings.Join(strings.Fields(plainText(n)), " "))
+		case atom.Nav, atom.Script, atom.Noscript, atom.Template:
+			// Navigation and scripts are not part of the article.
+		case atom.Figure:
+			buf.WriteString("\n")
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.DataAtom != atom.Figcaption {
+					buf.WriteString(strings.TrimLeft(text(c), " \t\n"))
+				}
+			}
+			if caption := find(n, isTag(atom.Figcaption)); caption != nil {
+				fmt.Fprintf(&buf, ".caption %s\n", strings.Join(strings.Fields(childText(caption)), " "))
+			}
+			buf.WriteString("\n")
 		case atom.A:
 			href, text := attr(n, "href"), childText(n)
 			// Skip links with no text.
@@ -221,7 +264,7 @@ func text(n *html.Node) string {
 		case atom.I:
 			buf.WriteString(highlight(n, "_"))
 		case atom.Img:
-			src := attr(n, "src")
+			src := saveAsset(attr(n, "src"))
 			fmt.Fprintf(&buf, ".image %s\n", src)
 		case atom.Iframe:
 			src, w, h := attr(n, "src"), attr(n, "width"), attr(n, "height")
@@ -245,6 +288,18 @@ func text(n *html.Node) string {
 	return buf.String()
 }
 
+// plainText returns the text of node and its descendants, without markup.
+func plainText(node *html.Node) string {
+	var buf bytes.Buffer
+	walk(node, func(n *html.Node) bool {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		return true
+	})
+	return buf.String()
+}
+
 func childText(node *html.Node) string {
 	var buf bytes.Buffer
 	for n := node.FirstChild; n != nil; n = n.NextSibling {
@@ -329,3 +384,97 @@ func walk(n *html.Node, fn selector) {
 		}
 	}
 }
+
+// imageExts maps the media types of images in data URLs to file
+// extensions.
+var imageExts = map[string]string{
+	"image/gif":     ".gif",
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/svg+xml": ".svg",
+	"image/webp":    ".webp",
+}
+
+// savedAssets maps the sources of saved images to their new links.
+var savedAssets = make(map[string]string)
+
+// saveAsset saves a copy of the image at src in the asset directory,
+// if any, and returns the link to use for it. Images on other hosts
+// are not copied.
+func saveAsset(src string) string {
+	if *assetDir == "" || src == "" {
+		return src
+	}
+	if link, ok := savedAssets[src]; ok {
+		return link
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		log.Printf("parsing image url %q: %v", src, err)
+		return src
+	}
+
+	var name string
+	var data []byte
+	switch {
+	case u.Scheme == "data":
+		// data:[<mediatype>][;base64],<data>
+		header, payload, ok := strings.Cut(u.Opaque, ",")
+		if !ok {
+			log.Printf("invalid data url for image")
+			return src
+		}
+		mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		if isBase64 {
+			data, err = base64.StdEncoding.DecodeString(payload)
+		} else {
+			var s string
+			s, err = url.PathUnescape(payload)
+			data = []byte(s)
+		}
+		if err != nil {
+			log.Printf("decoding data url for image: %v", err)
+			return src
+		}
+		name = "image" + imageExts[mediaType]
+	case u.Scheme != "" || u.Host != "":
+		return src // on another host
+	default:
+		data, err = os.ReadFile(filepath.Join(*baseDir, filepath.FromSlash(u.Path)))
+		if err != nil {
+			log.Printf("reading image: %v", err)
+			return src
+		}
+		name = path.Base(u.Path)
+	}
+
+	if err := os.MkdirAll(*assetDir, 0777); err != nil {
+		log.Printf("creating asset directory: %v", err)
+		return src
+	}
+	// Choose a name not already used in the directory.
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		f, err := os.OpenFile(filepath.Join(*assetDir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("%s%d%s", stem, i, ext)
+			continue
+		}
+		if err == nil {
+			_, err = f.Write(data)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			log.Printf("saving image: %v", err)
+			return src
+		}
+		break
+	}
+	link := path.Join(filepath.ToSlash(*assetDir), name)
+	savedAssets[src] = link
+	return link
+}
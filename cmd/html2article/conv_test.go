@@ -0,0 +1,110 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertHTML5(t *testing.T) {
+	const input = `<!DOCTYPE html>
+<html>
+<head><title>Ignored</title></head>
+<body>
+<nav><a href="/">Home</a></nav>
+<main>
+<article>
+<header><h1>Go Concurrency</h1></header>
+<section>
+<h2>Goroutines</h2>
+<p>A <code>go</code> statement
+starts a goroutine.</p>
+<pre><code class="language-go">go f(x, y)
+ch &lt;- v
+</code></pre>
+<figure>
+<img src="https://example.com/gopher.png">
+<figcaption>The  Go gopher.</figcaption>
+</figure>
+<h3>Channels</h3>
+<p>Channels connect goroutines.</p>
+</section>
+</article>
+</main>
+<script>var x = 1;</script>
+</body>
+</html>`
+	const want = `Go Concurrency
+
+* Goroutines
+
+A ` + "`go`" + ` statement starts a goroutine.
+
+	go f(x, y)
+	ch <- v
+
+.image https://example.com/gopher.png
+.caption The Go gopher.
+
+** Channels
+
+Channels connect goroutines.
+`
+	var buf strings.Builder
+	if err := convert(&buf, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("convert:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestConvertAssets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gopher.png"), []byte("gopher"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	defer func(assets, base string) {
+		*assetDir, *baseDir = assets, base
+	}(*assetDir, *baseDir)
+	*assetDir = filepath.Join(dir, "assets")
+	*baseDir = dir
+
+	const input = `<html><body>
+<p><img src="gopher.png"></p>
+<p><img src="data:image/png;base64,Z29waGVy"></p>
+<p><img src="gopher.png"></p>
+<p><img src="https://example.com/remote.png"></p>
+</body></html>`
+	var buf strings.Builder
+	if err := convert(&buf, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.ToSlash(*assetDir)
+	for _, want := range []string{
+		".image " + link + "/gopher.png\n",
+		".image " + link + "/image.png\n",
+		".image https://example.com/remote.png\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, buf.String())
+		}
+	}
+	for _, name := range []string{"gopher.png", "image.png"} {
+		data, err := os.ReadFile(filepath.Join(*assetDir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(data) != "gopher" {
+			t.Errorf("%s contains %q, want %q", name, data, "gopher")
+		}
+	}
+	entries, _ := os.ReadDir(*assetDir)
+	if len(entries) != 2 {
+		t.Errorf("asset directory has %d files, want 2", len(entries))
+	}
+}
@@ -8,7 +8,6 @@ require (
 	golang.org/x/mod v0.22.0
 	golang.org/x/net v0.34.0
 	golang.org/x/sync v0.10.0
+	golang.org/x/sys v0.29.0
 	golang.org/x/telemetry v0.0.0-20240521205824-bda55230c457
 )
-
-require golang.org/x/sys v0.29.0 // indirect
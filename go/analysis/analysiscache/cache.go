@@ -0,0 +1,450 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package analysiscache provides a persistent cache of the outputs of
+// analysis actions (the application of one analyzer to one package),
+// for use by analysis drivers.
+//
+// The 'go vet' command gets incrementality from the go command's
+// build cache. Other drivers, such as those built on the
+// [golang.org/x/tools/go/analysis/checker] package, can use this
+// package to avoid repeating the analysis of packages that have not
+// changed since a previous run.
+//
+// An entry in the cache holds the diagnostics reported by an action
+// and the facts it exported about its own package. It is identified by
+// a [Key] derived from the analyzer, a version string that identifies
+// the analyzer's implementation, and a hash of the package that must
+// reflect the content of the package and all its dependencies. The
+// driver is responsible for computing this hash.
+//
+// Positions are recorded relative to files, so an entry may be decoded
+// into a different [token.FileSet] than the one it was encoded from.
+// Facts about objects are recorded using [objectpath], so they may be
+// decoded into a different [types.Package].
+package analysiscache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/objectpath"
+)
+
+// A Cache is a directory of cached analysis results.
+// It is safe for concurrent use, including by several processes.
+type Cache struct {
+	dir string
+}
+
+// New returns a cache that stores results in the specified
+// directory, creating it if necessary.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Default returns a cache in the user's cache directory
+// (see [os.UserCacheDir]).
+func Default() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, "go-analysis"))
+}
+
+// A Key identifies an entry in a Cache.
+type Key [sha256.Size]byte
+
+func (key Key) String() string { return hex.EncodeToString(key[:]) }
+
+// formatVersion identifies the encoding of cache entries.
+const formatVersion = 1
+
+// NewKey returns the key for the result of applying analyzer a, of
+// the given version, to a package whose content, and the content of
+// whose dependencies, has the given hash.
+//
+// The version should change whenever the behavior of the analyzer
+// may change; see [ExecutableVersion].
+func NewKey(a *analysis.Analyzer, version string, pkgHash [sha256.Size]byte) Key {
+	h := sha256.New()
+	fmt.Fprintf(h, "format %d\n", formatVersion)
+	fmt.Fprintf(h, "analyzer %s\n", a.Name)
+	fmt.Fprintf(h, "version %q\n", version)
+	h.Write(pkgHash[:])
+	var key Key
+	h.Sum(key[:0])
+	return key
+}
+
+var executableVersion = sync.OnceValues(func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+})
+
+// ExecutableVersion returns a hash of the running executable, which is
+// a conservative version for all the analyzers linked into it: any
+// change to them, or to the driver, invalidates the cache.
+func ExecutableVersion() (string, error) {
+	return executableVersion()
+}
+
+// A Result holds the outputs of an analysis action that may be
+// cached.
+type Result struct {
+	// Diagnostics are the diagnostics reported by the action. When
+	// decoded, the Data field of each diagnostic, if any, holds its
+	// JSON encoding as a [json.RawMessage].
+	Diagnostics []analysis.Diagnostic
+
+	// ObjectFacts and PackageFacts are the facts exported by the
+	// action about its own package. Facts about other packages, and
+	// object facts for which there is no [objectpath.Path], are not
+	// cached.
+	ObjectFacts  []analysis.ObjectFact
+	PackageFacts []analysis.PackageFact
+}
+
+// Get returns the result recorded for the key, decoding its positions
+// relative to the files of the package, or to new files added to fset
+// if they are not among them. Facts are decoded relative to pkg, and
+// must be of the types declared by a.
+//
+// Get reports false if there is no valid entry for the key.
+func (c *Cache) Get(key Key, a *analysis.Analyzer, fset *token.FileSet, files []*ast.File, pkg *types.Package) (*Result, bool) {
+	data, err := os.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+	res, err := decode(data, a, fset, files, pkg)
+	if err != nil {
+		return nil, false // corrupt or stale entry
+	}
+	return res, true
+}
+
+// Put records the result for the key. Positions in res must be
+// relative to fset, and facts must be about pkg or its objects.
+func (c *Cache) Put(key Key, a *analysis.Analyzer, fset *token.FileSet, pkg *types.Package, res *Result) error {
+	data, err := encode(a, fset, pkg, res)
+	if err != nil {
+		return err
+	}
+	filename := c.filename(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+		return err
+	}
+	// Write then rename, so that readers never see a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (c *Cache) filename(key Key) string {
+	hex := key.String()
+	return filepath.Join(c.dir, hex[:2], hex)
+}
+
+// -- encoding --
+
+// An entry is the gob-encoded form of a Result.
+type entry struct {
+	Files        []fileInfo
+	Diagnostics  []diagnostic
+	ObjectFacts  []fact
+	PackageFacts []fact
+}
+
+// A fileInfo records a file referenced by a position, so that it can
+// be recreated if it is not among the files of the package.
+type fileInfo struct {
+	Name  string
+	Size  int
+	Lines []int
+}
+
+// A position is a token.Pos relative to a file in entry.Files.
+type position struct {
+	File   int // index+1 in entry.Files, or zero for token.NoPos
+	Offset int
+}
+
+type diagnostic struct {
+	Pos, End       position
+	Category       string
+	Message        string
+	URL            string
+	SuggestedFixes []suggestedFix
+	Related        []relatedInformation
+	Scope          analysis.DiagnosticScope
+	Data           []byte // JSON
+}
+
+type suggestedFix struct {
+	Message   string
+	TextEdits []textEdit
+}
+
+type textEdit struct {
+	Pos, End position
+	NewText  []byte
+}
+
+type relatedInformation struct {
+	Pos, End position
+	Message  string
+}
+
+// A fact is a fact, gob-encoded as the concrete type
+// a.FactTypes[Type], about the object with the given path
+// (or about the package if it is an element of PackageFacts).
+type fact struct {
+	Path objectpath.Path
+	Type int
+	Data []byte
+}
+
+func encode(a *analysis.Analyzer, fset *token.FileSet, pkg *types.Package, res *Result) ([]byte, error) {
+	var e entry
+
+	fileIndex := make(map[*token.File]int)
+	pos := func(p token.Pos) position {
+		f := fset.File(p)
+		if f == nil {
+			return position{}
+		}
+		i, ok := fileIndex[f]
+		if !ok {
+			e.Files = append(e.Files, fileInfo{f.Name(), f.Size(), f.Lines()})
+			i = len(e.Files)
+			fileIndex[f] = i
+		}
+		return position{File: i, Offset: f.Offset(p)}
+	}
+
+	for _, d := range res.Diagnostics {
+		ed := diagnostic{
+			Pos:      pos(d.Pos),
+			End:      pos(d.End),
+			Category: d.Category,
+			Message:  d.Message,
+			URL:      d.URL,
+			Scope:    d.Scope,
+		}
+		for _, fix := range d.SuggestedFixes {
+			efix := suggestedFix{Message: fix.Message}
+			for _, edit := range fix.TextEdits {
+				efix.TextEdits = append(efix.TextEdits, textEdit{pos(edit.Pos), pos(edit.End), edit.NewText})
+			}
+			ed.SuggestedFixes = append(ed.SuggestedFixes, efix)
+		}
+		for _, rel := range d.Related {
+			ed.Related = append(ed.Related, relatedInformation{pos(rel.Pos), pos(rel.End), rel.Message})
+		}
+		if d.Data != nil {
+			data, err := json.Marshal(d.Data)
+			if err != nil {
+				return nil, fmt.Errorf("encoding data of diagnostic %q: %v", d.Message, err)
+			}
+			ed.Data = data
+		}
+		e.Diagnostics = append(e.Diagnostics, ed)
+	}
+
+	encodeFact := func(path objectpath.Path, f analysis.Fact) (fact, error) {
+		t := reflect.TypeOf(f)
+		for i, ft := range a.FactTypes {
+			if reflect.TypeOf(ft) == t {
+				var buf bytes.Buffer
+				if err := gob.NewEncoder(&buf).Encode(f); err != nil {
+					return fact{}, fmt.Errorf("encoding %T fact: %v", f, err)
+				}
+				return fact{Path: path, Type: i, Data: buf.Bytes()}, nil
+			}
+		}
+		return fact{}, fmt.Errorf("%T is not a fact type of %s", f, a.Name)
+	}
+	for _, f := range res.ObjectFacts {
+		if f.Object.Pkg() != pkg {
+			continue
+		}
+		path, err := objectpath.For(f.Object)
+		if err != nil {
+			continue // not accessible from other packages
+		}
+		ef, err := encodeFact(path, f.Fact)
+		if err != nil {
+			return nil, err
+		}
+		e.ObjectFacts = append(e.ObjectFacts, ef)
+	}
+	for _, f := range res.PackageFacts {
+		if f.Package != pkg {
+			continue
+		}
+		ef, err := encodeFact("", f.Fact)
+		if err != nil {
+			return nil, err
+		}
+		e.PackageFacts = append(e.PackageFacts, ef)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&e); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte, a *analysis.Analyzer, fset *token.FileSet, files []*ast.File, pkg *types.Package) (*Result, error) {
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return nil, err
+	}
+
+	// Find or create each referenced file.
+	byName := make(map[string]*token.File)
+	for _, f := range files {
+		if tf := fset.File(f.FileStart); tf != nil {
+			byName[tf.Name()] = tf
+		}
+	}
+	tokFiles := make([]*token.File, len(e.Files))
+	for i, info := range e.Files {
+		tf := byName[info.Name]
+		if tf == nil {
+			tf = fset.AddFile(info.Name, -1, info.Size)
+			if !tf.SetLines(info.Lines) {
+				return nil, fmt.Errorf("invalid line table for %s", info.Name)
+			}
+			byName[info.Name] = tf
+		}
+		if tf.Size() != info.Size {
+			return nil, fmt.Errorf("size of %s has changed", info.Name)
+		}
+		tokFiles[i] = tf
+	}
+	pos := func(p position) (token.Pos, error) {
+		if p.File == 0 {
+			return token.NoPos, nil
+		}
+		if p.File > len(tokFiles) || p.Offset > tokFiles[p.File-1].Size() {
+			return token.NoPos, fmt.Errorf("invalid position")
+		}
+		return tokFiles[p.File-1].Pos(p.Offset), nil
+	}
+	var err error
+	rng := func(start, end position) (token.Pos, token.Pos) {
+		p, err1 := pos(start)
+		q, err2 := pos(end)
+		if err == nil {
+			err = err1
+		}
+		if err == nil {
+			err = err2
+		}
+		return p, q
+	}
+
+	res := new(Result)
+	for _, ed := range e.Diagnostics {
+		d := analysis.Diagnostic{
+			Category: ed.Category,
+			Message:  ed.Message,
+			URL:      ed.URL,
+			Scope:    ed.Scope,
+		}
+		d.Pos, d.End = rng(ed.Pos, ed.End)
+		for _, efix := range ed.SuggestedFixes {
+			fix := analysis.SuggestedFix{Message: efix.Message}
+			for _, edit := range efix.TextEdits {
+				p, q := rng(edit.Pos, edit.End)
+				fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{Pos: p, End: q, NewText: edit.NewText})
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, fix)
+		}
+		for _, rel := range ed.Related {
+			p, q := rng(rel.Pos, rel.End)
+			d.Related = append(d.Related, analysis.RelatedInformation{Pos: p, End: q, Message: rel.Message})
+		}
+		if ed.Data != nil {
+			d.Data = json.RawMessage(ed.Data)
+		}
+		res.Diagnostics = append(res.Diagnostics, d)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decodeFact := func(ef fact) (analysis.Fact, error) {
+		if ef.Type < 0 || ef.Type >= len(a.FactTypes) {
+			return nil, fmt.Errorf("invalid fact type")
+		}
+		f := reflect.New(reflect.TypeOf(a.FactTypes[ef.Type]).Elem()).Interface().(analysis.Fact)
+		if err := gob.NewDecoder(bytes.NewReader(ef.Data)).Decode(f); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	for _, ef := range e.ObjectFacts {
+		obj, err := objectpath.Object(pkg, ef.Path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := decodeFact(ef)
+		if err != nil {
+			return nil, err
+		}
+		res.ObjectFacts = append(res.ObjectFacts, analysis.ObjectFact{Object: obj, Fact: f})
+	}
+	for _, ef := range e.PackageFacts {
+		f, err := decodeFact(ef)
+		if err != nil {
+			return nil, err
+		}
+		res.PackageFacts = append(res.PackageFacts, analysis.PackageFact{Package: pkg, Fact: f})
+	}
+	return res, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysiscache_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysiscache"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/internal/testenv"
+	"golang.org/x/tools/internal/testfiles"
+	"golang.org/x/tools/txtar"
+)
+
+type badFact struct{}
+
+func (*badFact) AFact()         {}
+func (*badFact) String() string { return "bad" }
+
+func TestCache(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	const src = `
+-- go.mod --
+module example.com
+go 1.22
+
+-- a/a.go --
+package a
+
+import "example.com/b"
+
+func f() {
+	b.Bad()
+}
+
+-- b/b.go --
+package b
+
+func Bad() {}
+`
+	fs, err := txtar.FS(txtar.Parse([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := testfiles.CopyToTmp(t, fs)
+
+	// The analyzer exports a fact for each function named Bad
+	// and reports each call to a function with that fact.
+	var (
+		mu  sync.Mutex
+		ran []string // packages analyzed
+	)
+	bad := &analysis.Analyzer{
+		Name:      "bad",
+		Doc:       "report calls to bad functions",
+		FactTypes: []analysis.Fact{new(badFact)},
+		Run: func(pass *analysis.Pass) (any, error) {
+			mu.Lock()
+			ran = append(ran, pass.Pkg.Path())
+			mu.Unlock()
+
+			if fn, ok := pass.Pkg.Scope().Lookup("Bad").(*types.Func); ok {
+				pass.ExportObjectFact(fn, new(badFact))
+			}
+			for _, file := range pass.Files {
+				ast.Inspect(file, func(n ast.Node) bool {
+					if call, ok := n.(*ast.CallExpr); ok {
+						if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+							obj := pass.TypesInfo.Uses[sel.Sel]
+							if obj != nil && pass.ImportObjectFact(obj, new(badFact)) {
+								pass.Report(analysis.Diagnostic{
+									Pos:     call.Pos(),
+									End:     call.End(),
+									Message: "call of bad function",
+									SuggestedFixes: []analysis.SuggestedFix{{
+										Message:   "Delete call",
+										TextEdits: []analysis.TextEdit{{Pos: call.Pos(), End: call.End()}},
+									}},
+								})
+							}
+						}
+					}
+					return true
+				})
+			}
+			return nil, nil
+		},
+	}
+
+	cache, err := analysiscache.New(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// analyze runs the analyzer on package a and returns the
+	// packages analyzed and the diagnostics reported.
+	analyze := func() ([]string, []string) {
+		cfg := &packages.Config{
+			Mode: packages.LoadAllSyntax,
+			Dir:  dir,
+			Env:  append(os.Environ(), "GOPROXY=off", "GOWORK=off"),
+		}
+		pkgs, err := packages.Load(cfg, "example.com/a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ran = nil
+		graph, err := checker.Analyze([]*analysis.Analyzer{bad}, pkgs, &checker.Options{Cache: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var diags []string
+		for _, act := range graph.Roots {
+			if act.Err != nil {
+				t.Fatal(act.Err)
+			}
+			fset := act.Package.Fset
+			for _, d := range act.Diagnostics {
+				edit := d.SuggestedFixes[0].TextEdits[0]
+				diags = append(diags, fmt.Sprintf("%s: %s (fix: %s-%s)",
+					fset.Position(d.Pos), d.Message, fset.Position(edit.Pos), fset.Position(edit.End)))
+			}
+		}
+		sort.Strings(ran)
+		return ran, diags
+	}
+
+	ran1, diags1 := analyze()
+	if want := []string{"example.com/a", "example.com/b"}; !reflect.DeepEqual(ran1, want) {
+		t.Errorf("first run analyzed %v, want %v", ran1, want)
+	}
+	if len(diags1) != 1 {
+		t.Fatalf("first run reported %v, want one diagnostic", diags1)
+	}
+
+	// A second run uses only the cache.
+	ran2, diags2 := analyze()
+	if len(ran2) > 0 {
+		t.Errorf("second run analyzed %v, want none", ran2)
+	}
+	if !reflect.DeepEqual(diags2, diags1) {
+		t.Errorf("second run reported %v, want %v", diags2, diags1)
+	}
+
+	// A change to b invalidates the results for a too.
+	if err := os.WriteFile(filepath.Join(dir, "b/b.go"), []byte("package b\n\n// Bad is bad.\nfunc Bad() {}\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	ran3, diags3 := analyze()
+	if want := []string{"example.com/a", "example.com/b"}; !reflect.DeepEqual(ran3, want) {
+		t.Errorf("after change to b, analyzed %v, want %v", ran3, want)
+	}
+	if !reflect.DeepEqual(diags3, diags1) {
+		t.Errorf("after change to b, reported %v, want %v", diags3, diags1)
+	}
+
+	// A change to a alone invalidates only a, whose analysis
+	// uses the cached facts of b.
+	if err := os.WriteFile(filepath.Join(dir, "a/a.go"), []byte("package a\n\nimport \"example.com/b\"\n\nfunc f() { b.Bad() }\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	ran4, diags4 := analyze()
+	if want := []string{"example.com/a"}; !reflect.DeepEqual(ran4, want) {
+		t.Errorf("after change to a, analyzed %v, want %v", ran4, want)
+	}
+	if len(diags4) != 1 {
+		t.Errorf("after change to a, reported %v, want one diagnostic", diags4)
+	}
+}
@@ -345,7 +345,8 @@ func applyDiffsAndCompare(src, golden []byte, edits []diff.Edit, fileName string
 //	func panicf(format string, args interface{}) { // want panicf:"printfWrapper"
 //
 // Package facts are specified by the name "package" and appear on
-// line 1 of the first source file of the package.
+// line 1 of the first source file of the package. So do expectations
+// of package-scoped diagnostics that have no position.
 //
 // A single 'want' comment may contain a mixture of diagnostic and fact
 // expectations, including multiple facts about the same object:
@@ -623,6 +624,12 @@ func check(t Testing, gopath string, act *checker.Action) {
 	for _, f := range act.Diagnostics {
 		// TODO(matloob): Support ranges in analysistest.
 		posn := act.Package.Fset.Position(f.Pos)
+		if f.Scope == analysis.ScopePackage && !f.Pos.IsValid() {
+			// package diagnostics without a position:
+			// reported at start of first file, like package facts
+			posn = act.Package.Fset.Position(act.Package.Syntax[0].Pos())
+			posn.Line, posn.Column = 1, 1
+		}
 		checkMessage(posn, "diagnostic", "", f.Message)
 	}
 
@@ -204,6 +204,35 @@ func F() {} // want "say hello"`,
 	analysistest.RunWithSuggestedFixes(t, dir, noend, "a")
 }
 
+// TestPackageScope tests that a package-scoped diagnostic without a
+// position is matched by an expectation on line 1 of the first file.
+func TestPackageScope(t *testing.T) {
+	pkgscope := &analysis.Analyzer{
+		Name: "pkgscope",
+		Doc:  "reports the number of files in each package",
+		Run: func(pass *analysis.Pass) (any, error) {
+			pass.Report(analysis.Diagnostic{
+				Message: fmt.Sprintf("package has %d files", len(pass.Files)),
+				Scope:   analysis.ScopePackage,
+				Data:    map[string]int{"files": len(pass.Files)},
+			})
+			return nil, nil
+		},
+	}
+
+	filemap := map[string]string{
+		"a/a.go": `package a // want "package has 2 files"`,
+		"a/b.go": `package a`,
+	}
+	dir, cleanup, err := analysistest.WriteFiles(filemap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	analysistest.Run(t, dir, pkgscope, "a")
+}
+
 func TestModule(t *testing.T) {
 	const content = `
 Test that analysis.pass.Module is populated.
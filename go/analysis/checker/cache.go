@@ -0,0 +1,146 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checker
+
+// This file defines the use of the optional Options.Cache.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysiscache"
+	"golang.org/x/tools/go/packages"
+)
+
+// setCacheKeys sets the cache key of each action whose outputs may be
+// cached: that is, one whose result is not required by another action
+// of the same package, and whose package is free of errors.
+func setCacheKeys(actions []*Action, opts *Options) error {
+	version, err := analysiscache.ExecutableVersion()
+	if err != nil {
+		return fmt.Errorf("can't use analysis cache: %v", err)
+	}
+
+	// Results of horizontal dependencies are needed in memory.
+	needed := make(map[*Action]bool)
+	for _, act := range actions {
+		for _, dep := range act.Deps {
+			if dep.Package == act.Package {
+				needed[dep] = true
+			}
+		}
+	}
+
+	readFile := os.ReadFile
+	if opts.readFile != nil {
+		readFile = opts.readFile
+	}
+	hashes := make(map[*packages.Package][sha256.Size]byte)
+	for _, act := range actions {
+		if needed[act] || act.Package.IllTyped || len(act.Package.Errors) > 0 {
+			continue
+		}
+		hash, err := packageHash(act.Package, readFile, hashes)
+		if err != nil {
+			continue // e.g. missing file; don't cache
+		}
+		key := analysiscache.NewKey(act.Analyzer, version, hash)
+		act.cacheKey = &key
+	}
+	return nil
+}
+
+// packageHash returns a hash of the identity and files of a package
+// and of the hashes of its dependencies, memoized in hashes.
+func packageHash(pkg *packages.Package, readFile func(string) ([]byte, error), hashes map[*packages.Package][sha256.Size]byte) ([sha256.Size]byte, error) {
+	if hash, ok := hashes[pkg]; ok {
+		return hash, nil
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "id %s\n", pkg.ID)
+	fmt.Fprintf(h, "path %s\n", pkg.PkgPath)
+	fmt.Fprintf(h, "sizes %v\n", pkg.TypesSizes)
+	if pkg.Module != nil {
+		fmt.Fprintf(h, "module %s %s %s\n", pkg.Module.Path, pkg.Module.Version, pkg.Module.GoVersion)
+	}
+	for _, files := range [][]string{pkg.CompiledGoFiles, pkg.OtherFiles, pkg.IgnoredFiles} {
+		fmt.Fprintf(h, "files %d\n", len(files))
+		for _, filename := range files {
+			data, err := readFile(filename)
+			if err != nil {
+				return [sha256.Size]byte{}, err
+			}
+			fmt.Fprintf(h, "file %s %x\n", filename, sha256.Sum256(data))
+		}
+	}
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		dep, err := packageHash(pkg.Imports[path], readFile, hashes)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		fmt.Fprintf(h, "import %s %x\n", path, dep)
+	}
+	var hash [sha256.Size]byte
+	h.Sum(hash[:0])
+	hashes[pkg] = hash
+	return hash, nil
+}
+
+// execCached attempts to obtain the outputs of the action from the
+// cache, and reports whether it succeeded. Only the facts of the
+// vertical dependencies are then needed.
+func (act *Action) execCached() bool {
+	pkg := act.Package
+	res, ok := act.opts.Cache.Get(*act.cacheKey, act.Analyzer, pkg.Fset, pkg.Syntax, pkg.Types)
+	if !ok {
+		return false
+	}
+
+	var vertical []*Action
+	for _, dep := range act.Deps {
+		if dep.Package != pkg {
+			vertical = append(vertical, dep)
+		}
+	}
+	execAll(vertical)
+	for _, dep := range vertical {
+		if dep.Err != nil {
+			return false // report the failure in the usual way
+		}
+	}
+
+	act.objectFacts = make(map[objectFactKey]analysis.Fact)
+	act.packageFacts = make(map[packageFactKey]analysis.Fact)
+	for _, dep := range vertical {
+		inheritFacts(act, dep)
+	}
+	for _, f := range res.ObjectFacts {
+		act.objectFacts[objectFactKey{f.Object, factType(f.Fact)}] = f.Fact
+	}
+	for _, f := range res.PackageFacts {
+		act.packageFacts[packageFactKey{f.Package, factType(f.Fact)}] = f.Fact
+	}
+	act.Diagnostics = res.Diagnostics
+	return true
+}
+
+// putCached records the outputs of the action in the cache.
+func (act *Action) putCached() {
+	res := &analysiscache.Result{
+		Diagnostics:  act.Diagnostics,
+		ObjectFacts:  act.AllObjectFacts(),
+		PackageFacts: act.AllPackageFacts(),
+	}
+	// Failure to write the cache is not an error.
+	_ = act.opts.Cache.Put(*act.cacheKey, act.Analyzer, act.Package.Fset, act.Package.Types, res)
+}
@@ -43,6 +43,7 @@ import (
 	"time"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysiscache"
 	"golang.org/x/tools/go/analysis/internal"
 	"golang.org/x/tools/go/analysis/internal/analysisflags"
 	"golang.org/x/tools/go/packages"
@@ -56,6 +57,15 @@ type Options struct {
 	SanityCheck bool      // check fact encoding is ok and deterministic
 	FactLog     io.Writer // if non-nil, log each exported fact to it
 
+	// Cache, if non-nil, holds the diagnostics and facts of
+	// previous runs. An action whose outputs are found in the cache
+	// is not executed, and its Result is nil. Only actions whose
+	// results are not required by other analyzers are cached.
+	// Cache entries are keyed by a hash of the package and its
+	// dependencies, and by [analysiscache.ExecutableVersion], so any
+	// change to the program invalidates them.
+	Cache *analysiscache.Cache
+
 	// TODO(adonovan): expose ReadFile so that an Overlay specified
 	// in the [packages.Config] can be communicated via
 	// Pass.ReadFile to each Analyzer.
@@ -131,6 +141,7 @@ type Action struct {
 
 	opts         *Options
 	once         sync.Once
+	cacheKey     *analysiscache.Key // non-nil if outputs may be cached
 	pass         *analysis.Pass
 	objectFacts  map[objectFactKey]analysis.Fact
 	packageFacts map[packageFactKey]analysis.Fact
@@ -213,6 +224,16 @@ func Analyze(analyzers []*analysis.Analyzer, pkgs []*packages.Package, opts *Opt
 		}
 	}
 
+	if opts.Cache != nil {
+		all := make([]*Action, 0, len(actions))
+		for _, act := range actions {
+			all = append(all, act)
+		}
+		if err := setCacheKeys(all, opts); err != nil {
+			return nil, err
+		}
+	}
+
 	// Execute the graph in parallel.
 	execAll(roots)
 
@@ -265,6 +286,10 @@ func execAll(actions []*Action) {
 func (act *Action) exec() { act.once.Do(act.execOnce) }
 
 func (act *Action) execOnce() {
+	if act.cacheKey != nil && act.execCached() {
+		return
+	}
+
 	// Analyze dependencies.
 	execAll(act.Deps)
 
@@ -343,6 +368,9 @@ func (act *Action) execOnce() {
 			if err := analysisinternal.ValidateFixes(act.Package.Fset, act.Analyzer, d.SuggestedFixes); err != nil {
 				panic(err)
 			}
+			if err := analysisinternal.ValidateDiagnostic(act.Package.Fset, act.Analyzer, d); err != nil {
+				panic(err)
+			}
 			act.Diagnostics = append(act.Diagnostics, d)
 		},
 		ImportObjectFact:  act.ObjectFact,
@@ -390,6 +418,10 @@ func (act *Action) execOnce() {
 	// Help detect (disallowed) calls after Run.
 	pass.ExportObjectFact = nil
 	pass.ExportPackageFact = nil
+
+	if act.cacheKey != nil && act.Err == nil {
+		act.putCached()
+	}
 }
 
 // inheritFacts populates act.facts with
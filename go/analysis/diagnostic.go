@@ -4,9 +4,13 @@
 
 package analysis
 
-import "go/token"
+import (
+	"fmt"
+	"go/token"
+)
 
-// A Diagnostic is a message associated with a source location or range.
+// A Diagnostic is a message associated with a source location or range,
+// or, depending on its Scope, with an entire file or package.
 //
 // An Analyzer may return a variety of diagnostics; the optional Category,
 // which should be a constant, may be used to classify them.
@@ -49,6 +53,42 @@ type Diagnostic struct {
 	// Related contains optional secondary positions and messages
 	// related to the primary diagnostic.
 	Related []RelatedInformation
+
+	// Scope describes the extent of the code to which the
+	// diagnostic applies. The default, ScopePosition, denotes the
+	// range Pos to End. For ScopeFile, Pos must be a valid
+	// position in the file in question. For ScopePackage, Pos may
+	// be NoPos, in which case the driver chooses where (if
+	// anywhere) to display the diagnostic.
+	Scope DiagnosticScope
+
+	// Data is optional structured, machine-readable detail about
+	// the diagnostic, for use by drivers and other tools. It must
+	// be marshalable by [encoding/json]. Drivers that produce JSON
+	// output include it verbatim; others may ignore it.
+	Data any
+}
+
+// A DiagnosticScope describes the extent of the code to which a
+// Diagnostic applies.
+type DiagnosticScope int
+
+const (
+	ScopePosition DiagnosticScope = iota // the range Pos to End
+	ScopeFile                            // the entire file containing Pos
+	ScopePackage                         // the entire package
+)
+
+func (scope DiagnosticScope) String() string {
+	switch scope {
+	case ScopePosition:
+		return "position"
+	case ScopeFile:
+		return "file"
+	case ScopePackage:
+		return "package"
+	}
+	return fmt.Sprintf("DiagnosticScope(%d)", int(scope))
 }
 
 // RelatedInformation contains information related to a diagnostic.
@@ -175,6 +175,11 @@ Diagnostic is defined as:
 The optional Category field is a short identifier that classifies the
 kind of message when an analysis produces several kinds of diagnostic.
 
+A diagnostic may also describe a whole file or package, as indicated
+by its optional Scope field, and may carry optional machine-readable
+detail in its Data field, which drivers may render or pass on to other
+tools (for example, in their JSON output).
+
 The [Diagnostic] struct does not have a field to indicate its severity
 because opinions about the relative importance of Analyzers and their
 diagnostics vary widely among users. The design of this framework does
@@ -0,0 +1,95 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysisflags
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// A config is the content of the file named by the -config flag, for
+// example:
+//
+//	{
+//		"analyzers": {"shadow": false},
+//		"flags": {"printf.funcs": "Logf,Warnf", "c": 1}
+//	}
+//
+// Analyzers enables or disables analyzers by name, as if by the
+// -NAME flags of a multichecker. Flags sets the value of each named
+// command-line flag, which may be a JSON string, number, or boolean.
+type config struct {
+	Analyzers map[string]bool            `json:"analyzers"`
+	Flags     map[string]json.RawMessage `json:"flags"`
+}
+
+// applyConfig reads the configuration file and sets each flag it
+// mentions that was not explicitly set on the command line, so that
+// command-line flags take precedence over the file.
+func applyConfig(filename string, multi bool) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var cfg config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("parsing %s: %v", filename, err)
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	set := func(name, value string) error {
+		if explicit[name] {
+			return nil
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+		return nil
+	}
+
+	if len(cfg.Analyzers) > 0 && !multi {
+		return fmt.Errorf("%s: analyzers can be selected only in a multichecker", filename)
+	}
+	for name, enable := range cfg.Analyzers {
+		if f := flag.Lookup(name); f == nil || !isTriState(f.Value) {
+			return fmt.Errorf("%s: no analyzer named %q", filename, name)
+		}
+		if err := set(name, fmt.Sprint(enable)); err != nil {
+			return err
+		}
+	}
+
+	for name, raw := range cfg.Flags {
+		if flag.Lookup(name) == nil || name == "config" {
+			return fmt.Errorf("%s: no flag named %q", filename, name)
+		}
+		var value string
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return fmt.Errorf("%s: flag %s: %v", filename, name, err)
+			}
+		} else if len(raw) > 0 && (raw[0] == '{' || raw[0] == '[') {
+			return fmt.Errorf("%s: flag %s: value must be a string, number, or boolean", filename, name)
+		} else {
+			value = string(raw)
+		}
+		if err := set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isTriState(v flag.Value) bool {
+	_, ok := v.(*triState)
+	return ok
+}
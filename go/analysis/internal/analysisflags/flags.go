@@ -33,6 +33,10 @@ var (
 // parses the flags, then filters and returns the list of
 // analyzers enabled by flags.
 //
+// The -config=file flag names a JSON file of flag values (see
+// [config]), which apply to flags not set on the command line. This
+// allows a checker's configuration to be managed centrally.
+//
 // The result is intended to be passed to unitchecker.Run or checker.Run.
 // Use in unitchecker.Run will gob.Register all fact types for the returned
 // graph of analyzers but of course not the ones only reachable from
@@ -67,9 +71,13 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 		})
 	}
 
-	// standard flags: -flags, -V.
+	// standard flags: -flags, -V, -config.
 	printflags := flag.Bool("flags", false, "print analyzer flags in JSON")
 	addVersionFlag()
+	var configFile string
+	if flag.Lookup("config") == nil {
+		flag.StringVar(&configFile, "config", "", "read analyzer selection and flags from this JSON file")
+	}
 
 	// flags common to all checkers
 	flag.BoolVar(&JSON, "json", JSON, "emit JSON output")
@@ -90,6 +98,13 @@ func Parse(analyzers []*analysis.Analyzer, multi bool) []*analysis.Analyzer {
 
 	flag.Parse() // (ExitOnError)
 
+	// -config: apply flags from the file, unless set on the command line.
+	if configFile != "" {
+		if err := applyConfig(configFile, multi); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// -flags: print flags so that go vet knows which ones are legitimate.
 	if *printflags {
 		printFlags()
@@ -170,7 +185,7 @@ func printFlags() {
 		// flags or fix as these have no effect on unitchecker
 		// (as invoked by 'go vet').
 		switch f.Name {
-		case "debug", "cpuprofile", "memprofile", "trace", "fix":
+		case "debug", "cpuprofile", "memprofile", "trace", "fix", "cache":
 			return
 		}
 
@@ -322,7 +337,7 @@ func PrintPlain(out io.Writer, fset *token.FileSet, contextLines int, diag analy
 	fmt.Fprintf(out, "%s: %s\n", posn, diag.Message)
 
 	// show offending line plus N lines of context.
-	if contextLines >= 0 {
+	if contextLines >= 0 && posn.IsValid() {
 		posn := fset.Position(diag.Pos)
 		end := fset.Position(diag.End)
 		if !end.IsValid() {
@@ -369,6 +384,8 @@ type JSONDiagnostic struct {
 	Message        string                   `json:"message"`
 	SuggestedFixes []JSONSuggestedFix       `json:"suggested_fixes,omitempty"`
 	Related        []JSONRelatedInformation `json:"related,omitempty"`
+	Scope          string                   `json:"scope,omitempty"` // "file" or "package"; omitted for a position
+	Data           json.RawMessage          `json:"data,omitempty"`
 }
 
 // A JSONRelated describes a secondary position and message related to
@@ -422,6 +439,14 @@ func (tree JSONTree) Add(fset *token.FileSet, id, name string, diags []analysis.
 				SuggestedFixes: fixes,
 				Related:        related,
 			}
+			if f.Scope != analysis.ScopePosition {
+				jdiag.Scope = f.Scope.String()
+			}
+			if f.Data != nil {
+				if data, err := json.Marshal(f.Data); err == nil {
+					jdiag.Data = data
+				}
+			}
 			diagnostics = append(diagnostics, jdiag)
 		}
 		v = diagnostics
@@ -5,9 +5,13 @@
 package analysisflags_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"go/token"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -17,11 +21,13 @@ import (
 )
 
 func main() {
+	a1 := &analysis.Analyzer{Name: "a1", Doc: "a1"}
+	s := a1.Flags.String("s", "default", "a string")
 	fmt.Println(analysisflags.Parse([]*analysis.Analyzer{
-		{Name: "a1", Doc: "a1"},
+		a1,
 		{Name: "a2", Doc: "a2"},
 		{Name: "a3", Doc: "a3"},
-	}, true))
+	}, true), "s="+*s, "c="+fmt.Sprint(analysisflags.Context))
 	os.Exit(0)
 }
 
@@ -40,6 +46,14 @@ func TestExec(t *testing.T) {
 		panic("unreachable")
 	}
 
+	config := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(config, []byte(`{
+	"analyzers": {"a2": false},
+	"flags": {"a1.s": "fromfile", "c": 2}
+}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
 	for _, test := range []struct {
 		flags string
 		want  string // output should contain want
@@ -51,6 +65,9 @@ func TestExec(t *testing.T) {
 		{"-a1=1 -a3=1", "[a1 a3]"},
 		{"-a1=1 -a3=0", "[a1]"},
 		{"-V=full", "analysisflags.test version devel"},
+		{"", "[a1 a2 a3] s=default c=-1"},
+		{"-config=" + config, "[a1 a3] s=fromfile c=2"},
+		{"-config=" + config + " -a2=1 -a1.s=flag", "[a2] s=flag c=2"},
 	} {
 		cmd := exec.Command(progname, "-test.run=TestExec")
 		cmd.Env = append(os.Environ(), "ANALYSISFLAGS_CHILD=1", "FLAGS="+test.flags)
@@ -66,3 +83,43 @@ func TestExec(t *testing.T) {
 		}
 	}
 }
+
+func TestJSONTreeScopeAndData(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("a.go", -1, 100)
+	file.SetLinesForContent([]byte("package a\n"))
+
+	tree := make(analysisflags.JSONTree)
+	tree.Add(fset, "a", "an", []analysis.Diagnostic{
+		{Pos: file.Pos(0), Message: "pos"},
+		{Pos: file.Pos(0), Message: "file", Scope: analysis.ScopeFile},
+		{Message: "pkg", Scope: analysis.ScopePackage, Data: map[string]int{"n": 1}},
+	}, nil)
+	var buf bytes.Buffer
+	if err := tree.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]map[string][]analysisflags.JSONDiagnostic
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	diags := got["a"]["an"]
+	if len(diags) != 3 {
+		t.Fatalf("got %d diagnostics, want 3:\n%s", len(diags), buf.Bytes())
+	}
+	for i, want := range []struct{ scope, data string }{
+		{"", ""},
+		{"file", ""},
+		{"package", `{"n":1}`},
+	} {
+		var data bytes.Buffer
+		if diags[i].Data != nil {
+			json.Compact(&data, diags[i].Data)
+		}
+		if diags[i].Scope != want.scope || data.String() != want.data {
+			t.Errorf("diagnostic %d: got scope %q, data %s; want scope %q, data %s",
+				i, diags[i].Scope, data.String(), want.scope, want.data)
+		}
+	}
+}
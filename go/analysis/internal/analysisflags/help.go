@@ -42,6 +42,8 @@ func Help(progname string, analyzers []*analysis.Analyzer, args []string) {
 		fmt.Println("\nBy default all analyzers are run.")
 		fmt.Println("To select specific analyzers, use the -NAME flag for each one,")
 		fmt.Println(" or -NAME=false to run all analyzers not explicitly disabled.")
+		fmt.Println("Analyzers and flags may also be set by a JSON file named by -config,")
+		fmt.Println(` such as {"analyzers": {"NAME": false}, "flags": {"NAME.flag": "value"}}.`)
 
 		// Show only the core command-line flags.
 		fmt.Println("\nCore flags:")
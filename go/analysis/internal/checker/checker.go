@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysiscache"
 	"golang.org/x/tools/go/analysis/checker"
 	"golang.org/x/tools/go/analysis/internal/analysisflags"
 	"golang.org/x/tools/go/packages"
@@ -57,6 +58,9 @@ var (
 
 	// Fix determines whether to apply all suggested fixes.
 	Fix bool
+
+	// CacheDir, if set, is the directory in which to cache analysis results.
+	CacheDir string
 )
 
 // RegisterFlags registers command-line flags used by the analysis driver.
@@ -72,6 +76,8 @@ func RegisterFlags() {
 	flag.BoolVar(&IncludeTests, "test", IncludeTests, "indicates whether test files should be analyzed, too")
 
 	flag.BoolVar(&Fix, "fix", false, "apply all suggested fixes")
+
+	flag.StringVar(&CacheDir, "cache", "", "cache analysis results in this directory")
 }
 
 // Run loads the packages specified by args using go/packages,
@@ -161,6 +167,14 @@ func Run(args []string, analyzers []*analysis.Analyzer) int {
 		Sequential:  dbg('p'),
 		FactLog:     factLog,
 	}
+	if CacheDir != "" {
+		cache, err := analysiscache.New(CacheDir)
+		if err != nil {
+			log.Print(err)
+			return 1
+		}
+		opts.Cache = cache
+	}
 	if dbg('v') {
 		log.Printf("building graph of analysis passes")
 	}
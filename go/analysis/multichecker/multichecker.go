@@ -5,6 +5,19 @@
 // Package multichecker defines the main function for an analysis driver
 // with several analyzers. This package makes it easy for anyone to build
 // an analysis tool containing just the analyzers they need.
+//
+// In addition to the -NAME flag that enables or disables each
+// analyzer, and the -NAME.flag flags of the analyzers, the tool accepts
+// a -config=file flag that names a JSON file of the same settings:
+//
+//	{
+//		"analyzers": {"shadow": false},
+//		"flags": {"printf.funcs": "Logf,Warnf"}
+//	}
+//
+// Flags set explicitly on the command line take precedence over the
+// file. This allows an organization to distribute a single checker
+// along with a centrally managed configuration.
 package multichecker
 
 import (
@@ -72,16 +72,20 @@ type asmFunc struct {
 	size        int // size of all arguments
 	vars        map[string]*asmVar
 	varByOffset map[int]*asmVar
+	nosplit     bool // Go declaration has a //go:nosplit directive
+	noescape    bool // Go declaration has a //go:noescape directive
 }
 
 // An asmVar describes a single assembly variable.
 type asmVar struct {
-	name  string
-	kind  asmKind
-	typ   string
-	off   int
-	size  int
-	inner []*asmVar
+	name   string
+	kind   asmKind
+	typ    string
+	off    int
+	size   int
+	inner  []*asmVar
+	result bool // variable is (part of) a result
+	ptrArg bool // variable is a pointer, or the pointer word of a string, slice, or interface, of an argument
 }
 
 var (
@@ -164,11 +168,19 @@ func run(pass *analysis.Pass) (interface{}, error) {
 
 	// Gather declarations. knownFunc[name][arch] is func description.
 	knownFunc := make(map[string]map[string]*asmFunc)
+	genericFunc := make(map[string]bool)
 
 	for _, f := range pass.Files {
 		for _, decl := range f.Decls {
-			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body == nil {
-				knownFunc[decl.Name.Name] = asmParseDecl(pass, decl)
+			if decl, ok := decl.(*ast.FuncDecl); ok {
+				// The frame layout of a generic function depends
+				// on its instantiation, so it cannot be implemented
+				// in assembly, even to replace a Go body.
+				if fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func); ok && isGeneric(fn) {
+					genericFunc[decl.Name.Name] = true
+				} else if decl.Body == nil {
+					knownFunc[decl.Name.Name] = asmParseDecl(pass, decl)
+				}
 			}
 		}
 	}
@@ -201,6 +213,7 @@ Files:
 			noframe            bool
 			haveRetArg         bool
 			retLine            []int
+			ptrRegs            map[string]string // registers holding pointer arguments, for noescape checks
 		)
 
 		flushRet := func() {
@@ -294,13 +307,21 @@ Files:
 				}
 				// Trim off optional ABI selector.
 				fnName, abi = trimABI(fnName)
+				if abi == "ABIInternal" && len(archDef.retRegs) == 0 {
+					// Architectures without a register-based
+					// calling convention use ABI0 for ABIInternal.
+					abi = ""
+				}
 				flag := m[3]
 				fn = knownFunc[fnName][arch]
 				if fn != nil {
 					size, _ := strconv.Atoi(m[5])
-					if size != fn.size && (flag != "7" && !strings.Contains(flag, "NOSPLIT") || size != 0) {
+					if size != fn.size && (!isNosplit(flag) || size != 0) {
 						badf("wrong argument size %d; expected $...-%d", size, fn.size)
 					}
+					if fn.nosplit && !isNosplit(flag) {
+						badf("//go:nosplit directive of Go declaration has no effect; TEXT needs NOSPLIT flag")
+					}
 				}
 				localSize, _ = strconv.Atoi(m[4])
 				localSize += archDef.intSize
@@ -310,11 +331,14 @@ Files:
 				}
 				argSize, _ = strconv.Atoi(m[5])
 				noframe = strings.Contains(flag, "NOFRAME")
-				if fn == nil && !strings.Contains(fnName, "<>") && !noframe {
+				if fn == nil && genericFunc[fnName] {
+					badf("function %s is generic and cannot be implemented in assembly", fnName)
+				} else if fn == nil && !strings.Contains(fnName, "<>") && !noframe {
 					badf("function %s missing Go declaration", fnName)
 				}
 				wroteSP = false
 				haveRetArg = false
+				ptrRegs = nil
 				continue
 			} else if strings.Contains(line, "TEXT") && strings.Contains(line, "SB") {
 				// function, but not visible from Go (didn't match asmTEXT), so stop checking
@@ -395,6 +419,10 @@ Files:
 				continue
 			}
 
+			if fn.noescape {
+				ptrRegs = checkNoescape(badf, fn, line, ptrRegs)
+			}
+
 			for _, m := range asmUnnamedFP.FindAllStringSubmatch(line, -1) {
 				off, _ := strconv.Atoi(m[2])
 				v := fn.varByOffset[off]
@@ -411,7 +439,7 @@ Files:
 				if m[2] != "" {
 					off, _ = strconv.Atoi(m[2])
 				}
-				if name == "ret" || strings.HasPrefix(name, "ret_") {
+				if (name == "ret" || strings.HasPrefix(name, "ret_")) && abi != "ABIInternal" {
 					haveRetArg = true
 				}
 				v := fn.vars[name]
@@ -428,6 +456,12 @@ Files:
 					}
 					continue
 				}
+				if v.result && abi == "ABIInternal" {
+					// Results have no stack slots in the
+					// register-based calling convention.
+					badf("use of %s in ABIInternal function; results are returned in registers", m[0])
+					continue
+				}
 				asmCheckVar(badf, fn, line, m[0], off, v, archDef)
 			}
 		}
@@ -472,6 +506,7 @@ type component struct {
 	typ    string
 	suffix string // Such as _base for string base, _0_lo for lo half of first element of [1]uint64 on 32 bit machine.
 	outer  string // The suffix for immediately containing composite type.
+	ptr    bool   // Whether the component is a pointer, or the pointer word of a string, slice, or interface.
 }
 
 func newComponent(suffix string, kind asmKind, typ string, offset, size int, outer string) component {
@@ -492,6 +527,7 @@ func appendComponentsRecursive(arch *asmArch, t types.Type, cc []component, suff
 	size := int(arch.sizes.Sizeof(t))
 	kind := asmKindForType(t, size)
 	cc = append(cc, newComponent(suffix, kind, s, off, size, suffix))
+	cc[len(cc)-1].ptr = isPointer(t)
 
 	switch kind {
 	case 8:
@@ -507,18 +543,22 @@ func appendComponentsRecursive(arch *asmArch, t types.Type, cc []component, suff
 	case asmEmptyInterface:
 		cc = append(cc, newComponent(suffix+"_type", asmKind(arch.ptrSize), "interface type", off, arch.ptrSize, suffix))
 		cc = append(cc, newComponent(suffix+"_data", asmKind(arch.ptrSize), "interface data", off+arch.ptrSize, arch.ptrSize, suffix))
+		cc[len(cc)-1].ptr = true
 
 	case asmInterface:
 		cc = append(cc, newComponent(suffix+"_itable", asmKind(arch.ptrSize), "interface itable", off, arch.ptrSize, suffix))
 		cc = append(cc, newComponent(suffix+"_data", asmKind(arch.ptrSize), "interface data", off+arch.ptrSize, arch.ptrSize, suffix))
+		cc[len(cc)-1].ptr = true
 
 	case asmSlice:
 		cc = append(cc, newComponent(suffix+"_base", asmKind(arch.ptrSize), "slice base", off, arch.ptrSize, suffix))
+		cc[len(cc)-1].ptr = true
 		cc = append(cc, newComponent(suffix+"_len", asmKind(arch.intSize), "slice len", off+arch.ptrSize, arch.intSize, suffix))
 		cc = append(cc, newComponent(suffix+"_cap", asmKind(arch.intSize), "slice cap", off+arch.ptrSize+arch.intSize, arch.intSize, suffix))
 
 	case asmString:
 		cc = append(cc, newComponent(suffix+"_base", asmKind(arch.ptrSize), "string base", off, arch.ptrSize, suffix))
+		cc[len(cc)-1].ptr = true
 		cc = append(cc, newComponent(suffix+"_len", asmKind(arch.intSize), "string len", off+arch.ptrSize, arch.intSize, suffix))
 
 	case asmComplex:
@@ -606,11 +646,13 @@ func asmParseDecl(pass *analysis.Pass, decl *ast.FuncDecl) map[string]*asmFunc {
 				for _, c := range cc {
 					outer := name + c.outer
 					v := asmVar{
-						name: name + c.suffix,
-						kind: c.kind,
-						typ:  c.typ,
-						off:  offset + c.offset,
-						size: c.size,
+						name:   name + c.suffix,
+						kind:   c.kind,
+						typ:    c.typ,
+						off:    offset + c.offset,
+						size:   c.size,
+						result: isret,
+						ptrArg: !isret && c.ptr,
 					}
 					if vo := fn.vars[outer]; vo != nil {
 						vo.inner = append(vo.inner, &v)
@@ -625,12 +667,26 @@ func asmParseDecl(pass *analysis.Pass, decl *ast.FuncDecl) map[string]*asmFunc {
 		}
 	}
 
+	var nosplit, noescape bool
+	if decl.Doc != nil {
+		for _, c := range decl.Doc.List {
+			switch strings.TrimSpace(c.Text) {
+			case "//go:nosplit":
+				nosplit = true
+			case "//go:noescape":
+				noescape = true
+			}
+		}
+	}
+
 	m := make(map[string]*asmFunc)
 	for _, arch = range arches {
 		fn = &asmFunc{
 			arch:        arch,
 			vars:        make(map[string]*asmVar),
 			varByOffset: make(map[int]*asmVar),
+			nosplit:     nosplit,
+			noescape:    noescape,
 		}
 		offset = 0
 		addParams(decl.Type.Params.List, false)
@@ -645,6 +701,80 @@ func asmParseDecl(pass *analysis.Pass, decl *ast.FuncDecl) map[string]*asmFunc {
 	return m
 }
 
+// isGeneric reports whether fn is generic, or a method of a generic type.
+func isGeneric(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	return sig.TypeParams().Len() > 0 || sig.RecvTypeParams().Len() > 0
+}
+
+// isPointer reports whether values of type t are pointers.
+func isPointer(t types.Type) bool {
+	switch t := t.Underlying().(type) {
+	case *types.Pointer, *types.Chan, *types.Map, *types.Signature:
+		return true
+	case *types.Basic:
+		return t.Kind() == types.UnsafePointer
+	}
+	return false
+}
+
+// isNosplit reports whether the flag operand of a TEXT instruction
+// includes NOSPLIT, by name or by value.
+func isNosplit(flag string) bool {
+	if n, err := strconv.Atoi(flag); err == nil {
+		return n&4 != 0 // NOSPLIT in textflag.h
+	}
+	return strings.Contains(flag, "NOSPLIT")
+}
+
+// checkNoescape checks that a line of a function declared //go:noescape
+// does not store a pointer argument in a result or a global variable,
+// which would make the pointer escape. It follows pointer arguments
+// through the registers to which they are moved: ptrRegs maps each
+// register holding a pointer argument to the name of the argument, and
+// checkNoescape returns its updated value.
+func checkNoescape(badf func(string, ...interface{}), fn *asmFunc, line string, ptrRegs map[string]string) map[string]string {
+	m := asmOpcode.FindStringSubmatch(line)
+	if m == nil {
+		return ptrRegs
+	}
+	op, src, dst := m[1], strings.TrimSpace(m[2]), strings.TrimSpace(m[3])
+	switch {
+	case strings.HasPrefix(op, "CALL"):
+		return nil // calls clobber registers
+	case !strings.HasPrefix(op, "MOV") || dst == "" || strings.Contains(dst, ","):
+		return ptrRegs
+	}
+
+	// fpVar returns the variable of an operand such as x+8(FP).
+	fpVar := func(operand string) *asmVar {
+		if m := asmNamedFP.FindStringSubmatch(operand); m != nil && m[0] == operand {
+			return fn.vars[m[1]]
+		}
+		return nil
+	}
+
+	if !strings.Contains(dst, "(") {
+		// Move to a register.
+		if v := fpVar(src); v != nil && v.ptrArg {
+			if ptrRegs == nil {
+				ptrRegs = make(map[string]string)
+			}
+			ptrRegs[dst] = v.name
+		} else {
+			delete(ptrRegs, dst)
+		}
+	} else if arg, ok := ptrRegs[src]; ok {
+		// Store of a pointer argument to memory.
+		if v := fpVar(dst); v != nil && v.result {
+			badf("pointer argument %s escapes to result %s, but function is declared //go:noescape", arg, v.name)
+		} else if strings.HasSuffix(dst, "(SB)") {
+			badf("pointer argument %s escapes to global %s, but function is declared //go:noescape", arg, dst)
+		}
+	}
+	return ptrRegs
+}
+
 // asmCheckVar checks a single variable reference.
 func asmCheckVar(badf func(string, ...interface{}), fn *asmFunc, line, expr string, off int, v *asmVar, archDef *asmArch) {
 	m := asmOpcode.FindStringSubmatch(line)
@@ -57,3 +57,16 @@ func returnmissingABIInternal() int
 func returnsyscallABIInternal() int
 
 func retjmp() int
+
+//go:nosplit
+func nosplitdirective(x int)
+
+var sink *byte
+
+//go:noescape
+func noescape(p *int, s []byte, n int) (*int, int)
+
+func resultFPABIInternal() int
+func returnABI0Internal() int
+
+func generic[T any](x T) {}
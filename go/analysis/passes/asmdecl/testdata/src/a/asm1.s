@@ -362,3 +362,29 @@ TEXT ·returnsyscallABIInternal<ABIInternal>(SB), NOSPLIT, $0
 // return jump
 TEXT ·retjmp(SB), NOSPLIT, $0-8
 	RET	retjmp1(SB) // It's okay to not write results if there's a tail call.
+
+// //go:nosplit directive in Go declaration
+TEXT ·nosplitdirective(SB), 0, $0-8 // want `//go:nosplit directive of Go declaration has no effect; TEXT needs NOSPLIT flag`
+	RET
+
+// pointer arguments of //go:noescape function
+TEXT ·noescape(SB), NOSPLIT, $0-56
+	MOVQ	p+0(FP), AX
+	MOVQ	AX, ret+40(FP) // want `pointer argument p escapes to result ret, but function is declared //go:noescape`
+	MOVQ	s_base+8(FP), BX
+	MOVQ	BX, ·sink(SB) // want `pointer argument s_base escapes to global ·sink\(SB\), but function is declared //go:noescape`
+	MOVQ	s_len+16(FP), CX
+	MOVQ	CX, ret1+48(FP)
+	MOVQ	n+32(FP), AX
+	MOVQ	AX, ret+40(FP) // ok: AX no longer holds p
+	RET
+
+// writing to result slot in ABIInternal function
+TEXT ·resultFPABIInternal<ABIInternal>(SB), NOSPLIT, $0
+	MOVQ	$123, AX
+	MOVQ	AX, ret+0(FP) // want `use of ret\+0\(FP\) in ABIInternal function; results are returned in registers`
+	RET
+
+// generic function
+TEXT ·generic(SB), NOSPLIT, $0-16 // want `function generic is generic and cannot be implemented in assembly`
+	RET
@@ -254,3 +254,8 @@ TEXT ·returnnamed(SB),0,$0-21
 
 TEXT ·returnintmissing(SB),0,$0-4
 	RET // want `RET without writing to 4-byte ret\+0\(FP\)`
+
+// ABIInternal is ABI0 on 386
+TEXT ·returnABI0Internal<ABIInternal>(SB), NOSPLIT, $0-4
+	MOVL	$123, AX
+	RET // want `RET without writing to 4-byte ret\+0\(FP\)`
@@ -50,6 +50,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		(*ast.GenDecl)(nil),
 		(*ast.RangeStmt)(nil),
 		(*ast.ReturnStmt)(nil),
+		(*ast.SendStmt)(nil),
 	}
 	inspect.WithStack(nodeFilter, func(node ast.Node, push bool, stack []ast.Node) bool {
 		if !push {
@@ -74,6 +75,8 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			checkCopyLocksCompositeLit(pass, node)
 		case *ast.ReturnStmt:
 			checkCopyLocksReturnStmt(pass, node)
+		case *ast.SendStmt:
+			checkCopyLocksSendStmt(pass, node)
 		}
 		return true
 	})
@@ -146,6 +149,13 @@ func checkCopyLocksReturnStmt(pass *analysis.Pass, rs *ast.ReturnStmt) {
 	}
 }
 
+// checkCopyLocksSendStmt detects lock copy in a channel send
+func checkCopyLocksSendStmt(pass *analysis.Pass, ss *ast.SendStmt) {
+	if path := lockPathRhs(pass, ss.Value); path != nil {
+		pass.ReportRangef(ss.Value, "send copies lock value: %v", path)
+	}
+}
+
 // checkCopyLocksCallExpr detects lock copy in the arguments to a function call
 func checkCopyLocksCallExpr(pass *analysis.Pass, ce *ast.CallExpr) {
 	var id *ast.Ident
@@ -0,0 +1,33 @@
+package a
+
+import (
+	"fmt"
+	"sync"
+)
+
+func SendStmt() {
+	var mu sync.Mutex
+	var t struct{ mu sync.Mutex }
+	c := make(chan sync.Mutex, 1)
+	tc := make(chan struct{ mu sync.Mutex }, 1)
+
+	c <- mu   // want "send copies lock value: sync.Mutex"
+	tc <- t   // want "send copies lock value: struct{mu sync.Mutex} contains sync.Mutex"
+	c <- t.mu // want "send copies lock value: sync.Mutex"
+	select {
+	case c <- mu: // want "send copies lock value: sync.Mutex"
+	default:
+	}
+
+	// Sending a zero value or a result does not copy an existing lock.
+	c <- sync.Mutex{}
+	c <- newMutex()
+
+	pc := make(chan *sync.Mutex, 1)
+	pc <- &mu
+
+	_ = fmt.Sprintf("%v", t) // want "call of fmt.Sprintf copies lock value: struct{mu sync.Mutex} contains sync.Mutex"
+	_ = fmt.Sprintf("%v", &t)
+}
+
+func newMutex() sync.Mutex { return sync.Mutex{} }
@@ -0,0 +1,26 @@
+// This file tests that locks are found in types declared in other
+// packages, including through instantiations of generic types and
+// type aliases.
+
+package crosspkg
+
+import (
+	"crosspkg/dep"
+	"sync"
+)
+
+func _(b dep.Box[sync.Mutex]) {} // want "passes lock by value: crosspkg/dep.Box\\[sync.Mutex\\] contains sync.Mutex"
+
+func _(a dep.Alias) {} // want "passes lock by value: crosspkg/dep.Guarded contains sync.Mutex"
+
+func _(n dep.Nested) {} // want "passes lock by value: crosspkg/dep.Nested contains crosspkg/dep.Box\\[crosspkg/dep.Guarded\\] contains crosspkg/dep.Guarded contains sync.Mutex"
+
+func _(ok dep.Box[*sync.Mutex]) {}
+
+func _(c chan dep.Alias, n *dep.Nested) {
+	c <- dep.Alias{}
+	var a dep.Alias
+	c <- a  // want "send copies lock value: crosspkg/dep.Guarded contains sync.Mutex"
+	x := *n // want "assignment copies lock value to x: crosspkg/dep.Nested contains .*"
+	_ = &x
+}
@@ -0,0 +1,11 @@
+package dep
+
+import "sync"
+
+type Box[T any] struct{ V T }
+
+type Guarded struct{ mu sync.Mutex }
+
+type Alias = Guarded
+
+type Nested struct{ b Box[Guarded] }
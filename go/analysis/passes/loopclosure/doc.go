@@ -50,7 +50,13 @@
 //	}
 //
 // A fix is the same as before. The checker also reports problems
-// in goroutines started by golang.org/x/sync/errgroup.Group.
+// in goroutines started by golang.org/x/sync/errgroup.Group, and in
+// function literals passed to other functions that may call them
+// asynchronously: those that refer to their function parameters in a
+// go statement, send them on a channel, store them in a variable or
+// field for later use, or pass them to another such function. These
+// functions are identified across package boundaries, so that custom
+// worker pools and schedulers are also checked.
 // A hard-to-spot variant of this form is common in parallel tests:
 //
 //	func Test(t *testing.T) {
@@ -6,8 +6,11 @@ package loopclosure
 
 import (
 	_ "embed"
+	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"slices"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -23,16 +26,28 @@ import (
 var doc string
 
 var Analyzer = &analysis.Analyzer{
-	Name:     "loopclosure",
-	Doc:      analysisutil.MustExtractDoc(doc, "loopclosure"),
-	URL:      "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/loopclosure",
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      run,
+	Name:      "loopclosure",
+	Doc:       analysisutil.MustExtractDoc(doc, "loopclosure"),
+	URL:       "https://pkg.go.dev/golang.org/x/tools/go/analysis/passes/loopclosure",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(asyncFunc)},
 }
 
+// asyncFunc is a fact indicating that a function may call some of its
+// function parameters asynchronously, such as in another goroutine or
+// after storing them for later use.
+type asyncFunc struct{ Params []int } // indices of the parameters, in order
+
+func (*asyncFunc) AFact() {}
+
+func (f *asyncFunc) String() string { return fmt.Sprintf("async%v", f.Params) }
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
+	findAsyncFuncs(pass, inspect)
+
 	nodeFilter := []ast.Node{
 		(*ast.File)(nil),
 		(*ast.RangeStmt)(nil),
@@ -98,9 +113,11 @@ func run(pass *analysis.Pass) (interface{}, error) {
 				stmts = litStmts(s.Call.Fun)
 			case *ast.DeferStmt:
 				stmts = litStmts(s.Call.Fun)
-			case *ast.ExprStmt: // check for errgroup.Group.Go
+			case *ast.ExprStmt: // check for errgroup.Group.Go and other asynchronous calls
 				if call, ok := s.X.(*ast.CallExpr); ok {
-					stmts = litStmts(goInvoke(pass.TypesInfo, call))
+					for _, arg := range asyncArgs(pass, call) {
+						stmts = append(stmts, litStmts(arg)...)
+					}
 				}
 			}
 			for _, stmt := range stmts {
@@ -230,6 +247,155 @@ func goInvoke(info *types.Info, call *ast.CallExpr) ast.Expr {
 	return call.Args[0]
 }
 
+// asyncArgs returns the arguments of the call that may be called
+// asynchronously (but not awaited) as a consequence of the call: the
+// function passed to errgroup.Group.Go, and those passed for the
+// parameters of a function with an asyncFunc fact.
+func asyncArgs(pass *analysis.Pass, call *ast.CallExpr) []ast.Expr {
+	if fun := goInvoke(pass.TypesInfo, call); fun != nil {
+		return []ast.Expr{fun}
+	}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil
+	}
+	var fact asyncFunc
+	if !pass.ImportObjectFact(fn.Origin(), &fact) {
+		return nil
+	}
+	// In a call to a method expression, T.f(x, ...),
+	// the receiver is the first argument.
+	offset := 0
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if seln, ok := pass.TypesInfo.Selections[sel]; ok && seln.Kind() == types.MethodExpr {
+			offset = 1
+		}
+	}
+	var args []ast.Expr
+	for _, i := range fact.Params {
+		if i+offset < len(call.Args) {
+			args = append(args, call.Args[i+offset])
+		}
+	}
+	return args
+}
+
+// findAsyncFuncs exports an asyncFunc fact for each function declared
+// in the package that may call one of its function parameters
+// asynchronously: that is, it refers to the parameter within a go
+// statement, sends it on a channel, stores it in a variable that is
+// not local, or passes it to a function that does one of those things.
+func findAsyncFuncs(pass *analysis.Pass, inspect *inspector.Inspector) {
+	type declInfo struct {
+		fn     *types.Func
+		body   *ast.BlockStmt
+		params []*types.Var // non-variadic parameters of function type; nil for others
+		async  []int
+	}
+	var decls []*declInfo
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+		if !ok || decl.Body == nil {
+			return
+		}
+		sig := fn.Type().(*types.Signature)
+		var params []*types.Var
+		found := false
+		for i := range sig.Params().Len() {
+			v := sig.Params().At(i)
+			if _, ok := v.Type().Underlying().(*types.Signature); ok && !(sig.Variadic() && i == sig.Params().Len()-1) {
+				params = append(params, v)
+				found = true
+			} else {
+				params = append(params, nil)
+			}
+		}
+		if found {
+			decls = append(decls, &declInfo{fn: fn, body: decl.Body, params: params})
+		}
+	})
+
+	// Iterate to a fixed point, as the functions of the package
+	// may pass their parameters to one another.
+	for changed := true; changed; {
+		changed = false
+		for _, d := range decls {
+			for i, v := range d.params {
+				if v != nil && !slices.Contains(d.async, i) && launches(pass, d.body, v) {
+					d.async = append(d.async, i)
+					slices.Sort(d.async)
+					pass.ExportObjectFact(d.fn, &asyncFunc{Params: slices.Clone(d.async)})
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// launches reports whether the body may call the function parameter v
+// asynchronously. See findAsyncFuncs.
+func launches(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) bool {
+	info := pass.TypesInfo
+	isParam := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && info.Uses[id] == v
+	}
+	refersTo := func(n ast.Node) bool {
+		found := false
+		ast.Inspect(n, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && info.Uses[id] == v {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+	// isStored reports whether e, assigned to lhs, is v or an
+	// append of v, and lhs is not a local variable.
+	isStored := func(lhs, e ast.Expr) bool {
+		if id, ok := ast.Unparen(lhs).(*ast.Ident); ok {
+			obj, ok := info.ObjectOf(id).(*types.Var)
+			if !ok || obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+				return false // blank, or a local variable
+			}
+		}
+		if isParam(e) {
+			return true
+		}
+		if call, ok := ast.Unparen(e).(*ast.CallExpr); ok && len(call.Args) > 1 {
+			if b, ok := typeutil.Callee(info, call).(*types.Builtin); ok && b.Name() == "append" {
+				return slices.ContainsFunc(call.Args[1:], isParam)
+			}
+		}
+		return false
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			found = refersTo(n)
+		case *ast.SendStmt:
+			found = isParam(n.Value)
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, rhs := range n.Rhs {
+					if isStored(n.Lhs[i], rhs) {
+						found = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			found = slices.ContainsFunc(asyncArgs(pass, n), func(arg ast.Expr) bool {
+				return refersTo(arg)
+			})
+		}
+		return !found
+	})
+	return found
+}
+
 // parallelSubtest returns statements that can be easily proven to execute
 // concurrently via the go test runner, as t.Run has been invoked with a
 // function literal that calls t.Parallel.
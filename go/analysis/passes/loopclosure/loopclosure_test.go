@@ -17,3 +17,8 @@ func TestVersions(t *testing.T) {
 	dir := testfiles.ExtractTxtarFileToTmp(t, filepath.Join(analysistest.TestData(), "src", "versions", "go22.txtar"))
 	analysistest.Run(t, dir, loopclosure.Analyzer, "golang.org/fake/versions")
 }
+
+func TestAsyncFuncs(t *testing.T) {
+	dir := testfiles.ExtractTxtarFileToTmp(t, filepath.Join(analysistest.TestData(), "src", "async", "async.txtar"))
+	analysistest.Run(t, dir, loopclosure.Analyzer, "golang.org/fake/async/...")
+}
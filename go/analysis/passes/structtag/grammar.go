@@ -0,0 +1,180 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package structtag
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// This file implements checking of the values of custom struct tag
+// keys, whose grammar is declared using the -grammar flag.
+
+func init() {
+	Analyzer.Flags.Var(&grammars, "grammar",
+		"declare the grammar of a custom struct tag key, in the form key=form:option,...\n"+
+			"(may be repeated); form is 'name' for values of the form name,option,...\n"+
+			"or 'opts' for values of the form option,...; an option ending in '='\n"+
+			"requires a value, as in min=")
+}
+
+// grammars holds the grammars declared by the -grammar flag.
+var grammars = grammarsFlag{}
+
+// A tagGrammar describes the syntax of the value of a struct tag key.
+type tagGrammar struct {
+	key     string
+	named   bool            // value begins with a name, like json's
+	options map[string]bool // permitted options; true if the option takes a value
+}
+
+// grammarsFlag is a flag.Value that accumulates tag grammars, keyed
+// by tag key.
+type grammarsFlag map[string]*tagGrammar
+
+func (gf *grammarsFlag) String() string {
+	var specs []string
+	for _, g := range *gf {
+		specs = append(specs, g.String())
+	}
+	sort.Strings(specs)
+	return strings.Join(specs, " ")
+}
+
+func (gf *grammarsFlag) Set(spec string) error {
+	g, err := parseGrammar(spec)
+	if err != nil {
+		return err
+	}
+	if *gf == nil {
+		*gf = make(grammarsFlag)
+	}
+	(*gf)[g.key] = g
+	return nil
+}
+
+func (g *tagGrammar) String() string {
+	form := "opts"
+	if g.named {
+		form = "name"
+	}
+	var opts []string
+	for opt, hasValue := range g.options {
+		if hasValue {
+			opt += "="
+		}
+		opts = append(opts, opt)
+	}
+	sort.Strings(opts)
+	return fmt.Sprintf("%s=%s:%s", g.key, form, strings.Join(opts, ","))
+}
+
+// parseGrammar parses a grammar declaration of the form
+// key=form[:option,...].
+func parseGrammar(spec string) (*tagGrammar, error) {
+	key, rest, ok := strings.Cut(spec, "=")
+	if !ok || key == "" {
+		return nil, fmt.Errorf("invalid tag grammar %q: want key=form:option,...", spec)
+	}
+	if checkTagSpaces[key] || key == "json" || key == "xml" {
+		return nil, fmt.Errorf("invalid tag grammar %q: cannot redefine built-in key %q", spec, key)
+	}
+	form, opts, _ := strings.Cut(rest, ":")
+	g := &tagGrammar{key: key, options: make(map[string]bool)}
+	switch form {
+	case "name":
+		g.named = true
+	case "opts":
+	default:
+		return nil, fmt.Errorf("invalid tag grammar %q: form must be 'name' or 'opts', not %q", spec, form)
+	}
+	if opts != "" {
+		for _, opt := range strings.Split(opts, ",") {
+			name, hasValue := strings.CutSuffix(opt, "=")
+			if name == "" || strings.ContainsAny(name, " =\"") {
+				return nil, fmt.Errorf("invalid tag grammar %q: bad option %q", spec, opt)
+			}
+			g.options[name] = hasValue
+		}
+	}
+	return g, nil
+}
+
+// check reports an error if value does not conform to the grammar.
+func (g *tagGrammar) check(value string) error {
+	if value == "-" {
+		return nil // conventionally means "ignore this field"
+	}
+	elems := strings.Split(value, ",")
+	if g.named {
+		if strings.TrimSpace(elems[0]) != elems[0] {
+			return fmt.Errorf("suspicious space in name %q", elems[0])
+		}
+		elems = elems[1:]
+	}
+	seen := make(map[string]bool)
+	for _, elem := range elems {
+		if elem == "" {
+			return fmt.Errorf("empty option")
+		}
+		if strings.TrimSpace(elem) != elem {
+			return fmt.Errorf("suspicious space in option %q", elem)
+		}
+		name, optValue, hasValue := strings.Cut(elem, "=")
+		takesValue, known := g.options[name]
+		switch {
+		case !known:
+			return fmt.Errorf("unknown option %q", name)
+		case seen[name]:
+			return fmt.Errorf("duplicate option %q", name)
+		case takesValue && (!hasValue || optValue == ""):
+			return fmt.Errorf("option %q requires a value", name)
+		case !takesValue && hasValue:
+			return fmt.Errorf("option %q does not take a value", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// checkGrammars checks the tag of a single struct field against the
+// declared grammars.
+func checkGrammars(pass *analysis.Pass, field *types.Var, tag string) {
+	for _, key := range sortedKeys(grammars) {
+		value, ok := reflect.StructTag(tag).Lookup(key)
+		if !ok {
+			continue
+		}
+		if err := grammars[key].check(value); err != nil {
+			pass.Reportf(field.Pos(), "struct field tag %s:%q: %v", key, value, err)
+		}
+	}
+}
+
+// namedGrammarKeys returns the sorted keys of the declared grammars
+// whose values begin with a name, whose uniqueness is checked.
+func namedGrammarKeys() []string {
+	var keys []string
+	for _, key := range sortedKeys(grammars) {
+		if grammars[key].named {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func sortedKeys(m grammarsFlag) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -23,7 +23,20 @@ import (
 
 const Doc = `check that struct field tags conform to reflect.StructTag.Get
 
-Also report certain struct tags (json, xml) used with unexported fields.`
+Also report certain struct tags (json, xml) used with unexported fields.
+
+The grammar of other tag keys may be declared using the -grammar flag,
+which may be repeated. For example,
+
+	-grammar=bson=name:omitempty,inline,minsize
+	-grammar=validate=opts:required,email,min=,max=
+
+declares that the value of a bson tag is a name followed by options
+drawn from the given list, as with json, and that the value of a
+validate tag is a list of options, of which min and max require a
+value (as in validate:"required,min=1"). Values of declared keys are
+checked for unknown, duplicate, and malformed options, and the names
+of keys of the first form are checked for duplicates, as with json.`
 
 var Analyzer = &analysis.Analyzer{
 	Name:             "structtag",
@@ -98,9 +111,14 @@ func checkCanonicalFieldTag(pass *analysis.Pass, field *types.Var, tag string, s
 	for _, key := range checkTagDups {
 		checkTagDuplicates(pass, tag, key, field, field, seen, 1)
 	}
+	for _, key := range namedGrammarKeys() {
+		checkTagDuplicates(pass, tag, key, field, field, seen, 1)
+	}
 
 	if err := validateStructTag(tag); err != nil {
 		pass.Reportf(field.Pos(), "struct field tag %#q not compatible with reflect.StructTag.Get: %s", tag, err)
+	} else {
+		checkGrammars(pass, field, tag)
 	}
 
 	// Check for use of json or xml tags with unexported fields.
@@ -15,3 +15,30 @@ func Test(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, structtag.Analyzer, "a")
 }
+
+func TestGrammar(t *testing.T) {
+	for _, spec := range []string{
+		"bson=name:omitempty,inline,minsize",
+		"validate=opts:required,email,min=,max=",
+	} {
+		if err := structtag.Analyzer.Flags.Set("grammar", spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, structtag.Analyzer, "grammar")
+}
+
+func TestGrammarFlagErrors(t *testing.T) {
+	for _, spec := range []string{
+		"bson",
+		"=name:a",
+		"bson=list:a",
+		"bson=name:a,,b",
+		"json=name:a",
+	} {
+		if err := structtag.Analyzer.Flags.Set("grammar", spec); err == nil {
+			t.Errorf("Set(grammar, %q) succeeded, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains tests for custom tag grammars declared using the
+// -grammar flag.
+
+package grammar
+
+type Doc struct {
+	ID      string `bson:"_id,omitempty"`
+	Name    string `bson:"name,omitempty,minsize"`
+	Alias   string `bson:"name"` // want `struct field Alias repeats bson tag "name" also at grammar.go:12`
+	Inline  Inner  `bson:",inline"`
+	Skipped string `bson:"-"`
+	Bad     string `bson:"bad,omitempty,omitempty"` // want `struct field tag bson:"bad,omitempty,omitempty": duplicate option "omitempty"`
+	Typo    string `bson:"typo,omitemtpy"`          // want `struct field tag bson:"typo,omitemtpy": unknown option "omitemtpy"`
+	Empty   string `bson:"empty,,minsize"`          // want `struct field tag bson:"empty,,minsize": empty option`
+	Space   string `bson:" space"`                  // want `struct field tag bson:" space": suspicious space in name " space"`
+
+	Email string `validate:"required,email"`
+	Age   int    `validate:"min=1,max=130"`
+	Min   int    `validate:"min"`             // want `struct field tag validate:"min": option "min" requires a value`
+	Req   int    `validate:"required=true"`   // want `struct field tag validate:"required=true": option "required" does not take a value`
+	Anon  string `validate:"required, email"` // want `struct field tag validate:"required, email": suspicious space in option " email"`
+
+	Other string `other:"anything,goes"`
+}
+
+type Inner struct {
+	X string `bson:"x"`
+}
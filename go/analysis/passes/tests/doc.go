@@ -15,4 +15,21 @@
 //
 // Please see the documentation for package testing in golang.org/pkg/testing
 // for the conventions that are enforced for Tests, Benchmarks, and Examples.
+//
+// The tests checker also reports misuses of t.Parallel in Test functions
+// and their subtests: calls to t.Setenv or t.Chdir in a parallel test,
+// or in a subtest of one, which panic; and defer statements in a test
+// whose subtests call t.Parallel. Such a deferred call runs when the
+// test function returns, before the parallel subtests have run:
+//
+//	func TestFoo(t *testing.T) {
+//		db := openDB()
+//		defer db.Close() // closes db before the subtests run; use t.Cleanup
+//		for _, tc := range cases {
+//			t.Run(tc.name, func(t *testing.T) {
+//				t.Parallel()
+//				...
+//			})
+//		}
+//	}
 package tests
@@ -0,0 +1,69 @@
+package a
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDeferWithParallelSubtests(t *testing.T) {
+	f, _ := os.CreateTemp("", "")
+	defer os.Remove(f.Name()) // want `deferred call runs before parallel subtests complete; use \(\*testing.T\).Cleanup instead`
+
+	for _, name := range []string{"a", "b"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_ = f
+		})
+	}
+}
+
+func TestDeferWithSequentialSubtests(t *testing.T) {
+	f, _ := os.CreateTemp("", "")
+	defer os.Remove(f.Name()) // ok: the subtests complete before the test returns
+
+	t.Run("a", func(t *testing.T) {
+		_ = f
+	})
+}
+
+func TestDeferWithGroupedParallelSubtests(t *testing.T) {
+	f, _ := os.CreateTemp("", "")
+	defer os.Remove(f.Name()) // ok: t.Run("group") waits for its parallel subtests
+
+	t.Run("group", func(t *testing.T) {
+		t.Run("a", func(t *testing.T) {
+			t.Parallel()
+			_ = f
+		})
+	})
+}
+
+func TestCleanupWithParallelSubtests(t *testing.T) {
+	f, _ := os.CreateTemp("", "")
+	t.Cleanup(func() { os.Remove(f.Name()) }) // ok
+
+	t.Run("a", func(t *testing.T) {
+		t.Parallel()
+		defer func() {}() // ok: no parallel subtests
+		_ = f
+	})
+}
+
+func TestSetenvParallel(t *testing.T) {
+	t.Setenv("K", "V") // want `call to \(\*testing.T\).Setenv panics in a parallel test`
+	t.Parallel()
+}
+
+func TestSetenvInSubtestOfParallel(t *testing.T) {
+	t.Parallel()
+	t.Run("a", func(t *testing.T) {
+		t.Setenv("K", "V") // want `call to \(\*testing.T\).Setenv panics in a subtest of a parallel test`
+	})
+}
+
+func TestSetenvSequential(t *testing.T) {
+	t.Setenv("K", "V") // ok
+	t.Run("a", func(t *testing.T) {
+		t.Parallel() // ok: Setenv was called by the parent, which is not parallel
+	})
+}
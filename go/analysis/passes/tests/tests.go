@@ -64,6 +64,7 @@ func run(pass *analysis.Pass) (interface{}, error) {
 				checkExampleOutput(pass, fn, f.Comments)
 			case strings.HasPrefix(fn.Name.Name, "Test"):
 				checkTest(pass, fn, "Test")
+				checkParallel(pass, fn)
 			case strings.HasPrefix(fn.Name.Name, "Benchmark"):
 				checkTest(pass, fn, "Benchmark")
 			case strings.HasPrefix(fn.Name.Name, "Fuzz"):
@@ -484,3 +485,99 @@ func checkTest(pass *analysis.Pass, fn *ast.FuncDecl, prefix string) {
 		pass.ReportRangef(fn.Name, "%s has malformed name: first letter after '%s' must not be lowercase", fn.Name.Name, prefix)
 	}
 }
+
+// checkParallel checks the use of t.Parallel by a test function and
+// the subtests it starts using t.Run with a function literal:
+//
+//  1. A test whose subtests call t.Parallel should not use defer for
+//     teardown, since the parallel subtests do not run until the test
+//     function returns. Use t.Cleanup instead.
+//  2. t.Setenv and t.Chdir panic when called by a parallel test, or by
+//     a subtest of one.
+//
+// Captured loop variables and calls to t.Fatal from other goroutines
+// are reported by the loopclosure and testinggoroutine analyzers.
+func checkParallel(pass *analysis.Pass, fn *ast.FuncDecl) {
+	if t := testingTParam(pass, fn.Type); t != nil {
+		checkParallelBody(pass, t, fn.Body, false)
+	}
+}
+
+// checkParallelBody checks the body of a test function whose
+// *testing.T parameter is t, and reports whether it calls t.Parallel.
+// parentParallel reports whether any enclosing test is parallel.
+func checkParallelBody(pass *analysis.Pass, t *types.Var, body *ast.BlockStmt, parentParallel bool) bool {
+	var (
+		parallel bool
+		envCalls []*ast.CallExpr
+		defers   []*ast.DeferStmt
+		subtests []*ast.FuncLit
+	)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // subtests are visited below
+		case *ast.DeferStmt:
+			defers = append(defers, n)
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				break
+			}
+			if id, ok := sel.X.(*ast.Ident); !ok || pass.TypesInfo.Uses[id] != t {
+				break
+			}
+			switch sel.Sel.Name {
+			case "Parallel":
+				parallel = true
+			case "Setenv", "Chdir":
+				envCalls = append(envCalls, n)
+			case "Run":
+				if len(n.Args) == 2 {
+					if lit, ok := n.Args[1].(*ast.FuncLit); ok {
+						subtests = append(subtests, lit)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	for _, call := range envCalls {
+		sel := call.Fun.(*ast.SelectorExpr)
+		if parallel {
+			pass.ReportRangef(call, "call to (*testing.T).%s panics in a parallel test", sel.Sel.Name)
+		} else if parentParallel {
+			pass.ReportRangef(call, "call to (*testing.T).%s panics in a subtest of a parallel test", sel.Sel.Name)
+		}
+	}
+
+	parallelSubtests := false
+	for _, lit := range subtests {
+		if subT := testingTParam(pass, lit.Type); subT != nil {
+			if checkParallelBody(pass, subT, lit.Body, parallel || parentParallel) {
+				parallelSubtests = true
+			}
+		}
+	}
+	if parallelSubtests {
+		for _, stmt := range defers {
+			pass.ReportRangef(stmt, "deferred call runs before parallel subtests complete; use (*testing.T).Cleanup instead")
+		}
+	}
+
+	return parallel
+}
+
+// testingTParam returns the variable of the sole parameter of the
+// function type, if it is a named parameter of type *testing.T.
+func testingTParam(pass *analysis.Pass, ftype *ast.FuncType) *types.Var {
+	if ftype.Params == nil || len(ftype.Params.List) != 1 || len(ftype.Params.List[0].Names) != 1 {
+		return nil
+	}
+	v, _ := pass.TypesInfo.Defs[ftype.Params.List[0].Names[0]].(*types.Var)
+	if v == nil || !isTestingType(v.Type(), "T") {
+		return nil
+	}
+	return v
+}
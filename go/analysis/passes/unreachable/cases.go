@@ -0,0 +1,342 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unreachable
+
+// This file defines the checks for case clauses that can never match.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkTypeSwitch reports the types of the type switch that can never
+// match because every value of the type is matched by an earlier case.
+func checkTypeSwitch(pass *analysis.Pass, stmt *ast.TypeSwitchStmt) {
+	info := pass.TypesInfo
+
+	// Find the static type of the operand, x in x.(type).
+	var assert *ast.TypeAssertExpr
+	switch s := stmt.Assign.(type) {
+	case *ast.AssignStmt:
+		assert, _ = s.Rhs[0].(*ast.TypeAssertExpr)
+	case *ast.ExprStmt:
+		assert, _ = s.X.(*ast.TypeAssertExpr)
+	}
+	if assert == nil {
+		return
+	}
+	xtype := info.TypeOf(assert.X)
+	if xtype == nil {
+		return
+	}
+
+	// earlier holds the interface type of each earlier case.
+	type earlierCase struct {
+		expr ast.Expr
+		typ  *types.Interface
+	}
+	var earlier []earlierCase
+
+	// matchedBy returns the earlier case that matches every non-nil
+	// value of type t, if any.
+	matchedBy := func(t types.Type) ast.Expr {
+		for _, e := range earlier {
+			if types.Implements(t, e.typ) || types.Implements(xtype, e.typ) {
+				return e.expr
+			}
+		}
+		return nil
+	}
+
+	for _, clause := range stmt.Body.List {
+		cc := clause.(*ast.CaseClause)
+		var unreachable []ast.Expr // types of cc matched by earlier cases
+		var by []ast.Expr          // the corresponding earlier cases
+		for _, e := range cc.List {
+			tv, ok := info.Types[e]
+			if !ok || !tv.IsType() || isTypeParam(tv.Type) {
+				continue // nil, or ill-typed
+			}
+			if earlier := matchedBy(tv.Type); earlier != nil {
+				unreachable = append(unreachable, e)
+				by = append(by, earlier)
+			}
+		}
+		for _, e := range cc.List {
+			if tv, ok := info.Types[e]; ok && tv.IsType() && !isTypeParam(tv.Type) {
+				if iface, ok := tv.Type.Underlying().(*types.Interface); ok {
+					earlier = append(earlier, earlierCase{e, iface})
+				}
+			}
+		}
+		if len(unreachable) == 0 {
+			continue
+		}
+
+		if len(unreachable) == len(cc.List) {
+			// The entire clause is unreachable.
+			pass.Report(analysis.Diagnostic{
+				Pos:     cc.Case,
+				End:     cc.Colon + 1,
+				Message: fmt.Sprintf("unreachable case: %s is matched by the earlier case %s", types.ExprString(unreachable[0]), types.ExprString(by[0])),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   "Remove",
+					TextEdits: []analysis.TextEdit{{Pos: cc.Pos(), End: cc.End()}},
+				}},
+			})
+		} else {
+			for i, e := range unreachable {
+				pass.Report(analysis.Diagnostic{
+					Pos:     e.Pos(),
+					End:     e.End(),
+					Message: fmt.Sprintf("unreachable case: %s is matched by the earlier case %s", types.ExprString(e), types.ExprString(by[i])),
+				})
+			}
+		}
+	}
+}
+
+// checkSwitch reports the clauses of a switch statement without a tag
+// whose conditions, which compare an integer variable with constants,
+// can never be true because the values they allow are all handled by
+// earlier clauses:
+//
+//	switch {
+//	case x < 10:
+//	case x < 5: // unreachable
+//	}
+func checkSwitch(pass *analysis.Pass, stmt *ast.SwitchStmt) {
+	if stmt.Tag != nil {
+		return
+	}
+	covered := make(map[*types.Var]intSet) // values handled by earlier clauses
+	fellThrough := false
+	for _, clause := range stmt.Body.List {
+		cc := clause.(*ast.CaseClause)
+
+		// A call or receive may change the variable
+		// before a later case is evaluated.
+		for _, e := range cc.List {
+			if hasEffects(e) {
+				return
+			}
+		}
+
+		var (
+			v   *types.Var
+			set intSet
+		)
+		for _, e := range cc.List {
+			ev, eset := comparison(pass.TypesInfo, e)
+			if ev == nil || v != nil && ev != v {
+				v = nil
+				break
+			}
+			v, set = ev, set.union(eset)
+		}
+		if v != nil {
+			// A clause reached by fallthrough is not dead,
+			// though its condition may be.
+			if !fellThrough && set.subsetOf(covered[v]) {
+				msg := fmt.Sprintf("unreachable case: every value of %s that satisfies it is handled by an earlier case", v.Name())
+				if len(set) == 0 {
+					msg = "unreachable case: condition is never true"
+				}
+				pass.Report(analysis.Diagnostic{
+					Pos:     cc.Case,
+					End:     cc.Colon + 1,
+					Message: msg,
+					SuggestedFixes: []analysis.SuggestedFix{{
+						Message:   "Remove",
+						TextEdits: []analysis.TextEdit{{Pos: cc.Pos(), End: cc.End()}},
+					}},
+				})
+			}
+			covered[v] = covered[v].union(set)
+		}
+
+		fellThrough = false
+		if n := len(cc.Body); n > 0 {
+			if branch, ok := cc.Body[n-1].(*ast.BranchStmt); ok && branch.Tok == token.FALLTHROUGH {
+				fellThrough = true
+			}
+		}
+	}
+}
+
+// comparison returns the variable and the set of its values for
+// which the condition e is true, if e is a comparison of an integer
+// variable with constants, or a combination of such comparisons of a
+// single variable using && and ||. Otherwise it returns nil.
+func comparison(info *types.Info, e ast.Expr) (*types.Var, intSet) {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND, token.LOR:
+			xv, xset := comparison(info, e.X)
+			yv, yset := comparison(info, e.Y)
+			if xv == nil || xv != yv {
+				return nil, nil
+			}
+			if e.Op == token.LAND {
+				return xv, xset.intersect(yset)
+			}
+			return xv, xset.union(yset)
+
+		case token.LSS, token.LEQ, token.GTR, token.GEQ, token.EQL, token.NEQ:
+			op, x, y := e.Op, e.X, e.Y
+			if info.Types[x].Value != nil {
+				// c op x => x op' c
+				x, y = y, x
+				switch op {
+				case token.LSS:
+					op = token.GTR
+				case token.LEQ:
+					op = token.GEQ
+				case token.GTR:
+					op = token.LSS
+				case token.GEQ:
+					op = token.LEQ
+				}
+			}
+			id, ok := ast.Unparen(x).(*ast.Ident)
+			if !ok {
+				return nil, nil
+			}
+			v, ok := info.Uses[id].(*types.Var)
+			if !ok {
+				return nil, nil
+			}
+			if basic, ok := v.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+				return nil, nil
+			}
+			c := info.Types[y].Value
+			if c == nil || c.Kind() != constant.Int {
+				return nil, nil
+			}
+			one := constant.MakeInt64(1)
+			switch op {
+			case token.LSS:
+				return v, intSet{{nil, constant.BinaryOp(c, token.SUB, one)}}
+			case token.LEQ:
+				return v, intSet{{nil, c}}
+			case token.GTR:
+				return v, intSet{{constant.BinaryOp(c, token.ADD, one), nil}}
+			case token.GEQ:
+				return v, intSet{{c, nil}}
+			case token.EQL:
+				return v, intSet{{c, c}}
+			case token.NEQ:
+				return v, intSet{{nil, constant.BinaryOp(c, token.SUB, one)}, {constant.BinaryOp(c, token.ADD, one), nil}}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// hasEffects reports whether the expression contains a call or a
+// receive operation.
+func hasEffects(e ast.Expr) bool {
+	effects := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			effects = true
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				effects = true
+			}
+		}
+		return !effects
+	})
+	return effects
+}
+
+// An interval is the set of integers between lo and hi inclusive.
+// A nil bound is unbounded.
+type interval struct{ lo, hi constant.Value }
+
+// An intSet is a set of integers, represented as a sorted list of
+// disjoint, non-adjacent, non-empty intervals.
+type intSet []interval
+
+// lessLo reports whether the lower bound a is less than b.
+func lessLo(a, b constant.Value) bool {
+	return a == nil && b != nil || a != nil && b != nil && constant.Compare(a, token.LSS, b)
+}
+
+// lessHi reports whether the upper bound a is less than b.
+func lessHi(a, b constant.Value) bool {
+	return a != nil && b == nil || a != nil && b != nil && constant.Compare(a, token.LSS, b)
+}
+
+// union returns the union of the two sets.
+func (s intSet) union(t intSet) intSet {
+	all := append(append(intSet(nil), s...), t...)
+	sort.Slice(all, func(i, j int) bool { return lessLo(all[i].lo, all[j].lo) })
+	var res intSet
+	for _, iv := range all {
+		if n := len(res); n > 0 {
+			last := &res[n-1]
+			// Merge overlapping or adjacent intervals.
+			if last.hi == nil || iv.lo == nil || !constant.Compare(iv.lo, token.GTR, constant.BinaryOp(last.hi, token.ADD, constant.MakeInt64(1))) {
+				if lessHi(last.hi, iv.hi) {
+					last.hi = iv.hi
+				}
+				continue
+			}
+		}
+		res = append(res, iv)
+	}
+	return res
+}
+
+// intersect returns the intersection of the two sets.
+func (s intSet) intersect(t intSet) intSet {
+	var res intSet
+	for _, a := range s {
+		for _, b := range t {
+			iv := a
+			if lessLo(iv.lo, b.lo) {
+				iv.lo = b.lo
+			}
+			if lessHi(b.hi, iv.hi) {
+				iv.hi = b.hi
+			}
+			if iv.lo == nil || iv.hi == nil || constant.Compare(iv.lo, token.LEQ, iv.hi) {
+				res = append(res, iv)
+			}
+		}
+	}
+	return res.union(nil)
+}
+
+// subsetOf reports whether every element of s belongs to t.
+func (s intSet) subsetOf(t intSet) bool {
+	for _, a := range s {
+		found := false
+		for _, b := range t {
+			if !lessLo(a.lo, b.lo) && !lessHi(b.hi, a.hi) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func isTypeParam(t types.Type) bool {
+	_, ok := types.Unalias(t).(*types.TypeParam)
+	return ok
+}
@@ -11,4 +11,23 @@
 // The unreachable analyzer finds statements that execution can never reach
 // because they are preceded by a return statement, a call to panic, an
 // infinite loop, or similar constructs.
+//
+// It also reports case clauses that can never be selected because
+// every value they match is matched by an earlier case: in a type
+// switch, a case whose type implements the interface type of an
+// earlier case, or any case after one whose interface type is
+// implemented by the type of the operand itself; and in a switch
+// without a tag, a case that compares an integer variable with
+// constants, all of whose values are handled by earlier cases of the
+// same form:
+//
+//	switch x.(type) {
+//	case io.Reader:
+//	case *os.File: // unreachable: *os.File implements io.Reader
+//	}
+//
+//	switch {
+//	case n < 10:
+//	case n < 5: // unreachable
+//	}
 package unreachable
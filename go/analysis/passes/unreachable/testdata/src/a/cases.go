@@ -0,0 +1,79 @@
+package unreachable
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func typeSwitches(x any, r io.Reader) {
+	switch x.(type) {
+	case io.Reader:
+		print(1)
+	case *os.File: // want `unreachable case: \*os.File is matched by the earlier case io.Reader`
+		print(2)
+	case io.ReadCloser: // want "unreachable case: io.ReadCloser is matched by the earlier case io.Reader"
+		print(3)
+	case int, fmt.Stringer:
+		print(4)
+	case string, *os.File: // want `unreachable case: \*os.File is matched by the earlier case io.Reader`
+		print(5)
+	case nil:
+		print(6)
+	default:
+		print(7)
+	}
+
+	// Every value of r is an io.Reader.
+	switch r := r.(type) {
+	case *os.File:
+		print(r)
+	case io.Reader:
+		print(r)
+	case fmt.Stringer: // want "unreachable case: fmt.Stringer is matched by the earlier case io.Reader"
+		print(r)
+	}
+}
+
+func exprSwitches(x, y int, u uint8) {
+	switch {
+	case x < 10:
+		print(1)
+	case x < 5: // want "unreachable case: every value of x that satisfies it is handled by an earlier case"
+		print(2)
+	case y < 5:
+		print(3)
+	case x >= 10 && x < 20:
+		print(4)
+	case x == 15 || x == 19: // want "unreachable case: every value of x that satisfies it is handled by an earlier case"
+		print(5)
+	case 19 >= x: // want "unreachable case: every value of x that satisfies it is handled by an earlier case"
+		print(6)
+	case x > 30 && x < 30: // want "unreachable case: condition is never true"
+		print(7)
+	case x != 27:
+		print(8)
+	case x > 100: // want "unreachable case: every value of x that satisfies it is handled by an earlier case"
+		print(9)
+	}
+
+	// A clause reached by fallthrough is not dead.
+	switch {
+	case u > 10:
+		fallthrough
+	case u > 20:
+		print(u)
+	}
+
+	// A call may change the variable.
+	switch {
+	case x < 10:
+		print(1)
+	case change(&x):
+		print(2)
+	case x < 5:
+		print(3)
+	}
+}
+
+func change(p *int) bool { *p = 0; return false }
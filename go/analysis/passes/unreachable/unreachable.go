@@ -58,6 +58,20 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		d.reachable = true
 		d.findDead(body)
 	})
+
+	// Check for case clauses that can never match.
+	switchFilter := []ast.Node{
+		(*ast.SwitchStmt)(nil),
+		(*ast.TypeSwitchStmt)(nil),
+	}
+	inspect.Preorder(switchFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.SwitchStmt:
+			checkSwitch(pass, n)
+		case *ast.TypeSwitchStmt:
+			checkTypeSwitch(pass, n)
+		}
+	})
 	return nil, nil
 }
 
@@ -20,6 +20,10 @@
 //	)
 //
 //	func main() { singlechecker.Main(findbadness.Analyzer) }
+//
+// The -config=file flag names a JSON file that sets the analyzer's
+// flags, such as {"flags": {"strict": true}}; see the multichecker
+// package for details.
 package singlechecker
 
 import (
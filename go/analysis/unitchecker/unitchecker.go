@@ -385,6 +385,10 @@ func run(fset *token.FileSet, cfg *Config, analyzers []*analysis.Analyzer) ([]re
 						log.Println(err)
 						d.SuggestedFixes = nil
 					}
+					if err := analysisinternal.ValidateDiagnostic(fset, a, d); err != nil {
+						log.Println(err)
+						d.Scope, d.Data = analysis.ScopePosition, nil
+					}
 					act.diagnostics = append(act.diagnostics, d)
 				},
 				ImportObjectFact:  facts.ImportObjectFact,
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -33,15 +34,7 @@ func AddNamedImport(fset *token.FileSet, f *ast.File, name, path string) (added
 		return false
 	}
 
-	newImport := &ast.ImportSpec{
-		Path: &ast.BasicLit{
-			Kind:  token.STRING,
-			Value: strconv.Quote(path),
-		},
-	}
-	if name != "" {
-		newImport.Name = &ast.Ident{Name: name}
-	}
+	newImport := newImportSpec(name, path)
 
 	// Find an import decl to add to.
 	// The goal is to find an existing import
@@ -130,37 +123,11 @@ func AddNamedImport(fset *token.FileSet, f *ast.File, name, path string) (added
 		// insert after the found import
 		insertAt = impIndex + 1
 	}
-	impDecl.Specs = append(impDecl.Specs, nil)
-	copy(impDecl.Specs[insertAt+1:], impDecl.Specs[insertAt:])
-	impDecl.Specs[insertAt] = newImport
 	pos := impDecl.Pos()
 	if insertAt > 0 {
-		// If there is a comment after an existing import, preserve the comment
-		// position by adding the new import after the comment.
-		if spec, ok := impDecl.Specs[insertAt-1].(*ast.ImportSpec); ok && spec.Comment != nil {
-			pos = spec.Comment.End()
-		} else {
-			// Assign same position as the previous import,
-			// so that the sorter sees it as being in the same block.
-			pos = impDecl.Specs[insertAt-1].Pos()
-		}
-	}
-	if newImport.Name != nil {
-		newImport.Name.NamePos = pos
-	}
-	newImport.Path.ValuePos = pos
-	newImport.EndPos = pos
-
-	// Clean up parens. impDecl contains at least one spec.
-	if len(impDecl.Specs) == 1 {
-		// Remove unneeded parens.
-		impDecl.Lparen = token.NoPos
-	} else if !impDecl.Lparen.IsValid() {
-		// impDecl needs parens added.
-		impDecl.Lparen = impDecl.Specs[0].Pos()
+		pos = posAfter(impDecl.Specs[insertAt-1].(*ast.ImportSpec))
 	}
-
-	f.Imports = append(f.Imports, newImport)
+	insertImport(f, impDecl, insertAt, newImport, pos)
 
 	if len(f.Decls) <= 1 {
 		return true
@@ -193,6 +160,329 @@ func AddNamedImport(fset *token.FileSet, f *ast.File, name, path string) (added
 	return true
 }
 
+// insertImport inserts the import spec at index i of the import decl,
+// at position pos.
+func insertImport(f *ast.File, decl *ast.GenDecl, i int, spec *ast.ImportSpec, pos token.Pos) {
+	decl.Specs = append(decl.Specs, nil)
+	copy(decl.Specs[i+1:], decl.Specs[i:])
+	decl.Specs[i] = spec
+
+	if spec.Name != nil {
+		spec.Name.NamePos = pos
+	}
+	spec.Path.ValuePos = pos
+	spec.EndPos = pos
+
+	// Clean up parens. decl contains at least one spec.
+	if len(decl.Specs) == 1 {
+		// Remove unneeded parens.
+		decl.Lparen = token.NoPos
+	} else if !decl.Lparen.IsValid() {
+		// decl needs parens added.
+		decl.Lparen = decl.Specs[0].Pos()
+	}
+
+	f.Imports = append(f.Imports, spec)
+}
+
+// posAfter returns the position for an import inserted after spec.
+func posAfter(spec *ast.ImportSpec) token.Pos {
+	// If there is a comment after an existing import, preserve the comment
+	// position by adding the new import after the comment.
+	if spec.Comment != nil {
+		return spec.Comment.End()
+	}
+	// Assign same position as the previous import,
+	// so that the sorter sees it as being in the same block.
+	return spec.Pos()
+}
+
+// posBefore returns the position for an import inserted before spec.
+// If spec has a doc comment, the position precedes it, and a line break
+// is added to tokFile, if non-nil, between the two, so that the comment
+// stays with spec.
+func posBefore(tokFile *token.File, spec *ast.ImportSpec) token.Pos {
+	if spec.Doc == nil {
+		return spec.Pos()
+	}
+	if tokFile != nil {
+		if offset := tokFile.Offset(spec.Doc.Pos()); offset > 0 {
+			addLines(tokFile, offset)
+			return spec.Doc.Pos() - 1
+		}
+	}
+	return spec.Doc.Pos()
+}
+
+// specStart returns the start of spec, including its doc comment.
+func specStart(spec *ast.ImportSpec) token.Pos {
+	if spec.Doc != nil {
+		return spec.Doc.Pos()
+	}
+	return spec.Pos()
+}
+
+// fileOf returns the token.File of f, or nil if it has no position.
+func fileOf(fset *token.FileSet, f *ast.File) *token.File {
+	if !f.Package.IsValid() {
+		return nil
+	}
+	return fset.File(f.Package)
+}
+
+// AddNamedImportBefore adds the import with the given name and path to
+// the file f, if absent, immediately before the import of the path
+// before, and thus in the same group of imports. If f does not import
+// before, it is equivalent to [AddNamedImport].
+func AddNamedImportBefore(fset *token.FileSet, f *ast.File, name, path, before string) (added bool) {
+	if imports(f, name, path) {
+		return false
+	}
+	decl, i := findImport(f, before)
+	if decl == nil {
+		return AddNamedImport(fset, f, name, path)
+	}
+	spec := decl.Specs[i].(*ast.ImportSpec)
+	insertImport(f, decl, i, newImportSpec(name, path), posBefore(fileOf(fset, f), spec))
+	return true
+}
+
+// AddNamedImportAfter adds the import with the given name and path to
+// the file f, if absent, immediately after the import of the path
+// after, and thus in the same group of imports. If f does not import
+// after, it is equivalent to [AddNamedImport].
+func AddNamedImportAfter(fset *token.FileSet, f *ast.File, name, path, after string) (added bool) {
+	if imports(f, name, path) {
+		return false
+	}
+	decl, i := findImport(f, after)
+	if decl == nil {
+		return AddNamedImport(fset, f, name, path)
+	}
+	spec := decl.Specs[i].(*ast.ImportSpec)
+	insertImport(f, decl, i+1, newImportSpec(name, path), posAfter(spec))
+	return true
+}
+
+// findImport returns the import decl of f that imports path, other
+// than an import of "C", and the index of the import spec within it.
+func findImport(f *ast.File, path string) (*ast.GenDecl, int) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || declImports(gen, "C") {
+			continue
+		}
+		for i, spec := range gen.Specs {
+			if importPath(spec.(*ast.ImportSpec)) == path {
+				return gen, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+func newImportSpec(name, path string) *ast.ImportSpec {
+	spec := &ast.ImportSpec{
+		Path: &ast.BasicLit{
+			Kind:  token.STRING,
+			Value: strconv.Quote(path),
+		},
+	}
+	if name != "" {
+		spec.Name = &ast.Ident{Name: name}
+	}
+	return spec
+}
+
+// An ImportGroup is one of the conventional groups of imports of a
+// file, which are separated by blank lines and appear in this order.
+type ImportGroup int
+
+const (
+	StdImports        ImportGroup = iota // packages of the standard library
+	ThirdPartyImports                    // packages of other modules
+	LocalImports                         // packages matching the local prefix
+)
+
+// ImportGroupOf returns the group of the import path. A path is local
+// if it has a prefix in localPrefix, a comma-separated list of import
+// path prefixes as for the -local flag of goimports, and is from the
+// standard library if its first element does not contain a dot.
+func ImportGroupOf(path, localPrefix string) ImportGroup {
+	if localPrefix != "" {
+		for _, p := range strings.Split(localPrefix, ",") {
+			if strings.HasPrefix(path, p) || strings.TrimSuffix(p, "/") == path {
+				return LocalImports
+			}
+		}
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if strings.Contains(first, ".") {
+		return ThirdPartyImports
+	}
+	return StdImports
+}
+
+// AddNamedImportToGroup adds the import with the given name and path to
+// the file f, if absent, in the specified group, as classified by
+// [ImportGroupOf] with the given local prefix.
+//
+// The import is inserted, in sorted order, into the first paragraph of
+// imports (a sequence of imports not separated by blank lines) all of
+// whose imports belong to group. If there is none, the import starts a
+// new paragraph, separated from the others by blank lines, before the
+// first paragraph of a later group. Unlike [AddNamedImport], it does
+// not merge the import declarations of f.
+func AddNamedImportToGroup(fset *token.FileSet, f *ast.File, name, path string, group ImportGroup, localPrefix string) (added bool) {
+	if imports(f, name, path) {
+		return false
+	}
+	tokFile := fileOf(fset, f)
+	paras := importParagraphs(tokFile, f)
+	if len(paras) == 0 {
+		return AddNamedImport(fset, f, name, path)
+	}
+	newImport := newImportSpec(name, path)
+
+	// Insert the import into the paragraph of the group, if any.
+	paraGroup := func(para importParagraph) ImportGroup {
+		g := ImportGroup(-1)
+		for _, spec := range para.decl.Specs[para.start:para.end] {
+			pg := ImportGroupOf(importPath(spec.(*ast.ImportSpec)), localPrefix)
+			if g >= 0 && pg != g {
+				return -1 // mixed groups
+			}
+			g = pg
+		}
+		return g
+	}
+	for _, para := range paras {
+		if paraGroup(para) != group {
+			continue
+		}
+		i := para.start
+		for i < para.end && importPath(para.decl.Specs[i].(*ast.ImportSpec)) < path {
+			i++
+		}
+		var pos token.Pos
+		if i > para.start {
+			pos = posAfter(para.decl.Specs[i-1].(*ast.ImportSpec))
+		} else {
+			pos = posBefore(tokFile, para.decl.Specs[i].(*ast.ImportSpec))
+		}
+		insertImport(f, para.decl, i, newImport, pos)
+		return true
+	}
+
+	// Otherwise, start a new paragraph before that of a later group.
+	// The printer separates two imports by a blank line if their
+	// lines differ by two or more, so add line breaks to the white
+	// space around the new import as needed.
+	for _, para := range paras {
+		if paraGroup(para) <= group {
+			continue
+		}
+		decl := para.decl
+		next := decl.Specs[para.start].(*ast.ImportSpec)
+		pos := specStart(next)
+		if tokFile != nil {
+			y := tokFile.Offset(pos)
+			if para.start > 0 {
+				// Between two paragraphs: add blank lines before and after.
+				prev := decl.Specs[para.start-1].(*ast.ImportSpec)
+				x := tokFile.Offset(specEnd(prev)) - 1 // last character of prev
+				if y-x >= 4 {
+					addLines(tokFile, x+1, x+2, y-1, y)
+					pos = tokFile.Pos(x + 2)
+				}
+			} else if decl.Lparen.IsValid() {
+				// At the start of the block: add a blank line after.
+				x := tokFile.Offset(decl.Lparen)
+				if y-x >= 3 {
+					addLines(tokFile, y-1, y)
+					pos = decl.Lparen + 1
+				}
+			}
+		}
+		insertImport(f, decl, para.start, newImport, pos)
+		return true
+	}
+
+	// Otherwise, start a new paragraph at the end of the last one.
+	last := paras[len(paras)-1]
+	decl := last.decl
+	prev := decl.Specs[last.end-1].(*ast.ImportSpec)
+	pos := posAfter(prev)
+	if tokFile != nil && decl.Rparen.IsValid() {
+		// Add a blank line before the new import.
+		end := specEnd(prev)
+		for _, cg := range f.Comments {
+			if cg.Pos() > end && cg.End() < decl.Rparen {
+				end = cg.End()
+			}
+		}
+		x := tokFile.Offset(end) - 1 // last character before the new import
+		y := tokFile.Offset(decl.Rparen)
+		if y-x >= 2 {
+			addLines(tokFile, y-1, y)
+			pos = decl.Rparen
+		}
+	}
+	insertImport(f, decl, last.end, newImport, pos)
+	return true
+}
+
+// An importParagraph is a sequence of import specs, decl.Specs[start:end],
+// not separated by blank lines.
+type importParagraph struct {
+	decl       *ast.GenDecl
+	start, end int
+}
+
+// importParagraphs returns the paragraphs of the import decls of f,
+// other than those of "C". If tokFile is nil, each decl is a paragraph.
+func importParagraphs(tokFile *token.File, f *ast.File) []importParagraph {
+	var paras []importParagraph
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || declImports(gen, "C") || len(gen.Specs) == 0 {
+			continue
+		}
+		start := 0
+		for i := 1; i < len(gen.Specs); i++ {
+			prev, spec := gen.Specs[i-1].(*ast.ImportSpec), gen.Specs[i].(*ast.ImportSpec)
+			if tokFile != nil && tokFile.Line(specStart(spec)) > tokFile.Line(specEnd(prev))+1 {
+				paras = append(paras, importParagraph{gen, start, i})
+				start = i
+			}
+		}
+		paras = append(paras, importParagraph{gen, start, len(gen.Specs)})
+	}
+	return paras
+}
+
+// specEnd returns the end of spec, including its line comment.
+func specEnd(spec *ast.ImportSpec) token.Pos {
+	if spec.Comment != nil {
+		return spec.Comment.End()
+	}
+	return spec.End()
+}
+
+// addLines adds line breaks to tokFile before each of the specified
+// offsets, which must lie in white space between tokens.
+func addLines(tokFile *token.File, offsets ...int) {
+	lines := tokFile.Lines()
+	for _, offset := range offsets {
+		if offset > 0 && offset < tokFile.Size() {
+			lines = append(lines, offset)
+		}
+	}
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+	tokFile.SetLines(lines)
+}
+
 func isThirdParty(importPath string) bool {
 	// Third party package import path usually contains "." (".com", ".org", ...)
 	// This logic is taken from golang.org/x/tools/imports package.
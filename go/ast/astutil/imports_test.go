@@ -9,6 +9,7 @@ import (
 	"go/ast"
 	"go/format"
 	"go/parser"
+	"go/printer"
 	"go/token"
 	"reflect"
 	"strconv"
@@ -859,6 +860,223 @@ type I int
 	}
 }
 
+var addGroupTests = []struct {
+	name  string
+	path  string
+	group ImportGroup
+	in    string
+	out   string
+}{
+	{
+		name:  "existing group",
+		path:  "example.com/b",
+		group: ThirdPartyImports,
+		in: `package main
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/a"
+	"example.com/c"
+
+	"local.com/x"
+)
+`,
+		out: `package main
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/a"
+	"example.com/b"
+	"example.com/c"
+
+	"local.com/x"
+)
+`,
+	},
+	{
+		name:  "start of existing group",
+		path:  "bytes",
+		group: StdImports,
+		in: `package main
+
+import (
+	// fmt is for printing.
+	"fmt"
+
+	"example.com/a"
+)
+`,
+		out: `package main
+
+import (
+	"bytes"
+	// fmt is for printing.
+	"fmt"
+
+	"example.com/a"
+)
+`,
+	},
+	{
+		name:  "std import not merged into local group",
+		path:  "os",
+		group: StdImports,
+		in: `package main
+
+import (
+	"fmt"
+
+	"local.com/os/x"
+)
+`,
+		out: `package main
+
+import (
+	"fmt"
+	"os"
+
+	"local.com/os/x"
+)
+`,
+	},
+	{
+		name:  "new group at end",
+		path:  "local.com/x",
+		group: LocalImports,
+		in: `package main
+
+import (
+	"fmt"
+
+	"example.com/a" // a comment
+)
+`,
+		out: `package main
+
+import (
+	"fmt"
+
+	"example.com/a" // a comment
+
+	"local.com/x"
+)
+`,
+	},
+	{
+		name:  "new group in middle",
+		path:  "example.com/a",
+		group: ThirdPartyImports,
+		in: `package main
+
+import (
+	"fmt"
+
+	// x is local.
+	"local.com/x"
+)
+`,
+		out: `package main
+
+import (
+	"fmt"
+
+	"example.com/a"
+
+	// x is local.
+	"local.com/x"
+)
+`,
+	},
+	{
+		name:  "new group at start",
+		path:  "fmt",
+		group: StdImports,
+		in: `package main
+
+import (
+	"example.com/a"
+)
+`,
+		out: `package main
+
+import (
+	"fmt"
+
+	"example.com/a"
+)
+`,
+	},
+	{
+		name:  "no imports",
+		path:  "fmt",
+		group: StdImports,
+		in: `package main
+`,
+		out: `package main
+
+import "fmt"
+`,
+	},
+}
+
+func TestAddNamedImportToGroup(t *testing.T) {
+	for _, test := range addGroupTests {
+		file := parse(t, test.name, test.in)
+		if got, want := ImportGroupOf(test.path, "local.com"), test.group; got != want {
+			t.Errorf("%s: ImportGroupOf(%q) = %v, want %v", test.name, test.path, got, want)
+		}
+		if !AddNamedImportToGroup(fset, file, "", test.path, test.group, "local.com") {
+			t.Errorf("%s: AddNamedImportToGroup returned false", test.name)
+		}
+		if got := print(t, test.name, file); got != test.out {
+			t.Errorf("%s:\ngot: %s\nwant: %s", test.name, got, test.out)
+		}
+		if AddNamedImportToGroup(fset, file, "", test.path, test.group, "local.com") {
+			t.Errorf("%s: second AddNamedImportToGroup returned true", test.name)
+		}
+	}
+}
+
+func TestAddNamedImportBeforeAfter(t *testing.T) {
+	const in = `package main
+
+import (
+	"fmt"
+
+	"example.com/a" // a comment
+	// c is documented.
+	"example.com/c"
+)
+`
+	const want = `package main
+
+import (
+	"fmt"
+
+	"example.com/a" // a comment
+	z "example.com/z"
+	y "example.com/y"
+	// c is documented.
+	"example.com/c"
+)
+`
+	file := parse(t, "beforeafter", in)
+	AddNamedImportAfter(fset, file, "z", "example.com/z", "example.com/a")
+	AddNamedImportBefore(fset, file, "y", "example.com/y", "example.com/c")
+	// Print without sorting the imports.
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, file); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
 var deleteTests = []test{
 	{
 		name: "import.4",
@@ -73,6 +73,79 @@ type Result struct {
 	// Types *A, A and B are accessible to reflection, but the unnamed
 	// type struct{B} is not.
 	RuntimeTypes typeutil.Map
+
+	// Reasons maps each reachable function to the set of events
+	// that caused it to become reachable, such as calls and
+	// runtime types. It is populated only if requested by
+	// [Config.RecordReasons]; otherwise it is nil.
+	//
+	// Unlike CallGraph, it records the reasons for reachability
+	// of methods of runtime types, which are not the targets of
+	// any call edge.
+	Reasons map[*ssa.Function][]Reason
+}
+
+// A Reason describes one cause of a function's reachability.
+type Reason struct {
+	Kind ReasonKind
+
+	// Caller is the function containing the call site, for
+	// StaticCall, DynamicCall, and InvokeCall reasons, or the
+	// (*reflect.Value).Call method for ReflectCall reasons.
+	Caller *ssa.Function
+
+	// Site is the call site, for StaticCall, DynamicCall,
+	// and InvokeCall reasons.
+	Site ssa.CallInstruction
+
+	// Type is the runtime type whose method was called, for
+	// InvokeCall reasons, or whose exported method is accessible
+	// to reflection, for RuntimeType reasons.
+	Type types.Type
+}
+
+// A ReasonKind indicates the kind of a [Reason].
+type ReasonKind int
+
+const (
+	Root        ReasonKind = iota // the function is one of the roots
+	StaticCall                    // the function is called directly
+	DynamicCall                   // the function value is called dynamically
+	InvokeCall                    // the method is called through an interface
+	ReflectCall                   // the function is address-taken and may be called by reflect.Value.Call
+	RuntimeType                   // the method is an exported method of a runtime type
+)
+
+func (k ReasonKind) String() string {
+	switch k {
+	case Root:
+		return "root"
+	case StaticCall:
+		return "static call"
+	case DynamicCall:
+		return "dynamic call"
+	case InvokeCall:
+		return "interface call"
+	case ReflectCall:
+		return "reflective call"
+	case RuntimeType:
+		return "runtime type"
+	}
+	return fmt.Sprintf("ReasonKind(%d)", int(k))
+}
+
+func (r Reason) String() string {
+	switch r.Kind {
+	case StaticCall, DynamicCall:
+		return fmt.Sprintf("%s from %s", r.Kind, r.Caller)
+	case InvokeCall:
+		return fmt.Sprintf("%s from %s on %s", r.Kind, r.Caller, r.Type)
+	case ReflectCall:
+		return r.Kind.String()
+	case RuntimeType:
+		return fmt.Sprintf("%s %s", r.Kind, r.Type)
+	}
+	return r.Kind.String()
 }
 
 // Working state of the RTA algorithm.
@@ -127,8 +200,11 @@ type interfaceTypeInfo struct {
 }
 
 // addReachable marks a function as potentially callable at run-time,
-// and ensures that it gets processed.
-func (r *rta) addReachable(f *ssa.Function, addrTaken bool) {
+// and ensures that it gets processed. why explains the cause.
+func (r *rta) addReachable(f *ssa.Function, addrTaken bool, why Reason) {
+	if r.result.Reasons != nil {
+		r.result.Reasons[f] = append(r.result.Reasons[f], why)
+	}
 	reachable := r.result.Reachable
 	n := len(reachable)
 	v := reachable[f]
@@ -145,8 +221,10 @@ func (r *rta) addReachable(f *ssa.Function, addrTaken bool) {
 // addEdge adds the specified call graph edge, and marks it reachable.
 // addrTaken indicates whether to mark the callee as "address-taken".
 // site is nil for calls made via reflection.
-func (r *rta) addEdge(caller *ssa.Function, site ssa.CallInstruction, callee *ssa.Function, addrTaken bool) {
-	r.addReachable(callee, addrTaken)
+// why.Caller and why.Site are set from caller and site.
+func (r *rta) addEdge(caller *ssa.Function, site ssa.CallInstruction, callee *ssa.Function, addrTaken bool, why Reason) {
+	why.Caller, why.Site = caller, site
+	r.addReachable(callee, addrTaken, why)
 
 	if g := r.result.CallGraph; g != nil {
 		if caller == nil {
@@ -177,7 +255,7 @@ func (r *rta) visitAddrTakenFunc(f *ssa.Function) {
 		// and add call graph edges.
 		sites, _ := r.dynCallSites.At(S).([]ssa.CallInstruction)
 		for _, site := range sites {
-			r.addEdge(site.Parent(), site, f, true)
+			r.addEdge(site.Parent(), site, f, true, Reason{Kind: DynamicCall})
 		}
 
 		// If the program includes (*reflect.Value).Call,
@@ -204,7 +282,7 @@ func (r *rta) visitAddrTakenFunc(f *ssa.Function) {
 		//   matters for e.g. deadcode detection.)
 		if r.reflectValueCall != nil {
 			var site ssa.CallInstruction = nil // can't find actual call site
-			r.addEdge(r.reflectValueCall, site, f, true)
+			r.addEdge(r.reflectValueCall, site, f, true, Reason{Kind: ReflectCall})
 		}
 	}
 }
@@ -221,7 +299,7 @@ func (r *rta) visitDynCall(site ssa.CallInstruction) {
 	// add an edge and mark it reachable.
 	funcs, _ := r.addrTakenFuncsBySig.At(S).(map[*ssa.Function]bool)
 	for g := range funcs {
-		r.addEdge(site.Parent(), site, g, true)
+		r.addEdge(site.Parent(), site, g, true, Reason{Kind: DynamicCall})
 	}
 }
 
@@ -232,7 +310,7 @@ func (r *rta) addInvokeEdge(site ssa.CallInstruction, C types.Type) {
 	// Ascertain the concrete method of C to be called.
 	imethod := site.Common().Method
 	cmethod := r.prog.LookupMethod(C, imethod.Pkg(), imethod.Name())
-	r.addEdge(site.Parent(), site, cmethod, true)
+	r.addEdge(site.Parent(), site, cmethod, true, Reason{Kind: InvokeCall, Type: C})
 }
 
 // visitInvoke is called each time the algorithm encounters an "invoke"-mode call.
@@ -266,7 +344,7 @@ func (r *rta) visitFunc(f *ssa.Function) {
 				if call.IsInvoke() {
 					r.visitInvoke(instr)
 				} else if g := call.StaticCallee(); g != nil {
-					r.addEdge(f, instr, g, false)
+					r.addEdge(f, instr, g, false, Reason{Kind: StaticCall})
 				} else if _, ok := call.Value.(*ssa.Builtin); !ok {
 					r.visitDynCall(instr)
 				}
@@ -306,6 +384,21 @@ func (r *rta) visitFunc(f *ssa.Function) {
 // graph; otherwise, only the other fields (reachable functions) are
 // populated.
 func Analyze(roots []*ssa.Function, buildCallGraph bool) *Result {
+	return (&Config{BuildCallGraph: buildCallGraph}).Analyze(roots)
+}
+
+// A Config specifies optional outputs of the analysis.
+// The zero value computes only the reachable functions
+// and runtime types.
+type Config struct {
+	BuildCallGraph bool // populate Result.CallGraph
+	RecordReasons  bool // populate Result.Reasons
+}
+
+// Analyze performs Rapid Type Analysis, starting at the specified
+// root functions, as described at the package-level [Analyze]
+// function. It returns nil if no roots were specified.
+func (c *Config) Analyze(roots []*ssa.Function) *Result {
 	if len(roots) == 0 {
 		return nil
 	}
@@ -315,7 +408,11 @@ func Analyze(roots []*ssa.Function, buildCallGraph bool) *Result {
 		prog:   roots[0].Prog,
 	}
 
-	if buildCallGraph {
+	if c.RecordReasons {
+		r.result.Reasons = make(map[*ssa.Function][]Reason)
+	}
+
+	if c.BuildCallGraph {
 		// TODO(adonovan): change callgraph API to eliminate the
 		// notion of a distinguished root node.  Some callgraphs
 		// have many roots, or none.
@@ -338,7 +435,7 @@ func Analyze(roots []*ssa.Function, buildCallGraph bool) *Result {
 	r.interfaceTypes.SetHasher(hasher)
 
 	for _, root := range roots {
-		r.addReachable(root, false)
+		r.addReachable(root, false, Reason{Kind: Root})
 	}
 
 	// Visit functions, processing their instructions, and adding
@@ -436,7 +533,7 @@ func (r *rta) addRuntimeType(T types.Type, skip bool) {
 
 			if m.Exported() {
 				// Exported methods are always potentially callable via reflection.
-				r.addReachable(r.prog.MethodValue(sel), true)
+				r.addReachable(r.prog.MethodValue(sel), true, Reason{Kind: RuntimeType, Type: T})
 			}
 		}
 
@@ -76,10 +76,11 @@ func TestRTA(t *testing.T) {
 
 			prog.Build()
 
-			res := rta.Analyze([]*ssa.Function{
+			config := &rta.Config{BuildCallGraph: true, RecordReasons: true}
+			res := config.Analyze([]*ssa.Function{
 				mainPkg.Func("main"),
 				mainPkg.Func("init"),
-			}, true)
+			})
 
 			check(t, f, mainPkg, res)
 		})
@@ -94,6 +95,7 @@ func TestRTA(t *testing.T) {
 //	edge      <func> --kind--> <func>	# call graph edge
 //	reachable <func>			# reachable function
 //	rtype     <type>			# run-time type descriptor needed
+//	reason    <func> <- <reason>		# reason for reachability
 //
 // Each line asserts that an element is found in the given set, or, if
 // the line is preceded by "!", that it is not in the set.
@@ -120,6 +122,7 @@ func check(t *testing.T, f *ast.File, pkg *ssa.Package, res *rta.Result) {
 		wantEdge      = make(map[string]bool)
 		wantReachable = make(map[string]bool)
 		wantRtype     = make(map[string]bool)
+		wantReason    = make(map[string]bool)
 	)
 	for _, line := range strings.Split(want, "\n") {
 		linenum++
@@ -153,6 +156,8 @@ func check(t *testing.T, f *ast.File, pkg *ssa.Package, res *rta.Result) {
 			want = wantReachable
 		case "rtype":
 			want = wantRtype
+		case "reason":
+			want = wantReason
 		default:
 			bad()
 		}
@@ -229,4 +234,25 @@ func check(t *testing.T, f *ast.File, pkg *ssa.Package, res *rta.Result) {
 		})
 		compare("rtype", got, wantRtype)
 	}
+
+	// Check reasons.
+	{
+		got := make(stringset)
+		for f, reasons := range res.Reasons {
+			if _, ok := res.Reachable[f]; !ok {
+				t.Errorf("reason recorded for unreachable function %s", f)
+			}
+			for _, r := range reasons {
+				str := fmt.Sprintf("%s <- %s", f.RelString(pkg.Pkg), r.Kind)
+				if r.Caller != nil && r.Kind != rta.ReflectCall {
+					str += " from " + r.Caller.RelString(pkg.Pkg)
+				}
+				if r.Type != nil {
+					str += " " + types.TypeString(r.Type, types.RelativeTo(pkg.Pkg))
+				}
+				got[str] = true
+			}
+		}
+		compare("reason", got, wantReason)
+	}
 }
@@ -0,0 +1,39 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vta
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/internal/vtainternal"
+)
+
+func init() {
+	vtainternal.TypeFlow = typeFlow
+}
+
+// typeFlow implements [vtainternal.TypeFlow].
+func typeFlow(funcs map[*ssa.Function]bool, initial *callgraph.Graph) func(ssa.Value) []types.Type {
+	b := builder{callees: makeCalleesFunc(funcs, initial)}
+	b.visit(funcs)
+	b.callees = nil
+	ptm := propagate(&b.graph, &b.canon)
+
+	return func(v ssa.Value) []types.Type {
+		switch v.(type) {
+		case *ssa.Const, *ssa.Global, *ssa.Function, *ssa.Parameter, *ssa.FreeVar, ssa.Instruction:
+		default:
+			return nil // e.g. *ssa.Builtin
+		}
+		var res []types.Type
+		ptm.propTypes(b.representative(b.nodeFromVal(v)))(func(p propType) bool {
+			res = append(res, p.typ)
+			return true
+		})
+		return res
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vta
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/internal/vtainternal"
+)
+
+func TestTypeFlow(t *testing.T) {
+	prog, want, err := testProg(t, "testdata/src/typeflow.go", ssa.BuilderMode(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flow := vtainternal.TypeFlow(ssautil.AllFunctions(prog), nil)
+
+	// Report the types flowing to each parameter of interface type.
+	var got []string
+	for fn := range ssautil.AllFunctions(prog) {
+		for _, p := range fn.Params {
+			if !types.IsInterface(p.Type()) {
+				continue
+			}
+			var ts []string
+			for _, t := range flow(p) {
+				ts = append(ts, types.TypeString(t, types.RelativeTo(fn.Pkg.Pkg)))
+			}
+			slices.Sort(ts)
+			got = append(got, fmt.Sprintf("%s: %s -> %s", fn.Name(), p.Name(), strings.Join(ts, ", ")))
+		}
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("got:\n%s\n\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
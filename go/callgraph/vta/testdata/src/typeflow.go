@@ -0,0 +1,47 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// go:build ignore
+
+package testdata
+
+type I interface {
+	Foo()
+}
+
+type A struct{}
+
+func (a A) Foo() {}
+
+type B struct{}
+
+func (b *B) Foo() {}
+
+type C struct{}
+
+func (c C) Foo() {}
+
+func Do(b bool) I {
+	if b {
+		return A{}
+	}
+	return &B{}
+}
+
+func Use(i I) {
+	i.Foo()
+}
+
+func Baz(b bool) {
+	Use(Do(b))
+	var c I = C{}
+	c.Foo()
+}
+
+// Relevant SSA:
+// func Use(i I):
+//   invoke i.Foo()
+
+// WANT:
+// Use: i -> *B, A
@@ -106,6 +106,30 @@ When the driver process emits in its response the name of a file
 that is a descendant of this directory, it must use an absolute path
 that has the value of PWD as a prefix, to ensure that the returned
 filenames satisfy the original query.
+
+# Driver sessions
+
+Version 2 of the driver protocol adds sessions, through which a client
+such as an editor may make many queries to a single long-lived driver
+process, and be notified by the driver of changes to the build
+configuration, such as edits to build files, so that it need not
+query the entire workspace again after each change.
+See [NewDriverSession].
+
+To request a session, the client runs the driver with no arguments
+and with the environment variable GOPACKAGESDRIVER_PROTOCOL=2. The
+client then writes a sequence of JSON-encoded [DriverQuery] messages to
+the driver's standard input, and the driver writes a sequence of
+JSON-encoded [DriverMessage] messages to its standard output.
+The first query, whose ID is zero, is also a valid [DriverRequest]; the
+driver must acknowledge the session by replying with a message whose
+Protocol is 2. A driver that implements only version 1 of the protocol
+replies instead with a [DriverResponse], and exits.
+
+The driver then replies to each query, in any order, with a message
+bearing the ID of the query, and may send [DriverChange] notifications
+at any time. The session ends when the client closes the driver's
+standard input, at which point the driver should exit.
 */
 package packages // import "golang.org/x/tools/go/packages"
 
@@ -78,40 +78,68 @@ type DriverResponse struct {
 	GoVersion int
 }
 
+// DriverQuery defines the schema of a query for package metadata sent to
+// an external driver during a session (see [DriverSession]). The query
+// patterns, which are command-line arguments outside of a session, are
+// part of the message.
+type DriverQuery struct {
+	DriverRequest
+
+	// ID identifies the query. The driver's reply bears the same ID.
+	// It is zero only for the first message of a session.
+	ID int64 `json:"id"`
+
+	Patterns []string `json:"patterns"`
+}
+
+// DriverMessage defines the schema of the messages sent by an external
+// driver during a session (see [DriverSession]). Each message is
+// either the handshake, a reply to a query, or a change notification.
+type DriverMessage struct {
+	// Protocol is set only in the first message of the session, by
+	// which the driver acknowledges that it supports sessions.
+	// It is the version of the driver protocol, 2.
+	Protocol int `json:"protocol,omitempty"`
+
+	// ID is the ID of the query to which this message is the reply,
+	// which is either Response, or Error if the query failed.
+	ID       int64           `json:"id,omitempty"`
+	Response *DriverResponse `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+
+	// Changed, if set, notifies the client of a change to the build
+	// configuration. It may be sent at any time after the handshake.
+	Changed *DriverChange `json:"changed,omitempty"`
+}
+
+// DriverChange notifies a client that the metadata of some packages
+// reported by a driver may have changed, for example because a build
+// file was edited, so that the client should query them again.
+type DriverChange struct {
+	// Packages holds the IDs of the packages that may have changed.
+	Packages []string `json:"packages,omitempty"`
+
+	// All indicates that the metadata of any package may have changed.
+	All bool `json:"all,omitempty"`
+}
+
+// driverProtocolEnv is the environment variable that requests a
+// session from a driver (see [DriverSession]).
+const driverProtocolEnv = "GOPACKAGESDRIVER_PROTOCOL"
+
 // driver is the type for functions that query the build system for the
 // packages named by the patterns.
 type driver func(cfg *Config, patterns []string) (*DriverResponse, error)
 
-// findExternalDriver returns the file path of a tool that supplies
-// the build system package structure, or "" if not found.
-// If GOPACKAGESDRIVER is set in the environment findExternalTool returns its
-// value, otherwise it searches for a binary named gopackagesdriver on the PATH.
+// findExternalDriver returns a driver that runs the external driver tool
+// (see [findExternalTool]), or nil if not found.
 func findExternalDriver(cfg *Config) driver {
-	const toolPrefix = "GOPACKAGESDRIVER="
-	tool := ""
-	for _, env := range cfg.Env {
-		if val := strings.TrimPrefix(env, toolPrefix); val != env {
-			tool = val
-		}
-	}
-	if tool != "" && tool == "off" {
-		return nil
-	}
+	tool := findExternalTool(cfg)
 	if tool == "" {
-		var err error
-		tool, err = exec.LookPath("gopackagesdriver")
-		if err != nil {
-			return nil
-		}
+		return nil
 	}
 	return func(cfg *Config, patterns []string) (*DriverResponse, error) {
-		req, err := json.Marshal(DriverRequest{
-			Mode:       cfg.Mode,
-			Env:        cfg.Env,
-			BuildFlags: cfg.BuildFlags,
-			Tests:      cfg.Tests,
-			Overlay:    cfg.Overlay,
-		})
+		req, err := json.Marshal(newDriverRequest(cfg))
 		if err != nil {
 			return nil, fmt.Errorf("failed to encode message to driver tool: %v", err)
 		}
@@ -120,19 +148,7 @@ func findExternalDriver(cfg *Config) driver {
 		stderr := new(bytes.Buffer)
 		cmd := exec.CommandContext(cfg.Context, tool, patterns...)
 		cmd.Dir = cfg.Dir
-		// The cwd gets resolved to the real path. On Darwin, where
-		// /tmp is a symlink, this breaks anything that expects the
-		// working directory to keep the original path, including the
-		// go command when dealing with modules.
-		//
-		// os.Getwd stdlib has a special feature where if the
-		// cwd and the PWD are the same node then it trusts
-		// the PWD, so by setting it in the env for the child
-		// process we fix up all the paths returned by the go
-		// command.
-		//
-		// (See similar trick in Invocation.run in ../../internal/gocommand/invoke.go)
-		cmd.Env = append(slices.Clip(cfg.Env), "PWD="+cfg.Dir)
+		cmd.Env = driverEnv(cfg)
 		cmd.Stdin = bytes.NewReader(req)
 		cmd.Stdout = buf
 		cmd.Stderr = stderr
@@ -151,3 +167,52 @@ func findExternalDriver(cfg *Config) driver {
 		return &response, nil
 	}
 }
+
+// findExternalTool returns the file path of a tool that supplies
+// the build system package structure, or "" if not found.
+// If GOPACKAGESDRIVER is set in the environment findExternalTool returns its
+// value, otherwise it searches for a binary named gopackagesdriver on the PATH.
+func findExternalTool(cfg *Config) string {
+	const toolPrefix = "GOPACKAGESDRIVER="
+	tool := ""
+	for _, env := range cfg.Env {
+		if val := strings.TrimPrefix(env, toolPrefix); val != env {
+			tool = val
+		}
+	}
+	if tool == "off" {
+		return ""
+	}
+	if tool == "" {
+		tool, _ = exec.LookPath("gopackagesdriver")
+	}
+	return tool
+}
+
+// newDriverRequest returns the request describing cfg to a driver.
+func newDriverRequest(cfg *Config) DriverRequest {
+	return DriverRequest{
+		Mode:       cfg.Mode,
+		Env:        cfg.Env,
+		BuildFlags: cfg.BuildFlags,
+		Tests:      cfg.Tests,
+		Overlay:    cfg.Overlay,
+	}
+}
+
+// driverEnv returns the environment of a driver process for cfg.
+func driverEnv(cfg *Config) []string {
+	// The cwd gets resolved to the real path. On Darwin, where
+	// /tmp is a symlink, this breaks anything that expects the
+	// working directory to keep the original path, including the
+	// go command when dealing with modules.
+	//
+	// os.Getwd stdlib has a special feature where if the
+	// cwd and the PWD are the same node then it trusts
+	// the PWD, so by setting it in the env for the child
+	// process we fix up all the paths returned by the go
+	// command.
+	//
+	// (See similar trick in Invocation.run in ../../internal/gocommand/invoke.go)
+	return append(slices.Clip(cfg.Env), "PWD="+cfg.Dir)
+}
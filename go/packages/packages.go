@@ -265,8 +265,15 @@ type Config struct {
 // proceeding with further analysis. The [PrintErrors] function is
 // provided for convenient display of all errors.
 func Load(cfg *Config, patterns ...string) ([]*Package, error) {
+	return load(cfg, defaultDriver, patterns)
+}
+
+// load loads the packages named by the patterns, as reported by the
+// driver. Like defaultDriver, the driver returns whether the response
+// is that of an external driver.
+func load(cfg *Config, driver func(*Config, ...string) (*DriverResponse, bool, error), patterns []string) ([]*Package, error) {
 	ld := newLoader(cfg)
-	response, external, err := defaultDriver(&ld.Config, patterns...)
+	response, external, err := driver(&ld.Config, patterns...)
 	if err != nil {
 		return nil, err
 	}
@@ -299,20 +306,6 @@ func Load(cfg *Config, patterns ...string) ([]*Package, error) {
 // defaultDriver will fall back to the go list driver.
 // The boolean result indicates that an external driver handled the request.
 func defaultDriver(cfg *Config, patterns ...string) (*DriverResponse, bool, error) {
-	const (
-		// windowsArgMax specifies the maximum command line length for
-		// the Windows' CreateProcess function.
-		windowsArgMax = 32767
-		// maxEnvSize is a very rough estimation of the maximum environment
-		// size of a user.
-		maxEnvSize = 16384
-		// safeArgMax specifies the maximum safe command line length to use
-		// by the underlying driver excl. the environment. We choose the Windows'
-		// ARG_MAX as the starting point because it's one of the lowest ARG_MAX
-		// constants out of the different supported platforms,
-		// e.g., https://www.in-ulm.de/~mascheck/various/argmax/#results.
-		safeArgMax = windowsArgMax - maxEnvSize
-	)
 	chunks, err := splitIntoChunks(patterns, safeArgMax)
 	if err != nil {
 		return nil, false, err
@@ -328,13 +321,35 @@ func defaultDriver(cfg *Config, patterns ...string) (*DriverResponse, bool, erro
 		// not handled: fall through
 	}
 
-	// go list fallback
+	response, err := goListOnChunks(cfg, chunks)
+	if err != nil {
+		return nil, false, err
+	}
+	return response, false, err
+}
 
+const (
+	// windowsArgMax specifies the maximum command line length for
+	// the Windows' CreateProcess function.
+	windowsArgMax = 32767
+	// maxEnvSize is a very rough estimation of the maximum environment
+	// size of a user.
+	maxEnvSize = 16384
+	// safeArgMax specifies the maximum safe command line length to use
+	// by the underlying driver excl. the environment. We choose the Windows'
+	// ARG_MAX as the starting point because it's one of the lowest ARG_MAX
+	// constants out of the different supported platforms,
+	// e.g., https://www.in-ulm.de/~mascheck/various/argmax/#results.
+	safeArgMax = windowsArgMax - maxEnvSize
+)
+
+// goListOnChunks implements the go list fallback of defaultDriver.
+func goListOnChunks(cfg *Config, chunks [][]string) (*DriverResponse, error) {
 	// Write overlays once, as there are many calls
 	// to 'go list' (one per chunk plus others too).
 	overlayFile, cleanupOverlay, err := gocommand.WriteOverlays(cfg.Overlay)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 	defer cleanupOverlay()
 
@@ -342,11 +357,7 @@ func defaultDriver(cfg *Config, patterns ...string) (*DriverResponse, bool, erro
 	driver := func(cfg *Config, patterns []string) (*DriverResponse, error) {
 		return goListDriver(cfg, &runner, overlayFile, patterns)
 	}
-	response, err := callDriverOnChunks(driver, cfg, chunks)
-	if err != nil {
-		return nil, false, err
-	}
-	return response, false, err
+	return callDriverOnChunks(driver, cfg, chunks)
 }
 
 // splitIntoChunks chunks the slice so that the total number of characters
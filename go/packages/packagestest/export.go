@@ -50,6 +50,9 @@ the 'go list' command on the specified modules:
 TestGoList uses TestAll to exercise the 'go list' command with all
 exporters known to packagestest. Currently, packagestest includes
 exporters that produce module mode layouts and GOPATH mode layouts.
+A third exporter, Workspace, produces layouts in which the modules are
+joined by a go.work file; as it does not support versioned modules, it
+is not among those used by TestAll.
 Running the test with verbose output will print:
 
 	=== RUN   TestGoList
@@ -195,7 +198,7 @@ func BenchmarkAll(b *testing.B, f func(*testing.B, Exporter)) {
 // ErrUnspported, Export skips the test.
 func Export(t testing.TB, exporter Exporter, modules []Module) *Exported {
 	t.Helper()
-	if exporter == Modules {
+	if exporter == Modules || exporter == Workspace {
 		testenv.NeedsTool(t, "go")
 	}
 
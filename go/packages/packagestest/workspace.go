@@ -0,0 +1,117 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package packagestest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/internal/testenv"
+)
+
+// Workspace is the exporter that produces go.work layouts.
+// Each "repository" is put in its own module, in a directory named after
+// the module path, and a go.work file joins them into a single workspace.
+// Given the two files
+//
+//	golang.org/repoa#a/a.go
+//	golang.org/repob#b/b.go
+//
+// You would get the directory layout
+//
+//	/sometemporarydirectory
+//	├── go.work
+//	└── golang.org
+//	    ├── repoa
+//	    │   ├── a
+//	    │   │   └── a.go
+//	    │   └── go.mod
+//	    └── repob
+//	        ├── b
+//	        │   └── b.go
+//	        └── go.mod
+//
+// and the working directory would be
+//
+//	/sometemporarydirectory/golang.org/repoa
+//
+// A module whose Files include "go.mod" keeps that file, which allows
+// tests to configure the requirements and Go version of each module;
+// otherwise a go.mod file is generated that declares only the module
+// path and the Go version of the go command. Unlike with the Modules
+// exporter, module names may not include a version, as all the modules
+// of a workspace are main modules.
+var Workspace = workspace{}
+
+type workspace struct{}
+
+func (workspace) Name() string {
+	return "Workspace"
+}
+
+func (workspace) Filename(exported *Exported, module, fragment string) string {
+	return filepath.Join(workspaceDir(exported, module), fragment)
+}
+
+func (workspace) Finalize(exported *Exported) error {
+	// Ensure that the primary module exists, as it is the working
+	// dir for the go command.
+	if exported.written[exported.primary] == nil {
+		exported.written[exported.primary] = make(map[string]string)
+	}
+	exported.Config.Dir = workspaceDir(exported, exported.primary)
+	if err := os.MkdirAll(exported.Config.Dir, 0755); err != nil {
+		return err
+	}
+
+	goVersion := fmt.Sprintf("1.%d", testenv.Go1Point())
+	var modules []string
+	for module, files := range exported.written {
+		if strings.Contains(module, "@") {
+			return fmt.Errorf("workspace module %s may not have a version", module)
+		}
+		modules = append(modules, module)
+
+		// Write out the go.mod file, unless the test provided one.
+		if files["go.mod"] == "" {
+			modfile := filepath.Join(workspaceDir(exported, module), "go.mod")
+			contents := fmt.Sprintf("module %s\n\ngo %s\n", module, goVersion)
+			if err := os.WriteFile(modfile, []byte(contents), 0644); err != nil {
+				return err
+			}
+			files["go.mod"] = modfile
+		}
+	}
+	sort.Strings(modules)
+
+	// Write out the go.work file that uses all the modules.
+	var gowork bytes.Buffer
+	fmt.Fprintf(&gowork, "go %s\n\nuse (\n", goVersion)
+	for _, module := range modules {
+		fmt.Fprintf(&gowork, "\t./%s\n", module)
+	}
+	fmt.Fprintf(&gowork, ")\n")
+	workfile := filepath.Join(exported.temp, "go.work")
+	if err := os.WriteFile(workfile, gowork.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	exported.Config.Env = append(exported.Config.Env,
+		"GO111MODULE=on",
+		"GOWORK="+workfile,
+		"GOPATH="+filepath.Join(exported.temp, "gopath"),
+		"GOPROXY=off",
+		"GOSUMDB=off",
+	)
+	return nil
+}
+
+func workspaceDir(exported *Exported, module string) string {
+	return filepath.Join(exported.temp, filepath.FromSlash(module))
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package packagestest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/packages/packagestest"
+)
+
+func TestWorkspaceExport(t *testing.T) {
+	exported := packagestest.Export(t, packagestest.Workspace, []packagestest.Module{{
+		Name: "golang.org/fake1",
+		Files: map[string]interface{}{
+			"a/a.go": `package a; import "golang.org/fake2/b"; var _ = b.B`,
+		},
+	}, {
+		Name: "golang.org/fake2",
+		Files: map[string]interface{}{
+			"go.mod": "module golang.org/fake2\n\ngo 1.21\n",
+			"b/b.go": "package b; const B = 1",
+		},
+	}})
+	defer exported.Cleanup()
+	// Check that the cfg contains all the right bits
+	var expectDir = filepath.Join(exported.Temp(), "golang.org", "fake1")
+	if exported.Config.Dir != expectDir {
+		t.Errorf("Got working directory %v expected %v", exported.Config.Dir, expectDir)
+	}
+	checkFiles(t, exported, []fileTest{
+		{"golang.org/fake1", "go.mod", "golang.org/fake1/go.mod", nil},
+		{"golang.org/fake1", "a/a.go", "golang.org/fake1/a/a.go", nil},
+		{"golang.org/fake2", "go.mod", "golang.org/fake2/go.mod", checkContent("module golang.org/fake2\n\ngo 1.21\n")},
+		{"golang.org/fake2", "b/b.go", "golang.org/fake2/b/b.go", nil},
+	})
+
+	// Check that the modules form a workspace.
+	exported.Config.Mode = packages.NeedName | packages.NeedImports
+	pkgs, err := packages.Load(exported.Config, "golang.org/fake1/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("packages contain errors")
+	}
+	if pkgs[0].Imports["golang.org/fake2/b"] == nil {
+		t.Errorf("golang.org/fake1/a does not import golang.org/fake2/b: %v", pkgs[0].Imports)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+// This file defines sessions with external drivers, which implement
+// version 2 of the driver protocol.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// A DriverSession is a long-lived connection to an external driver (see
+// [The driver protocol]) that supports sessions. Queries made through a
+// session do not start a new driver process, and the driver may notify
+// the client of changes to the build configuration, so that the client
+// can query again just the affected packages.
+//
+// A DriverSession is safe for concurrent use.
+type DriverSession struct {
+	tool     string
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stderr   *tailBuffer
+	onChange func(DriverChange)
+	done     chan struct{} // closed when the driver has exited
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *DriverMessage // by query ID
+	err     error                         // non-nil once the session has ended
+}
+
+var errSessionClosed = errors.New("driver session closed")
+
+// NewDriverSession starts a session with the external driver that [Load]
+// would use for cfg, which must support sessions. The context, directory,
+// and environment of cfg apply to the driver process. The Context only
+// bounds the handshake: the session lasts until it is closed.
+//
+// If onChange is non-nil, it is called with each change notification
+// sent by the driver. The calls are sequential, and must not block.
+//
+// NewDriverSession returns an error if there is no external driver, or
+// if it does not acknowledge the session, as is the case for drivers
+// that implement only version 1 of the protocol. Since such a driver
+// handles the session request as an ordinary query, clients should
+// start sessions only with drivers known to support them.
+func NewDriverSession(cfg *Config, onChange func(DriverChange)) (*DriverSession, error) {
+	cfg = &newLoader(cfg).Config
+	tool := findExternalTool(cfg)
+	if tool == "" {
+		return nil, errors.New("no go/packages driver")
+	}
+
+	cmd := exec.Command(tool)
+	cmd.Dir = cfg.Dir
+	cmd.Env = append(driverEnv(cfg), driverProtocolEnv+"=2")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := new(tailBuffer)
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%v: %v", tool, err)
+	}
+
+	s := &DriverSession{
+		tool:     tool,
+		cmd:      cmd,
+		stdin:    stdin,
+		stderr:   stderr,
+		onChange: onChange,
+		done:     make(chan struct{}),
+		enc:      json.NewEncoder(stdin),
+		pending:  make(map[int64]chan *DriverMessage),
+	}
+
+	// The first message is a valid request of version 1 of the protocol,
+	// so that drivers that do not support sessions reply and exit.
+	dec := json.NewDecoder(stdout)
+	handshake := make(chan error, 1)
+	go func() {
+		if err := s.send(DriverQuery{DriverRequest: newDriverRequest(cfg)}); err != nil {
+			handshake <- err
+			return
+		}
+		var msg DriverMessage
+		if err := dec.Decode(&msg); err != nil {
+			handshake <- err
+		} else if msg.Protocol != 2 {
+			handshake <- errors.New("driver does not support sessions")
+		} else {
+			handshake <- nil
+		}
+	}()
+	select {
+	case err = <-handshake:
+	case <-cfg.Context.Done():
+		err = cfg.Context.Err()
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		stdin.Close()
+		cmd.Wait()
+		return nil, fmt.Errorf("%v: %v: %s", tool, err, stderr)
+	}
+
+	go s.read(dec)
+	return s, nil
+}
+
+// Load loads and returns the Go packages named by the given patterns, as
+// [Load] does, but queries the driver through the session.
+//
+// Within a session, drivers should answer queries for "file=" patterns
+// without querying the entire workspace, so that clients may efficiently
+// determine the package of a file.
+func (s *DriverSession) Load(cfg *Config, patterns ...string) ([]*Package, error) {
+	return load(cfg, s.driver, patterns)
+}
+
+// Close ends the session, and waits for the driver to exit.
+func (s *DriverSession) Close() error {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = errSessionClosed
+	}
+	s.mu.Unlock()
+
+	// The driver should exit once its input is closed.
+	s.stdin.Close()
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		s.cmd.Process.Kill()
+		<-s.done
+	}
+	return nil
+}
+
+// driver is the driver function of the session (see [defaultDriver]).
+func (s *DriverSession) driver(cfg *Config, patterns ...string) (*DriverResponse, bool, error) {
+	response, err := s.query(cfg, patterns)
+	if err != nil {
+		return nil, false, err
+	}
+	if !response.NotHandled {
+		return response, true, nil
+	}
+	chunks, err := splitIntoChunks(patterns, safeArgMax)
+	if err != nil {
+		return nil, false, err
+	}
+	response, err = goListOnChunks(cfg, chunks)
+	return response, false, err
+}
+
+// query sends a query to the driver and awaits its response.
+func (s *DriverSession) query(cfg *Config, patterns []string) (*DriverResponse, error) {
+	s.mu.Lock()
+	if s.err != nil {
+		err := s.err
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.nextID++
+	id := s.nextID
+	reply := make(chan *DriverMessage, 1)
+	s.pending[id] = reply
+	s.mu.Unlock()
+
+	if err := s.send(DriverQuery{
+		DriverRequest: newDriverRequest(cfg),
+		ID:            id,
+		Patterns:      patterns,
+	}); err != nil {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%v: %v", s.tool, err)
+	}
+
+	select {
+	case msg, ok := <-reply:
+		if !ok {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return nil, s.err
+		}
+		if msg.Error != "" {
+			return nil, fmt.Errorf("%v: %s", s.tool, msg.Error)
+		}
+		if msg.Response == nil {
+			return nil, fmt.Errorf("%v: no response to query", s.tool)
+		}
+		return msg.Response, nil
+	case <-cfg.Context.Done():
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		return nil, cfg.Context.Err()
+	}
+}
+
+func (s *DriverSession) send(q DriverQuery) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.enc.Encode(q)
+}
+
+// read reads the messages of the driver until it exits.
+func (s *DriverSession) read(dec *json.Decoder) {
+	var err error
+	for {
+		var msg DriverMessage
+		if err = dec.Decode(&msg); err != nil {
+			break
+		}
+		switch {
+		case msg.Changed != nil:
+			if s.onChange != nil {
+				s.onChange(*msg.Changed)
+			}
+		case msg.ID != 0:
+			s.mu.Lock()
+			reply := s.pending[msg.ID]
+			delete(s.pending, msg.ID)
+			s.mu.Unlock()
+			if reply != nil {
+				reply <- &msg
+			}
+		}
+	}
+	if waitErr := s.cmd.Wait(); waitErr != nil {
+		err = waitErr
+	}
+
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = fmt.Errorf("%v: session ended: %v: %s", s.tool, err, s.stderr)
+	}
+	for id, reply := range s.pending {
+		close(reply)
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+	close(s.done)
+}
+
+// A tailBuffer is an io.Writer that retains the last bytes written to it,
+// for reporting the error output of a long-lived driver.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+const maxTailBuffer = 4 << 10
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > maxTailBuffer {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-maxTailBuffer:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
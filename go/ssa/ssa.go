@@ -362,6 +362,9 @@ type Function struct {
 	referrers []Instruction // referring instructions (iff Parent() != nil)
 	anonIdx   int32         // position of a nested function in parent's AnonFuncs. fn.Parent()!=nil => fn.Parent().AnonFunc[fn.anonIdx] == fn.
 
+	summaryOnce sync.Once        // guards summary
+	summary     *FunctionSummary // computed lazily by Summary
+
 	typeparams     *types.TypeParamList // type parameters of this function. typeparams.Len() > 0 => generic or instance of generic function
 	typeargs       []types.Type         // type arguments that instantiated typeparams. len(typeargs) > 0 => instance of generic function
 	topLevelOrigin *Function            // the origin function if this is an instance of a source function. nil if Parent()!=nil.
@@ -7,6 +7,7 @@ package ssautil // import "golang.org/x/tools/go/ssa/ssautil"
 import (
 	"go/ast"
 	"go/types"
+	"sort"
 
 	"golang.org/x/tools/go/ssa"
 
@@ -147,6 +148,132 @@ func MainPackages(pkgs []*ssa.Package) []*ssa.Package {
 	return mains
 }
 
+// Reachable returns the set of functions reachable from the
+// specified roots, which may be any functions of prog, not just main
+// and init functions. A function is reachable if it is a root or is
+// referenced or called by a reachable function. An interface method
+// call I.m is assumed to call the method m of every type that
+// implements I, as in class hierarchy analysis (see
+// [golang.org/x/tools/go/callgraph/cha]); other dynamic calls can
+// call only functions whose values are referenced by reachable code.
+// Calls made through reflection are not considered.
+//
+// Precondition: all packages are built.
+func Reachable(prog *ssa.Program, roots []*ssa.Function) map[*ssa.Function]bool {
+	// methodsByID contains all methods, grouped by ID.
+	// It is computed on demand.
+	var methodsByID map[string][]*ssa.Function
+
+	// methodsMemo records, for each abstract method call I.m,
+	// the concrete methods C.m of all types C that satisfy I.
+	type imethod struct {
+		I  *types.Interface
+		id string
+	}
+	methodsMemo := make(map[imethod][]*ssa.Function)
+	lookupMethods := func(I *types.Interface, m *types.Func) []*ssa.Function {
+		if methodsByID == nil {
+			methodsByID = make(map[string][]*ssa.Function)
+			for fn := range AllFunctions(prog) {
+				if fn.Signature.Recv() != nil && fn.Object() != nil {
+					id := fn.Object().(*types.Func).Id()
+					methodsByID[id] = append(methodsByID[id], fn)
+				}
+			}
+		}
+		key := imethod{I, m.Id()}
+		methods, ok := methodsMemo[key]
+		if !ok {
+			for _, fn := range methodsByID[key.id] {
+				if types.Implements(fn.Signature.Recv().Type(), I) {
+					methods = append(methods, fn)
+				}
+			}
+			methodsMemo[key] = methods
+		}
+		return methods
+	}
+
+	seen := make(map[*ssa.Function]bool)
+	var function func(fn *ssa.Function)
+	function = func(fn *ssa.Function) {
+		if !seen[fn] {
+			seen[fn] = true
+			var buf [10]*ssa.Value // avoid alloc in common case
+			for _, b := range fn.Blocks {
+				for _, instr := range b.Instrs {
+					for _, op := range instr.Operands(buf[:0]) {
+						if fn, ok := (*op).(*ssa.Function); ok {
+							function(fn)
+						}
+					}
+					if call, ok := instr.(ssa.CallInstruction); ok && call.Common().IsInvoke() {
+						common := call.Common()
+						I := common.Value.Type().Underlying().(*types.Interface)
+						for _, fn := range lookupMethods(I, common.Method) {
+							function(fn)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, fn := range roots {
+		function(fn)
+	}
+	return seen
+}
+
+// AllCalls returns the call instructions (including go and defer
+// statements) within the specified functions whose callee is a
+// function or method accepted by match, in order of position.
+//
+// The callee of a static call is the object of its
+// [ssa.CallCommon.StaticCallee], or, for an instantiation of a
+// generic function or method, that of its origin; the callee of an
+// interface method call is the abstract method. Calls of anonymous
+// functions, function values, and built-ins are not reported.
+//
+// For example, this call finds the calls to fmt.Printf in all
+// functions of a program:
+//
+//	calls := AllCalls(AllFunctions(prog), func(fn *types.Func) bool {
+//		return fn.FullName() == "fmt.Printf"
+//	})
+func AllCalls(funcs map[*ssa.Function]bool, match func(*types.Func) bool) []ssa.CallInstruction {
+	var calls []ssa.CallInstruction
+	for fn, in := range funcs {
+		if !in {
+			continue
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				var callee *types.Func
+				if common := call.Common(); common.IsInvoke() {
+					callee = common.Method
+				} else if g := common.StaticCallee(); g != nil {
+					callee, _ = g.Object().(*types.Func)
+				}
+				if callee != nil && match(callee.Origin()) {
+					calls = append(calls, call)
+				}
+			}
+		}
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		x, y := calls[i], calls[j]
+		if x.Pos() != y.Pos() {
+			return x.Pos() < y.Pos()
+		}
+		return x.Parent().String() < y.Parent().String()
+	})
+	return calls
+}
+
 // TODO(adonovan): propose a principled API for this. One possibility
 // is a new field, Package.SrcFunctions []*Function, which would
 // contain the list of SrcFunctions described in point 2 of the
@@ -0,0 +1,136 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssautil_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/internal/testenv"
+)
+
+const visitSrc = `package p
+
+import "fmt"
+
+type I interface{ M() }
+
+type A struct{}
+
+func (A) M() { fmt.Println("A") }
+
+type B struct{}
+
+func (*B) M() {}
+
+type C struct{}
+
+func (C) N() {}
+
+func main() {
+	var i I = A{}
+	i.M()
+}
+
+func root() {
+	f := helper
+	f()
+}
+
+func helper() { fmt.Printf("%d", 1) }
+
+func unreachable() { fmt.Printf("x") }
+
+func generic[T any](x T) { fmt.Printf("%v", x) }
+
+func instantiate() {
+	generic(1)
+	defer fmt.Printf("deferred")
+}
+`
+
+// buildVisitSrc builds the SSA form of visitSrc.
+func buildVisitSrc(t *testing.T) *ssa.Package {
+	testenv.NeedsGoBuild(t) // for importer.Default()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", visitSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := types.NewPackage("p", "")
+	ssapkg, _, err := ssautil.BuildPackage(&types.Config{Importer: importer.Default()}, fset, pkg, []*ast.File{f}, ssa.InstantiateGenerics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ssapkg
+}
+
+func TestReachable(t *testing.T) {
+	pkg := buildVisitSrc(t)
+
+	for _, test := range []struct {
+		roots []string
+		want  string // reachable functions of package p
+	}{
+		{[]string{"root"}, "helper root"},
+		{[]string{"main"}, "(*A).M (*B).M (A).M main"},
+		{[]string{"root", "instantiate"}, "generic[int] helper instantiate root"},
+	} {
+		var roots []*ssa.Function
+		for _, name := range test.roots {
+			roots = append(roots, pkg.Func(name))
+		}
+		var got []string
+		for fn := range ssautil.Reachable(pkg.Prog, roots) {
+			if obj := fn.Object(); obj != nil && obj.Pkg() == pkg.Pkg {
+				got = append(got, fn.RelString(pkg.Pkg))
+			}
+		}
+		sort.Strings(got)
+		if strings.Join(got, " ") != test.want {
+			t.Errorf("Reachable(%v) = %v, want %s", test.roots, got, test.want)
+		}
+	}
+}
+
+func TestAllCalls(t *testing.T) {
+	pkg := buildVisitSrc(t)
+	fset := pkg.Prog.Fset
+
+	for _, test := range []struct {
+		callee string
+		want   []string // positions and enclosing functions of calls
+	}{
+		{"fmt.Printf", []string{
+			"p.go:29:27 helper",
+			"p.go:31:32 unreachable",
+			"p.go:33:38 generic",
+			"p.go:33:38 generic[int]",
+			"p.go:37:2 instantiate",
+		}},
+		{"p.generic", []string{"p.go:36:9 instantiate"}},
+		{"(p.I).M", []string{"p.go:21:5 main"}},
+	} {
+		calls := ssautil.AllCalls(ssautil.AllFunctions(pkg.Prog), func(fn *types.Func) bool {
+			return fn.FullName() == test.callee
+		})
+		var got []string
+		for _, call := range calls {
+			got = append(got, fset.Position(call.Pos()).String()+" "+call.Parent().RelString(pkg.Pkg))
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("AllCalls(%s) =\n%s\nwant:\n%s", test.callee, strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa
+
+// This file defines FunctionSummary, a digest of a function's body
+// for use by cost heuristics.
+
+// A FunctionSummary records properties of the body of a function that
+// are commonly needed by heuristics, such as the cost estimates of an
+// inliner, so that each client need not compute them independently.
+//
+// A summary describes only the function's own body: the bodies of its
+// anonymous functions (see [Function.AnonFuncs]) are summarized
+// separately, and the properties of its callees are not considered.
+type FunctionSummary struct {
+	// Instrs is the number of instructions in the function.
+	Instrs int
+
+	// Values is the number of instructions that are also
+	// [Value]s, a measure of the size of the function.
+	Values int
+
+	// Calls holds the function's call instructions (*Call, *Go, and
+	// *Defer), both static and dynamic, in block order.
+	Calls []CallInstruction
+
+	// Allocates reports whether the function contains an instruction
+	// that may allocate memory on the heap: an Alloc of a heap
+	// variable, a MakeInterface of a non-constant value, MakeClosure,
+	// MakeSlice, MakeMap, or MakeChan.
+	Allocates bool
+
+	// Panics reports whether the function contains a Panic
+	// instruction, that is, an explicit call to panic.
+	Panics bool
+
+	// Loops reports whether the function's control-flow graph
+	// contains a cycle.
+	Loops bool
+}
+
+// Summary returns a summary of the body of the function, or nil if
+// the function has no body (see [Function.Blocks]).
+//
+// The summary is computed on the first call and retained by the
+// function; it must not be called before the function is built, or
+// while its body is being modified. It is safe to call concurrently.
+func (f *Function) Summary() *FunctionSummary {
+	f.summaryOnce.Do(func() {
+		if f.Blocks != nil {
+			f.summary = summarize(f)
+		}
+	})
+	return f.summary
+}
+
+// summarize computes the summary of a function body.
+func summarize(f *Function) *FunctionSummary {
+	s := new(FunctionSummary)
+	for _, b := range f.Blocks {
+		for _, instr := range b.Instrs {
+			s.Instrs++
+			if _, ok := instr.(Value); ok {
+				s.Values++
+			}
+			switch instr := instr.(type) {
+			case CallInstruction:
+				s.Calls = append(s.Calls, instr)
+			case *Alloc:
+				if instr.Heap {
+					s.Allocates = true
+				}
+			case *MakeInterface:
+				if _, ok := instr.X.(*Const); !ok {
+					s.Allocates = true
+				}
+			case *MakeClosure, *MakeSlice, *MakeMap, *MakeChan:
+				s.Allocates = true
+			case *Panic:
+				s.Panics = true
+			}
+		}
+	}
+	s.Loops = hasCycle(f)
+	return s
+}
+
+// hasCycle reports whether the control-flow graph of f contains a
+// cycle, that is, whether a depth-first search from its entry or
+// recover block encounters an edge to a block on the current path.
+// Unlike a search for back edges in the dominator tree, it also
+// detects cycles in irreducible graphs, which may arise from goto.
+func hasCycle(f *Function) bool {
+	const (
+		white = iota // unvisited
+		grey         // on the current path
+		black        // finished
+	)
+	color := make([]int, len(f.Blocks))
+	var visit func(b *BasicBlock) bool
+	visit = func(b *BasicBlock) bool {
+		color[b.Index] = grey
+		for _, succ := range b.Succs {
+			switch color[succ.Index] {
+			case grey:
+				return true
+			case white:
+				if visit(succ) {
+					return true
+				}
+			}
+		}
+		color[b.Index] = black
+		return false
+	}
+	for _, b := range []*BasicBlock{f.Blocks[0], f.Recover} {
+		if b != nil && color[b.Index] == white && visit(b) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssa_test
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+func TestFunctionSummary(t *testing.T) {
+	const src = `package p
+
+func leaf(x int) int { return x + 1 }
+
+func calls(x int) int {
+	go leaf(x)
+	defer leaf(x)
+	return leaf(x)
+}
+
+func alloc() *int {
+	x := 1
+	return &x
+}
+
+func iface(x int) any { return x }
+
+func panics(x int) {
+	if x < 0 {
+		panic("negative")
+	}
+}
+
+func loop(n int) (s int) {
+	for i := range n {
+		s += i
+	}
+	return s
+}
+
+func gotoLoop(n int) {
+	if n > 0 {
+		goto b
+	}
+a:
+	n--
+b:
+	if n > 0 {
+		goto a
+	}
+}
+
+func external()
+`
+	pkg, _ := buildPackage(t, src, ssa.SanityCheckFunctions)
+
+	for _, test := range []struct {
+		name                     string
+		calls                    int
+		allocates, panics, loops bool
+	}{
+		{name: "leaf"},
+		{name: "calls", calls: 3},
+		{name: "alloc", allocates: true},
+		{name: "iface", allocates: true},
+		{name: "panics", panics: true},
+		{name: "loop", loops: true},
+		{name: "gotoLoop", loops: true},
+	} {
+		fn := pkg.Func(test.name)
+		s := fn.Summary()
+		if s == nil {
+			t.Errorf("%s: nil summary", test.name)
+			continue
+		}
+		if s != fn.Summary() {
+			t.Errorf("%s: Summary is not retained", test.name)
+		}
+		got := fmt.Sprint(len(s.Calls), s.Allocates, s.Panics, s.Loops)
+		want := fmt.Sprint(test.calls, test.allocates, test.panics, test.loops)
+		if got != want {
+			t.Errorf("%s: got calls, allocates, panics, loops = %s, want %s", test.name, got, want)
+		}
+		if s.Values == 0 || s.Values > s.Instrs {
+			t.Errorf("%s: got %d values and %d instructions", test.name, s.Values, s.Instrs)
+		}
+	}
+
+	if s := pkg.Func("external").Summary(); s != nil {
+		t.Errorf("external: got summary %+v, want nil", s)
+	}
+}
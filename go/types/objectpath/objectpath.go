@@ -57,7 +57,11 @@ type Path string
 // All valid paths start with a package and end at an object
 // and thus may be defined by the regular language:
 //
-//	objectpath = PO (OT TT* TO)*
+//	objectpath = PO (OT TT* TO | OO)*
+//
+// where the only OO (object->object) operator, which yields a type
+// declared within the body of a function, is used only when
+// [Encoder.Local] is set.
 //
 // The concrete encoding follows directly:
 //   - The only PO operator is Package.Scope.Lookup, which requires an identifier.
@@ -93,6 +97,29 @@ type Path string
 // The encoding is not maximally compact---every R or P is
 // followed by an A, for example---but this simplifies the
 // encoder and decoder.
+//
+// The OO operator is encoded as ':' followed by an integer operand,
+// the index of the type among the types declared within the function,
+// in order of a depth-first traversal of its scopes, visiting the
+// names of each scope in sorted order before its children.
+// In the example below,
+//
+//	package p
+//
+//	func f() {
+//		type T struct{ X int }
+//	}
+//
+// field X has the path "f:0.UF0".
+//
+// The path of an instantiated method (see [Encoder.Instances]) is the
+// path of its generic method followed by the type arguments of its
+// receiver, enclosed in brackets and separated by commas. Each type
+// argument is encoded as the name of a predeclared type, or as the
+// quoted path of the package that declares the type, followed by a
+// dot, the path of its type name within that package and, if the type is
+// itself an instance, its type arguments. For example, the method M
+// of the type T[int, q.U] has the path `T.M0[int,"q".U]`.
 const (
 	// object->type operators
 	opType = '.' // .Type()		  (Object)
@@ -113,6 +140,14 @@ const (
 	opField  = 'F' // .Field(i)	(Struct)
 	opMethod = 'M' // .Method(i)	(Named or Interface; not Struct: "promoted" names are ignored)
 	opObj    = 'O' // .Obj()	(Named, TypeParam)
+
+	// object->object operators
+	opLocal = ':' // local type (Func)
+
+	// delimiters of the type arguments of an instantiated method
+	opTypeArgs   = '['
+	opTypeArgSep = ','
+	opTypeArgEnd = ']'
 )
 
 // For is equivalent to new(Encoder).For(obj).
@@ -125,7 +160,26 @@ func For(obj types.Object) (Path, error) {
 // An Encoder amortizes the cost of encoding the paths of multiple objects.
 // The zero value of an Encoder is ready to use.
 type Encoder struct {
+	// Local causes For to return paths for types declared within
+	// the body of a package-level function or method, and for the
+	// objects, such as fields, reachable from them.
+	// Such paths can be decoded only from a package that was
+	// produced by type-checking syntax, since export data does not
+	// describe function bodies.
+	Local bool
+
+	// Instances causes For to return paths for the methods of
+	// instantiated generic types, encoded as the path of the generic
+	// method followed by the type arguments of its receiver.
+	// Only type arguments that are predeclared types, type parameters,
+	// or (possibly instantiated) named types are supported.
+	// When decoding such a path, Object looks for the packages that
+	// declare the type arguments among the transitive imports of pkg,
+	// and returns a new instance of the method.
+	Instances bool
+
 	scopeMemo map[*types.Scope][]types.Object // memoization of scopeObjects
+	localMemo map[*types.Package][]localType  // memoization of localTypes
 }
 
 // For returns the path to an object relative to its package,
@@ -153,7 +207,8 @@ type Encoder struct {
 //
 // For does not return a path for predeclared names, imported package
 // names, local names, and unexported package-level names (except
-// types).
+// types), nor, unless enabled by the [Encoder.Local] and
+// [Encoder.Instances] fields, for local types and instantiated methods.
 //
 // Example: given this definition,
 //
@@ -214,6 +269,11 @@ func (enc *Encoder) For(obj types.Object) (Path, error) {
 	}
 	scope := pkg.Scope()
 
+	// Method of an instantiated type?
+	if fn, ok := obj.(*types.Func); ok && enc.Instances && fn.Origin() != fn {
+		return enc.instance(fn)
+	}
+
 	// 2. package-level object?
 	if scope.Lookup(obj.Name()) == obj {
 		// Only exported objects (and non-exported types) have a path.
@@ -228,9 +288,9 @@ func (enc *Encoder) For(obj types.Object) (Path, error) {
 	//    Reject obviously non-viable cases.
 	switch obj := obj.(type) {
 	case *types.TypeName:
-		if _, ok := types.Unalias(obj.Type()).(*types.TypeParam); !ok {
-			// With the exception of type parameters, only package-level type names
-			// have a path.
+		if _, ok := types.Unalias(obj.Type()).(*types.TypeParam); !ok && !(enc.Local && obj.Parent() != nil) {
+			// With the exception of type parameters (and, optionally,
+			// local types), only package-level type names have a path.
 			return "", fmt.Errorf("no path for %v", obj)
 		}
 	case *types.Const, // Only package-level constants have a path.
@@ -339,9 +399,183 @@ func (enc *Encoder) For(obj types.Object) (Path, error) {
 		}
 	}
 
+	// Finally, inspect the types declared within functions.
+	if enc.Local {
+		for _, local := range enc.localTypes(pkg) {
+			path := append(empty, local.path...)
+			if local.tname == obj {
+				return Path(path), nil // found local type
+			}
+			path = append(path, opType)
+
+			T := local.tname.Type()
+			if alias, ok := T.(*types.Alias); ok {
+				if r := find(obj, aliases.Rhs(alias), append(path, opRhs)); r != nil {
+					return Path(r), nil
+				}
+			} else if local.tname.IsAlias() {
+				// legacy alias
+				if r := find(obj, T, path); r != nil {
+					return Path(r), nil
+				}
+			} else if named, ok := T.(*types.Named); ok {
+				if r := find(obj, named.Underlying(), append(path, opUnderlying)); r != nil {
+					return Path(r), nil
+				}
+			}
+		}
+	}
+
 	return "", fmt.Errorf("can't find path for %v in %s", obj, pkg.Path())
 }
 
+// A localType is a type declared within a function,
+// along with its path.
+type localType struct {
+	path  string
+	tname *types.TypeName
+}
+
+// localTypes returns the types declared within the package-level
+// functions and methods of pkg, in path order.
+// Callers must not modify the result.
+func (enc *Encoder) localTypes(pkg *types.Package) []localType {
+	if locals, ok := enc.localMemo[pkg]; ok {
+		return locals
+	}
+	var locals []localType
+	addLocals := func(path []byte, fn *types.Func) {
+		for i, tname := range localTypeNames(fn) {
+			locals = append(locals, localType{string(appendOpArg(path, opLocal, i)), tname})
+		}
+	}
+	for _, o := range enc.scopeObjects(pkg.Scope()) {
+		switch o := o.(type) {
+		case *types.Func:
+			addLocals([]byte(o.Name()), o)
+		case *types.TypeName:
+			if T, ok := o.Type().(*types.Named); ok && !o.IsAlias() {
+				path := append([]byte(o.Name()), opType)
+				for i := 0; i < T.NumMethods(); i++ {
+					addLocals(appendOpArg(path, opMethod, i), T.Method(i))
+				}
+			}
+		}
+	}
+	if enc.localMemo == nil {
+		enc.localMemo = make(map[*types.Package][]localType)
+	}
+	enc.localMemo[pkg] = locals
+	return locals
+}
+
+// localTypeNames returns the types declared within the body of fn, in
+// order of a depth-first traversal of its scopes, visiting the sorted
+// names of each scope before its children.
+// Type parameters are excluded, as they have paths of their own.
+// The result is empty if fn was loaded from export data.
+func localTypeNames(fn *types.Func) []*types.TypeName {
+	var tnames []*types.TypeName
+	var visit func(scope *types.Scope)
+	visit = func(scope *types.Scope) {
+		for _, name := range scope.Names() {
+			if tname, ok := scope.Lookup(name).(*types.TypeName); ok {
+				if tparam, ok := tname.Type().(*types.TypeParam); ok && tparam.Obj() == tname {
+					continue
+				}
+				tnames = append(tnames, tname)
+			}
+		}
+		for i := 0; i < scope.NumChildren(); i++ {
+			visit(scope.Child(i))
+		}
+	}
+	if scope := fn.Scope(); scope != nil {
+		visit(scope)
+	}
+	return tnames
+}
+
+// instance returns the path of meth, a method of an instantiated type.
+func (enc *Encoder) instance(meth *types.Func) (Path, error) {
+	_, named := typesinternal.ReceiverNamed(meth.Type().(*types.Signature).Recv())
+	if named == nil || named.TypeArgs().Len() == 0 {
+		return "", fmt.Errorf("no path for %v: receiver is not an instantiated type", meth)
+	}
+	origin, err := enc.For(meth.Origin())
+	if err != nil {
+		return "", err
+	}
+	path, err := enc.appendTypeArgs([]byte(origin), named.TypeArgs())
+	if err != nil {
+		return "", fmt.Errorf("no path for %v: %v", meth, err)
+	}
+	return Path(path), nil
+}
+
+// appendTypeArgs appends the encoding of a list of type arguments to path.
+func (enc *Encoder) appendTypeArgs(path []byte, targs *types.TypeList) ([]byte, error) {
+	path = append(path, opTypeArgs)
+	for i := 0; i < targs.Len(); i++ {
+		if i > 0 {
+			path = append(path, opTypeArgSep)
+		}
+		var err error
+		path, err = enc.appendTypeArg(path, targs.At(i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(path, opTypeArgEnd), nil
+}
+
+// appendTypeArg appends the encoding of type argument T to path.
+func (enc *Encoder) appendTypeArg(path []byte, T types.Type) ([]byte, error) {
+	switch T := types.Unalias(T).(type) {
+	case *types.Basic:
+		if T.Kind() == types.UnsafePointer {
+			return append(path, `"unsafe".Pointer`...), nil
+		}
+		if T.Info()&types.IsUntyped == 0 {
+			return append(path, T.Name()...), nil
+		}
+
+	case *types.Interface:
+		if T.Empty() {
+			return append(path, "any"...), nil
+		}
+
+	case *types.Named:
+		tname := T.Obj()
+		if tname.Pkg() == nil {
+			return append(path, tname.Name()...), nil // error, comparable
+		}
+		path, err := enc.appendTypeName(path, tname)
+		if err != nil {
+			return nil, err
+		}
+		if T.TypeArgs().Len() > 0 {
+			return enc.appendTypeArgs(path, T.TypeArgs())
+		}
+		return path, nil
+
+	case *types.TypeParam:
+		return enc.appendTypeName(path, T.Obj())
+	}
+	return nil, fmt.Errorf("unsupported type argument %s", T)
+}
+
+// appendTypeName appends the quoted package path and object path of tname to path.
+func (enc *Encoder) appendTypeName(path []byte, tname *types.TypeName) ([]byte, error) {
+	p, err := enc.For(tname)
+	if err != nil {
+		return nil, err
+	}
+	path = strconv.AppendQuote(path, tname.Pkg().Path())
+	path = append(path, '.')
+	return append(path, p...), nil
+}
+
 func appendOpArg(path []byte, op byte, arg int) []byte {
 	path = append(path, op)
 	path = strconv.AppendInt(path, int64(arg), 10)
@@ -576,18 +810,48 @@ func (f *finder) findTypeParam(list *types.TypeParamList, path []byte, op byte)
 }
 
 // Object returns the object denoted by path p within the package pkg.
+//
+// If p is the path of a method of an instantiated type, Object
+// returns a new instance of the method, whose receiver type is
+// identical to, but not necessarily the same as, that of the
+// method whose path was encoded.
 func Object(pkg *types.Package, p Path) (types.Object, error) {
-	pathstr := string(p)
+	pathstr, targsstr := string(p), ""
+	if i := strings.IndexByte(pathstr, opTypeArgs); i >= 0 {
+		pathstr, targsstr = pathstr[:i], pathstr[i:]
+	}
+
+	obj, err := object(pkg, pathstr)
+	if err != nil {
+		return nil, err
+	}
+	if targsstr == "" {
+		return obj, nil
+	}
+
+	targs, rest, err := parseTypeArgs(pkg, targsstr)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("invalid path: unexpected %q after type arguments", rest)
+	}
+	return instance(obj, targs)
+}
+
+// object returns the object denoted by the path (sans type
+// arguments) within the package pkg.
+func object(pkg *types.Package, pathstr string) (types.Object, error) {
 	if pathstr == "" {
 		return nil, fmt.Errorf("empty path")
 	}
 
 	var pkgobj, suffix string
-	if dot := strings.IndexByte(pathstr, opType); dot < 0 {
+	if i := strings.IndexAny(pathstr, string([]byte{opType, opLocal})); i < 0 {
 		pkgobj = pathstr
 	} else {
-		pkgobj = pathstr[:dot]
-		suffix = pathstr[dot:] // suffix starts with "."
+		pkgobj = pathstr[:i]
+		suffix = pathstr[i:] // suffix starts with "." or ":"
 	}
 
 	obj := pkg.Scope().Lookup(pkgobj)
@@ -612,17 +876,18 @@ func Object(pkg *types.Package, p Path) (types.Object, error) {
 	// exactly one of which is non-nil, initially obj.
 	// All suffixes start with '.' (the only object->type operation),
 	// followed by optional type->type operations,
-	// then a type->object operation.
+	// then a type->object operation,
+	// or with ':' (the only object->object operation).
 	// The cycle then repeats.
 	var t types.Type
 	for suffix != "" {
 		code := suffix[0]
 		suffix = suffix[1:]
 
-		// Codes [AFMTr] have an integer operand.
+		// Codes [AFMTr:] have an integer operand.
 		var index int
 		switch code {
-		case opAt, opField, opMethod, opTypeParam, opRecvTypeParam:
+		case opAt, opField, opMethod, opTypeParam, opRecvTypeParam, opLocal:
 			rest := strings.TrimLeft(suffix, "0123456789")
 			numerals := suffix[:len(suffix)-len(rest)]
 			suffix = rest
@@ -649,6 +914,22 @@ func Object(pkg *types.Package, p Path) (types.Object, error) {
 			continue
 		}
 
+		if code == opLocal {
+			if t != nil {
+				return nil, fmt.Errorf("invalid path: unexpected %q in type context", opLocal)
+			}
+			fn, ok := obj.(*types.Func)
+			if !ok {
+				return nil, fmt.Errorf("cannot apply %q to %s (got %T, want func)", code, obj, obj)
+			}
+			tnames := localTypeNames(fn)
+			if n := len(tnames); index >= n {
+				return nil, fmt.Errorf("local type index %d out of range [0-%d)", index, n)
+			}
+			obj = tnames[index]
+			continue
+		}
+
 		if t == nil {
 			return nil, fmt.Errorf("invalid path: code %q in object context", code)
 		}
@@ -786,7 +1067,7 @@ func Object(pkg *types.Package, p Path) (types.Object, error) {
 	}
 
 	if obj == nil {
-		panic(p) // path does not end in an object-valued operator
+		panic(pathstr) // path does not end in an object-valued operator
 	}
 
 	if obj.Pkg() != pkg {
@@ -796,6 +1077,152 @@ func Object(pkg *types.Package, p Path) (types.Object, error) {
 	return obj, nil // success
 }
 
+// parseTypeArgs parses the bracketed list of type arguments at the
+// start of s, returning the types and the remainder of s.
+// The packages that declare the types are found among pkg and its
+// transitive imports.
+func parseTypeArgs(pkg *types.Package, s string) ([]types.Type, string, error) {
+	s = s[1:] // skip '['
+	var targs []types.Type
+	for {
+		T, rest, err := parseTypeArg(pkg, s)
+		if err != nil {
+			return nil, "", err
+		}
+		targs = append(targs, T)
+		if rest == "" {
+			return nil, "", fmt.Errorf("invalid path: unterminated type arguments")
+		}
+		s = rest[1:]
+		switch rest[0] {
+		case opTypeArgEnd:
+			return targs, s, nil
+		case opTypeArgSep:
+			// continue
+		default:
+			return nil, "", fmt.Errorf("invalid path: unexpected %q in type arguments", rest[0])
+		}
+	}
+}
+
+// parseTypeArg parses the type argument at the start of s,
+// returning the type and the remainder of s.
+func parseTypeArg(pkg *types.Package, s string) (types.Type, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		// predeclared type
+		end := strings.IndexAny(s, string([]byte{opTypeArgSep, opTypeArgEnd}))
+		if end < 0 {
+			return nil, "", fmt.Errorf("invalid path: unterminated type arguments")
+		}
+		tname, ok := types.Universe.Lookup(s[:end]).(*types.TypeName)
+		if !ok {
+			return nil, "", fmt.Errorf("invalid path: %q is not a predeclared type", s[:end])
+		}
+		return tname.Type(), s[end:], nil
+	}
+
+	quoted, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid path: bad package path in type arguments: %v", err)
+	}
+	pkgpath, _ := strconv.Unquote(quoted)
+	s = strings.TrimPrefix(s[len(quoted):], ".")
+	end := strings.IndexAny(s, string([]byte{opTypeArgs, opTypeArgSep, opTypeArgEnd}))
+	if end < 0 {
+		return nil, "", fmt.Errorf("invalid path: unterminated type arguments")
+	}
+	objpath := s[:end]
+	s = s[end:]
+
+	declPkg := importedPackage(pkg, pkgpath)
+	if declPkg == nil {
+		return nil, "", fmt.Errorf("package %s does not import %s", pkg.Path(), pkgpath)
+	}
+	obj, err := object(declPkg, objpath)
+	if err != nil {
+		return nil, "", err
+	}
+	tname, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, "", fmt.Errorf("type argument %s is not a type", obj)
+	}
+	T := tname.Type()
+
+	if s[0] == opTypeArgs {
+		targs, rest, err := parseTypeArgs(pkg, s)
+		if err != nil {
+			return nil, "", err
+		}
+		s = rest
+		T, err = types.Instantiate(nil, T, targs, false)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return T, s, nil
+}
+
+// importedPackage returns the package with the given path among pkg
+// and its transitive imports, or nil if there is none.
+func importedPackage(pkg *types.Package, path string) *types.Package {
+	if path == "unsafe" {
+		return types.Unsafe
+	}
+	seen := make(map[*types.Package]bool)
+	var visit func(p *types.Package) *types.Package
+	visit = func(p *types.Package) *types.Package {
+		if seen[p] {
+			return nil
+		}
+		seen[p] = true
+		if p.Path() == path {
+			return p
+		}
+		for _, imp := range p.Imports() {
+			if r := visit(imp); r != nil {
+				return r
+			}
+		}
+		return nil
+	}
+	return visit(pkg)
+}
+
+// instance returns the method of the instantiation of the receiver
+// type of the generic method obj with the given type arguments.
+func instance(obj types.Object, targs []types.Type) (types.Object, error) {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, fmt.Errorf("invalid path: type arguments applied to %s, which is not a method", obj)
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil, fmt.Errorf("invalid path: type arguments applied to %s, which is not a method", obj)
+	}
+	_, named := typesinternal.ReceiverNamed(recv)
+	if named == nil || named.TypeParams().Len() == 0 {
+		return nil, fmt.Errorf("invalid path: type arguments applied to %s, whose receiver is not generic", obj)
+	}
+	T, err := types.Instantiate(nil, named.Origin(), targs, true)
+	if err != nil {
+		return nil, err
+	}
+	inst := T.(*types.Named)
+	for i := 0; i < inst.NumMethods(); i++ {
+		if m := inst.Method(i); m.Origin() == fn {
+			return m, nil
+		}
+	}
+	if iface, ok := inst.Underlying().(*types.Interface); ok {
+		for i := 0; i < iface.NumMethods(); i++ {
+			if m := iface.Method(i); m.Origin() == fn {
+				return m, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no method %s in %s", fn.Name(), inst)
+}
+
 // scopeObjects is a memoization of scope objects.
 // Callers must not modify the result.
 func (enc *Encoder) scopeObjects(scope *types.Scope) []types.Object {
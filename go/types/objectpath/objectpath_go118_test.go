@@ -6,6 +6,7 @@ package objectpath_test
 
 import (
 	"go/types"
+	"strings"
 	"testing"
 
 	"golang.org/x/tools/go/types/objectpath"
@@ -127,3 +128,128 @@ func main() {}
 		}
 	}
 }
+
+func TestInstancePaths(t *testing.T) {
+	const src = `
+-- go.mod --
+module x.io
+go 1.18
+
+-- a/a.go --
+package a
+
+type U struct{}
+
+type G[X any] struct{}
+
+-- b/b.go --
+package b
+
+import (
+	"unsafe"
+
+	"x.io/a"
+)
+
+type T[P, Q any] struct{}
+
+func (T[P, Q]) M() {}
+
+type I[P any] interface{ N() P }
+
+var (
+	V1 T[int, a.U]
+	V2 T[int, a.G[string]]
+	V3 T[unsafe.Pointer, error]
+	V4 I[int]
+	V5 T[[]int, int]
+)
+
+func F[FP any]() (_ T[any, FP]) { return }
+`
+
+	pkgmap := loadPackages(t, src, "./b")
+	b := pkgmap["x.io/b"].Types
+	enc := &objectpath.Encoder{Instances: true}
+
+	// method returns the first method of the named type of the specified object.
+	method := func(name string) *types.Func {
+		T := b.Scope().Lookup(name).Type()
+		if sig, ok := T.(*types.Signature); ok {
+			T = sig.Results().At(0).Type()
+		}
+		named := T.(*types.Named)
+		if iface, ok := named.Underlying().(*types.Interface); ok {
+			return iface.Method(0)
+		}
+		return named.Method(0)
+	}
+
+	for _, test := range []struct {
+		obj      string // name of var or func whose type's method is sought
+		wantPath objectpath.Path
+		wantErr  string
+	}{
+		{"V1", `T.M0[int,"x.io/a".U]`, ""},
+		{"V2", `T.M0[int,"x.io/a".G[string]]`, ""},
+		{"V3", `T.M0["unsafe".Pointer,error]`, ""},
+		{"V4", `I.UM0[int]`, ""},
+		{"F", `T.M0[any,"x.io/b".F.T0O]`, ""},
+		{"V5", "", "no path for func (x.io/b.T[[]int, int]).M(): unsupported type argument []int"},
+	} {
+		meth := method(test.obj)
+		path, err := enc.For(meth)
+		if err != nil {
+			if err.Error() != test.wantErr {
+				t.Errorf("For(%v) failed: %v, want %q", meth, err, test.wantErr)
+			}
+			continue
+		}
+		if path != test.wantPath {
+			t.Errorf("For(%v) = %q, want %q", meth, path, test.wantPath)
+			continue
+		}
+
+		// Object returns a new instance of the method.
+		obj, err := objectpath.Object(b, path)
+		if err != nil {
+			t.Errorf("Object(%q) failed: %v", path, err)
+			continue
+		}
+		fn, ok := obj.(*types.Func)
+		if !ok || fn.Origin() != meth.Origin() {
+			t.Errorf("Object(%q) = %v, want an instance of %v", path, obj, meth.Origin())
+			continue
+		}
+		if got, want := fn.Type().(*types.Signature).Recv().Type(), meth.Type().(*types.Signature).Recv().Type(); !types.Identical(got, want) {
+			t.Errorf("Object(%q) has receiver type %v, want %v", path, got, want)
+		}
+	}
+
+	// Without the Instances option, the instance has no path.
+	if path, err := objectpath.For(method("V1")); err == nil {
+		t.Errorf("For(%v) = %q, want error", method("V1"), path)
+	}
+
+	// bad paths
+	for _, test := range []struct {
+		path    objectpath.Path
+		wantErr string
+	}{
+		{`T.M0[int`, "invalid path: unterminated type arguments"},
+		{`T.M0[int;int]`, `invalid path: "int;int" is not a predeclared type`},
+		{`T.M0[int]`, "got 1 type arguments but b.T[P, Q any] has 2 type parameters"},
+		{`T.M0[int,"x.io/c".U]`, "package x.io/b does not import x.io/c"},
+		{`T.M0[int,int]x`, `invalid path: unexpected "x" after type arguments`},
+		{`V1[int]`, "invalid path: type arguments applied to var b.V1 b.T[int, a.U], which is not a method"},
+	} {
+		obj, err := objectpath.Object(b, test.path)
+		if err == nil {
+			t.Errorf("Object(%q) = %v, want error", test.path, obj)
+			continue
+		}
+		if got := strings.ReplaceAll(err.Error(), "x.io/", ""); got != strings.ReplaceAll(test.wantErr, "x.io/", "") {
+			t.Errorf("Object(%q) error was %q, want %q", test.path, got, test.wantErr)
+		}
+	}
+}
@@ -197,6 +197,75 @@ type Issue68046 interface { f(x int) interface{Issue68046} }
 	}
 }
 
+func TestLocalPaths(t *testing.T) {
+	const src = `
+-- go.mod --
+module x.io
+go 1.18
+
+-- b/b.go --
+package b
+
+type T struct{}
+
+func (T) m() {
+	type L struct{ Y int }
+}
+
+func f() {
+	type A struct{ X int }
+	{
+		type B = struct{ Z int }
+	}
+	_ = func() {
+		type C interface{ M() }
+	}
+}
+
+func F[P any]() {
+	type D []P
+}
+
+const K = 0
+`
+
+	pkgmap := loadPackages(t, src, "./b")
+	enc := &objectpath.Encoder{Local: true}
+
+	paths := []pathTest{
+		// Good paths
+		{"b", "f:0", "type A struct{X int}", ""},
+		{"b", "f:0.UF0", "field X int", ""},
+		{"b", "f:1", "type B = struct{Z int}", ""},
+		{"b", "f:1.F0", "field Z int", ""},
+		{"b", "f:2", "type C interface{M()}", ""},
+		{"b", "f:2.UM0", "func (b.C).M()", ""},
+		{"b", "F:0", "type D []P", ""},
+		{"b", "T.M0:0", "type L struct{Y int}", ""},
+		{"b", "T.M0:0.UF0", "field Y int", ""},
+
+		// Bad paths
+		{"b", "f:", "", `invalid path: bad numeric operand "" for code ':'`},
+		{"b", "f:3", "", "local type index 3 out of range [0-3)"},
+		{"b", "f.:0", "", "invalid path: unexpected ':' in type context"},
+		{"b", "K:0", "", "cannot apply ':' to const b.K untyped int (got *types.Const, want func)"},
+	}
+	for _, test := range paths {
+		if err := testPathWith(enc, pkgmap, test); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Without the Local option, local types have no path.
+	local, err := objectpath.Object(pkgmap["x.io/b"].Types, "f:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err := objectpath.For(local); err == nil {
+		t.Errorf("For(%v) = %q, want error", local, path)
+	}
+}
+
 // loadPackages expands the archive and loads the package patterns relative to its root.
 func loadPackages(t *testing.T, archive string, patterns ...string) map[string]*packages.Package {
 	// TODO(adonovan): ExtractTxtarToTmp (sans File) would be useful.
@@ -217,6 +286,11 @@ type pathTest struct {
 }
 
 func testPath(pkgmap map[string]*packages.Package, test pathTest) error {
+	return testPathWith(new(objectpath.Encoder), pkgmap, test)
+}
+
+// testPathWith is like testPath but uses the specified encoder.
+func testPathWith(enc *objectpath.Encoder, pkgmap map[string]*packages.Package, test pathTest) error {
 	// We test objectpath by enumerating a set of paths
 	// and ensuring that Path(pkg, Object(pkg, path)) == path.
 	//
@@ -257,7 +331,7 @@ func testPath(pkgmap map[string]*packages.Package, test pathTest) error {
 	}
 
 	// check object -> path
-	path2, err := objectpath.For(obj)
+	path2, err := enc.For(obj)
 	if err != nil {
 		return fmt.Errorf("For(%v) failed: %v, want %q", obj, err, test.path)
 	}
@@ -0,0 +1,98 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfs
+
+import (
+	"context"
+	"io/fs"
+	"os"
+)
+
+// WithContext returns a FileSystem that delegates to fsys until ctx
+// is done, after which every operation, including reads and seeks of
+// files already open, fails with an error that wraps ctx.Err().
+//
+// Operations already in progress are not interrupted, but a ReadDir
+// that completes after ctx is done reports an error instead of
+// its (possibly long) result.
+func WithContext(ctx context.Context, fsys FileSystem) FileSystem {
+	return ctxFS{ctx, fsys}
+}
+
+type ctxFS struct {
+	ctx  context.Context
+	fsys FileSystem
+}
+
+func (c ctxFS) String() string { return c.fsys.String() }
+
+func (c ctxFS) RootType(path string) RootType { return c.fsys.RootType(path) }
+
+// check returns a PathError if the context is done.
+func (c ctxFS) check(op, path string) error {
+	if err := c.ctx.Err(); err != nil {
+		return &fs.PathError{Op: op, Path: path, Err: err}
+	}
+	return nil
+}
+
+func (c ctxFS) Open(path string) (ReadSeekCloser, error) {
+	if err := c.check("open", path); err != nil {
+		return nil, err
+	}
+	rsc, err := c.fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return ctxFile{rsc, c, path}, nil
+}
+
+func (c ctxFS) Lstat(path string) (os.FileInfo, error) {
+	if err := c.check("lstat", path); err != nil {
+		return nil, err
+	}
+	return c.fsys.Lstat(path)
+}
+
+func (c ctxFS) Stat(path string) (os.FileInfo, error) {
+	if err := c.check("stat", path); err != nil {
+		return nil, err
+	}
+	return c.fsys.Stat(path)
+}
+
+func (c ctxFS) ReadDir(path string) ([]os.FileInfo, error) {
+	if err := c.check("readdir", path); err != nil {
+		return nil, err
+	}
+	infos, err := c.fsys.ReadDir(path)
+	if err := c.check("readdir", path); err != nil {
+		return nil, err
+	}
+	return infos, err
+}
+
+type ctxFile struct {
+	rsc  ReadSeekCloser
+	cfs  ctxFS
+	path string
+}
+
+func (f ctxFile) Read(p []byte) (int, error) {
+	if err := f.cfs.check("read", f.path); err != nil {
+		return 0, err
+	}
+	return f.rsc.Read(p)
+}
+
+func (f ctxFile) Seek(offset int64, whence int) (int64, error) {
+	if err := f.cfs.check("seek", f.path); err != nil {
+		return 0, err
+	}
+	return f.rsc.Seek(offset, whence)
+}
+
+// Close closes the file even if the context is done.
+func (f ctxFile) Close() error { return f.rsc.Close() }
@@ -8,9 +8,12 @@
 package vfs
 
 import (
+	"errors"
+	"io"
 	"io/fs"
 	"os"
 	"path"
+	"sort"
 	"strings"
 )
 
@@ -78,3 +81,143 @@ type noSeekFile struct {
 func (f *noSeekFile) Seek(offset int64, whence int) (int64, error) {
 	return 0, &fs.PathError{Op: "seek", Path: f.path, Err: fs.ErrInvalid}
 }
+
+// ToFS converts a FileSystem to an fs.FS, the inverse of FromFS.
+// The result also implements fs.StatFS and fs.ReadDirFS.
+// The name "." denotes the root "/" of fsys.
+func ToFS(fsys FileSystem) fs.FS {
+	return fileSystemToFsys{fsys}
+}
+
+type fileSystemToFsys struct {
+	fsys FileSystem
+}
+
+// vfsPath returns the FileSystem path for the fs.FS name.
+func (f fileSystemToFsys) vfsPath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return "/", nil
+	}
+	return "/" + name, nil
+}
+
+func (f fileSystemToFsys) Open(name string) (fs.File, error) {
+	p, err := f.vfsPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.fsys.Stat(p)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	info = namedFileInfo{info, path.Base(name)}
+	if info.IsDir() {
+		return &dirFile{fsys: f, name: name, info: info}, nil
+	}
+	rsc, err := f.fsys.Open(p)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	return &regularFile{rsc, info}, nil
+}
+
+func (f fileSystemToFsys) Stat(name string) (fs.FileInfo, error) {
+	p, err := f.vfsPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.fsys.Stat(p)
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	return namedFileInfo{info, path.Base(name)}, nil
+}
+
+func (f fileSystemToFsys) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := f.vfsPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := f.fsys.ReadDir(p)
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// pathError returns err as an *fs.PathError for the fs.FS name,
+// replacing the FileSystem path of any existing PathError.
+func pathError(op, name string, err error) error {
+	var perr *fs.PathError
+	if errors.As(err, &perr) {
+		err = perr.Err
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// namedFileInfo overrides the name of a FileInfo, since
+// FileSystems disagree on the name of the root directory.
+type namedFileInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (fi namedFileInfo) Name() string { return fi.name }
+
+// regularFile is an fs.File for a regular file of a FileSystem.
+type regularFile struct {
+	ReadSeekCloser
+	info fs.FileInfo
+}
+
+func (f *regularFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+// dirFile is an fs.ReadDirFile for a directory of a FileSystem.
+// Its entries are read on the first call to ReadDir.
+type dirFile struct {
+	fsys    fileSystemToFsys
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry // entries not yet returned by ReadDir
+	read    bool          // entries have been read from fsys
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *dirFile) Close() error { return nil }
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
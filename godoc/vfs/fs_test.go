@@ -0,0 +1,77 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/tools/godoc/vfs"
+	"golang.org/x/tools/godoc/vfs/mapfs"
+)
+
+func TestToFS(t *testing.T) {
+	fsys := vfs.ToFS(mapfs.New(map[string]string{
+		"a.txt":         "a",
+		"dir/b.txt":     "bb",
+		"dir/sub/c.txt": "ccc",
+	}))
+	if err := fstest.TestFS(fsys, "a.txt", "dir/b.txt", "dir/sub/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Open("/a.txt"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open(/a.txt) = %v, want ErrInvalid", err)
+	}
+}
+
+func TestFSRoundTrip(t *testing.T) {
+	mfs := fstest.MapFS{
+		"a.txt":     {Data: []byte("a")},
+		"dir/b.txt": {Data: []byte("bb")},
+	}
+	if err := fstest.TestFS(vfs.ToFS(vfs.FromFS(mfs)), "a.txt", "dir/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fsys := vfs.WithContext(ctx, mapfs.New(map[string]string{
+		"dir/a.txt": "abc",
+	}))
+
+	f, err := fsys.Open("/dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := fsys.ReadDir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	if _, err := fsys.ReadDir("/dir"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadDir after cancel: got %v, want context.Canceled", err)
+	}
+	if _, err := fsys.Stat("/dir/a.txt"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Stat after cancel: got %v, want context.Canceled", err)
+	}
+	if _, err := fsys.Open("/dir/a.txt"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Open after cancel: got %v, want context.Canceled", err)
+	}
+	if _, err := io.ReadAll(f); !errors.Is(err, context.Canceled) {
+		t.Errorf("Read after cancel: got %v, want context.Canceled", err)
+	}
+
+	// The io/fs view reports cancellation too.
+	if _, err := fs.ReadDir(vfs.ToFS(fsys), "dir"); !errors.Is(err, context.Canceled) {
+		t.Errorf("fs.ReadDir after cancel: got %v, want context.Canceled", err)
+	}
+}
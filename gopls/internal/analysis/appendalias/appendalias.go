@@ -0,0 +1,219 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package appendalias
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/astutil/edge"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "appendalias",
+	Doc:      analysisinternal.MustExtractDoc(doc, "appendalias"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/appendalias",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	for curFile := range cursor.Root(inspect).Children() {
+		if ast.IsGenerated(curFile.Node().(*ast.File)) {
+			continue
+		}
+		for cur := range curFile.Preorder((*ast.CallExpr)(nil)) {
+			call := cur.Node().(*ast.CallExpr)
+			if isBuiltin(pass.TypesInfo, call, "append") && len(call.Args) >= 2 {
+				checkAppend(pass, cur, call)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// checkAppend checks a call append(s, ...) or append(s[:i], ...)
+// where s is a local variable.
+func checkAppend(pass *analysis.Pass, cur cursor.Cursor, call *ast.CallExpr) {
+	info := pass.TypesInfo
+
+	// Find the variable s, and whether it is resliced.
+	var (
+		id    *ast.Ident
+		slice *ast.SliceExpr
+	)
+	switch arg := ast.Unparen(call.Args[0]).(type) {
+	case *ast.Ident:
+		id = arg
+	case *ast.SliceExpr:
+		if x, ok := ast.Unparen(arg.X).(*ast.Ident); ok && !arg.Slice3 {
+			id, slice = x, arg
+		}
+	}
+	if id == nil {
+		return
+	}
+	s, ok := info.Uses[id].(*types.Var)
+	if !ok || !isLocal(s) {
+		return
+	}
+	if _, ok := s.Type().Underlying().(*types.Slice); !ok {
+		return // e.g. a type parameter, or a string resliced from an array
+	}
+
+	// append(s[:0], s...)
+	if slice != nil && call.Ellipsis.IsValid() && len(call.Args) == 2 &&
+		isZero(info, slice.Low) && slice.High != nil && isZero(info, slice.High) {
+		if arg, ok := ast.Unparen(call.Args[1]).(*ast.Ident); ok && info.Uses[arg] == s {
+			pass.Report(analysis.Diagnostic{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				Message: fmt.Sprintf("append(%[1]s[:0], %[1]s...) does not copy %[1]s: its result shares the backing array of %[1]s", s.Name()),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message: fmt.Sprintf("Use append(%[1]s[:0:0], %[1]s...)", s.Name()),
+					TextEdits: []analysis.TextEdit{{
+						Pos:     slice.High.End(),
+						End:     slice.High.End(),
+						NewText: []byte(":0"),
+					}},
+				}},
+			})
+			return
+		}
+	}
+
+	// The result must be assigned to a variable other than s.
+	ek, _ := cur.Edge()
+	if ek != edge.AssignStmt_Rhs {
+		return
+	}
+	assign := cur.Parent().Node().(*ast.AssignStmt)
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	lhs, ok := ast.Unparen(assign.Lhs[0]).(*ast.Ident)
+	if !ok || lhs.Name == "_" {
+		return
+	}
+	v, ok := info.ObjectOf(lhs).(*types.Var)
+	if !ok || v == s {
+		return
+	}
+
+	// Find the enclosing function.
+	var curFn cursor.Cursor
+	for curFn = range cur.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+		break
+	}
+	if curFn.Node() == nil {
+		return // package-level variable initializer
+	}
+
+	// Inspect the later uses of s, up to the end of the first
+	// assignment to s.
+	end := token.NoPos
+	for curUse := range curFn.Preorder((*ast.Ident)(nil)) {
+		use := curUse.Node().(*ast.Ident)
+		if use.Pos() < assign.End() || info.Uses[use] != s {
+			continue
+		}
+		if end.IsValid() && use.Pos() >= end {
+			break
+		}
+		if ek, _ := curUse.Edge(); ek == edge.AssignStmt_Lhs {
+			// s is reassigned; later uses refer to another array,
+			// but the right-hand side still refers to the old one.
+			end = curUse.Parent().Node().End()
+			continue
+		}
+		if slice != nil {
+			pass.Report(analysis.Diagnostic{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				Message: fmt.Sprintf("append to %s[:...] assigned to %s overwrites the elements of %s, which is used later", s.Name(), v.Name(), s.Name()),
+				Related: []analysis.RelatedInformation{{
+					Pos:     use.Pos(),
+					End:     use.End(),
+					Message: fmt.Sprintf("%s used here", s.Name()),
+				}},
+			})
+			return
+		}
+		if op := modification(info, curUse); op != "" {
+			pass.Report(analysis.Diagnostic{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				Message: fmt.Sprintf("%s may share its backing array with %s, which is modified later", v.Name(), s.Name()),
+				Related: []analysis.RelatedInformation{{
+					Pos:     use.Pos(),
+					End:     use.End(),
+					Message: fmt.Sprintf("%s %s here", s.Name(), op),
+				}},
+			})
+			return
+		}
+	}
+}
+
+// modification returns a description of the operation, if any, by
+// which the use of a slice variable at cur may modify its elements:
+// an append, a copy, or an assignment to an element.
+func modification(info *types.Info, cur cursor.Cursor) string {
+	ek, i := cur.Edge()
+	switch ek {
+	case edge.CallExpr_Args:
+		if i == 0 {
+			call := cur.Parent().Node().(*ast.CallExpr)
+			if isBuiltin(info, call, "append") && len(call.Args) >= 2 {
+				return "appended to"
+			}
+			if isBuiltin(info, call, "copy") {
+				return "copied to"
+			}
+		}
+	case edge.IndexExpr_X:
+		parent := cur.Parent()
+		switch ek, _ := parent.Edge(); ek {
+		case edge.AssignStmt_Lhs, edge.IncDecStmt_X:
+			return "modified"
+		}
+	}
+	return ""
+}
+
+// isBuiltin reports whether call is a call to the named built-in function.
+func isBuiltin(info *types.Info, call *ast.CallExpr, name string) bool {
+	b, ok := typeutil.Callee(info, call).(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// isLocal reports whether v is a local variable or parameter.
+func isLocal(v *types.Var) bool {
+	return !v.IsField() && v.Pkg() != nil && v.Parent() != v.Pkg().Scope()
+}
+
+// isZero reports whether e is absent or the constant zero.
+func isZero(info *types.Info, e ast.Expr) bool {
+	if e == nil {
+		return true
+	}
+	tv, ok := info.Types[e]
+	return ok && tv.Value != nil && constant.Sign(tv.Value) == 0
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package appendalias defines an analyzer that checks for calls to
+// append whose result may unexpectedly share its backing array with
+// another slice.
+//
+// # Analyzer appendalias
+//
+// appendalias: check for append results that alias the original slice
+//
+// The result of append(s, ...) shares its backing array with s when s
+// has enough spare capacity. Assigning the result to a variable other
+// than s is therefore a common source of aliasing bugs. The appendalias
+// analyzer reports such an assignment when s is modified later in the
+// same function, by another append, a copy, or an assignment to one of
+// its elements:
+//
+//	x := append(base, 1)
+//	y := append(base, 2) // may overwrite x[len(base)]
+//
+// It also reports an assignment of the result of append(s[:i], ...) to
+// another variable when s is used later, since the call overwrites the
+// elements of s from index i onwards:
+//
+//	rest := append(s[:i], s[i+1:]...)
+//	use(s) // s no longer holds its original elements
+//
+// Finally, it reports append(s[:0], s...), which does not copy s, as
+// its result shares the backing array of s. The likely intent is
+// append(s[:0:0], s...) or slices.Clone(s), and a fix is offered to
+// use the former.
+//
+// The analysis is purely syntactic: it considers only local variables
+// and the statements that follow the append call in the source, and
+// stops at the first assignment to s. It does not account for slices
+// that are known to be at full capacity.
+package appendalias
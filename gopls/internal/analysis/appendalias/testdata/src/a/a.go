@@ -0,0 +1,102 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+var global []int
+
+func sink(...any) {}
+
+func appendTwice(base []int) {
+	x := append(base, 1) // want "x may share its backing array with base, which is modified later"
+	y := append(base, 2)
+	sink(x, y)
+}
+
+func elementAssigned(base []int) {
+	var x []int
+	x = append(base, 1) // want "x may share its backing array with base, which is modified later"
+	base[0] = 2
+	sink(x)
+}
+
+func incremented(base []int) {
+	x := append(base, 1) // want "x may share its backing array with base, which is modified later"
+	base[0]++
+	sink(x)
+}
+
+func copied(base, other []int) {
+	x := append(base, 1) // want "x may share its backing array with base, which is modified later"
+	copy(base, other)
+	sink(x)
+}
+
+func readOnly(base []int) {
+	x := append(base, 1) // ok: base is only read
+	sink(x, base, len(base))
+}
+
+func reassigned(base []int) {
+	x := append(base, 1) // ok: base refers to another array when modified
+	base = make([]int, 3)
+	base[0] = 1
+	sink(x)
+}
+
+func reassignedFromItself(base []int) {
+	x := append(base, 1) // want "x may share its backing array with base, which is modified later"
+	base = append(base, 2)
+	base[0] = 1
+	sink(x)
+}
+
+func sameVariable(s []int) {
+	s = append(s, 1) // ok
+	s = append(s, 2)
+	sink(s)
+}
+
+func fullSlice(base []int) {
+	x := append(base[:len(base):len(base)], 1) // ok: the three-index slice forces a copy
+	y := append(base, 2)
+	sink(x, y)
+}
+
+func nonLocal() {
+	x := append(global, 1) // ok: not a local variable
+	global = append(global, 2)
+	sink(x)
+}
+
+func remove(s []int, i int) {
+	rest := append(s[:i], s[i+1:]...) // want "append to s\\[:...\\] assigned to rest overwrites the elements of s, which is used later"
+	sink(rest, s)
+}
+
+func removeUnused(s []int, i int) []int {
+	rest := append(s[:i], s[i+1:]...) // ok: s is not used later
+	return rest
+}
+
+func selfCopy(s []int) []int {
+	clone := append(s[:0], s...) // want `append\(s\[:0\], s...\) does not copy s`
+	return clone
+}
+
+func selfCopyInline(s []int) {
+	sink(append(s[:0], s...)) // want `append\(s\[:0\], s...\) does not copy s`
+}
+
+func realCopy(s []int) []int {
+	return append(s[:0:0], s...) // ok
+}
+
+func inClosure(base []int) func() {
+	return func() {
+		x := append(base, 1) // want "x may share its backing array with base, which is modified later"
+		base = append(base, 2)
+		sink(x)
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package deadbranch
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/astutil/edge"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "deadbranch",
+	Doc:      analysisinternal.MustExtractDoc(doc, "deadbranch"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/deadbranch",
+}
+
+// A checker holds the state of the analysis of one package.
+type checker struct {
+	pass     *analysis.Pass
+	file     *ast.File
+	content  []byte                // content of file
+	portable map[*types.Const]bool // memo of portableConst
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	c := &checker{pass: pass, portable: make(map[*types.Const]bool)}
+	for curFile := range cursor.Root(inspect).Children() {
+		file := curFile.Node().(*ast.File)
+		if ast.IsGenerated(file) {
+			continue
+		}
+		content, err := pass.ReadFile(pass.Fset.File(file.Pos()).Name())
+		if err != nil {
+			continue
+		}
+		c.file, c.content = file, content
+
+		for cur := range curFile.Preorder((*ast.IfStmt)(nil), (*ast.SwitchStmt)(nil)) {
+			switch n := cur.Node().(type) {
+			case *ast.IfStmt:
+				c.checkIf(cur, n)
+			case *ast.SwitchStmt:
+				c.checkSwitch(n)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// checkIf reports the unreachable branch, if any, of an if statement
+// with a constant condition.
+func (c *checker) checkIf(cur cursor.Cursor, stmt *ast.IfStmt) {
+	cond := c.pass.TypesInfo.Types[stmt.Cond].Value
+	if cond == nil || cond.Kind() != constant.Bool {
+		return
+	}
+	always := constant.BoolVal(cond)
+	if always && stmt.Else == nil {
+		return // nothing is unreachable
+	}
+	var msg string
+	if always {
+		msg = "condition is always true: the else branch is unreachable"
+	} else {
+		msg = "condition is always false: the if branch is unreachable"
+	}
+	diag := analysis.Diagnostic{
+		Pos: stmt.Cond.Pos(),
+		End: stmt.Cond.End(),
+	}
+	if !c.portableExpr(stmt.Cond) {
+		diag.Message = msg + " in the current build configuration"
+		c.pass.Report(diag)
+		return
+	}
+	diag.Message = msg
+
+	// Offer a fix unless the statement has an init statement,
+	// which may have effects, or a label, which needs a statement.
+	ek, _ := cur.Edge()
+	if stmt.Init == nil && ek != edge.LabeledStmt_Stmt {
+		var edit analysis.TextEdit
+		switch {
+		case ek == edge.IfStmt_Else && always:
+			// else if true { A } else { B } => else { A }
+			edit = c.replace(stmt, stmt.Body)
+		case ek == edge.IfStmt_Else && stmt.Else != nil:
+			// else if false { A } else B => else B
+			edit = c.replace(stmt, stmt.Else)
+		case ek == edge.IfStmt_Else:
+			// } else if false { A } => }
+			parent := cur.Parent().Node().(*ast.IfStmt)
+			edit = analysis.TextEdit{Pos: parent.Body.End(), End: stmt.End()}
+		case always:
+			// if true { A } else B => A
+			edit = c.unwrap(stmt, stmt.Body)
+		case stmt.Else == nil:
+			// if false { A } => (nothing)
+			edit = c.deleteLines(stmt)
+		default:
+			// if false { A } else B => B
+			if block, ok := stmt.Else.(*ast.BlockStmt); ok {
+				edit = c.unwrap(stmt, block)
+			} else {
+				edit = c.replace(stmt, stmt.Else)
+			}
+		}
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Remove unreachable branch",
+			TextEdits: []analysis.TextEdit{edit},
+		}}
+	}
+	c.pass.Report(diag)
+}
+
+// checkSwitch reports the clauses of a switch statement with a
+// constant tag that can never be selected.
+func (c *checker) checkSwitch(stmt *ast.SwitchStmt) {
+	info := c.pass.TypesInfo
+	tag := constant.MakeBool(true)
+	if stmt.Tag != nil {
+		tag = info.Types[stmt.Tag].Value
+		if tag == nil {
+			return
+		}
+	}
+	portable := stmt.Tag == nil || c.portableExpr(stmt.Tag)
+
+	// Classify the clauses. A clause is dead if it cannot be
+	// selected, because every one of its values differs from the
+	// tag or an earlier clause is always selected, and it cannot be
+	// reached by fallthrough from the preceding clause.
+	var (
+		dead     []*ast.CaseClause
+		selected bool // some earlier clause is always selected
+		deflt    *ast.CaseClause
+		reached  bool // the preceding clause may fall through
+	)
+	for _, clause := range stmt.Body.List {
+		cc := clause.(*ast.CaseClause)
+		isDead := selected
+		if cc.List == nil {
+			deflt = cc
+			isDead = false // decided below
+		} else if !selected {
+			isDead = true
+			for _, e := range cc.List {
+				v := info.Types[e].Value
+				if v == nil || v.Kind() != tag.Kind() {
+					isDead = false // may match
+					continue
+				}
+				if !c.portableExpr(e) {
+					portable = false
+				}
+				if constant.Compare(v, token.EQL, tag) {
+					isDead = false
+					selected = true
+					break
+				}
+			}
+		}
+		if reached {
+			isDead = false
+		}
+		if isDead {
+			dead = append(dead, cc)
+		}
+		if cc == deflt && reached {
+			deflt = nil // the default is reached by fallthrough
+		}
+		reached = !isDead && fallsThrough(cc)
+	}
+	if selected && deflt != nil {
+		dead = append(dead, deflt)
+	}
+
+	for _, cc := range dead {
+		what := "case"
+		if cc.List == nil {
+			what = "default case"
+		}
+		diag := analysis.Diagnostic{
+			Pos:     cc.Case,
+			End:     cc.Colon + 1,
+			Message: fmt.Sprintf("%s is never selected: this branch is unreachable", what),
+		}
+		if !portable {
+			diag.Message += " in the current build configuration"
+		} else {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "Remove unreachable branch",
+				TextEdits: []analysis.TextEdit{c.deleteLines(cc)},
+			}}
+		}
+		c.pass.Report(diag)
+	}
+}
+
+// fallsThrough reports whether the case clause ends with a
+// fallthrough statement.
+func fallsThrough(cc *ast.CaseClause) bool {
+	if len(cc.Body) > 0 {
+		branch, ok := cc.Body[len(cc.Body)-1].(*ast.BranchStmt)
+		return ok && branch.Tok == token.FALLTHROUGH
+	}
+	return false
+}
+
+// replace returns an edit that replaces the old node by the text of
+// the new one.
+func (c *checker) replace(old, new ast.Node) analysis.TextEdit {
+	return analysis.TextEdit{
+		Pos:     old.Pos(),
+		End:     old.End(),
+		NewText: c.text(new.Pos(), new.End()),
+	}
+}
+
+// deleteLines returns an edit that deletes the node, which must be
+// the last on its line, along with the indentation and line break
+// that precede it, if it is the first on its line.
+func (c *checker) deleteLines(n ast.Node) analysis.TextEdit {
+	start := n.Pos()
+	pos := start
+	for pos > c.file.FileStart && strings.IndexByte(" \t", c.content[pos-1-c.file.FileStart]) >= 0 {
+		pos--
+	}
+	if pos > c.file.FileStart && c.content[pos-1-c.file.FileStart] == '\n' {
+		start = pos - 1
+	}
+	return analysis.TextEdit{Pos: start, End: n.End()}
+}
+
+// unwrap returns an edit that replaces the statement by the
+// statements of the block, outdented by one level, or by the block
+// itself if removing its braces might change the meaning of its
+// statements or their text.
+func (c *checker) unwrap(stmt ast.Stmt, block *ast.BlockStmt) analysis.TextEdit {
+	if !canUnwrap(block) {
+		return c.replace(stmt, block)
+	}
+	body := c.text(block.Lbrace+1, block.Rbrace)
+	lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimPrefix(line, []byte("\t"))
+	}
+	return analysis.TextEdit{
+		Pos:     stmt.Pos(),
+		End:     stmt.End(),
+		NewText: bytes.Join(lines, []byte("\n")),
+	}
+}
+
+// canUnwrap reports whether the statements of the block may be moved
+// into the enclosing block: that is, the block declares nothing, and
+// contains no multi-line raw strings, whose text must not be outdented.
+func canUnwrap(block *ast.BlockStmt) bool {
+	for _, stmt := range block.List {
+		switch stmt := stmt.(type) {
+		case *ast.DeclStmt:
+			return false
+		case *ast.AssignStmt:
+			if stmt.Tok == token.DEFINE {
+				return false
+			}
+		}
+	}
+	ok := true
+	ast.Inspect(block, func(n ast.Node) bool {
+		if lit, is := n.(*ast.BasicLit); is && lit.Kind == token.STRING && strings.Contains(lit.Value, "\n") {
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+// text returns the source text of the file between start and end.
+func (c *checker) text(start, end token.Pos) []byte {
+	return c.content[start-c.file.FileStart : end-c.file.FileStart]
+}
+
+// portableExpr reports whether the constant value of the expression
+// is known to be the same in every build configuration.
+func (c *checker) portableExpr(e ast.Expr) bool {
+	info := c.pass.TypesInfo
+	ok := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if obj, is := info.Uses[n].(*types.Const); is && !c.portableConst(obj) {
+				ok = false
+			}
+		case *ast.CallExpr:
+			// unsafe.Sizeof, Alignof, and Offsetof depend on the architecture.
+			if b, is := typeutil.Callee(info, n).(*types.Builtin); is && b.Pkg() != nil && b.Pkg().Path() == "unsafe" {
+				ok = false
+			}
+		case *ast.UnaryExpr:
+			// So does ^x when x is of type int, uint, or uintptr.
+			if n.Op == token.XOR {
+				if basic, is := info.TypeOf(n).Underlying().(*types.Basic); is {
+					switch basic.Kind() {
+					case types.Int, types.Uint, types.Uintptr:
+						ok = false
+					}
+				}
+			}
+		}
+		return ok
+	})
+	return ok
+}
+
+// portableConst reports whether the value of the constant is known
+// to be the same in every build configuration: it is predeclared, or
+// declared in this package in a file without build constraints by an
+// expression that is itself portable.
+func (c *checker) portableConst(obj *types.Const) bool {
+	if obj.Pkg() == nil {
+		return true // true, false, iota
+	}
+	if obj.Pkg() != c.pass.Pkg {
+		return false // declared in an unknown file
+	}
+	if portable, ok := c.portable[obj]; ok {
+		return portable
+	}
+	c.portable[obj] = false // (constants are not recursive)
+
+	var file *ast.File
+	for _, f := range c.pass.Files {
+		if f.FileStart <= obj.Pos() && obj.Pos() < f.FileEnd {
+			file = f
+			break
+		}
+	}
+	if file == nil || hasBuildConstraint(file) || isGOOSOrGOARCHFile(c.pass.Fset.File(file.Pos()).Name()) {
+		return false
+	}
+
+	// Find the expression that defines the constant, which may be
+	// implicitly repeated from a preceding spec, as with iota.
+	portable := false
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST || !(decl.Pos() <= obj.Pos() && obj.Pos() < decl.End()) {
+			continue
+		}
+		var values []ast.Expr
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if len(spec.Values) > 0 {
+				values = spec.Values
+			}
+			for i, name := range spec.Names {
+				if name.Pos() == obj.Pos() && i < len(values) {
+					portable = c.portableExpr(values[i])
+				}
+			}
+		}
+	}
+	c.portable[obj] = portable
+	return portable
+}
+
+// hasBuildConstraint reports whether the file has a //go:build or
+// // +build constraint.
+func hasBuildConstraint(file *ast.File) bool {
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+		for _, comment := range cg.List {
+			if constraint.IsGoBuild(comment.Text) || constraint.IsPlusBuild(comment.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGOOSOrGOARCHFile reports whether the name of the file, such as
+// os_linux.go or asm_linux_amd64_test.go, restricts it to a particular
+// operating system or architecture, following the rules of go/build.
+func isGOOSOrGOARCHFile(filename string) bool {
+	name := strings.TrimSuffix(filepath.Base(filename), ".go")
+	name = strings.TrimSuffix(name, "_test")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return false
+	}
+	l := strings.Split(name[i:], "_")
+	n := len(l)
+	if n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]] {
+		return true
+	}
+	return knownOS[l[n-1]] || knownArch[l[n-1]]
+}
+
+// knownOS and knownArch are the sets of values of GOOS and GOARCH
+// recognized in file names, as in go/build.
+var (
+	knownOS = setOf("aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios",
+		"js", "linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos")
+	knownArch = setOf("386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64",
+		"mips", "mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le",
+		"riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm")
+)
+
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
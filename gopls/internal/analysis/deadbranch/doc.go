@@ -0,0 +1,34 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package deadbranch defines an analyzer that checks for branches of
+// if and switch statements that can never execute because their
+// conditions are constant.
+//
+// # Analyzer deadbranch
+//
+// deadbranch: check for branches that are unreachable due to constant conditions
+//
+// The deadbranch analyzer reports the then or else branch of an if
+// statement whose condition is a constant, and the cases of a switch
+// statement that can never be selected because the tag and the case
+// values are constants:
+//
+//	const verbose = false
+//	...
+//	if verbose { // condition is always false: the if branch is unreachable
+//		log.Print("...")
+//	}
+//
+// It offers a fix to remove the unreachable branch.
+//
+// The values of some constants depend on the build configuration: for
+// example, runtime.GOOS, a constant declared in a file such as
+// os_linux.go or one with a //go:build constraint, or an expression
+// involving unsafe.Sizeof. A branch that depends on such a constant is
+// reported only as unreachable in the current build configuration,
+// without a fix, since it may be reachable in another. For the same
+// reason, no fix is offered for a condition that involves a constant
+// declared in another package, as its declaration cannot be inspected.
+package deadbranch
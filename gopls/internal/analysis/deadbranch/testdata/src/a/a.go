@@ -0,0 +1,127 @@
+package a
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+const debug = false
+
+const (
+	modeA = iota
+	modeB
+)
+
+const mode = modeB
+
+const isFoo = onFoo // declared in a file with build constraints
+
+func ifs(x int) {
+	if debug { // want "condition is always false: the if branch is unreachable"
+		fmt.Println("debug")
+	}
+
+	if !debug { // want "condition is always true: the else branch is unreachable"
+		fmt.Println("a")
+		fmt.Println("b")
+	} else {
+		fmt.Println("c")
+	}
+
+	if mode == modeA { // want "condition is always false: the if branch is unreachable"
+		fmt.Println("a")
+	} else {
+		fmt.Println("b")
+	}
+
+	if mode == modeA { // want "condition is always false: the if branch is unreachable"
+		fmt.Println("a")
+	} else if x > 0 {
+		fmt.Println("b")
+	}
+
+	if x > 0 {
+		fmt.Println("a")
+	} else if debug { // want "condition is always false: the if branch is unreachable"
+		fmt.Println("b")
+	}
+
+	if x > 0 {
+		fmt.Println("a")
+	} else if !debug { // want "condition is always true: the else branch is unreachable"
+		fmt.Println("b")
+	} else {
+		fmt.Println("c")
+	}
+
+	// Declarations in the block prevent the removal of its braces.
+	if true { // want "condition is always true: the else branch is unreachable"
+		y := x
+		fmt.Println(y)
+	} else {
+		fmt.Println("c")
+	}
+
+	// No fix is offered when there is an init statement.
+	if y := x; debug { // want "condition is always false: the if branch is unreachable"
+		fmt.Println(y)
+	}
+
+	// Nothing is unreachable.
+	if !debug {
+		fmt.Println("a")
+	}
+}
+
+func switches(x int) {
+	switch mode {
+	case modeA: // want "case is never selected: this branch is unreachable"
+		fmt.Println("a")
+	case x:
+		fmt.Println("x")
+	case modeB:
+		fmt.Println("b")
+	case 2, 3: // want "case is never selected: this branch is unreachable"
+		fmt.Println("c")
+	default: // want "default case is never selected: this branch is unreachable"
+		fmt.Println("d")
+	}
+
+	switch {
+	case debug: // want "case is never selected: this branch is unreachable"
+		fmt.Println("a")
+	case x > 0:
+		fmt.Println("x")
+	}
+
+	// A clause reached by fallthrough is not dead.
+	switch mode {
+	case modeB:
+		fmt.Println("b")
+		fallthrough
+	case modeA:
+		fmt.Println("a")
+	}
+}
+
+func buildDependent() {
+	if runtime.GOOS == "plan9" { // want "condition is always false: the if branch is unreachable in the current build configuration"
+		fmt.Println("plan9")
+	}
+
+	if onFoo { // want "condition is always true: the else branch is unreachable in the current build configuration"
+		fmt.Println("foo")
+	} else {
+		fmt.Println("bar")
+	}
+
+	if unsafe.Sizeof(0) == 2 { // want "condition is always false: the if branch is unreachable in the current build configuration"
+		fmt.Println("16-bit")
+	}
+
+	switch isFoo {
+	case false: // want "case is never selected: this branch is unreachable in the current build configuration"
+		fmt.Println("not foo")
+	}
+}
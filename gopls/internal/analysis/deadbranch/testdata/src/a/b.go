@@ -0,0 +1,6 @@
+//go:build !nosuchtag
+
+package a
+
+const onFoo = true
+
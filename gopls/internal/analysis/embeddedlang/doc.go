@@ -0,0 +1,26 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package embeddedlang defines an analyzer that checks the syntax of
+// strings written in embedded languages, such as regular expressions,
+// that are passed to recognized APIs.
+//
+// # Analyzer embeddedlang
+//
+// embeddedlang: check syntax of strings in embedded languages
+//
+// The embeddedlang analyzer reports constant strings that are passed
+// to functions expecting text in some embedded language, and that are
+// not valid in that language. Such mistakes otherwise surface only at
+// run time. For example:
+//
+//	var re = regexp.MustCompile(`^(\w+`) // missing closing )
+//
+// Where possible, the diagnostic indicates the precise offending
+// portion of the string literal.
+//
+// The analyzer checks the pattern arguments of the functions in the
+// regexp package. Other languages and APIs may be added by calling
+// [Register].
+package embeddedlang
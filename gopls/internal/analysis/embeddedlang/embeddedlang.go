@@ -0,0 +1,185 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embeddedlang
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "embeddedlang",
+	Doc:      analysisinternal.MustExtractDoc(doc, "embeddedlang"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/embeddedlang",
+}
+
+// A Language is an embedded language whose syntax can be checked.
+type Language struct {
+	Name string // e.g. "regular expression"
+
+	// Check reports the first syntax error in s, or nil if s is valid.
+	Check func(s string) *Error
+}
+
+// An Error is a syntax error in a string of an embedded language.
+//
+// Start and End are byte offsets within the (unquoted) string
+// that delimit the erroneous portion; if End <= Start, the error
+// applies to the entire string.
+type Error struct {
+	Start, End int
+	Message    string
+}
+
+// A Sink is a parameter of a function or method whose argument is
+// text in an embedded language.
+type Sink struct {
+	PkgPath string    // package path of the function, e.g. "regexp"
+	Recv    string    // name of the receiver type, or "" for a function
+	Name    string    // name of the function or method
+	Arg     int       // index of the parameter
+	Lang    *Language // language of the argument
+}
+
+var (
+	sinksMu sync.Mutex
+	sinks   = make(map[sinkKey][]Sink)
+)
+
+type sinkKey struct{ pkgPath, recv, name string }
+
+// Register adds sinks to the set checked by the analyzer.
+// It is typically called from an init function.
+func Register(ss ...Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for _, s := range ss {
+		k := sinkKey{s.PkgPath, s.Recv, s.Name}
+		sinks[k] = append(sinks[k], s)
+	}
+}
+
+// lookupSinks returns the registered sinks for the given callee.
+func lookupSinks(fn *types.Func) []Sink {
+	if fn.Pkg() == nil {
+		return nil
+	}
+	var recv string
+	if r := fn.Signature().Recv(); r != nil {
+		_, named := typesinternal.ReceiverNamed(r)
+		if named == nil {
+			return nil
+		}
+		recv = named.Obj().Name()
+	}
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	return sinks[sinkKey{fn.Pkg().Path(), recv, fn.Name()}]
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	for n := range inspect.PreorderSeq((*ast.CallExpr)(nil)) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			continue
+		}
+		for _, sink := range lookupSinks(fn) {
+			if sink.Arg >= len(call.Args) {
+				continue
+			}
+			arg := call.Args[sink.Arg]
+			tv := pass.TypesInfo.Types[arg]
+			if tv.Value == nil || tv.Value.Kind() != constant.String {
+				continue // not a constant string
+			}
+			err := sink.Lang.Check(constant.StringVal(tv.Value))
+			if err == nil {
+				continue
+			}
+			pos, end := arg.Pos(), arg.End()
+			if lit, ok := ast.Unparen(arg).(*ast.BasicLit); ok && err.End > err.Start {
+				if start, ok := litPos(lit, err.Start); ok {
+					if stop, ok := litPos(lit, err.End); ok {
+						pos, end = start, stop
+					}
+				}
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:     pos,
+				End:     end,
+				Message: fmt.Sprintf("invalid %s: %s", sink.Lang.Name, err.Message),
+			})
+		}
+	}
+	return nil, nil
+}
+
+// litPos returns the position within the string literal lit
+// corresponding to the given byte offset within its unquoted value.
+func litPos(lit *ast.BasicLit, offset int) (token.Pos, bool) {
+	src := lit.Value
+	if len(src) < 2 {
+		return token.NoPos, false
+	}
+	if src[0] == '`' {
+		// Raw string: offsets correspond directly,
+		// except for discarded carriage returns.
+		i := 1
+		for n := 0; n < offset; i++ {
+			if i >= len(src)-1 {
+				return token.NoPos, false
+			}
+			if src[i] != '\r' {
+				n++
+			}
+		}
+		return lit.Pos() + token.Pos(i), true
+	}
+
+	// Interpreted string: decode escapes one at a time.
+	s := src[1 : len(src)-1]
+	i, n := 0, 0 // offsets in s and in value
+	for n < offset {
+		if len(s) == 0 {
+			return token.NoPos, false
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return token.NoPos, false
+		}
+		if multibyte {
+			n += utf8.RuneLen(r)
+		} else {
+			n++ // ASCII, or a byte escape such as \xff
+		}
+		i += len(s) - len(tail)
+		s = tail
+	}
+	if n != offset {
+		return token.NoPos, false // offset falls within an escape sequence
+	}
+	return lit.Pos() + 1 + token.Pos(i), true
+}
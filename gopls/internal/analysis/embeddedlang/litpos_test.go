@@ -0,0 +1,40 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embeddedlang
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+)
+
+func TestLitPos(t *testing.T) {
+	for _, test := range []struct {
+		lit    string
+		offset int
+		want   int // offset within lit, or -1 for failure
+	}{
+		{"`abc`", 0, 1},
+		{"`abc`", 3, 4},
+		{"`a\r\nb`", 2, 4}, // \r is discarded
+		{`"abc"`, 2, 3},
+		{`"a\tb"`, 2, 4},
+		{`"a\x41b"`, 2, 6},
+		{`"é*+"`, 2, 3},
+		{`"\u00e9*+"`, 2, 7},
+		{`"\u00e9*+"`, 1, -1}, // within the escape
+		{`"ab"`, 3, -1},       // out of range
+	} {
+		lit := &ast.BasicLit{ValuePos: 1, Kind: token.STRING, Value: test.lit}
+		pos, ok := litPos(lit, test.offset)
+		got := -1
+		if ok {
+			got = int(pos - lit.Pos())
+		}
+		if got != test.want {
+			t.Errorf("litPos(%s, %d) = %d, want %d", test.lit, test.offset, got, test.want)
+		}
+	}
+}
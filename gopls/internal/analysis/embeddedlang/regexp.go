@@ -0,0 +1,64 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embeddedlang
+
+import (
+	"errors"
+	"regexp/syntax"
+	"strings"
+)
+
+// This file defines the first-party checker for regular expressions
+// passed to functions of the regexp package.
+
+var (
+	// Regexp is the language of regular expressions accepted by
+	// regexp.Compile (RE2 syntax with Perl flags).
+	Regexp = &Language{
+		Name:  "regular expression",
+		Check: func(s string) *Error { return checkRegexp(s, syntax.Perl) },
+	}
+
+	// RegexpPOSIX is the language of regular expressions accepted
+	// by regexp.CompilePOSIX.
+	RegexpPOSIX = &Language{
+		Name:  "POSIX regular expression",
+		Check: func(s string) *Error { return checkRegexp(s, syntax.POSIX) },
+	}
+)
+
+func init() {
+	var sinks []Sink
+	for _, name := range []string{"Compile", "MustCompile", "MatchString", "Match", "MatchReader"} {
+		sinks = append(sinks, Sink{PkgPath: "regexp", Name: name, Arg: 0, Lang: Regexp})
+	}
+	for _, name := range []string{"CompilePOSIX", "MustCompilePOSIX"} {
+		sinks = append(sinks, Sink{PkgPath: "regexp", Name: name, Arg: 0, Lang: RegexpPOSIX})
+	}
+	Register(sinks...)
+}
+
+// checkRegexp parses s as a regular expression with the given flags.
+func checkRegexp(s string, flags syntax.Flags) *Error {
+	_, err := syntax.Parse(s, flags)
+	if err == nil {
+		return nil
+	}
+	var serr *syntax.Error
+	if !errors.As(err, &serr) {
+		return &Error{Message: err.Error()}
+	}
+	res := &Error{Message: serr.Code.String()}
+	if serr.Expr != "" {
+		res.Message += ": `" + serr.Expr + "`"
+		// Locate the offending subexpression, if it is a proper part of s.
+		if serr.Expr != s {
+			if i := strings.Index(s, serr.Expr); i >= 0 {
+				res.Start, res.End = i, i+len(serr.Expr)
+			}
+		}
+	}
+	return res
+}
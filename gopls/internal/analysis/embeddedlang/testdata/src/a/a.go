@@ -0,0 +1,20 @@
+package a
+
+import "regexp"
+
+const pattern = `a(b`
+
+var (
+	_ = regexp.MustCompile(`^\w+$`)
+	_ = regexp.MustCompile(`^(\w+$`)  // want "invalid regular expression: missing closing \\): `\\^\\(\\\\w\\+\\$`"
+	_ = regexp.MustCompile("x\\qy")   // want "invalid regular expression: invalid escape sequence: `\\\\q`"
+	_ = regexp.MustCompile("é*+")     // want "invalid regular expression: invalid nested repetition operator: `\\*\\+`"
+	_ = regexp.MustCompile(pattern)   // want "invalid regular expression: missing closing \\)"
+	_ = regexp.MustCompilePOSIX(`\d`) // want "invalid POSIX regular expression: invalid escape sequence"
+	_ = regexp.MustCompile(`\d`)
+)
+
+func f(s string) {
+	regexp.MatchString("[a-", s) // want "invalid regular expression: missing closing \\]: `\\[a-`"
+	regexp.MatchString(s, s)
+}
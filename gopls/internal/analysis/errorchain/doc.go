@@ -0,0 +1,45 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package errorchain defines an analyzer that checks for inspections
+// of error values that do not account for wrapped errors.
+//
+// # Analyzer errorchain
+//
+// errorchain: check for error inspections that ignore wrapped errors
+//
+// Since Go 1.13, an error may wrap another, forming a chain, and the
+// errors.Is and errors.As functions inspect the whole chain. The
+// errorchain analyzer reports inspections of an error that see only
+// the first error in the chain, namely type switches on an error
+// value, such as
+//
+//	switch err.(type) {
+//	case *fs.PathError:
+//		...
+//	}
+//
+// which could be expressed as a switch on calls to errors.As.
+//
+// The analyzer offers a fix to convert the switch to that form, so
+// long as the conversion preserves the behavior of the switch for
+// errors that are not wrapped. In particular, the variable bound by
+// the switch may be used in at most one case, and that case must list
+// a single type. The fix does change the behavior for wrapped errors,
+// which is usually, but not always, the intent: some code
+// deliberately inspects only the outermost error. For this reason
+// the analyzer is not enabled by default.
+//
+// The analyzer also reports calls to errors.As whose target is a
+// pointer variable that has never been assigned, and so is nil,
+// which causes errors.As to panic:
+//
+//	var target *MyError
+//	if errors.As(err, target) { // should be &target
+//		...
+//	}
+//
+// If the variable is a pointer to an interface type, the variable
+// should instead be declared as that interface type.
+package errorchain
@@ -0,0 +1,330 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errorchain
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorchain",
+	Doc:      analysisinternal.MustExtractDoc(doc, "errorchain"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/errorchain",
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func run(pass *analysis.Pass) (any, error) {
+	switch pass.Pkg.Path() {
+	case "errors", "errors_test":
+		return nil, nil // the errors package inspects errors directly
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	for curFile := range cursor.Root(inspect).Children() {
+		file := curFile.Node().(*ast.File)
+		if ast.IsGenerated(file) {
+			continue
+		}
+		for cur := range curFile.Preorder(
+			(*ast.TypeSwitchStmt)(nil),
+			(*ast.CallExpr)(nil),
+		) {
+			switch n := cur.Node().(type) {
+			case *ast.TypeSwitchStmt:
+				checkTypeSwitch(pass, file, n)
+			case *ast.CallExpr:
+				checkAsTarget(pass, cur, n)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// checkTypeSwitch reports a type switch on an error value that tests
+// for specific types, and offers to convert it to a switch on calls
+// to errors.As.
+func checkTypeSwitch(pass *analysis.Pass, file *ast.File, stmt *ast.TypeSwitchStmt) {
+	info := pass.TypesInfo
+	var (
+		bound  *ast.Ident // variable bound by the switch, if any
+		assert *ast.TypeAssertExpr
+	)
+	switch s := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		assert, _ = s.X.(*ast.TypeAssertExpr)
+	case *ast.AssignStmt:
+		bound, _ = s.Lhs[0].(*ast.Ident)
+		assert, _ = s.Rhs[0].(*ast.TypeAssertExpr)
+	}
+	if assert == nil || !types.Identical(info.TypeOf(assert.X), errorType) {
+		return
+	}
+	if !hasNonNilCase(info, stmt.Body) {
+		return // e.g. switch err.(type) { case nil: ... }
+	}
+
+	x := analysisinternal.Format(pass.Fset, assert.X)
+	diag := analysis.Diagnostic{
+		Pos:     stmt.Switch,
+		End:     stmt.Assign.End(),
+		Message: fmt.Sprintf("type switch on error %s does not match wrapped errors; use errors.As", x),
+	}
+	if edits := typeSwitchEdits(pass, file, stmt, bound, assert.X); edits != nil {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Use errors.As",
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diag)
+}
+
+// typeSwitchEdits returns the edits to convert a type switch on
+// error x to a switch on calls to errors.As, or nil if the
+// conversion would not preserve the behavior of the switch.
+func typeSwitchEdits(pass *analysis.Pass, file *ast.File, stmt *ast.TypeSwitchStmt, bound *ast.Ident, x ast.Expr) []analysis.TextEdit {
+	info := pass.TypesInfo
+	if _, ok := x.(*ast.Ident); !ok {
+		return nil // x must be safe to evaluate in each case
+	}
+
+	// Find the case, if any, whose body uses the bound variable.
+	// The variable becomes a target of errors.As, which is
+	// possible only if that case lists a single type.
+	var boundCase *ast.CaseClause
+	if bound != nil {
+		for _, clause := range stmt.Body.List {
+			clause := clause.(*ast.CaseClause)
+			if !uses(info, clause, info.Implicits[clause]) {
+				continue
+			}
+			if boundCase != nil || len(clause.List) != 1 || isNil(info, clause.List[0]) {
+				return nil // variable used in several cases, or with type error
+			}
+			boundCase = clause
+		}
+		if boundCase != nil && (stmt.Init != nil || bound.Name == x.(*ast.Ident).Name) {
+			return nil // no room to declare the target, or it would shadow x
+		}
+	}
+
+	errorsName, edits := analysisinternal.AddImport(info, file, stmt.Pos(), "errors", "errors")
+	xText := analysisinternal.Format(pass.Fset, x)
+
+	// Replace the "x.(type)" part of the header.
+	header := " "
+	if boundCase != nil {
+		T := boundCase.List[0]
+		header = fmt.Sprintf(" %s := %s; ", bound.Name, zeroValue(pass, T))
+	} else if stmt.Init != nil {
+		header = "; "
+	}
+	edits = append(edits, headerEdit(stmt.Switch, stmt.Init, stmt.Body, header))
+
+	for _, clause := range stmt.Body.List {
+		clause := clause.(*ast.CaseClause)
+		if clause.List == nil {
+			continue // default
+		}
+		var conds []string
+		for _, T := range clause.List {
+			switch {
+			case isNil(info, T):
+				conds = append(conds, xText+" == nil")
+			case clause == boundCase:
+				conds = append(conds, fmt.Sprintf("%s.As(%s, &%s)", errorsName, xText, bound.Name))
+			default:
+				conds = append(conds, fmt.Sprintf("%s.As(%s, new(%s))",
+					errorsName, xText, analysisinternal.Format(pass.Fset, T)))
+			}
+		}
+		edits = append(edits, analysis.TextEdit{
+			Pos:     clause.List[0].Pos(),
+			End:     clause.List[len(clause.List)-1].End(),
+			NewText: []byte(strings.Join(conds, " || ")),
+		})
+	}
+	return edits
+}
+
+// checkAsTarget reports a call to errors.As whose target is a pointer
+// variable that is declared without a value and never assigned.
+//
+// Targets of other invalid types are reported by the errorsas
+// analyzer, so they are not reported here.
+func checkAsTarget(pass *analysis.Pass, cur cursor.Cursor, call *ast.CallExpr) {
+	info := pass.TypesInfo
+	if !analysisinternal.IsFunctionNamed(typeutil.Callee(info, call), "errors", "As") || len(call.Args) != 2 {
+		return
+	}
+	id, ok := call.Args[1].(*ast.Ident)
+	if !ok {
+		return
+	}
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok {
+		return
+	}
+	ptr, ok := types.Unalias(v.Type()).(*types.Pointer)
+	if !ok || types.Identical(ptr.Elem(), errorType) {
+		return
+	}
+	_, isInterface := ptr.Elem().Underlying().(*types.Interface)
+	if !isInterface && !types.Implements(ptr.Elem(), errorType.Underlying().(*types.Interface)) {
+		return // reported by errorsas
+	}
+
+	// Find the body of the function that declares v.
+	var body *ast.BlockStmt
+	for cur := range cur.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+		switch n := cur.Node().(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body != nil && body.Pos() <= v.Pos() && v.Pos() < body.End() {
+			break
+		}
+		body = nil
+	}
+	if body == nil || !declaredNil(info, body, v) || assigned(info, body, v) {
+		return
+	}
+
+	if isInterface {
+		elem := types.TypeString(ptr.Elem(), types.RelativeTo(pass.Pkg))
+		pass.ReportRangef(id, "errors.As target %s is a nil pointer to interface %s, which causes a panic; declare %s as %s and pass &%s",
+			id.Name, elem, id.Name, elem, id.Name)
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:     id.Pos(),
+		End:     id.End(),
+		Message: fmt.Sprintf("errors.As target %s is a nil pointer, which causes a panic; pass &%s", id.Name, id.Name),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Pass &%s", id.Name),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     id.Pos(),
+				End:     id.Pos(),
+				NewText: []byte("&"),
+			}},
+		}},
+	})
+}
+
+// declaredNil reports whether v is declared within body by a var
+// declaration without a value.
+func declaredNil(info *types.Info, body *ast.BlockStmt, v *types.Var) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok && len(spec.Values) == 0 {
+			for _, name := range spec.Names {
+				if info.Defs[name] == v {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// assigned reports whether variable v is assigned, or its address
+// taken, anywhere within body.
+func assigned(info *types.Info, body *ast.BlockStmt, v *types.Var) bool {
+	is := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && info.Uses[id] == v
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				found = found || is(lhs)
+			}
+		case *ast.UnaryExpr:
+			found = found || n.Op == token.AND && is(n.X)
+		case *ast.RangeStmt:
+			found = found || n.Key != nil && is(n.Key) || n.Value != nil && is(n.Value)
+		}
+		return !found
+	})
+	return found
+}
+
+// headerEdit returns the edit that replaces the part of a switch
+// header after its init statement (if any), up to the opening brace
+// of its body, by text.
+func headerEdit(switchPos token.Pos, init ast.Stmt, body *ast.BlockStmt, text string) analysis.TextEdit {
+	start := switchPos + token.Pos(len("switch"))
+	if init != nil {
+		start = init.End()
+	}
+	return analysis.TextEdit{
+		Pos:     start,
+		End:     body.Lbrace,
+		NewText: []byte(text),
+	}
+}
+
+// zeroValue returns an expression for the zero value of type T.
+func zeroValue(pass *analysis.Pass, T ast.Expr) string {
+	text := analysisinternal.Format(pass.Fset, T)
+	switch pass.TypesInfo.TypeOf(T).Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return "(" + text + ")(nil)"
+	case *types.Struct:
+		return text + "{}"
+	}
+	return "*new(" + text + ")"
+}
+
+// hasNonNilCase reports whether any case of a switch body lists a
+// value or type other than nil.
+func hasNonNilCase(info *types.Info, body *ast.BlockStmt) bool {
+	for _, clause := range body.List {
+		for _, e := range clause.(*ast.CaseClause).List {
+			if !isNil(info, e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNil(info *types.Info, e ast.Expr) bool {
+	return info.Types[e].IsNil()
+}
+
+// uses reports whether n contains a reference to obj.
+func uses(info *types.Info, n ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && obj != nil && info.Uses[id] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,95 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+var _ = errors.New // force import of "errors" to avoid duplicate import edits
+
+type MyError struct{}
+
+func (*MyError) Error() string { return "" }
+
+type Temporary interface {
+	error
+	Temporary() bool
+}
+
+func typeSwitch(err error) {
+	switch err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *fs.PathError:
+		println("path")
+	case *MyError, Temporary:
+		println("mine or temporary")
+	case nil:
+		println("nil")
+	default:
+		println("other")
+	}
+}
+
+func typeSwitchBound(err error) {
+	switch e := err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *fs.PathError:
+		println(e.Path)
+	case *MyError:
+		println("mine")
+	}
+}
+
+func typeSwitchInit() {
+	switch err := fmt.Errorf(""); err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *MyError:
+	}
+}
+
+func typeSwitchNoFix(err error) {
+	switch e := err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *fs.PathError:
+		println(e.Path)
+	case *MyError:
+		println(e.Error())
+	}
+
+	switch err := err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *MyError, *fs.PathError:
+		println(err.Error())
+	}
+
+	switch err := err.(type) { // want "type switch on error err does not match wrapped errors; use errors.As"
+	case *fs.PathError:
+		println(err.Path)
+	}
+}
+
+func typeSwitchOK(err error, x any) {
+	switch err.(type) {
+	case nil:
+	}
+
+	switch x.(type) {
+	case *MyError:
+	}
+}
+
+var errLocal = fmt.Errorf("local")
+
+// Switches on error values are not reported, since sentinel errors
+// such as io.EOF are often, by contract, never wrapped.
+func valueSwitch(err, other error) {
+	switch err {
+	case nil:
+		println("nil")
+	case io.EOF, errLocal:
+		println("eof")
+	default:
+		println("other")
+	}
+
+	switch err {
+	case other:
+	}
+}
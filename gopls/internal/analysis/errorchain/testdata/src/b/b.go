@@ -0,0 +1,47 @@
+package b
+
+import "errors"
+
+type MyError struct{}
+
+func (*MyError) Error() string { return "" }
+
+type ValueError struct{}
+
+func (ValueError) Error() string { return "" }
+
+type Temporary interface {
+	Temporary() bool
+}
+
+func nilTargets(err error) {
+	var value *ValueError
+	if errors.As(err, value) { // want "errors.As target value is a nil pointer, which causes a panic; pass &value"
+	}
+
+	var temp *Temporary
+	if errors.As(err, temp) { // want "errors.As target temp is a nil pointer to interface Temporary, which causes a panic; declare temp as Temporary and pass &temp"
+	}
+}
+
+func validTargets(err error, param *ValueError) {
+	var mine *MyError
+	if errors.As(err, &mine) {
+	}
+
+	var value *ValueError
+	value = new(ValueError)
+	if errors.As(err, value) {
+	}
+
+	if errors.As(err, param) {
+	}
+
+	var e *error
+	if errors.As(err, e) { // reported by errorsas
+	}
+
+	var mine2 *MyError
+	if errors.As(err, mine2) { // reported by errorsas: MyError does not implement error
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package errorwrap defines an analyzer that checks that calls to
+// fmt.Errorf follow the error-wrapping style of their package.
+//
+// # Analyzer errorwrap
+//
+// errorwrap: check that fmt.Errorf calls follow the package's error-wrapping style
+//
+// When fmt.Errorf formats an error using the %w verb, the resulting
+// error wraps the original, so that errors.Is and errors.As can
+// inspect it, and it becomes part of the API of the function that
+// returns it. Formatting the error with %v (or %s) instead produces an
+// opaque error. Either style may be appropriate, but mixing them in a
+// single package is often a mistake.
+//
+// Rather than impose a global rule, the errorwrap analyzer infers the
+// style of each package by counting the errors formatted by calls to
+// fmt.Errorf with a literal format string. If most are formatted using
+// %w, it reports those formatted using %v or %s:
+//
+//	return fmt.Errorf("reading config: %v", err) // should be %w
+//
+// Conversely, if most are formatted using %v or %s, it reports those
+// formatted using %w. In both cases it offers a fix to change the
+// verb. If the package has no predominant style, no deviations are
+// reported.
+//
+// The analyzer also reports calls to fmt.Errorf whose format string
+// contains nothing but the error verb, such as
+//
+//	return fmt.Errorf("%w", err)
+//
+// since the resulting error adds no context to the original. For %w,
+// it offers a fix to use the original error directly; note that this
+// changes the result when err is nil.
+//
+// This analyzer is not enabled by default.
+package errorwrap
@@ -0,0 +1,212 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errorwrap
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/internal/analysisinternal"
+	internalastutil "golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/fmtstr"
+	"golang.org/x/tools/internal/versions"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorwrap",
+	Doc:      analysisinternal.MustExtractDoc(doc, "errorwrap"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/errorwrap",
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// An errorfCall is a call to fmt.Errorf with a literal format string.
+type errorfCall struct {
+	file    *ast.File
+	call    *ast.CallExpr
+	lit     *ast.BasicLit       // the format string
+	errOps  []*fmtstr.Operation // operations that format an error with %v, %s, or %w
+	noCtx   bool                // the format string contains nothing but its operations
+	wrapped int                 // number of errOps using %w
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if !analysisinternal.Imports(pass.Pkg, "fmt") {
+		return nil, nil
+	}
+
+	// Gather the calls to fmt.Errorf, and count the errors
+	// formatted using each style.
+	var (
+		calls          []*errorfCall
+		wraps, opaques int
+	)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	for curFile := range cursor.Root(inspect).Children() {
+		file := curFile.Node().(*ast.File)
+		if ast.IsGenerated(file) {
+			continue
+		}
+		for cur := range curFile.Preorder((*ast.CallExpr)(nil)) {
+			if c := parseErrorf(pass, file, cur.Node().(*ast.CallExpr)); c != nil {
+				calls = append(calls, c)
+				wraps += c.wrapped
+				opaques += len(c.errOps) - c.wrapped
+			}
+		}
+	}
+
+	// Infer the package's predominant style, if any.
+	var wrap, opaque bool
+	switch {
+	case wraps > opaques:
+		wrap = true
+	case opaques > wraps:
+		opaque = true
+	}
+
+	for _, c := range calls {
+		if c.noCtx {
+			checkNoContext(pass, c)
+			continue
+		}
+		for _, op := range c.errOps {
+			switch {
+			case wrap && op.Verb.Verb != 'w':
+				var fixes []analysis.SuggestedFix
+				// Before go1.20, a call may wrap at most one error.
+				if c.wrapped == 0 || versions.AtLeast(versions.FileVersion(pass.TypesInfo, c.file), versions.Go1_20) {
+					fixes = replaceVerb(c, op, 'w')
+				}
+				pass.Report(analysis.Diagnostic{
+					Pos:            c.call.Args[op.Verb.ArgIndex].Pos(),
+					End:            c.call.Args[op.Verb.ArgIndex].End(),
+					Message:        fmt.Sprintf("error is formatted using %%%c, but most errors in this package are wrapped using %%w", op.Verb.Verb),
+					SuggestedFixes: fixes,
+				})
+			case opaque && op.Verb.Verb == 'w':
+				pass.Report(analysis.Diagnostic{
+					Pos:            c.call.Args[op.Verb.ArgIndex].Pos(),
+					End:            c.call.Args[op.Verb.ArgIndex].End(),
+					Message:        "error is wrapped using %w, but most errors in this package are formatted using %v",
+					SuggestedFixes: replaceVerb(c, op, 'v'),
+				})
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseErrorf returns information about call if it is a call to
+// fmt.Errorf with a literal format string that formats at least one
+// error, or nil otherwise.
+func parseErrorf(pass *analysis.Pass, file *ast.File, call *ast.CallExpr) *errorfCall {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || !analysisinternal.IsFunctionNamed(fn, "fmt", "Errorf") || len(call.Args) < 2 || call.Ellipsis.IsValid() {
+		return nil
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+	ops, err := fmtstr.Parse(format, 0)
+	if err != nil {
+		return nil
+	}
+
+	c := &errorfCall{file: file, call: call, lit: lit}
+	for _, op := range ops {
+		switch op.Verb.Verb {
+		case 'v', 's', 'w':
+		default:
+			continue
+		}
+		if op.Flags != "" || op.Verb.ArgIndex >= len(call.Args) {
+			continue // e.g. %+v, which may have special meaning
+		}
+		t := pass.TypesInfo.TypeOf(call.Args[op.Verb.ArgIndex])
+		if t == nil || !types.Implements(t, errorType) {
+			continue
+		}
+		c.errOps = append(c.errOps, op)
+		if op.Verb.Verb == 'w' {
+			c.wrapped++
+		}
+	}
+	if len(c.errOps) == 0 {
+		return nil
+	}
+
+	// Does the format string contain anything but its operations?
+	var text strings.Builder
+	prev := 0
+	for _, op := range ops {
+		text.WriteString(format[prev:op.Range.Start])
+		prev = op.Range.End
+	}
+	text.WriteString(format[prev:])
+	c.noCtx = len(ops) == 1 && strings.TrimSpace(text.String()) == ""
+
+	return c
+}
+
+// checkNoContext reports a call to fmt.Errorf whose format string
+// consists only of the error verb, offering to replace a call that
+// wraps the error by the error itself.
+func checkNoContext(pass *analysis.Pass, c *errorfCall) {
+	op := c.errOps[0]
+	var fixes []analysis.SuggestedFix
+	if op.Verb.Verb == 'w' && len(c.call.Args) == 2 {
+		arg := c.call.Args[op.Verb.ArgIndex]
+		fixes = []analysis.SuggestedFix{{
+			Message: "Use the error directly",
+			TextEdits: []analysis.TextEdit{
+				{Pos: c.call.Pos(), End: arg.Pos()},
+				{Pos: arg.End(), End: c.call.End()},
+			},
+		}}
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:            c.call.Pos(),
+		End:            c.call.End(),
+		Message:        "fmt.Errorf call adds no context to the error",
+		SuggestedFixes: fixes,
+	})
+}
+
+// replaceVerb returns a fix that replaces the verb of op by verb.
+func replaceVerb(c *errorfCall, op *fmtstr.Operation, verb rune) []analysis.SuggestedFix {
+	start, end, err := internalastutil.RangeInStringLiteral(c.lit, op.Verb.Range.Start, op.Verb.Range.End)
+	if err != nil {
+		return nil
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Use %%%c", verb),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     start,
+			End:     end,
+			NewText: []byte(string(verb)),
+		}},
+	}}
+}
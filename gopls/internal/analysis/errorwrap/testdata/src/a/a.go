@@ -0,0 +1,49 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Most errors in this package are wrapped.
+
+func f1(err error) error {
+	return fmt.Errorf("f1: %w", err)
+}
+
+func f2(err error) error {
+	return fmt.Errorf("f2: %w", err)
+}
+
+func f3(name string, err *os.PathError) error {
+	return fmt.Errorf("f3 %s: %w", name, err)
+}
+
+func g1(err error) error {
+	return fmt.Errorf("g1: %v", err) // want `error is formatted using %v, but most errors in this package are wrapped using %w`
+}
+
+func g2(name string, err error) error {
+	return fmt.Errorf("g2 %q: %s", name, err) // want `error is formatted using %s, but most errors in this package are wrapped using %w`
+}
+
+func g3(err error) error {
+	return fmt.Errorf("g3: %+v", err) // flags are ignored
+}
+
+func g4(err1, err2 error) error {
+	return fmt.Errorf("g4: %w, %v", err1, err2) // want `error is formatted using %v, but most errors in this package are wrapped using %w`
+}
+
+func h(err error) error {
+	return fmt.Errorf("%w", err) // want `fmt.Errorf call adds no context to the error`
+}
+
+func nonError(x int) error {
+	return fmt.Errorf("x = %v", x)
+}
+
+func notErrorf(err error) error {
+	return errors.New(fmt.Sprintf("%v", err))
+}
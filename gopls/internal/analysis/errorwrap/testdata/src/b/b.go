@@ -0,0 +1,21 @@
+package b
+
+import "fmt"
+
+// Most errors in this package are opaque.
+
+func f1(err error) error {
+	return fmt.Errorf("f1: %v", err)
+}
+
+func f2(err error) error {
+	return fmt.Errorf("f2: %s", err)
+}
+
+func g(err error) error {
+	return fmt.Errorf("g: %w", err) // want `error is wrapped using %w, but most errors in this package are formatted using %v`
+}
+
+func h(err error) error {
+	return fmt.Errorf(" %v ", err) // want `fmt.Errorf call adds no context to the error`
+}
@@ -0,0 +1,13 @@
+package c
+
+import "fmt"
+
+// This package has no predominant style.
+
+func f(err error) error {
+	return fmt.Errorf("f: %w", err)
+}
+
+func g(err error) error {
+	return fmt.Errorf("g: %v", err)
+}
@@ -157,7 +157,7 @@ func SuggestedFix(fset *token.FileSet, start, end token.Pos, content []byte, fil
 
 	// Find reference to the type declaration of the struct being initialized.
 	typ = typeparams.Deref(typ)
-	tStruct, ok := typ.Underlying().(*types.Struct)
+	tStruct, ok := typeparams.CoreType(typ).(*types.Struct)
 	if !ok {
 		return nil, nil, fmt.Errorf("%s is not a (pointer to) struct type",
 			types.TypeString(typ, typesinternal.NameRelativeTo(pkg)))
@@ -233,6 +233,17 @@ func SuggestedFix(fset *token.FileSet, start, end token.Pos, content []byte, fil
 		return nil, nil, fmt.Errorf("no elements to fill")
 	}
 
+	fix, err := literalFix(fset, content, file, expr, elts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fset, fix, nil
+}
+
+// literalFix returns a fix that replaces the braces of the composite
+// literal expr, and everything between them, by the elements elts,
+// preserving the comments of expr and the indentation of its line.
+func literalFix(fset *token.FileSet, content []byte, file *ast.File, expr *ast.CompositeLit, elts []ast.Expr) (*analysis.SuggestedFix, error) {
 	// Find the line on which the composite literal is declared.
 	split := bytes.Split(content, []byte("\n"))
 	lineNumber := safetoken.StartPosition(fset, expr.Lbrace).Line
@@ -265,7 +276,7 @@ func SuggestedFix(fset *token.FileSet, start, end token.Pos, content []byte, fil
 		// Print the current elt with comments
 		eltcomments := fcmap.Filter(elt).Comments()
 		if err := format.Node(&buf, fset, &printer.CommentedNode{Node: elt, Comments: eltcomments}); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		buf.WriteString(",")
 
@@ -291,7 +302,7 @@ func SuggestedFix(fset *token.FileSet, start, end token.Pos, content []byte, fil
 	buf.WriteString("}")
 	formatted, err := format.Source(buf.Bytes())
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	sug := indent(formatted, whitespace)
@@ -299,7 +310,7 @@ func SuggestedFix(fset *token.FileSet, start, end token.Pos, content []byte, fil
 	idx := bytes.IndexByte(sug, '{') // cannot fail
 	sug = sug[idx:]
 
-	return fset, &analysis.SuggestedFix{
+	return &analysis.SuggestedFix{
 		TextEdits: []analysis.TextEdit{
 			{
 				Pos:     expr.Lbrace,
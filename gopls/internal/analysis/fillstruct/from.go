@@ -0,0 +1,210 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fillstruct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/internal/typeparams"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// DiagnoseFrom computes diagnostics for filling the innermost struct
+// literal enclosing the [start, end) interval of file from a variable
+// in scope, in the manner of a copy constructor: each missing field
+// of the literal is initialized from the same-named field of the
+// variable, converting it if necessary.
+//
+// There is one diagnostic for each suitable variable, innermost
+// first. Unlike [Diagnose], each diagnostic's fix contains its edits.
+func DiagnoseFrom(fset *token.FileSet, file *ast.File, content []byte, start, end token.Pos, pkg *types.Package, info *types.Info) []analysis.Diagnostic {
+	path, _ := astutil.PathEnclosingInterval(file, start, end)
+	var expr *ast.CompositeLit
+	for _, n := range path {
+		if lit, ok := n.(*ast.CompositeLit); ok {
+			expr = lit
+			break
+		}
+	}
+	if expr == nil {
+		return nil
+	}
+	typ := info.TypeOf(expr)
+	if typ == nil {
+		return nil
+	}
+	tStruct, ok := typeparams.CoreType(typeparams.Deref(typ)).(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	// Preserve the prefilled fields, which must all be keyed.
+	prefilled := make(map[string]bool)
+	var elts []ast.Expr
+	for _, e := range expr.Elts {
+		kv, ok := e.(*ast.KeyValueExpr)
+		if !ok {
+			return nil // positional elements
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			prefilled[key.Name] = true
+		}
+		elts = append(elts, kv)
+	}
+	var missing []*types.Var
+	for i := 0; i < tStruct.NumFields(); i++ {
+		field := tStruct.Field(i)
+		if accessible(field, pkg) && !prefilled[field.Name()] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	qual := typesinternal.FileQualifier(file, pkg)
+	var diags []analysis.Diagnostic
+	for _, v := range candidateVars(pkg, expr.Pos()) {
+		vStruct, ok := typeparams.CoreType(typeparams.Deref(v.Type())).(*types.Struct)
+		if !ok {
+			continue
+		}
+		kvs := fromFields(ast.NewIdent(v.Name()), vStruct, missing, pkg, qual)
+		if kvs == nil {
+			continue
+		}
+		fix, err := literalFix(fset, content, file, expr, append(elts[:len(elts):len(elts)], kvs...))
+		if err != nil {
+			continue
+		}
+		fix.Message = fmt.Sprintf("Fill from %s", v.Name())
+		diags = append(diags, analysis.Diagnostic{
+			Message:        fix.Message,
+			Pos:            expr.Pos(),
+			End:            expr.End(),
+			SuggestedFixes: []analysis.SuggestedFix{*fix},
+		})
+	}
+	return diags
+}
+
+// candidateVars returns the variables in scope at pos, other than
+// fields and blanks, from the innermost scope outwards, in name order
+// within each scope.
+func candidateVars(pkg *types.Package, pos token.Pos) []*types.Var {
+	innermost := pkg.Scope().Innermost(pos)
+	if innermost == nil {
+		return nil
+	}
+	var vars []*types.Var
+	seen := make(map[string]bool)
+	for scope := innermost; scope != nil && scope != types.Universe; scope = scope.Parent() {
+		names := scope.Names() // sorted
+		for _, name := range names {
+			if seen[name] || name == "_" {
+				continue
+			}
+			// The declaration may not yet be in scope at pos
+			// (as for x in x := T{...}), in which case the
+			// name denotes whatever it denotes outside.
+			if s, _ := innermost.LookupParent(name, pos); s != scope {
+				continue
+			}
+			seen[name] = true
+			if v, ok := scope.Lookup(name).(*types.Var); ok && !v.IsField() {
+				vars = append(vars, v)
+			}
+		}
+	}
+	return vars
+}
+
+// fromFields returns key/value elements that initialize each of the
+// fields from the same-named field of the struct x, of type src,
+// or nil if some field has no suitable counterpart.
+func fromFields(x ast.Expr, src *types.Struct, fields []*types.Var, pkg *types.Package, qual types.Qualifier) []ast.Expr {
+	byName := make(map[string]*types.Var)
+	for i := 0; i < src.NumFields(); i++ {
+		if f := src.Field(i); accessible(f, pkg) {
+			byName[f.Name()] = f
+		}
+	}
+	var kvs []ast.Expr
+	for _, field := range fields {
+		f, ok := byName[field.Name()]
+		if !ok {
+			return nil
+		}
+		value := ValueFrom(&ast.SelectorExpr{X: x, Sel: ast.NewIdent(f.Name())}, f.Type(), field.Type(), pkg, qual)
+		if value == nil {
+			return nil
+		}
+		kvs = append(kvs, &ast.KeyValueExpr{Key: ast.NewIdent(field.Name()), Value: value})
+	}
+	return kvs
+}
+
+// ValueFrom returns an expression of type dst that is derived from
+// the expression x of type src, or nil if there is none.
+//
+// The expression is x itself if it is assignable to dst; otherwise a
+// conversion dst(x) if src is convertible to dst (except from an
+// integer to a string, which is rarely intended); otherwise, if both
+// are struct types, a dst literal filled from the fields of x.
+func ValueFrom(x ast.Expr, src, dst types.Type, pkg *types.Package, qual types.Qualifier) ast.Expr {
+	switch {
+	case types.AssignableTo(src, dst):
+		return x
+
+	case types.ConvertibleTo(src, dst) && !isIntegerToString(src, dst):
+		return &ast.CallExpr{
+			Fun:  convertFun(typesinternal.TypeExpr(dst, qual)),
+			Args: []ast.Expr{x},
+		}
+	}
+
+	srcStruct, ok1 := src.Underlying().(*types.Struct)
+	dstStruct, ok2 := dst.Underlying().(*types.Struct)
+	if ok1 && ok2 {
+		var fields []*types.Var
+		for i := 0; i < dstStruct.NumFields(); i++ {
+			if field := dstStruct.Field(i); accessible(field, pkg) {
+				fields = append(fields, field)
+			}
+		}
+		if kvs := fromFields(x, srcStruct, fields, pkg, qual); kvs != nil {
+			return &ast.CompositeLit{Type: typesinternal.TypeExpr(dst, qual), Elts: kvs}
+		}
+	}
+	return nil
+}
+
+// convertFun returns the function operand of a conversion to the
+// type denoted by t, parenthesized if necessary.
+func convertFun(t ast.Expr) ast.Expr {
+	switch t.(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.ChanType:
+		return &ast.ParenExpr{X: t}
+	}
+	return t
+}
+
+// isIntegerToString reports whether src is an integer type and dst a
+// string type.
+func isIntegerToString(src, dst types.Type) bool {
+	s, ok1 := src.Underlying().(*types.Basic)
+	d, ok2 := dst.Underlying().(*types.Basic)
+	return ok1 && ok2 && s.Info()&types.IsInteger != 0 && d.Info()&types.IsString != 0
+}
+
+// accessible reports whether the field is accessible from pkg.
+func accessible(field *types.Var, pkg *types.Package) bool {
+	return field.Pkg() == nil || field.Pkg() == pkg || field.Exported()
+}
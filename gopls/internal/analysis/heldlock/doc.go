@@ -0,0 +1,31 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package heldlock defines an analyzer that checks for blocking
+// operations performed while a mutex is held.
+//
+// # Analyzer heldlock
+//
+// heldlock: check for blocking operations while a mutex is held
+//
+// The heldlock analyzer reports operations that may block indefinitely
+// or for a long time, such as channel sends and receives, blocking
+// select statements, calls to time.Sleep or sync.WaitGroup.Wait, and
+// network calls through net.Conn, net.Listener or net/http, that are
+// performed while a sync.Mutex or sync.RWMutex locked by the same
+// function may still be held on some path:
+//
+//	mu.Lock()
+//	defer mu.Unlock()
+//	resp, err := http.Get(url) // http.Get called while mu is locked
+//
+// Holding a lock while blocking makes every other goroutine that needs
+// the lock wait too, which is a common cause of latency collapse and
+// of deadlock. The diagnostic indicates where the lock was acquired.
+//
+// The analysis considers each function separately: it does not know
+// about locks held by callers, nor about operations that block within
+// callees other than those listed above. A lock released by a deferred
+// call is considered held until the function returns.
+package heldlock
@@ -0,0 +1,264 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heldlock
+
+import (
+	_ "embed"
+	"fmt"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/typeparams"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "heldlock",
+	Doc:      analysisinternal.MustExtractDoc(doc, "heldlock"),
+	Requires: []*analysis.Analyzer{buildssa.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/heldlock",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ssainput := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	for _, fn := range ssainput.SrcFuncs {
+		runFunc(pass, fn)
+	}
+	return nil, nil
+}
+
+// A lockset maps each mutex that may be held, identified by its
+// description (such as "s.mu"), to the position of a call that locked it.
+type lockset map[string]token.Pos
+
+// runFunc reports the blocking operations of fn that may be performed
+// while a mutex locked by fn is held.
+//
+// It computes, by a forward dataflow analysis over the control-flow
+// graph, the set of mutexes that may be held on entry to each block:
+// the union of the sets on exit from its predecessors. Lock and RLock
+// calls add a mutex to the set, and Unlock and RUnlock calls remove it.
+func runFunc(pass *analysis.Pass, fn *ssa.Function) {
+	if len(fn.Blocks) == 0 {
+		return // external function
+	}
+
+	// transfer applies the effects of the instructions of b to the
+	// set of held mutexes, calling visit (if non-nil) for each
+	// instruction with the set that holds before it.
+	transfer := func(b *ssa.BasicBlock, held lockset, visit func(ssa.Instruction, lockset)) lockset {
+		held = maps.Clone(held)
+		for _, instr := range b.Instrs {
+			if visit != nil {
+				visit(instr, held)
+			}
+			if call, ok := instr.(*ssa.Call); ok {
+				if mu, lock := lockOp(&call.Call); mu != nil {
+					if lock {
+						if _, ok := held[describe(mu)]; !ok {
+							held[describe(mu)] = call.Pos()
+						}
+					} else {
+						delete(held, describe(mu))
+					}
+				}
+			}
+		}
+		return held
+	}
+
+	out := make([]lockset, len(fn.Blocks)) // set on exit from each block
+
+	// entry returns the set of mutexes that may be held on entry to b.
+	entry := func(b *ssa.BasicBlock) lockset {
+		held := make(lockset)
+		for _, pred := range b.Preds {
+			for mu, pos := range out[pred.Index] {
+				if prev, ok := held[mu]; !ok || pos < prev {
+					held[mu] = pos
+				}
+			}
+		}
+		return held
+	}
+
+	// Iterate to a fixed point. The sets only grow,
+	// and are bounded by the set of mutexes locked by fn.
+	for changed := true; changed; {
+		changed = false
+		for _, b := range fn.Blocks {
+			held := transfer(b, entry(b), nil)
+			if out[b.Index] == nil || !maps.Equal(held, out[b.Index]) {
+				out[b.Index] = held
+				changed = true
+			}
+		}
+	}
+
+	// Report blocking operations while a mutex is held.
+	for _, b := range fn.Blocks {
+		transfer(b, entry(b), func(instr ssa.Instruction, held lockset) {
+			if len(held) == 0 || !instr.Pos().IsValid() {
+				return
+			}
+			op := blockingOp(instr)
+			if op == "" {
+				return
+			}
+			mus := slices.Sorted(maps.Keys(held))
+			var related []analysis.RelatedInformation
+			for _, mu := range mus {
+				related = append(related, analysis.RelatedInformation{
+					Pos:     held[mu],
+					Message: fmt.Sprintf("%s locked here", mu),
+				})
+			}
+			verb := "is"
+			if len(mus) > 1 {
+				verb = "are"
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:     instr.Pos(),
+				Message: fmt.Sprintf("%s while %s %s locked", op, strings.Join(mus, " and "), verb),
+				Related: related,
+			})
+		})
+	}
+}
+
+// lockOp reports whether the call locks or unlocks a sync.Mutex or
+// sync.RWMutex, and if so returns the (pointer to the) mutex.
+// TryLock is not considered a lock operation.
+func lockOp(call *ssa.CallCommon) (mu ssa.Value, lock bool) {
+	fn, ok := calleeFunc(call)
+	if !ok || len(call.Args) == 0 {
+		return nil, false
+	}
+	switch fn.FullName() {
+	case "(*sync.Mutex).Lock", "(*sync.RWMutex).Lock", "(*sync.RWMutex).RLock":
+		return call.Args[0], true
+	case "(*sync.Mutex).Unlock", "(*sync.RWMutex).Unlock", "(*sync.RWMutex).RUnlock":
+		return call.Args[0], false
+	}
+	return nil, false
+}
+
+// blockingFuncs is the set of functions and methods, identified by
+// their full names, whose calls may block for a long time.
+//
+// (*sync.Cond).Wait is deliberately absent, as it releases its lock
+// while waiting.
+var blockingFuncs = map[string]bool{
+	"time.Sleep":                        true,
+	"(*sync.WaitGroup).Wait":            true,
+	"net.Dial":                          true,
+	"net.DialTimeout":                   true,
+	"(*net.Dialer).Dial":                true,
+	"(*net.Dialer).DialContext":         true,
+	"(net.Conn).Read":                   true,
+	"(net.Conn).Write":                  true,
+	"(*net.conn).Read":                  true, // e.g. (*net.TCPConn).Read
+	"(*net.conn).Write":                 true,
+	"(net.PacketConn).ReadFrom":         true,
+	"(net.PacketConn).WriteTo":          true,
+	"(net.Listener).Accept":             true,
+	"(*net.TCPListener).Accept":         true,
+	"(*net.TCPListener).AcceptTCP":      true,
+	"(*net.UnixListener).Accept":        true,
+	"(*net.UnixListener).AcceptUnix":    true,
+	"net/http.Get":                      true,
+	"net/http.Head":                     true,
+	"net/http.Post":                     true,
+	"net/http.PostForm":                 true,
+	"(*net/http.Client).Do":             true,
+	"(*net/http.Client).Get":            true,
+	"(*net/http.Client).Head":           true,
+	"(*net/http.Client).Post":           true,
+	"(*net/http.Client).PostForm":       true,
+	"(net/http.RoundTripper).RoundTrip": true,
+	"(*net/http.Transport).RoundTrip":   true,
+}
+
+// blockingOp returns a description of the blocking operation performed
+// by instr, such as "channel send", or "" if it does not block.
+func blockingOp(instr ssa.Instruction) string {
+	switch instr := instr.(type) {
+	case *ssa.Send:
+		return "channel send"
+	case *ssa.UnOp:
+		if instr.Op == token.ARROW {
+			return "channel receive"
+		}
+	case *ssa.Select:
+		if instr.Blocking {
+			return "blocking select"
+		}
+	case *ssa.Call:
+		if fn, ok := calleeFunc(&instr.Call); ok && blockingFuncs[fn.FullName()] {
+			return funcName(fn) + " called"
+		}
+	}
+	return ""
+}
+
+// calleeFunc returns the function or (abstract or concrete) method
+// called by call, if it is statically known.
+func calleeFunc(call *ssa.CallCommon) (*types.Func, bool) {
+	if call.IsInvoke() {
+		return call.Method, true
+	}
+	if callee := call.StaticCallee(); callee != nil {
+		fn, ok := callee.Object().(*types.Func)
+		return fn, ok
+	}
+	return nil, false
+}
+
+// funcName returns the name of a function or method qualified by the
+// name of its package, such as "http.Get" or "net.Conn.Read".
+func funcName(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Signature().Recv(); recv != nil {
+		if named, ok := typeparams.Deref(recv.Type()).(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	if fn.Pkg() != nil {
+		name = fn.Pkg().Name() + "." + name
+	}
+	return name
+}
+
+// describe returns a description of the address of a mutex, such as
+// "mu" or "s.mu", that identifies it within a function.
+func describe(v ssa.Value) string {
+	switch v := v.(type) {
+	case *ssa.FieldAddr:
+		if st, ok := typeparams.CoreType(typeparams.MustDeref(v.X.Type())).(*types.Struct); ok {
+			return describe(v.X) + "." + st.Field(v.Field).Name()
+		}
+	case *ssa.IndexAddr:
+		return describe(v.X) + "[...]"
+	case *ssa.UnOp:
+		if v.Op == token.MUL {
+			return describe(v.X)
+		}
+	case *ssa.Alloc:
+		if v.Comment != "" {
+			return v.Comment // name of the variable
+		}
+	}
+	return v.Name()
+}
@@ -0,0 +1,130 @@
+package a
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type server struct {
+	mu    sync.Mutex
+	rw    sync.RWMutex
+	cond  *sync.Cond
+	ch    chan int
+	conns []net.Conn
+}
+
+var global sync.Mutex
+
+func (s *server) sleep() {
+	s.mu.Lock()
+	time.Sleep(time.Second) // want `time.Sleep called while s.mu is locked`
+	s.mu.Unlock()
+	time.Sleep(time.Second) // ok: unlocked
+}
+
+func (s *server) deferred(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	http.Get(url) // want `http.Get called while s.mu is locked`
+}
+
+func (s *server) send(v int) {
+	s.rw.RLock()
+	s.ch <- v // want `channel send while s.rw is locked`
+	s.rw.RUnlock()
+}
+
+func (s *server) receive() int {
+	global.Lock()
+	v := <-s.ch // want `channel receive while global is locked`
+	global.Unlock()
+	return v
+}
+
+func (s *server) selects() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select { // want `blocking select while s.mu is locked`
+	case v := <-s.ch:
+		_ = v
+	case <-time.After(time.Second):
+	}
+	select { // ok: non-blocking
+	case s.ch <- 1:
+	default:
+	}
+}
+
+func (s *server) somePath(cond bool) {
+	s.mu.Lock()
+	if cond {
+		s.mu.Unlock()
+	}
+	time.Sleep(time.Second) // want `time.Sleep called while s.mu is locked`
+	if !cond {
+		s.mu.Unlock()
+	}
+}
+
+func (s *server) allPaths(cond bool) {
+	s.mu.Lock()
+	if cond {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	time.Sleep(time.Second) // ok: unlocked on all paths
+}
+
+func (s *server) loop() {
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second) // want `time.Sleep called while s.mu is locked`
+		s.mu.Lock()
+	}
+	s.mu.Unlock()
+}
+
+func (s *server) conn(buf []byte) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	mu.Lock()
+	s.conns[0].Read(buf) // want `net.Conn.Read called while mu is locked`
+	wg.Wait()            // want `sync.WaitGroup.Wait called while mu is locked`
+	mu.Unlock()
+}
+
+func (s *server) two(client *http.Client, req *http.Request) {
+	s.mu.Lock()
+	global.Lock()
+	client.Do(req) // want `http.Client.Do called while global and s.mu are locked`
+	global.Unlock()
+	s.mu.Unlock()
+}
+
+func (s *server) wait() {
+	s.mu.Lock()
+	s.cond.Wait() // ok: Wait releases the lock
+	s.mu.Unlock()
+}
+
+func (s *server) goroutine() {
+	s.mu.Lock()
+	go time.Sleep(time.Second) // ok: not blocking
+	go func() {
+		s.ch <- 1 // ok: in another goroutine
+	}()
+	s.mu.Unlock()
+}
+
+type embedded struct {
+	sync.Mutex
+	ch chan int
+}
+
+func (e *embedded) send() {
+	e.Lock()
+	e.ch <- 1 // want `channel send while e.Mutex is locked`
+	e.Unlock()
+}
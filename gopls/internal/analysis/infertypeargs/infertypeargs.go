@@ -54,89 +54,98 @@ func diagnose(fset *token.FileSet, inspect *inspector.Inspector, start, end toke
 	nodeFilter := []ast.Node{(*ast.CallExpr)(nil)}
 	inspect.Preorder(nodeFilter, func(node ast.Node) {
 		call := node.(*ast.CallExpr)
-		x, lbrack, indices, rbrack := typeparams.UnpackIndexExpr(call.Fun)
-		ident := calledIdent(x)
-		if ident == nil || len(indices) == 0 {
-			return // no explicit args, nothing to do
-		}
-
 		if (start.IsValid() && call.End() < start) || (end.IsValid() && call.Pos() > end) {
 			return // non-overlapping
 		}
-
-		// Confirm that instantiation actually occurred at this ident.
-		idata, ok := info.Instances[ident]
-		if !ok {
-			return // something went wrong, but fail open
-		}
-		instance := idata.Type
-
-		// Start removing argument expressions from the right, and check if we can
-		// still infer the call expression.
-		required := len(indices) // number of type expressions that are required
-		for i := len(indices) - 1; i >= 0; i-- {
-			var fun ast.Expr
-			if i == 0 {
-				// No longer an index expression: just use the parameterized operand.
-				fun = x
-			} else {
-				fun = typeparams.PackIndexExpr(x, lbrack, indices[:i], indices[i-1].End())
-			}
-			newCall := &ast.CallExpr{
-				Fun:      fun,
-				Lparen:   call.Lparen,
-				Args:     call.Args,
-				Ellipsis: call.Ellipsis,
-				Rparen:   call.Rparen,
-			}
-			info := &types.Info{
-				Instances:    make(map[*ast.Ident]types.Instance),
-				FileVersions: make(map[*ast.File]string),
-			}
-			if err := types.CheckExpr(fset, pkg, call.Pos(), newCall, info); err != nil {
-				// Most likely inference failed.
-				break
-			}
-			newIData := info.Instances[ident]
-			newInstance := newIData.Type
-			if !types.Identical(instance, newInstance) {
-				// The inferred result type does not match the original result type, so
-				// this simplification is not valid.
-				break
-			}
-			required = i
-		}
-		if required < len(indices) {
-			var s, e token.Pos
-			var edit analysis.TextEdit
-			if required == 0 {
-				s, e = lbrack, rbrack+1 // erase the entire index
-				edit = analysis.TextEdit{Pos: s, End: e}
-			} else {
-				s = indices[required].Pos()
-				e = rbrack
-				//  erase from end of last arg to include last comma & white-spaces
-				edit = analysis.TextEdit{Pos: indices[required-1].End(), End: e}
-			}
+		if diag, ok := DiagnoseCall(fset, pkg, info, call); ok {
 			// Recheck that our (narrower) fixes overlap with the requested range.
-			if (start.IsValid() && e < start) || (end.IsValid() && s > end) {
+			if (start.IsValid() && diag.End < start) || (end.IsValid() && diag.Pos > end) {
 				return // non-overlapping
 			}
-			diags = append(diags, analysis.Diagnostic{
-				Pos:     s,
-				End:     e,
-				Message: "unnecessary type arguments",
-				SuggestedFixes: []analysis.SuggestedFix{{
-					Message:   "Simplify type arguments",
-					TextEdits: []analysis.TextEdit{edit},
-				}},
-			})
+			diags = append(diags, diag)
 		}
 	})
 
 	return diags
 }
 
+// DiagnoseCall reports a diagnostic describing the type arguments of
+// the call that may be omitted because inference would supply them,
+// if any. Its suggested fix removes them.
+func DiagnoseCall(fset *token.FileSet, pkg *types.Package, info *types.Info, call *ast.CallExpr) (analysis.Diagnostic, bool) {
+	x, lbrack, indices, rbrack := typeparams.UnpackIndexExpr(call.Fun)
+	ident := calledIdent(x)
+	if ident == nil || len(indices) == 0 {
+		return analysis.Diagnostic{}, false // no explicit args, nothing to do
+	}
+
+	// Confirm that instantiation actually occurred at this ident.
+	idata, ok := info.Instances[ident]
+	if !ok {
+		return analysis.Diagnostic{}, false // something went wrong, but fail open
+	}
+	instance := idata.Type
+
+	// Start removing argument expressions from the right, and check if we can
+	// still infer the call expression.
+	required := len(indices) // number of type expressions that are required
+	for i := len(indices) - 1; i >= 0; i-- {
+		var fun ast.Expr
+		if i == 0 {
+			// No longer an index expression: just use the parameterized operand.
+			fun = x
+		} else {
+			fun = typeparams.PackIndexExpr(x, lbrack, indices[:i], indices[i-1].End())
+		}
+		newCall := &ast.CallExpr{
+			Fun:      fun,
+			Lparen:   call.Lparen,
+			Args:     call.Args,
+			Ellipsis: call.Ellipsis,
+			Rparen:   call.Rparen,
+		}
+		info := &types.Info{
+			Instances:    make(map[*ast.Ident]types.Instance),
+			FileVersions: make(map[*ast.File]string),
+		}
+		if err := types.CheckExpr(fset, pkg, call.Pos(), newCall, info); err != nil {
+			// Most likely inference failed.
+			break
+		}
+		newIData := info.Instances[ident]
+		newInstance := newIData.Type
+		if !types.Identical(instance, newInstance) {
+			// The inferred result type does not match the original result type, so
+			// this simplification is not valid.
+			break
+		}
+		required = i
+	}
+	if required == len(indices) {
+		return analysis.Diagnostic{}, false
+	}
+	var s, e token.Pos
+	var edit analysis.TextEdit
+	if required == 0 {
+		s, e = lbrack, rbrack+1 // erase the entire index
+		edit = analysis.TextEdit{Pos: s, End: e}
+	} else {
+		s = indices[required].Pos()
+		e = rbrack
+		//  erase from end of last arg to include last comma & white-spaces
+		edit = analysis.TextEdit{Pos: indices[required-1].End(), End: e}
+	}
+	return analysis.Diagnostic{
+		Pos:     s,
+		End:     e,
+		Message: "unnecessary type arguments",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Simplify type arguments",
+			TextEdits: []analysis.TextEdit{edit},
+		}},
+	}, true
+}
+
 func calledIdent(x ast.Expr) *ast.Ident {
 	switch x := x.(type) {
 	case *ast.Ident:
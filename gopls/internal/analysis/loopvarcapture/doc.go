@@ -0,0 +1,44 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package loopvarcapture defines an analyzer that checks for function
+// literals run by go and defer statements that capture loop variables
+// in files whose Go version predates go1.22.
+//
+// # Analyzer loopvarcapture
+//
+// loopvarcapture: check for goroutines and deferred calls that capture loop variables
+//
+// Before go1.22, each loop declared a single variable that was shared
+// by all its iterations. A function literal that refers to such a
+// variable and is run by a go statement within the loop may observe
+// the updates made by later iterations, which is a data race; one run
+// by a defer statement observes only the final value of the variable:
+//
+//	for _, v := range list {
+//		go func() {
+//			use(v) // incorrect, and a data race
+//		}()
+//	}
+//
+// Unlike the loopclosure analyzer, which reports only the go and defer
+// statements that are the last in the loop body, loopvarcapture
+// reports them wherever they appear in the loop body, and offers a fix
+// to declare a copy of the variable at the start of each iteration:
+//
+//	for _, v := range list {
+//		v := v
+//		go func() {
+//			use(v)
+//		}()
+//	}
+//
+// No fix is offered if the loop body assigns to the variable, as the
+// assignment would then update the copy.
+//
+// Files whose Go version is go1.22 or later are not checked, as each
+// iteration of a loop declares new variables. The modernize analyzer
+// reports copies such as v := v that are no longer needed in those
+// files.
+package loopvarcapture
@@ -0,0 +1,221 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loopvarcapture
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/versions"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "loopvarcapture",
+	Doc:      analysisinternal.MustExtractDoc(doc, "loopvarcapture"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/loopvarcapture",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	for curFile := range cursor.Root(inspect).Children() {
+		file := curFile.Node().(*ast.File)
+		if ast.IsGenerated(file) || !versions.Before(versions.FileVersion(pass.TypesInfo, file), versions.Go1_22) {
+			continue
+		}
+		content, err := pass.ReadFile(pass.Fset.File(file.Pos()).Name())
+		if err != nil {
+			continue
+		}
+		for cur := range curFile.Preorder((*ast.GoStmt)(nil), (*ast.DeferStmt)(nil)) {
+			checkStmt(pass, file, content, cur)
+		}
+	}
+	return nil, nil
+}
+
+// checkStmt checks a go or defer statement whose function is a
+// function literal, reporting the variables of the enclosing loops
+// that the literal refers to.
+func checkStmt(pass *analysis.Pass, file *ast.File, content []byte, cur cursor.Cursor) {
+	info := pass.TypesInfo
+
+	var (
+		call *ast.CallExpr
+		what string
+	)
+	switch stmt := cur.Node().(type) {
+	case *ast.GoStmt:
+		call, what = stmt.Call, "goroutine"
+	case *ast.DeferStmt:
+		call, what = stmt.Call, "deferred call"
+	}
+	lit, ok := ast.Unparen(call.Fun).(*ast.FuncLit)
+	if !ok {
+		return
+	}
+
+	// Gather the variables of the loops that enclose the statement
+	// within the same function, mapping each to its loop's body.
+	loopBody := make(map[*types.Var]*ast.BlockStmt)
+ancestors:
+	for curAnc := range cur.Ancestors((*ast.ForStmt)(nil), (*ast.RangeStmt)(nil), (*ast.FuncLit)(nil), (*ast.FuncDecl)(nil)) {
+		switch n := curAnc.Node().(type) {
+		case *ast.ForStmt:
+			if init, ok := n.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+				for _, lhs := range init.Lhs {
+					addLoopVar(info, loopBody, lhs, n.Body)
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				addLoopVar(info, loopBody, n.Key, n.Body)
+				addLoopVar(info, loopBody, n.Value, n.Body)
+			}
+		default:
+			break ancestors // function boundary
+		}
+	}
+	if len(loopBody) == 0 {
+		return
+	}
+
+	// Find the loop variables captured by the literal,
+	// in order of their first reference.
+	var (
+		captured []*types.Var
+		seen     = make(map[*types.Var]bool)
+	)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if v, ok := info.Uses[id].(*types.Var); ok && loopBody[v] != nil && !seen[v] {
+				seen[v] = true
+				captured = append(captured, v)
+			}
+		}
+		return true
+	})
+	if len(captured) == 0 {
+		return
+	}
+
+	var names []string
+	for _, v := range captured {
+		names = append(names, v.Name())
+	}
+	noun := "variable"
+	if len(names) > 1 {
+		noun = "variables"
+	}
+	diag := analysis.Diagnostic{
+		Pos:     lit.Pos(),
+		End:     lit.Type.End(),
+		Message: fmt.Sprintf("%s captures loop %s %s, shared by all iterations", what, noun, strings.Join(names, " and ")),
+	}
+
+	// Offer to copy the variables at the start of each loop body,
+	// unless a body assigns to one of them.
+	var (
+		edits  []analysis.TextEdit
+		bodies []*ast.BlockStmt
+		vars   = make(map[*ast.BlockStmt][]string)
+	)
+	for _, v := range captured {
+		body := loopBody[v]
+		if _, ok := vars[body]; !ok {
+			bodies = append(bodies, body)
+		}
+		vars[body] = append(vars[body], v.Name())
+		if assigns(info, body, v) {
+			bodies = nil
+			break
+		}
+	}
+	for _, body := range bodies {
+		names := strings.Join(vars[body], ", ")
+		first := body.List[0].Pos()
+		edits = append(edits, analysis.TextEdit{
+			Pos:     first,
+			End:     first,
+			NewText: fmt.Appendf(nil, "%s := %s%s", names, names, separator(file, content, first)),
+		})
+	}
+	if len(edits) > 0 {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Declare a per-iteration copy of %s", strings.Join(names, " and ")),
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diag)
+}
+
+// addLoopVar records the variable declared by the loop variable
+// expression e, if any.
+func addLoopVar(info *types.Info, loopBody map[*types.Var]*ast.BlockStmt, e ast.Expr, body *ast.BlockStmt) {
+	if id, ok := e.(*ast.Ident); ok {
+		if v, ok := info.Defs[id].(*types.Var); ok {
+			loopBody[v] = body
+		}
+	}
+}
+
+// assigns reports whether the body assigns to v.
+func assigns(info *types.Info, body *ast.BlockStmt, v *types.Var) bool {
+	isVar := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && info.Uses[id] == v
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if isVar(lhs) {
+					found = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if isVar(n.X) {
+				found = true
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN && (isVar(n.Key) || isVar(n.Value)) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// separator returns the text that separates a statement inserted
+// before the one at pos from it: a line break followed by the
+// indentation of pos, if pos is the first on its line, or else a
+// semicolon.
+func separator(file *ast.File, content []byte, pos token.Pos) string {
+	start := int(pos - file.FileStart)
+	i := start
+	for i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && content[i-1] == '\n' {
+		return "\n" + string(content[i:start])
+	}
+	return "; "
+}
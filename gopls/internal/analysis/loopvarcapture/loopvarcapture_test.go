@@ -0,0 +1,20 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loopvarcapture_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/gopls/internal/analysis/loopvarcapture"
+	"golang.org/x/tools/internal/testfiles"
+)
+
+func Test(t *testing.T) {
+	dir := testfiles.ExtractTxtarFileToTmp(t, filepath.Join(analysistest.TestData(), "loopvarcapture.txtar"))
+	analysistest.RunWithSuggestedFixes(t, filepath.Join(dir, "a"), loopvarcapture.Analyzer, "example.com/a")
+	analysistest.Run(t, filepath.Join(dir, "b"), loopvarcapture.Analyzer, "example.com/b")
+}
@@ -27,5 +27,7 @@
 //     go1.24;
 //   - replacing omitempty by omitzero on structs, added in go 1.24;
 //   - replacing append(s[:i], s[i+1]...) by slices.Delete(s, i, i+1),
-//     added in go1.21
+//     added in go1.21;
+//   - removing x := x copies of range loop variables, which are
+//     unneeded since go1.22 gave each iteration its own variables.
 package modernize
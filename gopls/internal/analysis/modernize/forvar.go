@@ -0,0 +1,106 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modernize
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// The forvar pass removes the redundant copies of range loop
+// variables that were needed before go1.22, when loop variables
+// were shared by all iterations:
+//
+//	for _, x := range s {
+//		x := x
+//		go func() { use(x) }()
+//	}
+//
+// =>
+//
+//	for _, x := range s {
+//		go func() { use(x) }()
+//	}
+//
+// Only the copies among the leading statements of the loop body are
+// removed. Copies of the variables of three-clause loops are left
+// alone, as the body may update the copy without affecting the loop.
+func forvar(pass *analysis.Pass) {
+	info := pass.TypesInfo
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	for curFile := range filesUsing(inspect, info, "go1.22") {
+		file := curFile.Node().(*ast.File)
+		for curLoop := range curFile.Preorder((*ast.RangeStmt)(nil)) {
+			loop := curLoop.Node().(*ast.RangeStmt)
+			if loop.Tok != token.DEFINE {
+				continue
+			}
+
+			// isLoopVar reports whether id is a variable declared by the loop.
+			isLoopVar := func(id *ast.Ident) bool {
+				obj := info.Uses[id]
+				for _, e := range []ast.Expr{loop.Key, loop.Value} {
+					if v, ok := e.(*ast.Ident); ok && v.Name != "_" && info.Defs[v] == obj {
+						return true
+					}
+				}
+				return false
+			}
+
+			for i, stmt := range loop.Body.List {
+				if !isSelfCopy(stmt, isLoopVar) {
+					break
+				}
+				// Delete the statement and the space that follows it,
+				// unless that space contains comments.
+				end := stmt.End()
+				if i+1 < len(loop.Body.List) {
+					end = loop.Body.List[i+1].Pos()
+					for _, cg := range file.Comments {
+						if stmt.End() <= cg.Pos() && cg.Pos() < end {
+							end = stmt.End()
+							break
+						}
+					}
+				}
+				pass.Report(analysis.Diagnostic{
+					Pos:      stmt.Pos(),
+					End:      stmt.End(),
+					Category: "forvar",
+					Message:  "copying variable is unneeded",
+					SuggestedFixes: []analysis.SuggestedFix{{
+						Message: "Remove unneeded redeclaration",
+						TextEdits: []analysis.TextEdit{{
+							Pos: stmt.Pos(),
+							End: end,
+						}},
+					}},
+				})
+			}
+		}
+	}
+}
+
+// isSelfCopy reports whether stmt is of the form "x := x" or
+// "x, y := x, y", where each right operand is a loop variable.
+func isSelfCopy(stmt ast.Stmt, isLoopVar func(*ast.Ident) bool) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+		return false
+	}
+	for i, lhs := range assign.Lhs {
+		lhs, ok1 := lhs.(*ast.Ident)
+		rhs, ok2 := assign.Rhs[i].(*ast.Ident)
+		if !ok1 || !ok2 || lhs.Name != rhs.Name || !isLoopVar(rhs) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package forvar
+
+func _(m map[int]string, s []int) {
+	for i, v := range s {
+		i := i // want "copying variable is unneeded"
+		v := v // want "copying variable is unneeded"
+		go func() { println(i, v) }()
+	}
+	for k, v := range m {
+		k, v := k, v // want "copying variable is unneeded"
+		go func() { println(k, v) }()
+	}
+	for _, v := range s {
+		v := v // want "copying variable is unneeded"
+		// a comment
+		go func() { println(v) }()
+	}
+	for _, v := range s {
+		println(v)
+		v := v // nope: not a leading statement
+		go func() { println(v) }()
+	}
+	for i := 0; i < len(s); i++ {
+		i := i // nope: three-clause loop
+		go func() { println(i) }()
+	}
+	var x int
+	for x = range s {
+		x := x // nope: the loop does not declare x
+		go func() { println(x) }()
+	}
+	for _, v := range s {
+		w := v // nope: not a copy of the same name
+		go func() { println(w) }()
+	}
+}
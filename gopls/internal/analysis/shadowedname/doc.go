@@ -0,0 +1,34 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package shadowedname defines an analyzer that checks for local
+// variables that accidentally shadow an imported package or a
+// built-in function that is needed later.
+//
+// # Analyzer shadowedname
+//
+// shadowedname: check for variables that shadow a needed package or built-in
+//
+// A local variable whose name is that of an imported package hides the
+// package for the rest of its scope, so that a later reference to a
+// member of the package fails to compile with a confusing error:
+//
+//	func f(rawURL string) error {
+//		url, err := url.Parse(rawURL)
+//		...
+//		_ = url.QueryEscape(s) // error: url.QueryEscape undefined (type *url.URL has no field or method QueryEscape)
+//	}
+//
+// Similarly, a variable named after a built-in function such as len
+// or new, or a predeclared type such as string, makes a later call or
+// conversion using that name fail to compile:
+//
+//	len := 3
+//	...
+//	n := len(s) // error: invalid operation: cannot call non-function len
+//
+// The shadowedname analyzer reports such variables, and offers a fix
+// to rename the variable so that the later references refer to the
+// package or built-in again.
+package shadowedname
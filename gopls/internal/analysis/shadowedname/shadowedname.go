@@ -0,0 +1,204 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shadowedname
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/astutil/edge"
+	"golang.org/x/tools/internal/typeparams"
+)
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:             "shadowedname",
+	Doc:              analysisinternal.MustExtractDoc(doc, "shadowedname"),
+	Requires:         []*analysis.Analyzer{inspect.Analyzer},
+	Run:              run,
+	RunDespiteErrors: true, // the needed references are compile errors
+	URL:              "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/shadowedname",
+}
+
+// A shadowing records the references to a local variable that
+// shadows a package or built-in.
+type shadowing struct {
+	v        *types.Var
+	shadowed types.Object // the package or built-in that v shadows
+	refs     []*ast.Ident // all references to v
+	needed   []*ast.Ident // references that need the shadowed object
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	info := pass.TypesInfo
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	for curFile := range cursor.Root(inspect).Children() {
+		file := curFile.Node().(*ast.File)
+		if ast.IsGenerated(file) {
+			continue
+		}
+
+		// Gather the references to each local variable that
+		// shadows a package or built-in, in order.
+		var shadowings []*shadowing
+		byVar := make(map[*types.Var]*shadowing)
+		for cur := range curFile.Preorder((*ast.Ident)(nil)) {
+			id := cur.Node().(*ast.Ident)
+			v, ok := info.Uses[id].(*types.Var)
+			if !ok || v.IsField() || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+				continue // not a local variable
+			}
+			s, ok := byVar[v]
+			if !ok {
+				// The variable hides the object that its name
+				// would otherwise denote in its scope.
+				if v.Parent().Parent() != nil {
+					_, obj := v.Parent().Parent().LookupParent(v.Name(), v.Pos())
+					switch obj := obj.(type) {
+					case *types.PkgName:
+						s = &shadowing{v: v, shadowed: obj}
+					case *types.Builtin, *types.TypeName:
+						if obj.Parent() == types.Universe {
+							s = &shadowing{v: v, shadowed: obj}
+						}
+					}
+				}
+				byVar[v] = s
+				if s != nil {
+					shadowings = append(shadowings, s)
+				}
+			}
+			if s == nil {
+				continue
+			}
+			s.refs = append(s.refs, id)
+			if needsShadowed(info, cur, s) {
+				s.needed = append(s.needed, id)
+			}
+		}
+
+		for _, s := range shadowings {
+			if len(s.needed) > 0 {
+				report(pass, s)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// needsShadowed reports whether the reference to s.v at cur is
+// invalid for the variable but would be valid for the shadowed object.
+func needsShadowed(info *types.Info, cur cursor.Cursor, s *shadowing) bool {
+	switch obj := s.shadowed.(type) {
+	case *types.PkgName:
+		// v.Sel, where Sel is not a field or method of v
+		// but is an exported member of the package.
+		if ek, _ := cur.Edge(); ek == edge.SelectorExpr_X {
+			sel := cur.Parent().Node().(*ast.SelectorExpr)
+			if _, ok := info.Selections[sel]; !ok && sel.Sel.IsExported() {
+				return obj.Imported().Scope().Lookup(sel.Sel.Name) != nil
+			}
+		}
+
+	case *types.Builtin, *types.TypeName:
+		// v(...), where v is not a function.
+		if ek, _ := cur.Edge(); ek == edge.CallExpr_Fun {
+			_, isFunc := typeparams.CoreType(s.v.Type()).(*types.Signature)
+			return !isFunc && s.v.Type() != types.Typ[types.Invalid]
+		}
+	}
+	return false
+}
+
+// report reports the variable of s, with a fix to rename it and all
+// its references except those that need the shadowed object.
+func report(pass *analysis.Pass, s *shadowing) {
+	var what string
+	switch obj := s.shadowed.(type) {
+	case *types.PkgName:
+		what = fmt.Sprintf("imported package %s", obj.Name())
+	case *types.Builtin:
+		what = fmt.Sprintf("built-in function %s", obj.Name())
+	case *types.TypeName:
+		what = fmt.Sprintf("predeclared type %s", obj.Name())
+	}
+	needed := s.needed[0]
+	diag := analysis.Diagnostic{
+		Pos:     s.v.Pos(),
+		End:     s.v.Pos() + token.Pos(len(s.v.Name())),
+		Message: fmt.Sprintf("variable %s shadows the %s, which is needed later", s.v.Name(), what),
+		Related: []analysis.RelatedInformation{{
+			Pos:     needed.Pos(),
+			End:     needed.End(),
+			Message: fmt.Sprintf("%s is needed here", what),
+		}},
+	}
+
+	// Offer the fix only if we can find the declaration.
+	if decl := declIdent(pass.TypesInfo, s.v); decl != nil {
+		newName := freshName(s.v.Parent(), s.v.Name())
+		edits := []analysis.TextEdit{{Pos: decl.Pos(), End: decl.End(), NewText: []byte(newName)}}
+	refs:
+		for _, ref := range s.refs {
+			for _, n := range s.needed {
+				if ref == n {
+					continue refs
+				}
+			}
+			edits = append(edits, analysis.TextEdit{Pos: ref.Pos(), End: ref.End(), NewText: []byte(newName)})
+		}
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Rename variable %s to %s", s.v.Name(), newName),
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diag)
+}
+
+// declIdent returns the identifier that declares v, or nil if there
+// is none, as for the implicit variables of a type switch.
+func declIdent(info *types.Info, v *types.Var) *ast.Ident {
+	for id, obj := range info.Defs {
+		if obj == v {
+			return id
+		}
+	}
+	return nil
+}
+
+// freshName returns a name formed by appending a number to base that
+// is not declared in the specified scope, any enclosing scope, or any
+// scope it encloses.
+func freshName(scope *types.Scope, base string) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s%d", base, i)
+		if _, obj := scope.LookupParent(name, token.NoPos); obj == nil && !declaredWithin(scope, name) {
+			return name
+		}
+	}
+}
+
+// declaredWithin reports whether name is declared in any scope
+// enclosed by scope.
+func declaredWithin(scope *types.Scope, name string) bool {
+	for i := range scope.NumChildren() {
+		child := scope.Child(i)
+		if child.Lookup(name) != nil || declaredWithin(child, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package a
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+func pkg(rawURL, s string) (string, error) {
+	url, err := url.Parse(rawURL) // want "variable url shadows the imported package url, which is needed later"
+	if err != nil {
+		return "", err
+	}
+	return url.String() + url.QueryEscape(s), nil
+}
+
+func pkgUnneeded(rawURL string) string {
+	url, _ := url.Parse(rawURL) // ok: the package is not needed later
+	return url.Path
+}
+
+func param(filepath string) string { // want "variable filepath shadows the imported package filepath, which is needed later"
+	return filepath + filepath.Ext(filepath)
+}
+
+func conflict(strings []string) string { // want "variable strings shadows the imported package strings, which is needed later"
+	strings1 := ""
+	return strings1 + strings.Join(strings, ",")
+}
+
+func builtin(s []int) int {
+	len := 3 // want "variable len shadows the built-in function len, which is needed later"
+	return len + len(s)
+}
+
+func builtinUnneeded(s []int) int {
+	new := len(s) // ok: new is never called
+	return new
+}
+
+func builtinFunc() int {
+	max := func(x, y int) int { return x } // ok: the variable is a function
+	return max(1, 2)
+}
+
+func conversion(b []byte) string {
+	string := "prefix" // want "variable string shadows the predeclared type string, which is needed later"
+	return string + string(b)
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package unuseddecl defines an analyzer that checks for unused
+// package-level constants, variables, and types.
+//
+// # Analyzer unuseddecl
+//
+// unuseddecl: check for unused constants, variables, and types
+//
+// The unuseddecl analyzer reports package-level constants, variables,
+// and types that are never referenced outside of their own
+// declaration. It is the counterpart of unusedfunc for declarations
+// other than functions and methods.
+//
+// A constant, variable, or type is considered unused if it is
+// unexported and not referenced (except within its own declaration,
+// or, for a type, those of its methods). Only declarations of a single
+// name are reported. Constants in a group that uses iota or implicit
+// repetition are never reported, since deleting one would change the
+// values of the others, nor are variables whose initializers may have
+// side effects.
+//
+// Each diagnostic offers a fix to delete the unused declaration (for
+// a type, along with its methods).
+//
+// Like unusedfunc, this analyzer relies on the gopls analysis driver
+// analyzing only the widest package for each file, so it may produce
+// incorrect results in other drivers.
+package unuseddecl
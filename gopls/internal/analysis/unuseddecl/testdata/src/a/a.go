@@ -0,0 +1,69 @@
+package a
+
+// Test of unused constants, variables, and types.
+
+func main() {
+	_ = liveConst
+	_ = liveVar
+	var _ liveType
+}
+
+// -- constants --
+
+const Exported = 1
+
+const liveConst = 1
+
+// deadConst has a doc comment.
+const deadConst = 2 // want `const "deadConst" is unused`
+
+const (
+	deadInGroup = 1 // want `const "deadInGroup" is unused`
+	usedInGroup = 2
+	userInGroup = usedInGroup + 1 // want `const "userInGroup" is unused`
+)
+
+const (
+	enum0 = iota // deleting one would change the others
+	enum1
+)
+
+const selfRef = len("selfRef") // want `const "selfRef" is unused`
+
+// -- variables --
+
+var liveVar int
+
+var deadVar = []int{1, 2, 3} // want `var "deadVar" is unused`
+
+var (
+	deadGroupVar int // want `var "deadGroupVar" is unused`
+	otherVar     int // want `var "otherVar" is unused`
+)
+
+var effectfulVar = compute() // initializer may have side effects
+
+var conversionVar = int64(1) // want `var "conversionVar" is unused`
+
+var multiA, multiB = 1, 2 // not yet supported
+
+func compute() int { return 0 }
+
+//go:linkname linknamed
+var linknamed int
+
+// -- types --
+
+type liveType int
+
+type deadType struct{ next *deadType } // want `type "deadType" is unused`
+
+type deadWithMethods int // want `type "deadWithMethods" is unused`
+
+func (d deadWithMethods) String() string { return "" }
+
+func (d *deadWithMethods) Set(x deadWithMethods) { *d = x }
+
+type embedded int
+
+type embedder struct{ embedded } // want `type "embedder" is unused`
@@ -0,0 +1,273 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unuseddecl
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// Assumptions
+//
+// Like unusedfunc, this analyzer depends on the invariant of the
+// gopls analysis driver that only the "widest" package for a given
+// file is analyzed, which allows it to make "closed world"
+// assumptions about the target package.
+//
+// A constant, variable, or type is unreferenced if it is unexported
+// and never referenced except within its own declaration. For a
+// type, references from the declarations of its methods (including
+// their receivers) are also ignored: if the type itself is never
+// referenced, no value of it can exist, so its methods are dead too.
+//
+// To keep the deletion fixes simple and safe, we consider only specs
+// that declare a single name. We skip constants in a group that uses
+// iota or implicit repetition, as deleting one would change the
+// values of the others, and variables whose initializers may have
+// side effects.
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name: "unuseddecl",
+	Doc:  analysisinternal.MustExtractDoc(doc, "unuseddecl"),
+	Run:  run,
+	URL:  "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/unuseddecl",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	candidates := make(map[types.Object]*candidate)
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue // skip generated files
+		}
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok == token.IMPORT || hasLinkname(decl.Doc) {
+				continue
+			}
+			if decl.Tok == token.CONST && !independentConsts(decl) {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				var id *ast.Ident
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					if len(spec.Names) != 1 || hasLinkname(spec.Doc) {
+						continue
+					}
+					if decl.Tok == token.VAR && !sideEffectFree(pass.TypesInfo, spec.Values) {
+						continue
+					}
+					id = spec.Names[0]
+				case *ast.TypeSpec:
+					id = spec.Name
+				}
+				// Exported and blank declarations are exempt from diagnostics.
+				if id.IsExported() || id.Name == "_" {
+					continue
+				}
+				if obj := pass.TypesInfo.Defs[id]; obj != nil {
+					candidates[obj] = &candidate{decl: decl, spec: spec}
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Associate each method with the declaration of its receiver type.
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv != nil {
+				fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+				if _, named := typesinternal.ReceiverNamed(fn.Signature().Recv()); named != nil {
+					if c, ok := candidates[named.Obj()]; ok {
+						c.methods = append(c.methods, decl)
+					}
+				}
+			}
+		}
+	}
+
+	// Scan for uses of each symbol, ignoring uses within its
+	// own declaration or those of its methods.
+	for id, obj := range pass.TypesInfo.Uses {
+		c, ok := candidates[obj]
+		if !ok {
+			continue
+		}
+		if c.encloses(id.Pos()) {
+			continue
+		}
+		delete(candidates, obj) // symbol is referenced
+	}
+
+	// Report the remaining unreferenced symbols.
+	for obj, c := range candidates {
+		var noun string
+		switch obj.(type) {
+		case *types.Const:
+			noun = "const"
+		case *types.Var:
+			noun = "var"
+		case *types.TypeName:
+			noun = "type"
+		}
+		title := fmt.Sprintf("Delete %s %q", noun, obj.Name())
+		if len(c.methods) > 0 {
+			title += " and its methods"
+		}
+		edits := []analysis.TextEdit{c.deleteSpec()}
+		for _, method := range c.methods {
+			edits = append(edits, deleteDecl(method))
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     obj.Pos(),
+			End:     obj.Pos() + token.Pos(len(obj.Name())),
+			Message: fmt.Sprintf("%s %q is unused", noun, obj.Name()),
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   title,
+				TextEdits: edits,
+			}},
+		})
+	}
+	return nil, nil
+}
+
+// A candidate is an unexported package-level constant, variable, or
+// type that may be deleted if unreferenced.
+type candidate struct {
+	decl    *ast.GenDecl
+	spec    ast.Spec
+	methods []*ast.FuncDecl // methods of a type
+}
+
+// encloses reports whether pos lies within the candidate's own
+// declaration, or that of one of its methods.
+func (c *candidate) encloses(pos token.Pos) bool {
+	if c.spec.Pos() <= pos && pos < c.spec.End() {
+		return true
+	}
+	for _, method := range c.methods {
+		if method.Pos() <= pos && pos < method.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteSpec returns an edit that deletes the candidate's spec,
+// including its doc comment, or the entire declaration if it has
+// only one spec.
+func (c *candidate) deleteSpec() analysis.TextEdit {
+	var doc, comment *ast.CommentGroup
+	switch spec := c.spec.(type) {
+	case *ast.ValueSpec:
+		doc, comment = spec.Doc, spec.Comment
+	case *ast.TypeSpec:
+		doc, comment = spec.Doc, spec.Comment
+	}
+	pos, end := c.spec.Pos(), c.spec.End()
+	if len(c.decl.Specs) == 1 {
+		pos, end = c.decl.Pos(), c.decl.End()
+		doc = c.decl.Doc
+	}
+	if doc != nil {
+		pos = doc.Pos()
+	}
+	if comment != nil && comment.End() > end {
+		end = comment.End() // trailing line comment
+	}
+	return analysis.TextEdit{Pos: pos, End: end}
+}
+
+// independentConsts reports whether each constant declared by decl
+// may be deleted without changing the values of the others: that is,
+// no spec relies on iota or on implicit repetition of the previous
+// spec's expressions.
+func independentConsts(decl *ast.GenDecl) bool {
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		if len(spec.Values) == 0 {
+			return false // implicit repetition
+		}
+		for _, value := range spec.Values {
+			usesIota := false
+			ast.Inspect(value, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+					usesIota = true
+				}
+				return !usesIota
+			})
+			if usesIota {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sideEffectFree reports whether evaluation of the expressions
+// certainly has no side effects. It conservatively assumes that
+// any call other than a conversion, and any channel receive, may
+// have effects.
+func sideEffectFree(info *types.Info, exprs []ast.Expr) bool {
+	ok := true
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				if tv, found := info.Types[n.Fun]; !found || !tv.IsType() {
+					ok = false
+				}
+			case *ast.UnaryExpr:
+				if n.Op == token.ARROW {
+					ok = false
+				}
+			case *ast.FuncLit:
+				return false // body is not evaluated
+			}
+			return ok
+		})
+	}
+	return ok
+}
+
+// hasLinkname reports whether the doc comment contains a
+// //go:linkname directive.
+func hasLinkname(doc *ast.CommentGroup) bool {
+	if doc != nil {
+		for _, comment := range doc.List {
+			if strings.HasPrefix(comment.Text, "//go:linkname ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deleteDecl returns an edit that deletes the declaration,
+// including its doc comment.
+func deleteDecl(decl *ast.FuncDecl) analysis.TextEdit {
+	pos := decl.Pos() // start of func decl or associated comment
+	if decl.Doc != nil {
+		pos = decl.Doc.Pos()
+	}
+	return analysis.TextEdit{Pos: pos, End: decl.End()}
+}
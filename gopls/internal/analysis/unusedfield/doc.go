@@ -0,0 +1,33 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package unusedfield defines an analyzer that checks for unused
+// struct fields.
+//
+// # Analyzer unusedfield
+//
+// unusedfield: check for unused struct fields
+//
+// The unusedfield analyzer reports unexported fields of
+// package-level struct types that are never read or written outside
+// their own declaration, and offers a fix to delete them.
+//
+// Because a field may be accessed by means other than a selector
+// expression or a keyed composite literal, the analyzer is
+// conservative. It does not report any field of a struct type that:
+//
+//   - has a field with a struct tag, such as a serialization tag;
+//   - may be inspected using reflection, because a value of the type
+//     (or one containing it) is converted to an interface;
+//   - is converted to or from another struct type;
+//   - appears in an unkeyed composite literal; or
+//   - is declared in a package that imports "unsafe".
+//
+// Embedded fields, blank fields, and fields declared together with
+// others (as in "x, y int") are never reported.
+//
+// Like unusedfunc, this analyzer relies on the gopls analysis driver
+// analyzing only the widest package for each file, so it may produce
+// incorrect results in other drivers.
+package unusedfield
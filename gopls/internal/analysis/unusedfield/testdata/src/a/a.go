@@ -0,0 +1,85 @@
+package a
+
+import "fmt"
+
+type basic struct {
+	used   int
+	unused int // want `field "unused" is unused`
+
+	// doc is a documented field.
+	doc string // want `field "doc" is unused`
+
+	Exported int
+	_        int
+	x, y     int
+	written  bool
+}
+
+func _(b *basic) int {
+	b.written = true
+	return b.used
+}
+
+type keyed struct {
+	key   int
+	other int // want `field "other" is unused`
+}
+
+var _ = keyed{key: 1}
+
+type generic[T any] struct {
+	elem  T
+	extra T // want `field "extra" is unused`
+}
+
+func _(g generic[int]) int { return g.elem }
+
+// Structs with tags may be used for serialization.
+type tagged struct {
+	name string `json:"name"`
+	age  int
+}
+
+// Structs converted to interfaces may be inspected by reflection.
+type printed struct {
+	inner inner
+}
+
+type inner struct {
+	hidden int
+}
+
+func _(p printed) {
+	fmt.Println(p)
+}
+
+type returned struct{ hidden int }
+
+func _() any { return &returned{} }
+
+type assigned struct{ hidden int }
+
+func _() {
+	var x any
+	x = []assigned{}
+	_ = x
+}
+
+type element struct{ hidden int }
+
+var _ = []any{element{}}
+
+// Unkeyed literals initialize every field.
+type unkeyed struct {
+	a, b int
+	c    int
+}
+
+var _ = unkeyed{1, 2, 3}
+
+// Conversions between struct types require matching fields.
+type converted struct{ hidden int }
+
+type otherConverted struct{ hidden int }
+
+var _ = converted(otherConverted{})
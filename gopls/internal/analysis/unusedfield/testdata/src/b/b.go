@@ -0,0 +1,10 @@
+package b
+
+import "unsafe"
+
+// Fields may be accessed by pointer arithmetic.
+type layout struct {
+	hidden int
+}
+
+var _ = unsafe.Sizeof(layout{})
@@ -0,0 +1,362 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unusedfield
+
+import (
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/astutil/cursor"
+	"golang.org/x/tools/internal/astutil/edge"
+)
+
+// Assumptions
+//
+// Like unusedfunc, this analyzer makes a "closed world" assumption
+// about the package: since only unexported fields are candidates, and
+// each package has a private namespace for unexported identifiers,
+// a field not referenced within the package can be referenced only
+// through reflection, through unsafe pointer arithmetic, or
+// implicitly by an operation on the struct as a whole (a conversion
+// or unkeyed literal). We exclude struct types that may be subject
+// to any of these.
+
+//go:embed doc.go
+var doc string
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "unusedfield",
+	Doc:      analysisinternal.MustExtractDoc(doc, "unusedfield"),
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+	URL:      "https://pkg.go.dev/golang.org/x/tools/gopls/internal/analysis/unusedfield",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() == "unsafe" {
+			return nil, nil // fields may be accessed by pointer arithmetic
+		}
+	}
+
+	// Gather candidate fields of package-level struct types.
+	var (
+		structs = make(map[*types.TypeName][]*types.Var)
+		fields  = make(map[*types.Var]*ast.Field)
+	)
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue // skip generated files
+		}
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				styp, ok := spec.Type.(*ast.StructType)
+				if !ok || hasTag(styp) {
+					continue
+				}
+				tname, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				for _, field := range styp.Fields.List {
+					if len(field.Names) != 1 {
+						continue // embedded, or declared with other fields
+					}
+					id := field.Names[0]
+					if id.IsExported() || id.Name == "_" {
+						continue
+					}
+					if v, ok := pass.TypesInfo.Defs[id].(*types.Var); ok {
+						structs[tname] = append(structs[tname], v)
+						fields[v] = field
+					}
+				}
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	// Exclude struct types whose fields may be accessed implicitly.
+	excluded := make(map[*types.TypeName]bool)
+	var exclude func(t types.Type)
+	exclude = func(t types.Type) {
+		switch t := t.(type) {
+		case *types.Named:
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				exclude(t.TypeArgs().At(i))
+			}
+			obj := t.Origin().Obj()
+			if obj.Pkg() != pass.Pkg || excluded[obj] {
+				return
+			}
+			excluded[obj] = true
+			exclude(t.Underlying())
+		case *types.Alias:
+			exclude(types.Unalias(t))
+		case *types.Pointer:
+			exclude(t.Elem())
+		case *types.Slice:
+			exclude(t.Elem())
+		case *types.Array:
+			exclude(t.Elem())
+		case *types.Map:
+			exclude(t.Key())
+			exclude(t.Elem())
+		case *types.Chan:
+			exclude(t.Elem())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				exclude(t.Field(i).Type())
+			}
+		}
+	}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	info := pass.TypesInfo
+	for cur := range cursor.Root(inspect).Preorder() {
+		switch n := cur.Node().(type) {
+		case *ast.CompositeLit:
+			// An unkeyed literal initializes every field.
+			if len(n.Elts) > 0 {
+				if _, ok := n.Elts[0].(*ast.KeyValueExpr); !ok {
+					excludeNamed(excluded, pass.Pkg, info.TypeOf(n))
+				}
+			}
+
+		case *ast.CallExpr:
+			// A conversion between struct types requires
+			// their fields to match.
+			if tv := info.Types[n.Fun]; tv.IsType() && len(n.Args) == 1 {
+				if isStruct(tv.Type) {
+					excludeNamed(excluded, pass.Pkg, tv.Type)
+					excludeNamed(excluded, pass.Pkg, info.TypeOf(n.Args[0]))
+				}
+			}
+		}
+
+		// A value converted to an interface may be inspected by reflection.
+		if e, ok := cur.Node().(ast.Expr); ok {
+			if t := expectedType(info, cur); t != nil && types.IsInterface(t) {
+				if tv, ok := info.Types[e]; ok && !types.IsInterface(tv.Type) {
+					exclude(tv.Type)
+				}
+			}
+		}
+	}
+
+	// Discard fields that are referenced. A field of an instantiated
+	// type is a distinct object whose origin is the declared field.
+	for _, obj := range info.Uses {
+		if v, ok := obj.(*types.Var); ok && v.IsField() {
+			delete(fields, v.Origin())
+		}
+	}
+
+	// Report the remaining unreferenced fields.
+	for tname, vars := range structs {
+		if excluded[tname] {
+			continue
+		}
+		for _, v := range vars {
+			field, ok := fields[v]
+			if !ok {
+				continue
+			}
+			pos := field.Pos()
+			if field.Doc != nil {
+				pos = field.Doc.Pos()
+			}
+			end := field.End()
+			if field.Comment != nil {
+				end = field.Comment.End() // trailing line comment
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:     v.Pos(),
+				End:     v.Pos() + token.Pos(len(v.Name())),
+				Message: fmt.Sprintf("field %q is unused", v.Name()),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Delete field %q", v.Name()),
+					TextEdits: []analysis.TextEdit{{Pos: pos, End: end}},
+				}},
+			})
+		}
+	}
+	return nil, nil
+}
+
+// hasTag reports whether any field of the struct type has a tag.
+func hasTag(styp *ast.StructType) bool {
+	for _, field := range styp.Fields.List {
+		if field.Tag != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isStruct reports whether t is a struct, or a pointer to one.
+func isStruct(t types.Type) bool {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// excludeNamed marks as excluded the named type of pkg denoted by t,
+// or by its element type if t is a pointer.
+func excludeNamed(excluded map[*types.TypeName]bool, pkg *types.Package, t types.Type) {
+	if t == nil {
+		return
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pkg() == pkg {
+		excluded[named.Origin().Obj()] = true
+	}
+}
+
+// expectedType returns the type to which the value of the expression
+// at cur is implicitly or explicitly converted by its context, or nil
+// if it is not converted or the type is unknown.
+func expectedType(info *types.Info, cur cursor.Cursor) types.Type {
+	ek, idx := cur.Edge()
+	parent := cur.Parent()
+	switch ek {
+	case edge.CallExpr_Args:
+		call := parent.Node().(*ast.CallExpr)
+		tv := info.Types[call.Fun]
+		if tv.IsType() {
+			return tv.Type // conversion
+		}
+		if tv.Type == nil {
+			return nil
+		}
+		sig, ok := tv.Type.Underlying().(*types.Signature)
+		if !ok {
+			return nil
+		}
+		params := sig.Params()
+		if sig.Variadic() && idx >= params.Len()-1 {
+			last := params.At(params.Len() - 1).Type()
+			if call.Ellipsis.IsValid() {
+				return last
+			}
+			if s, ok := last.Underlying().(*types.Slice); ok {
+				return s.Elem()
+			}
+			return nil
+		}
+		if idx < params.Len() {
+			return params.At(idx).Type()
+		}
+
+	case edge.AssignStmt_Rhs:
+		assign := parent.Node().(*ast.AssignStmt)
+		if len(assign.Lhs) == len(assign.Rhs) {
+			return info.TypeOf(assign.Lhs[idx])
+		}
+
+	case edge.ValueSpec_Values:
+		spec := parent.Node().(*ast.ValueSpec)
+		if len(spec.Names) == len(spec.Values) {
+			return info.TypeOf(spec.Names[idx])
+		}
+
+	case edge.ReturnStmt_Results:
+		for fn := range cur.Ancestors((*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)) {
+			var t types.Type
+			switch fn := fn.Node().(type) {
+			case *ast.FuncDecl:
+				if obj := info.Defs[fn.Name]; obj != nil {
+					t = obj.Type()
+				}
+			case *ast.FuncLit:
+				t = info.TypeOf(fn)
+			}
+			if sig, ok := t.(*types.Signature); ok && idx < sig.Results().Len() {
+				return sig.Results().At(idx).Type()
+			}
+			return nil
+		}
+
+	case edge.SendStmt_Value:
+		send := parent.Node().(*ast.SendStmt)
+		if ch, ok := deref(info.TypeOf(send.Chan)).(*types.Chan); ok {
+			return ch.Elem()
+		}
+
+	case edge.CompositeLit_Elts:
+		lit := parent.Node().(*ast.CompositeLit)
+		switch t := deref(info.TypeOf(lit)).(type) {
+		case *types.Struct:
+			if idx < t.NumFields() {
+				return t.Field(idx).Type()
+			}
+		case *types.Slice:
+			return t.Elem()
+		case *types.Array:
+			return t.Elem()
+		case *types.Map:
+			return t.Elem()
+		}
+
+	case edge.KeyValueExpr_Value:
+		kv := parent.Node().(*ast.KeyValueExpr)
+		lit, ok := parent.Parent().Node().(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		switch t := deref(info.TypeOf(lit)).(type) {
+		case *types.Struct:
+			if id, ok := kv.Key.(*ast.Ident); ok {
+				if field, ok := info.Uses[id].(*types.Var); ok {
+					return field.Type()
+				}
+			}
+		case *types.Slice:
+			return t.Elem()
+		case *types.Array:
+			return t.Elem()
+		case *types.Map:
+			return t.Elem()
+		}
+
+	case edge.KeyValueExpr_Key:
+		if lit, ok := parent.Parent().Node().(*ast.CompositeLit); ok {
+			if m, ok := deref(info.TypeOf(lit)).(*types.Map); ok {
+				return m.Key()
+			}
+		}
+	}
+	return nil
+}
+
+// deref returns the underlying type of t, or of its element type if
+// t is a pointer. It returns nil if t is nil.
+func deref(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	t = t.Underlying()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem().Underlying()
+	}
+	return t
+}
@@ -20,6 +20,16 @@
 // that of any method of an interface type declared within the same
 // package.
 //
+// Functions without a body, and functions referenced by the assembly
+// files of the package, are implemented in or used by assembly, and
+// are never reported.
+//
+// Each diagnostic offers a fix to delete the unused declaration. If
+// deleting it would cause other functions to become unused--for
+// example, helper functions called only by the deleted one--a second
+// fix deletes them too, even if they are declared in other files of
+// the package.
+//
 // The tool may report a false positive for a declaration of an
 // unexported function that is referenced from another package using
 // the go:linkname mechanism, if the declaration's doc comment does
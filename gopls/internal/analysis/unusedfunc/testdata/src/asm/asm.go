@@ -0,0 +1,11 @@
+package asm
+
+// Test of functions implemented in or referenced from assembly.
+
+func implemented(x int) int // ok: implemented in asm.s
+
+func notImplemented(x int) int // ok: perhaps implemented for another architecture
+
+func calledFromAsm(x int) int { return x } // ok: called from asm.s
+
+func unused() {} // want `function "unused" is unused`
@@ -0,0 +1,5 @@
+#include "textflag.h"
+
+TEXT ·implemented(SB), NOSPLIT, $0-16
+	CALL ·calledFromAsm(SB)
+	RET
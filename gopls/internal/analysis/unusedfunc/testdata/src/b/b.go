@@ -0,0 +1,38 @@
+package b
+
+// Test of the cascading deletion of functions used only by an unused function.
+
+func main() {
+	shared()
+}
+
+func entry() { // want `function "entry" is unused`
+	helper()
+	shared()
+	var t T
+	t.method()
+}
+
+// helper is used only by entry.
+func helper() {
+	leaf()
+	even(1)
+}
+
+func shared() {}
+
+type T int
+
+func (T) method() {}
+
+// even and odd are mutually recursive, and used only by helper.
+
+func even(n int) bool { return n == 0 || odd(n-1) }
+
+func odd(n int) bool { return n != 0 && even(n-1) }
+
+func orphan() { // want `function "orphan" is unused`
+	shared2()
+}
+
+func shared2() {} // used by orphan and other (in b2.go)
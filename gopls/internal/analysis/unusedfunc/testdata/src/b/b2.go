@@ -0,0 +1,8 @@
+package b
+
+// leaf is used only by helper (in b.go).
+func leaf() {}
+
+func other() { // want `function "other" is unused`
+	shared2()
+}
@@ -5,15 +5,19 @@
 package unusedfunc
 
 import (
+	"cmp"
 	_ "embed"
 	"fmt"
 	"go/ast"
 	"go/types"
+	"maps"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/util/asm"
 	"golang.org/x/tools/gopls/internal/util/astutil"
 	"golang.org/x/tools/internal/analysisinternal"
 )
@@ -77,6 +81,9 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 	})
 
+	// Functions referenced from assembly are used.
+	asmRefs := asmSymbols(pass)
+
 	// Map each function/method symbol to its declaration.
 	decls := make(map[*types.Func]*ast.FuncDecl)
 	for _, file := range pass.Files {
@@ -109,12 +116,38 @@ func run(pass *analysis.Pass) (any, error) {
 					continue
 				}
 
+				// Skip if there's a preceding //go:linkname directive.
+				//
+				// (A program can link fine without such a directive,
+				// but it is bad style; and the directive may
+				// appear anywhere, not just on the preceding line,
+				// but again that is poor form.)
+				//
+				// TODO(adonovan): use ast.ParseDirective when #68021 lands.
+				if hasLinkname(decl) {
+					continue
+				}
+
+				// Skip functions implemented (in the absence of a
+				// body) or referenced by assembly. Since assembly
+				// may be written for only some architectures, we
+				// assume that a function without a body is
+				// implemented elsewhere even without a TEXT symbol.
+				if decl.Body == nil || decl.Recv == nil && asmRefs[id.Name] {
+					continue
+				}
+
 				fn := pass.TypesInfo.Defs[id].(*types.Func)
 				decls[fn] = decl
 			}
 		}
 	}
 
+	// Record the references among candidates, so that we can
+	// offer to delete an unused function along with the functions
+	// that are used only by it.
+	refs := computeRefs(pass.TypesInfo, decls)
+
 	// Scan for uses of each function symbol.
 	// (Ignore uses within the function's body.)
 	use := func(ref ast.Node, obj types.Object) {
@@ -138,46 +171,207 @@ func run(pass *analysis.Pass) (any, error) {
 	}
 
 	// Report the remaining unreferenced symbols.
-nextDecl:
 	for fn, decl := range decls {
 		noun := "function"
 		if decl.Recv != nil {
 			noun = "method"
 		}
 
-		pos := decl.Pos() // start of func decl or associated comment
-		if decl.Doc != nil {
-			pos = decl.Doc.Pos()
-
-			// Skip if there's a preceding //go:linkname directive.
-			//
-			// (A program can link fine without such a directive,
-			// but it is bad style; and the directive may
-			// appear anywhere, not just on the preceding line,
-			// but again that is poor form.)
-			//
-			// TODO(adonovan): use ast.ParseDirective when #68021 lands.
-			for _, comment := range decl.Doc.List {
-				if strings.HasPrefix(comment.Text, "//go:linkname ") {
-					continue nextDecl
-				}
+		fixes := []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Delete %s %q", noun, fn.Name()),
+			TextEdits: []analysis.TextEdit{deleteDecl(decl)},
+		}}
+
+		// Offer to delete the functions that would become
+		// unused as a consequence, possibly in other files.
+		if cascade := refs.cascade(fn); len(cascade) > 0 {
+			edits := []analysis.TextEdit{deleteDecl(decl)}
+			for _, fn2 := range cascade {
+				edits = append(edits, deleteDecl(refs.decls[fn2]))
 			}
+			fixes = append(fixes, analysis.SuggestedFix{
+				Message:   fmt.Sprintf("Delete %s %q and %d %s used only by it", noun, fn.Name(), len(cascade), plural(len(cascade), "declaration", "declarations")),
+				TextEdits: edits,
+			})
 		}
 
 		pass.Report(analysis.Diagnostic{
-			Pos:     decl.Name.Pos(),
-			End:     decl.Name.End(),
-			Message: fmt.Sprintf("%s %q is unused", noun, fn.Name()),
-			SuggestedFixes: []analysis.SuggestedFix{{
-				Message: fmt.Sprintf("Delete %s %q", noun, fn.Name()),
-				TextEdits: []analysis.TextEdit{{
-					// delete declaration
-					Pos: pos,
-					End: decl.End(),
-				}},
-			}},
+			Pos:            decl.Name.Pos(),
+			End:            decl.Name.End(),
+			Message:        fmt.Sprintf("%s %q is unused", noun, fn.Name()),
+			SuggestedFixes: fixes,
 		})
 	}
 
 	return nil, nil
 }
+
+// hasLinkname reports whether the declaration's doc comment
+// contains a //go:linkname directive.
+func hasLinkname(decl *ast.FuncDecl) bool {
+	if decl.Doc != nil {
+		for _, comment := range decl.Doc.List {
+			if strings.HasPrefix(comment.Text, "//go:linkname ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asmSymbols returns the names of the symbols of the package that are
+// defined or referenced by its assembly files.
+func asmSymbols(pass *analysis.Pass) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, filename := range pass.OtherFiles {
+		if !strings.HasSuffix(filename, ".s") {
+			continue
+		}
+		content, err := pass.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		for _, id := range asm.Parse(content).Idents {
+			if name, ok := id.Local(pass.Pkg.Path()); ok {
+				symbols[name] = true
+			}
+		}
+	}
+	return symbols
+}
+
+// deleteDecl returns an edit that deletes the declaration,
+// including its doc comment.
+func deleteDecl(decl *ast.FuncDecl) analysis.TextEdit {
+	pos := decl.Pos() // start of func decl or associated comment
+	if decl.Doc != nil {
+		pos = decl.Doc.Pos()
+	}
+	return analysis.TextEdit{Pos: pos, End: decl.End()}
+}
+
+// A refGraph records the references among the candidate functions
+// of a package, that is, the unexported functions that may be
+// deleted if unreferenced.
+type refGraph struct {
+	decls    map[*types.Func]*ast.FuncDecl
+	callees  map[*types.Func][]*types.Func        // candidates referenced in the body of each candidate
+	users    map[*types.Func]map[*types.Func]bool // candidates whose bodies refer to each candidate
+	external map[*types.Func]bool                 // candidates referenced from outside any candidate
+}
+
+// computeRefs returns the graph of references among the candidates.
+// References from within a candidate's own body are ignored.
+func computeRefs(info *types.Info, decls map[*types.Func]*ast.FuncDecl) *refGraph {
+	g := &refGraph{
+		decls:    maps.Clone(decls),
+		callees:  make(map[*types.Func][]*types.Func),
+		users:    make(map[*types.Func]map[*types.Func]bool),
+		external: make(map[*types.Func]bool),
+	}
+
+	// Map each identifier within a candidate's body to the candidate.
+	enclosing := make(map[*ast.Ident]*types.Func)
+	for fn, decl := range decls {
+		if decl.Body != nil {
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					enclosing[id] = fn
+				}
+				return true
+			})
+		}
+	}
+
+	// (Uses includes the Sel identifier of each method selection.)
+	for id, obj := range info.Uses {
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			continue
+		}
+		fn = fn.Origin()
+		if _, ok := decls[fn]; !ok {
+			continue
+		}
+		switch user := enclosing[id]; user {
+		case fn:
+			// Ignore uses within the function's body.
+		case nil:
+			g.external[fn] = true
+		default:
+			if g.users[fn] == nil {
+				g.users[fn] = make(map[*types.Func]bool)
+			}
+			if !g.users[fn][user] {
+				g.users[fn][user] = true
+				g.callees[user] = append(g.callees[user], fn)
+			}
+		}
+	}
+	return g
+}
+
+// cascade returns the candidates, in declaration order, that would
+// become unused, and could be deleted, if the unused function fn
+// were deleted. They are the candidates referenced, directly or
+// indirectly, by fn, that are not used by any function other than
+// fn and each other.
+func (g *refGraph) cascade(fn *types.Func) []*types.Func {
+	// Find the candidates that would remain live without fn:
+	// those referenced from elsewhere, and their callees.
+	live := make(map[*types.Func]bool)
+	var markLive func(f *types.Func)
+	markLive = func(f *types.Func) {
+		if f != fn && !live[f] {
+			live[f] = true
+			for _, callee := range g.callees[f] {
+				markLive(callee)
+			}
+		}
+	}
+	for f := range g.external {
+		markLive(f)
+	}
+
+	// Gather the candidates reachable from fn that are not live.
+	dead := make(map[*types.Func]bool)
+	var markDead func(f *types.Func)
+	markDead = func(f *types.Func) {
+		for _, callee := range g.callees[f] {
+			if callee != fn && !live[callee] && !dead[callee] {
+				dead[callee] = true
+				markDead(callee)
+			}
+		}
+	}
+	markDead(fn)
+
+	// A candidate that is also used by some other unused
+	// function cannot be deleted without breaking the build.
+	// Iterate to a fixed point, as removal may expose others.
+	for changed := true; changed; {
+		changed = false
+		for f := range dead {
+			for user := range g.users[f] {
+				if user != fn && !dead[user] {
+					delete(dead, f)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	result := slices.Collect(maps.Keys(dead))
+	slices.SortFunc(result, func(x, y *types.Func) int {
+		return cmp.Compare(g.decls[x].Pos(), g.decls[y].Pos())
+	})
+	return result
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -21,6 +21,8 @@
 //   - unexported methods whose name matches an interface method
 //     declared in the same package, since the method's signature
 //     may be required to conform to the interface type.
+//   - functions called from the assembly files of the package, since
+//     the assembly depends on the layout of their parameters.
 //   - functions with empty bodies, or containing just a call to panic.
 //   - parameters that are unnamed, or named "_", the blank identifier.
 //
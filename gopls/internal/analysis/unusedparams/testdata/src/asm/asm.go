@@ -0,0 +1,12 @@
+package asm
+
+// Test of functions called from assembly, whose parameters
+// are part of the frame layout that the assembly assumes.
+
+func implemented(x int) int
+
+func calledFromAsm(x, y int) int { return x } // ok: called from asm.s
+
+func notCalledFromAsm(x, y int) int { return x } // want "unused parameter: y"
+
+var _ = notCalledFromAsm(1, 2)
@@ -9,10 +9,12 @@ import (
 	"fmt"
 	"go/ast"
 	"go/types"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/util/asm"
 	"golang.org/x/tools/gopls/internal/util/moreslices"
 	"golang.org/x/tools/internal/analysisinternal"
 	"golang.org/x/tools/internal/astutil/cursor"
@@ -128,6 +130,10 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 	}
 
+	// Functions referenced from assembly must conform to the
+	// frame layout that it assumes.
+	asmRefs := asmSymbols(pass)
+
 	// Find all vars (notably parameters) that are used.
 	usedVars := make(map[*types.Var]bool)
 	for _, obj := range pass.TypesInfo.Uses {
@@ -178,6 +184,11 @@ func run(pass *analysis.Pass) (any, error) {
 				return true
 			}
 
+			// Ignore functions called from assembly.
+			if n.Recv == nil && asmRefs[n.Name.Name] {
+				return true
+			}
+
 			fn = pass.TypesInfo.Defs[n.Name].(*types.Func)
 			ftype, body = n.Type, n.Body
 
@@ -309,3 +320,24 @@ func run(pass *analysis.Pass) (any, error) {
 	})
 	return nil, nil
 }
+
+// asmSymbols returns the names of the symbols of the package that are
+// defined or referenced by its assembly files.
+func asmSymbols(pass *analysis.Pass) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, filename := range pass.OtherFiles {
+		if !strings.HasSuffix(filename, ".s") {
+			continue
+		}
+		content, err := pass.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		for _, id := range asm.Parse(content).Idents {
+			if name, ok := id.Local(pass.Pkg.Path()); ok {
+				symbols[name] = true
+			}
+		}
+	}
+	return symbols
+}
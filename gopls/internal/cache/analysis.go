@@ -109,8 +109,15 @@ const AnalysisProgressTitle = "Analyzing Dependencies"
 // Analyze applies the set of enabled analyzers to the packages in the pkgs
 // map, and returns their diagnostics.
 //
-// Notifications of progress may be sent to the optional reporter.
-func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Package, reporter *progress.Tracker) ([]*Diagnostic, error) {
+// Notifications of progress may be sent to the optional reporter,
+// and recorded in the optional index progress.
+//
+// If the context is cancelled, for example because the snapshot was
+// invalidated, Analyze returns the context's error along with the
+// diagnostics of the analyzers that completed before the cancellation,
+// so that the caller may report them rather than discarding the work
+// wholesale. Such partial results are not cached.
+func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Package, reporter *progress.Tracker, index *IndexProgress) ([]*Diagnostic, error) {
 	start := time.Now() // for progress reporting
 
 	var tagStr string // sorted comma-separated list of PackageIDs
@@ -244,9 +251,24 @@ func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Pac
 			return nil, err
 		}
 		root.analyzers = enabledAnalyzers
+
+		// Analyzers of root packages may also consult their
+		// assembly files, for example to check declarations
+		// (asmdecl) or to find functions called from assembly.
+		for _, uri := range root.ph.mp.OtherFiles {
+			if strings.HasSuffix(uri.Path(), ".s") {
+				fh, err := s.ReadFile(ctx, uri)
+				if err != nil {
+					return nil, err
+				}
+				root.asmFiles = append(root.asmFiles, fh)
+			}
+		}
 		roots = append(roots, root)
 	}
 
+	index.addAnalysis(len(nodes))
+
 	// Progress reporting. If supported, gopls reports progress on analysis
 	// passes that are taking a long time.
 	maybeReport := func(completed int64) {}
@@ -343,6 +365,7 @@ func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Pac
 				return err // cancelled, or failed to produce a package
 			}
 
+			index.addAnalysisDone()
 			maybeReport(completed.Add(1))
 			an.summary = summary
 
@@ -368,7 +391,34 @@ func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Pac
 		enqueue(leaf)
 	}
 	if err := g.Wait(); err != nil {
-		return nil, err // cancelled, or failed to produce a package
+		if ctx.Err() != nil {
+			// Cancelled: report the diagnostics of the analyzers
+			// that completed, whether in root packages whose
+			// analysis finished or in those that were interrupted.
+			var results []*Diagnostic
+			for _, root := range roots {
+				summary := root.summary
+				if summary == nil {
+					summary = root.partial
+				}
+				if summary == nil {
+					continue // not started
+				}
+				for _, a := range enabledAnalyzers {
+					srcAnalyzer, ok := toSrc[a]
+					if !ok {
+						continue // added only for requirements
+					}
+					if summary := summary.Actions[stableNames[a]]; summary != nil && summary.Err == "" {
+						for _, gobDiag := range summary.Diagnostics {
+							results = append(results, toSourceDiagnostic(srcAnalyzer, &gobDiag))
+						}
+					}
+				}
+			}
+			return results, ctx.Err()
+		}
+		return nil, err // failed to produce a package
 	}
 
 	// Inv: all root nodes now have a summary (#66732).
@@ -465,11 +515,13 @@ type analysisNode struct {
 	batch           *typeCheckBatch             // type checking batch, for shared type checking
 	ph              *packageHandle              // package handle, for key and reachability analysis
 	analyzers       []*analysis.Analyzer        // set of analyzers to run
+	asmFiles        []file.Handle               // assembly files, for root nodes only
 	preds           []*analysisNode             // graph edges:
 	succs           map[PackageID]*analysisNode //   (preds -> self -> succs)
 	unfinishedSuccs atomic.Int32
 	unfinishedPreds atomic.Int32                  // effectively a summary.Actions refcount
 	summary         *analyzeSummary               // serializable result of analyzing this package
+	partial         *analyzeSummary               // completed actions of a cancelled run (used for root nodes only)
 	stableNames     map[*analysis.Analyzer]string // cross-process stable names for Analyzers
 
 	summaryHashOnce sync.Once
@@ -605,6 +657,9 @@ func (an *analysisNode) runCached(ctx context.Context, key file.Hash) (*analyzeS
 		cachedSummary, err := inFlightAnalyses.get(ctx, key, func(ctx context.Context) (*analyzeSummary, error) {
 			summary, err := an.run(ctx)
 			if err != nil {
+				// Record the completed actions of a cancelled run,
+				// which must not be cached or shared.
+				an.partial = summary
 				return nil, err
 			}
 			if summary == nil { // debugging #66732 (can't happen)
@@ -661,6 +716,12 @@ func (an *analysisNode) cacheKey() file.Hash {
 	// metadata errors: used for 'compiles' field
 	fmt.Fprintf(hasher, "errors: %d", len(an.ph.mp.Errors))
 
+	// assembly files
+	fmt.Fprintf(hasher, "asm: %d\n", len(an.asmFiles))
+	for _, fh := range an.asmFiles {
+		fmt.Fprintf(hasher, "%s %s\n", fh.URI(), fh.Identity().Hash)
+	}
+
 	// vdeps, in PackageID order
 	for _, vdep := range moremaps.Sorted(an.succs) {
 		hash := vdep.summaryHash()
@@ -676,8 +737,14 @@ func (an *analysisNode) cacheKey() file.Hash {
 // This function does not access the snapshot.
 //
 // Postcondition: on success, the analyzeSummary.Actions
-// key set is {a.Name for a in analyzers}.
+// key set is {a.Name for a in analyzers}. If the context is cancelled
+// once the package is type-checked, run returns the context's error
+// along with a partial summary in which the actions that did not
+// complete have errors.
 func (an *analysisNode) run(ctx context.Context) (*analyzeSummary, error) {
+	ctx, done := event.Start(ctx, "cache.analysisNode.run", label.Package.Of(string(an.ph.mp.ID)))
+	defer done()
+
 	// Type-check the package syntax.
 	pkg, err := an.typeCheck(ctx)
 	if err != nil {
@@ -725,11 +792,6 @@ func (an *analysisNode) run(ctx context.Context) (*analyzeSummary, error) {
 	execActions(ctx, roots)
 	// Inv: each root's summary is set (whether success or error).
 
-	// Don't return (or cache) the result in case of cancellation.
-	if err := ctx.Err(); err != nil {
-		return nil, err // cancelled
-	}
-
 	// Return summaries only for the requested actions.
 	summaries := make(map[string]*actionSummary)
 	for _, root := range roots {
@@ -738,11 +800,19 @@ func (an *analysisNode) run(ctx context.Context) (*analyzeSummary, error) {
 		}
 		summaries[root.stableName] = root.summary
 	}
-
-	return &analyzeSummary{
+	summary := &analyzeSummary{
 		Compiles: pkg.compiles,
 		Actions:  summaries,
-	}, nil
+	}
+
+	// In case of cancellation, some actions may not have run:
+	// return the summary, whose cancelled actions have errors,
+	// only as a partial result, which the caller must not cache.
+	if err := ctx.Err(); err != nil {
+		return summary, err // cancelled
+	}
+
+	return summary, nil
 }
 
 func (an *analysisNode) typeCheck(ctx context.Context) (*analysisPackage, error) {
@@ -799,6 +869,7 @@ filterErrors:
 	return &analysisPackage{
 		pkg:          ppkg,
 		files:        files,
+		asmFiles:     an.asmFiles,
 		typeErrors:   typeErrors,
 		compiles:     compiles,
 		factsDecoder: factsDecoder,
@@ -867,6 +938,7 @@ func typesLookup(pkg *types.Package) func(string) *types.Package {
 type analysisPackage struct {
 	pkg          *Package
 	files        []*ast.File   // same as parsed[i].File
+	asmFiles     []file.Handle // assembly files (Pass.OtherFiles)
 	typeErrors   []types.Error // filtered type checker errors
 	compiles     bool          // package is transitively free of list/parse/type errors
 	factsDecoder *facts.Decoder
@@ -906,7 +978,11 @@ func execActions(ctx context.Context, actions []*action) {
 			defer wg.Done()
 			act.once.Do(func() {
 				execActions(ctx, act.hdeps) // analyze "horizontal" dependencies
-				act.result, act.summary, act.err = act.exec(ctx)
+				if err := ctx.Err(); err != nil {
+					act.err = err // cancelled: don't start the analyzer
+				} else {
+					act.result, act.summary, act.err = act.exec(ctx)
+				}
 				if act.err != nil {
 					act.summary = &actionSummary{Err: act.err.Error()}
 					// TODO(adonovan): suppress logging. But
@@ -1047,6 +1123,20 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 				break
 			}
 		}
+		if mapper == nil {
+			// Analyzers such as asmdecl report diagnostics in
+			// assembly files (OtherFiles), which they parse.
+			for _, fh := range apkg.asmFiles {
+				if fh.URI().Path() == tokFile.Name() {
+					content, err := fh.Content()
+					if err != nil {
+						return protocol.Location{}, err
+					}
+					mapper = protocol.NewMapper(fh.URI(), content)
+					break
+				}
+			}
+		}
 		if mapper == nil {
 			// The start position was not among the package's parsed
 			// Go files, indicating that the analyzer added new files
@@ -1060,11 +1150,10 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 			//
 			// In principle these files could be:
 			//
-			// - OtherFiles (non-Go files such as asm).
-			//   However, we set Pass.OtherFiles=[] because
-			//   gopls won't service "diagnose" requests
-			//   for non-Go files, so there's no point
-			//   reporting diagnostics in them.
+			// - OtherFiles other than assembly files (such as
+			//   C files). However, we omit them from
+			//   Pass.OtherFiles because gopls won't service
+			//   requests for them.
 			//
 			// - IgnoredFiles (files tagged for other configs).
 			//   However, we set Pass.IgnoredFiles=[] because,
@@ -1119,11 +1208,16 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 	// Now run the (pkg, analyzer) action.
 	var diagnostics []gobDiagnostic
 
+	otherFiles := make([]string, len(apkg.asmFiles))
+	for i, fh := range apkg.asmFiles {
+		otherFiles[i] = fh.URI().Path()
+	}
+
 	pass := &analysis.Pass{
 		Analyzer:     analyzer,
 		Fset:         apkg.pkg.FileSet(),
 		Files:        apkg.files,
-		OtherFiles:   nil, // since gopls doesn't handle non-Go (e.g. asm) files
+		OtherFiles:   otherFiles,
 		IgnoredFiles: nil, // zero-config gopls should analyze these files in another view
 		Pkg:          apkg.pkg.Types(),
 		TypesInfo:    apkg.pkg.TypesInfo(),
@@ -1136,6 +1230,27 @@ func (act *action) exec(ctx context.Context) (any, *actionSummary, error) {
 				bug.Reportf("invalid SuggestedFixes: %v", err)
 				d.SuggestedFixes = nil
 			}
+			if err := analysisinternal.ValidateDiagnostic(apkg.pkg.FileSet(), analyzer, d); err != nil {
+				bug.Reportf("invalid diagnostic: %v", err)
+				d.Scope, d.Data = analysis.ScopePosition, nil
+			}
+			// Display a diagnostic about a whole file or package,
+			// if it lacks a specific range, at the package clause.
+			switch d.Scope {
+			case analysis.ScopeFile:
+				if !d.End.IsValid() {
+					for _, f := range apkg.files {
+						if f.FileStart <= d.Pos && d.Pos <= f.FileEnd {
+							d.Pos, d.End = f.Name.Pos(), f.Name.End()
+							break
+						}
+					}
+				}
+			case analysis.ScopePackage:
+				if !d.Pos.IsValid() && len(apkg.files) > 0 {
+					d.Pos, d.End = apkg.files[0].Name.Pos(), apkg.files[0].Name.End()
+				}
+			}
 			diagnostic, err := toGobDiagnostic(posToLocation, analyzer, d)
 			if err != nil {
 				// Don't bug.Report here: these errors all originate in
@@ -0,0 +1,214 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"go/types"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/filecache"
+	"golang.org/x/tools/internal/event"
+)
+
+// This file defines the API hash of a package, and the key under
+// which the cross-reference index of a package is stored in the file
+// cache in addition to its package key.
+//
+// The package key (packageHandle.key) of a package changes whenever
+// any reachable dependency changes, even if the change is confined to
+// a function body. So, after such an edit, or on the first run
+// following a change to the source on disk, a query for the references
+// to a widely used symbol would need to type-check the entire reverse
+// dependency set. But the cross-reference index of a package depends
+// only on its own source and on the APIs of its dependencies, so we
+// also store it under a key derived from those inputs (see
+// [typeCheckBatch.xrefsKey]), which survives such edits.
+
+// apiHash returns a hash of the API of pkg: its exported package-level
+// objects, and the named types of pkg reachable from them, along with
+// their methods. The hash does not depend on positions, function
+// bodies, or unexported declarations that are unreachable from the
+// API, and so it is the same for packages type-checked from syntax and
+// those loaded from export data.
+func apiHash(pkg *types.Package) file.Hash {
+	hasher := sha256.New()
+	qual := func(p *types.Package) string { return p.Path() }
+
+	var (
+		queue   []*types.Named // reachable named types of pkg, in order of discovery
+		seen    = make(map[types.Type]bool)
+		visit   func(T types.Type)
+		visitTP func(list *types.TypeParamList)
+	)
+	visitTP = func(list *types.TypeParamList) {
+		for i := 0; i < list.Len(); i++ {
+			visit(list.At(i))
+		}
+	}
+	visit = func(T types.Type) {
+		switch T := T.(type) {
+		case *types.Alias:
+			visit(types.Unalias(T))
+		case *types.Basic:
+		case *types.Pointer:
+			visit(T.Elem())
+		case *types.Slice:
+			visit(T.Elem())
+		case *types.Array:
+			visit(T.Elem())
+		case *types.Chan:
+			visit(T.Elem())
+		case *types.Map:
+			visit(T.Key())
+			visit(T.Elem())
+		case *types.Signature:
+			visitTP(T.TypeParams())
+			visit(T.Params())
+			visit(T.Results())
+		case *types.Tuple:
+			for i := 0; i < T.Len(); i++ {
+				visit(T.At(i).Type())
+			}
+		case *types.Struct:
+			for i := 0; i < T.NumFields(); i++ {
+				visit(T.Field(i).Type())
+			}
+		case *types.Interface:
+			for i := 0; i < T.NumEmbeddeds(); i++ {
+				visit(T.EmbeddedType(i))
+			}
+			for i := 0; i < T.NumMethods(); i++ {
+				visit(T.Method(i).Type())
+			}
+		case *types.Union:
+			for i := 0; i < T.Len(); i++ {
+				visit(T.Term(i).Type())
+			}
+		case *types.TypeParam:
+			if !seen[T] {
+				seen[T] = true
+				visit(T.Constraint())
+			}
+		case *types.Named:
+			for i := 0; i < T.TypeArgs().Len(); i++ {
+				visit(T.TypeArgs().At(i))
+			}
+			origin := T.Origin()
+			if origin.Obj().Pkg() == pkg && !seen[origin] {
+				seen[origin] = true
+				queue = append(queue, origin)
+			}
+		}
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if obj := scope.Lookup(name); obj.Exported() {
+			fmt.Fprintln(hasher, types.ObjectString(obj, qual))
+			visit(obj.Type())
+		}
+	}
+	for i := 0; i < len(queue); i++ { // queue grows during iteration
+		T := queue[i]
+		fmt.Fprintf(hasher, "type %s %s\n", types.TypeString(T, qual), types.TypeString(T.Underlying(), qual))
+		visitTP(T.TypeParams())
+		visit(T.Underlying())
+		for j := 0; j < T.NumMethods(); j++ {
+			m := T.Method(j)
+			fmt.Fprintln(hasher, types.ObjectString(m, qual))
+			visit(m.Type())
+		}
+	}
+
+	var hash file.Hash
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+// getAPIHash returns the API hash of the package with the given id,
+// reading it from the file cache or, failing that, computing it from
+// the package imported (and perhaps type-checked) for the purpose.
+func (b *typeCheckBatch) getAPIHash(ctx context.Context, id PackageID) (file.Hash, error) {
+	return b.apiHashes.get(ctx, id, func(ctx context.Context) (file.Hash, error) {
+		ph := b.getHandle(id)
+		if ph == nil {
+			return file.Hash{}, fmt.Errorf("no package handle for %s", id)
+		}
+		var hash file.Hash
+		data, err := filecache.Get(apiKind, ph.key)
+		if err == nil && len(data) == len(hash) {
+			copy(hash[:], data)
+			return hash, nil
+		} else if err != nil && err != filecache.ErrNotFound {
+			event.Error(ctx, "reading API hash from filecache", err)
+		}
+
+		pkg, err := b.getImportPackage(ctx, id)
+		if err != nil {
+			return file.Hash{}, err
+		}
+		hash = apiHash(pkg)
+		if err := filecache.Set(apiKind, ph.key, hash[:]); err != nil {
+			event.Error(ctx, fmt.Sprintf("storing API hash for %s", id), err)
+		}
+		return hash, nil
+	})
+}
+
+// xrefsKey returns the alternative key under which the cross-reference
+// index of the package of ph is stored: a hash of the local inputs to
+// type-checking the package and the API hashes of its transitive
+// dependencies. Unlike ph.key, it does not change when a dependency
+// changes in a way that does not affect its API.
+//
+// Computing the key may require the dependencies to be imported, but
+// never requires syntax packages.
+func (b *typeCheckBatch) xrefsKey(ctx context.Context, ph *packageHandle) (file.Hash, error) {
+	// Compute the transitive dependencies.
+	var deps []PackageID
+	seen := make(map[PackageID]bool)
+	var visit func(mp *metadata.Package)
+	visit = func(mp *metadata.Package) {
+		for _, depID := range mp.DepsByPkgPath {
+			if !seen[depID] {
+				seen[depID] = true
+				deps = append(deps, depID)
+				if dep := b.Metadata(depID); dep != nil {
+					visit(dep)
+				}
+			}
+		}
+	}
+	visit(ph.mp)
+	sort.Slice(deps, func(i, j int) bool { return deps[i] < deps[j] })
+
+	hashes := make([]file.Hash, len(deps))
+	var g errgroup.Group
+	for i, depID := range deps {
+		g.Go(func() error {
+			hash, err := b.getAPIHash(ctx, depID)
+			hashes[i] = hash
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return file.Hash{}, err
+	}
+
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "xrefs %s\n", ph.localKey)
+	for i, depID := range deps {
+		fmt.Fprintf(hasher, "dep %s %s\n", depID, hashes[i])
+	}
+	var hash file.Hash
+	hasher.Sum(hash[:0])
+	return hash, nil
+}
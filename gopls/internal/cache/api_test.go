@@ -0,0 +1,170 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestAPIHash(t *testing.T) {
+	const base = `package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y string }
+
+func (T) M() int { return 0 }
+
+func (T) m() {}
+
+type hidden struct{ Z int }
+
+func F() { println("hello") }
+`
+	tests := []struct {
+		desc string
+		src  string
+		same bool // whether the API hash is the same as that of base
+	}{
+		{
+			"edit function body",
+			`package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y string }
+
+func (T) M() int { return 1 + 2 }
+
+func (T) m() {}
+
+type hidden struct{ Z int }
+
+func F() {
+	println("goodbye")
+}
+`,
+			true,
+		},
+		{
+			"change unreachable unexported type",
+			`package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y string }
+
+func (T) M() int { return 0 }
+
+func (T) m() {}
+
+type hidden struct{ Z, W int }
+
+func F() { println("hello") }
+`,
+			true,
+		},
+		{
+			"change reachable unexported type",
+			`package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y, W string }
+
+func (T) M() int { return 0 }
+
+func (T) m() {}
+
+type hidden struct{ Z int }
+
+func F() { println("hello") }
+`,
+			false,
+		},
+		{
+			"reorder methods",
+			`package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y string }
+
+func (T) m() {}
+
+func (T) M() int { return 0 }
+
+type hidden struct{ Z int }
+
+func F() { println("hello") }
+`,
+			false,
+		},
+		{
+			"change signature",
+			`package p
+
+type T struct {
+	X int
+	u
+}
+
+type u struct{ Y string }
+
+func (T) M() int { return 0 }
+
+func (T) m() {}
+
+type hidden struct{ Z int }
+
+func F(x int) { println("hello") }
+`,
+			false,
+		},
+	}
+
+	want := apiHash(checkAPITestPackage(t, base))
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := apiHash(checkAPITestPackage(t, test.src))
+			if same := got == want; same != test.same {
+				t.Errorf("API hash unchanged = %t, want %t", same, test.same)
+			}
+		})
+	}
+}
+
+func checkAPITestPackage(t *testing.T, src string) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("example.com/p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
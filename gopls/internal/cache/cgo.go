@@ -0,0 +1,164 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+// This file defines support for cgo, whose generated declarations and
+// error messages use names and positions that are unfamiliar to users.
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// cgoNameRe matches the names of the Go declarations that cgo generates
+// (in _cgo_gotypes.go) for the C symbols referenced by a package.
+var cgoNameRe = regexp.MustCompile(`\b_C(?:func|type|var|iconst|fconst|sconst|macro)_(\w+)`)
+
+// CgoName reports whether name is that of a Go declaration generated by
+// cgo for a C symbol, and if so returns the name by which Go code refers
+// to it, without the "C." qualifier. For example, the name of the
+// declaration of C.struct_point is "_Ctype_struct_point".
+func CgoName(name string) (string, bool) {
+	m := cgoNameRe.FindStringSubmatch(name)
+	if m == nil || len(m[0]) != len(name) {
+		return "", false
+	}
+	return m[1], true
+}
+
+// DemangleCgo replaces the names of declarations generated by cgo within
+// s, such as the message of a type error, by the C.name form used in Go
+// source.
+func DemangleCgo(s string) string {
+	if !strings.Contains(s, "_C") {
+		return s // fast path
+	}
+	return cgoNameRe.ReplaceAllString(s, "C.$1")
+}
+
+// buildOutputLineRe matches a "file:line:col: message" line of the
+// output of a failed build, as reported by cgo or the C compiler.
+var buildOutputLineRe = regexp.MustCompile(`^(.*?):(\d+):(\d+): (.*)$`)
+
+// cgoErrorDiagnostics returns diagnostics for the errors in the output of
+// cgo processing that is reported by the go/packages error e, or nil if e
+// is not such an error.
+//
+// When cgo fails, for example because the C compiler rejects the preamble
+// of an import "C" declaration, go list reports the entire output of the
+// build as a single error with no position. Each line of it that refers
+// to a file of the package becomes a diagnostic, so that errors in the
+// preamble or in the headers it includes are reported where they occur.
+func cgoErrorDiagnostics(ctx context.Context, e packages.Error, mp *metadata.Package, fs file.Source) ([]*Diagnostic, error) {
+	if e.Pos != "" || !strings.Contains(e.Msg, "\n") {
+		return nil, nil
+	}
+	var diags []*Diagnostic
+	for _, line := range strings.Split(e.Msg, "\n") {
+		m := buildOutputLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		filename := m[1]
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(mp.LoadDir, filename)
+		}
+		uri := protocol.URIFromPath(filename)
+		if !slices.Contains(mp.GoFiles, uri) && !slices.Contains(mp.OtherFiles, uri) {
+			continue // e.g. a system header
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		col8, _ := strconv.Atoi(m[3])
+
+		// Messages of the C compiler have a severity, and their columns
+		// may not count bytes (GCC counts tabs as up to 8 columns), so
+		// they are reported on the entire line. Messages of cgo itself
+		// have no severity, and are positioned at the faulty C.name.
+		msg := m[4]
+		severity := protocol.SeverityError
+		wholeLine := true
+		switch {
+		case strings.HasPrefix(msg, "error: "):
+			msg = strings.TrimPrefix(msg, "error: ")
+		case strings.HasPrefix(msg, "warning: "):
+			msg = strings.TrimPrefix(msg, "warning: ")
+			severity = protocol.SeverityWarning
+		case strings.HasPrefix(msg, "note: "):
+			continue
+		default:
+			wholeLine = false
+		}
+
+		fh, err := fs.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fh.Content()
+		if err != nil {
+			continue // e.g. deleted header
+		}
+		mapper := protocol.NewMapper(uri, content)
+		var rng protocol.Range
+		if wholeLine {
+			start, end, ok := lineOffsets(content, lineNum)
+			if !ok {
+				continue
+			}
+			rng, err = mapper.OffsetRange(start, end)
+		} else {
+			var posn protocol.Position
+			posn, err = mapper.LineCol8Position(lineNum, col8)
+			rng = protocol.Range{Start: posn, End: posn}
+		}
+		if err != nil {
+			continue // stale output
+		}
+		diags = append(diags, &Diagnostic{
+			URI:      uri,
+			Range:    rng,
+			Severity: severity,
+			Source:   ListError,
+			Message:  msg,
+		})
+	}
+	return diags, nil
+}
+
+// lineOffsets returns the offsets of the start and end of the text of the
+// given (1-based) line of content, excluding surrounding white space.
+func lineOffsets(content []byte, line int) (start, end int, ok bool) {
+	for i := 1; i < line; i++ {
+		nl := bytes.IndexByte(content[start:], '\n')
+		if nl < 0 {
+			return 0, 0, false
+		}
+		start += nl + 1
+	}
+	end = len(content)
+	if nl := bytes.IndexByte(content[start:], '\n'); nl >= 0 {
+		end = start + nl
+	}
+	for start < end && isSpace(content[start]) {
+		start++
+	}
+	for end > start && isSpace(content[end-1]) {
+		end--
+	}
+	return start, end, true
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r'
+}
@@ -73,6 +73,7 @@ type typeCheckBatch struct {
 	cpulimit         chan unit                               // concurrency limiter for CPU-bound operations
 	syntaxPackages   *futureCache[PackageID, *Package]       // transient cache of in-progress syntax futures
 	importPackages   *futureCache[PackageID, *types.Package] // persistent cache of imports
+	apiHashes        *futureCache[PackageID, file.Hash]      // persistent cache of API hashes
 	gopackagesdriver bool                                    // for bug reporting: were packages loaded with a driver?
 }
 
@@ -267,6 +268,7 @@ func newTypeCheckBatch(parseCache *parseCache, gopackagesdriver bool) *typeCheck
 		cpulimit:         make(chan unit, runtime.GOMAXPROCS(0)),
 		syntaxPackages:   newFutureCache[PackageID, *Package](false),      // don't persist syntax packages
 		importPackages:   newFutureCache[PackageID, *types.Package](true), // ...but DO persist imports
+		apiHashes:        newFutureCache[PackageID, file.Hash](true),
 		gopackagesdriver: gopackagesdriver,
 	}
 }
@@ -424,15 +426,17 @@ func (b *typeCheckBatch) getPackage(ctx context.Context, ph *packageHandle) (*Pa
 		}
 
 		// Update caches.
-		go storePackageResults(ctx, ph, p) // ...and write all packages to disk
+		go b.storePackageResults(ctx, ph, p) // ...and write all packages to disk
 		return p, nil
 	})
 }
 
 // storePackageResults serializes and writes information derived from p to the
 // file cache.
-// The context is used only for logging; cancellation does not affect the operation.
-func storePackageResults(ctx context.Context, ph *packageHandle, p *Package) {
+// The context is used for logging and for computing the API hashes
+// of p's dependencies; cancellation prevents only the storing of the
+// cross-reference index under its alternative key (see xrefsKey).
+func (b *typeCheckBatch) storePackageResults(ctx context.Context, ph *packageHandle, p *Package) {
 	toCache := map[string][]byte{
 		xrefsKind:       p.pkg.xrefs(),
 		methodSetsKind:  p.pkg.methodsets().Encode(),
@@ -454,6 +458,16 @@ func storePackageResults(ctx context.Context, ph *packageHandle, p *Package) {
 			event.Error(ctx, fmt.Sprintf("storing %s data for %s", kind, ph.mp.ID), err)
 		}
 	}
+
+	hash := apiHash(p.pkg.types)
+	if err := filecache.Set(apiKind, ph.key, hash[:]); err != nil {
+		event.Error(ctx, fmt.Sprintf("storing API hash for %s", ph.mp.ID), err)
+	}
+	if key, err := b.xrefsKey(ctx, ph); err == nil {
+		if err := filecache.Set(xrefsKind, key, toCache[xrefsKind]); err != nil {
+			event.Error(ctx, fmt.Sprintf("storing xrefs data for %s", ph.mp.ID), err)
+		}
+	}
 }
 
 // Metadata implements the [metadata.Source] interface.
@@ -1751,7 +1765,12 @@ func releaseVersion() string {
 func depsErrors(ctx context.Context, snapshot *Snapshot, mp *metadata.Package) ([]*Diagnostic, error) {
 	// Select packages that can't be found, and were imported in non-workspace packages.
 	// Workspace packages already show their own errors.
+	//
+	// The exception is a missing go.sum entry for the module providing a
+	// package imported by this package, which the type checker reports
+	// only as a missing package.
 	var relevantErrors []*packagesinternal.PackageError
+	sumErrors := make(map[string]*packagesinternal.PackageError) // keyed by imported package path
 	for _, depsError := range mp.DepsErrors {
 		// Up to Go 1.15, the missing package was included in the stack, which
 		// was presumably a bug. We want the next one up.
@@ -1762,13 +1781,18 @@ func depsErrors(ctx context.Context, snapshot *Snapshot, mp *metadata.Package) (
 
 		directImporter := depsError.ImportStack[directImporterIdx]
 		if snapshot.IsWorkspacePackage(PackageID(directImporter)) {
+			if directImporter == string(mp.PkgPath) {
+				if match := missingGoSumRe.FindStringSubmatch(depsError.Err); match != nil {
+					sumErrors[match[1]] = depsError
+				}
+			}
 			continue
 		}
 		relevantErrors = append(relevantErrors, depsError)
 	}
 
 	// Don't build the import index for nothing.
-	if len(relevantErrors) == 0 {
+	if len(relevantErrors) == 0 && len(sumErrors) == 0 {
 		return nil, nil
 	}
 
@@ -1841,6 +1865,33 @@ func depsErrors(ctx context.Context, snapshot *Snapshot, mp *metadata.Package) (
 		return nil, err
 	}
 
+	// Add a diagnostic to each import of a package whose module lacks a
+	// go.sum entry, with a fix to download just that module.
+	for pkgPath, depErr := range sumErrors {
+		var fixes []SuggestedFix
+		if modVer := findModuleProviding(pm.File, pkgPath); modVer != nil {
+			fixes = downloadGoSumQuickFix(pm.URI, []string{modVer.String()})
+		}
+		for _, imp := range allImports[pkgPath] {
+			rng, err := imp.cgf.NodeRange(imp.imp)
+			if err != nil {
+				return nil, err
+			}
+			diag := &Diagnostic{
+				URI:            imp.cgf.URI,
+				Range:          rng,
+				Severity:       protocol.SeverityError,
+				Source:         ListError,
+				Message:        fmt.Sprintf("error while importing %v: %v", pkgPath, depErr.Err),
+				SuggestedFixes: fixes,
+			}
+			if !bundleLazyFixes(diag) {
+				bug.Reportf("failed to bundle fixes for diagnostic %q", diag.Message)
+			}
+			errors = append(errors, diag)
+		}
+	}
+
 	// Add a diagnostic to the module that contained the lowest-level import of
 	// the missing package.
 	for _, depErr := range relevantErrors {
@@ -1877,6 +1928,10 @@ func depsErrors(ctx context.Context, snapshot *Snapshot, mp *metadata.Package) (
 	return errors, nil
 }
 
+// missingGoSumRe matches a go list error for a package whose module
+// has no go.sum entry.
+var missingGoSumRe = regexp.MustCompile(`^missing go.sum entry for module providing package ([^\s]+)`)
+
 // missingPkgError returns an error message for a missing package that varies
 // based on the user's workspace mode.
 func missingPkgError(from PackageID, pkgPath string, viewType ViewType) error {
@@ -2018,12 +2073,13 @@ func typeErrorsToDiagnostics(pkg *syntaxPackage, inputs *typeCheckInputs, errs [
 				bug.Reportf("internal error: could not compute pos to range for %v: %v", e, err)
 				continue
 			}
-			msg := related[0].Msg // primary
+			// Messages about C symbols use their cgo-generated names.
+			msg := DemangleCgo(related[0].Msg) // primary
 			if i > 0 {
 				if inputs.supportsRelatedInformation {
 					msg += " (see details)"
 				} else {
-					msg += fmt.Sprintf(" (this error: %v)", e.Msg)
+					msg += fmt.Sprintf(" (this error: %v)", DemangleCgo(e.Msg))
 				}
 			}
 			diag := &Diagnostic{
@@ -2061,7 +2117,7 @@ func typeErrorsToDiagnostics(pkg *syntaxPackage, inputs *typeCheckInputs, errs [
 				primary := diags[0]
 				primary.Related = append(primary.Related, protocol.DiagnosticRelatedInformation{
 					Location: protocol.Location{URI: diag.URI, Range: diag.Range},
-					Message:  related[i].Msg, // use the unmodified secondary error for related errors.
+					Message:  DemangleCgo(related[i].Msg), // use the unmodified secondary error for related errors.
 				})
 				diag.Related = []protocol.DiagnosticRelatedInformation{{
 					Location: protocol.Location{URI: primary.URI, Range: primary.Range},
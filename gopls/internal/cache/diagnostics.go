@@ -107,12 +107,17 @@ const (
 	ParseError             DiagnosticSource = "syntax"
 	TypeError              DiagnosticSource = "compiler"
 	ModTidyError           DiagnosticSource = "go mod tidy"
+	VendorError            DiagnosticSource = "go mod vendor"
 	CompilerOptDetailsInfo DiagnosticSource = "optimizer details" // cmd/compile -json=0,dir
 	UpgradeNotification    DiagnosticSource = "upgrade available"
 	Vulncheck              DiagnosticSource = "vulncheck imports"
 	Govulncheck            DiagnosticSource = "govulncheck"
 	TemplateError          DiagnosticSource = "template"
 	WorkFileError          DiagnosticSource = "go.work file"
+	SumFileError           DiagnosticSource = "go.sum file"
+	StaleGeneratedFile     DiagnosticSource = "go generate"
+	EditedGeneratedFile    DiagnosticSource = "generated file"
+	LargeFile              DiagnosticSource = "large file"
 )
 
 // A SuggestedFix represents a suggested fix (for a diagnostic)
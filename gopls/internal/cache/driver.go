@@ -0,0 +1,120 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"debug/buildinfo"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file records the health of go/packages drivers (GOPACKAGESDRIVER),
+// such as those of Bazel or Please, whose failures are otherwise opaque.
+
+// maxDriverErrors is the number of recent failures recorded per driver.
+const maxDriverErrors = 10
+
+// DriverStats reports the queries made by a session to an external
+// go/packages driver.
+type DriverStats struct {
+	Driver   string // path of the driver binary
+	Version  string // version of the driver's main module, if it is a Go binary
+	Queries  int    // number of completed queries
+	Failures int    // number of failed queries
+
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+	LastLatency  time.Duration
+
+	Errors []DriverError // most recent failures, oldest first
+}
+
+// MeanLatency returns the mean latency of the driver's queries.
+func (s DriverStats) MeanLatency() time.Duration {
+	if s.Queries == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Queries)
+}
+
+// A DriverError describes a failed query to a go/packages driver.
+type DriverError struct {
+	Time  time.Time
+	View  string   // ID of the view that made the query
+	Query []string // query patterns
+	Err   string   // error, including the driver's stderr output
+}
+
+// A driverMonitor records statistics about the queries made to
+// go/packages drivers.
+type driverMonitor struct {
+	mu    sync.Mutex
+	stats map[string]*DriverStats // by driver path
+}
+
+// record records a query to driver made by the given view.
+func (m *driverMonitor) record(driver, viewID string, query []string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[driver]
+	if !ok {
+		stats = &DriverStats{Driver: driver, Version: driverVersion(driver)}
+		if m.stats == nil {
+			m.stats = make(map[string]*DriverStats)
+		}
+		m.stats[driver] = stats
+	}
+	stats.Queries++
+	stats.TotalLatency += latency
+	stats.MaxLatency = max(stats.MaxLatency, latency)
+	stats.LastLatency = latency
+	if err != nil {
+		stats.Failures++
+		if len(stats.Errors) == maxDriverErrors {
+			stats.Errors = slices.Delete(stats.Errors, 0, 1)
+		}
+		stats.Errors = append(stats.Errors, DriverError{
+			Time:  time.Now(),
+			View:  viewID,
+			Query: query,
+			Err:   err.Error(),
+		})
+	}
+}
+
+// all returns the statistics of all drivers, sorted by path.
+func (m *driverMonitor) all() []DriverStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var all []DriverStats
+	for _, stats := range m.stats {
+		s := *stats
+		s.Errors = slices.Clone(s.Errors)
+		all = append(all, s)
+	}
+	slices.SortFunc(all, func(x, y DriverStats) int {
+		return strings.Compare(x.Driver, y.Driver)
+	})
+	return all
+}
+
+// driverVersion returns the version of the main module of the driver
+// binary, or "" if it is not a Go binary, such as a wrapper script.
+func driverVersion(driver string) string {
+	path, err := exec.LookPath(driver)
+	if err != nil {
+		return ""
+	}
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return info.Main.Path + "@" + info.Main.Version
+}
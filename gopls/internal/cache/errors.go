@@ -41,6 +41,12 @@ func goPackagesErrorDiagnostics(ctx context.Context, e packages.Error, mp *metad
 		return []*Diagnostic{diag}, nil
 	}
 
+	if diags, err := cgoErrorDiagnostics(ctx, e, mp, fs); err != nil {
+		return nil, err
+	} else if len(diags) > 0 {
+		return diags, nil
+	}
+
 	// Parse error location and attempt to convert to protocol form.
 	loc, err := func() (protocol.Location, error) {
 		filename, line, col8 := parseGoListError(e, mp.LoadDir)
@@ -155,6 +161,15 @@ func editGoDirectiveQuickFix(haveModule bool, uri protocol.DocumentURI, version
 	return []SuggestedFix{SuggestedFixFromCommand(cmd, protocol.QuickFix)}
 }
 
+func downloadGoSumQuickFix(uri protocol.DocumentURI, modules []string) []SuggestedFix {
+	title := fmt.Sprintf("go mod download %s", strings.Join(modules, " "))
+	cmd := command.NewDownloadGoSumCommand(title, command.DownloadGoSumArgs{
+		URI:     uri,
+		Modules: modules,
+	})
+	return []SuggestedFix{SuggestedFixFromCommand(cmd, protocol.QuickFix)}
+}
+
 // encodeDiagnostics gob-encodes the given diagnostics.
 func encodeDiagnostics(srcDiags []*Diagnostic) []byte {
 	var gobDiags []gobDiagnostic
@@ -0,0 +1,76 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import "sync/atomic"
+
+// An IndexProgress records the progress of building the type
+// information and indexes (cross-references, method sets, tests, and
+// analysis facts) of a set of packages, such as the workspace packages
+// diagnosed by one pass of the server's diagnostics loop.
+//
+// It is updated by [Snapshot.PackageDiagnostics] and [Snapshot.Analyze],
+// and may be read concurrently by [IndexProgress.Status].
+// A nil *IndexProgress records nothing.
+type IndexProgress struct {
+	packages      atomic.Int64 // number of packages to index
+	typeChecked   atomic.Int64 // number of packages type-checked
+	cached        atomic.Int64 // number of packages whose results were in the file cache
+	analysisTotal atomic.Int64 // number of analysis nodes
+	analysisDone  atomic.Int64 // number of completed analysis nodes
+}
+
+// IndexStatus is a snapshot of the counts of an [IndexProgress].
+type IndexStatus struct {
+	Packages        int // number of packages to index
+	TypeChecked     int // number of packages type-checked
+	Indexed         int // number of packages type-checked or loaded from the file cache
+	AnalysisPending int // number of packages whose analysis is not yet complete
+}
+
+// SetPackages records the number of packages to index.
+func (p *IndexProgress) SetPackages(n int) {
+	if p != nil {
+		p.packages.Store(int64(n))
+	}
+}
+
+// Status returns the current counts of p.
+func (p *IndexProgress) Status() IndexStatus {
+	if p == nil {
+		return IndexStatus{}
+	}
+	typeChecked := int(p.typeChecked.Load())
+	return IndexStatus{
+		Packages:        int(p.packages.Load()),
+		TypeChecked:     typeChecked,
+		Indexed:         typeChecked + int(p.cached.Load()),
+		AnalysisPending: int(p.analysisTotal.Load() - p.analysisDone.Load()),
+	}
+}
+
+func (p *IndexProgress) addTypeChecked() {
+	if p != nil {
+		p.typeChecked.Add(1)
+	}
+}
+
+func (p *IndexProgress) addCached() {
+	if p != nil {
+		p.cached.Add(1)
+	}
+}
+
+func (p *IndexProgress) addAnalysis(total int) {
+	if p != nil {
+		p.analysisTotal.Add(int64(total))
+	}
+}
+
+func (p *IndexProgress) addAnalysisDone() {
+	if p != nil {
+		p.analysisDone.Add(1)
+	}
+}
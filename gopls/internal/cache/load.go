@@ -136,6 +136,10 @@ func (s *Snapshot) load(ctx context.Context, allowNetwork AllowNetwork, scopes .
 		return ctx.Err()
 	}
 
+	if driver := s.view.folder.Env.EffectiveGOPACKAGESDRIVER; driver != "" {
+		s.view.drivers.record(driver, s.view.id, query, time.Since(startTime), err)
+	}
+
 	// This log message is sought for by TestReloadOnlyOnce.
 	{
 		lbls := append(s.Labels(),
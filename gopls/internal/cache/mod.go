@@ -429,19 +429,23 @@ See https://github.com/golang/go/issues/39164 for more detail on this issue.`,
 		tidyCmd := command.NewTidyCommand("Run go mod tidy", command.URIArgs{URIs: args})
 		updateCmd := command.NewUpdateGoSumCommand("Update go.sum", command.URIArgs{URIs: args})
 		msg := "go.sum is out of sync with go.mod. Please update it by applying the quick fix."
+		var fixes []SuggestedFix
 		if innermost != nil {
 			msg = fmt.Sprintf("go.sum is out of sync with go.mod: entry for %v is missing. Please updating it by applying the quick fix.", innermost)
+			// Prefer the targeted fix, which downloads only the
+			// offending module and does not reload the workspace.
+			fixes = append(fixes, downloadGoSumQuickFix(pm.URI, []string{innermost.String()})...)
 		}
+		fixes = append(fixes,
+			SuggestedFixFromCommand(tidyCmd, protocol.QuickFix),
+			SuggestedFixFromCommand(updateCmd, protocol.QuickFix))
 		return &Diagnostic{
-			URI:      pm.URI,
-			Range:    loc.Range,
-			Severity: protocol.SeverityError,
-			Source:   ListError,
-			Message:  msg,
-			SuggestedFixes: []SuggestedFix{
-				SuggestedFixFromCommand(tidyCmd, protocol.QuickFix),
-				SuggestedFixFromCommand(updateCmd, protocol.QuickFix),
-			},
+			URI:            pm.URI,
+			Range:          loc.Range,
+			Severity:       protocol.SeverityError,
+			Source:         ListError,
+			Message:        msg,
+			SuggestedFixes: fixes,
 		}, nil
 	case strings.Contains(goCmdError, "disabled by GOPROXY=off") && innermost != nil:
 		title := fmt.Sprintf("Download %v@%v", innermost.Path, innermost.Version)
@@ -487,3 +491,18 @@ func findModuleReference(mf *modfile.File, ver module.Version) *modfile.Line {
 	}
 	return nil
 }
+
+// findModuleProviding returns the module required by the go.mod file
+// whose path is the longest prefix of pkgPath, or nil if none is found.
+func findModuleProviding(mf *modfile.File, pkgPath string) *module.Version {
+	var best *module.Version
+	for _, req := range mf.Require {
+		if pkgPath != req.Mod.Path && !strings.HasPrefix(pkgPath, req.Mod.Path+"/") {
+			continue
+		}
+		if best == nil || len(req.Mod.Path) > len(best.Path) {
+			best = &req.Mod
+		}
+	}
+	return best
+}
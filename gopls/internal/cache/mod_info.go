@@ -0,0 +1,279 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/memoize"
+)
+
+// A ModuleInfo holds information about a version of a module
+// dependency, obtained from the module proxy and the module cache.
+type ModuleInfo struct {
+	Module module.Version
+
+	// Latest is the latest version of the module, if it is newer than
+	// Module.Version. It has the same major version.
+	Latest string
+
+	// LatestMajor is the latest version of the latest major version of
+	// the module, such as example.com/v3@v3.1.0, if it is newer than
+	// the major version of Module.
+	LatestMajor module.Version
+
+	// Deprecated is the deprecation message of the latest version of
+	// the module, if it is deprecated.
+	Deprecated string
+
+	// Retractions are the versions retracted by the latest version of
+	// the module.
+	Retractions []*modfile.Retract
+
+	// License is the kind of the license of the module, such as "MIT",
+	// or "unknown" if it is not recognized. It is empty if the module
+	// is not in the module cache or has no license file.
+	License string
+}
+
+// Retracted reports whether Module.Version is retracted by the latest
+// version of the module, and if so, the rationale for its retraction.
+func (info *ModuleInfo) Retracted() (rationale string, retracted bool) {
+	for _, r := range info.Retractions {
+		if semver.Compare(r.Low, info.Module.Version) <= 0 && semver.Compare(info.Module.Version, r.High) <= 0 {
+			return r.Rationale, true
+		}
+	}
+	return "", false
+}
+
+// moduleInfoTTL is the duration for which module information is
+// cached. The latest versions of a module change over time, so they
+// must be queried again eventually.
+const moduleInfoTTL = 1 * time.Hour
+
+// A moduleInfoCache holds the module information of a View.
+type moduleInfoCache struct {
+	mu      sync.Mutex
+	entries map[module.Version]*moduleInfoEntry
+}
+
+type moduleInfoEntry struct {
+	promise *memoize.Promise // [moduleInfoResult]
+	created time.Time
+}
+
+type moduleInfoResult struct {
+	info *ModuleInfo
+	err  error
+}
+
+// ModuleInfo returns information about the specified dependency of
+// the go.mod file modURI. The results of queries to the module proxy
+// are cached for an hour, except for errors; modules that match
+// GOPRIVATE are not queried.
+func (s *Snapshot) ModuleInfo(ctx context.Context, modURI protocol.DocumentURI, mod module.Version) (*ModuleInfo, error) {
+	c := &s.view.moduleInfos
+	c.mu.Lock()
+	entry, ok := c.entries[mod]
+	if !ok || time.Since(entry.created) > moduleInfoTTL {
+		dir := modURI.DirPath()
+		entry = &moduleInfoEntry{
+			promise: memoize.NewPromise("moduleInfo", func(ctx context.Context, arg interface{}) interface{} {
+				info, err := moduleInfoImpl(ctx, arg.(*Snapshot), dir, mod)
+				return moduleInfoResult{info, err}
+			}),
+			created: time.Now(),
+		}
+		if c.entries == nil {
+			c.entries = make(map[module.Version]*moduleInfoEntry)
+		}
+		c.entries[mod] = entry
+	}
+	c.mu.Unlock()
+
+	v, err := s.awaitPromise(ctx, entry.promise)
+	if err != nil {
+		return nil, err
+	}
+	res := v.(moduleInfoResult)
+	if res.err != nil {
+		// Don't cache errors, which may be due to transient
+		// conditions such as the lack of a network connection.
+		c.mu.Lock()
+		if c.entries[mod] == entry {
+			delete(c.entries, mod)
+		}
+		c.mu.Unlock()
+	}
+	return res.info, res.err
+}
+
+// moduleInfoImpl queries the module proxy, from the directory of a
+// go.mod file, for information about a dependency.
+func moduleInfoImpl(ctx context.Context, snapshot *Snapshot, dir string, mod module.Version) (*ModuleInfo, error) {
+	ctx, done := event.Start(ctx, "cache.ModuleInfo")
+	defer done()
+
+	info := &ModuleInfo{Module: mod}
+	info.License = moduleLicense(snapshot.view.folder.Env.GOMODCACHE, mod)
+	if snapshot.IsGoPrivatePath(mod.Path) {
+		return info, nil
+	}
+
+	latest, err := queryModule(ctx, snapshot, dir, mod.Path+"@latest")
+	if err != nil {
+		return nil, err
+	}
+	if semver.Compare(latest.Version, mod.Version) > 0 {
+		info.Latest = latest.Version
+	}
+	if latest.GoMod != "" {
+		data, err := os.ReadFile(latest.GoMod)
+		if err != nil {
+			return nil, err
+		}
+		f, err := modfile.ParseLax(latest.GoMod, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if f.Module != nil {
+			info.Deprecated = f.Module.Deprecated
+		}
+		info.Retractions = f.Retract
+	}
+
+	// Query successive major versions until one does not exist.
+	prefix, pathMajor, ok := module.SplitPathVersion(mod.Path)
+	if ok && !strings.HasPrefix(pathMajor, ".") { // not gopkg.in
+		major := 1
+		if pathMajor != "" {
+			major, _ = strconv.Atoi(strings.TrimPrefix(pathMajor, "/v"))
+		}
+		for {
+			major++
+			next, err := queryModule(ctx, snapshot, dir, fmt.Sprintf("%s/v%d@latest", prefix, major))
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				break // no such major version
+			}
+			info.LatestMajor = module.Version{Path: next.Path, Version: next.Version}
+		}
+	}
+	return info, nil
+}
+
+// queryModule runs 'go list -m' for the specified module query.
+func queryModule(ctx context.Context, snapshot *Snapshot, dir, query string) (*moduleQueryResult, error) {
+	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(NetworkOK, dir, "list", []string{"-m", "-json", query}, "GOWORK=off")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanupInvocation()
+	inv.ModFlag = "mod"
+	stdout, err := snapshot.view.gocmdRunner.Run(ctx, *inv)
+	if err != nil {
+		return nil, err
+	}
+	var result moduleQueryResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("decoding go list output for %s: %v", query, err)
+	}
+	return &result, nil
+}
+
+// moduleQueryResult is the subset of the output of 'go list -m -json'
+// used by queryModule.
+type moduleQueryResult struct {
+	Path    string
+	Version string
+	GoMod   string // file containing the go.mod file of the version
+}
+
+// licenseFiles are the names of the files that may hold the license
+// of a module, in order of preference.
+var licenseFiles = []string{
+	"LICENSE", "LICENSE.md", "LICENSE.txt",
+	"LICENCE", "LICENCE.md", "LICENCE.txt",
+	"COPYING", "COPYING.md", "COPYING.txt",
+}
+
+// moduleLicense returns the kind of the license of the module, or ""
+// if the module is not in the module cache or has no license file.
+func moduleLicense(gomodcache string, mod module.Version) string {
+	path, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return ""
+	}
+	version, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(gomodcache, path+"@"+version)
+	for _, name := range licenseFiles {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return classifyLicense(string(data))
+		}
+	}
+	return ""
+}
+
+// licenseKinds associates common licenses with distinctive phrases of
+// their text, in lower case with normalized spacing. The first match
+// wins, so more specific licenses come first.
+var licenseKinds = []struct {
+	kind    string
+	phrases []string // all must occur
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "endorse or promote products derived from this software"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// classifyLicense returns the kind of the license with the given text,
+// such as "MIT", or "unknown".
+func classifyLicense(text string) string {
+	text = spaces.ReplaceAllString(strings.ToLower(text), " ")
+	for _, k := range licenseKinds {
+		matches := true
+		for _, phrase := range k.phrases {
+			if !strings.Contains(text, phrase) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return k.kind
+		}
+	}
+	return "unknown"
+}
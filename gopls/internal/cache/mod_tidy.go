@@ -35,6 +35,8 @@ type TidiedModule struct {
 	Diagnostics []*Diagnostic
 	// The bytes of the go.mod file after it was tidied.
 	TidiedContent []byte
+	// The bytes of the go.sum file after it was tidied.
+	TidiedSum []byte
 }
 
 // ModTidy returns the go.mod file that would be obtained by running
@@ -131,6 +133,11 @@ func modTidyImpl(ctx context.Context, snapshot *Snapshot, pm *ParsedModule) (*Ti
 		return nil, err
 	}
 
+	tempSum, err := os.ReadFile(filepath.Join(tempDir, "go.sum"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
 	// Compare the original and tidied go.mod files to compute errors and
 	// suggested fixes.
 	diagnostics, err := modTidyDiagnostics(ctx, snapshot, pm, ideal)
@@ -141,6 +148,7 @@ func modTidyImpl(ctx context.Context, snapshot *Snapshot, pm *ParsedModule) (*Ti
 	return &TidiedModule{
 		Diagnostics:   diagnostics,
 		TidiedContent: tempContents,
+		TidiedSum:     tempSum,
 	}, nil
 }
 
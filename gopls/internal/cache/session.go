@@ -41,6 +41,7 @@ func NewSession(ctx context.Context, c *Cache) *Session {
 		id:          strconv.FormatInt(index, 10),
 		cache:       c,
 		gocmdRunner: &gocommand.Runner{},
+		drivers:     new(driverMonitor),
 		overlayFS:   newOverlayFS(c),
 		parseCache:  newParseCache(1 * time.Minute), // keep recently parsed files for a minute, to optimize typing CPU
 		viewMap:     make(map[protocol.DocumentURI]*View),
@@ -60,6 +61,7 @@ type Session struct {
 	// Immutable attributes shared across views.
 	cache       *Cache            // shared cache
 	gocmdRunner *gocommand.Runner // limits go command concurrency
+	drivers     *driverMonitor    // records queries to go/packages drivers
 
 	viewMu  sync.Mutex
 	views   []*View
@@ -136,7 +138,7 @@ func (s *Session) NewView(ctx context.Context, folder *Folder) (*View, *Snapshot
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	view, snapshot, release := s.createView(ctx, def)
+	view, snapshot, release := s.createView(ctx, def, false)
 	s.views = append(s.views, view)
 	// we always need to drop the view map
 	s.viewMap = make(map[protocol.DocumentURI]*View)
@@ -146,8 +148,11 @@ func (s *Session) NewView(ctx context.Context, folder *Folder) (*View, *Snapshot
 // createView creates a new view, with an initial snapshot that retains the
 // supplied context, detached from events and cancelation.
 //
+// If dormant is set, the initial workspace load of the view is deferred
+// until its initialization is first awaited.
+//
 // The caller is responsible for calling the release function once.
-func (s *Session) createView(ctx context.Context, def *viewDefinition) (*View, *Snapshot, func()) {
+func (s *Session) createView(ctx context.Context, def *viewDefinition, dormant bool) (*View, *Snapshot, func()) {
 	index := atomic.AddInt64(&viewIndex, 1)
 
 	// We want a true background context and not a detached context here
@@ -227,6 +232,7 @@ func (s *Session) createView(ctx context.Context, def *viewDefinition) (*View, *
 	v := &View{
 		id:                   strconv.FormatInt(index, 10),
 		gocmdRunner:          s.gocmdRunner,
+		drivers:              s.drivers,
 		initialWorkspaceLoad: make(chan struct{}),
 		initializationSema:   make(chan struct{}, 1),
 		baseCtx:              baseCtx,
@@ -237,6 +243,10 @@ func (s *Session) createView(ctx context.Context, def *viewDefinition) (*View, *
 		viewDefinition:       def,
 		importsState:         newImportsState(backgroundCtx, s.cache.modCache, pe),
 	}
+	v.markUsed()
+	if dormant {
+		v.wake = make(chan struct{})
+	}
 	if def.folder.Options.ImportsSource != "off" {
 		v.modcacheState = newModcacheState(def.folder.Env.GOMODCACHE)
 	}
@@ -287,12 +297,29 @@ func (s *Session) createView(ctx context.Context, def *viewDefinition) (*View, *
 	v.cancelInitialWorkspaceLoad = initCancel
 	snapshot := v.snapshot
 
-	// Pass a second reference to the background goroutine.
-	bgRelease := snapshot.Acquire()
-	go func() {
-		defer bgRelease()
-		snapshot.initialize(initCtx, true)
-	}()
+	if dormant {
+		go func() {
+			select {
+			case <-v.wake:
+			case <-initCtx.Done():
+				return // view was shut down
+			}
+			// Initialize the latest snapshot, as the first may be long gone.
+			snapshot, release, err := v.Snapshot()
+			if err != nil {
+				return
+			}
+			defer release()
+			snapshot.initialize(initCtx, true)
+		}()
+	} else {
+		// Pass a second reference to the background goroutine.
+		bgRelease := snapshot.Acquire()
+		go func() {
+			defer bgRelease()
+			snapshot.initialize(initCtx, true)
+		}()
+	}
 
 	// Return a third reference to the caller.
 	return v, snapshot, snapshot.Acquire()
@@ -336,6 +363,61 @@ func (s *Session) RemoveView(ctx context.Context, dir protocol.DocumentURI) bool
 	return removed > 0
 }
 
+// EvictIdleViews discards the state of each view of the session that has
+// not been used for at least the given duration, by replacing it with a
+// dormant view of the same definition, whose initial workspace load is
+// deferred until it is next used. It returns the number of views evicted.
+func (s *Session) EvictIdleViews(ctx context.Context, idle time.Duration) int {
+	s.viewMu.Lock()
+	defer s.viewMu.Unlock()
+
+	if s.viewMap == nil {
+		return 0 // Session is shut down.
+	}
+
+	cutoff := time.Now().Add(-idle).UnixNano()
+	evicted := 0
+	for i, v := range s.views {
+		if v.dormant() || v.lastUsed.Load() > cutoff {
+			continue
+		}
+		v2, _, release := s.createView(ctx, v.viewDefinition, true)
+		release() // don't need the snapshot
+		v.shutdown()
+		s.views[i] = v2
+		evicted++
+	}
+	if evicted > 0 {
+		s.viewMap = make(map[protocol.DocumentURI]*View) // reset view associations
+	}
+	return evicted
+}
+
+// Footprint returns the number of views of the session and the total
+// number of Go files in the packages they have loaded, which is a rough
+// measure of the memory the session requires.
+func (s *Session) Footprint() (views, files int) {
+	for _, v := range s.Views() {
+		snapshot, release, err := v.Snapshot()
+		if err != nil {
+			continue // view is shut down
+		}
+		views++
+		// MetadataGraph does not await loading, so this is not a use of the view.
+		for _, mp := range snapshot.MetadataGraph().Packages {
+			files += len(mp.CompiledGoFiles)
+		}
+		release()
+	}
+	return views, files
+}
+
+// DriverStats returns statistics about the queries made by the session
+// to go/packages drivers, by driver.
+func (s *Session) DriverStats() []DriverStats {
+	return s.drivers.all()
+}
+
 // View returns the view with a matching id, if present.
 func (s *Session) View(id string) (*View, error) {
 	s.viewMu.Lock()
@@ -504,6 +586,13 @@ func selectViewDefs(ctx context.Context, fs file.Source, folders []*Folder, open
 		defs = append(defs, def)
 	}
 
+	// Next, add a view for each additional build configuration requested by
+	// the "buildConfigurations" setting. These follow the default views so
+	// that the default configuration is preferred when both match a file.
+	for _, def := range slices.Clone(defs) {
+		defs = append(defs, configurationViewDefs(def)...)
+	}
+
 	// Next, ensure that the set of views covers all open files contained in a
 	// workspace folder.
 	//
@@ -574,6 +663,36 @@ checkFiles:
 	return defs, nil
 }
 
+// configurationViewDefs returns the definitions of views that are
+// copies of def with GOOS and GOARCH set according to each of the
+// folder's additional build configurations. Configurations that match
+// the port of def are skipped.
+func configurationViewDefs(def *viewDefinition) []*viewDefinition {
+	switch def.Type() {
+	case GoModView, GoWorkView, GOPATHView:
+	default:
+		return nil // configuration is controlled by the driver or by the file
+	}
+	var defs []*viewDefinition
+	for _, config := range def.folder.Options.BuildConfigurations {
+		goos, goarch, _ := strings.Cut(config, "/") // validated by settings
+		if goos == def.GOOS() && goarch == def.GOARCH() {
+			continue
+		}
+		def2 := *def // shallow copy
+		def2.envOverlay = maps.Clone(def.envOverlay)
+		if def2.envOverlay == nil {
+			def2.envOverlay = make(map[string]string)
+		}
+		def2.envOverlay["GOOS"] = goos
+		def2.envOverlay["GOARCH"] = goarch
+		if !slices.ContainsFunc(defs, func(d *viewDefinition) bool { return viewDefinitionsEqual(d, &def2) }) {
+			defs = append(defs, &def2)
+		}
+	}
+	return defs
+}
+
 // The viewDefiner interface allows the [RelevantViews] algorithm to operate on both
 // Views and viewDefinitions.
 type viewDefiner interface{ definition() *viewDefinition }
@@ -731,7 +850,7 @@ func (s *Session) ResetView(ctx context.Context, uri protocol.DocumentURI) (*Vie
 	s.viewMap = make(map[protocol.DocumentURI]*View)
 	for i, v := range s.views {
 		if v == view {
-			v2, _, release := s.createView(ctx, view.viewDefinition)
+			v2, _, release := s.createView(ctx, view.viewDefinition, false)
 			release() // don't need the snapshot
 			v.shutdown()
 			s.views[i] = v2
@@ -872,7 +991,7 @@ func (s *Session) DidModifyFiles(ctx context.Context, modifications []file.Modif
 					}
 				}
 				if newView == nil {
-					v, _, release := s.createView(ctx, def)
+					v, _, release := s.createView(ctx, def, false)
 					release()
 					newView = v
 				}
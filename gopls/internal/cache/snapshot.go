@@ -7,6 +7,7 @@ package cache
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -323,6 +324,8 @@ func fileKind(fh file.Handle) file.Kind {
 		return file.Sum
 	case ".work":
 		return file.Work
+	case ".s":
+		return file.Asm
 	}
 	return file.UnknownKind
 }
@@ -521,14 +524,15 @@ const (
 	diagnosticsKind = "diagnostics"
 	typerefsKind    = "typerefs"
 	symbolsKind     = "symbols"
+	apiKind         = "api"
 )
 
 // PackageDiagnostics returns diagnostics for files contained in specified
 // packages.
 //
 // If these diagnostics cannot be loaded from cache, the requested packages
-// may be type-checked.
-func (s *Snapshot) PackageDiagnostics(ctx context.Context, ids ...PackageID) (map[protocol.DocumentURI][]*Diagnostic, error) {
+// may be type-checked. The optional progress records how many were.
+func (s *Snapshot) PackageDiagnostics(ctx context.Context, progress *IndexProgress, ids ...PackageID) (map[protocol.DocumentURI][]*Diagnostic, error) {
 	ctx, done := event.Start(ctx, "cache.snapshot.PackageDiagnostics")
 	defer done()
 
@@ -544,6 +548,7 @@ func (s *Snapshot) PackageDiagnostics(ctx context.Context, ids ...PackageID) (ma
 	pre := func(_ int, ph *packageHandle) bool {
 		data, err := filecache.Get(diagnosticsKind, ph.key)
 		if err == nil { // hit
+			progress.addCached()
 			collect(ph.loadDiagnostics)
 			collect(decodeDiagnostics(data))
 			return false
@@ -553,6 +558,7 @@ func (s *Snapshot) PackageDiagnostics(ctx context.Context, ids ...PackageID) (ma
 		return true
 	}
 	post := func(_ int, pkg *Package) {
+		progress.addTypeChecked()
 		collect(pkg.loadDiagnostics)
 		collect(pkg.pkg.diagnostics)
 	}
@@ -562,11 +568,20 @@ func (s *Snapshot) PackageDiagnostics(ctx context.Context, ids ...PackageID) (ma
 // References returns cross-reference indexes for the specified packages.
 //
 // If these indexes cannot be loaded from cache, the requested packages may
-// be type-checked.
+// be type-checked. An index is loaded from the cache even if the
+// dependencies of its package have changed since it was computed,
+// so long as their APIs have not; confirming this may require the
+// dependencies, but not the requested packages, to be type-checked.
 func (s *Snapshot) References(ctx context.Context, ids ...PackageID) ([]xrefIndex, error) {
 	ctx, done := event.Start(ctx, "cache.snapshot.References")
 	defer done()
 
+	// Join the type-checking batch in advance, so that the pre func,
+	// which is called within forEachPackage, may use it to compute
+	// the API hashes of dependencies.
+	b, release := s.acquireTypeChecking()
+	defer release()
+
 	indexes := make([]xrefIndex, len(ids))
 	pre := func(i int, ph *packageHandle) bool {
 		data, err := filecache.Get(xrefsKind, ph.key)
@@ -576,6 +591,17 @@ func (s *Snapshot) References(ctx context.Context, ids ...PackageID) ([]xrefInde
 		} else if err != filecache.ErrNotFound {
 			event.Error(ctx, "reading xrefs from filecache", err)
 		}
+
+		// Look for an index computed for dependencies with the same API.
+		if key, err := b.xrefsKey(ctx, ph); err == nil {
+			data, err := filecache.Get(xrefsKind, key)
+			if err == nil { // hit
+				indexes[i] = xrefIndex{mp: ph.mp, data: data}
+				return false
+			} else if err != filecache.ErrNotFound {
+				event.Error(ctx, "reading xrefs from filecache", err)
+			}
+		}
 		return true
 	}
 	post := func(i int, pkg *Package) {
@@ -594,6 +620,12 @@ func (index xrefIndex) Lookup(targets map[PackagePath]map[objectpath.Path]struct
 	return xrefs.Lookup(index.mp, index.data, targets)
 }
 
+// Referenced returns the set of symbols in other packages referenced
+// by the indexed package, grouped by package path.
+func (index xrefIndex) Referenced() map[PackagePath]map[objectpath.Path]struct{} {
+	return xrefs.Referenced(index.data)
+}
+
 // MethodSets returns method-set indexes for the specified packages.
 //
 // If these indexes cannot be loaded from cache, the requested packages may
@@ -1166,6 +1198,10 @@ func (s *Snapshot) awaitLoaded(ctx context.Context) error {
 
 // AwaitInitialized waits until the snapshot's view is initialized.
 func (s *Snapshot) AwaitInitialized(ctx context.Context) {
+	s.view.markUsed()
+	if s.view.wake != nil {
+		s.view.wakeOnce.Do(func() { close(s.view.wake) })
+	}
 	select {
 	case <-ctx.Done():
 		return
@@ -1174,6 +1210,8 @@ func (s *Snapshot) AwaitInitialized(ctx context.Context) {
 	// We typically prefer to run something as intensive as the IWL without
 	// blocking. I'm not sure if there is a way to do that here.
 	s.initialize(ctx, false)
+	// Loading may be slow: the view is in use until it completes.
+	s.view.markUsed()
 }
 
 // reloadWorkspace reloads the metadata for all invalidated workspace packages.
@@ -1253,7 +1291,7 @@ searchOverlays:
 		// may be loaded by multiple views. If they were to be diagnosed by
 		// multiple views, their diagnostics may become inconsistent.
 		if len(mps) > 0 {
-			diags, err := s.PackageDiagnostics(ctx, mps[0].ID)
+			diags, err := s.PackageDiagnostics(ctx, nil, mps[0].ID)
 			if err != nil {
 				return nil, err
 			}
@@ -1609,6 +1647,7 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 	}
 
 	// Finally, process sumfile changes that may affect loading.
+	sumEntriesAdded := false // go.sum entries were added, without reinitialization
 	for uri, newFH := range changedFiles {
 		if !changedOnDisk(oldFiles[uri], newFH) {
 			continue // like with go.mod files, we only reinit when things change on disk
@@ -1620,7 +1659,15 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 		if base == "go.sum" {
 			modURI := protocol.URIFromPath(filepath.Join(dir, "go.mod"))
 			if _, active := result.view.workspaceModFiles[modURI]; active {
-				reinit = true
+				// Adding entries to go.sum (for example, with
+				// 'go mod download') cannot change the module graph,
+				// so unless initialization failed, only packages that
+				// could not be loaded need to be reloaded.
+				if s.initialErr == nil && onlyAddsLines(oldFiles[uri], newFH) {
+					sumEntriesAdded = true
+				} else {
+					reinit = true
+				}
 			}
 		}
 	}
@@ -1764,6 +1811,25 @@ func (s *Snapshot) clone(ctx, bgCtx context.Context, changed StateChange, done f
 		}
 	}
 
+	// Added go.sum entries may resolve errors loading packages or their
+	// dependencies. As above, rather than guess which ones, invalidate
+	// metadata for any package with errors or missing dependencies.
+	if sumEntriesAdded {
+		for id, mp := range s.meta.Packages {
+			if len(mp.Errors) > 0 || len(mp.DepsErrors) > 0 {
+				directIDs[id] = true
+				continue
+			}
+			for _, impID := range mp.DepsByImpPath {
+				if impID == "" { // missing import
+					directIDs[id] = true
+					break
+				}
+			}
+		}
+		needsDiagnosis = true
+	}
+
 	// Invalidate reverse dependencies too.
 	// idsToInvalidate keeps track of transitive reverse dependencies.
 	// If an ID is present in the map, invalidate its types.
@@ -1985,6 +2051,33 @@ func fileWasSaved(originalFH, currentFH file.Handle) bool {
 	return !o.saved && c.saved
 }
 
+// onlyAddsLines reports whether the content of newFH is that of oldFH
+// with zero or more lines added. A missing file has no lines.
+func onlyAddsLines(oldFH, newFH file.Handle) bool {
+	var oldContent []byte
+	if oldFH != nil && fileExists(oldFH) {
+		content, err := oldFH.Content()
+		if err != nil {
+			return false
+		}
+		oldContent = content
+	}
+	newContent, err := newFH.Content()
+	if err != nil {
+		return false
+	}
+	have := make(map[string]bool)
+	for _, line := range strings.Split(string(newContent), "\n") {
+		have[line] = true
+	}
+	for _, line := range strings.Split(string(oldContent), "\n") {
+		if !have[line] {
+			return false
+		}
+	}
+	return true
+}
+
 // metadataChanges detects features of the change from oldFH->newFH that may
 // affect package metadata.
 //
@@ -2177,6 +2270,42 @@ func (s *Snapshot) WantCompilerOptDetails(dir protocol.DocumentURI) bool {
 	return ok
 }
 
+// CompilerOptDetailsKey returns a key that identifies the build of
+// the packages and tests in the given directory, for use in caching
+// the compiler's optimization details.
+//
+// The key is derived from the keys of the package handles, which
+// reflect the source of each package and of its dependencies, plus
+// the Go version, environment, and build flags.
+func (s *Snapshot) CompilerOptDetailsKey(ctx context.Context, dir protocol.DocumentURI) (file.Hash, error) {
+	var ids []PackageID
+	for id, mp := range s.MetadataGraph().Packages {
+		if len(mp.CompiledGoFiles) > 0 && mp.CompiledGoFiles[0].Dir() == dir && !mp.IsIntermediateTestVariant() {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return file.Hash{}, fmt.Errorf("no packages in %s", dir)
+	}
+	slices.Sort(ids)
+	handles, err := s.getPackageHandles(ctx, ids)
+	if err != nil {
+		return file.Hash{}, err
+	}
+
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "compileropt: %s\n", dir)
+	fmt.Fprintf(hasher, "go: %s\n", s.view.folder.Env.GoVersionOutput)
+	fmt.Fprintf(hasher, "env: %s %s %v\n", s.view.folder.Env.GOOS, s.view.folder.Env.GOARCH, s.Options().Env)
+	fmt.Fprintf(hasher, "flags: %q\n", s.Options().BuildFlags)
+	for _, id := range ids {
+		fmt.Fprintf(hasher, "package %s %s\n", id, handles[id].key)
+	}
+	var hash file.Hash
+	hasher.Sum(hash[:0])
+	return hash, nil
+}
+
 // A CodeLensSourceFunc is a function that reports CodeLenses (range-associated
 // commands) for a given file.
 type CodeLensSourceFunc func(context.Context, *Snapshot, file.Handle) ([]protocol.CodeLens, error)
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/tools/gopls/internal/cache/typerefs"
@@ -101,6 +102,7 @@ type View struct {
 	*viewDefinition // build configuration
 
 	gocmdRunner *gocommand.Runner // limits go command concurrency
+	drivers     *driverMonitor    // records queries to go/packages drivers
 
 	// baseCtx is the context handed to NewView. This is the parent of all
 	// background contexts created for this view.
@@ -118,6 +120,10 @@ type View struct {
 	// parseCache holds an LRU cache of recently parsed files.
 	parseCache *parseCache
 
+	// moduleInfos caches information about module dependencies from
+	// the module proxy.
+	moduleInfos moduleInfoCache
+
 	// fs is the file source used to populate this view.
 	fs *overlayFS
 
@@ -136,6 +142,16 @@ type View struct {
 	// to avoid too many go/packages calls.
 	initialWorkspaceLoad chan struct{}
 
+	// wake, if non-nil, is closed to start the initial workspace load of a
+	// dormant view (see [Session.EvictIdleViews]), which is deferred until
+	// the view's initialization is first awaited.
+	wake     chan struct{}
+	wakeOnce sync.Once
+
+	// lastUsed is the time, in Unix nanoseconds, at which the view was last
+	// used to serve a request.
+	lastUsed atomic.Int64
+
 	// initializationSema is used limit concurrent initialization of snapshots in
 	// the view. We use a channel instead of a mutex to avoid blocking when a
 	// context is canceled.
@@ -233,6 +249,15 @@ func (d *viewDefinition) GOARCH() string {
 	return d.folder.Env.GOARCH
 }
 
+// GOFLAGS returns the effective GOFLAGS value for this view definition,
+// accounting for its env overlay.
+func (d *viewDefinition) GOFLAGS() string {
+	if goflags, ok := d.envOverlay["GOFLAGS"]; ok {
+		return goflags
+	}
+	return d.folder.Env.GOFLAGS
+}
+
 // adjustedGO111MODULE is the value of GO111MODULE to use for loading packages.
 // It is adjusted to default to "auto" rather than "on", since if we are in
 // GOPATH and have no module, we may as well allow a GOPATH view to work.
@@ -389,7 +414,7 @@ func (s *Session) UpdateFolders(ctx context.Context, newFolders []*Folder) error
 	}
 	var newViews []*View
 	for _, def := range defs {
-		v, _, release := s.createView(ctx, def)
+		v, _, release := s.createView(ctx, def, false)
 		release()
 		newViews = append(newViews, v)
 	}
@@ -487,6 +512,25 @@ func (v *View) filterFunc() func(protocol.DocumentURI) bool {
 	return v._filterFunc
 }
 
+// markUsed records that the view is in use.
+func (v *View) markUsed() {
+	v.lastUsed.Store(time.Now().UnixNano())
+}
+
+// dormant reports whether the view is dormant: that is, whether its
+// initial workspace load is deferred until its initialization is awaited.
+func (v *View) dormant() bool {
+	if v.wake == nil {
+		return false
+	}
+	select {
+	case <-v.wake:
+		return false
+	default:
+		return true
+	}
+}
+
 // shutdown releases resources associated with the view.
 func (v *View) shutdown() {
 	// Cancel the initial workspace load if it is still running.
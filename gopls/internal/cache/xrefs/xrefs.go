@@ -162,6 +162,24 @@ func Lookup(mp *metadata.Package, data []byte, targets map[metadata.PackagePath]
 	return locs
 }
 
+// Referenced returns the set of symbols referenced by the package
+// whose serialized index is data, grouped by the path of the
+// package that defines them. Each symbol is denoted by its object
+// path; the empty path denotes an import of the package itself.
+func Referenced(data []byte) map[metadata.PackagePath]map[objectpath.Path]struct{} {
+	var packages []*gobPackage
+	packageCodec.Decode(data, &packages)
+	result := make(map[metadata.PackagePath]map[objectpath.Path]struct{}, len(packages))
+	for _, gp := range packages {
+		objectSet := make(map[objectpath.Path]struct{}, len(gp.Objects))
+		for _, gobObj := range gp.Objects {
+			objectSet[gobObj.Path] = struct{}{}
+		}
+		result[gp.PkgPath] = objectSet
+	}
+	return result
+}
+
 // -- serialized representation --
 
 // The cross-reference index records the location of all references
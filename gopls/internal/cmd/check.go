@@ -6,10 +6,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"slices"
+	"strings"
 
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/settings"
 )
@@ -17,21 +22,52 @@ import (
 // check implements the check verb for gopls.
 type check struct {
 	app *Application
+
+	JSON  bool `flag:"json" help:"emit diagnostics in JSON format"`
+	Types bool `flag:"types" help:"report only syntax and type errors, and fail if there are any"`
 }
 
-func (c *check) Name() string      { return "check" }
-func (c *check) Parent() string    { return c.app.Name() }
-func (c *check) Usage() string     { return "<filename>" }
-func (c *check) ShortHelp() string { return "show diagnostic results for the specified file" }
+func (c *check) Name() string   { return "check" }
+func (c *check) Parent() string { return c.app.Name() }
+func (c *check) Usage() string  { return "[check-flags] <filename or package pattern>..." }
+func (c *check) ShortHelp() string {
+	return "show diagnostic results for the specified files or packages"
+}
 func (c *check) DetailedHelp(f *flag.FlagSet) {
 	fmt.Fprint(f.Output(), `
 Example: show the diagnostic results of this file:
 
 	$ gopls check internal/cmd/check.go
+
+An argument that is not a Go file name is a package pattern, as
+accepted by 'go list', denoting the Go files of the matching packages,
+including their tests:
+
+	$ gopls check ./...
+
+The -types flag disables all analyzers, so that only syntax and type
+errors are reported; in that case, check exits with a non-zero status
+if it reports any errors. Combined with the file cache of gopls, which
+holds the type information of unchanged dependencies, this makes for a
+quick check that the code compiles:
+
+	$ gopls check -types ./...
+
+check-flags:
 `)
 	printFlagDefaults(f)
 }
 
+// A checkDiagnostic is a diagnostic reported by the check verb,
+// in the form of its JSON output.
+type checkDiagnostic struct {
+	Span     span              `json:"span"`
+	Severity string            `json:"severity,omitempty"`
+	Source   string            `json:"source,omitempty"`
+	Message  string            `json:"message"`
+	Related  []checkDiagnostic `json:"related,omitempty"` // related information; only Span and Message are set
+}
+
 // Run performs the check on the files specified by args and prints the
 // results to stdout.
 func (c *check) Run(ctx context.Context, args ...string) error {
@@ -39,6 +75,11 @@ func (c *check) Run(ctx context.Context, args ...string) error {
 		return nil
 	}
 
+	filenames, err := expandPatterns(args)
+	if err != nil {
+		return err
+	}
+
 	// TODO(adonovan): formally, we are required to set this
 	// option if we want RelatedInformation, but it appears to
 	// have no effect on the server, even though the default is
@@ -49,6 +90,13 @@ func (c *check) Run(ctx context.Context, args ...string) error {
 			origOptions(opts)
 		}
 		opts.RelatedInformationSupported = true
+		if c.Types {
+			opts.Staticcheck = false
+			opts.Analyses = make(map[string]bool)
+			for name := range settings.DefaultAnalyzers {
+				opts.Analyses[name] = false
+			}
+		}
 	}
 
 	conn, err := c.app.connect(ctx)
@@ -62,8 +110,11 @@ func (c *check) Run(ctx context.Context, args ...string) error {
 		uris     []protocol.DocumentURI
 		checking = make(map[protocol.DocumentURI]*cmdFile)
 	)
-	for _, arg := range args {
-		uri := protocol.URIFromPath(arg)
+	for _, filename := range filenames {
+		uri := protocol.URIFromPath(filename)
+		if checking[uri] != nil {
+			continue
+		}
 		uris = append(uris, uri)
 		file, err := conn.openFile(ctx, uri)
 		if err != nil {
@@ -75,36 +126,139 @@ func (c *check) Run(ctx context.Context, args ...string) error {
 		return err
 	}
 
-	// print prints a single element of a diagnostic.
-	print := func(uri protocol.DocumentURI, rng protocol.Range, message string) error {
+	// convert converts a single element of a diagnostic.
+	convert := func(uri protocol.DocumentURI, rng protocol.Range, message string) (checkDiagnostic, error) {
 		file, err := conn.openFile(ctx, uri)
 		if err != nil {
-			return err
+			return checkDiagnostic{}, err
 		}
 		spn, err := file.rangeSpan(rng)
 		if err != nil {
-			return fmt.Errorf("could not convert position %v for %q", rng, message)
+			return checkDiagnostic{}, fmt.Errorf("could not convert position %v for %q", rng, message)
 		}
-		fmt.Printf("%v: %v\n", spn, message)
-		return nil
+		return checkDiagnostic{Span: spn, Message: message}, nil
 	}
 
-	for _, file := range checking {
+	// Gather the diagnostics, in order of file name and position.
+	slices.Sort(uris)
+	results := []checkDiagnostic{} // (non-nil, for JSON)
+	for _, uri := range uris {
+		file := checking[uri]
 		file.diagnosticsMu.Lock()
 		diags := slices.Clone(file.diagnostics)
 		file.diagnosticsMu.Unlock()
 
+		slices.SortStableFunc(diags, func(x, y protocol.Diagnostic) int {
+			return protocol.CompareRange(x.Range, y.Range)
+		})
 		for _, diag := range diags {
-			if err := print(file.uri, diag.Range, diag.Message); err != nil {
+			if c.Types && !isTypeCheckSource(diag.Source) {
+				continue
+			}
+			result, err := convert(file.uri, diag.Range, diag.Message)
+			if err != nil {
 				return err
 			}
+			result.Severity = severityName(diag.Severity)
+			result.Source = diag.Source
 			for _, rel := range diag.RelatedInformation {
-				if err := print(rel.Location.URI, rel.Location.Range, "- "+rel.Message); err != nil {
+				related, err := convert(rel.Location.URI, rel.Location.Range, rel.Message)
+				if err != nil {
 					return err
 				}
+				result.Related = append(result.Related, related)
 			}
+			results = append(results, result)
+		}
+	}
 
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			fmt.Printf("%v: %v\n", result.Span, result.Message)
+			for _, rel := range result.Related {
+				fmt.Printf("%v: - %v\n", rel.Span, rel.Message)
+			}
 		}
 	}
+
+	if c.Types && len(results) > 0 {
+		noun := "errors"
+		if len(results) == 1 {
+			noun = "error"
+		}
+		return fmt.Errorf("%d %s", len(results), noun)
+	}
 	return nil
 }
+
+// expandPatterns returns the file names denoted by the command-line
+// arguments, each of which is either a Go file name or a package
+// pattern. The files of a package pattern are those of the matching
+// packages and their tests.
+func expandPatterns(args []string) ([]string, error) {
+	var (
+		filenames []string
+		patterns  []string
+	)
+	for _, arg := range args {
+		if strings.HasSuffix(arg, ".go") {
+			filenames = append(filenames, arg)
+		} else {
+			patterns = append(patterns, arg)
+		}
+	}
+	if len(patterns) == 0 {
+		return filenames, nil
+	}
+
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles,
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matching %s", strings.Join(patterns, " "))
+	}
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue // test main package, whose files are generated
+		}
+		filenames = append(filenames, pkg.GoFiles...)
+	}
+	return filenames, nil
+}
+
+// isTypeCheckSource reports whether the diagnostic source denotes a
+// syntax or type error, or an error of the build system that prevents
+// type checking, such as a missing import.
+func isTypeCheckSource(source string) bool {
+	switch cache.DiagnosticSource(source) {
+	case cache.ParseError, cache.TypeError, cache.ListError:
+		return true
+	}
+	return false
+}
+
+// severityName returns the name of a diagnostic severity.
+func severityName(severity protocol.DiagnosticSeverity) string {
+	switch severity {
+	case protocol.SeverityError:
+		return "error"
+	case protocol.SeverityWarning:
+		return "warning"
+	case protocol.SeverityInformation:
+		return "information"
+	case protocol.SeverityHint:
+		return "hint"
+	}
+	return ""
+}
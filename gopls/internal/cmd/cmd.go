@@ -66,6 +66,9 @@ type Application struct {
 	// Control ocagent export of telemetry
 	OCAgent string `flag:"ocagent" help:"the address of the ocagent (e.g. http://localhost:55678), or off"`
 
+	// Control export of traces to an OpenTelemetry collector
+	OTLP string `flag:"otlp" help:"the address of an OpenTelemetry collector to which to export traces (e.g. http://localhost:4318), or off; by default, that of $OTEL_EXPORTER_OTLP_ENDPOINT, if set"`
+
 	// PrepareOptions is called to update the options when a new view is built.
 	// It is primarily to allow the behavior of gopls to be modified by hooks.
 	PrepareOptions func(*settings.Options)
@@ -102,6 +105,7 @@ func New() *Application {
 
 		Serve: Serve{
 			RemoteListenTimeout: 1 * time.Minute,
+			RemoteViewTimeout:   30 * time.Minute,
 		},
 	}
 	app.Serve.app = app
@@ -238,7 +242,7 @@ func (app *Application) Run(ctx context.Context, args ...string) error {
 	// executable, and immediately runs a gc.
 	filecache.Start()
 
-	ctx = debug.WithInstance(ctx, app.OCAgent)
+	ctx = debug.WithInstance(ctx, app.OCAgent, app.OTLP)
 	if len(args) == 0 {
 		s := flag.NewFlagSet(app.Name(), flag.ExitOnError)
 		return tool.Run(ctx, s, &app.Serve, args)
@@ -298,6 +302,9 @@ func (app *Application) featureCommands() []tool.Application {
 		newRemote(app, ""),
 		newRemote(app, "inspect"),
 		&links{app: app},
+		&newModule{app: app},
+		&peers{app: app},
+		&pointsto{app: app},
 		&prepareRename{app: app},
 		&references{app: app},
 		&rename{app: app},
@@ -305,6 +312,7 @@ func (app *Application) featureCommands() []tool.Application {
 		&signature{app: app},
 		&stats{app: app},
 		&symbols{app: app},
+		&unusedExports{app: app},
 
 		&workspaceSymbol{app: app},
 	}
@@ -59,6 +59,7 @@ Valid kinds include:
 	refactor.inline
 	refactor.inline.call
 	refactor.rewrite
+	refactor.rewrite.addFuzzSeed
 	refactor.rewrite.changeQuote
 	refactor.rewrite.fillStruct
 	refactor.rewrite.fillSwitch
@@ -0,0 +1,131 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"golang.org/x/tools/gopls/internal/flowquery"
+	"golang.org/x/tools/internal/tool"
+)
+
+// pointsto implements the pointsto verb for gopls.
+type pointsto struct {
+	app *Application
+}
+
+func (p *pointsto) Name() string   { return "pointsto" }
+func (p *pointsto) Parent() string { return p.app.Name() }
+func (p *pointsto) Usage() string  { return "<position>" }
+func (p *pointsto) ShortHelp() string {
+	return "display the concrete types that may flow to an interface value"
+}
+func (p *pointsto) DetailedHelp(f *flag.FlagSet) {
+	fmt.Fprint(f.Output(), `
+The pointsto command reports, as JSON, the concrete types that may flow
+to the value of the interface-typed expression at the specified position.
+
+Unlike most gopls commands, it does not start a server: it loads the
+entire module containing the file from source and analyzes it using
+Variable Type Analysis (VTA), which may take some time.
+
+Example:
+
+	$ # 1-indexed location (:line:column or :#offset) of the target expression
+	$ gopls pointsto helper/helper.go:8:6
+	$ gopls pointsto helper/helper.go:#53
+`)
+	printFlagDefaults(f)
+}
+
+func (p *pointsto) Run(ctx context.Context, args ...string) error {
+	if len(args) != 1 {
+		return tool.CommandLineErrorf("pointsto expects 1 argument (position)")
+	}
+	pos, err := flowPosition(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := flowquery.PointsTo(ctx, pos)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+// peers implements the peers verb for gopls.
+type peers struct {
+	app *Application
+}
+
+func (p *peers) Name() string   { return "peers" }
+func (p *peers) Parent() string { return p.app.Name() }
+func (p *peers) Usage() string  { return "<position>" }
+func (p *peers) ShortHelp() string {
+	return "display the operations on channels that may alias a channel"
+}
+func (p *peers) DetailedHelp(f *flag.FlagSet) {
+	fmt.Fprint(f.Output(), `
+The peers command reports, as JSON, the make(chan) calls and the send,
+receive, and close operations of the channels that may alias the
+channel of the operation ('<-' or close) at the specified position.
+
+Like pointsto, it loads the entire module containing the file and
+analyzes it using Variable Type Analysis (VTA). VTA does not
+distinguish channels of the same element type; if the module has main
+packages, only the operations in functions reachable from them are
+reported.
+
+Example:
+
+	$ # 1-indexed location (:line:column or :#offset) of the '<-' operator
+	$ gopls peers helper/helper.go:8:6
+	$ gopls peers helper/helper.go:#53
+`)
+	printFlagDefaults(f)
+}
+
+func (p *peers) Run(ctx context.Context, args ...string) error {
+	if len(args) != 1 {
+		return tool.CommandLineErrorf("peers expects 1 argument (position)")
+	}
+	pos, err := flowPosition(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := flowquery.Peers(ctx, pos)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+// flowPosition converts a position argument to a flowquery.Position.
+func flowPosition(arg string) (flowquery.Position, error) {
+	from := parseSpan(arg)
+	pos := flowquery.Position{Filename: from.URI().Path()}
+	switch {
+	case from.HasPosition():
+		pos.Line, pos.Column = from.Start().Line(), from.Start().Column()
+	case from.HasOffset():
+		pos.Offset = from.Start().Offset()
+	default:
+		return pos, tool.CommandLineErrorf("invalid position %q (want file:line:column or file:#offset)", arg)
+	}
+	return pos, nil
+}
+
+// printJSON prints the JSON encoding of v to the standard output.
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+	return nil
+}
@@ -35,11 +35,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/tools/gopls/internal/cmd"
 	"golang.org/x/tools/gopls/internal/debug"
+	"golang.org/x/tools/gopls/internal/flowquery"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/gopls/internal/version"
@@ -141,6 +144,50 @@ var C int
 		res.checkStdout(`c2.go:2:5-6: C redeclared in this block`)
 		res.checkStdout(`c.go:2:5-6: - other declaration of C`)
 	}
+
+	// package patterns
+	{
+		res := gopls(t, tree, "check", "./...")
+		res.checkExit(true)
+		res.checkStdout(`a.go:.* fmt.Sprintf format %s has arg 123 of wrong type int`)
+		res.checkStdout(`c2.go:2:5-6: C redeclared in this block`)
+	}
+
+	// -types: type errors only, in JSON
+	{
+		res := gopls(t, tree, "check", "-types", "-json", "./...")
+		res.checkExit(false)
+		res.checkStderr("2 errors") // reported at each declaration of C
+		if strings.Contains(res.stdout, "Sprintf") {
+			t.Errorf("unexpected analyzer diagnostic: %v", res)
+		}
+		var diags []struct {
+			Span     json.RawMessage
+			Severity string
+			Source   string
+			Message  string
+			Related  []struct{ Message string }
+		}
+		if err := json.Unmarshal([]byte(res.stdout), &diags); err != nil {
+			t.Fatal(err)
+		}
+		if len(diags) != 2 ||
+			diags[1].Message != "C redeclared in this block" ||
+			diags[1].Severity != "error" ||
+			diags[1].Source != "compiler" ||
+			len(diags[1].Related) != 1 {
+			t.Errorf("unexpected diagnostics: %s", res.stdout)
+		}
+	}
+
+	// -types with no errors
+	{
+		res := gopls(t, tree, "check", "-types", ".")
+		res.checkExit(true)
+		if res.stdout != "" {
+			t.Errorf("unexpected output: %v", res)
+		}
+	}
 }
 
 // TestCallHierarchy tests the 'call_hierarchy' subcommand (call_hierarchy.go).
@@ -941,6 +988,54 @@ package foo
 	}
 }
 
+// TestStatsDriver tests the -driver flag of the 'stats' subcommand.
+func TestStatsDriver(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell script driver")
+	}
+
+	tree := writeTree(t, `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a.go --
+package a
+-- driver.sh --
+#!/bin/sh
+cat > /dev/null
+echo '{"NotHandled": true}'
+`)
+	driver := filepath.Join(tree, "driver.sh")
+	if err := os.Chmod(driver, 0755); err != nil {
+		t.Fatal(err)
+	}
+	env := []string{"GOPACKAGESDRIVER=" + driver}
+
+	res := goplsWithEnv(t, tree, env, "stats", "-driver")
+	res.checkExit(true)
+	var stats cmd.GoplsStats
+	if err := json.Unmarshal([]byte(res.stdout), &stats); err != nil {
+		t.Fatalf("failed to unmarshal JSON output of stats command: %v", err)
+	}
+	if stats.DriverStats == nil || len(stats.DriverStats.Drivers) != 1 {
+		t.Fatalf("got DriverStats %+v, want one driver", stats.DriverStats)
+	}
+	got := stats.DriverStats.Drivers[0]
+	if got.Driver != "./driver.sh" || got.Queries == 0 || got.Failures != 0 {
+		t.Errorf("got driver stats %+v, want successful queries of ./driver.sh", got)
+	}
+
+	// Without -driver, the section is omitted.
+	res = goplsWithEnv(t, tree, env, "stats")
+	res.checkExit(true)
+	res.checkStdout(`"GoVersion"`)
+	if strings.Contains(res.stdout, "DriverStats") {
+		t.Errorf("stats without -driver reported DriverStats:\n%s", res.stdout)
+	}
+}
+
 // TestCodeAction tests the 'codeaction' subcommand (codeaction.go).
 func TestCodeAction(t *testing.T) {
 	t.Parallel()
@@ -1069,6 +1164,200 @@ func someFunctionName()
 	}
 }
 
+func TestUnusedExports(t *testing.T) {
+	t.Parallel()
+
+	tree := writeTree(t, `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a/a.go --
+package a
+
+const Used, Unused = 1, 2
+
+type T int
+
+func (T) M() {}
+
+func F() T { return 0 }
+
+func G() {}
+
+func unexported() {}
+
+-- a/a_test.go --
+package a
+
+var _ = G
+
+-- b/b.go --
+package b
+
+import "example.com/a"
+
+var _ = a.Used
+
+var _ = a.F().M
+
+func H() {}
+
+-- main/main.go --
+package main
+
+func Main() {}
+
+func main() {}
+`)
+	// too many arguments
+	{
+		res := gopls(t, tree, "unused_exports", "a", "b")
+		res.checkExit(false)
+		res.checkStderr("expects at most one argument")
+	}
+	// success
+	{
+		res := gopls(t, tree, "unused_exports")
+		res.checkExit(true)
+		got := res.stdout
+		want := []string{
+			"a/a.go:3:13-19: unused const example.com/a.Unused\n",
+			"a/a.go:11:6-7: unused func example.com/a.G\n",
+			"b/b.go:9:6-7: unused func example.com/b.H\n",
+		}
+		for _, w := range want {
+			if !strings.Contains(got, w) {
+				t.Errorf("unused_exports: output does not contain %q:\n%s", w, got)
+			}
+		}
+		// T is used via its method M; Main is in a main package.
+		for _, name := range []string{"a.T", "a.F", "a.Used", "main.Main"} {
+			if strings.Contains(got, name+"\n") {
+				t.Errorf("unused_exports: output unexpectedly contains %q:\n%s", name, got)
+			}
+		}
+	}
+}
+
+func TestPointsTo(t *testing.T) {
+	t.Parallel()
+
+	tree := writeTree(t, `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a/a.go --
+package a
+
+import "io"
+
+type R struct{}
+
+func (*R) Read([]byte) (int, error) { return 0, nil }
+
+type S string
+
+func (S) Read([]byte) (int, error) { return 0, nil }
+
+func Use(r io.Reader) {
+	r.Read(nil)
+}
+
+func F() {
+	Use(&R{})
+	Use(S(""))
+	var x int
+	_ = x
+}
+`)
+	// no arguments
+	{
+		res := gopls(t, tree, "pointsto")
+		res.checkExit(false)
+		res.checkStderr("expects 1 argument")
+	}
+	// not an interface
+	{
+		res := gopls(t, tree, "pointsto", "a/a.go:20:6")
+		res.checkExit(false)
+		res.checkStderr("has type int, not an interface")
+	}
+	// success
+	{
+		res := gopls(t, tree, "pointsto", "a/a.go:14:2")
+		res.checkExit(true)
+		var got flowquery.PointsToResult
+		if res.toJSON(&got) {
+			want := flowquery.PointsToResult{
+				Pos:  "./a/a.go:14:2",
+				Type: "io.Reader",
+				Types: []flowquery.PointsToType{
+					{Type: "*example.com/a.R", NamePos: "./a/a.go:5:6"},
+					{Type: "example.com/a.S", NamePos: "./a/a.go:9:6"},
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("pointsto: unexpected result (-want +got):\n%s", diff)
+			}
+		}
+	}
+}
+
+func TestPeers(t *testing.T) {
+	t.Parallel()
+
+	tree := writeTree(t, `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a/a.go --
+package a
+
+func F() {
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+		close(ch)
+	}()
+	<-ch
+	select {
+	case x := <-ch:
+		_ = x
+	}
+	strs := make(chan string)
+	strs <- ""
+}
+`)
+	// not a channel operation
+	{
+		res := gopls(t, tree, "peers", "a/a.go:4:2")
+		res.checkExit(false)
+		res.checkStderr("no channel operation selected")
+	}
+	// success
+	{
+		res := gopls(t, tree, "peers", "a/a.go:9:2")
+		res.checkExit(true)
+		var got flowquery.PeersResult
+		if res.toJSON(&got) {
+			want := flowquery.PeersResult{
+				Pos:      "./a/a.go:9:2",
+				Type:     "chan int",
+				Allocs:   []string{"./a/a.go:4:12"},
+				Sends:    []string{"./a/a.go:6:6"},
+				Receives: []string{"./a/a.go:9:2", "./a/a.go:11:12"},
+				Closes:   []string{"./a/a.go:7:8"},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("peers: unexpected result (-want +got):\n%s", diff)
+			}
+		}
+	}
+}
+
 // -- test framework --
 
 func TestMain(m *testing.M) {
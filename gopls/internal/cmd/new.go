@@ -0,0 +1,145 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/internal/tool"
+)
+
+// newModule implements the new verb for gopls.
+type newModule struct {
+	Vars  varsFlag  `flag:"var" help:"set template parameter, in the form name=value (repeatable)"`
+	Steps string    `flag:"steps" help:"comma-separated list of template steps to run, or \"all\""`
+	Hooks hooksFlag `flag:"hook" help:"run space-separated command in the new module after creation (repeatable)"`
+
+	app *Application
+}
+
+func (n *newModule) Name() string      { return "new" }
+func (n *newModule) Parent() string    { return n.app.Name() }
+func (n *newModule) Usage() string     { return "[new-flags] template[@version] [module [dir]]" }
+func (n *newModule) ShortHelp() string { return "create a new module from a template" }
+func (n *newModule) DetailedHelp(f *flag.FlagSet) {
+	fmt.Fprint(f.Output(), `
+The new command creates a new module by copying a template module,
+in the manner of golang.org/x/tools/cmd/gonew. It changes the module
+path of the copy, and the imports of its packages, to the specified
+module path (by default, the template's own path), and writes the new
+module to the specified directory, which must not exist or must be
+empty. If the directory is omitted, the new command uses ./elem, where
+elem is the final path element of the module path.
+
+Each occurrence of {{gonew.name}} in the names and contents of the
+template's files is replaced by the value of the template parameter
+name given by a -var flag. Parameters declared by the template's
+gonew.json manifest that are not set by -var take their default values.
+
+Once the module has been created, the post-processing steps declared
+by the manifest and selected by the -steps flag are run in the new
+module's directory, followed by the commands given by -hook flags.
+
+Example:
+
+	$ gopls new golang.org/x/example/hello example.com/myprog
+	$ gopls new -var author=Gopher -hook 'go mod tidy' example.com/template@v1.0.0 example.com/demo ./demo
+
+new-flags:
+`)
+	printFlagDefaults(f)
+}
+
+func (n *newModule) Run(ctx context.Context, args ...string) error {
+	if len(args) < 1 || len(args) > 3 {
+		return tool.CommandLineErrorf("new expects 1 to 3 arguments")
+	}
+	cmdArgs := command.NewModuleArgs{
+		Template: args[0],
+		Vars:     n.Vars,
+		Hooks:    n.Hooks,
+	}
+	if n.Steps != "" {
+		cmdArgs.Steps = strings.Split(n.Steps, ",")
+	}
+	modPath, _, _ := strings.Cut(args[0], "@")
+	if len(args) >= 2 {
+		cmdArgs.Module = args[1]
+		modPath = args[1]
+	}
+	dir := path.Base(modPath)
+	if len(args) == 3 {
+		dir = args[2]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	cmdArgs.Dir = protocol.URIFromPath(dir)
+
+	conn, err := n.app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.terminate(ctx)
+
+	if _, err := conn.executeCommand(ctx, command.NewNewModuleCommand("", cmdArgs)); err != nil {
+		return err
+	}
+	fmt.Printf("initialized %s in %s\n", modPath, dir)
+	return nil
+}
+
+// varsFlag is a repeatable flag of the form name=value.
+type varsFlag map[string]string
+
+func (v *varsFlag) String() string {
+	var kvs []string
+	for k, val := range *v {
+		kvs = append(kvs, k+"="+val)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, ",")
+}
+
+func (v *varsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid template parameter %q, want name=value", s)
+	}
+	if *v == nil {
+		*v = make(varsFlag)
+	}
+	(*v)[name] = value
+	return nil
+}
+
+// hooksFlag is a repeatable flag whose value is a space-separated command.
+type hooksFlag [][]string
+
+func (h *hooksFlag) String() string {
+	var cmds []string
+	for _, hook := range *h {
+		cmds = append(cmds, strings.Join(hook, " "))
+	}
+	return strings.Join(cmds, ",")
+}
+
+func (h *hooksFlag) Set(s string) error {
+	hook := strings.Fields(s)
+	if len(hook) == 0 {
+		return fmt.Errorf("empty hook command")
+	}
+	*h = append(*h, hook)
+	return nil
+}
@@ -31,10 +31,12 @@ type Serve struct {
 	Port        int           `flag:"port" help:"port on which to run gopls for debugging purposes"`
 	Address     string        `flag:"listen" help:"address on which to listen for remote connections. If prefixed by 'unix;', the subsequent address is assumed to be a unix domain socket. Otherwise, TCP is used."`
 	IdleTimeout time.Duration `flag:"listen.timeout" help:"when used with -listen, shut down the server when there are no connected clients for this duration"`
+	ViewTimeout time.Duration `flag:"listen.viewtimeout" help:"when used with -listen, discard the state of views that have not been used for this duration"`
 	Trace       bool          `flag:"rpc.trace" help:"print the full rpc trace in lsp inspector format"`
 	Debug       string        `flag:"debug" help:"serve debug information on the supplied address"`
 
 	RemoteListenTimeout time.Duration `flag:"remote.listen.timeout" help:"when used with -remote=auto, the -listen.timeout value used to start the daemon"`
+	RemoteViewTimeout   time.Duration `flag:"remote.listen.viewtimeout" help:"when used with -remote=auto, the -listen.viewtimeout value used to start the daemon"`
 	RemoteDebug         string        `flag:"remote.debug" help:"when used with -remote=auto, the -debug value used to start the daemon"`
 	RemoteLogfile       string        `flag:"remote.logfile" help:"when used with -remote=auto, the -logfile value used to start the daemon"`
 
@@ -68,6 +70,9 @@ func (s *Serve) remoteArgs(network, address string) []string {
 	if s.RemoteListenTimeout != 0 {
 		args = append(args, "-listen.timeout", s.RemoteListenTimeout.String())
 	}
+	if s.RemoteViewTimeout != 0 {
+		args = append(args, "-listen.viewtimeout", s.RemoteViewTimeout.String())
+	}
 	if s.RemoteLogfile != "" {
 		args = append(args, "-logfile", s.RemoteLogfile)
 	}
@@ -99,8 +104,10 @@ func (s *Serve) Run(ctx context.Context, args ...string) error {
 		if err != nil {
 			return fmt.Errorf("creating forwarder: %w", err)
 		}
+	} else if isDaemon {
+		ss = lsprpc.NewDaemonStreamServer(cache.New(nil), s.ViewTimeout, s.app.options)
 	} else {
-		ss = lsprpc.NewStreamServer(cache.New(nil), isDaemon, s.app.options)
+		ss = lsprpc.NewStreamServer(cache.New(nil), false, s.app.options)
 	}
 
 	var network, addr string
@@ -131,7 +138,12 @@ func (s *Serve) Run(ctx context.Context, args ...string) error {
 	if addr != "" {
 		log.Printf("Gopls daemon: listening on %s network, address %s...", network, addr)
 		defer log.Printf("Gopls daemon: exiting")
-		return jsonrpc2.ListenAndServe(ctx, network, addr, ss, s.IdleTimeout)
+		err := jsonrpc2.ListenAndServe(ctx, network, addr, ss, s.IdleTimeout)
+		if errors.Is(err, jsonrpc2.ErrIdleTimeout) {
+			log.Printf("Gopls daemon: no clients connected for %v", s.IdleTimeout)
+			return nil
+		}
+		return err
 	}
 	stream := jsonrpc2.NewHeaderStream(fakenet.NewConn("stdio", os.Stdin, os.Stdout))
 	if s.Trace && di != nil {
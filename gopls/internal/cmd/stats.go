@@ -30,7 +30,8 @@ import (
 type stats struct {
 	app *Application
 
-	Anon bool `flag:"anon" help:"hide any fields that may contain user names, file names, or source code"`
+	Anon   bool `flag:"anon" help:"hide any fields that may contain user names, file names, or source code"`
+	Driver bool `flag:"driver" help:"include statistics and recent errors of the go/packages driver (GOPACKAGESDRIVER)"`
 }
 
 func (s *stats) Name() string      { return "stats" }
@@ -48,8 +49,13 @@ By default, this command may include output that refers to the location or
 content of user code. When the -anon flag is set, fields that may refer to user
 code are hidden.
 
+When the -driver flag is set, the output also reports, for each external
+go/packages driver (GOPACKAGESDRIVER) used to load the workspace, its version,
+the latency of its queries, and its most recent errors.
+
 Example:
   $ gopls stats -anon
+  $ gopls stats -driver
 `)
 	printFlagDefaults(f)
 }
@@ -152,6 +158,29 @@ func (s *stats) Run(ctx context.Context, args ...string) error {
 		return err
 	}
 
+	if s.Driver {
+		if _, err := do("Querying driver stats", func() error {
+			res, err := conn.executeCommand(ctx, &protocol.Command{
+				Command: command.DriverStats.String(),
+			})
+			if err != nil {
+				return err
+			}
+			driverStats := res.(command.DriverStatsResult)
+			if s.Anon {
+				// Driver paths and errors may refer to user files.
+				for i := range driverStats.Drivers {
+					driverStats.Drivers[i].Driver = ""
+					driverStats.Drivers[i].Errors = nil
+				}
+			}
+			stats.DriverStats = &driverStats
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
 	if _, err := do("Collecting directory info", func() error {
 		var err error
 		stats.DirStats, err = findDirStats()
@@ -174,6 +203,9 @@ func (s *stats) Run(ctx context.Context, args ...string) error {
 				continue
 			}
 			vf := v.FieldByName(f.Name)
+			if vf.Kind() == reflect.Pointer && vf.IsNil() {
+				continue // optional section not requested
+			}
 			if s.Anon && f.Tag.Get("anon") != "ok" && !vf.IsZero() {
 				// Fields that can be served with -anon must be explicitly marked as OK.
 				// But, if it's zero value, it's ok to print.
@@ -210,6 +242,7 @@ type GoplsStats struct {
 	MemStats                     command.MemStatsResult       `anon:"ok"`
 	WorkspaceStats               command.WorkspaceStatsResult `anon:"ok"`
 	DirStats                     dirStats                     `anon:"ok"`
+	DriverStats                  *command.DriverStatsResult   `anon:"ok"` // only with -driver; anonymized by -anon
 }
 
 type dirStats struct {
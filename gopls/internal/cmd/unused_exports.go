@@ -0,0 +1,84 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/internal/tool"
+)
+
+// unusedExports implements the unused_exports verb for gopls.
+type unusedExports struct {
+	app *Application
+}
+
+func (u *unusedExports) Name() string      { return "unused_exports" }
+func (u *unusedExports) Parent() string    { return u.app.Name() }
+func (u *unusedExports) Usage() string     { return "[dir]" }
+func (u *unusedExports) ShortHelp() string { return "list exported symbols unused by other packages" }
+func (u *unusedExports) DetailedHelp(f *flag.FlagSet) {
+	fmt.Fprint(f.Output(), `
+The unused_exports command lists the exported package-level symbols
+of the workspace packages that are not referenced from any other
+package in the workspace containing the specified directory (by
+default, the current directory).
+
+Unlike the unusedfunc analyzer, which reports only unexported
+symbols, this command considers references from the entire
+workspace. Symbols used only by other modules may still be listed.
+
+Example:
+
+	$ gopls unused_exports
+	$ gopls unused_exports ./internal
+`)
+	printFlagDefaults(f)
+}
+
+func (u *unusedExports) Run(ctx context.Context, args ...string) error {
+	dir := "."
+	switch len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		return tool.CommandLineErrorf("unused_exports expects at most one argument")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	conn, err := u.app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.terminate(ctx)
+
+	res, err := conn.executeCommand(ctx, command.NewUnusedExportsCommand("", command.UnusedExportsArgs{
+		URI: protocol.URIFromPath(dir),
+	}))
+	if err != nil {
+		return err
+	}
+	for _, sym := range res.(command.UnusedExportsResult).Symbols {
+		f, err := conn.openFile(ctx, sym.Location.URI)
+		if err != nil {
+			return err
+		}
+		span, err := f.locationSpan(sym.Location)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v: unused %s %s.%s\n", span, sym.Kind, sym.PkgPath, sym.Name)
+	}
+	return nil
+}
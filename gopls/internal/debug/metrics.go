@@ -10,6 +10,10 @@ import (
 	"golang.org/x/tools/internal/jsonrpc2"
 )
 
+// maxGroups bounds the number of rows of each metric, since a
+// client may send requests and notifications for any method name.
+const maxGroups = 200
+
 var (
 	// the distributions we use for histograms
 	bytesDistribution        = []int64{1 << 10, 1 << 11, 1 << 12, 1 << 14, 1 << 16, 1 << 20}
@@ -19,6 +23,7 @@ var (
 		Name:        "received_bytes",
 		Description: "Distribution of received bytes, by method.",
 		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method},
+		MaxGroups:   maxGroups,
 		Buckets:     bytesDistribution,
 	}
 
@@ -26,6 +31,7 @@ var (
 		Name:        "sent_bytes",
 		Description: "Distribution of sent bytes, by method.",
 		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method},
+		MaxGroups:   maxGroups,
 		Buckets:     bytesDistribution,
 	}
 
@@ -33,6 +39,15 @@ var (
 		Name:        "latency",
 		Description: "Distribution of latency in milliseconds, by method.",
 		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method},
+		MaxGroups:   maxGroups,
+		Buckets:     millisecondsDistribution,
+	}
+
+	queueLatency = metric.HistogramFloat64{
+		Name:        "queue_latency",
+		Description: "Distribution of time spent queued in milliseconds, by method.",
+		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method},
+		MaxGroups:   maxGroups,
 		Buckets:     millisecondsDistribution,
 	}
 
@@ -40,12 +55,14 @@ var (
 		Name:        "started",
 		Description: "Count of RPCs started by method.",
 		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method},
+		MaxGroups:   maxGroups,
 	}
 
 	completed = metric.Scalar{
 		Name:        "completed",
 		Description: "Count of RPCs completed by method and status.",
 		Keys:        []label.Key{jsonrpc2.RPCDirection, jsonrpc2.Method, jsonrpc2.StatusCode},
+		MaxGroups:   maxGroups,
 	}
 )
 
@@ -53,6 +70,7 @@ func registerMetrics(m *metric.Config) {
 	receivedBytes.Record(m, jsonrpc2.ReceivedBytes)
 	sentBytes.Record(m, jsonrpc2.SentBytes)
 	latency.Record(m, jsonrpc2.Latency)
+	queueLatency.Record(m, jsonrpc2.QueueLatency)
 	started.Count(m, jsonrpc2.Started)
 	completed.Count(m, jsonrpc2.Latency)
 }
@@ -34,6 +34,7 @@ import (
 	"golang.org/x/tools/internal/event/export"
 	"golang.org/x/tools/internal/event/export/metric"
 	"golang.org/x/tools/internal/event/export/ocagent"
+	"golang.org/x/tools/internal/event/export/otlp"
 	"golang.org/x/tools/internal/event/export/prometheus"
 	"golang.org/x/tools/internal/event/keys"
 	"golang.org/x/tools/internal/event/label"
@@ -52,12 +53,14 @@ type Instance struct {
 	StartTime     time.Time
 	ServerAddress string
 	OCAgentConfig string
+	OTLPConfig    string
 
 	LogWriter io.Writer
 
 	exporter event.Exporter
 
 	ocagent    *ocagent.Exporter
+	otlp       *otlp.Exporter
 	prometheus *prometheus.Exporter
 	rpcs       *Rpcs
 	traces     *traces
@@ -363,16 +366,23 @@ func GetInstance(ctx context.Context) *Instance {
 
 // WithInstance creates debug instance ready for use using the supplied
 // configuration and stores it in the returned context.
-func WithInstance(ctx context.Context, agent string) context.Context {
+//
+// The agent and collector are the addresses of the ocagent and of the
+// OpenTelemetry collector to which to export telemetry, or "off".
+// If the collector is empty, it is specified by the standard
+// OTEL_EXPORTER_OTLP_* environment variables, if any.
+func WithInstance(ctx context.Context, agent, collector string) context.Context {
 	i := &Instance{
 		StartTime:     time.Now(),
 		OCAgentConfig: agent,
+		OTLPConfig:    collector,
 	}
 	i.LogWriter = os.Stderr
 	ocConfig := ocagent.Discover()
 	//TODO: we should not need to adjust the discovered configuration
 	ocConfig.Address = i.OCAgentConfig
 	i.ocagent = ocagent.Connect(ocConfig)
+	i.otlp = otlp.Connect(otlpConfig(i.OTLPConfig))
 	i.prometheus = prometheus.New()
 	i.rpcs = &Rpcs{}
 	i.traces = &traces{}
@@ -381,6 +391,25 @@ func WithInstance(ctx context.Context, agent string) context.Context {
 	return context.WithValue(ctx, instanceKey, i)
 }
 
+// otlpConfig returns the configuration of the export of traces to the
+// OpenTelemetry collector at the specified address, which is "off",
+// or empty to use the configuration of the environment.
+func otlpConfig(collector string) *otlp.Config {
+	if collector == "off" {
+		return nil
+	}
+	cfg := otlp.FromEnv(os.Getenv)
+	if collector != "" {
+		// The address overrides that of the environment,
+		// but headers such as credentials still apply.
+		if cfg == nil {
+			cfg = &otlp.Config{}
+		}
+		cfg.Address, cfg.TracesURL = collector, ""
+	}
+	return cfg
+}
+
 // SetLogFile sets the logfile for use with this instance.
 func (i *Instance) SetLogFile(logfile string, isDaemon bool) (func(), error) {
 	// TODO: probably a better solution for deferring closure to the caller would
@@ -544,6 +573,9 @@ func makeInstanceExporter(i *Instance) event.Exporter {
 		if i.ocagent != nil {
 			ctx = i.ocagent.ProcessEvent(ctx, ev, lm)
 		}
+		if i.otlp != nil {
+			ctx = i.otlp.ProcessEvent(ctx, ev, lm)
+		}
 		if i.prometheus != nil {
 			ctx = i.prometheus.ProcessEvent(ctx, ev, lm)
 		}
@@ -822,6 +854,20 @@ Env overlay: <b>{{$envOverlay}})</b><br>
 {{end -}}
 Folder: <b>{{.Folder.Name}}:{{.Folder.Dir}}</b></li>
 {{end}}</ul>
+{{with .DriverStats}}
+<h2>Packages drivers</h2>
+<ul>{{range .}}
+<li>Driver: <b>{{.Driver}}</b><br>
+Version: <b>{{if .Version}}{{.Version}}{{else}}unknown{{end}}</b><br>
+Queries: <b>{{.Queries}}</b> ({{.Failures}} failed)<br>
+Latency: mean <b>{{.MeanLatency}}</b>, max <b>{{.MaxLatency}}</b>, last <b>{{.LastLatency}}</b><br>
+{{- if .Errors}}
+Recent errors:
+<ul>{{range .Errors}}<li>{{.Time.Format "2006-01-02 15:04:05"}} view {{.View}} query {{.Query}}:<pre>{{.Err}}</pre></li>{{end}}</ul>
+{{end -}}
+</li>
+{{end}}</ul>
+{{end}}
 <h2>Overlays</h2>
 {{$session := .}}
 <ul>{{range .Overlays}}
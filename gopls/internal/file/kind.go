@@ -11,7 +11,7 @@ import (
 )
 
 // Kind describes the kind of the file in question.
-// It can be one of Go,mod, Sum, or Tmpl.
+// It can be one of Go,mod, Sum, Tmpl, Work, or Asm.
 type Kind int
 
 const (
@@ -28,6 +28,8 @@ const (
 	Tmpl
 	// Work is a go.work file.
 	Work
+	// Asm is a Go assembly (.s) file.
+	Asm
 )
 
 func (k Kind) String() string {
@@ -42,6 +44,8 @@ func (k Kind) String() string {
 		return "tmpl"
 	case Work:
 		return "go.work"
+	case Asm:
+		return "asm"
 	default:
 		return fmt.Sprintf("internal error: unknown file kind %d", k)
 	}
@@ -0,0 +1,405 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package flowquery answers batch queries about the flow of values
+// through the packages of a module, in the manner of the "pointsto"
+// and "peers" queries of the former cmd/guru tool.
+//
+// Unlike the rest of gopls, it does not use a snapshot: each query
+// loads the module containing the queried file from source, builds
+// its SSA form, and analyzes it using Variable Type Analysis (see
+// [golang.org/x/tools/go/callgraph/vta]). The results are designed
+// to be encoded as JSON.
+//
+// VTA approximates the concrete types that may flow to each variable,
+// but does not distinguish values of the same type. So, for example,
+// the peers of a channel operation are all the operations on channels
+// of the same element type.
+package flowquery
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/typeparams"
+	"golang.org/x/tools/internal/vtainternal"
+)
+
+// A Position identifies the subject of a query: a point in a file,
+// denoted either by a 1-based line and column (in bytes), or, if
+// Line is zero, by a byte offset.
+type Position struct {
+	Filename     string
+	Line, Column int
+	Offset       int
+}
+
+// PointsToResult is the result of a [PointsTo] query.
+type PointsToResult struct {
+	Pos   string         `json:"pos"`   // location of the queried expression
+	Type  string         `json:"type"`  // static (interface) type of the expression
+	Types []PointsToType `json:"types"` // concrete types that may flow to it
+}
+
+// A PointsToType is a concrete type that may flow to an interface value.
+type PointsToType struct {
+	Type    string `json:"type"`
+	NamePos string `json:"namepos,omitempty"` // location of the declaration of a named type
+}
+
+// PeersResult is the result of a [Peers] query.
+type PeersResult struct {
+	Pos      string   `json:"pos"`                // location of the queried channel operation
+	Type     string   `json:"type"`               // type of the channel
+	Allocs   []string `json:"allocs,omitempty"`   // locations of make(chan) calls
+	Sends    []string `json:"sends,omitempty"`    // locations of send operations
+	Receives []string `json:"receives,omitempty"` // locations of receive operations
+	Closes   []string `json:"closes,omitempty"`   // locations of close calls
+}
+
+// PointsTo reports the concrete types that may flow to the value of
+// the interface-typed expression at the specified position.
+func PointsTo(ctx context.Context, pos Position) (*PointsToResult, error) {
+	q, err := load(ctx, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the innermost enclosing expression.
+	i := slices.IndexFunc(q.path, func(n ast.Node) bool {
+		_, ok := n.(ast.Expr)
+		return ok
+	})
+	if i < 0 {
+		return nil, fmt.Errorf("no expression selected")
+	}
+	expr := q.path[i].(ast.Expr)
+	if tv, ok := q.info.Types[expr]; ok && !tv.IsValue() {
+		return nil, fmt.Errorf("selected expression is not a value")
+	}
+	T := q.info.TypeOf(expr)
+	if T == nil || !types.IsInterface(T) {
+		return nil, fmt.Errorf("selected expression has type %v, not an interface", T)
+	}
+
+	// Find the SSA value of the expression.
+	var v ssa.Value
+	if id, ok := expr.(*ast.Ident); ok {
+		obj, ok := q.info.ObjectOf(id).(*types.Var)
+		if !ok {
+			return nil, fmt.Errorf("selected identifier does not denote a variable")
+		}
+		v, _ = q.prog.VarValue(obj, q.pkg, q.path[i:])
+	} else if fn := ssa.EnclosingFunction(q.pkg, q.path[i:]); fn != nil {
+		v, _ = fn.ValueForExpr(expr)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("no SSA value for selected expression (perhaps it was optimized away)")
+	}
+
+	// (If v is the address of a variable, VTA reports the types
+	// that may be stored in the variable.)
+	flow := vtainternal.TypeFlow(ssautil.AllFunctions(q.prog), nil)
+	res := &PointsToResult{
+		Pos:  q.position(expr.Pos()),
+		Type: types.TypeString(T, nil),
+	}
+	seen := make(map[string]bool)
+	for _, t := range flow(v) {
+		str := types.TypeString(t, nil)
+		if seen[str] {
+			continue
+		}
+		seen[str] = true
+		pt := PointsToType{Type: str}
+		if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := types.Unalias(t).(*types.Named); ok && named.Obj().Pos().IsValid() {
+			pt.NamePos = q.position(named.Obj().Pos())
+		}
+		res.Types = append(res.Types, pt)
+	}
+	slices.SortFunc(res.Types, func(x, y PointsToType) int {
+		return strings.Compare(x.Type, y.Type)
+	})
+	return res, nil
+}
+
+// Peers reports the operations on channels that may alias the
+// channel of the send, receive, or close operation at the specified
+// position.
+//
+// If the module contains main packages, only operations in functions
+// reachable from them (according to the VTA call graph) are reported.
+func Peers(ctx context.Context, pos Position) (*PeersResult, error) {
+	q, err := load(ctx, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the enclosing channel operation.
+	var (
+		ch    ast.Expr
+		opPos token.Pos
+	)
+outer:
+	for _, n := range q.path {
+		switch n := n.(type) {
+		case *ast.SendStmt:
+			ch, opPos = n.Chan, n.Arrow
+			break outer
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				ch, opPos = n.X, n.OpPos
+				break outer
+			}
+		case *ast.CallExpr:
+			if id, ok := ast.Unparen(n.Fun).(*ast.Ident); ok && len(n.Args) == 1 {
+				if b, ok := q.info.Uses[id].(*types.Builtin); ok && b.Name() == "close" {
+					ch, opPos = n.Args[0], n.Lparen
+					break outer
+				}
+			}
+		case *ast.FuncDecl, *ast.FuncLit:
+			break outer
+		}
+	}
+	if ch == nil {
+		return nil, fmt.Errorf("no channel operation selected (select a '<-' operator or a call to close)")
+	}
+	chType := q.info.TypeOf(ch)
+	elem := chanElem(chType)
+	if elem == nil {
+		return nil, fmt.Errorf("channel operand has type %v, not a channel type", chType)
+	}
+
+	var allocs, sends, receives, closes []token.Pos
+	add := func(list *[]token.Pos, ch ssa.Value, pos token.Pos) {
+		if pos.IsValid() {
+			if e := chanElem(ch.Type()); e != nil && types.Identical(e, elem) {
+				*list = append(*list, pos)
+			}
+		}
+	}
+	for fn := range q.functions(opPos) {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.MakeChan:
+					add(&allocs, instr, instr.Pos())
+				case *ssa.Send:
+					add(&sends, instr.Chan, instr.Pos())
+				case *ssa.UnOp:
+					if instr.Op == token.ARROW {
+						add(&receives, instr.X, instr.Pos())
+					}
+				case *ssa.Select:
+					for _, st := range instr.States {
+						if st.Dir == types.SendOnly {
+							add(&sends, st.Chan, st.Pos)
+						} else {
+							add(&receives, st.Chan, st.Pos)
+						}
+					}
+				case ssa.CallInstruction:
+					common := instr.Common()
+					if b, ok := common.Value.(*ssa.Builtin); ok && b.Name() == "close" {
+						add(&closes, common.Args[0], instr.Pos())
+					}
+				}
+			}
+		}
+	}
+
+	// positions returns the sorted, distinct locations of a list of positions.
+	positions := func(list []token.Pos) []string {
+		slices.Sort(list)
+		var res []string
+		for _, pos := range slices.Compact(list) {
+			res = append(res, q.position(pos))
+		}
+		return res
+	}
+	return &PeersResult{
+		Pos:      q.position(opPos),
+		Type:     types.TypeString(chType, nil),
+		Allocs:   positions(allocs),
+		Sends:    positions(sends),
+		Receives: positions(receives),
+		Closes:   positions(closes),
+	}, nil
+}
+
+// A query holds the program loaded for a query.
+type query struct {
+	fset *token.FileSet
+	prog *ssa.Program
+	pkgs []*ssa.Package // the module's well-typed packages
+	pkg  *ssa.Package   // the package containing the query
+	info *types.Info    // type information for pkg
+	path []ast.Node     // path from the query position to the root of its file
+}
+
+// load loads, type-checks, and builds the SSA form of the module
+// containing the queried file, and locates the query position.
+func load(ctx context.Context, pos Position) (*query, error) {
+	filename, err := filepath.Abs(pos.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the module containing the file. Outside a module,
+	// load only the file's package.
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedModule,
+		Dir:     filepath.Dir(filename),
+	}
+	pkgs, err := packages.Load(cfg, "file="+filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no package contains %s", filename)
+	}
+	pattern := "."
+	if mod := pkgs[0].Module; mod != nil && mod.Dir != "" {
+		cfg.Dir, pattern = mod.Dir, "./..."
+	}
+
+	cfg.Mode = packages.LoadSyntax
+	pkgs, err = packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	prog, ssapkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics|ssa.GlobalDebug)
+	prog.Build()
+
+	q := &query{fset: prog.Fset, prog: prog}
+	for i, pkg := range pkgs {
+		if ssapkgs[i] != nil {
+			q.pkgs = append(q.pkgs, ssapkgs[i])
+		}
+		for _, file := range pkg.Syntax {
+			tokFile := prog.Fset.File(file.FileStart)
+			if q.path != nil || !sameFile(tokFile.Name(), filename) {
+				continue
+			}
+			if ssapkgs[i] == nil {
+				return nil, fmt.Errorf("package %s contains errors", pkg.PkgPath)
+			}
+			p, err := filePos(tokFile, pos)
+			if err != nil {
+				return nil, err
+			}
+			q.pkg, q.info = ssapkgs[i], pkg.TypesInfo
+			q.path, _ = astutil.PathEnclosingInterval(file, p, p)
+		}
+	}
+	if q.path == nil {
+		return nil, fmt.Errorf("no package in %s contains %s", cfg.Dir, filename)
+	}
+	return q, nil
+}
+
+// functions returns the set of functions to search for operations
+// related to the one at pos: the functions reachable from the
+// module's main packages according to the VTA call graph, if that
+// includes the function enclosing pos, or else all functions.
+func (q *query) functions(pos token.Pos) map[*ssa.Function]bool {
+	all := ssautil.AllFunctions(q.prog)
+
+	var roots []*ssa.Function
+	for _, pkg := range q.pkgs {
+		if pkg.Pkg.Name() == "main" {
+			if main := pkg.Func("main"); main != nil {
+				roots = append(roots, pkg.Func("init"), main)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		return all
+	}
+
+	cg := vta.CallGraph(all, nil)
+	reachable := make(map[*ssa.Function]bool)
+	var visit func(n *callgraph.Node)
+	visit = func(n *callgraph.Node) {
+		if n != nil && !reachable[n.Func] {
+			reachable[n.Func] = true
+			for _, e := range n.Out {
+				visit(e.Callee)
+			}
+		}
+	}
+	for _, fn := range roots {
+		visit(cg.Nodes[fn])
+	}
+	for fn := range reachable {
+		if fn.Pkg == q.pkg && fn.Syntax() != nil && fn.Syntax().Pos() <= pos && pos < fn.Syntax().End() {
+			return reachable
+		}
+	}
+	return all
+}
+
+// position returns the location of pos in the form file:line:col.
+func (q *query) position(pos token.Pos) string {
+	return safetoken.StartPosition(q.fset, pos).String()
+}
+
+// filePos returns the position in tokFile denoted by pos.
+func filePos(tokFile *token.File, pos Position) (token.Pos, error) {
+	offset := pos.Offset
+	if pos.Line > 0 {
+		if pos.Line > tokFile.LineCount() {
+			return token.NoPos, fmt.Errorf("line %d is beyond end of file %s", pos.Line, tokFile.Name())
+		}
+		start, err := safetoken.Offset(tokFile, tokFile.LineStart(pos.Line))
+		if err != nil {
+			return token.NoPos, err
+		}
+		offset = start + max(pos.Column-1, 0)
+	}
+	return safetoken.Pos(tokFile, offset)
+}
+
+// chanElem returns the element type of a channel type, or nil.
+func chanElem(t types.Type) types.Type {
+	if ch, ok := typeparams.CoreType(t).(*types.Chan); ok {
+		return ch.Elem()
+	}
+	return nil
+}
+
+// sameFile reports whether x and y name the same file.
+func sameFile(x, y string) bool {
+	if x == y {
+		return true
+	}
+	xi, err := os.Stat(x)
+	if err != nil {
+		return false
+	}
+	yi, err := os.Stat(y)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(xi, yi)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package goasm provides language-server features for files of Go
+// assembly language.
+package goasm
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/asm"
+	"golang.org/x/tools/internal/event"
+)
+
+// Definition handles the textDocument/definition request for Go
+// assembly files. For a symbol of the file's package, it returns the
+// location of the Go declaration of the symbol, or, lacking one, that
+// of the TEXT instruction that defines it in an assembly file of the
+// package.
+func Definition(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) ([]protocol.Location, error) {
+	ctx, done := event.Start(ctx, "goasm.Definition")
+	defer done()
+
+	content, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	mapper := protocol.NewMapper(fh.URI(), content)
+	offset, err := mapper.PositionOffset(position)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the symbol at the cursor.
+	var found *asm.Ident
+	for _, id := range asm.Parse(content).Idents {
+		if id.Offset <= offset && offset <= id.End {
+			found = &id
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil // not a symbol
+	}
+
+	// Find the package of the file, among those of the workspace.
+	// (Assembly files are not among the files by which the
+	// metadata graph indexes packages.)
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mps = slices.DeleteFunc(mps, func(mp *metadata.Package) bool {
+		return !slices.Contains(mp.OtherFiles, fh.URI())
+	})
+	if len(mps) == 0 {
+		return nil, fmt.Errorf("no package for file %s", fh.URI())
+	}
+	metadata.RemoveIntermediateTestVariants(&mps)
+	mp := mps[0]
+
+	name, ok := found.Local(string(mp.PkgPath))
+	if !ok {
+		return nil, nil // symbol of another package
+	}
+
+	// Find the Go declaration.
+	pkgs, err := snapshot.TypeCheck(ctx, mp.ID)
+	if err != nil {
+		return nil, err
+	}
+	pkg := pkgs[0]
+	if obj := pkg.Types().Scope().Lookup(name); obj != nil && obj.Pos().IsValid() {
+		tokFile := pkg.FileSet().File(obj.Pos())
+		pgf, err := pkg.File(protocol.URIFromPath(tokFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+		loc, err := pgf.PosLocation(obj.Pos(), obj.Pos()+token.Pos(len(name)))
+		if err != nil {
+			return nil, err
+		}
+		return []protocol.Location{loc}, nil
+	}
+
+	// Find the TEXT or DATA instruction that defines the symbol,
+	// for symbols defined only in assembly.
+	for _, uri := range mp.OtherFiles {
+		if !strings.HasSuffix(uri.Path(), ".s") {
+			continue
+		}
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err // context cancelled
+		}
+		content, err := fh.Content()
+		if err != nil {
+			continue // can't read file
+		}
+		for _, id := range asm.Parse(content).Idents {
+			if local, ok := id.Local(string(mp.PkgPath)); ok && local == name && id.Kind != asm.Ref {
+				mapper := protocol.NewMapper(uri, content)
+				loc, err := mapper.OffsetLocation(id.End-len(name), id.End)
+				if err != nil {
+					return nil, err
+				}
+				return []protocol.Location{loc}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no definition of %s", name)
+}
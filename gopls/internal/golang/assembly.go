@@ -13,25 +13,74 @@ package golang
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"html"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/util/morestrings"
 )
 
+// AssemblyOptions specifies the target and compiler flags of an
+// assembly listing. Zero values denote the defaults of the view.
+type AssemblyOptions struct {
+	GOARCH  string // target architecture, e.g. "arm64"; one of assemblyArchs
+	GOAMD64 string // microarchitecture level when GOARCH=amd64, e.g. "v3"
+	GCFlags string // additional compiler flags; one of assemblyGCFlags
+}
+
+// assemblyArchs lists the architectures that may be selected.
+var assemblyArchs = []string{
+	"386", "amd64", "arm", "arm64", "loong64", "mips", "mipsle", "mips64",
+	"mips64le", "ppc64", "ppc64le", "riscv64", "s390x",
+}
+
+// assemblyGOAMD64 lists the microarchitecture levels of amd64.
+var assemblyGOAMD64 = []string{"v1", "v2", "v3", "v4"}
+
+// assemblyGCFlags lists the optimization-related compiler flags that
+// may be selected, and their descriptions.
+var assemblyGCFlags = []struct{ flags, label string }{
+	{"", "default"},
+	{"-l", "-l (disable inlining)"},
+	{"-N -l", "-N -l (disable optimizations)"},
+	{"-B", "-B (disable bounds checks)"},
+}
+
 // AssemblyHTML returns an HTML document containing an assembly listing of the selected function.
 //
 // TODO(adonovan):
 // - display a "Compiling..." message as a cold build can be slow.
 // - cross-link jumps and block labels, like github.com/aclements/objbrowse.
-func AssemblyHTML(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, symbol string, web Web) ([]byte, error) {
+func AssemblyHTML(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, symbol string, opts AssemblyOptions, web Web) ([]byte, error) {
+	// Validate the options, as they become command-line flags.
+	goarch := cmp.Or(opts.GOARCH, snapshot.View().GOARCH())
+	if opts.GOARCH != "" && !slices.Contains(assemblyArchs, opts.GOARCH) {
+		return nil, fmt.Errorf("unsupported GOARCH %q", opts.GOARCH)
+	}
+	if opts.GOAMD64 != "" && !slices.Contains(assemblyGOAMD64, opts.GOAMD64) {
+		return nil, fmt.Errorf("invalid GOAMD64 %q", opts.GOAMD64)
+	}
+	if !slices.ContainsFunc(assemblyGCFlags, func(f struct{ flags, label string }) bool { return f.flags == opts.GCFlags }) {
+		return nil, fmt.Errorf("unsupported compiler flags %q", opts.GCFlags)
+	}
+	var env []string
+	if opts.GOARCH != "" {
+		env = append(env, "GOARCH="+opts.GOARCH)
+	}
+	if goarch == "amd64" && opts.GOAMD64 != "" {
+		env = append(env, "GOAMD64="+opts.GOAMD64)
+	}
+
 	// Compile the package with -S, and capture its stderr stream.
-	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, pkg.Metadata().CompiledGoFiles[0].DirPath(), "build", []string{"-gcflags=-S", "."})
+	gcflags := strings.TrimSpace("-S " + opts.GCFlags)
+	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, pkg.Metadata().CompiledGoFiles[0].DirPath(), "build", []string{"-gcflags=" + gcflags, "."}, env...)
 	if err != nil {
 		return nil, err // e.g. failed to write overlays (rare)
 	}
@@ -44,9 +93,73 @@ func AssemblyHTML(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pack
 
 	escape := html.EscapeString
 
+	// insnRx matches an assembly instruction line.
+	// Submatch groups are: (offset-hex-dec, file-line-column, instruction).
+	insnRx := regexp.MustCompile(`^(\s+0x[0-9a-f ]+)\(([^)]*)\)\s+(.*)$`)
+
+	// Parse the functions of interest out of the listing.
+	// Each function is of the form:
+	//
+	//     symbol STEXT k=v...
+	//         0x0000 00000 (/file.go:123) NOP...
+	//         ...
+	//
+	// Allow matches of symbol, symbol.func1, symbol.deferwrap, etc.
+	//
+	// Instructions for lines of the file of the function's first
+	// instruction are cross-linked with its source lines, below.
+	var (
+		asm              bytes.Buffer
+		on               bool
+		srcFile          string // file of the first instruction
+		minLine, maxLine int    // range of lines of srcFile
+	)
+	for _, line := range strings.Split(content, "\n") {
+		// start of function symbol?
+		if strings.Contains(line, " STEXT ") {
+			on = strings.HasPrefix(line, symbol) &&
+				(line[len(symbol)] == ' ' || line[len(symbol)] == '.')
+		}
+		if !on {
+			continue // within uninteresting symbol
+		}
+
+		// In lines of the form
+		//   "\t0x0000 00000 (/file.go:123) NOP..."
+		// replace the "(/file.go:123)" portion with an "L0123" source link.
+		// Skip filenames of the form "<foo>".
+		if parts := insnRx.FindStringSubmatch(line); parts != nil {
+			link := "     " // if unknown
+			linenum := 0
+			if file, num, ok := morestrings.CutLast(parts[2], ":"); ok && !strings.HasPrefix(file, "<") {
+				if n, err := strconv.Atoi(num); err == nil {
+					text := fmt.Sprintf("L%04d", n)
+					link = sourceLink(text, web.SrcURL(file, n, 1))
+					if srcFile == "" {
+						srcFile, minLine, maxLine = file, n, n
+					}
+					if file == srcFile {
+						linenum = n
+						minLine, maxLine = min(minLine, n), max(maxLine, n)
+					}
+				}
+			}
+			if linenum > 0 {
+				fmt.Fprintf(&asm, "<span class='insn line-%d' onclick='selectLine(%[1]d, false)'>", linenum)
+			}
+			fmt.Fprintf(&asm, "%s\t%s\t%s", escape(parts[1]), link, escape(parts[3]))
+			if linenum > 0 {
+				asm.WriteString("</span>")
+			}
+		} else {
+			asm.WriteString(escape(line))
+		}
+		asm.WriteByte('\n')
+	}
+
 	// Produce the report.
 	title := fmt.Sprintf("%s assembly for %s",
-		escape(snapshot.View().GOARCH()),
+		escape(goarch),
 		escape(symbol))
 	var buf bytes.Buffer
 	buf.WriteString(`<!DOCTYPE html>
@@ -56,6 +169,48 @@ func AssemblyHTML(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pack
   <title>` + escape(title) + `</title>
   <link rel="stylesheet" href="/assets/common.css">
   <script src="/assets/common.js"></script>
+  <style>
+.listing {
+  display: flex;
+  gap: 1em;
+  align-items: flex-start;
+}
+
+#source {
+  position: sticky;
+  top: 0;
+  max-height: 95vh;
+  overflow: auto;
+  flex: 0 0 40%;
+}
+
+#asm { flex: 1; }
+
+.insn, .src-line { cursor: pointer; }
+
+.selected { background-color: rgba(255, 215, 0, 0.4); }
+  </style>
+  <script type='text/javascript'>
+// selectLine highlights a source line and its instructions,
+// scrolling the instructions into view if fromSource.
+function selectLine(line, fromSource) {
+	for (var e of document.querySelectorAll('.selected')) {
+		e.classList.remove('selected');
+	}
+	var src = document.getElementById('src-' + line);
+	if (src) {
+		src.classList.add('selected');
+		src.scrollIntoView({block: 'nearest'});
+	}
+	var insns = document.querySelectorAll('.line-' + line);
+	for (var e of insns) {
+		e.classList.add('selected');
+	}
+	if (fromSource && insns.length > 0) {
+		insns[0].scrollIntoView({block: 'center'});
+	}
+}
+  </script>
 </head>
 <body>
 <h1>` + title + `</h1>
@@ -68,53 +223,66 @@ func AssemblyHTML(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pack
 <p>
   Click on a source line marker <code>L1234</code> to navigate your editor there.
   (VS Code users: please upvote <a href='https://github.com/microsoft/vscode/issues/208093'>#208093</a>)
+  Click on an instruction or a source line to highlight the corresponding lines.
 </p>
 <p>
-  Reload the page to recompile.
+  Reload the page, or change the options below, to recompile.
 </p>
-<pre>
 `)
 
-	// insnRx matches an assembly instruction line.
-	// Submatch groups are: (offset-hex-dec, file-line-column, instruction).
-	insnRx := regexp.MustCompile(`^(\s+0x[0-9a-f ]+)\(([^)]*)\)\s+(.*)$`)
-
-	// Parse the functions of interest out of the listing.
-	// Each function is of the form:
-	//
-	//     symbol STEXT k=v...
-	//         0x0000 00000 (/file.go:123) NOP...
-	//         ...
-	//
-	// Allow matches of symbol, symbol.func1, symbol.deferwrap, etc.
-	on := false
-	for _, line := range strings.Split(content, "\n") {
-		// start of function symbol?
-		if strings.Contains(line, " STEXT ") {
-			on = strings.HasPrefix(line, symbol) &&
-				(line[len(symbol)] == ' ' || line[len(symbol)] == '.')
+	// Options form. Submitting it requests the same page with new options.
+	option := func(value, label, selected string) {
+		sel := ""
+		if value == selected {
+			sel = " selected"
 		}
-		if !on {
-			continue // within uninteresting symbol
+		fmt.Fprintf(&buf, "  <option value='%s'%s>%s</option>\n", escape(value), sel, escape(label))
+	}
+	fmt.Fprintf(&buf, "<form>\n")
+	fmt.Fprintf(&buf, "<input type='hidden' name='view' value='%s'/>\n", escape(snapshot.View().ID()))
+	fmt.Fprintf(&buf, "<input type='hidden' name='pkg' value='%s'/>\n", escape(string(pkg.Metadata().ID)))
+	fmt.Fprintf(&buf, "<input type='hidden' name='symbol' value='%s'/>\n", escape(symbol))
+	fmt.Fprintf(&buf, "<label>GOARCH: <select name='goarch' onchange='this.form.submit()'>\n")
+	option("", "default ("+snapshot.View().GOARCH()+")", opts.GOARCH)
+	for _, arch := range assemblyArchs {
+		option(arch, arch, opts.GOARCH)
+	}
+	fmt.Fprintf(&buf, "</select></label>\n")
+	if goarch == "amd64" {
+		fmt.Fprintf(&buf, "<label>GOAMD64: <select name='goamd64' onchange='this.form.submit()'>\n")
+		option("", "default", opts.GOAMD64)
+		for _, level := range assemblyGOAMD64 {
+			option(level, level, opts.GOAMD64)
 		}
+		fmt.Fprintf(&buf, "</select></label>\n")
+	}
+	fmt.Fprintf(&buf, "<label>Compiler flags: <select name='gcflags' onchange='this.form.submit()'>\n")
+	for _, f := range assemblyGCFlags {
+		option(f.flags, f.label, opts.GCFlags)
+	}
+	fmt.Fprintf(&buf, "</select></label>\n")
+	fmt.Fprintf(&buf, "</form>\n")
 
-		// In lines of the form
-		//   "\t0x0000 00000 (/file.go:123) NOP..."
-		// replace the "(/file.go:123)" portion with an "L0123" source link.
-		// Skip filenames of the form "<foo>".
-		if parts := insnRx.FindStringSubmatch(line); parts != nil {
-			link := "     " // if unknown
-			if file, linenum, ok := morestrings.CutLast(parts[2], ":"); ok && !strings.HasPrefix(file, "<") {
-				if linenum, err := strconv.Atoi(linenum); err == nil {
-					text := fmt.Sprintf("L%04d", linenum)
-					link = sourceLink(text, web.SrcURL(file, linenum, 1))
+	fmt.Fprintf(&buf, "<div class='listing'>\n")
+
+	// Source lines of the function.
+	if srcFile != "" {
+		if fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(srcFile)); err == nil {
+			if src, err := fh.Content(); err == nil {
+				lines := strings.Split(string(src), "\n")
+				fmt.Fprintf(&buf, "<pre id='source'>")
+				for n := minLine; n <= maxLine && n <= len(lines); n++ {
+					fmt.Fprintf(&buf, "<span class='src-line' id='src-%d' onclick='selectLine(%[1]d, true)'>%4[1]d  %s</span>\n",
+						n, escape(lines[n-1]))
 				}
+				fmt.Fprintf(&buf, "</pre>\n")
 			}
-			fmt.Fprintf(&buf, "%s\t%s\t%s", escape(parts[1]), link, escape(parts[3]))
-		} else {
-			buf.WriteString(escape(line))
 		}
-		buf.WriteByte('\n')
 	}
+
+	fmt.Fprintf(&buf, "<pre id='asm'>\n")
+	buf.Write(asm.Bytes())
+	fmt.Fprintf(&buf, "</pre>\n")
+	fmt.Fprintf(&buf, "</div>\n")
 	return buf.Bytes(), nil
 }
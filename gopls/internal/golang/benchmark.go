@@ -0,0 +1,210 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the comparison of two runs of a set of
+// benchmarks, in the manner of benchstat, and its report.
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A BenchmarkComparison is the result of running a set of benchmarks
+// on two versions of a package.
+type BenchmarkComparison struct {
+	PkgPath  PackagePath
+	Old, New string // descriptions of the two versions, e.g. "HEAD"
+	Deltas   []BenchmarkDelta
+}
+
+// A BenchmarkDelta describes the change in one measurement of one
+// benchmark, such as its time per operation, between two versions.
+type BenchmarkDelta struct {
+	Name     string // e.g. "BenchmarkFoo-8"
+	Unit     string // e.g. "ns/op"
+	Old, New BenchmarkSample
+}
+
+// A BenchmarkSample summarizes the values of a measurement over
+// several runs of a benchmark.
+type BenchmarkSample struct {
+	Median, Min, Max float64
+	N                int
+}
+
+// Percent returns the change from the old median to the new, as a
+// percentage. It is infinite if only the old median is zero.
+func (d BenchmarkDelta) Percent() float64 {
+	return (d.New.Median - d.Old.Median) / d.Old.Median * 100
+}
+
+// Significant reports whether the change is larger than the variation
+// among the runs of each version, that is, whether the ranges of the
+// old and new values are disjoint.
+//
+// This is a cruder test than the Mann-Whitney U-test of benchstat,
+// but it is conservative: it needs no assumptions about the
+// distribution of the values, and with a handful of runs of each
+// version it rarely reports noise as a change.
+func (d BenchmarkDelta) Significant() bool {
+	return d.Old.N > 1 && d.New.N > 1 &&
+		d.Old.Median != d.New.Median &&
+		(d.New.Min > d.Old.Max || d.New.Max < d.Old.Min)
+}
+
+// CompareBenchmarks parses the outputs of two runs of "go test -bench"
+// and returns the change in each measurement of each benchmark
+// reported by both. Deltas are grouped by unit, and ordered as in the
+// new output.
+func CompareBenchmarks(oldOutput, newOutput []byte) []BenchmarkDelta {
+	_, oldValues := parseBenchmarkOutput(oldOutput)
+	newKeys, newValues := parseBenchmarkOutput(newOutput)
+
+	var units []string
+	for _, key := range newKeys {
+		if !slices.Contains(units, key.unit) {
+			units = append(units, key.unit)
+		}
+	}
+	slices.SortStableFunc(newKeys, func(x, y benchmarkKey) int {
+		return slices.Index(units, x.unit) - slices.Index(units, y.unit)
+	})
+
+	var deltas []BenchmarkDelta
+	for _, key := range newKeys {
+		old, ok := oldValues[key]
+		if !ok {
+			continue // benchmark or unit is new
+		}
+		deltas = append(deltas, BenchmarkDelta{
+			Name: key.name,
+			Unit: key.unit,
+			Old:  summarize(old),
+			New:  summarize(newValues[key]),
+		})
+	}
+	return deltas
+}
+
+// A benchmarkKey identifies a measurement of a benchmark.
+type benchmarkKey struct{ name, unit string }
+
+// parseBenchmarkOutput returns the values of each measurement in the
+// output of "go test -bench", and the measurements in order of first
+// appearance. A result line has the form:
+//
+//	BenchmarkFoo-8   	 1000000	      1234 ns/op	     16 B/op
+func parseBenchmarkOutput(output []byte) ([]benchmarkKey, map[benchmarkKey][]float64) {
+	var keys []benchmarkKey
+	values := make(map[benchmarkKey][]float64)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields)%2 != 0 || !benchmarkRe.MatchString(fields[0]) {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue // not an iteration count, e.g. "--- FAIL"
+		}
+		for i := 2; i < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			key := benchmarkKey{fields[0], fields[i+1]}
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = append(values[key], v)
+		}
+	}
+	return keys, values
+}
+
+// summarize returns the median and range of a non-empty list of values.
+func summarize(values []float64) BenchmarkSample {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	n := len(values)
+	median := values[n/2]
+	if n%2 == 0 {
+		median = (values[n/2-1] + values[n/2]) / 2
+	}
+	return BenchmarkSample{Median: median, Min: values[0], Max: values[n-1], N: n}
+}
+
+// BenchmarkComparisonHTML formats the report of a comparison of
+// benchmarks, as a table similar to the output of benchstat.
+func BenchmarkComparisonHTML(cmp *BenchmarkComparison) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html>
+<head>
+<style>
+td, th { font-family: monospace; padding: 0 1em; text-align: right; }
+td:first-child, th:first-child { text-align: left; }
+p { max-width: 6in; }
+</style>
+  <script src="/assets/common.js"></script>
+  <link rel="stylesheet" href="/assets/common.css">
+</head>
+<body>
+<h1>Benchmarks of %s</h1>
+<p>
+  Each value is the median of the runs of the benchmark, followed by
+  the largest deviation from it. A change is shown as "~" if the
+  ranges of the values of the two versions overlap.
+</p>
+`, html.EscapeString(string(cmp.PkgPath)))
+	if len(cmp.Deltas) == 0 {
+		buf.WriteString("<p>(no benchmark results common to both versions)</p>\n")
+	}
+	for i, d := range cmp.Deltas {
+		if i == 0 || d.Unit != cmp.Deltas[i-1].Unit {
+			if i > 0 {
+				buf.WriteString("</table>\n")
+			}
+			fmt.Fprintf(&buf, "<h2>%s</h2>\n<table>\n<tr><th></th><th>%s</th><th>%s</th><th>delta</th></tr>\n",
+				html.EscapeString(d.Unit),
+				html.EscapeString(cmp.Old),
+				html.EscapeString(cmp.New))
+		}
+		delta := "~"
+		if d.Significant() {
+			delta = fmt.Sprintf("%+.2f%%", d.Percent())
+		}
+		fmt.Fprintf(&buf, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(d.Name),
+			formatSample(d.Old),
+			formatSample(d.New),
+			delta)
+	}
+	if len(cmp.Deltas) > 0 {
+		buf.WriteString("</table>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
+
+// formatSample formats the median of a sample and its largest
+// relative deviation, e.g. "1234 ±3%".
+func formatSample(s BenchmarkSample) string {
+	var str string
+	if math.Abs(s.Median) >= 1000 {
+		str = fmt.Sprintf("%.0f", s.Median)
+	} else {
+		str = fmt.Sprintf("%.4g", s.Median)
+	}
+	if s.Median != 0 && s.N > 1 {
+		dev := max(s.Max-s.Median, s.Median-s.Min) / math.Abs(s.Median) * 100
+		str += fmt.Sprintf(" ±%.0f%%", dev)
+	}
+	return str
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompareBenchmarks(t *testing.T) {
+	const oldOutput = `goos: linux
+goarch: amd64
+pkg: example.com/a
+BenchmarkA
+BenchmarkA-8   	 1000000	      1000 ns/op	      16 B/op	       1 allocs/op
+BenchmarkA-8   	 1000000	      1010 ns/op	      16 B/op	       1 allocs/op
+BenchmarkA-8   	 1000000	      990 ns/op	      16 B/op	       1 allocs/op
+BenchmarkB-8   	 2000000	      500 ns/op	       0 B/op	       0 allocs/op
+BenchmarkB-8   	 2000000	      520 ns/op	       0 B/op	       0 allocs/op
+BenchmarkGone-8	 1000	      1 ns/op
+PASS
+`
+	const newOutput = `BenchmarkA-8   	 1000000	      800 ns/op	      16 B/op	       1 allocs/op
+BenchmarkA-8   	 1000000	      810 ns/op	      16 B/op	       1 allocs/op
+BenchmarkA-8   	 1000000	      790 ns/op	      16 B/op	       1 allocs/op
+BenchmarkB-8   	 2000000	      510 ns/op	       8 B/op	       1 allocs/op
+BenchmarkB-8   	 2000000	      530 ns/op	       8 B/op	       1 allocs/op
+BenchmarkNew-8 	 1000	      1 ns/op
+--- FAIL: BenchmarkC
+PASS
+`
+	var got []string
+	for _, d := range CompareBenchmarks([]byte(oldOutput), []byte(newOutput)) {
+		got = append(got, fmt.Sprintf("%s %s %g %g %.0f %t",
+			d.Name, d.Unit, d.Old.Median, d.New.Median, d.Percent(), d.Significant()))
+	}
+	want := []string{
+		"BenchmarkA-8 ns/op 1000 800 -20 true",
+		"BenchmarkB-8 ns/op 510 520 2 false", // ranges overlap
+		"BenchmarkA-8 B/op 16 16 0 false",
+		"BenchmarkB-8 B/op 0 8 +Inf true",
+		"BenchmarkA-8 allocs/op 1 1 0 false",
+		"BenchmarkB-8 allocs/op 0 1 +Inf true",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CompareBenchmarks: unexpected deltas (-want +got):\n%s", diff)
+	}
+}
+
+func TestFormatSample(t *testing.T) {
+	for _, test := range []struct {
+		sample BenchmarkSample
+		want   string
+	}{
+		{BenchmarkSample{Median: 1234567, Min: 1200000, Max: 1240000, N: 3}, "1234567 ±3%"},
+		{BenchmarkSample{Median: 12.345, Min: 12.345, Max: 12.345, N: 3}, "12.35 ±0%"},
+		{BenchmarkSample{Median: 0, N: 3}, "0"},
+		{BenchmarkSample{Median: 5, Min: 5, Max: 5, N: 1}, "5"},
+	} {
+		if got := formatSample(test.sample); got != test.want {
+			t.Errorf("formatSample(%+v) = %q, want %q", test.sample, got, test.want)
+		}
+	}
+}
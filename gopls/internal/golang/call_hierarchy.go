@@ -77,22 +77,66 @@ func IncomingCalls(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle
 		return nil, err
 	}
 
-	// Group references by their enclosing function declaration.
-	incomingCalls := make(map[protocol.Location]*protocol.CallHierarchyIncomingCall)
+	// The references to a method include those to corresponding
+	// methods: the interface methods that a concrete method
+	// implements, or the concrete methods that implement an
+	// interface method. Calls of an interface method on behalf of
+	// a concrete one are dynamic.
+	var (
+		method   bool // query is a method
+		abstract bool // query is an interface method
+	)
+	if pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI()); err == nil {
+		if p, err := pgf.PositionPos(pos); err == nil {
+			_, obj, _ := referencedObject(pkg, pgf, p)
+			if fn, ok := obj.(*types.Func); ok && fn.Signature().Recv() != nil {
+				method, abstract = true, isInterfaceMethod(fn)
+			}
+		}
+	}
+	dynamicCalls := snapshot.Options().CallHierarchyDynamicCalls
+
+	// Group references by their enclosing function declaration,
+	// and whether they are dynamic.
+	type key struct {
+		loc     protocol.Location
+		dynamic bool
+	}
+	incomingCalls := make(map[key]*protocol.CallHierarchyIncomingCall)
 	for _, ref := range refs {
+		dynamic := false
+		if method {
+			callee, err := calleeAt(ctx, snapshot, ref.location)
+			if err != nil {
+				event.Error(ctx, fmt.Sprintf("error getting callee at %v", ref.location), err)
+				continue
+			}
+			if callee != nil && isInterfaceMethod(callee) != abstract {
+				if !dynamicCalls {
+					continue // a call of a corresponding method
+				}
+				dynamic = !abstract
+			}
+		}
 		callItem, err := enclosingNodeCallItem(ctx, snapshot, ref.pkgPath, ref.location)
 		if err != nil {
 			event.Error(ctx, fmt.Sprintf("error getting enclosing node for %q", ref.pkgPath), err)
 			continue
 		}
-		loc := protocol.Location{
-			URI:   callItem.URI,
-			Range: callItem.Range,
+		if dynamic {
+			callItem.Detail = "dynamic • " + callItem.Detail
 		}
-		call, ok := incomingCalls[loc]
+		k := key{
+			loc: protocol.Location{
+				URI:   callItem.URI,
+				Range: callItem.Range,
+			},
+			dynamic: dynamic,
+		}
+		call, ok := incomingCalls[k]
 		if !ok {
 			call = &protocol.CallHierarchyIncomingCall{From: callItem}
-			incomingCalls[loc] = call
+			incomingCalls[k] = call
 		}
 		call.FromRanges = append(call.FromRanges, ref.location.Range)
 	}
@@ -105,6 +149,35 @@ func IncomingCalls(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle
 	return incomingCallItems, nil
 }
 
+// calleeAt returns the function or method referred to by the
+// identifier at loc, or nil if there is none.
+func calleeAt(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) (*types.Func, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := pgf.PositionPos(loc.Range.Start)
+	if err != nil {
+		return nil, err
+	}
+	path, _ := astutil.PathEnclosingInterval(pgf.File, pos, pos)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	id, ok := path[0].(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	fn, _ := pkg.TypesInfo().Uses[id].(*types.Func)
+	return fn, nil
+}
+
+// isInterfaceMethod reports whether fn is a method of an interface.
+func isInterfaceMethod(fn *types.Func) bool {
+	recv := fn.Signature().Recv()
+	return recv != nil && types.IsInterface(recv.Type())
+}
+
 // enclosingNodeCallItem creates a CallHierarchyItem representing the function call at loc.
 func enclosingNodeCallItem(ctx context.Context, snapshot *cache.Snapshot, pkgPath PackagePath, loc protocol.Location) (protocol.CallHierarchyItem, error) {
 	// Parse the file containing the reference.
@@ -307,9 +380,60 @@ func OutgoingCalls(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle
 		outgoingCall.FromRanges = append(outgoingCall.FromRanges, rng)
 	}
 
-	outgoingCallItems := make([]protocol.CallHierarchyOutgoingCall, 0, len(outgoingCalls))
+	// Calls of interface methods are dynamic calls of the
+	// concrete methods that implement them.
+	dynamicCalls := make(map[protocol.Location]*protocol.CallHierarchyOutgoingCall)
+	if snapshot.Options().CallHierarchyDynamicCalls {
+		declFH, err := snapshot.ReadFile(ctx, declPGF.URI)
+		if err != nil {
+			return nil, err
+		}
+		for _, callRange := range callRanges {
+			_, obj, _ := referencedObject(declPkg, declPGF, callRange.start)
+			fn, ok := obj.(*types.Func)
+			if !ok || !isInterfaceMethod(fn) {
+				continue
+			}
+			rng, err := declPGF.PosRange(callRange.start, callRange.end)
+			if err != nil {
+				return nil, err
+			}
+			locs, err := implementations(ctx, snapshot, declFH, rng.Start)
+			if err != nil {
+				event.Error(ctx, fmt.Sprintf("error finding implementations of %s", fn.Name()), err)
+				continue
+			}
+			for _, loc := range locs {
+				outgoingCall, ok := dynamicCalls[loc]
+				if !ok {
+					var pkgPath PackagePath
+					if mps, err := snapshot.MetadataForFile(ctx, loc.URI); err == nil && len(mps) > 0 {
+						pkgPath = mps[0].PkgPath
+					}
+					outgoingCall = &protocol.CallHierarchyOutgoingCall{
+						To: protocol.CallHierarchyItem{
+							Name:           fn.Name(),
+							Kind:           protocol.Method,
+							Tags:           []protocol.SymbolTag{},
+							Detail:         fmt.Sprintf("dynamic • %s • %s", pkgPath, filepath.Base(loc.URI.Path())),
+							URI:            loc.URI,
+							Range:          loc.Range,
+							SelectionRange: loc.Range,
+						},
+					}
+					dynamicCalls[loc] = outgoingCall
+				}
+				outgoingCall.FromRanges = append(outgoingCall.FromRanges, rng)
+			}
+		}
+	}
+
+	outgoingCallItems := make([]protocol.CallHierarchyOutgoingCall, 0, len(outgoingCalls)+len(dynamicCalls))
 	for _, callItem := range outgoingCalls {
 		outgoingCallItems = append(outgoingCallItems, *callItem)
 	}
+	for _, callItem := range dynamicCalls {
+		outgoingCallItems = append(outgoingCallItems, *callItem)
+	}
 	return outgoingCallItems, nil
 }
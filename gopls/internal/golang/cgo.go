@@ -0,0 +1,457 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines hover and definition for references to C symbols
+// (C.name) in cgo files. The type checker resolves such references to
+// declarations generated by cgo (see [cache.CgoName]), which are of
+// little interest to users, so we look for the C declaration instead.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+)
+
+// A cgoDecl is the C declaration of a symbol referenced by a cgo file.
+type cgoDecl struct {
+	loc  protocol.Location // of the declared name
+	text string            // declaration, without any function body
+	doc  string            // text of the preceding comment, if any
+}
+
+// findCgoDecl returns the C declaration of the symbol referred to as
+// C.name by the cgo files of pkg, or nil if it cannot be found.
+//
+// It searches the preambles of the import "C" declarations, then the
+// headers that they include from the package directory or from the
+// directories of -I flags in #cgo directives. System headers are not
+// searched.
+func findCgoDecl(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, name string) (*cgoDecl, error) {
+	// Tags of structs, unions, and enums are referred to as C.struct_tag.
+	var keyword string
+	for _, kw := range []string{"struct", "union", "enum"} {
+		if tag, ok := strings.CutPrefix(name, kw+"_"); ok {
+			keyword, name = kw, tag
+			break
+		}
+	}
+
+	var includes, includeDirs []string
+	var dir string // package directory
+	for _, pgf := range pkg.CompiledGoFiles() {
+		preamble := cgoPreamble(pgf)
+		if preamble == nil {
+			continue
+		}
+		dir = pgf.URI.DirPath()
+
+		// Blank out the markers of the Go comments that form the
+		// preamble, preserving offsets within the file.
+		start, end, err := safetoken.Offsets(pgf.Tok, preamble.Pos(), preamble.End())
+		if err != nil {
+			return nil, err
+		}
+		src := []byte(strings.Repeat(" ", start) + string(pgf.Src[start:end]))
+		for _, c := range preamble.List {
+			i, err := safetoken.Offset(pgf.Tok, c.Pos())
+			if err != nil {
+				return nil, err
+			}
+			copy(src[i:], "  ")
+			if strings.HasPrefix(c.Text, "/*") {
+				copy(src[i+len(c.Text)-2:], "  ")
+			}
+		}
+
+		s := scanC(src, keyword, name)
+		if s.found {
+			loc, err := pgf.Mapper.OffsetLocation(s.start, s.end)
+			if err != nil {
+				return nil, err
+			}
+			return &cgoDecl{loc: loc, text: s.text, doc: s.doc}, nil
+		}
+		includes = append(includes, s.includes...)
+		for _, d := range s.includeDirs {
+			d = strings.ReplaceAll(d, "${SRCDIR}", dir)
+			if !filepath.IsAbs(d) {
+				d = filepath.Join(dir, d)
+			}
+			includeDirs = append(includeDirs, d)
+		}
+	}
+
+	for _, inc := range includes {
+		// Only "quoted" includes are relative to the package directory.
+		dirs := includeDirs
+		if inc[0] == '"' {
+			dirs = append([]string{dir}, dirs...)
+		}
+		for _, d := range dirs {
+			uri := protocol.URIFromPath(filepath.Join(d, inc[1:len(inc)-1]))
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				return nil, err // context cancelled
+			}
+			content, err := fh.Content()
+			if err != nil {
+				continue // no such header
+			}
+			if s := scanC(content, keyword, name); s.found {
+				loc, err := protocol.NewMapper(uri, content).OffsetLocation(s.start, s.end)
+				if err != nil {
+					return nil, err
+				}
+				return &cgoDecl{loc: loc, text: s.text, doc: s.doc}, nil
+			}
+			break // header found, without the declaration
+		}
+	}
+	return nil, nil
+}
+
+// cgoPreamble returns the comment that precedes the import "C"
+// declaration of a cgo file, or nil if there is none.
+func cgoPreamble(pgf *parsego.File) *ast.CommentGroup {
+	for _, decl := range pgf.File.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			break // imports come first
+		}
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if spec.Path.Value == `"C"` {
+				if spec.Doc == nil && !decl.Lparen.IsValid() {
+					return decl.Doc
+				}
+				return spec.Doc
+			}
+		}
+	}
+	return nil
+}
+
+// cScan is the result of scanC.
+type cScan struct {
+	found       bool
+	start, end  int    // offsets of the declared name
+	text        string // declaration, without any function body
+	doc         string // text of the preceding comment
+	includes    []string
+	includeDirs []string
+}
+
+// scanC scans C source for the declaration of name, or of the tag of a
+// struct, union, or enum if keyword is nonzero. It is not a parser: since
+// C requires declaration before use, it assumes that the first mention
+// of the name outside a function body is its declaration. If it does not
+// find the declaration, it reports the includes of the source (with
+// their quotes or angle brackets), and the directories of -I flags in
+// #cgo directives.
+//
+// Blanks in src are ignored, so it may be the entire content of a file
+// whose other parts are blanked out.
+func scanC(src []byte, keyword, name string) cScan {
+	var (
+		res       cScan
+		comments  [][2]int // offsets of comments
+		stmtStart = -1     // offset of the statement enclosing the current token
+		braces    []bool   // enclosing braces; true for function bodies
+		last      byte     // last character of the previous token
+	)
+	inBody := func() bool {
+		for _, body := range braces {
+			if body {
+				return true
+			}
+		}
+		return false
+	}
+	// found records the declaration of the name at src[start:end].
+	found := func(start, end, declEnd int) cScan {
+		if stmtStart < 0 {
+			stmtStart = start
+		}
+		res.found = true
+		res.start, res.end = start, end
+		res.text = strings.TrimSpace(string(src[stmtStart:declEnd]))
+		// Collect the comments that are adjacent to the statement.
+		next := stmtStart
+		var doc []string
+		for i := len(comments) - 1; i >= 0; i-- {
+			c := comments[i]
+			if gap := string(src[c[1]:next]); strings.TrimSpace(gap) != "" || strings.Count(gap, "\n") > 1 {
+				break
+			}
+			doc = append([]string{commentText(string(src[c[0]:c[1]]))}, doc...)
+			next = c[0]
+		}
+		res.doc = strings.Join(doc, "\n")
+		return res
+	}
+
+	atLineStart := true
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			atLineStart = true
+			i++
+			continue
+
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+
+		case bytes.HasPrefix(src[i:], []byte("//")):
+			end := lineEnd(src, i, false)
+			comments = append(comments, [2]int{i, end})
+			i = end
+			continue
+
+		case bytes.HasPrefix(src[i:], []byte("/*")):
+			end := len(src)
+			if j := bytes.Index(src[i+2:], []byte("*/")); j >= 0 {
+				end = i + 2 + j + 2
+			}
+			comments = append(comments, [2]int{i, end})
+			i = end
+			continue
+
+		case c == '#' && atLineStart:
+			// A preprocessor directive.
+			end := lineEnd(src, i, true)
+			fields := strings.Fields(string(src[i+1 : end]))
+			switch {
+			case len(fields) < 2:
+			case fields[0] == "define" && keyword == "":
+				macro, _, _ := strings.Cut(fields[1], "(")
+				if macro == name {
+					directive := string(src[i:end])
+					k := strings.Index(directive, "define") + len("define")
+					start := i + k + strings.Index(directive[k:], macro)
+					stmtStart = i
+					return found(start, start+len(name), end)
+				}
+			case fields[0] == "include":
+				res.includes = append(res.includes, fields[1])
+			case fields[0] == "cgo":
+				res.includeDirs = append(res.includeDirs, cgoIncludeDirs(fields[1:])...)
+			}
+			stmtStart = -1
+			i = end
+			atLineStart = true
+			continue
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = min(j+1, len(src))
+
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			j := i
+			for isCIdentByte(src, j) {
+				j++
+			}
+			if stmtStart < 0 {
+				stmtStart = i
+			}
+			word := string(src[i:j])
+			if !inBody() {
+				if keyword == "" && word == name {
+					return found(i, j, declEnd(src, j))
+				}
+				if keyword != "" && word == keyword {
+					k := j
+					for k < len(src) && strings.IndexByte(" \t\r\n", src[k]) >= 0 {
+						k++
+					}
+					if bytes.HasPrefix(src[k:], []byte(name)) && !isCIdentByte(src, k+len(name)) {
+						return found(k, k+len(name), declEnd(src, k+len(name)))
+					}
+				}
+			}
+			last = src[j-1]
+			i = j
+			atLineStart = false
+			continue
+
+		default:
+			if stmtStart < 0 {
+				stmtStart = i
+			}
+			switch c {
+			case '{':
+				braces = append(braces, last == ')')
+			case '}':
+				// The end of a function body also ends its statement.
+				if n := len(braces); n > 0 {
+					if braces[n-1] && n == 1 {
+						stmtStart = -1
+					}
+					braces = braces[:n-1]
+				}
+			case ';':
+				if len(braces) == 0 {
+					stmtStart = -1
+				}
+			}
+			i++
+		}
+		last = c
+		atLineStart = false
+	}
+	return res
+}
+
+// declEnd returns the offset of the end of the declaration whose name
+// ends at offset i of src: the end of the parameter list of a function,
+// or the end of the statement (excluding its semicolon).
+func declEnd(src []byte, i int) int {
+	for i < len(src) && strings.IndexByte(" \t\r\n", src[i]) >= 0 {
+		i++
+	}
+	depth := 0
+	function := i < len(src) && src[i] == '('
+	for ; i < len(src); i++ {
+		switch src[i] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth < 0 {
+				return i + 1 // end of enclosing braces, e.g. of an enum
+			}
+			if depth == 0 && function {
+				return i + 1
+			}
+		case ';', ',':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// lineEnd returns the offset of the end of the line of src containing
+// offset i. If continued, backslash-newline sequences continue the line.
+func lineEnd(src []byte, i int, continued bool) int {
+	for i < len(src) && src[i] != '\n' {
+		if continued && src[i] == '\\' && i+1 < len(src) && src[i+1] == '\n' {
+			i++
+		}
+		i++
+	}
+	return i
+}
+
+// isCIdentByte reports whether src[i] is part of a C identifier.
+func isCIdentByte(src []byte, i int) bool {
+	if i >= len(src) {
+		return false
+	}
+	c := src[i]
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// commentText returns the text of a C comment, without its markers.
+func commentText(comment string) string {
+	if text, ok := strings.CutPrefix(comment, "//"); ok {
+		return strings.TrimSpace(text)
+	}
+	comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// cgoIncludeDirs returns the directories of the -I flags in the
+// arguments of a #cgo directive, such as
+//
+//	#cgo linux CFLAGS: -I${SRCDIR}/include -DDEBUG
+func cgoIncludeDirs(args []string) []string {
+	var dirs []string
+	for i, arg := range args {
+		if strings.HasSuffix(arg, "CFLAGS:") || strings.HasSuffix(arg, "CPPFLAGS:") {
+			flags := args[i+1:]
+			for j, flag := range flags {
+				if dir, ok := strings.CutPrefix(flag, "-I"); ok {
+					if dir == "" && j+1 < len(flags) {
+						dir = flags[j+1]
+					}
+					if dir != "" {
+						dirs = append(dirs, dir)
+					}
+				}
+			}
+			break
+		}
+	}
+	return dirs
+}
+
+// hoverCgo computes hover information for a reference to the C symbol
+// declared in Go as obj, whose C name is name.
+func hoverCgo(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, obj types.Object, name string, qual types.Qualifier) (*hoverResult, error) {
+	// Show the Go view of the C declaration, as seen by the user.
+	// Types are shown with their underlying type, since the names of
+	// anonymous C types are arbitrary, and cgo declares a C variable
+	// as a pointer to it.
+	var signature string
+	switch obj := obj.(type) {
+	case *types.TypeName:
+		signature = fmt.Sprintf("type %s %s", obj.Name(), types.TypeString(obj.Type().Underlying(), qual))
+	case *types.Var:
+		typ := obj.Type()
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		signature = fmt.Sprintf("var %s %s", obj.Name(), types.TypeString(typ, qual))
+	default:
+		signature = objectString(obj, qual, token.NoPos, nil, nil)
+	}
+	signature = cache.DemangleCgo(signature)
+	h := &hoverResult{
+		signature:  signature,
+		singleLine: signature,
+		symbolName: "C." + name,
+	}
+	decl, err := findCgoDecl(ctx, snapshot, pkg, name)
+	if err != nil {
+		return nil, err
+	}
+	if decl != nil {
+		h.synopsis = doc.Synopsis(decl.doc)
+		h.fullDocumentation = decl.doc
+		if snapshot.Options().PreferredContentFormat == protocol.Markdown {
+			h.footer = fmt.Sprintf("```c\n%s\n```", decl.text)
+		} else {
+			h.footer = decl.text
+		}
+	}
+	return h, nil
+}
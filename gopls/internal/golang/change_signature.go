@@ -323,7 +323,12 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		newContent[pgf.URI] = src
 	}
 
-	// Translate the resulting state into document changes.
+	return contentChanges(ctx, snapshot, newContent)
+}
+
+// contentChanges returns the document changes that replace the content of
+// each file in newContent.
+func contentChanges(ctx context.Context, snapshot *cache.Snapshot, newContent map[protocol.DocumentURI][]byte) ([]protocol.DocumentChange, error) {
 	var changes []protocol.DocumentChange
 	for uri, after := range newContent {
 		fh, err := snapshot.ReadFile(ctx, uri)
@@ -485,6 +490,7 @@ type signatureRewrite struct {
 	params            *ast.FieldList
 	callArgs          []ast.Expr
 	variadic          bool
+	extraDecls        string // source of additional declarations used by newDecl, if any
 }
 
 // rewriteCalls returns the document changes required to rewrite the
@@ -620,6 +626,9 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		// TODO(rfindley): we can probably get away with one fewer parse operations
 		// by returning the modified AST from replaceDecl. Investigate if that is
 		// accurate.
+		if rw.extraDecls != "" {
+			modifiedSrc = append(modifiedSrc, []byte("\n\n"+rw.extraDecls)...)
+		}
 		modifiedSrc = append(modifiedSrc, []byte("\n\n"+FormatNode(fset, wrapper))...)
 		modifiedFile, err = parser.ParseFile(rw.pkg.FileSet(), rw.pgf.URI.Path(), modifiedSrc, parser.ParseComments|parser.SkipObjectResolution)
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 	"go/token"
 	"go/types"
 	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/cache"
@@ -55,13 +56,59 @@ func runTestCodeLens(ctx context.Context, snapshot *cache.Snapshot, fh file.Hand
 		codeLens = append(codeLens, protocol.CodeLens{Range: rng, Command: cmd})
 	}
 
+	// Benchmarks may also be compared with the HEAD revision,
+	// and, if the package has unsaved changes, with its saved files.
+	unsaved := false
+	for _, o := range snapshot.Overlays() {
+		if !o.SameContentsOnDisk() && slices.Contains(pkg.Metadata().CompiledGoFiles, o.URI()) {
+			unsaved = true
+			break
+		}
+	}
 	for _, fn := range benchFuncs {
+		rng := protocol.Range{Start: fn.rng.Start, End: fn.rng.Start}
 		cmd := command.NewRunTestsCommand("run benchmark", command.RunTestsArgs{
 			URI:        puri,
 			Benchmarks: []string{fn.name},
 		})
+		compare := command.NewCompareBenchmarksCommand("compare with HEAD", command.CompareBenchmarksArgs{
+			URI:          puri,
+			Benchmarks:   []string{fn.name},
+			Ref:          "HEAD",
+			ShowDocument: true,
+		})
+		codeLens = append(codeLens,
+			protocol.CodeLens{Range: rng, Command: cmd},
+			protocol.CodeLens{Range: rng, Command: compare})
+		if unsaved {
+			compare := command.NewCompareBenchmarksCommand("compare unsaved changes", command.CompareBenchmarksArgs{
+				URI:          puri,
+				Benchmarks:   []string{fn.name},
+				ShowDocument: true,
+			})
+			codeLens = append(codeLens, protocol.CodeLens{Range: rng, Command: compare})
+		}
+	}
+
+	fuzzFuncs, err := fuzzFuncs(pkg.TypesInfo(), pgf)
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range fuzzFuncs {
 		rng := protocol.Range{Start: fn.rng.Start, End: fn.rng.Start}
-		codeLens = append(codeLens, protocol.CodeLens{Range: rng, Command: cmd})
+		fuzz := command.NewRunFuzzCommand("fuzz (30s)", command.RunFuzzArgs{
+			URI:      puri,
+			Fuzz:     fn.name,
+			FuzzTime: "30s",
+		})
+		corpus := command.NewFuzzCorpusCommand("browse corpus", command.FuzzCorpusArgs{
+			URI:          puri,
+			Fuzz:         fn.name,
+			ShowDocument: true,
+		})
+		codeLens = append(codeLens,
+			protocol.CodeLens{Range: rng, Command: fuzz},
+			protocol.CodeLens{Range: rng, Command: corpus})
 	}
 
 	if len(benchFuncs) > 0 {
@@ -7,10 +7,12 @@ package golang
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"os"
 	"path/filepath"
 	"reflect"
 	"slices"
@@ -21,6 +23,7 @@ import (
 	"golang.org/x/tools/gopls/internal/analysis/fillstruct"
 	"golang.org/x/tools/gopls/internal/analysis/fillswitch"
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang/stubmethods"
@@ -238,10 +241,15 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: protocol.SourceOrganizeImports, fn: sourceOrganizeImports},
 	{kind: settings.AddTest, fn: addTest, needPkg: true},
 	{kind: settings.GoAssembly, fn: goAssembly, needPkg: true},
+	{kind: settings.GoCustom, fn: goCustom},
 	{kind: settings.GoDoc, fn: goDoc, needPkg: true},
 	{kind: settings.GoFreeSymbols, fn: goFreeSymbols},
+	{kind: settings.GoGenerate, fn: goGenerate},
+	{kind: settings.GoGenerateConversion, fn: goGenerateConversion, needPkg: true},
+	{kind: settings.GoShowGenerator, fn: goShowGenerator},
 	{kind: settings.GoTest, fn: goTest},
 	{kind: settings.GoToggleCompilerOptDetails, fn: toggleCompilerOptDetails},
+	{kind: settings.GoUpdateExampleOutput, fn: goUpdateExampleOutput},
 	{kind: settings.GoplsDocFeatures, fn: goplsDocFeatures},
 	{kind: settings.RefactorExtractFunction, fn: refactorExtractFunction},
 	{kind: settings.RefactorExtractMethod, fn: refactorExtractMethod},
@@ -251,11 +259,17 @@ var codeActionProducers = [...]codeActionProducer{
 	{kind: settings.RefactorExtractConstantAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorExtractVariableAll, fn: refactorExtractVariableAll, needPkg: true},
 	{kind: settings.RefactorInlineCall, fn: refactorInlineCall, needPkg: true},
+	{kind: settings.RefactorRewriteAddFuzzSeed, fn: refactorRewriteAddFuzzSeed, needPkg: true},
+	{kind: settings.RefactorRewriteAddTypeArgs, fn: refactorRewriteAddTypeArgs, needPkg: true},
 	{kind: settings.RefactorRewriteChangeQuote, fn: refactorRewriteChangeQuote},
 	{kind: settings.RefactorRewriteFillStruct, fn: refactorRewriteFillStruct, needPkg: true},
+	{kind: settings.RefactorRewriteFillStructFrom, fn: refactorRewriteFillStructFrom, needPkg: true},
 	{kind: settings.RefactorRewriteFillSwitch, fn: refactorRewriteFillSwitch, needPkg: true},
+	{kind: settings.RefactorRewriteInferConstraint, fn: refactorRewriteInferConstraint, needPkg: true},
+	{kind: settings.RefactorRewriteIntroduceOptions, fn: refactorRewriteIntroduceOptions, needPkg: true},
 	{kind: settings.RefactorRewriteInvertIf, fn: refactorRewriteInvertIf},
 	{kind: settings.RefactorRewriteJoinLines, fn: refactorRewriteJoinLines, needPkg: true},
+	{kind: settings.RefactorRewriteRemoveTypeArgs, fn: refactorRewriteRemoveTypeArgs, needPkg: true},
 	{kind: settings.RefactorRewriteRemoveUnusedParam, fn: refactorRewriteRemoveUnusedParam, needPkg: true},
 	{kind: settings.RefactorRewriteMoveParamLeft, fn: refactorRewriteMoveParamLeft, needPkg: true},
 	{kind: settings.RefactorRewriteMoveParamRight, fn: refactorRewriteMoveParamRight, needPkg: true},
@@ -355,12 +369,76 @@ func quickFix(ctx context.Context, req *codeActionsRequest) error {
 			if title != "" {
 				req.addApplyFixAction(title, fixCreateUndeclared, req.loc)
 			}
+
+		// "could not import P" compiler error, for a package P
+		// of the current module whose directory does not exist.
+		// Offer "Create package P" and "Remove import of P" code actions.
+		case strings.HasPrefix(msg, "could not import "):
+			path, _ := astutil.PathEnclosingInterval(req.pgf.File, start, end)
+			for _, n := range path {
+				if spec, ok := n.(*ast.ImportSpec); ok {
+					if err := missingPackageFixes(ctx, req, spec); err != nil {
+						event.Error(ctx, "missing package fixes", err, label.File.Of(req.loc.URI.Path()))
+					}
+					break
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
+// missingPackageFixes offers quick fixes for an import of a package
+// belonging to the current module whose directory does not exist:
+// one to create the directory with a stub file declaring the package,
+// and one to delete the import.
+func missingPackageFixes(ctx context.Context, req *codeActionsRequest, spec *ast.ImportSpec) error {
+	mod := req.pkg.Metadata().Module
+	if mod == nil || mod.Dir == "" {
+		return nil
+	}
+	importPath := metadata.UnquoteImportPath(spec)
+	rel, ok := strings.CutPrefix(string(importPath), mod.Path+"/")
+	if !ok || rel == "" {
+		return nil
+	}
+	dir := filepath.Join(mod.Dir, filepath.FromSlash(rel))
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		return nil // directory exists, or is inaccessible
+	}
+
+	// Create dir/name.go containing "package name".
+	name := imports.ImportPathToAssumedName(string(importPath))
+	fh, err := req.snapshot.ReadFile(ctx, protocol.URIFromPath(filepath.Join(dir, name+".go")))
+	if err != nil {
+		return err // canceled
+	}
+	req.addEditAction(fmt.Sprintf("Create package %s", importPath), nil,
+		protocol.DocumentChangeCreate(fh.URI()),
+		protocol.DocumentChangeEdit(fh, []protocol.TextEdit{
+			{Range: protocol.Range{}, NewText: fmt.Sprintf("package %s\n", name)},
+		}))
+
+	// Delete the import.
+	var localName string
+	if spec.Name != nil {
+		localName = spec.Name.Name
+	}
+	edits, err := ComputeImportFixEdits(req.snapshot.Options().Local, req.pgf.Src, &imports.ImportFix{
+		StmtInfo: imports.ImportInfo{
+			ImportPath: string(importPath),
+			Name:       localName,
+		},
+		FixType: imports.DeleteImport,
+	})
+	if err != nil {
+		return err
+	}
+	req.addEditAction(fmt.Sprintf("Remove import of %s", importPath), nil, protocol.DocumentChangeEdit(req.fh, edits))
+	return nil
+}
+
 // allImportsFixesResult is the result of a lazy call to allImportsFixes.
 // It implements the codeActionsRequest lazyInit interface.
 type allImportsFixesResult struct {
@@ -385,6 +463,8 @@ func importFixTitle(fix *imports.ImportFix) string {
 		str = fmt.Sprintf("Delete import: %s %q", fix.StmtInfo.Name, fix.StmtInfo.ImportPath)
 	case imports.SetImportName:
 		str = fmt.Sprintf("Rename import: %s %q", fix.StmtInfo.Name, fix.StmtInfo.ImportPath)
+	case imports.MergeImport:
+		str = fmt.Sprintf("Merge import: %s %q into %s", fix.StmtInfo.Name, fix.StmtInfo.ImportPath, fix.IdentName)
 	}
 	return str
 }
@@ -575,6 +655,32 @@ func addTest(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// goGenerateConversion produces "Generate conversion from X to Y"
+// and "Regenerate conversion from X to Y" code actions.
+// See [server.commandHandler.GenerateConversion] for command implementation.
+func goGenerateConversion(ctx context.Context, req *codeActionsRequest) error {
+	conv := findConversionFunc(req.pkg, req.pgf, req.start, req.end)
+	if conv == nil {
+		return nil
+	}
+	qual := typesinternal.FileQualifier(req.pgf.File, req.pkg.Types())
+	cmd := command.NewGenerateConversionCommand(conv.title(qual), req.loc)
+	req.addCommandAction(cmd, false)
+	return nil
+}
+
+// refactorRewriteIntroduceOptions produces "Move parameters into struct T"
+// code actions.
+// See [server.commandHandler.IntroduceOptionsStruct] for command implementation.
+func refactorRewriteIntroduceOptions(ctx context.Context, req *codeActionsRequest) error {
+	if opts := findOptionsStruct(req.pkg, req.pgf, req.start, req.end); opts != nil {
+		title := fmt.Sprintf("Move parameters into struct %s", opts.typeName)
+		cmd := command.NewIntroduceOptionsStructCommand(title, req.loc)
+		req.addCommandAction(cmd, false)
+	}
+	return nil
+}
+
 // identityTransform returns a change signature transformation that leaves the
 // given fieldlist unmodified.
 func identityTransform(fields *ast.FieldList) []command.ChangeSignatureParam {
@@ -661,6 +767,21 @@ func refactorRewriteChangeQuote(ctx context.Context, req *codeActionsRequest) er
 	return nil
 }
 
+// refactorRewriteAddTypeArgs produces "Add explicit type arguments" code actions.
+func refactorRewriteAddTypeArgs(ctx context.Context, req *codeActionsRequest) error {
+	return addTypeArgs(req)
+}
+
+// refactorRewriteInferConstraint produces "Infer constraint of T from usage" code actions.
+func refactorRewriteInferConstraint(ctx context.Context, req *codeActionsRequest) error {
+	return inferConstraint(req)
+}
+
+// refactorRewriteRemoveTypeArgs produces "Remove redundant type arguments" code actions.
+func refactorRewriteRemoveTypeArgs(ctx context.Context, req *codeActionsRequest) error {
+	return removeTypeArgs(req)
+}
+
 // refactorRewriteInvertIf produces "Invert 'if' condition" code actions.
 // See [invertIfCondition] for command implementation.
 func refactorRewriteInvertIf(ctx context.Context, req *codeActionsRequest) error {
@@ -708,6 +829,18 @@ func refactorRewriteFillStruct(ctx context.Context, req *codeActionsRequest) err
 	return nil
 }
 
+// refactorRewriteFillStructFrom produces "Fill from VAR" code actions.
+func refactorRewriteFillStructFrom(ctx context.Context, req *codeActionsRequest) error {
+	for _, diag := range fillstruct.DiagnoseFrom(req.pkg.FileSet(), req.pgf.File, req.pgf.Src, req.start, req.end, req.pkg.Types(), req.pkg.TypesInfo()) {
+		changes, err := suggestedFixToDocumentChange(ctx, req.snapshot, req.pkg.FileSet(), &diag.SuggestedFixes[0])
+		if err != nil {
+			return err
+		}
+		req.addEditAction(diag.Message, nil, changes...)
+	}
+	return nil
+}
+
 // refactorRewriteFillSwitch produces "Add cases for TYPE/ENUM" code actions.
 func refactorRewriteFillSwitch(ctx context.Context, req *codeActionsRequest) error {
 	for _, diag := range fillswitch.Diagnose(req.pgf.File, req.start, req.end, req.pkg.Types(), req.pkg.TypesInfo()) {
@@ -822,6 +955,90 @@ func goTest(ctx context.Context, req *codeActionsRequest) error {
 	return nil
 }
 
+// goUpdateExampleOutput produces an "Update output" code action for
+// the Example function at the selection, if it has an output comment.
+// See [server.commandHandler.UpdateExampleOutput] for command implementation.
+func goUpdateExampleOutput(ctx context.Context, req *codeActionsRequest) error {
+	fn := enclosingExample(req.pgf, req.loc.Range)
+	if fn == nil {
+		return nil
+	}
+	cmd := command.NewUpdateExampleOutputCommand("Update output of "+fn.Name.Name, command.UpdateExampleOutputArgs{
+		URI:     req.loc.URI,
+		Example: fn.Name.Name,
+	})
+	req.addCommandAction(cmd, false)
+	return nil
+}
+
+// goCustom produces a code action for each custom code action of the
+// customCodeActionsFile setting that applies to the file.
+// See [server.commandHandler.RunCustomCodeAction] for command implementation.
+func goCustom(ctx context.Context, req *codeActionsRequest) error {
+	actions, err := CustomCodeActions(ctx, req.snapshot)
+	if err != nil {
+		// A bad configuration file should not block other code actions.
+		event.Error(ctx, "loading custom code actions", err)
+		return nil
+	}
+	for _, a := range actions {
+		if a.AppliesTo(req.snapshot.Folder(), req.loc.URI) {
+			cmd := command.NewRunCustomCodeActionCommand(a.Title, command.RunCustomCodeActionArgs{
+				Location: req.loc,
+				Title:    a.Title,
+			})
+			req.addCommandAction(cmd, false)
+		}
+	}
+	return nil
+}
+
+// goGenerate produces "Run" and "Preview" code actions for the
+// //go:generate directive at the selection.
+// See [server.commandHandler.Generate] for command implementation.
+func goGenerate(ctx context.Context, req *codeActionsRequest) error {
+	for _, group := range req.pgf.File.Comments {
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, "//go:generate ") {
+				continue
+			}
+			rng, err := req.pgf.NodeRange(c)
+			if err != nil {
+				return err
+			}
+			if !protocol.Intersect(rng, req.loc.Range) {
+				continue
+			}
+			args := command.GenerateArgs{
+				Dir:       req.fh.URI().Dir(),
+				File:      req.fh.URI(),
+				Directive: strings.TrimRight(c.Text, " \t\r"),
+			}
+			req.addCommandAction(command.NewGenerateCommand("Run go:generate directive", args), false)
+			args.Preview = true
+			req.addCommandAction(command.NewGenerateCommand("Preview go:generate directive", args), false)
+			return nil
+		}
+	}
+	return nil
+}
+
+// goShowGenerator produces a "Go to go:generate directive" code action
+// in a generated file whose generator is named by a //go:generate
+// directive in the same directory.
+func goShowGenerator(ctx context.Context, req *codeActionsRequest) error {
+	comment := generatedComment(req.pgf)
+	if comment == nil {
+		return nil
+	}
+	loc, ok, err := generatorDirective(ctx, req.snapshot, req.pgf, comment)
+	if err != nil || !ok {
+		return err
+	}
+	req.addCommandAction(command.NewShowGeneratorCommand("Go to go:generate directive", loc), false)
+	return nil
+}
+
 // goAssembly produces "Browse ARCH assembly for FUNC" code actions.
 // See [server.commandHandler.Assembly] for command implementation.
 func goAssembly(ctx context.Context, req *codeActionsRequest) error {
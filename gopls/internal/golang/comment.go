@@ -5,9 +5,9 @@
 package golang
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"fmt"
 	"go/ast"
 	"go/doc/comment"
 	"go/token"
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/settings"
@@ -29,33 +30,161 @@ var errNoCommentReference = errors.New("no comment reference found")
 
 // DocCommentToMarkdown converts the text of a [doc comment] to Markdown.
 //
-// TODO(adonovan): provide a package (or file imports) as context for
-// proper rendering of doc links; see [newDocCommentParser] and golang/go#61677.
+// Only doc links to standard packages are recognized; use
+// [DocLinks.Markdown] to resolve doc links in the context of the file
+// that declares the comment.
 //
 // [doc comment]: https://go.dev/doc/comment
 func DocCommentToMarkdown(text string, options *settings.Options) string {
-	var parser comment.Parser
+	return (*DocLinks)(nil).Markdown(text, options)
+}
+
+// DocLinks resolves the doc links of a doc comment, such as
+// [fmt.Println] or [Buffer.Write], in the context of the package and
+// file that declare the comment.
+//
+// A nil *DocLinks resolves only doc links to standard packages.
+type DocLinks struct {
+	parser    comment.Parser
+	pkgPath   PackagePath            // path of the declaring package
+	isPrivate func(path string) bool // reports whether path matches GOPRIVATE
+}
+
+// newDocLinks returns a DocLinks that resolves doc links using the
+// imports of the specified file of package mp, whose types are pkg.
+func newDocLinks(snapshot *cache.Snapshot, mp *metadata.Package, pkg *types.Package, file *ast.File) *DocLinks {
+	// importPath returns the path of the package imported by imp, if
+	// its name (if rename) or declared name (otherwise) is name.
+	importPath := func(imp *ast.ImportSpec, name string, rename bool) (string, bool) {
+		dep := snapshot.Metadata(mp.DepsByImpPath[metadata.UnquoteImportPath(imp)])
+		if dep == nil {
+			return "", false
+		}
+		if rename && imp.Name != nil {
+			return string(dep.PkgPath), imp.Name.Name == name
+		}
+		return string(dep.PkgPath), string(dep.Name) == name
+	}
+	return &DocLinks{
+		parser: comment.Parser{
+			LookupPackage: func(name string) (string, bool) {
+				// First try each imported package name, then the
+				// declared name of each import, as some packages are
+				// typically imported under a non-default name (e.g.
+				// pathpkg "path") but referred to in doc links using
+				// their canonical name.
+				for _, rename := range []bool{true, false} {
+					for _, imp := range file.Imports {
+						if path, ok := importPath(imp, name, rename); ok {
+							return path, true
+						}
+					}
+				}
+				// Finally try the last segment of each import path
+				// of the package, as the comment may appear in a
+				// different file from the import.
+				for path := range mp.DepsByPkgPath {
+					if pathpkg.Base(trimVersionSuffix(string(path))) == name {
+						return string(path), true
+					}
+				}
+				return "", false
+			},
+			LookupSym: func(recv, name string) bool {
+				if recv == "" {
+					return pkg.Scope().Lookup(name) != nil
+				}
+				tname, ok := pkg.Scope().Lookup(recv).(*types.TypeName)
+				if !ok {
+					return false
+				}
+				m, _, _ := types.LookupFieldOrMethod(tname.Type(), true, pkg, name)
+				return is[*types.Func](m)
+			},
+		},
+		pkgPath:   mp.PkgPath,
+		isPrivate: snapshot.IsGoPrivatePath,
+	}
+}
+
+// publicLink returns the URL of the documentation of the specified
+// package and fragment on the LinkTarget host, or "" if there is no
+// such host or the package matches GOPRIVATE.
+func (dl *DocLinks) publicLink(options *settings.Options, path PackagePath, fragment string) string {
+	if options.LinkTarget == "" || dl != nil && dl.isPrivate(string(path)) {
+		return ""
+	}
+	return cache.BuildLink(options.LinkTarget, string(path), fragment)
+}
+
+// Markdown converts the text of a doc comment to Markdown. Doc links
+// refer to the documentation on the LinkTarget host.
+func (dl *DocLinks) Markdown(text string, options *settings.Options) string {
+	md, _ := dl.format(text, true, func(path PackagePath, fragment string) string {
+		return dl.publicLink(options, path, fragment)
+	}, 0)
+	return md
+}
+
+// format renders the text of a doc comment as Markdown, or as plain
+// text if !markdown. Doc links refer to the URLs returned by linkURL,
+// which returns "" for no link.
+//
+// If maxLines > 0, format renders only as many of the leading blocks
+// (paragraphs, headings, lists, and code blocks) of the comment as fit
+// in maxLines lines, but at least one, and reports whether any blocks
+// were omitted, so that a comment is never cut in the middle of a block.
+func (dl *DocLinks) format(text string, markdown bool, linkURL func(path PackagePath, fragment string) string, maxLines int) (string, bool) {
+	var (
+		parser  comment.Parser
+		pkgPath PackagePath
+	)
+	if dl != nil {
+		parser, pkgPath = dl.parser, dl.pkgPath
+	}
 	doc := parser.Parse(text)
 
-	var printer comment.Printer
-	// The default produces {#Hdr-...} tags for headings.
-	// vscode displays thems, which is undesirable.
-	// The godoc for comment.Printer says the tags
-	// avoid a security problem.
-	printer.HeadingID = func(*comment.Heading) string { return "" }
-	printer.DocLinkURL = func(link *comment.DocLink) string {
-		msg := fmt.Sprintf("https://%s/%s", options.LinkTarget, link.ImportPath)
-		if link.Name != "" {
-			msg += "#"
+	printer := &comment.Printer{
+		// The default produces {#Hdr-...} tags for headings.
+		// vscode displays thems, which is undesirable.
+		// The godoc for comment.Printer says the tags
+		// avoid a security problem.
+		HeadingID: func(*comment.Heading) string { return "" },
+		DocLinkURL: func(link *comment.DocLink) string {
+			path := pkgPath
+			if link.ImportPath != "" {
+				path = PackagePath(link.ImportPath)
+			}
+			if path == "" {
+				return ""
+			}
+			fragment := link.Name
 			if link.Recv != "" {
-				msg += link.Recv + "."
+				fragment = link.Recv + "." + link.Name
 			}
-			msg += link.Name
-		}
-		return msg
+			return linkURL(path, fragment)
+		},
+	}
+	render := printer.Markdown
+	if !markdown {
+		render = printer.Text
 	}
 
-	return string(printer.Markdown(doc))
+	truncated := false
+	if maxLines > 0 {
+		n := 1
+		for ; n < len(doc.Content); n++ {
+			prefix := &comment.Doc{Content: doc.Content[:n+1], Links: doc.Links}
+			if bytes.Count(render(prefix), []byte("\n")) > maxLines {
+				break
+			}
+		}
+		if n < len(doc.Content) {
+			doc.Content = doc.Content[:n]
+			truncated = true
+		}
+	}
+	return string(render(doc)), truncated
 }
 
 // docLinkDefinition finds the definition of the doc link in comments at pos.
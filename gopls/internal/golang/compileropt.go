@@ -12,18 +12,102 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/filecache"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/internal/event"
 )
 
-// CompilerOptDetails invokes the Go compiler with the "-json=0,dir"
+// CompilerOptDetails returns the compiler's optimization decisions
+// for the packages and tests in the specified directory, as a set of
+// diagnostics, filtered and classified according to the
+// compilerOptDetails and compilerOptDetailsSeverity settings.
+//
+// The decisions are cached in the file cache for each distinct build
+// of the packages, so the compiler is invoked only when the packages
+// or their dependencies have changed.
+func CompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	reports, err := cachedCompilerOptDetails(ctx, snapshot, pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	opts := snapshot.Options()
+	for uri, diags := range reports {
+		diags = slices.DeleteFunc(diags, func(diag *cache.Diagnostic) bool {
+			enabled, ok := opts.CompilerOptDetails[compilerOptDetailKind(diag.Message)]
+			return ok && !enabled
+		})
+		for _, diag := range diags {
+			diag.Severity = opts.CompilerOptDetailsSeverity.Protocol()
+		}
+		reports[uri] = diags
+	}
+	return reports, nil
+}
+
+// compilerOptDetailsKind is the file cache kind of the compiler's
+// optimization decisions.
+const compilerOptDetailsKind = "compileropt"
+
+// cachedCompilerOptDetails returns the unfiltered optimization
+// decisions for the packages in pkgDir, from the file cache if
+// possible, and otherwise by running the compiler.
+func cachedCompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	key, err := snapshot.CompilerOptDetailsKey(ctx, pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := filecache.Get(compilerOptDetailsKind, key); err == nil {
+		var reports map[protocol.DocumentURI][]*cache.Diagnostic
+		if err := json.Unmarshal(data, &reports); err == nil {
+			return reports, nil
+		}
+		event.Error(ctx, "decoding compiler optimization details", err)
+	} else if err != filecache.ErrNotFound {
+		event.Error(ctx, "reading compiler optimization details from filecache", err)
+	}
+
+	reports, err := runCompilerOptDetails(ctx, snapshot, pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(reports)
+	if err != nil {
+		return nil, err
+	}
+	if err := filecache.Set(compilerOptDetailsKind, key, data); err != nil {
+		event.Error(ctx, "writing compiler optimization details to filecache", err)
+	}
+	return reports, nil
+}
+
+// compilerOptDetailKind returns the kind of optimization decision
+// reported by a compiler optimization details message, or "" if the
+// decision belongs to no kind that may be filtered.
+func compilerOptDetailKind(msg string) settings.CompilerOptDetail {
+	code, _, _ := strings.Cut(msg, "(")
+	switch {
+	case code == "isInBounds" || code == "isSliceInBounds":
+		return settings.CompilerOptDetailBounds
+	case code == "escape" || code == "escapes" || code == "leak":
+		return settings.CompilerOptDetailEscape
+	case strings.HasPrefix(code, "canInline") || strings.HasPrefix(code, "cannotInline") || code == "inlineCall":
+		return settings.CompilerOptDetailInline
+	case code == "nilcheck":
+		return settings.CompilerOptDetailNil
+	}
+	return ""
+}
+
+// runCompilerOptDetails invokes the Go compiler with the "-json=0,dir"
 // flag on the packages and tests in the specified directory, parses
 // its log of optimization decisions, and returns them as a set of
 // diagnostics.
-func CompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+func runCompilerOptDetails(ctx context.Context, snapshot *cache.Snapshot, pkgDir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
 	outDir, err := os.MkdirTemp("", fmt.Sprintf("gopls-%d.details", os.Getpid()))
 	if err != nil {
 		return nil, err
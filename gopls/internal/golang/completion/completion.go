@@ -87,6 +87,11 @@ type CompletionItem struct {
 	// A higher score indicates that this completion item is more relevant.
 	Score float64
 
+	// Features describes the item for the purpose of ranking.
+	// Its Score is that of the built-in heuristics, which may
+	// differ from the item's Score if a custom ranker is in use.
+	Features Features
+
 	// snippet is the LSP snippet for the completion item. The LSP
 	// specification contains details about LSP snippets. For example, a
 	// snippet for a function with the following signature:
@@ -106,6 +111,9 @@ type CompletionItem struct {
 	// Documentation is the documentation for the completion item.
 	Documentation string
 
+	// DocLinks, if non-nil, resolves the doc links of Documentation.
+	DocLinks *golang.DocLinks
+
 	// isSlice reports whether the underlying type of the object
 	// from which this candidate was derived is a slice.
 	// (Used to complete append() calls.)
@@ -123,6 +131,7 @@ type completionOptions struct {
 	matcher               settings.Matcher
 	budget                time.Duration
 	completeFunctionCalls bool
+	rankerWeights         string
 }
 
 // Snippet is a convenience returns the snippet if available, otherwise
@@ -629,6 +638,7 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 			snippets:              opts.InsertTextFormat == protocol.SnippetTextFormat,
 			postfix:               opts.ExperimentalPostfixCompletions,
 			completeFunctionCalls: opts.CompleteFunctionCalls,
+			rankerWeights:         opts.CompletionRankerWeights,
 		},
 		// default to a matcher that always matches
 		matcher:            prefixMatcher(""),
@@ -697,11 +707,20 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 	// unimportedMembers etc. for more completion items.
 	c.deepSearch(ctx, 0, deadline)
 
+	// Offer values of types in other workspace packages that
+	// implement the expected interface type.
+	if deadline == nil || time.Now().Before(*deadline) {
+		if err := c.implementingTypes(ctx); err != nil && ctx.Err() == nil {
+			event.Error(ctx, "finding implementing types", err)
+		}
+	}
+
 	// Statement candidates offer an entire statement in certain contexts, as
 	// opposed to a single object. Add statement candidates last because they
 	// depend on other candidates having already been collected.
 	c.addStatementCandidates()
 
+	c.rankItems(ctx)
 	c.sortItems()
 	return c.items, c.getSurrounding(), nil
 }
@@ -830,6 +849,35 @@ func (c *completer) scanToken(contents []byte) (token.Pos, token.Token, string)
 	}
 }
 
+// rankItems computes the ranking features of each item and, if the
+// user has configured a ranker of their own, scores each item by it.
+func (c *completer) rankItems(ctx context.Context) {
+	ranker := DefaultRanker
+	if c.opts.rankerWeights != "" {
+		if r, err := LoadRanker(c.opts.rankerWeights); err != nil {
+			event.Error(ctx, "loading completion ranker", err)
+		} else {
+			ranker = r
+		}
+	}
+	prefixLen := len(c.getSurrounding().Prefix())
+	expectedType := c.inference.objType != nil
+	for i := range c.items {
+		item := &c.items[i]
+		item.Features = Features{
+			Score:        item.Score,
+			Depth:        item.Depth,
+			Kind:         fmt.Sprint(item.Kind),
+			Deprecated:   item.Deprecated || slices.Contains(item.Tags, protocol.ComplDeprecated),
+			Edits:        len(item.AdditionalTextEdits) > 0,
+			LabelLen:     len(item.Label),
+			PrefixLen:    prefixLen,
+			ExpectedType: expectedType,
+		}
+		item.Score = ranker.Rank(item.Features)
+	}
+}
+
 func (c *completer) sortItems() {
 	sort.SliceStable(c.items, func(i, j int) bool {
 		// Sort by score first.
@@ -1414,38 +1462,13 @@ func (c *completer) selector(ctx context.Context, sel *ast.SelectorExpr) error {
 
 			// For functions, add a parameter snippet.
 			if fn != nil {
-				paramList := func(list *ast.FieldList) []string {
-					var params []string
-					if list != nil {
-						var cfg printer.Config // slight overkill
-						param := func(name string, typ ast.Expr) {
-							var buf strings.Builder
-							buf.WriteString(name)
-							buf.WriteByte(' ')
-							cfg.Fprint(&buf, token.NewFileSet(), typ)
-							params = append(params, buf.String())
-						}
-
-						for _, field := range list.List {
-							if field.Names != nil {
-								for _, name := range field.Names {
-									param(name.Name, field.Type)
-								}
-							} else {
-								param("_", field.Type)
-							}
-						}
-					}
-					return params
-				}
-
 				// Ideally we would eliminate the suffix of type
 				// parameters that are redundant with inference
 				// from the argument types (#51783), but it's
 				// quite fiddly to do using syntax alone.
 				// (See inferableTypeParams in format.go.)
-				tparams := paramList(fn.Type.TypeParams)
-				params := paramList(fn.Type.Params)
+				tparams := syntaxParamList(fn.Type.TypeParams)
+				params := syntaxParamList(fn.Type.Params)
 				var sn snippet.Builder
 				c.functionCallSnippet(id.Name, tparams, params, &sn)
 				item.snippet = &sn
@@ -2010,9 +2033,20 @@ func enclosingCompositeLiteral(path []ast.Node, pos token.Pos, info *types.Info)
 				return nil
 			}
 
+			// The type of a literal may be a type parameter, such as
+			// T in T{...}, whose core type determines the kind of
+			// literal: field names of struct T ~struct{...}, or
+			// elements of S ~[]E, including elided literals of
+			// such elements at any depth.
+			clType := typesinternal.Unpointer(tv.Type)
+			if core := typeparams.CoreType(clType); core != nil {
+				clType = core
+			} else {
+				clType = clType.Underlying()
+			}
 			clInfo := compLitInfo{
 				cl:     n,
-				clType: typesinternal.Unpointer(tv.Type).Underlying(),
+				clType: clType,
 			}
 
 			var (
@@ -3724,6 +3758,33 @@ func forEachPackageMember(content []byte, f func(tok token.Token, id *ast.Ident,
 	}
 }
 
+// syntaxParamList formats each parameter of a function declared in
+// another package, using only its syntax, as "name type".
+func syntaxParamList(list *ast.FieldList) []string {
+	var params []string
+	if list != nil {
+		var cfg printer.Config // slight overkill
+		param := func(name string, typ ast.Expr) {
+			var buf strings.Builder
+			buf.WriteString(name)
+			buf.WriteByte(' ')
+			cfg.Fprint(&buf, token.NewFileSet(), typ)
+			params = append(params, buf.String())
+		}
+
+		for _, field := range list.List {
+			if field.Names != nil {
+				for _, name := range field.Names {
+					param(name.Name, field.Type)
+				}
+			} else {
+				param("_", field.Type)
+			}
+		}
+	}
+	return params
+}
+
 func is[T any](x any) bool {
 	_, ok := x.(T)
 	return ok
@@ -262,6 +262,9 @@ Suffixes:
 	} else {
 		item.Documentation = doc.Synopsis(comment.Text())
 	}
+	if strings.Contains(item.Documentation, "[") { // may contain doc links
+		item.DocLinks = golang.DocLinksForObject(ctx, c.snapshot, c.pkg, obj)
+	}
 	if internalastutil.Deprecation(comment) != "" {
 		if c.snapshot.Options().CompletionTags {
 			item.Tags = []protocol.CompletionItemTag{protocol.ComplDeprecated}
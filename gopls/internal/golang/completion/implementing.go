@@ -0,0 +1,288 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+// This file defines completion candidates that construct a value of a
+// type, declared in a workspace package not yet imported by the
+// current file, that implements the expected interface type.
+//
+// Deep completion already offers such candidates for the types of
+// imported packages. Like the completion of unimported package
+// members, this search uses only the method-set index and syntax, as
+// the types of the other packages are generally not available.
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/methodsets"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/golang/completion/snippet"
+	"golang.org/x/tools/gopls/internal/protocol"
+	goplsastutil "golang.org/x/tools/gopls/internal/util/astutil"
+	"golang.org/x/tools/internal/imports"
+)
+
+// implementingTypes adds completion items for composite literals and
+// constructor calls of types that implement the expected interface
+// type, such as "&pkg.T{}" or "pkg.NewT()". Candidates are ranked by
+// the proximity of their package to the current one.
+func (c *completer) implementingTypes(ctx context.Context) error {
+	expType := c.inference.objType
+	if !c.opts.unimported || !c.opts.snippets || expType == nil ||
+		c.wantTypeName() || c.inference.typeName.isTypeParam ||
+		is[*types.TypeParam](expType) || !types.IsInterface(expType) || isEmptyInterface(expType) {
+		return nil
+	}
+	if enclosingSelector(c.path, c.pos) != nil {
+		return nil // the package is already chosen
+	}
+	key, hasMethods := methodsets.KeyOf(expType)
+	if !hasMethods {
+		return nil
+	}
+
+	pkgs, err := c.importableWorkspacePackages(ctx)
+	if err != nil {
+		return err
+	}
+	ids := make([]metadata.PackageID, len(pkgs))
+	for i, mp := range pkgs {
+		ids[i] = mp.ID
+	}
+	indexes, err := c.snapshot.MethodSets(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		if results := index.Search(key, nil); len(results) > 0 {
+			if err := c.addImplementingTypes(ctx, pkgs[i], results); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importableWorkspacePackages returns the workspace packages other
+// than the current one that the current file could import but does
+// not.
+func (c *completer) importableWorkspacePackages(ctx context.Context) ([]*metadata.Package, error) {
+	wsPkgs, err := c.snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := c.pkg.Metadata()
+	rdeps, err := c.snapshot.ReverseDependencies(ctx, current.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	goList := c.snapshot.View().Type() != cache.GoPackagesDriverView
+	var pkgs []*metadata.Package
+	for _, mp := range wsPkgs {
+		if mp.Name == "main" || mp.ForTest != "" ||
+			mp.PkgPath == current.PkgPath ||
+			rdeps[mp.ID] != nil || // would create a cycle
+			alreadyImports(c.pgf.File, golang.ImportPath(mp.PkgPath)) ||
+			!metadata.IsValidImport(current.PkgPath, mp.PkgPath, goList) ||
+			slices.ContainsFunc(pkgs, func(p *metadata.Package) bool { return p.PkgPath == mp.PkgPath }) {
+			continue
+		}
+		pkgs = append(pkgs, mp)
+	}
+	return pkgs, nil
+}
+
+// addImplementingTypes adds completion items for the types of package
+// mp found by a method-set search.
+func (c *completer) addImplementingTypes(ctx context.Context, mp *metadata.Package, results []methodsets.Result) error {
+	// Find the names of the types.
+	var names []string
+	for _, res := range results {
+		fh, err := c.snapshot.ReadFile(ctx, protocol.URIFromPath(res.Location.Filename))
+		if err != nil {
+			return err
+		}
+		content, err := fh.Content()
+		if err != nil {
+			return err
+		}
+		if res.Location.End <= len(content) {
+			if name := string(content[res.Location.Start:res.Location.End]); token.IsExported(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Find the declarations of the types, their methods, and the
+	// functions that may construct them.
+	var (
+		specs  = make(map[string]*ast.TypeSpec)
+		funcs  []*ast.FuncDecl
+		ptrRcv = make(map[string]bool) // types with pointer receivers
+	)
+	for _, uri := range mp.CompiledGoFiles {
+		fh, err := c.snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return err
+		}
+		content, err := fh.Content()
+		if err != nil {
+			return err
+		}
+		file, _ := parser.ParseFile(token.NewFileSet(), "", goplsastutil.PurgeFuncBodies(content), parser.SkipObjectResolution)
+		if file == nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && slices.Contains(names, spec.Name.Name) {
+						specs[spec.Name.Name] = spec
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					funcs = append(funcs, decl)
+				} else if len(decl.Recv.List) > 0 {
+					if star, ok := decl.Recv.List[0].Type.(*ast.StarExpr); ok {
+						if name := recvTypeName(star.X); name != "" {
+							ptrRcv[name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	imp := &importInfo{importPath: string(mp.PkgPath)}
+	if imports.ImportPathToAssumedName(string(mp.PkgPath)) != string(mp.Name) {
+		imp.name = string(mp.Name)
+	}
+	edits, err := c.importEdits(imp)
+	if err != nil {
+		return err
+	}
+	proximity := importPathProximity(string(c.pkg.Metadata().PkgPath), string(mp.PkgPath))
+	score := literalCandidateScore * (0.25 + 0.5*proximity)
+
+	for _, name := range names {
+		spec := specs[name]
+		if spec == nil || spec.TypeParams != nil {
+			continue // not found, or generic
+		}
+		typeName := string(mp.Name) + "." + name
+
+		// A composite literal, for struct types.
+		if _, ok := spec.Type.(*ast.StructType); ok {
+			if matchScore := c.matcher.Score(typeName); matchScore > 0 {
+				label := typeName + "{}"
+				// *T implements the interface; so does T if it has
+				// no pointer methods, declared or promoted.
+				if ptrRcv[name] || hasEmbeddedField(spec.Type.(*ast.StructType)) {
+					label = "&" + label
+				}
+				var snip snippet.Builder
+				snip.WriteText(strings.TrimSuffix(label, "}"))
+				snip.WriteFinalTabstop()
+				snip.WriteText("}")
+				c.items = append(c.items, CompletionItem{
+					Label:               label,
+					InsertText:          label,
+					Detail:              fmt.Sprintf("struct (from %q)", mp.PkgPath),
+					Kind:                protocol.VariableCompletion,
+					Score:               float64(matchScore) * score,
+					AdditionalTextEdits: edits,
+					snippet:             &snip,
+				})
+			}
+		}
+
+		// A call of each constructor function, such as NewT.
+		for _, fn := range funcs {
+			if !fn.Name.IsExported() || fn.Type.TypeParams != nil || !constructs(fn, name) {
+				continue
+			}
+			label := string(mp.Name) + "." + fn.Name.Name
+			matchScore := c.matcher.Score(label)
+			if matchScore <= 0 {
+				continue
+			}
+			var snip snippet.Builder
+			c.functionCallSnippet(label, nil, syntaxParamList(fn.Type.Params), &snip)
+			c.items = append(c.items, CompletionItem{
+				Label:               label,
+				InsertText:          label,
+				Detail:              fmt.Sprintf("func (from %q)", mp.PkgPath),
+				Kind:                protocol.FunctionCompletion,
+				Score:               float64(matchScore) * score,
+				AdditionalTextEdits: edits,
+				snippet:             &snip,
+			})
+		}
+	}
+	return nil
+}
+
+// recvTypeName returns the name of the base type of a method receiver,
+// such as T in "func (T[K]) f()".
+func recvTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.IndexExpr:
+		return recvTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(expr.X)
+	}
+	return ""
+}
+
+// hasEmbeddedField reports whether a struct type has an embedded
+// field, whose methods the struct may promote.
+func hasEmbeddedField(struc *ast.StructType) bool {
+	return slices.ContainsFunc(struc.Fields.List, func(field *ast.Field) bool {
+		return field.Names == nil
+	})
+}
+
+// constructs reports whether the single result of function fn has
+// type T or *T, for the named type T.
+func constructs(fn *ast.FuncDecl, name string) bool {
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	typ := results.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	id, ok := typ.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+// importPathProximity returns a measure in [0, 1] of the closeness of
+// two package paths: the fraction of segments that they share as a
+// common prefix.
+func importPathProximity(from, to string) float64 {
+	x, y := strings.Split(from, "/"), strings.Split(to, "/")
+	n := 0
+	for n < len(x) && n < len(y) && x[n] == y[n] {
+		n++
+	}
+	return float64(n) / float64(max(len(x), len(y)))
+}
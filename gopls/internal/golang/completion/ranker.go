@@ -0,0 +1,159 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+// This file defines the ranking of completion candidates, and the
+// local log of accepted completions from which users may train
+// rankers of their own.
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Features describes a completion candidate, and the context in which
+// it was offered, for the purpose of ranking.
+type Features struct {
+	// Candidate features.
+	Score      float64 `json:"score"`      // relevance according to the built-in heuristics
+	Depth      int     `json:"depth"`      // number of selections in a deep completion
+	Kind       string  `json:"kind"`       // kind of item, e.g. "func"
+	Deprecated bool    `json:"deprecated"` // candidate is deprecated
+	Edits      bool    `json:"edits"`      // accepting the candidate makes other edits, e.g. adding an import
+	LabelLen   int     `json:"labelLen"`   // length of the candidate's label
+
+	// Context features, common to all candidates of one request.
+	PrefixLen    int  `json:"prefixLen"`    // length of the identifier before the cursor
+	ExpectedType bool `json:"expectedType"` // type of the expression at the cursor is known
+}
+
+// A Ranker computes the relevance of completion candidates, which
+// determines their order. A higher value is more relevant.
+type Ranker interface {
+	Rank(Features) float64
+}
+
+// DefaultRanker ranks candidates by the score of the built-in
+// heuristics.
+var DefaultRanker Ranker = heuristicRanker{}
+
+type heuristicRanker struct{}
+
+func (heuristicRanker) Rank(f Features) float64 { return f.Score }
+
+// A LinearRanker ranks candidates by a weighted sum of their features.
+// Its JSON encoding is the format of the weights file named by the
+// completionRankerWeights setting.
+//
+// Context features are the same for all candidates of a request, so
+// they have no weight in a linear model.
+type LinearRanker struct {
+	Bias       float64            `json:"bias"`
+	Score      float64            `json:"score"`
+	Depth      float64            `json:"depth"`
+	Deprecated float64            `json:"deprecated"`
+	Edits      float64            `json:"edits"`
+	LabelLen   float64            `json:"labelLen"`
+	Kinds      map[string]float64 `json:"kinds"` // weight of each kind; missing kinds weigh zero
+}
+
+func (r *LinearRanker) Rank(f Features) float64 {
+	x := r.Bias +
+		r.Score*f.Score +
+		r.Depth*float64(f.Depth) +
+		r.LabelLen*float64(f.LabelLen) +
+		r.Kinds[f.Kind]
+	if f.Deprecated {
+		x += r.Deprecated
+	}
+	if f.Edits {
+		x += r.Edits
+	}
+	return x
+}
+
+// rankers caches the rankers loaded from weights files, keyed by file name.
+var rankers struct {
+	mu sync.Mutex
+	m  map[string]*loadedRanker
+}
+
+type loadedRanker struct {
+	modTime time.Time
+	size    int64
+	ranker  *LinearRanker
+}
+
+// LoadRanker returns the LinearRanker defined by the named weights
+// file. The file is read again only if it has changed.
+func LoadRanker(filename string) (Ranker, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	rankers.mu.Lock()
+	defer rankers.mu.Unlock()
+	if r, ok := rankers.m[filename]; ok && r.modTime.Equal(info.ModTime()) && r.size == info.Size() {
+		return r.ranker, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ranker := new(LinearRanker)
+	if err := json.Unmarshal(data, ranker); err != nil {
+		return nil, fmt.Errorf("invalid completion ranker weights in %s: %v", filename, err)
+	}
+	if rankers.m == nil {
+		rankers.m = make(map[string]*loadedRanker)
+	}
+	rankers.m[filename] = &loadedRanker{info.ModTime(), info.Size(), ranker}
+	return ranker, nil
+}
+
+// A LogEntry records the candidates offered by a completion request
+// and the one that the user accepted. The completion log, if enabled
+// by the completionLog setting, holds one LogEntry per line, in JSON.
+type LogEntry struct {
+	Time       time.Time  `json:"time"`
+	Candidates []Features `json:"candidates"` // in order of rank
+	Accepted   int        `json:"accepted"`   // index of accepted candidate
+}
+
+// maxLoggedCandidates bounds the number of candidates in a LogEntry,
+// not counting the accepted candidate, which is always included.
+const maxLoggedCandidates = 50
+
+// AppendLog appends an entry for an accepted completion candidate to
+// the named log file. The log is purely local: gopls never sends it
+// anywhere.
+func AppendLog(filename string, candidates []Features, accepted int) error {
+	n := max(maxLoggedCandidates, accepted+1)
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	data, err := json.Marshal(LogEntry{
+		Time:       time.Now(),
+		Candidates: candidates,
+		Accepted:   accepted,
+	})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package completion
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLinearRanker(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "weights.json")
+	write := func(weights string) {
+		if err := os.WriteFile(filename, []byte(weights), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f := Features{Score: 2, Depth: 1, Kind: "func", Deprecated: true, LabelLen: 4}
+
+	write(`{"bias": 1, "score": 3, "depth": -1, "deprecated": -0.5, "labelLen": 0.25, "kinds": {"func": 10}}`)
+	ranker, err := LoadRanker(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ranker.Rank(f), 1+3*2-1-0.5+0.25*4+10.0; got != want {
+		t.Errorf("Rank(%+v) = %g, want %g", f, got, want)
+	}
+
+	// A change to the file is observed.
+	write(`{"score": 1}`)
+	ranker, err = LoadRanker(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ranker.Rank(f), DefaultRanker.Rank(f); got != want {
+		t.Errorf("Rank(%+v) = %g, want %g", f, got, want)
+	}
+
+	write(`{"score": "high"}`)
+	if _, err := LoadRanker(filename); err == nil {
+		t.Errorf("LoadRanker succeeded with invalid weights")
+	}
+}
+
+func TestAppendLog(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "completion.log")
+	candidates := make([]Features, 2*maxLoggedCandidates)
+	for i := range candidates {
+		candidates[i].LabelLen = i
+	}
+	for _, accepted := range []int{1, maxLoggedCandidates + 10} {
+		if err := AppendLog(filename, candidates, accepted); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []LogEntry
+	for scan := bufio.NewScanner(file); scan.Scan(); {
+		var entry LogEntry
+		if err := json.Unmarshal(scan.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+	for i, want := range []struct{ accepted, n int }{
+		{1, maxLoggedCandidates},
+		{maxLoggedCandidates + 10, maxLoggedCandidates + 11},
+	} {
+		e := entries[i]
+		if e.Accepted != want.accepted || len(e.Candidates) != want.n {
+			t.Errorf("entry %d: accepted %d of %d candidates, want %d of %d",
+				i, e.Accepted, len(e.Candidates), want.accepted, want.n)
+		}
+		if got := e.Candidates[e.Accepted].LabelLen; got != e.Accepted {
+			t.Errorf("entry %d: accepted candidate has LabelLen %d, want %d", i, got, e.Accepted)
+		}
+	}
+}
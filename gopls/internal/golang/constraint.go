@@ -0,0 +1,381 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/analysisinternal"
+	"golang.org/x/tools/internal/typesinternal"
+	"golang.org/x/tools/internal/versions"
+)
+
+// This file defines the "Infer constraint of T from usage" code action.
+
+// A kindSet is a set of the kinds of basic types that a type
+// parameter's type set may contain.
+type kindSet uint
+
+const (
+	integerKinds kindSet = 1 << iota
+	floatKinds
+	complexKinds
+	stringKinds
+
+	allKinds     = integerKinds | floatKinds | complexKinds | stringKinds
+	orderedKinds = integerKinds | floatKinds | stringKinds
+	numericKinds = integerKinds | floatKinds | complexKinds
+)
+
+// kindTerms holds the terms of the union that denotes each kind of
+// basic type, in order.
+var kindTerms = []struct {
+	kinds kindSet
+	terms []string
+}{
+	{integerKinds, []string{"~int", "~int8", "~int16", "~int32", "~int64", "~uint", "~uint8", "~uint16", "~uint32", "~uint64", "~uintptr"}},
+	{floatKinds, []string{"~float32", "~float64"}},
+	{complexKinds, []string{"~complex64", "~complex128"}},
+	{stringKinds, []string{"~string"}},
+}
+
+// A usage summarizes the operations applied to the values of a type
+// parameter within the body of a function.
+type usage struct {
+	kinds      kindSet // possible kinds of the core type; allKinds if unconstrained
+	comparable bool    // values are compared with == or !=
+	methods    []string
+	sigs       map[string]string // method name to signature, such as "(int) string"
+}
+
+// inferConstraint produces an "Infer constraint of T from usage" code
+// action when the selection is a type parameter of a function whose
+// constraint is any. The action replaces the constraint by the
+// minimal one that permits the operations applied to the values of the
+// type parameter in the function body: a union of basic types (or
+// cmp.Ordered), comparable, and methods.
+func inferConstraint(req *codeActionsRequest) error {
+	file, info := req.pgf.File, req.pkg.TypesInfo()
+
+	// Find the type parameter and its declaration.
+	path, _ := astutil.PathEnclosingInterval(file, req.start, req.end)
+	if len(path) < 5 {
+		return nil
+	}
+	id, ok := path[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	field, ok := path[1].(*ast.Field)
+	if !ok || !slices.Contains(field.Names, id) {
+		return nil
+	}
+	decl, ok := path[4].(*ast.FuncDecl)
+	if !ok || decl.Body == nil || decl.Type.TypeParams == nil || path[2] != decl.Type.TypeParams {
+		return nil
+	}
+	tname, ok := info.Defs[id].(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	tparam, ok := tname.Type().(*types.TypeParam)
+	if !ok {
+		return nil
+	}
+	if iface, ok := tparam.Constraint().Underlying().(*types.Interface); !ok || !iface.Empty() {
+		return nil // constraint is not any
+	}
+
+	u, ok := usageOf(info, file, decl, tparam)
+	if !ok || (u.kinds == allKinds && !u.comparable && len(u.methods) == 0) {
+		return nil // unknown or unconstrained usage
+	}
+
+	// Format the constraint.
+	var (
+		elems      []string
+		importEdit []protocol.TextEdit
+	)
+	if u.kinds != allKinds {
+		if u.kinds == orderedKinds && !versions.Before(versions.FileVersion(info, file), versions.Go1_21) {
+			name, edits := analysisinternal.AddImport(info, file, decl.Pos(), "cmp", "cmp")
+			for _, edit := range edits {
+				rng, err := req.pgf.PosRange(edit.Pos, edit.End)
+				if err != nil {
+					return err
+				}
+				importEdit = append(importEdit, protocol.TextEdit{Range: rng, NewText: string(edit.NewText)})
+			}
+			elems = append(elems, name+".Ordered")
+		} else {
+			var terms []string
+			for _, kt := range kindTerms {
+				if u.kinds&kt.kinds != 0 {
+					terms = append(terms, kt.terms...)
+				}
+			}
+			elems = append(elems, strings.Join(terms, " | "))
+		}
+	} else if u.comparable {
+		// Every type in a union of basic types is comparable.
+		elems = append(elems, "comparable")
+	}
+	for _, name := range u.methods {
+		elems = append(elems, name+u.sigs[name])
+	}
+	constraint := elems[0]
+	if len(elems) > 1 || len(u.methods) > 0 {
+		constraint = "interface{ " + strings.Join(elems, "; ") + " }"
+	}
+
+	// Replace the type parameter's field, splitting it if it
+	// declares other type parameters.
+	var parts []string
+	for _, name := range field.Names {
+		if name == id {
+			parts = append(parts, name.Name+" "+constraint)
+		} else {
+			start, end, err := req.pgf.NodeOffsets(field.Type)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, name.Name+" "+string(req.pgf.Src[start:end]))
+		}
+	}
+	rng, err := req.pgf.NodeRange(field)
+	if err != nil {
+		return err
+	}
+	edits := append(importEdit, protocol.TextEdit{Range: rng, NewText: strings.Join(parts, ", ")})
+	req.addEditAction(fmt.Sprintf("Infer constraint of %s from usage", id.Name), nil,
+		protocol.DocumentChangeEdit(req.fh, edits))
+	return nil
+}
+
+// usageOf returns the usage of the values of type parameter tparam
+// within the body of decl. It reports false if a method is called
+// whose signature cannot be determined from the call.
+func usageOf(info *types.Info, file *ast.File, decl *ast.FuncDecl, tparam *types.TypeParam) (usage, bool) {
+	u := usage{kinds: allKinds, sigs: make(map[string]string)}
+	isT := func(e ast.Expr) bool {
+		t := info.TypeOf(e)
+		return t != nil && types.Identical(t, tparam)
+	}
+	q := typesinternal.FileQualifier(file, tparam.Obj().Pkg())
+	declSig, _ := info.Defs[decl.Name].Type().(*types.Signature)
+
+	// binary records the effect of the binary operator op
+	// applied to a value of type T.
+	binary := func(op token.Token) {
+		switch op {
+		case token.EQL, token.NEQ:
+			u.comparable = true
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			u.kinds &= orderedKinds
+		case token.ADD, token.ADD_ASSIGN:
+			u.kinds &= numericKinds | stringKinds
+		case token.SUB, token.MUL, token.QUO, token.SUB_ASSIGN, token.MUL_ASSIGN, token.QUO_ASSIGN:
+			u.kinds &= numericKinds
+		case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT, token.SHL, token.SHR,
+			token.REM_ASSIGN, token.AND_ASSIGN, token.OR_ASSIGN, token.XOR_ASSIGN, token.AND_NOT_ASSIGN, token.SHL_ASSIGN, token.SHR_ASSIGN:
+			u.kinds &= integerKinds
+		}
+	}
+
+	ok := true
+	var stack []ast.Node
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if isT(n.X) || isT(n.Y) {
+				binary(n.Op)
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE && len(n.Lhs) == 1 && isT(n.Lhs[0]) {
+				binary(n.Tok)
+			}
+		case *ast.IncDecStmt:
+			if isT(n.X) {
+				u.kinds &= numericKinds
+			}
+		case *ast.UnaryExpr:
+			if isT(n.X) {
+				switch n.Op {
+				case token.SUB:
+					u.kinds &= numericKinds
+				case token.XOR:
+					u.kinds &= integerKinds
+				}
+			}
+		case *ast.CallExpr:
+			// Conversion of a T value to a numeric type?
+			if tv, ok := info.Types[n.Fun]; ok && tv.IsType() && len(n.Args) == 1 && isT(n.Args[0]) {
+				if basic, ok := tv.Type.Underlying().(*types.Basic); ok {
+					switch {
+					case basic.Info()&(types.IsInteger|types.IsFloat) != 0:
+						u.kinds &= integerKinds | floatKinds
+					case basic.Info()&types.IsComplex != 0:
+						u.kinds &= complexKinds
+					}
+				}
+				return true
+			}
+			// Method call on a T value?
+			sel, isSel := n.Fun.(*ast.SelectorExpr)
+			if !isSel || !isT(sel.X) {
+				return true
+			}
+			if _, seen := u.sigs[sel.Sel.Name]; seen {
+				return true
+			}
+			sig, sigOK := methodSig(info, declSig, n, stack, q)
+			if !sigOK {
+				ok = false
+				return false
+			}
+			u.methods = append(u.methods, sel.Sel.Name)
+			u.sigs[sel.Sel.Name] = sig
+		}
+		return ok
+	})
+	return u, ok
+}
+
+// methodSig returns the signature, such as "(int) string", of the
+// method called by call, inferred from the types of its arguments and
+// from the context of the call, given by the stack of enclosing nodes
+// within the body of the function declaration whose signature is declSig.
+func methodSig(info *types.Info, declSig *types.Signature, call *ast.CallExpr, stack []ast.Node, q types.Qualifier) (string, bool) {
+	if call.Ellipsis.IsValid() {
+		return "", false
+	}
+	typeString := func(t types.Type) (string, bool) {
+		if t == nil {
+			return "", false
+		}
+		return types.TypeString(types.Default(t), q), true
+	}
+
+	var params []string
+	for _, arg := range call.Args {
+		s, ok := typeString(info.TypeOf(arg))
+		if !ok {
+			return "", false
+		}
+		params = append(params, s)
+	}
+
+	// Infer the results from the context.
+	var results []types.Type
+	if len(stack) < 2 {
+		return "", false
+	}
+	switch parent := stack[len(stack)-2].(type) {
+	case *ast.ExprStmt:
+		// no results
+	case *ast.AssignStmt:
+		if parent.Tok == token.DEFINE || len(parent.Rhs) != 1 {
+			return "", false
+		}
+		for _, lhs := range parent.Lhs {
+			results = append(results, info.TypeOf(lhs))
+		}
+	case *ast.ValueSpec:
+		if parent.Type == nil || len(parent.Values) != 1 {
+			return "", false
+		}
+		for range parent.Names {
+			results = append(results, info.TypeOf(parent.Type))
+		}
+	case *ast.ReturnStmt:
+		sig := declSig
+		for i := len(stack) - 1; i >= 0; i-- {
+			if lit, ok := stack[i].(*ast.FuncLit); ok {
+				sig, _ = info.TypeOf(lit).(*types.Signature)
+				break
+			}
+		}
+		if sig == nil {
+			return "", false
+		}
+		if len(parent.Results) == 1 {
+			for i := range sig.Results().Len() {
+				results = append(results, sig.Results().At(i).Type())
+			}
+		} else {
+			i := slices.Index(parent.Results, ast.Expr(call))
+			if i < 0 || i >= sig.Results().Len() {
+				return "", false
+			}
+			results = append(results, sig.Results().At(i).Type())
+		}
+	case *ast.CallExpr:
+		sig, ok := info.TypeOf(parent.Fun).(*types.Signature)
+		i := slices.Index(parent.Args, ast.Expr(call))
+		if !ok || i < 0 || i >= sig.Params().Len() || (sig.Variadic() && i >= sig.Params().Len()-1) {
+			return "", false
+		}
+		results = append(results, sig.Params().At(i).Type())
+	case *ast.BinaryExpr:
+		other := parent.X
+		if other == ast.Expr(call) {
+			other = parent.Y
+		}
+		switch parent.Op {
+		case token.LAND, token.LOR:
+			results = append(results, types.Typ[types.Bool])
+		default:
+			t := info.TypeOf(other)
+			if t == nil {
+				return "", false
+			}
+			if basic, ok := t.(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+				return "", false // e.g. x.Len() > 0: int, or float64?
+			}
+			results = append(results, t)
+		}
+	case *ast.UnaryExpr:
+		if parent.Op != token.NOT {
+			return "", false
+		}
+		results = append(results, types.Typ[types.Bool])
+	case *ast.IfStmt:
+		results = append(results, types.Typ[types.Bool])
+	case *ast.ForStmt:
+		results = append(results, types.Typ[types.Bool])
+	default:
+		return "", false
+	}
+
+	var resultStrs []string
+	for _, t := range results {
+		s, ok := typeString(t)
+		if !ok {
+			return "", false
+		}
+		resultStrs = append(resultStrs, s)
+	}
+	sig := "(" + strings.Join(params, ", ") + ")"
+	switch len(resultStrs) {
+	case 0:
+	case 1:
+		sig += " " + resultStrs[0]
+	default:
+		sig += " (" + strings.Join(resultStrs, ", ") + ")"
+	}
+	return sig, true
+}
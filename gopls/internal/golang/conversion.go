@@ -0,0 +1,227 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the behavior of the "Generate conversion" command.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/analysis/fillstruct"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/typeparams"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// conversionComment is the first line of a generated conversion
+// function body; its presence enables the "Regenerate" action.
+const conversionComment = `// Generated by gopls; update with the "Regenerate conversion" code action.`
+
+// A conversionFunc is a function declaration whose signature,
+// func(x X) Y or func (x X) M() Y, in which X and Y are (pointers to)
+// struct types, specifies a conversion from X to Y.
+type conversionFunc struct {
+	decl       *ast.FuncDecl
+	src        *types.Var // the parameter or receiver x
+	dst        types.Type // the result type Y
+	regenerate bool       // the body was previously generated
+}
+
+// title returns the title of the code action for the function.
+func (conv *conversionFunc) title(qual types.Qualifier) string {
+	verb := "Generate"
+	if conv.regenerate {
+		verb = "Regenerate"
+	}
+	return fmt.Sprintf("%s conversion from %s to %s", verb,
+		types.TypeString(conv.src.Type(), qual),
+		types.TypeString(conv.dst, qual))
+}
+
+// findConversionFunc returns the conversion function whose
+// declaration encloses the [start, end) interval, or nil if there is
+// none, or if its body is neither empty nor previously generated.
+func findConversionFunc(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) *conversionFunc {
+	path, _ := astutil.PathEnclosingInterval(pgf.File, start, end)
+	if len(path) < 2 {
+		return nil
+	}
+	decl, ok := path[len(path)-2].(*ast.FuncDecl)
+	if !ok || decl.Body == nil || decl.Type.TypeParams != nil {
+		return nil
+	}
+	fn, ok := pkg.TypesInfo().Defs[decl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.TypeParams().Len() > 0 || sig.RecvTypeParams().Len() > 0 || sig.Results().Len() != 1 {
+		return nil
+	}
+	var src *types.Var
+	switch {
+	case sig.Params().Len() == 1:
+		src = sig.Params().At(0)
+	case sig.Params().Len() == 0 && sig.Recv() != nil:
+		src = sig.Recv()
+	default:
+		return nil
+	}
+	dst := sig.Results().At(0).Type()
+	if src.Name() == "" || src.Name() == "_" || !isStructOrPointer(src.Type()) || !isStructOrPointer(dst) {
+		return nil
+	}
+
+	// Offer to generate an empty body, or regenerate a generated one.
+	conv := &conversionFunc{decl: decl, src: src, dst: dst}
+	var comments []*ast.Comment
+	for _, cg := range pgf.File.Comments {
+		if decl.Body.Lbrace < cg.Pos() && cg.End() < decl.Body.Rbrace {
+			comments = append(comments, cg.List...)
+		}
+	}
+	switch {
+	case len(decl.Body.List) == 0 && len(comments) == 0:
+	case len(comments) > 0 && comments[0].Text == conversionComment:
+		conv.regenerate = true
+	default:
+		return nil
+	}
+	return conv
+}
+
+// isStructOrPointer reports whether t is a struct type or a pointer
+// to a struct type.
+func isStructOrPointer(t types.Type) bool {
+	_, ok := typeparams.Deref(t).Underlying().(*types.Struct)
+	return ok
+}
+
+// GenerateConversion generates, or regenerates, the body of the
+// conversion function declared at the specified location.
+func GenerateConversion(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) ([]protocol.DocumentChange, error) {
+	fh, err := snapshot.ReadFile(ctx, loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := pgf.RangePos(loc.Range)
+	if err != nil {
+		return nil, err
+	}
+	conv := findConversionFunc(pkg, pgf, start, end)
+	if conv == nil {
+		return nil, fmt.Errorf("no conversion function at selection")
+	}
+	body, err := conversionBody(pkg.Types(), pgf.File, conv)
+	if err != nil {
+		return nil, err
+	}
+	rng, err := pgf.PosRange(conv.decl.Body.Lbrace, conv.decl.Body.End())
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, []protocol.TextEdit{{
+		Range:   rng,
+		NewText: body,
+	}})}, nil
+}
+
+// conversionBody returns the formatted body, including braces, of
+// the conversion function. Each accessible field of the result type
+// is initialized from the field of the source with the same name (or,
+// failing that, the same name ignoring case), converting it if
+// necessary; a TODO comment marks each field that cannot be.
+func conversionBody(pkg *types.Package, file *ast.File, conv *conversionFunc) (string, error) {
+	qual := typesinternal.FileQualifier(file, pkg)
+	srcStruct := typeparams.Deref(conv.src.Type()).Underlying().(*types.Struct)
+	dstStruct := typeparams.Deref(conv.dst).Underlying().(*types.Struct)
+	srcName := types.TypeString(typeparams.Deref(conv.src.Type()), qual)
+
+	var srcFields []*types.Var
+	for i := 0; i < srcStruct.NumFields(); i++ {
+		if f := srcStruct.Field(i); fieldAccessible(f, pkg) {
+			srcFields = append(srcFields, f)
+		}
+	}
+	lookup := func(name string) *types.Var {
+		for _, f := range srcFields {
+			if f.Name() == name {
+				return f
+			}
+		}
+		for _, f := range srcFields {
+			if strings.EqualFold(f.Name(), name) {
+				return f
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\t%s\n", conversionComment)
+	_, srcPtr := conv.src.Type().Underlying().(*types.Pointer)
+	_, dstPtr := conv.dst.Underlying().(*types.Pointer)
+	if srcPtr && dstPtr {
+		fmt.Fprintf(&buf, "\tif %s == nil {\n\t\treturn nil\n\t}\n", conv.src.Name())
+	}
+	buf.WriteString("\treturn ")
+	if dstPtr {
+		buf.WriteString("&")
+	}
+	if err := format.Node(&buf, token.NewFileSet(), typesinternal.TypeExpr(typeparams.Deref(conv.dst), qual)); err != nil {
+		return "", err
+	}
+	buf.WriteString("{\n")
+	for i := 0; i < dstStruct.NumFields(); i++ {
+		field := dstStruct.Field(i)
+		if !fieldAccessible(field, pkg) {
+			continue
+		}
+		f := lookup(field.Name())
+		if f == nil {
+			fmt.Fprintf(&buf, "\t\t// TODO: %s: no matching field in %s\n", field.Name(), srcName)
+			continue
+		}
+		x := &ast.SelectorExpr{X: ast.NewIdent(conv.src.Name()), Sel: ast.NewIdent(f.Name())}
+		value := fillstruct.ValueFrom(x, f.Type(), field.Type(), pkg, qual)
+		if value == nil {
+			fmt.Fprintf(&buf, "\t\t// TODO: %s: cannot convert %s.%s (%s) to %s\n",
+				field.Name(), conv.src.Name(), f.Name(),
+				types.TypeString(f.Type(), qual), types.TypeString(field.Type(), qual))
+			continue
+		}
+		fmt.Fprintf(&buf, "\t\t%s: ", field.Name())
+		if err := format.Node(&buf, token.NewFileSet(), value); err != nil {
+			return "", err
+		}
+		buf.WriteString(",\n")
+	}
+	buf.WriteString("\t}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return "{\n" + string(formatted) + "}", nil
+}
+
+// fieldAccessible reports whether the field is accessible from pkg.
+func fieldAccessible(field *types.Var, pkg *types.Package) bool {
+	return field.Pkg() == nil || field.Pkg() == pkg || field.Exported()
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the custom code actions configured by the
+// customCodeActionsFile setting.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// A CustomCodeAction is a code action defined by the file named by
+// the customCodeActionsFile setting.
+type CustomCodeAction struct {
+	// Title is the title of the action, which identifies it.
+	Title string `json:"title"`
+
+	// Files is a pattern, in the syntax of [path.Match], that
+	// selects the files to which the action applies. A pattern
+	// without a slash is matched against the base name of the file;
+	// otherwise it is matched against its slash-separated path
+	// relative to the workspace folder. An empty pattern matches all
+	// Go files.
+	Files string `json:"files"`
+
+	// Command is the command to run, and its arguments, in which
+	// ${file}, ${dir}, ${startLine}, and ${endLine} are replaced by
+	// the absolute name of the file, its directory, and the 1-based
+	// start and end lines of the selection.
+	Command []string `json:"command"`
+
+	// ApplyEdits indicates that the standard output of the command is
+	// the new content of the file, which gopls should apply as edits
+	// to it. Otherwise the output is displayed as a message.
+	ApplyEdits bool `json:"applyEdits"`
+}
+
+// CustomCodeActions returns the custom code actions defined by the
+// customCodeActionsFile setting of the snapshot's workspace folder, or
+// nil if the setting is empty.
+func CustomCodeActions(ctx context.Context, snapshot *cache.Snapshot) ([]CustomCodeAction, error) {
+	name := snapshot.Options().CustomCodeActionsFile
+	if name == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(snapshot.Folder().Path(), name)
+	}
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(name))
+	if err != nil {
+		return nil, err
+	}
+	content, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	var actions []CustomCodeAction
+	if err := json.Unmarshal(content, &actions); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", name, err)
+	}
+	seen := make(map[string]bool)
+	for _, a := range actions {
+		switch {
+		case a.Title == "":
+			return nil, fmt.Errorf("%s: custom code action has no title", name)
+		case seen[a.Title]:
+			return nil, fmt.Errorf("%s: duplicate custom code action %q", name, a.Title)
+		case len(a.Command) == 0:
+			return nil, fmt.Errorf("%s: custom code action %q has no command", name, a.Title)
+		}
+		if _, err := path.Match(a.Files, ""); err != nil {
+			return nil, fmt.Errorf("%s: custom code action %q: invalid files pattern %q", name, a.Title, a.Files)
+		}
+		seen[a.Title] = true
+	}
+	return actions, nil
+}
+
+// AppliesTo reports whether the action applies to the specified
+// file of the workspace folder.
+func (a *CustomCodeAction) AppliesTo(folder, uri protocol.DocumentURI) bool {
+	if a.Files == "" {
+		return true
+	}
+	name := path.Base(filepath.ToSlash(uri.Path()))
+	if strings.Contains(a.Files, "/") {
+		rel, err := filepath.Rel(folder.Path(), uri.Path())
+		if err != nil {
+			return false
+		}
+		name = filepath.ToSlash(rel)
+	}
+	ok, _ := path.Match(a.Files, name)
+	return ok
+}
+
+// CommandLine returns the command and arguments of the action for
+// the specified selection.
+func (a *CustomCodeAction) CommandLine(loc protocol.Location) []string {
+	r := strings.NewReplacer(
+		"${file}", loc.URI.Path(),
+		"${dir}", loc.URI.DirPath(),
+		"${startLine}", strconv.Itoa(int(loc.Range.Start.Line)+1),
+		"${endLine}", strconv.Itoa(int(loc.Range.End.Line)+1),
+	)
+	args := make([]string, len(a.Command))
+	for i, arg := range a.Command {
+		args[i] = r.Replace(arg)
+	}
+	return args
+}
@@ -12,7 +12,6 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
-	"regexp"
 	"strings"
 
 	"golang.org/x/tools/go/ast/astutil"
@@ -21,6 +20,7 @@ import (
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/asm"
 	goplsastutil "golang.org/x/tools/gopls/internal/util/astutil"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/internal/event"
@@ -179,6 +179,18 @@ func Definition(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 		return builtinDefinition(ctx, snapshot, obj)
 	}
 
+	// C symbols referenced by cgo files are declared in Go by
+	// cgo-generated code; prefer their C declaration, if found.
+	if name, ok := cache.CgoName(obj.Name()); ok && obj.Pkg() == pkg.Types() {
+		decl, err := findCgoDecl(ctx, snapshot, pkg, name)
+		if err != nil {
+			return nil, err
+		}
+		if decl != nil {
+			return []protocol.Location{decl.loc}, nil
+		}
+	}
+
 	// Non-go (e.g. assembly) symbols
 	//
 	// When already at the definition of a Go function without
@@ -459,9 +471,6 @@ func nonGoDefinition(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 	// Examples:
 	//   TEXT runtime·foo(SB)
 	//   TEXT ·foo<ABIInternal>(SB)
-	// TODO(adonovan): why does ^TEXT cause it not to match?
-	pattern := regexp.MustCompile("TEXT\\b.*·(" + regexp.QuoteMeta(symbol) + ")[\\(<]")
-
 	for _, uri := range pkg.Metadata().OtherFiles {
 		if strings.HasSuffix(uri.Path(), ".s") {
 			fh, err := snapshot.ReadFile(ctx, uri)
@@ -472,13 +481,16 @@ func nonGoDefinition(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 			if err != nil {
 				continue // can't read file
 			}
-			if match := pattern.FindSubmatchIndex(content); match != nil {
-				mapper := protocol.NewMapper(uri, content)
-				loc, err := mapper.OffsetLocation(match[2], match[3])
-				if err != nil {
-					return nil, err
+			for _, id := range asm.Parse(content).Idents {
+				if name, ok := id.Local(string(pkg.Metadata().PkgPath)); ok && name == symbol && id.Kind == asm.Text {
+					// Select just the name, without its package qualifier.
+					mapper := protocol.NewMapper(uri, content)
+					loc, err := mapper.OffsetLocation(id.End-len(symbol), id.End)
+					if err != nil {
+						return nil, err
+					}
+					return []protocol.Location{loc}, nil
 				}
-				return []protocol.Location{loc}, nil
 			}
 		}
 	}
@@ -25,14 +25,27 @@ func DiagnoseFile(ctx context.Context, snapshot *cache.Snapshot, uri protocol.Do
 	// diagnostics.
 
 	// Get package (list/parse/type check) diagnostics.
-	pkgDiags, err := snapshot.PackageDiagnostics(ctx, mp.ID)
+	pkgDiags, err := snapshot.PackageDiagnostics(ctx, nil, mp.ID)
 	if err != nil {
 		return nil, err
 	}
 	diags := pkgDiags[uri]
 
+	// Report only the syntax errors of a large file.
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if IsLargeFile(snapshot, fh) {
+		notice, err := largeFileDiagnostic(ctx, snapshot, fh)
+		if err != nil {
+			return nil, err
+		}
+		return append(SyntaxDiagnostics(diags), notice), nil
+	}
+
 	// Get analysis diagnostics.
-	pkgAnalysisDiags, err := snapshot.Analyze(ctx, map[PackageID]*metadata.Package{mp.ID: mp}, nil)
+	pkgAnalysisDiags, err := snapshot.Analyze(ctx, map[PackageID]*metadata.Package{mp.ID: mp}, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -46,21 +59,25 @@ func DiagnoseFile(ctx context.Context, snapshot *cache.Snapshot, uri protocol.Do
 // Analyze reports go/analysis-framework diagnostics in the specified package.
 //
 // If the provided tracker is non-nil, it may be used to provide notifications
-// of the ongoing analysis pass.
+// of the ongoing analysis pass; if the provided index progress is
+// non-nil, the pass records its progress there.
+//
+// If the context is cancelled, Analyze returns the diagnostics of the
+// analyzers that completed, along with the context's error.
 //
 // TODO(rfindley): merge this with snapshot.Analyze.
-func Analyze(ctx context.Context, snapshot *cache.Snapshot, pkgIDs map[PackageID]*metadata.Package, tracker *progress.Tracker) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+func Analyze(ctx context.Context, snapshot *cache.Snapshot, pkgIDs map[PackageID]*metadata.Package, tracker *progress.Tracker, index *cache.IndexProgress) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
 	// Exit early if the context has been canceled. This also protects us
 	// from a race on Options, see golang/go#36699.
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	analysisDiagnostics, err := snapshot.Analyze(ctx, pkgIDs, tracker)
-	if err != nil {
+	analysisDiagnostics, err := snapshot.Analyze(ctx, pkgIDs, tracker, index)
+	if err != nil && ctx.Err() == nil {
 		return nil, err
 	}
-	return moremaps.Group(analysisDiagnostics, byURI), nil
+	return moremaps.Group(analysisDiagnostics, byURI), err // err is cancellation, if any
 }
 
 // byURI is used for grouping diagnostics.
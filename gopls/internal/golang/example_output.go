@@ -0,0 +1,145 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Update example output" code action.
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// outputPrefix matches the start of an example's output comment.
+// (Copied from go/doc.)
+var outputPrefix = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+
+// exampleOutputComment returns the output comment of the example
+// function fn, which is the last comment in its body, if it begins
+// with "Output:" or "Unordered output:", or nil if there is none.
+func exampleOutputComment(pgf *parsego.File, fn *ast.FuncDecl) *ast.CommentGroup {
+	if fn.Body == nil {
+		return nil
+	}
+	return exampleOutputCommentIn(fn.Body, pgf.File.Comments)
+}
+
+// exampleOutputCommentIn returns the output comment of an example
+// function body, given the comments of its file, or nil if there is none.
+func exampleOutputCommentIn(body *ast.BlockStmt, comments []*ast.CommentGroup) *ast.CommentGroup {
+	var last *ast.CommentGroup
+	for _, cg := range comments {
+		if body.Lbrace < cg.Pos() && cg.End() < body.Rbrace {
+			last = cg
+		}
+	}
+	if last == nil || !outputPrefix.MatchString(last.Text()) {
+		return nil
+	}
+	return last
+}
+
+// enclosingExample returns the declaration of the Example function of
+// a test file that encloses the range, if it has an output comment.
+func enclosingExample(pgf *parsego.File, rng protocol.Range) *ast.FuncDecl {
+	if !strings.HasSuffix(pgf.URI.Path(), "_test.go") {
+		return nil
+	}
+	for _, decl := range pgf.File.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Example") {
+			continue
+		}
+		fnRng, err := pgf.NodeRange(fn)
+		if err != nil || !protocol.Intersect(fnRng, rng) {
+			continue
+		}
+		if exampleOutputComment(pgf, fn) != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// ExampleTestOutput returns the actual output of the named example,
+// as reported by the given output of a failed 'go test' run. It
+// returns an error, including the 'go test' output, if the example
+// did not run (for example because the package failed to build) or
+// failed for some other reason (for example because it panicked).
+func ExampleTestOutput(name string, testOutput string) (string, error) {
+	start := strings.Index(testOutput, fmt.Sprintf("--- FAIL: %s (", name))
+	if start < 0 {
+		return "", fmt.Errorf("%s did not run:\n%s", name, testOutput)
+	}
+	rest := testOutput[start:]
+	const gotHeader, wantHeader = "\ngot:\n", "\nwant:\n"
+	got := strings.Index(rest, gotHeader)
+	want := strings.LastIndex(rest, wantHeader)
+	if got < 0 || want < got+len(gotHeader) {
+		return "", fmt.Errorf("%s failed:\n%s", name, rest)
+	}
+	return rest[got+len(gotHeader) : want], nil
+}
+
+// UpdateExampleOutput returns the changes that replace the output
+// comment of the named example function in fh by the given output.
+func UpdateExampleOutput(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, name, output string) ([]protocol.DocumentChange, error) {
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+	if err != nil {
+		return nil, err
+	}
+	var comment *ast.CommentGroup
+	for _, decl := range pgf.File.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			comment = exampleOutputComment(pgf, fn)
+			break
+		}
+	}
+	if comment == nil {
+		return nil, fmt.Errorf("no output comment for %s", name)
+	}
+
+	// Preserve the header and the indentation of the comment.
+	header := "Output:"
+	if m := outputPrefix.FindStringSubmatch(comment.Text()); m[1] != "" {
+		header = "Unordered output:"
+	}
+	start, _, err := pgf.NodeOffsets(comment)
+	if err != nil {
+		return nil, err
+	}
+	lineStart := start
+	for lineStart > 0 && (pgf.Src[lineStart-1] == ' ' || pgf.Src[lineStart-1] == '\t') {
+		lineStart--
+	}
+	indent := string(pgf.Src[lineStart:start])
+
+	var buf strings.Builder
+	buf.WriteString("// " + header)
+	if output = strings.TrimSpace(output); output != "" {
+		for _, line := range strings.Split(output, "\n") {
+			buf.WriteString("\n" + indent + "//")
+			if line != "" {
+				buf.WriteString(" " + line)
+			}
+		}
+	}
+
+	rng, err := pgf.NodeRange(comment)
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, []protocol.TextEdit{{
+		Range:   rng,
+		NewText: buf.String(),
+	}})}, nil
+}
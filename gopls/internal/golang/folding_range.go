@@ -49,6 +49,8 @@ func FoldingRange(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 
 	// Get folding ranges for comments separately as they are not walked by ast.Inspect.
 	ranges = append(ranges, commentsFoldingRange(pgf)...)
+	ranges = append(ranges, regionsFoldingRange(pgf)...)
+	ranges = append(ranges, importsFoldingRange(pgf)...)
 
 	visit := func(n ast.Node) bool {
 		rng := foldingRangeFunc(pgf, n, lineFoldingOnly)
@@ -226,3 +228,92 @@ func commentsFoldingRange(pgf *parsego.File) (comments []*FoldingRangeInfo) {
 	}
 	return comments
 }
+
+// regionsFoldingRange returns the folding ranges for the user-defined
+// regions of the file, each delimited by a pair of comments such as
+// "//region name" and "//endregion" (also "// #region" and
+// "// #endregion"). Regions may be nested; unmatched markers are ignored.
+// The folding range starts at the end of the region comment, and ends at
+// the end of the endregion comment and has kind protocol.Region.
+func regionsFoldingRange(pgf *parsego.File) (regions []*FoldingRangeInfo) {
+	var stack []*ast.Comment // open "region" comments
+	for _, commentGrp := range pgf.File.Comments {
+		for _, c := range commentGrp.List {
+			switch regionMarker(c.Text) {
+			case "region":
+				stack = append(stack, c)
+			case "endregion":
+				if len(stack) == 0 {
+					continue // unmatched
+				}
+				start := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				rng, err := pgf.PosRange(start.End(), c.End())
+				if err != nil {
+					bug.Reportf("failed to create mapped range: %s", err) // can't happen
+					continue
+				}
+				regions = append(regions, &FoldingRangeInfo{
+					Range: rng,
+					Kind:  protocol.Region,
+				})
+			}
+		}
+	}
+	return regions
+}
+
+// regionMarker returns "region" or "endregion" if the comment text is
+// a region or endregion marker, or "" otherwise.
+func regionMarker(text string) string {
+	rest, ok := strings.CutPrefix(text, "//")
+	if !ok {
+		return ""
+	}
+	rest = strings.TrimPrefix(strings.TrimLeft(rest, " \t"), "#")
+	for _, marker := range []string{"region", "endregion"} {
+		if after, ok := strings.CutPrefix(rest, marker); ok && (after == "" || after[0] == ' ' || after[0] == '\t') {
+			return marker
+		}
+	}
+	return ""
+}
+
+// importsFoldingRange returns the folding ranges for runs of consecutive
+// unparenthesized import declarations, such as:
+//
+//	import "fmt"
+//	import "os"
+//
+// The folding range starts at the end of the first declaration, and ends
+// at the end of the last one and has kind protocol.Imports.
+func importsFoldingRange(pgf *parsego.File) (imports []*FoldingRangeInfo) {
+	var run []*ast.GenDecl
+	flush := func() {
+		if len(run) > 1 {
+			rng, err := pgf.PosRange(run[0].End(), run[len(run)-1].End())
+			if err != nil {
+				bug.Reportf("failed to create mapped range: %s", err) // can't happen
+			} else {
+				imports = append(imports, &FoldingRangeInfo{
+					Range: rng,
+					Kind:  protocol.Imports,
+				})
+			}
+		}
+		run = run[:0]
+	}
+	for _, decl := range pgf.File.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			break // imports precede all other declarations
+		}
+		if decl.Lparen.IsValid() {
+			flush() // a parenthesized declaration has its own folding range
+			continue
+		}
+		run = append(run, decl)
+	}
+	flush()
+	return imports
+}
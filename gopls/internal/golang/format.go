@@ -14,8 +14,10 @@ import (
 	"go/format"
 	"go/parser"
 	"go/token"
+	"os/exec"
 	"strings"
 	"text/scanner"
+	"time"
 
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/parsego"
@@ -93,9 +95,55 @@ func Format(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]pr
 		}
 		formatted = string(b)
 	}
+
+	// Run the external formatters, if any.
+	for _, command := range snapshot.Options().Formatters {
+		b, err := runFormatter(ctx, snapshot, fh.URI().DirPath(), command, []byte(formatted))
+		if err != nil {
+			return nil, err
+		}
+		formatted = string(b)
+	}
 	return computeTextEdits(ctx, pgf, formatted)
 }
 
+// formatterTimeout is the maximum duration of a run of an external
+// formatter.
+const formatterTimeout = 10 * time.Second
+
+// runFormatter runs the external formatter command, which reads Go
+// source from its standard input and writes the formatted source to
+// its standard output, in the specified directory. It reports an error
+// if the command fails or its output is not valid Go.
+func runFormatter(ctx context.Context, snapshot *cache.Snapshot, dir string, command []string, src []byte) ([]byte, error) {
+	ctx, done := event.Start(ctx, "golang.runFormatter")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, formatterTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = snapshot.View().Env()
+	cmd.Stdin = bytes.NewReader(src)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %v", formatterTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("formatter %q failed: %v:\n%s", command[0], err, msg)
+		}
+		return nil, fmt.Errorf("formatter %q failed: %v", command[0], err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "", stdout.Bytes(), parser.ParseComments|parser.SkipObjectResolution); err != nil {
+		return nil, fmt.Errorf("formatter %q produced invalid Go: %v", command[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
 func formatSource(ctx context.Context, fh file.Handle) ([]byte, error) {
 	_, done := event.Start(ctx, "golang.formatSource")
 	defer done()
@@ -193,6 +241,11 @@ func computeFixEdits(src []byte, options *imports.Options, fixes []*imports.Impo
 		return nil, err
 	}
 	extra := !strings.Contains(left, "\n") // one line may have more than imports
+	for _, fix := range fixes {
+		if fix.FixType == imports.MergeImport {
+			extra = true // the fix renames references throughout the file
+		}
+	}
 	if extra {
 		left = string(src)
 	}
@@ -0,0 +1,307 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines support for fuzz tests: their corpus, and the
+// "Add corpus entry as seed" code action.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/astutil"
+)
+
+var fuzzRe = regexp.MustCompile(`^Fuzz([^a-z]|$)`)
+
+// fuzzFuncs returns all Fuzz functions in the specified file.
+func fuzzFuncs(info *types.Info, pgf *parsego.File) ([]testFunc, error) {
+	if !strings.HasSuffix(pgf.URI.Path(), "_test.go") {
+		return nil, nil
+	}
+	var fuzz []testFunc
+	for _, d := range pgf.File.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && matchTestFunc(fn, info, fuzzRe, "F") {
+			rng, err := pgf.NodeRange(fn)
+			if err != nil {
+				return nil, err
+			}
+			fuzz = append(fuzz, testFunc{fn.Name.Name, rng})
+		}
+	}
+	return fuzz, nil
+}
+
+// A FuzzCorpusEntry describes a file of the corpus of a fuzz test.
+type FuzzCorpusEntry struct {
+	Filename string
+	Seed     bool     // in testdata/fuzz, as opposed to the build cache
+	Values   []string // Go expressions for the arguments of the fuzz target
+	Err      error    // decoding error
+}
+
+// FuzzCorpus returns the entries of the corpus of the named fuzz test
+// of the package mp: first the seed corpus in the testdata/fuzz
+// directory of the package, which includes the failing inputs found by
+// the fuzzer, then the corpus that the fuzzer generated in the build
+// cache.
+func FuzzCorpus(ctx context.Context, snapshot *cache.Snapshot, mp *metadata.Package, name string) ([]FuzzCorpusEntry, error) {
+	var entries []FuzzCorpusEntry
+	for _, seed := range []bool{true, false} {
+		dir := fuzzCorpusDir(snapshot, mp, name, seed)
+		if dir == "" {
+			continue
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue // no corpus
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			filename := filepath.Join(dir, f.Name())
+			fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(filename))
+			if err != nil {
+				return nil, err // context cancelled
+			}
+			entry := FuzzCorpusEntry{Filename: filename, Seed: seed}
+			if content, err := fh.Content(); err != nil {
+				entry.Err = err
+			} else {
+				entry.Values, entry.Err = parseFuzzCorpusFile(content)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// fuzzCorpusDir returns the directory of the seed corpus, or of the
+// corpus generated in the build cache, of the named fuzz test of the
+// package mp.
+func fuzzCorpusDir(snapshot *cache.Snapshot, mp *metadata.Package, name string, seed bool) string {
+	if seed {
+		if len(mp.CompiledGoFiles) == 0 {
+			return ""
+		}
+		return filepath.Join(mp.CompiledGoFiles[0].DirPath(), "testdata", "fuzz", name)
+	}
+	gocache := snapshot.View().Folder().Env.GOCACHE
+	if gocache == "" || gocache == "off" {
+		return ""
+	}
+	// The fuzzer keys its cache by the path of the package under
+	// test, even for an external test package.
+	pkgPath := mp.PkgPath
+	if mp.ForTest != "" {
+		pkgPath = mp.ForTest
+	}
+	return filepath.Join(gocache, "fuzz", filepath.FromSlash(string(pkgPath)), name)
+}
+
+// fuzzCorpusHeader is the first line of a corpus file.
+const fuzzCorpusHeader = "go test fuzz v1"
+
+// fuzzCorpusTypes is the set of types of the values in a corpus file.
+var fuzzCorpusTypes = map[string]bool{
+	"[]byte": true, "string": true, "bool": true, "byte": true, "rune": true,
+	"float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// parseFuzzCorpusFile decodes a file of a fuzz corpus, whose lines
+// after the header are conversions such as int(1) or []byte("abc"),
+// and returns the Go expression of each value.
+//
+// Floating-point values whose bits cannot be expressed by a literal,
+// such as NaNs, are written in the form math.Float64frombits(0x...).
+func parseFuzzCorpusFile(content []byte) ([]string, error) {
+	lines := strings.Split(string(content), "\n")
+	if strings.TrimSpace(lines[0]) != fuzzCorpusHeader {
+		return nil, fmt.Errorf("missing %q header", fuzzCorpusHeader)
+	}
+	var values []string
+	for i, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		expr, err := parser.ParseExpr(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || call.Ellipsis.IsValid() {
+			return nil, fmt.Errorf("line %d: not a conversion", i+2)
+		}
+		switch fun := types.ExprString(call.Fun); fun {
+		case "math.Float32frombits", "math.Float64frombits":
+		default:
+			if !fuzzCorpusTypes[fun] {
+				return nil, fmt.Errorf("line %d: unsupported type %s", i+2, fun)
+			}
+		}
+		switch arg := call.Args[0].(type) {
+		case *ast.BasicLit, *ast.Ident: // 1, "abc", true
+		case *ast.UnaryExpr: // -1
+			if _, ok := arg.X.(*ast.BasicLit); !ok || arg.Op != token.SUB {
+				return nil, fmt.Errorf("line %d: value is not a literal", i+2)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: value is not a literal", i+2)
+		}
+		values = append(values, line)
+	}
+	return values, nil
+}
+
+// FuzzCorpusHTML formats the report of the corpus of a fuzz test.
+func FuzzCorpusHTML(pkgPath PackagePath, name string, entries []FuzzCorpusEntry, web Web) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<!DOCTYPE html>
+<html>
+<head>
+<style>
+li { font-family: monospace; }
+p { max-width: 6in; }
+</style>
+  <script src="/assets/common.js"></script>
+  <link rel="stylesheet" href="/assets/common.css">
+</head>
+<body>
+<h1>Corpus of %s.%s</h1>
+<p>
+  The seed corpus (in testdata/fuzz) includes the failing inputs
+  found by the fuzzer. Use the "Add corpus entry as seed" code
+  action within the fuzz test to add an entry to its seeds.
+</p>
+`, html.EscapeString(string(pkgPath)), html.EscapeString(name))
+	for _, seed := range []bool{true, false} {
+		if seed {
+			buf.WriteString("<h2>Seed corpus</h2>\n")
+		} else {
+			buf.WriteString("<h2>Generated corpus</h2>\n")
+		}
+		var group []FuzzCorpusEntry
+		for _, e := range entries {
+			if e.Seed == seed {
+				group = append(group, e)
+			}
+		}
+		if len(group) == 0 {
+			buf.WriteString("<p>(none)</p>\n")
+			continue
+		}
+		buf.WriteString("<ul>\n")
+		for _, e := range group {
+			url := web.SrcURL(e.Filename, 1, 1)
+			fmt.Fprintf(&buf, "<li>%s: ", sourceLink(html.EscapeString(filepath.Base(e.Filename)), string(url)))
+			if e.Err != nil {
+				fmt.Fprintf(&buf, "<i>%s</i>", html.EscapeString(e.Err.Error()))
+			} else {
+				buf.WriteString(html.EscapeString(strings.Join(e.Values, ", ")))
+			}
+			buf.WriteString("</li>\n")
+		}
+		buf.WriteString("</ul>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
+
+// refactorRewriteAddFuzzSeed produces "Add corpus entry as seed" code
+// actions, which add a call to F.Add to a fuzz test for each entry of
+// its seed corpus in testdata/fuzz, such as a failing input found by
+// the fuzzer.
+func refactorRewriteAddFuzzSeed(ctx context.Context, req *codeActionsRequest) error {
+	// Find the enclosing fuzz test.
+	var decl *ast.FuncDecl
+	for _, d := range req.pgf.File.Decls {
+		if d, ok := d.(*ast.FuncDecl); ok && d.Body != nil && astutil.NodeContains(d, req.start) {
+			decl = d
+			break
+		}
+	}
+	if decl == nil ||
+		!strings.HasSuffix(req.pgf.URI.Path(), "_test.go") ||
+		!matchTestFunc(decl, req.pkg.TypesInfo(), fuzzRe, "F") {
+		return nil
+	}
+	params := decl.Type.Params.List
+	if len(params[0].Names) == 0 || params[0].Names[0].Name == "_" {
+		return nil // no F to call
+	}
+	f := params[0].Names[0].Name
+
+	dir := fuzzCorpusDir(req.snapshot, req.pkg.Metadata(), decl.Name.Name, true)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil // no seed corpus
+	}
+
+	// Insert after the last f.Add call at the top of the body,
+	// or at the start of the body.
+	pos := decl.Body.Lbrace + 1
+	for _, stmt := range decl.Body.List {
+		if isFuzzAddCall(stmt, f) {
+			pos = stmt.End()
+		}
+	}
+	rng, err := req.pgf.PosRange(pos, pos)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fh, err := req.snapshot.ReadFile(ctx, protocol.URIFromPath(filepath.Join(dir, file.Name())))
+		if err != nil {
+			return err
+		}
+		content, err := fh.Content()
+		if err != nil {
+			continue
+		}
+		values, err := parseFuzzCorpusFile(content)
+		if err != nil || len(values) == 0 || strings.Contains(strings.Join(values, ""), "math.") {
+			continue // can't express as literals
+		}
+		call := fmt.Sprintf("\n\t%s.Add(%s)", f, strings.Join(values, ", "))
+		req.addEditAction(fmt.Sprintf("Add corpus entry %s as seed", file.Name()), nil,
+			protocol.DocumentChangeEdit(req.fh, []protocol.TextEdit{{Range: rng, NewText: call}}))
+	}
+	return nil
+}
+
+// isFuzzAddCall reports whether stmt is a call f.Add(...).
+func isFuzzAddCall(stmt ast.Stmt, f string) bool {
+	if expr, ok := stmt.(*ast.ExprStmt); ok {
+		if call, ok := expr.X.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Add" {
+				id, ok := sel.X.(*ast.Ident)
+				return ok && id.Name == f
+			}
+		}
+	}
+	return false
+}
@@ -58,6 +58,10 @@ type hoverResult struct {
 	// fullDocumentation is the symbol's full documentation.
 	fullDocumentation string
 
+	// docLinks, if non-nil, resolves the doc links of the documentation
+	// in the context of the file that declares it.
+	docLinks *DocLinks
+
 	// signature is the symbol's signature.
 	signature string
 
@@ -181,7 +185,7 @@ func hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp pro
 	// object.
 	// As with import paths, we allow hovering just after the package name.
 	if pgf.File.Name != nil && gastutil.NodeContains(pgf.File.Name, pos) {
-		return hoverPackageName(pkg, pgf)
+		return hoverPackageName(snapshot, pkg, pgf)
 	}
 
 	// Handle hovering over embed directive argument.
@@ -296,6 +300,12 @@ func hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp pro
 		return *hoverRange, h, err
 	}
 
+	// C symbols are declared in Go by cgo-generated code.
+	if name, ok := cache.CgoName(obj.Name()); ok && obj.Pkg() == pkg.Types() {
+		h, err := hoverCgo(ctx, snapshot, pkg, obj, name, qual)
+		return *hoverRange, h, err
+	}
+
 	// For all other objects, consider the full syntax of their declaration in
 	// order to correctly compute their documentation, signature, and link.
 	//
@@ -517,12 +527,16 @@ func hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp pro
 		linkPath string            // => link path
 		anchor   string            // link anchor
 		linkMeta *metadata.Package // metadata for the linked package
+		docLinks *DocLinks         // resolves doc links of the documentation
 	)
 	{
 		linkMeta = findFileInDeps(snapshot, pkg.Metadata(), declPGF.URI)
 		if linkMeta == nil {
 			return protocol.Range{}, nil, bug.Errorf("no package data for %s", declPGF.URI)
 		}
+		if obj.Pkg() != nil {
+			docLinks = newDocLinks(snapshot, linkMeta, obj.Pkg(), declPGF.File)
+		}
 
 		// For package names, we simply link to their imported package.
 		if pkgName, ok := obj.(*types.PkgName); ok {
@@ -617,6 +631,7 @@ func hover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pp pro
 	return *hoverRange, &hoverResult{
 		synopsis:          doc.Synopsis(docText),
 		fullDocumentation: docText,
+		docLinks:          docLinks,
 		singleLine:        singleLineSignature,
 		symbolName:        linkName,
 		signature:         signature,
@@ -748,11 +763,15 @@ func hoverImport(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Packa
 
 // hoverPackageName computes hover information for the package name of the file
 // pgf in pkg.
-func hoverPackageName(pkg *cache.Package, pgf *parsego.File) (protocol.Range, *hoverResult, error) {
-	var comment *ast.CommentGroup
+func hoverPackageName(snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File) (protocol.Range, *hoverResult, error) {
+	var (
+		comment  *ast.CommentGroup
+		docLinks *DocLinks
+	)
 	for _, pgf := range pkg.CompiledGoFiles() {
 		if pgf.File.Doc != nil {
 			comment = pgf.File.Doc
+			docLinks = newDocLinks(snapshot, pkg.Metadata(), pkg.Types(), pgf.File)
 			break
 		}
 	}
@@ -801,6 +820,7 @@ func hoverPackageName(pkg *cache.Package, pgf *parsego.File) (protocol.Range, *h
 		signature:         "package " + string(pkg.Metadata().Name),
 		synopsis:          doc.Synopsis(docText),
 		fullDocumentation: docText,
+		docLinks:          docLinks,
 		footer:            footer,
 	}, nil
 }
@@ -1150,6 +1170,24 @@ func HoverDocForObject(ctx context.Context, snapshot *cache.Snapshot, fset *toke
 	return chooseDocComment(decl, spec, field), nil
 }
 
+// DocLinksForObject returns a DocLinks that resolves the doc links of
+// the doc comment of obj, a symbol referenced by pkg, in the context of
+// the file that declares it. It returns nil if the file is not found.
+func DocLinksForObject(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, obj types.Object) *DocLinks {
+	if obj.Pkg() == nil {
+		return nil
+	}
+	pgf, _, err := parseFull(ctx, snapshot, pkg.FileSet(), obj.Pos())
+	if err != nil {
+		return nil
+	}
+	mp := findFileInDeps(snapshot, pkg.Metadata(), pgf.URI)
+	if mp == nil {
+		return nil
+	}
+	return newDocLinks(snapshot, mp, obj.Pkg(), pgf.File)
+}
+
 func chooseDocComment(decl ast.Decl, spec ast.Spec, field *ast.Field) *ast.CommentGroup {
 	if field != nil {
 		if field.Doc != nil {
@@ -1271,8 +1309,30 @@ func formatHover(h *hoverResult, options *settings.Options, pkgURL func(path Pac
 		case settings.FullDocumentation:
 			doc = h.fullDocumentation
 		}
-		if options.PreferredContentFormat == protocol.Markdown {
-			doc = DocCommentToMarkdown(doc, options)
+		if markdown || options.MaxHoverLines > 0 {
+			docURL := func(path PackagePath, fragment string) string {
+				switch {
+				case options.LinksInHover == settings.LinksInHover_None:
+					return ""
+				case pkgURL != nil: // LinksInHover == "gopls"
+					return string(pkgURL(path, fragment))
+				default:
+					return h.docLinks.publicLink(options, path, fragment)
+				}
+			}
+			// Plain text is reformatted only if truncated.
+			formatted, truncated := h.docLinks.format(doc, markdown, docURL, options.MaxHoverLines)
+			if markdown || truncated {
+				doc = formatted
+			}
+			if truncated {
+				// Link to the full documentation, if possible.
+				more := "…"
+				if url, caption := hoverLink(h, options, pkgURL); url != "" && markdown {
+					more = fmt.Sprintf("[Read more %s](%s)", caption, url)
+				}
+				doc = strings.TrimRight(doc, "\n") + "\n\n" + more
+			}
 		}
 		sections = append(sections, []string{
 			doc,
@@ -1392,11 +1452,29 @@ func StdSymbolOf(obj types.Object) *stdlib.Symbol {
 
 // If pkgURL is non-nil, it should be used to generate doc links.
 func formatLink(h *hoverResult, options *settings.Options, pkgURL func(path PackagePath, fragment string) protocol.URI) string {
-	if options.LinksInHover == settings.LinksInHover_None || h.linkPath == "" {
+	url, caption := hoverLink(h, options, pkgURL)
+	if url == "" {
 		return ""
 	}
-	var url protocol.URI
-	var caption string
+	switch options.PreferredContentFormat {
+	case protocol.Markdown:
+		return fmt.Sprintf("[`%s` %s](%s)", h.symbolName, caption, url)
+	case protocol.PlainText:
+		return ""
+	default:
+		return url
+	}
+}
+
+// hoverLink returns the URL of the documentation of the hovered
+// symbol, and a caption describing where it leads, such as
+// "on pkg.go.dev". It returns "" if the symbol is not linkable.
+//
+// If pkgURL is non-nil, it should be used to generate doc links.
+func hoverLink(h *hoverResult, options *settings.Options, pkgURL func(path PackagePath, fragment string) protocol.URI) (url protocol.URI, caption string) {
+	if options.LinksInHover == settings.LinksInHover_None || h.linkPath == "" {
+		return "", ""
+	}
 	if pkgURL != nil { // LinksInHover == "gopls"
 		// Discard optional module version portion.
 		// (Ideally the hoverResult would retain the structure...)
@@ -1412,19 +1490,12 @@ func formatLink(h *hoverResult, options *settings.Options, pkgURL func(path Pack
 		caption = "in gopls doc viewer"
 	} else {
 		if options.LinkTarget == "" {
-			return ""
+			return "", ""
 		}
 		url = cache.BuildLink(options.LinkTarget, h.linkPath, h.linkAnchor)
 		caption = "on " + options.LinkTarget
 	}
-	switch options.PreferredContentFormat {
-	case protocol.Markdown:
-		return fmt.Sprintf("[`%s` %s](%s)", h.symbolName, caption, url)
-	case protocol.PlainText:
-		return ""
-	default:
-		return url
-	}
+	return url, caption
 }
 
 // findDeclInfo returns the syntax nodes involved in the declaration of the
@@ -139,33 +139,49 @@ func parameterNames(node ast.Node, m *protocol.Mapper, tf *token.File, info *typ
 	return hints
 }
 
-func funcTypeParams(node ast.Node, m *protocol.Mapper, tf *token.File, info *types.Info, _ *types.Qualifier) []protocol.InlayHint {
+// funcTypeParams returns a hint for the type arguments that are
+// inferred in a call to a generic function, such as f or pkg.f, that
+// does not write them all explicitly. For a partial instantiation,
+// such as f[int], the hint shows only the inferred type arguments.
+func funcTypeParams(node ast.Node, m *protocol.Mapper, tf *token.File, info *types.Info, q *types.Qualifier) []protocol.InlayHint {
 	ce, ok := node.(*ast.CallExpr)
 	if !ok {
 		return nil
 	}
-	id, ok := ce.Fun.(*ast.Ident)
-	if !ok {
+	fun, _, explicit, rbrack := typeparams.UnpackIndexExpr(ce.Fun)
+	if fun == nil {
+		fun = ce.Fun
+	}
+	var id *ast.Ident
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
 		return nil
 	}
 	inst := info.Instances[id]
-	if inst.TypeArgs == nil {
-		return nil
-	}
-	start, err := m.PosPosition(tf, id.End())
-	if err != nil {
+	if inst.TypeArgs == nil || len(explicit) >= inst.TypeArgs.Len() {
 		return nil
 	}
 	var args []string
-	for i := 0; i < inst.TypeArgs.Len(); i++ {
-		args = append(args, inst.TypeArgs.At(i).String())
+	for i := len(explicit); i < inst.TypeArgs.Len(); i++ {
+		args = append(args, types.TypeString(inst.TypeArgs.At(i), *q))
+	}
+	// Show the inferred type arguments after the function name,
+	// or after the explicit ones.
+	pos, label := id.End(), "["+strings.Join(args, ", ")+"]"
+	if len(explicit) > 0 {
+		pos, label = rbrack, ", "+strings.Join(args, ", ")
 	}
-	if len(args) == 0 {
+	start, err := m.PosPosition(tf, pos)
+	if err != nil {
 		return nil
 	}
 	return []protocol.InlayHint{{
 		Position: start,
-		Label:    buildLabel("[" + strings.Join(args, ", ") + "]"),
+		Label:    buildLabel(label),
 		Kind:     protocol.Type,
 	}}
 }
@@ -0,0 +1,133 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// InlineValue returns the inline values that a debugger should display
+// in the specified range of the file when execution has stopped at
+// the specified location: each occurrence, in the function enclosing
+// the stop and no later than its line, of a local variable that is in
+// scope at the stop, or of a selection of fields from one.
+//
+// Variables are reported as lookups by name, and field selections as
+// expressions to evaluate. A variable is not reported if its name
+// refers to a different variable at the stop, since the debugger would
+// look up the wrong one.
+func InlineValue(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng, stopped protocol.Range) ([]protocol.InlineValue, error) {
+	ctx, done := event.Start(ctx, "golang.InlineValue")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, fmt.Errorf("getting file for InlineValue: %w", err)
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	// The end of the stopped range denotes the line of the stop.
+	stop, err := pgf.PositionPos(stopped.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restrict the range to the function declaration enclosing the stop.
+	path, _ := astutil.PathEnclosingInterval(pgf.File, stop, stop)
+	var decl *ast.FuncDecl
+	for _, n := range path {
+		if fn, ok := n.(*ast.FuncDecl); ok && fn.Body != nil {
+			decl = fn
+			break
+		}
+	}
+	if decl == nil {
+		return nil, nil // not within a function
+	}
+	start, end = max(start, decl.Pos()), min(end, decl.End())
+	scope := pkg.Types().Scope().Innermost(stop)
+	if scope == nil {
+		return nil, nil
+	}
+
+	info := pkg.TypesInfo()
+
+	// inScope reports whether v is a local variable that the debugger
+	// would find by looking up its name at the stop.
+	inScope := func(v *types.Var) bool {
+		if v.Name() == "_" || v.IsField() || v.Parent() == nil || v.Parent() == pkg.Types().Scope() {
+			return false
+		}
+		_, obj := scope.LookupParent(v.Name(), stop)
+		return obj == v
+	}
+
+	// fieldsOf returns the variable from which the fields of a
+	// selection such as x.f.g are selected, or nil.
+	var fieldsOf func(e ast.Expr) *types.Var
+	fieldsOf = func(e ast.Expr) *types.Var {
+		switch e := e.(type) {
+		case *ast.Ident:
+			v, _ := info.Uses[e].(*types.Var)
+			return v
+		case *ast.SelectorExpr:
+			if sel, ok := info.Selections[e]; ok && sel.Kind() == types.FieldVal {
+				return fieldsOf(e.X)
+			}
+		case *ast.ParenExpr:
+			return fieldsOf(e.X)
+		}
+		return nil
+	}
+
+	var values []protocol.InlineValue
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if n == nil || n.End() <= start || end <= n.Pos() {
+			return false
+		}
+		nrng, err := pgf.NodeRange(n)
+		if err != nil || nrng.Start.Line > stopped.End.Line {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			// A selection of fields is evaluated as an expression.
+			if sel, ok := info.Selections[n]; ok && sel.Kind() == types.FieldVal {
+				if v := fieldsOf(n); v != nil && inScope(v) {
+					startOffset, endOffset, err := pgf.NodeOffsets(n)
+					if err != nil {
+						return false
+					}
+					values = append(values, protocol.InlineValue{Value: protocol.InlineValueEvaluatableExpression{
+						Range:      nrng,
+						Expression: string(pgf.Src[startOffset:endOffset]),
+					}})
+					return false
+				}
+			}
+		case *ast.Ident:
+			if v, ok := info.ObjectOf(n).(*types.Var); ok && inScope(v) {
+				values = append(values, protocol.InlineValue{Value: protocol.InlineValueVariableLookup{
+					Range:               nrng,
+					VariableName:        v.Name(),
+					CaseSensitiveLookup: true,
+				}})
+			}
+		}
+		return true
+	})
+	return values, nil
+}
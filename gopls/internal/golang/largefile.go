@@ -0,0 +1,102 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// This file defines the degraded mode of operation for large Go
+// files, typically machine-generated ones, whose full support would
+// make gopls unresponsive. In degraded mode, gopls reports only the
+// syntax errors of a file, and provides no semantic tokens for it;
+// features that depend only on syntax, such as folding ranges, are
+// unaffected. The threshold is the largeFileSize setting.
+
+// IsLargeFile reports whether the contents of the Go file exceed the
+// largeFileSize setting, so that gopls operates on it in degraded mode.
+func IsLargeFile(snapshot *cache.Snapshot, fh file.Handle) bool {
+	limit := snapshot.Options().LargeFileSize
+	if limit <= 0 {
+		return false
+	}
+	content, err := fh.Content()
+	return err == nil && len(content) > limit
+}
+
+// LargeFileDiagnostics returns an informational diagnostic for each
+// non-ignored Go file of the specified packages that is large enough
+// to be operated on in degraded mode (see [IsLargeFile]). The keys of
+// the result are thus the set of such files.
+func LargeFileDiagnostics(ctx context.Context, snapshot *cache.Snapshot, pkgs []*metadata.Package) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	if snapshot.Options().LargeFileSize <= 0 {
+		return nil, nil
+	}
+	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
+	seen := make(map[protocol.DocumentURI]bool)
+	for _, mp := range pkgs {
+		for _, uri := range mp.CompiledGoFiles {
+			if seen[uri] || snapshot.IgnoredFile(uri) {
+				continue
+			}
+			seen[uri] = true
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				return nil, err
+			}
+			if !IsLargeFile(snapshot, fh) {
+				continue
+			}
+			diag, err := largeFileDiagnostic(ctx, snapshot, fh)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				continue
+			}
+			reports[uri] = []*cache.Diagnostic{diag}
+		}
+	}
+	return reports, nil
+}
+
+// largeFileDiagnostic returns the diagnostic, at its package clause,
+// that informs the user that the large file is in degraded mode.
+func largeFileDiagnostic(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) (*cache.Diagnostic, error) {
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+	if err != nil {
+		return nil, err
+	}
+	rng, err := pgf.NodeRange(pgf.File.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &cache.Diagnostic{
+		URI:      fh.URI(),
+		Range:    rng,
+		Severity: protocol.SeverityInformation,
+		Source:   cache.LargeFile,
+		Message: fmt.Sprintf("%s is larger than %d bytes (largeFileSize): only syntax errors are reported, and semantic highlighting is disabled",
+			filepath.Base(fh.URI().Path()), snapshot.Options().LargeFileSize),
+	}, nil
+}
+
+// SyntaxDiagnostics returns a new slice of the diagnostics that
+// report syntax errors, which are the only ones reported for a large
+// file.
+func SyntaxDiagnostics(diags []*cache.Diagnostic) []*cache.Diagnostic {
+	return slices.DeleteFunc(slices.Clone(diags), func(diag *cache.Diagnostic) bool {
+		return diag.Source != cache.ParseError
+	})
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// LinkedEditingRanges returns the ranges of text that should be
+// edited together with the text at the given position, or nil if
+// there are none. The linked ranges are:
+//   - the occurrences in the file of a local variable, constant, or label;
+//   - the occurrences of a term in the //go:build and // +build lines
+//     of a file that has both; and
+//   - if the linkedEditingStructTags option is set, the name of a struct
+//     field and the identically spelled names in its tag.
+func LinkedEditingRanges(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) (*protocol.LinkedEditingRanges, error) {
+	ctx, done := event.Start(ctx, "golang.LinkedEditingRanges")
+	defer done()
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	pos, err := pgf.PositionPos(position)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ranges      []posRange
+		wordPattern string
+	)
+	if ranges = buildTermRanges(pgf, pos); ranges != nil {
+		wordPattern = `[\w.]+`
+	}
+	if ranges == nil && snapshot.Options().LinkedEditingStructTags {
+		ranges = structTagRanges(pgf, pos)
+	}
+	if ranges == nil {
+		ranges = localObjectRanges(pkg.TypesInfo(), pkg.Types(), pgf, pos)
+	}
+	if len(ranges) < 2 {
+		return nil, nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+	result := &protocol.LinkedEditingRanges{WordPattern: wordPattern}
+	for _, r := range ranges {
+		rng, err := pgf.PosRange(r.start, r.end)
+		if err != nil {
+			return nil, err
+		}
+		result.Ranges = append(result.Ranges, rng)
+	}
+	return result, nil
+}
+
+// localObjectRanges returns the ranges of the occurrences in pgf of
+// the local variable, constant, or label denoted by the identifier
+// at pos, including its declaration.
+func localObjectRanges(info *types.Info, pkg *types.Package, pgf *parsego.File, pos token.Pos) []posRange {
+	id := identAt(pgf.File, pos)
+	if id == nil {
+		return nil
+	}
+	obj := info.ObjectOf(id)
+	switch obj := obj.(type) {
+	case *types.Label:
+		// Labels have no scope, but are always local.
+	case *types.Var:
+		if obj.IsField() || obj.Parent() == nil || obj.Parent() == pkg.Scope() {
+			return nil
+		}
+	case *types.Const:
+		if obj.Parent() == nil || obj.Parent() == pkg.Scope() {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if obj.Pkg() != pkg {
+		return nil
+	}
+
+	var (
+		ranges   []posRange
+		declared bool
+	)
+	ast.Inspect(pgf.File, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.ObjectOf(id) == obj {
+			ranges = append(ranges, posRange{id.Pos(), id.End()})
+			declared = declared || info.Defs[id] == obj
+		}
+		return true
+	})
+	// The symbolic variable of a type switch is a distinct
+	// (implicit) object in each case clause; the identifiers of
+	// the clauses are not linked to the declaring identifier.
+	if !declared {
+		return nil
+	}
+	return ranges
+}
+
+// identAt returns the identifier at pos, or immediately before it.
+func identAt(file *ast.File, pos token.Pos) *ast.Ident {
+	for _, p := range []token.Pos{pos, pos - 1} {
+		path, _ := astutil.PathEnclosingInterval(file, p, p)
+		if len(path) > 0 {
+			if id, ok := path[0].(*ast.Ident); ok {
+				return id
+			}
+		}
+	}
+	return nil
+}
+
+// buildTermRanges returns the ranges of the occurrences of the build
+// constraint term at pos in the //go:build and // +build lines of
+// pgf, if it has both.
+func buildTermRanges(pgf *parsego.File, pos token.Pos) []posRange {
+	var (
+		terms              []namedRange
+		goBuild, plusBuild bool
+	)
+	for _, group := range pgf.File.Comments {
+		if group.Pos() > pgf.File.Package {
+			break // constraints must precede the package clause
+		}
+		for _, c := range group.List {
+			switch {
+			case constraint.IsGoBuild(c.Text):
+				goBuild = true
+			case constraint.IsPlusBuild(c.Text):
+				plusBuild = true
+			default:
+				continue
+			}
+			// Skip the "//go:build" or "// +build" prefix.
+			i := strings.Index(c.Text, "build") + len("build")
+			for i < len(c.Text) {
+				if !isBuildTermByte(c.Text[i]) {
+					i++
+					continue
+				}
+				j := i
+				for j < len(c.Text) && isBuildTermByte(c.Text[j]) {
+					j++
+				}
+				terms = append(terms, namedRange{c.Text[i:j], posRange{c.Slash + token.Pos(i), c.Slash + token.Pos(j)}})
+				i = j
+			}
+		}
+	}
+	if !goBuild || !plusBuild {
+		return nil
+	}
+
+	var term string
+	for _, t := range terms {
+		if t.rng.start <= pos && pos <= t.rng.end {
+			term = t.name
+			break
+		}
+	}
+	if term == "" {
+		return nil
+	}
+	var ranges []posRange
+	for _, t := range terms {
+		if t.name == term {
+			ranges = append(ranges, t.rng)
+		}
+	}
+	return ranges
+}
+
+// A namedRange is the range of a word in a comment or string literal.
+type namedRange struct {
+	name string
+	rng  posRange
+}
+
+// isBuildTermByte reports whether b may appear in a build constraint term.
+func isBuildTermByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '_' || b == '.'
+}
+
+// structTagRanges returns the ranges of the name of the struct field
+// at pos and of the names in its tag that are spelled the same way,
+// if the position is within one of them.
+func structTagRanges(pgf *parsego.File, pos token.Pos) []posRange {
+	path, _ := astutil.PathEnclosingInterval(pgf.File, pos, pos)
+	var field *ast.Field
+	for _, n := range path {
+		if f, ok := n.(*ast.Field); ok {
+			field = f
+			break
+		}
+	}
+	if field == nil || field.Tag == nil || len(field.Names) != 1 {
+		return nil
+	}
+	name := field.Names[0]
+	ranges := []posRange{{name.Pos(), name.End()}}
+	inRange := name.Pos() <= pos && pos <= name.End()
+	for _, tagName := range structTagNames(field.Tag) {
+		if tagName.name == name.Name {
+			ranges = append(ranges, tagName.rng)
+			inRange = inRange || tagName.rng.start <= pos && pos <= tagName.rng.end
+		}
+	}
+	if !inRange || len(ranges) < 2 {
+		return nil
+	}
+	// The field must belong to a struct, not a parameter list.
+	for i, n := range path {
+		if n == field {
+			if i+2 < len(path) {
+				if _, ok := path[i+2].(*ast.StructType); ok {
+					return ranges
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// structTagNames returns the names in a raw-string struct tag of the
+// conventional form `key:"name,options" key2:"name2"`.
+func structTagNames(lit *ast.BasicLit) []namedRange {
+	var names []namedRange
+	for _, pair := range scanStructTag(lit) {
+		if name, _, _ := strings.Cut(pair.value.name, ","); name != "" && !strings.Contains(name, `\`) {
+			start := pair.value.rng.start
+			names = append(names, namedRange{name, posRange{start, start + token.Pos(len(name))}})
+		}
+	}
+	return names
+}
+
+// A structTagPair is a key:"value" pair of a struct tag.
+// The range of the value excludes its quotation marks.
+type structTagPair struct {
+	key, value namedRange
+}
+
+// scanStructTag returns the key:"value" pairs of a raw-string struct
+// tag of the conventional form `key:"value" key2:"value2"`.
+func scanStructTag(lit *ast.BasicLit) []structTagPair {
+	if !strings.HasPrefix(lit.Value, "`") || len(lit.Value) < 2 {
+		return nil
+	}
+	tag := lit.Value[1 : len(lit.Value)-1]
+	base := lit.Pos() + 1 // position of tag[0]
+
+	var pairs []structTagPair
+	// This loop follows reflect.StructTag.Lookup.
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		base += token.Pos(i)
+		tag = tag[i:]
+
+		// Scan the key.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := namedRange{tag[:i], posRange{base, base + token.Pos(i)}}
+
+		// Scan the quoted value.
+		start := i + 2
+		i = start
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value := namedRange{tag[start:i], posRange{base + token.Pos(start), base + token.Pos(i)}}
+		pairs = append(pairs, structTagPair{key, value})
+		base += token.Pos(i + 1)
+		tag = tag[i+1:]
+	}
+	return pairs
+}
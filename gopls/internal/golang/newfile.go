@@ -0,0 +1,157 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the initial content of new Go files
+// (workspace/didCreateFiles).
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// NewFile returns the edits that populate the new Go file fh with a
+// header comment, a build constraint, and a package clause, or nil if
+// the file is not empty.
+//
+// The package clause declares the package of the other files in the
+// same directory, or if there are none, a package named after the
+// directory. The header is formed from the newFileHeader setting, or
+// copied from the copyright header of another file in the directory.
+// The build constraint, if any, is derived from the suffix of the file
+// name, for constraints that are not implied by it, such as "unix".
+func NewFile(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]protocol.TextEdit, error) {
+	content, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) > 0 {
+		return nil, nil // not a new file
+	}
+
+	// Find the package of the other files in the directory.
+	dir := fh.URI().Dir()
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		pkgName  PackageName
+		siblings []protocol.DocumentURI
+	)
+	for _, mp := range allMetadata {
+		if mp.ForTest != "" || metadata.IsCommandLineArguments(mp.ID) || mp.Name == "" {
+			continue
+		}
+		var others []protocol.DocumentURI
+		for _, uri := range mp.GoFiles {
+			if uri.Dir() == dir && uri != fh.URI() {
+				others = append(others, uri)
+			}
+		}
+		if len(others) > 0 {
+			pkgName, siblings = mp.Name, others
+			break
+		}
+	}
+	if pkgName == "" {
+		base := filepath.Base(dir.Path())
+		if !isValidIdentifier(base) {
+			return nil, nil // no sensible package name
+		}
+		pkgName = PackageName(base)
+	}
+
+	header, err := newFileHeader(ctx, snapshot, siblings)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if header != "" {
+		buf.WriteString(header)
+		buf.WriteString("\n\n")
+	}
+	if constraint := fileNameConstraint(filepath.Base(fh.URI().Path())); constraint != "" {
+		fmt.Fprintf(&buf, "//go:build %s\n\n", constraint)
+	}
+	fmt.Fprintf(&buf, "package %s\n", pkgName)
+
+	m := protocol.NewMapper(fh.URI(), content)
+	rng, err := m.OffsetRange(0, len(content))
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.TextEdit{{Range: rng, NewText: buf.String()}}, nil
+}
+
+// newFileHeader returns the header comment of a new file, formed from
+// the newFileHeader setting, or else copied from the copyright header
+// of the first of the sibling files that has one.
+func newFileHeader(ctx context.Context, snapshot *cache.Snapshot, siblings []protocol.DocumentURI) (string, error) {
+	if text := snapshot.Options().NewFileHeader; text != "" {
+		tmpl, err := template.New("newFileHeader").Parse(text)
+		if err != nil {
+			return "", err // validated by the settings package
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, struct{ Year int }{time.Now().Year()}); err != nil {
+			return "", err
+		}
+		var lines []string
+		for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+			lines = append(lines, strings.TrimRight("// "+line, " "))
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	for _, uri := range siblings {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return "", err
+		}
+		pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+		if err != nil {
+			return "", err
+		}
+		if c := copyrightComment(pgf.File); c != nil {
+			start, end, err := pgf.NodeOffsets(c)
+			if err != nil {
+				return "", err
+			}
+			return string(pgf.Src[start:end]), nil
+		}
+	}
+	return "", nil
+}
+
+// fileNameConstraint returns the build constraint suggested by the
+// suffix of a Go file name, such as "unix" for "file_unix.go".
+func fileNameConstraint(name string) string {
+	name = strings.TrimSuffix(name, ".go")
+	name = strings.TrimSuffix(name, "_test")
+	if i := strings.LastIndex(name, "_"); i >= 0 {
+		return fileNameConstraints[name[i+1:]]
+	}
+	return ""
+}
+
+// fileNameConstraints maps file name suffixes to the build
+// constraints they suggest. Unlike GOOS and GOARCH suffixes, which
+// imply a constraint, these suffixes have no meaning to the go command.
+var fileNameConstraints = map[string]string{
+	"unix": "unix",
+}
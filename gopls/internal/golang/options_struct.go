@@ -0,0 +1,388 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "Introduce options struct" refactoring.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/analysisinternal"
+	internalastutil "golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/tokeninternal"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// An optionsStruct describes the struct type that replaces the
+// parameters of a function in the "Introduce options struct"
+// refactoring. For example, it rewrites
+//
+//	func Dial(ctx context.Context, addr string, timeout time.Duration) error
+//
+// to
+//
+//	// DialOptions holds the parameters of [Dial].
+//	type DialOptions struct {
+//		Addr    string
+//		Timeout time.Duration
+//	}
+//
+//	func Dial(ctx context.Context, opts DialOptions) error
+//
+// A leading context.Context parameter remains a separate parameter.
+type optionsStruct struct {
+	decl     *ast.FuncDecl
+	fn       *types.Func
+	first    int          // index of the first moved parameter
+	params   []*types.Var // the moved parameters
+	fields   []string     // the corresponding field names
+	typeName string       // the name of the new struct type
+}
+
+// findOptionsStruct returns the options struct for the function
+// whose parameter list encloses the [start, end) interval, or nil if
+// there is none or the refactoring does not apply to the function.
+//
+// The refactoring applies to functions with a body and at least two
+// parameters, excluding a leading context.Context, all of which have
+// names. It does not apply to generic or variadic functions.
+func findOptionsStruct(pkg *cache.Package, pgf *parsego.File, start, end token.Pos) *optionsStruct {
+	path, _ := astutil.PathEnclosingInterval(pgf.File, start, end)
+	if len(path) < 2 {
+		return nil
+	}
+	decl, ok := path[len(path)-2].(*ast.FuncDecl)
+	if !ok || decl.Body == nil || decl.Type.TypeParams != nil {
+		return nil
+	}
+	if params := decl.Type.Params; !(params.Opening <= start && end <= params.Closing+1) {
+		return nil
+	}
+	fn, ok := pkg.TypesInfo().Defs[decl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Variadic() || sig.RecvTypeParams().Len() > 0 {
+		return nil
+	}
+	first := 0
+	if sig.Params().Len() > 0 && len(decl.Type.Params.List[0].Names) == 1 &&
+		analysisinternal.IsTypeNamed(sig.Params().At(0).Type(), "context", "Context") {
+		first = 1
+	}
+	if sig.Params().Len()-first < 2 {
+		return nil
+	}
+
+	opts := &optionsStruct{decl: decl, fn: fn, first: first}
+	seen := make(map[string]bool)
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		if param.Name() == "" || param.Name() == "_" {
+			return nil
+		}
+		if i < first {
+			continue
+		}
+		field := exportedName(param.Name())
+		if seen[field] {
+			return nil // e.g. parameters x and X
+		}
+		seen[field] = true
+		opts.params = append(opts.params, param)
+		opts.fields = append(opts.fields, field)
+	}
+
+	// The struct is named after the function, and after the
+	// receiver type too for a method; it is exported if the
+	// function is.
+	name := fn.Name()
+	if recv := sig.Recv(); recv != nil {
+		_, named := typesinternal.ReceiverNamed(recv)
+		if named == nil {
+			return nil
+		}
+		name = named.Obj().Name() + exportedName(name)
+	}
+	if fn.Exported() {
+		name = exportedName(name)
+	} else {
+		r, size := utf8.DecodeRuneInString(name)
+		name = string(unicode.ToLower(r)) + name[size:]
+	}
+	opts.typeName = name + "Options"
+	if pkg.Types().Scope().Lookup(opts.typeName) != nil {
+		return nil
+	}
+	return opts
+}
+
+// exportedName returns name with its first letter in upper case.
+func exportedName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// IntroduceOptionsStruct replaces the parameters of the function
+// declared at the specified location by a struct type, and updates
+// all calls to construct the struct from their arguments.
+//
+// Like ChangeSignature, it rewrites the calls by inlining a wrapper
+// of the new function, which preserves the evaluation order of the
+// arguments.
+func IntroduceOptionsStruct(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) ([]protocol.DocumentChange, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
+		return nil, fmt.Errorf("can't change signatures for packages with parse or type errors")
+	}
+	start, end, err := pgf.RangePos(loc.Range)
+	if err != nil {
+		return nil, err
+	}
+	opts := findOptionsStruct(pkg, pgf, start, end)
+	if opts == nil {
+		return nil, fmt.Errorf("no function parameters at selection")
+	}
+	decl := opts.decl
+	info := pkg.TypesInfo()
+	fset := tokeninternal.FileSetFor(pgf.Tok)
+
+	// Choose the name of the new parameter.
+	used := make(map[string]bool)
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	paramName := "opts"
+	for i := 1; used[paramName]; i++ {
+		paramName = fmt.Sprintf("opts%d", i)
+	}
+
+	// Declare the struct type, preserving the grouping of the
+	// parameters and the syntax of their types.
+	var typeDecl bytes.Buffer
+	fmt.Fprintf(&typeDecl, "type %s struct {\n", opts.typeName)
+	index := 0 // index of the current parameter
+	for _, field := range decl.Type.Params.List {
+		var names []string
+		for range field.Names {
+			if index >= opts.first {
+				names = append(names, opts.fields[index-opts.first])
+			}
+			index++
+		}
+		if len(names) > 0 {
+			fmt.Fprintf(&typeDecl, "\t%s %s\n", strings.Join(names, ", "), FormatNode(fset, field.Type))
+		}
+	}
+	typeDecl.WriteString("}\n")
+	typeSrc, err := format.Source(typeDecl.Bytes())
+	if err != nil {
+		return nil, bug.Errorf("formatting options struct: %v", err)
+	}
+
+	// field returns the field name of a moved parameter, or "".
+	field := func(obj types.Object) string {
+		for i, param := range opts.params {
+			if obj == param {
+				return opts.fields[i]
+			}
+		}
+		return ""
+	}
+
+	// Build the new declaration, in which each reference to a moved
+	// parameter x is replaced by opts.X.
+	newDecl := internalastutil.CloneNode(decl)
+	newParams := &ast.FieldList{}
+	index = 0
+	for _, fld := range newDecl.Type.Params.List {
+		if index >= opts.first {
+			break
+		}
+		newParams.List = append(newParams.List, fld)
+		index += len(fld.Names)
+	}
+	newParams.List = append(newParams.List, &ast.Field{
+		Names: []*ast.Ident{ast.NewIdent(paramName)},
+		Type:  ast.NewIdent(opts.typeName),
+	})
+	newDecl.Type.Params = newParams
+	var (
+		refIDs []*ast.Ident                 // references to moved parameters
+		refs   = make(map[token.Pos]string) // field name of each reference, by position
+	)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if f := field(info.Uses[id]); f != "" {
+				refIDs = append(refIDs, id)
+				refs[id.Pos()] = f
+			}
+		}
+		return true
+	})
+	newDecl.Body = astutil.Apply(newDecl.Body, nil, func(c *astutil.Cursor) bool {
+		if id, ok := c.Node().(*ast.Ident); ok {
+			if f, ok := refs[id.Pos()]; ok {
+				c.Replace(&ast.SelectorExpr{X: ast.NewIdent(paramName), Sel: ast.NewIdent(f)})
+			}
+		}
+		return true
+	}).(*ast.BlockStmt)
+
+	// Build the arguments of the call from the wrapper to the new
+	// declaration: the leading parameters, followed by a struct
+	// literal containing the moved parameters.
+	var args []ast.Expr
+	lit := &ast.CompositeLit{Type: ast.NewIdent(opts.typeName)}
+	sig := opts.fn.Type().(*types.Signature)
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		if i < opts.first {
+			args = append(args, ast.NewIdent(param.Name()))
+			continue
+		}
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent(opts.fields[i-opts.first]),
+			Value: ast.NewIdent(param.Name()),
+		})
+	}
+	args = append(args, lit)
+
+	newContent, err := rewriteCalls(ctx, signatureRewrite{
+		snapshot:   snapshot,
+		pkg:        pkg,
+		pgf:        pgf,
+		origDecl:   decl,
+		newDecl:    newDecl,
+		params:     internalastutil.CloneNode(decl.Type.Params),
+		callArgs:   args,
+		extraDecls: string(typeSrc),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Rewrite the original declaration textually, to preserve its
+	// comments. Calls within the declaration are rewritten directly,
+	// so the inlined calls there are discarded.
+	var edits []diff.Edit
+	edit := func(start, end token.Pos, new string) {
+		edits = append(edits, diff.Edit{
+			Start: int(start - decl.Type.Params.Opening),
+			End:   int(end - decl.Type.Params.Opening),
+			New:   new,
+		})
+	}
+	edit(decl.Type.Params.Opening, decl.Type.Params.Closing+1, strings.TrimPrefix(FormatNode(fset, &ast.FuncType{Params: newParams}), "func"))
+	for _, id := range refIDs {
+		edit(id.Pos(), id.End(), paramName+"."+refs[id.Pos()])
+	}
+	var callErr error
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || typeutil.StaticCallee(info, call) != opts.fn {
+			return callErr == nil
+		}
+		if call.Ellipsis.IsValid() || len(call.Args) != sig.Params().Len() {
+			callErr = fmt.Errorf("cannot rewrite recursive call %s", FormatNode(fset, call))
+			return false
+		}
+		edit(call.Args[opts.first].Pos(), call.Args[opts.first].Pos(), opts.typeName+"{")
+		for i, arg := range call.Args[opts.first:] {
+			edit(arg.Pos(), arg.Pos(), opts.fields[i]+": ")
+		}
+		last := call.Args[len(call.Args)-1]
+		edit(last.End(), last.End(), "}")
+		return true
+	})
+	if callErr != nil {
+		return nil, callErr
+	}
+	regionStart, regionEnd, err := safetoken.Offsets(pgf.Tok, decl.Type.Params.Opening, decl.Body.End())
+	if err != nil {
+		return nil, err
+	}
+	region, err := diff.Apply(string(pgf.Src[regionStart:regionEnd]), edits)
+	if err != nil {
+		return nil, bug.Errorf("rewriting declaration: %v", err)
+	}
+
+	// Splice the rewritten declaration, preceded by the struct type,
+	// into the file. Inlining does not change the order of the
+	// declarations.
+	src, ok := newContent[pgf.URI]
+	if !ok {
+		src = pgf.Src
+	}
+	file0, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, bug.Errorf("re-parsing declaring file failed: %v", err)
+	}
+	idx := findDecl(pgf.File, decl)
+	if idx < 0 {
+		return nil, bug.Errorf("didn't find original decl")
+	}
+	decl0, _ := file0.Decls[idx].(*ast.FuncDecl)
+	if decl0 == nil || decl0.Name.Name != decl.Name.Name {
+		return nil, bug.Errorf("inlining affected declaration order: found %v, not func %s", decl0, decl.Name.Name)
+	}
+	declStart := decl0.Pos()
+	if decl0.Doc != nil {
+		declStart = decl0.Doc.Pos()
+	}
+	tok0 := fset.File(decl0.Pos())
+	offsets := make([]int, 3)
+	for i, pos := range []token.Pos{declStart, decl0.Type.Params.Opening, decl0.Body.End()} {
+		offsets[i], err = safetoken.Offset(tok0, pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+	docName := decl.Name.Name
+	if recv := sig.Recv(); recv != nil {
+		_, named := typesinternal.ReceiverNamed(recv)
+		docName = named.Obj().Name() + "." + docName
+	}
+	var buf bytes.Buffer
+	buf.Write(src[:offsets[0]])
+	fmt.Fprintf(&buf, "// %s holds the parameters of [%s].\n", opts.typeName, docName)
+	buf.Write(typeSrc)
+	buf.WriteString("\n")
+	buf.Write(src[offsets[0]:offsets[1]])
+	buf.WriteString(region)
+	buf.Write(src[offsets[2]:])
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, bug.Errorf("formatting declaring file: %v", err)
+	}
+	newContent[pgf.URI] = formatted
+
+	return contentChanges(ctx, snapshot, newContent)
+}
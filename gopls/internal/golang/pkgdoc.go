@@ -15,8 +15,6 @@ package golang
 //   Or factor with golang.org/x/pkgsite/internal/godoc/dochtml.
 // - emit breadcrumbs for parent + sibling packages.
 // - list promoted methods---we have type information!
-// - gather Example tests, following go/doc and pkgsite.
-// - add option for doc.AllDecls: show non-exported symbols too.
 // - style the <li> bullets in the index as invisible.
 // - add push notifications such as didChange -> reload.
 // - there appears to be a maximum file size beyond which the
@@ -42,7 +40,10 @@ import (
 	"iter"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/gopls/internal/cache"
@@ -263,6 +264,10 @@ type Web interface {
 
 	// SrcURL forms URLs that cause the editor to open a file at a specific position.
 	SrcURL(filename string, line, col8 int) protocol.URI
+
+	// RunExampleURL forms URLs that, when fetched, run the named
+	// Example function of a package and stream the test output.
+	RunExampleURL(viewID string, path PackagePath, example string) protocol.URI
 }
 
 // PackageDocHTML formats the package documentation page.
@@ -271,13 +276,19 @@ type Web interface {
 // effect of causing gopls to direct the client editor to navigate to
 // the specified file/line/column position, in UTF-8 coordinates.
 //
+// If showUnexported is set, the page includes non-exported symbols.
+//
+// The optional testPkgs are the test variants of the package (the
+// in-package test and the external test package) whose Example
+// functions are displayed beneath the symbols they document.
+//
 // TODO(adonovan): this function could use some unit tests; we
 // shouldn't have to use integration tests to cover microdetails of
 // HTML rendering. (It is tempting to abstract this function so that
 // it depends only on FileSet/File/Types/TypeInfo/etc, but we should
 // bend the tests to the production interfaces, not the other way
 // around.)
-func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error) {
+func PackageDocHTML(viewID string, pkg *cache.Package, testPkgs []*cache.Package, showUnexported bool, web Web) ([]byte, error) {
 	// We can't use doc.NewFromFiles (even with doc.PreserveAST
 	// mode) as it calls ast.NewPackage which assumes that each
 	// ast.File has an ast.Scope and resolves identifiers to
@@ -285,8 +296,7 @@ func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error)
 	// But doc.New only requires pkg.{Name,Files},
 	// so we just boil it down.
 	//
-	// The only loss is doc.classifyExamples.
-	// TODO(adonovan): simulate that too.
+	// The only loss is doc.classifyExamples; see below.
 	fileMap := make(map[string]*ast.File)
 	for _, f := range pkg.Syntax() {
 		fileMap[pkg.FileSet().File(f.FileStart).Name()] = f
@@ -302,22 +312,23 @@ func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error)
 	mode := doc.PreserveAST | doc.AllDecls
 	docpkg := doc.New(astpkg, pkg.Types().Path(), mode)
 
-	// Discard non-exported symbols.
-	// TODO(adonovan): do this conditionally, and expose option in UI.
-	const showUnexported = false
-	if !showUnexported {
+	// Discard non-exported symbols, unless requested, and
+	// symbols that cannot be referenced, such as init and _.
+	{
 		var (
-			unexported   = func(name string) bool { return !token.IsExported(name) }
+			hidden = func(name string) bool {
+				return name == "_" || !showUnexported && !token.IsExported(name)
+			}
 			filterValues = func(slice *[]*doc.Value) {
 				delValue := func(v *doc.Value) bool {
-					v.Names = slices.DeleteFunc(v.Names, unexported)
+					v.Names = slices.DeleteFunc(v.Names, hidden)
 					return len(v.Names) == 0
 				}
 				*slice = slices.DeleteFunc(*slice, delValue)
 			}
 			filterFuncs = func(funcs *[]*doc.Func) {
 				*funcs = slices.DeleteFunc(*funcs, func(v *doc.Func) bool {
-					return unexported(v.Name)
+					return hidden(v.Name) || v.Recv == "" && v.Name == "init"
 				})
 			}
 		)
@@ -329,10 +340,22 @@ func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error)
 			filterValues(&t.Vars)
 			filterFuncs(&t.Funcs)
 			filterFuncs(&t.Methods)
-			return unexported(t.Name)
+			return hidden(t.Name)
 		})
 	}
 
+	// Gather the Example functions from the test files and
+	// associate them with the symbols they document.
+	var testFiles []*ast.File
+	for _, tpkg := range testPkgs {
+		for _, pgf := range tpkg.CompiledGoFiles() {
+			if strings.HasSuffix(pgf.URI.Path(), "_test.go") {
+				testFiles = append(testFiles, pgf.File)
+			}
+		}
+	}
+	classifyExamples(docpkg, doc.Examples(testFiles...))
+
 	// docHTML renders the doc comment as Markdown.
 	// The fileNode is used to deduce the enclosing file
 	// for the correct import mapping.
@@ -370,6 +393,11 @@ func PackageDocHTML(viewID string, pkg *cache.Package, web Web) ([]byte, error)
 	title := fmt.Sprintf("%s package - %s - Gopls packages",
 		pkg.Types().Name(), escape(pkg.Types().Path()))
 
+	checked := ""
+	if showUnexported {
+		checked = " checked"
+	}
+
 	var buf bytes.Buffer
 	buf.WriteString(`<!DOCTYPE html>
 <html>
@@ -387,6 +415,7 @@ header {
   left: 0;
   width: 100%;
   padding: 0.3em;
+  background-color: Canvas;
 }
 
 .Documentation-sinceVersion {
@@ -403,6 +432,26 @@ header {
   min-width: 25em;
   padding: 0.3em;
 }
+
+details.example { margin: 1em 0; }
+details.example > summary { cursor: pointer; color: #007d9c; }
+
+#hdr-Search {
+  margin-right: 0.3em;
+  float: right;
+  padding: 0.3em;
+}
+
+#hdr-Unexported {
+  margin-right: 0.6em;
+  float: right;
+  padding: 0.3em;
+}
+
+@media (prefers-color-scheme: dark) {
+  .lit { color: lightgreen; }
+  details.example > summary { color: rgb(86, 182, 214); }
+}
   </style>
   <script type='text/javascript'>
 window.addEventListener('load', function() {
@@ -410,11 +459,75 @@ window.addEventListener('load', function() {
 	document.getElementById('hdr-Selector').onchange = (e) => {
 		window.location.href = e.target.value;
 	};
+
+	// Hook up the symbol search box, which filters the index
+	// and, on Enter, navigates to the first remaining symbol.
+	var search = document.getElementById('hdr-Search');
+	var entries = document.querySelectorAll('#hdr-Index + ul li');
+	search.oninput = () => {
+		var query = search.value.toLowerCase();
+		for (var li of entries) {
+			li.hidden = query != "" && !li.textContent.toLowerCase().includes(query);
+		}
+		if (query != "") {
+			document.getElementById('hdr-Index').scrollIntoView();
+		}
+	};
+	search.onkeydown = (e) => {
+		if (e.key == 'Enter') {
+			for (var li of entries) {
+				if (!li.hidden) {
+					window.location.hash = li.querySelector('a').hash;
+					break;
+				}
+			}
+		}
+	};
+
+	// Hook up the unexported symbols toggle, which reloads the page.
+	var unexported = document.querySelector('#hdr-Unexported input');
+	unexported.onchange = () => {
+		var url = new URL(window.location.href);
+		if (unexported.checked) {
+			url.searchParams.set('unexported', '1');
+		} else {
+			url.searchParams.delete('unexported');
+		}
+		url.hash = '';
+		window.location.href = url.href;
+	};
 });
+
+// runExample fetches a /runexample URL, which runs an example,
+// and streams its output into the element after the button.
+async function runExample(button, url) {
+	var out = button.nextElementSibling;
+	out.hidden = false;
+	out.textContent = "";
+	button.disabled = true;
+	try {
+		var resp = await fetch(url);
+		var reader = resp.body.getReader();
+		var decoder = new TextDecoder();
+		for (;;) {
+			var {done, value} = await reader.read();
+			if (done) {
+				break;
+			}
+			out.textContent += decoder.decode(value, {stream: true});
+		}
+	} catch (e) {
+		out.textContent += e;
+	} finally {
+		button.disabled = false;
+	}
+}
   </script>
 </head>
 <body>
 <header>
+<input type='search' id='hdr-Search' placeholder='Search symbols'/>
+<label id='hdr-Unexported'><input type='checkbox'` + checked + `/> Show unexported</label>
 <select id='hdr-Selector'>
 <optgroup label="Documentation">
   <option label="Overview" value="#hdr-Overview"/>
@@ -499,23 +612,30 @@ window.addEventListener('load', function() {
 
 	// -- main element --
 
-	// nodeHTML returns HTML markup for a syntax tree.
-	// It replaces referring identifiers with links,
-	// and adds style spans for strings and comments.
-	nodeHTML := func(n ast.Node) string {
+	// spanHTML returns HTML markup for the portion [start, end) of a
+	// syntax tree, which belongs to the package or one of its test
+	// variants. It replaces referring identifiers with links, and
+	// adds style spans for strings and comments.
+	spanHTML := func(n ast.Node, start, end token.Pos) string {
 
-		// linkify returns the appropriate URL (if any) for an identifier.
-		linkify := func(id *ast.Ident) protocol.URI {
-			if obj, ok := pkg.TypesInfo().Uses[id]; ok && obj.Pkg() != nil {
+		// linkify returns the appropriate URL (if any) for an
+		// identifier in a file of package p.
+		linkify := func(p *cache.Package, id *ast.Ident) protocol.URI {
+			if obj, ok := p.TypesInfo().Uses[id]; ok && obj.Pkg() != nil {
 				// imported package name?
 				if pkgname, ok := obj.(*types.PkgName); ok {
 					// TODO(adonovan): do this for Defs of PkgName too.
 					return web.PkgURL(viewID, PackagePath(pkgname.Imported().Path()), "")
 				}
 
+				// symbol declared in a test file, e.g. by an example?
+				if p != pkg && strings.HasSuffix(safetoken.StartPosition(p.FileSet(), obj.Pos()).Filename, "_test.go") {
+					return ""
+				}
+
 				// package-level symbol?
 				if obj.Parent() == obj.Pkg().Scope() {
-					if obj.Pkg() == pkg.Types() {
+					if obj.Pkg().Path() == pkg.Types().Path() {
 						return "#" + obj.Name() // intra-package ref
 					} else {
 						return web.PkgURL(viewID, PackagePath(obj.Pkg().Path()), obj.Name())
@@ -557,9 +677,12 @@ window.addEventListener('load', function() {
 		// type decls like "type ( T1; T2 )" to make them
 		// appear as separate decls. We should too.
 		var buf bytes.Buffer
-		for _, file := range pkg.CompiledGoFiles() {
-			if goplsastutil.NodeContains(file.File, n.Pos()) {
-				pos := n.Pos()
+		for _, p := range append([]*cache.Package{pkg}, testPkgs...) {
+			for _, file := range p.CompiledGoFiles() {
+				if !goplsastutil.NodeContains(file.File, start) {
+					continue
+				}
+				pos := start
 
 				// emit emits source in the interval [pos:to] and updates pos.
 				emit := func(to token.Pos) {
@@ -568,23 +691,26 @@ window.addEventListener('load', function() {
 					if !to.IsValid() {
 						bug.Reportf("invalid Pos")
 					}
-					start, err := safetoken.Offset(file.Tok, pos)
+					from, err := safetoken.Offset(file.Tok, pos)
 					if err != nil {
 						bug.Reportf("invalid start Pos: %v", err)
 					}
-					end, err := safetoken.Offset(file.Tok, to)
+					until, err := safetoken.Offset(file.Tok, to)
 					if err != nil {
 						bug.Reportf("invalid end Pos: %v", err)
 					}
-					buf.WriteString(escape(string(file.Src[start:end])))
+					buf.WriteString(escape(string(file.Src[from:until])))
 					pos = to
 				}
 				ast.Inspect(n, func(n ast.Node) bool {
+					if n == nil || n.End() <= start || end <= n.Pos() {
+						return false // outside span
+					}
 					switch n := n.(type) {
 					case *ast.Ident:
 						emit(n.Pos())
 						pos = n.End()
-						if url := linkify(n); url != "" {
+						if url := linkify(p, n); url != "" {
 							fmt.Fprintf(&buf, "<a class='id' href='%s'>%s</a>", url, escape(n.Name))
 						} else {
 							buf.WriteString(escape(n.Name)) // plain
@@ -597,7 +723,7 @@ window.addEventListener('load', function() {
 					}
 					return true
 				})
-				emit(n.End())
+				emit(end)
 				return buf.String()
 			}
 		}
@@ -612,6 +738,11 @@ window.addEventListener('load', function() {
 		return escape(buf.String())
 	}
 
+	// nodeHTML returns HTML markup for a syntax tree.
+	nodeHTML := func(n ast.Node) string {
+		return spanHTML(n, n.Pos(), n.End())
+	}
+
 	// fnString is like fn.String() except that it:
 	// - shows the receiver name;
 	// - uses space "(T) M()" not dot "(T).M()" after receiver;
@@ -675,6 +806,44 @@ window.addEventListener('load', function() {
 		return strings.ReplaceAll(buf.String(), ", invalid type)", ", ...)")
 	}
 
+	// examples emits the Example functions that document a symbol.
+	examples := func(exs []*doc.Example) {
+		for _, ex := range exs {
+			title := "Example"
+			if ex.Suffix != "" {
+				title += " (" + ex.Suffix + ")"
+			}
+			fmt.Fprintf(&buf, "<details class='example' id='example-%s'>\n", ex.Name)
+			fmt.Fprintf(&buf, "<summary>%s</summary>\n", escape(title))
+			if ex.Doc != "" {
+				fmt.Fprintf(&buf, "<div class='comment'>%s</div>\n", docHTML(ex.Code, ex.Doc))
+			}
+
+			// code, without the braces and output comment of a function body
+			code := nodeHTML(ex.Code) // the entire file
+			if body, ok := ex.Code.(*ast.BlockStmt); ok {
+				end := body.Rbrace
+				if cg := exampleOutputCommentIn(body, ex.Comments); cg != nil {
+					end = cg.Pos()
+				}
+				code = strings.TrimSpace(spanHTML(body, body.Lbrace+1, end))
+				code = strings.ReplaceAll(code, "\n\t", "\n") // dedent
+			}
+			fmt.Fprintf(&buf, "<pre class='code'>%s</pre>\n", code)
+
+			// Only examples with an output comment are run by 'go test'.
+			if ex.Output != "" || ex.EmptyOutput {
+				fmt.Fprintf(&buf, "<div>Output:</div>\n")
+				fmt.Fprintf(&buf, "<pre class='code'>%s</pre>\n", escape(ex.Output))
+				url := web.RunExampleURL(viewID, pkg.Metadata().PkgPath, "Example"+ex.Name)
+				fmt.Fprintf(&buf, "<button onclick='runExample(this, %s)'>Run</button>\n",
+					escape(strconv.Quote(string(url))))
+				fmt.Fprintf(&buf, "<pre class='code' hidden></pre>\n")
+			}
+			fmt.Fprintf(&buf, "</details>\n")
+		}
+	}
+
 	fmt.Fprintf(&buf, "<main>\n")
 
 	// package name
@@ -694,6 +863,7 @@ window.addEventListener('load', function() {
 			break
 		}
 	}
+	examples(docpkg.Examples)
 
 	// symbol index
 	fmt.Fprintf(&buf, "<h2 id='hdr-Index'>Index</h2>\n")
@@ -734,9 +904,32 @@ window.addEventListener('load', function() {
 			fmt.Fprintf(&buf, "</ul>\n")
 		}
 	}
-	// TODO(adonovan): add index of Examples here.
 	fmt.Fprintf(&buf, "</ul>\n")
 
+	// index of examples
+	var allExamples []*doc.Example
+	allExamples = append(allExamples, docpkg.Examples...)
+	for _, fn := range docpkg.Funcs {
+		allExamples = append(allExamples, fn.Examples...)
+	}
+	for _, doctype := range docpkg.Types {
+		allExamples = append(allExamples, doctype.Examples...)
+		for _, fn := range doctype.Funcs {
+			allExamples = append(allExamples, fn.Examples...)
+		}
+		for _, method := range doctype.Methods {
+			allExamples = append(allExamples, method.Examples...)
+		}
+	}
+	if len(allExamples) > 0 {
+		fmt.Fprintf(&buf, "<h3 id='hdr-Examples'>Examples</h3>\n")
+		fmt.Fprintf(&buf, "<ul>\n")
+		for _, ex := range allExamples {
+			fmt.Fprintf(&buf, "<li><a href='#example-%s'>%s</a></li>\n", ex.Name, escape(exampleLabel(ex)))
+		}
+		fmt.Fprintf(&buf, "</ul>\n")
+	}
+
 	// constants and variables
 	values := func(vals []*doc.Value) {
 		for _, v := range vals {
@@ -793,6 +986,8 @@ window.addEventListener('load', function() {
 
 			// comment (if any)
 			fmt.Fprintf(&buf, "<div class='comment'>%s</div>\n", docHTML(docfn.Decl, docfn.Doc))
+
+			examples(docfn.Examples)
 		}
 	}
 	funcs(docpkg.Funcs)
@@ -814,6 +1009,7 @@ window.addEventListener('load', function() {
 
 		// comment (if any)
 		fmt.Fprintf(&buf, "<div class='comment'>%s</div>\n", docHTML(doctype.Decl, doctype.Doc))
+		examples(doctype.Examples)
 
 		// subelements
 		values(doctype.Consts) // constants of type T
@@ -835,6 +1031,8 @@ window.addEventListener('load', function() {
 			// comment (if any)
 			fmt.Fprintf(&buf, "<div class='comment'>%s</div>\n",
 				docHTML(docmethod.Decl, docmethod.Doc))
+
+			examples(docmethod.Examples)
 		}
 	}
 
@@ -865,3 +1063,73 @@ func tupleVariables(t *types.Tuple) iter.Seq[*types.Var] {
 		}
 	}
 }
+
+// classifyExamples associates each example with the package,
+// function, type, or method that it documents, following the
+// conventions of go/doc, whose equivalent function requires
+// [doc.NewFromFiles].
+func classifyExamples(p *doc.Package, examples []*doc.Example) {
+	// Mapping of names for funcs, types, and methods to the example listing.
+	ids := make(map[string]*[]*doc.Example)
+	ids[""] = &p.Examples // package-level examples have an empty name
+	for _, f := range p.Funcs {
+		ids[f.Name] = &f.Examples
+	}
+	for _, t := range p.Types {
+		ids[t.Name] = &t.Examples
+		for _, f := range t.Funcs {
+			ids[f.Name] = &f.Examples
+		}
+		for _, m := range t.Methods {
+			recv := m.Recv
+			if i := strings.Index(recv, "["); i >= 0 {
+				recv = recv[:i] // strip type parameters
+			}
+			ids[strings.TrimPrefix(recv, "*")+"_"+m.Name] = &m.Examples
+		}
+	}
+
+	// Group each example with the associated func, type, or method,
+	// trying each possible split point for the suffix, starting with
+	// none. Examples with malformed names that match nothing are skipped.
+	for _, ex := range examples {
+		for i := len(ex.Name); i >= 0; i = strings.LastIndexByte(ex.Name[:i], '_') {
+			prefix, suffix := ex.Name, ""
+			if i < len(ex.Name) {
+				prefix, suffix = ex.Name[:i], ex.Name[i+1:]
+				if r, _ := utf8.DecodeRuneInString(suffix); !unicode.IsLower(r) {
+					continue
+				}
+			}
+			if exs, ok := ids[prefix]; ok {
+				ex.Suffix = suffix
+				*exs = append(*exs, ex)
+				break
+			}
+		}
+	}
+
+	// Sort each list of examples by suffix.
+	for _, exs := range ids {
+		slices.SortFunc(*exs, func(x, y *doc.Example) int {
+			return strings.Compare(x.Suffix, y.Suffix)
+		})
+	}
+}
+
+// exampleLabel returns the label of an example in the index,
+// e.g. "Package", "Buffer.Grow", or "Println (multiple)".
+func exampleLabel(ex *doc.Example) string {
+	label := ex.Name
+	if ex.Suffix != "" {
+		label = strings.TrimSuffix(label, "_"+ex.Suffix)
+	}
+	label = strings.ReplaceAll(label, "_", ".")
+	if label == "" {
+		label = "Package"
+	}
+	if ex.Suffix != "" {
+		label += " (" + ex.Suffix + ")"
+	}
+	return label
+}
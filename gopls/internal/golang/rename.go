@@ -54,6 +54,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -140,6 +141,18 @@ func PrepareRename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle,
 		return item, nil, nil
 	}
 
+	// Is the cursor within an argument of a call? If so, we
+	// rename the corresponding parameter of the callee.
+	if arg, param, err := paramAtArgument(pkg.TypesInfo(), pgf.File, pos); err != nil {
+		return nil, nil, err
+	} else if param != nil {
+		rng, err := pgf.NodeRange(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &PrepareItem{Range: rng, Text: param.Name()}, nil, nil
+	}
+
 	targets, node, err := objectsAt(pkg.TypesInfo(), pgf.File, pos)
 	if err != nil {
 		return nil, nil, err
@@ -234,6 +247,57 @@ func prepareRenameFuncSignature(pgf *parsego.File, pos token.Pos) (*PrepareItem,
 	}, nil
 }
 
+// paramAtArgument returns the argument of the call that encloses pos,
+// and the corresponding parameter of the called function or method,
+// if pos is within the argument but not on an identifier (which is
+// renamed as usual). Only the innermost call is considered, and only
+// if pos lies within one of its arguments and not within a nested
+// function literal. It returns an error if the parameter cannot be
+// renamed.
+func paramAtArgument(info *types.Info, file *ast.File, pos token.Pos) (ast.Expr, *types.Var, error) {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	if len(path) == 0 {
+		return nil, nil, nil
+	}
+	if _, ok := path[0].(*ast.Ident); ok {
+		return nil, nil, nil
+	}
+	for i := 1; i < len(path); i++ {
+		var call *ast.CallExpr
+		switch n := path[i].(type) {
+		case *ast.FuncLit, ast.Stmt, ast.Decl:
+			return nil, nil, nil // don't look beyond the enclosing function or statement
+		case *ast.CallExpr:
+			call = n
+		default:
+			continue
+		}
+		argIndex := slices.IndexFunc(call.Args, func(arg ast.Expr) bool { return arg == path[i-1] })
+		if argIndex < 0 {
+			return nil, nil, nil // e.g. within the callee expression
+		}
+		arg := call.Args[argIndex]
+		fn, ok := typeutil.Callee(info, call).(*types.Func)
+		if !ok || fn.Pkg() == nil {
+			return nil, nil, nil // e.g. conversion, or call of a func value or built-in
+		}
+		fn = fn.Origin()
+		params := fn.Signature().Params()
+		if fn.Signature().Variadic() && argIndex >= params.Len()-1 {
+			argIndex = params.Len() - 1
+		}
+		if argIndex >= params.Len() {
+			return nil, nil, fmt.Errorf("cannot rename parameter: too many arguments in call to %s", fn.Name())
+		}
+		param := params.At(argIndex)
+		if param.Name() == "" || param.Name() == "_" {
+			return nil, nil, fmt.Errorf("cannot rename parameter %d of %s: it has no name", argIndex+1, fn.Name())
+		}
+		return arg, param, nil
+	}
+	return nil, nil, nil
+}
+
 // nameBlankParams returns a copy of ftype with blank or unnamed params
 // assigned a unique name.
 func nameBlankParams(ftype *ast.FuncType) *ast.FuncType {
@@ -422,7 +486,15 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, pp pro
 		return nil, false, err
 	}
 
-	// Convert edits to protocol form.
+	result, err := protocolRenameEdits(ctx, snapshot, editMap)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, inPackageName, nil
+}
+
+// protocolRenameEdits converts the edits of a renaming to protocol form.
+func protocolRenameEdits(ctx context.Context, snapshot *cache.Snapshot, editMap map[protocol.DocumentURI][]diff.Edit) (map[protocol.DocumentURI][]protocol.TextEdit, error) {
 	result := make(map[protocol.DocumentURI][]protocol.TextEdit)
 	for uri, edits := range editMap {
 		// Sort and de-duplicate edits.
@@ -454,21 +526,21 @@ func Rename(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, pp pro
 		// vendor/k8s.io/kubectl -> ../../staging/src/k8s.io/kubectl.
 		fh, err := snapshot.ReadFile(ctx, uri)
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		data, err := fh.Content()
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		m := protocol.NewMapper(uri, data)
 		textedits, err := protocol.EditsFromDiffEdits(m, edits)
 		if err != nil {
-			return nil, false, err
+			return nil, err
 		}
 		result[uri] = textedits
 	}
 
-	return result, inPackageName, nil
+	return result, nil
 }
 
 // renameOrdinary renames an ordinary (non-package) name throughout the workspace.
@@ -505,6 +577,23 @@ func renameOrdinary(ctx context.Context, snapshot *cache.Snapshot, f file.Handle
 		if err != nil {
 			return nil, err
 		}
+
+		// Within an argument of a call? Rename the corresponding
+		// parameter, starting from its declaration.
+		if _, param, err := paramAtArgument(pkg.TypesInfo(), pgf.File, pos); err != nil {
+			return nil, err
+		} else if param != nil {
+			loc, err := mapPosition(ctx, pkg.FileSet(), snapshot, param.Pos(), param.Pos())
+			if err != nil {
+				return nil, err
+			}
+			declFH, err := snapshot.ReadFile(ctx, loc.URI)
+			if err != nil {
+				return nil, err
+			}
+			return renameOrdinary(ctx, snapshot, declFH, loc.Range.Start, newName)
+		}
+
 		objects, _, err := objectsAt(pkg.TypesInfo(), pgf.File, pos)
 		if err != nil {
 			return nil, err
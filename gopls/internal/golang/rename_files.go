@@ -0,0 +1,224 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the edits that accompany the renaming of files and
+// directories by the client (workspace/willRenameFiles).
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/event"
+)
+
+// RenameFile returns the edits that should be applied before the file
+// or directory oldURI is renamed to newURI, so that the workspace
+// remains consistent after the renaming. It also returns the renamings
+// of other files that should accompany it.
+//
+// Renaming a package directory (or one of its ancestors) updates the
+// import paths of the packages within it throughout the workspace.
+// If the last segment of the directory name of a package changes, and
+// the package was named after it, the package is renamed too.
+//
+// Moving a Go file to the directory of another package updates its
+// package clause. Renaming a Go file also renames its test file, if
+// any: for example, renaming a.go to b.go renames a_test.go to
+// b_test.go.
+func RenameFile(ctx context.Context, snapshot *cache.Snapshot, oldURI, newURI protocol.DocumentURI) (map[protocol.DocumentURI][]protocol.TextEdit, []protocol.FileRename, error) {
+	ctx, done := event.Start(ctx, "golang.RenameFile")
+	defer done()
+
+	edits := make(map[protocol.DocumentURI][]diff.Edit)
+	var renames []protocol.FileRename
+	if filepath.Ext(oldURI.Path()) == ".go" {
+		if err := moveGoFile(ctx, snapshot, oldURI, newURI, edits); err != nil {
+			return nil, nil, err
+		}
+		if oldTest, newTest, ok := testFileRenaming(ctx, snapshot, oldURI, newURI); ok {
+			if err := moveGoFile(ctx, snapshot, oldTest, newTest, edits); err != nil {
+				return nil, nil, err
+			}
+			renames = append(renames, protocol.FileRename{
+				OldURI: string(oldTest),
+				NewURI: string(newTest),
+			})
+		}
+	} else {
+		if err := moveDirectory(ctx, snapshot, oldURI, newURI, edits); err != nil {
+			return nil, nil, err
+		}
+	}
+	result, err := protocolRenameEdits(ctx, snapshot, edits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, renames, nil
+}
+
+// moveDirectory computes the edits required by the renaming of the
+// directory oldDir to newDir, which may contain packages at any depth.
+func moveDirectory(ctx context.Context, snapshot *cache.Snapshot, oldDir, newDir protocol.DocumentURI, edits map[protocol.DocumentURI][]diff.Edit) error {
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	oldBase := filepath.Base(oldDir.Path())
+	newBase := filepath.Base(newDir.Path())
+	for _, mp := range allMetadata {
+		if mp.IsIntermediateTestVariant() || metadata.IsCommandLineArguments(mp.ID) {
+			continue
+		}
+		pkgDir := packageDir(mp)
+		if pkgDir == "" || !oldDir.Encloses(pkgDir) {
+			continue // not affected by the directory renaming
+		}
+		if mp.Module == nil {
+			continue // e.g. GOPATH mode
+		}
+		rel, err := filepath.Rel(oldDir.Path(), pkgDir.Path())
+		if err != nil {
+			return err
+		}
+		newPath, ok := modulePackagePath(mp.Module.Path, mp.Module.Dir, filepath.Join(newDir.Path(), rel))
+		if !ok {
+			continue // moved out of its module
+		}
+
+		// Rename the package if it is named after its directory.
+		name := mp.Name
+		if pkgDir == oldDir && isValidIdentifier(newBase) {
+			switch string(mp.Name) {
+			case oldBase:
+				name = PackageName(newBase)
+			case oldBase + "_test":
+				name = PackageName(newBase + "_test")
+			}
+		}
+		if name != mp.Name {
+			if err := renamePackageClause(ctx, mp, snapshot, name, edits); err != nil {
+				return err
+			}
+		}
+
+		isXTest := mp.ForTest != "" && mp.PkgPath != mp.ForTest
+		if !isXTest { // x_test packages cannot be imported
+			if err := renameImports(ctx, snapshot, mp, ImportPath(newPath), name, edits); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// moveGoFile computes the edits required by the renaming of the Go
+// file oldURI to newURI: if it moves to the directory of another
+// package, its package clause must change to match.
+func moveGoFile(ctx context.Context, snapshot *cache.Snapshot, oldURI, newURI protocol.DocumentURI, edits map[protocol.DocumentURI][]diff.Edit) error {
+	if oldURI.Dir() == newURI.Dir() {
+		return nil
+	}
+	allMetadata, err := snapshot.AllMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	var name PackageName
+	for _, mp := range allMetadata {
+		if mp.ForTest == "" && !metadata.IsCommandLineArguments(mp.ID) && packageDir(mp) == newURI.Dir() {
+			name = mp.Name
+			break
+		}
+	}
+	if name == "" {
+		return nil // no package in the destination directory
+	}
+
+	fh, err := snapshot.ReadFile(ctx, oldURI)
+	if err != nil {
+		return err
+	}
+	pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+	if err != nil {
+		return err
+	}
+	if pgf.File.Name == nil {
+		return nil // no package clause
+	}
+	if strings.HasSuffix(pgf.File.Name.Name, "_test") && strings.HasSuffix(newURI.Path(), "_test.go") {
+		name += "_test" // an external test file remains one
+	}
+	if string(name) == pgf.File.Name.Name {
+		return nil
+	}
+	edit, err := posEdit(pgf.Tok, pgf.File.Name.Pos(), pgf.File.Name.End(), string(name))
+	if err != nil {
+		return err
+	}
+	edits[oldURI] = append(edits[oldURI], edit)
+	return nil
+}
+
+// testFileRenaming returns the renaming of the test file of the
+// non-test Go file oldURI that accompanies its renaming to newURI,
+// if the test file exists and its new name is not already taken.
+func testFileRenaming(ctx context.Context, snapshot *cache.Snapshot, oldURI, newURI protocol.DocumentURI) (oldTest, newTest protocol.DocumentURI, ok bool) {
+	testName := func(uri protocol.DocumentURI) (protocol.DocumentURI, bool) {
+		stem, ok := strings.CutSuffix(uri.Path(), ".go")
+		if !ok || strings.HasSuffix(stem, "_test") {
+			return "", false
+		}
+		return protocol.URIFromPath(stem + "_test.go"), true
+	}
+	oldTest, ok1 := testName(oldURI)
+	newTest, ok2 := testName(newURI)
+	if !ok1 || !ok2 || oldTest == newTest {
+		return "", "", false
+	}
+	if !fileExists(ctx, snapshot, oldTest) || fileExists(ctx, snapshot, newTest) {
+		return "", "", false
+	}
+	return oldTest, newTest, true
+}
+
+// fileExists reports whether the file exists, or is open in an editor.
+func fileExists(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI) bool {
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return false
+	}
+	_, err = fh.Content()
+	return err == nil
+}
+
+// packageDir returns the directory of the package, or "" if it has no
+// Go files.
+func packageDir(mp *metadata.Package) protocol.DocumentURI {
+	if len(mp.GoFiles) == 0 {
+		return ""
+	}
+	return mp.GoFiles[0].Dir()
+}
+
+// modulePackagePath returns the path of the package in directory dir
+// of the module with the specified path and root directory. It
+// returns false if dir is not within the module.
+func modulePackagePath(modPath, modDir, dir string) (PackagePath, bool) {
+	if modDir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(modDir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return PackagePath(path.Join(modPath, filepath.ToSlash(rel))), true
+}
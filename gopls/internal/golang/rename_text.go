@@ -0,0 +1,203 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the optional renaming of the textual occurrences
+// of a name in comments and string literals.
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// RenameInCommentsAndStrings returns the edits that replace the old
+// name of the object at the specified position, as a whole word, in
+// the comments and string literals of the package that declares it.
+// It excludes the occurrences already updated by the edits of the
+// corresponding call to [Rename], such as doc links.
+//
+// Only package-level objects, fields, and methods are considered:
+// the names of local variables are too likely to appear by chance.
+// Import paths and struct field tags are never updated.
+func RenameInCommentsAndStrings(ctx context.Context, snapshot *cache.Snapshot, f file.Handle, pp protocol.Position, newName string, renamed map[protocol.DocumentURI][]protocol.TextEdit) (map[protocol.DocumentURI][]protocol.TextEdit, error) {
+	if !isValidIdentifier(newName) {
+		return nil, nil // e.g. a signature rewrite
+	}
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, f.URI())
+	if err != nil {
+		return nil, err
+	}
+	pos, err := pgf.PositionPos(pp)
+	if err != nil {
+		return nil, err
+	}
+	objects, _, err := objectsAt(pkg.TypesInfo(), pgf.File, pos)
+	if err != nil {
+		return nil, nil // e.g. a package name
+	}
+	var obj types.Object
+	for obj = range objects {
+		break
+	}
+	if !isTextRenamable(obj) {
+		return nil, nil
+	}
+
+	// Find the widest variant of the declaring package.
+	loc, err := mapPosition(ctx, pkg.FileSet(), snapshot, obj.Pos(), obj.Pos())
+	if err != nil {
+		return nil, err
+	}
+	mps, err := snapshot.MetadataForFile(ctx, loc.URI)
+	if err != nil {
+		return nil, err
+	}
+	metadata.RemoveIntermediateTestVariants(&mps)
+	if len(mps) == 0 {
+		return nil, fmt.Errorf("no package metadata for file %s", loc.URI)
+	}
+	pkgs, err := snapshot.TypeCheck(ctx, mps[len(mps)-1].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[protocol.DocumentURI][]protocol.TextEdit)
+	for _, pgf := range pkgs[0].CompiledGoFiles() {
+		edits, err := textOccurrences(pgf, obj.Name(), newName, renamed[pgf.URI])
+		if err != nil {
+			return nil, err
+		}
+		if len(edits) > 0 {
+			result[pgf.URI] = edits
+		}
+	}
+	return result, nil
+}
+
+// isTextRenamable reports whether the textual occurrences of the
+// name of obj may be renamed along with it.
+func isTextRenamable(obj types.Object) bool {
+	switch obj := obj.(type) {
+	case *types.Var:
+		return obj.IsField() || typesinternal.IsPackageLevel(obj)
+	case *types.Func:
+		return true // a package-level function or a method
+	case *types.TypeName:
+		_, isTypeParam := types.Unalias(obj.Type()).(*types.TypeParam)
+		return !isTypeParam && typesinternal.IsPackageLevel(obj)
+	case *types.Const:
+		return typesinternal.IsPackageLevel(obj)
+	}
+	return false
+}
+
+// textOccurrences returns the edits that replace the whole-word
+// occurrences of oldName in the comments and string literals of the
+// file, other than those that overlap an edit in renamed.
+func textOccurrences(pgf *parsego.File, oldName, newName string, renamed []protocol.TextEdit) ([]protocol.TextEdit, error) {
+	var edits []protocol.TextEdit
+	add := func(start, end token.Pos) error {
+		startOffset, endOffset, err := safetoken.Offsets(pgf.Tok, start, end)
+		if err != nil {
+			return err
+		}
+		text := string(pgf.Src[startOffset:endOffset])
+		for _, offset := range wordOffsets(text, oldName) {
+			rng, err := pgf.Mapper.OffsetRange(startOffset+offset, startOffset+offset+len(oldName))
+			if err != nil {
+				return err
+			}
+			if !overlapsEdit(rng, renamed) {
+				edits = append(edits, protocol.TextEdit{Range: rng, NewText: newName})
+			}
+		}
+		return nil
+	}
+
+	for _, cg := range pgf.File.Comments {
+		for _, comment := range cg.List {
+			if isDirective(comment.Text) {
+				continue
+			}
+			if err := add(comment.Pos(), comment.End()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var (
+		tags = make(map[*ast.BasicLit]bool) // struct field tags
+		err  error
+	)
+	ast.Inspect(pgf.File, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false // import paths are not names
+		case *ast.Field:
+			// Renaming a field must not change its encoding.
+			if n.Tag != nil {
+				tags[n.Tag] = true
+			}
+		case *ast.BasicLit:
+			if n.Kind == token.STRING && !tags[n] {
+				err = add(n.Pos(), n.End())
+			}
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// wordOffsets returns the offsets of the occurrences of word in text
+// that are not part of a longer identifier.
+func wordOffsets(text, word string) []int {
+	var offsets []int
+	for i := 0; ; {
+		j := strings.Index(text[i:], word)
+		if j < 0 {
+			return offsets
+		}
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isIdentRune(before) && !isIdentRune(after) {
+			offsets = append(offsets, start)
+		}
+		i = end
+	}
+}
+
+// isIdentRune reports whether r may appear in an identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// overlapsEdit reports whether rng overlaps the range of any of the edits.
+func overlapsEdit(rng protocol.Range, edits []protocol.TextEdit) bool {
+	for _, edit := range edits {
+		if protocol.ComparePosition(rng.Start, edit.Range.End) < 0 &&
+			protocol.ComparePosition(edit.Range.Start, rng.End) < 0 {
+			return true
+		}
+	}
+	return false
+}
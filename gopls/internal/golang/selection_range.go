@@ -0,0 +1,163 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// LexicalSelectionRanges returns the ranges, innermost first, of the
+// lexical structure around pos that the syntax tree does not
+// represent, for use by textDocument/selectionRange:
+//   - within a comment: the word, the text of the comment, the
+//     comment, and its comment group;
+//   - within an import path: the path segment and the path;
+//   - within a struct tag: the key or the comma-separated element
+//     of the value, the value, the key:"value" pair, and the tag; and
+//   - within any other string literal: the word and the string.
+//
+// The ranges of a string literal exclude its quotation marks; the
+// literal itself belongs to path, the syntax path enclosing pos.
+func LexicalSelectionRanges(pgf *parsego.File, path []ast.Node, pos token.Pos) ([]protocol.Range, error) {
+	var spans []posRange
+	add := func(start, end token.Pos) {
+		if start < end && start <= pos && pos <= end &&
+			(len(spans) == 0 || spans[len(spans)-1] != posRange{start, end}) {
+			spans = append(spans, posRange{start, end})
+		}
+	}
+
+	if c, group := commentAt(pgf.File, pos); c != nil {
+		base := c.Pos()
+		if start, end, ok := wordAt(c.Text, int(pos-base)); ok {
+			add(base+token.Pos(start), base+token.Pos(end))
+		}
+		// The text, excluding the comment markers and surrounding space.
+		start, end := 2, len(c.Text)
+		if strings.HasPrefix(c.Text, "/*") {
+			end -= len("*/")
+		}
+		for start < end && isSpace(c.Text[start]) {
+			start++
+		}
+		for end > start && isSpace(c.Text[end-1]) {
+			end--
+		}
+		add(base+token.Pos(start), base+token.Pos(end))
+		add(c.Pos(), c.End())
+		add(group.Pos(), group.End())
+
+	} else if len(path) > 0 {
+		if lit, ok := path[0].(*ast.BasicLit); ok && lit.Kind == token.STRING && len(lit.Value) >= 2 {
+			content := lit.Value[1 : len(lit.Value)-1]
+			base := lit.Pos() + 1 // position of content[0]
+			offset := min(max(int(pos-base), 0), len(content))
+
+			var parent ast.Node
+			if len(path) > 1 {
+				parent = path[1]
+			}
+			switch parent := parent.(type) {
+			case *ast.ImportSpec:
+				start := strings.LastIndexByte(content[:offset], '/') + 1
+				end := len(content)
+				if i := strings.IndexByte(content[offset:], '/'); i >= 0 {
+					end = offset + i
+				}
+				add(base+token.Pos(start), base+token.Pos(end))
+
+			case *ast.Field:
+				if parent.Tag != lit {
+					break
+				}
+				for _, pair := range scanStructTag(lit) {
+					key, value := pair.key.rng, pair.value.rng
+					add(key.start, key.end)
+					// The comma-separated element of the value, such as "omitempty".
+					if value.start <= pos && pos <= value.end {
+						elems := pair.value.name
+						i := int(pos - value.start)
+						start := strings.LastIndexByte(elems[:i], ',') + 1
+						end := len(elems)
+						if j := strings.IndexByte(elems[i:], ','); j >= 0 {
+							end = i + j
+						}
+						add(value.start+token.Pos(start), value.start+token.Pos(end))
+					}
+					add(value.start, value.end)
+					add(key.start, value.end+1) // include closing quote
+				}
+
+			default:
+				if start, end, ok := wordAt(content, offset); ok {
+					add(base+token.Pos(start), base+token.Pos(end))
+				}
+			}
+			add(base, base+token.Pos(len(content)))
+		}
+	}
+
+	var ranges []protocol.Range
+	for _, span := range spans {
+		rng, err := pgf.PosRange(span.start, span.end)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, rng)
+	}
+	return ranges, nil
+}
+
+// commentAt returns the comment containing pos, and its group,
+// or nil if there is none.
+func commentAt(file *ast.File, pos token.Pos) (*ast.Comment, *ast.CommentGroup) {
+	for _, group := range file.Comments {
+		if group.Pos() > pos {
+			break
+		}
+		for _, c := range group.List {
+			if c.Pos() <= pos && pos <= c.End() {
+				return c, group
+			}
+		}
+	}
+	return nil, nil
+}
+
+// wordAt returns the byte offsets of the word (a sequence of letters,
+// digits, and underscores) of text that contains or ends at offset.
+func wordAt(text string, offset int) (start, end int, ok bool) {
+	isWord := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	start = offset
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if !isWord(r) {
+			break
+		}
+		start -= size
+	}
+	end = offset
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !isWord(r) {
+			break
+		}
+		end += size
+	}
+	return start, end, start < end
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
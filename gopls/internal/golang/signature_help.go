@@ -19,6 +19,7 @@ import (
 	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/typeparams"
 	"golang.org/x/tools/internal/typesinternal"
 )
 
@@ -134,6 +135,13 @@ loop:
 		return nil, 0, bug.Errorf("call to unexpected built-in %v (%T)", obj, obj)
 	}
 
+	// If the type arguments of a call to a generic function could not
+	// all be inferred (for example, because the call is incomplete),
+	// show the parameter types instantiated with those that can.
+	if callExpr != nil {
+		sig = inferSignature(info, sig, callExpr)
+	}
+
 	activeParam := 0
 	if callExpr != nil {
 		// only return activeParam when CallExpr
@@ -171,6 +179,169 @@ loop:
 	}, activeParam, nil
 }
 
+// inferSignature returns the signature sig of a call to a generic
+// function, instantiated with the type arguments that can be inferred
+// from the types of the arguments of the call, which may be incomplete.
+// Type parameters that cannot be inferred are left as is.
+// If sig is not generic, or no type arguments can be inferred,
+// inferSignature returns sig.
+//
+// Like the type checker, it first unifies the parameter types with the
+// types of typed arguments, then with the default types of untyped
+// constant arguments, and then infers type parameters from the core
+// types of the constraints of those already inferred, as for
+// [S ~[]E, E any]. Unlike the type checker, it does not report
+// inconsistencies: the first inferred type argument wins.
+func inferSignature(info *types.Info, sig *types.Signature, call *ast.CallExpr) *types.Signature {
+	tparams := sig.TypeParams()
+	if tparams.Len() == 0 {
+		return sig
+	}
+	targs := make(map[*types.TypeParam]types.Type)
+	for i := 0; i < tparams.Len(); i++ {
+		targs[tparams.At(i)] = nil
+	}
+	params := sig.Params()
+	for _, untyped := range []bool{false, true} {
+		for i, arg := range call.Args {
+			// Find the type of the corresponding parameter.
+			var ptype types.Type
+			switch {
+			case i < params.Len()-1 || i == params.Len()-1 && (!sig.Variadic() || call.Ellipsis.IsValid()):
+				ptype = params.At(i).Type()
+			case sig.Variadic():
+				ptype = params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+			default:
+				continue // too many arguments
+			}
+			tv, ok := info.Types[arg]
+			if !ok || tv.Type == nil || tv.Type == types.Typ[types.Invalid] || tv.IsNil() {
+				continue
+			}
+			if basic, ok := tv.Type.(*types.Basic); (ok && basic.Info()&types.IsUntyped != 0) != untyped {
+				continue
+			}
+			if untyped {
+				// An untyped constant determines only a type
+				// parameter that is the parameter type itself.
+				if tparam, ok := ptype.(*types.TypeParam); ok {
+					if targ, ok := targs[tparam]; ok && targ == nil {
+						targs[tparam] = types.Default(tv.Type)
+					}
+				}
+				continue
+			}
+			unifyTypes(ptype, tv.Type, targs)
+		}
+	}
+
+	// Infer type parameters from the core types of
+	// the constraints of inferred type parameters.
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < tparams.Len(); i++ {
+			tparam := tparams.At(i)
+			targ := targs[tparam]
+			if targ == nil {
+				continue
+			}
+			if core := typeparams.CoreType(tparam); core != nil && !is[*types.Interface](core) {
+				n := countInferred(targs)
+				unifyTypes(core, targ.Underlying(), targs)
+				changed = changed || countInferred(targs) > n
+			}
+		}
+	}
+
+	if countInferred(targs) == 0 {
+		return sig
+	}
+	args := make([]types.Type, tparams.Len())
+	for i := range args {
+		tparam := tparams.At(i)
+		if args[i] = targs[tparam]; args[i] == nil {
+			args[i] = tparam
+		}
+	}
+	inst, err := types.Instantiate(nil, sig, args, false)
+	if err != nil {
+		return sig
+	}
+	return inst.(*types.Signature)
+}
+
+// unifyTypes records in targs the types to which the type parameters
+// (keys of targs) that occur in the parameter type x correspond in the
+// argument type y, by a structural comparison of x and y. Only type
+// parameters not already inferred (nil values of targs) are recorded.
+func unifyTypes(x, y types.Type, targs map[*types.TypeParam]types.Type) {
+	if tparam, ok := x.(*types.TypeParam); ok {
+		if targ, ok := targs[tparam]; ok && targ == nil {
+			targs[tparam] = y
+		}
+		return
+	}
+	// As in inexact unification, a named argument type
+	// matches an unnamed parameter type by its underlying type.
+	if _, ok := x.(*types.Named); !ok {
+		y = y.Underlying()
+	}
+	switch x := x.(type) {
+	case *types.Named:
+		if y, ok := y.(*types.Named); ok && x.Origin() == y.Origin() {
+			xargs, yargs := x.TypeArgs(), y.TypeArgs()
+			for i := 0; i < xargs.Len() && i < yargs.Len(); i++ {
+				unifyTypes(xargs.At(i), yargs.At(i), targs)
+			}
+		}
+	case *types.Pointer:
+		if y, ok := y.(*types.Pointer); ok {
+			unifyTypes(x.Elem(), y.Elem(), targs)
+		}
+	case *types.Slice:
+		if y, ok := y.(*types.Slice); ok {
+			unifyTypes(x.Elem(), y.Elem(), targs)
+		}
+	case *types.Array:
+		if y, ok := y.(*types.Array); ok {
+			unifyTypes(x.Elem(), y.Elem(), targs)
+		}
+	case *types.Map:
+		if y, ok := y.(*types.Map); ok {
+			unifyTypes(x.Key(), y.Key(), targs)
+			unifyTypes(x.Elem(), y.Elem(), targs)
+		}
+	case *types.Chan:
+		if y, ok := y.(*types.Chan); ok {
+			unifyTypes(x.Elem(), y.Elem(), targs)
+		}
+	case *types.Signature:
+		if y, ok := y.(*types.Signature); ok && y.TypeParams().Len() == 0 {
+			unifyTuples(x.Params(), y.Params(), targs)
+			unifyTuples(x.Results(), y.Results(), targs)
+		}
+	}
+}
+
+func unifyTuples(x, y *types.Tuple, targs map[*types.TypeParam]types.Type) {
+	if x.Len() == y.Len() {
+		for i := 0; i < x.Len(); i++ {
+			unifyTypes(x.At(i).Type(), y.At(i).Type(), targs)
+		}
+	}
+}
+
+// countInferred returns the number of inferred type arguments in targs.
+func countInferred(targs map[*types.TypeParam]types.Type) int {
+	n := 0
+	for _, targ := range targs {
+		if targ != nil {
+			n++
+		}
+	}
+	return n
+}
+
 // Note: callExpr may be nil when signatureHelp is invoked outside the call
 // argument list (golang/go#69552).
 func builtinSignature(ctx context.Context, snapshot *cache.Snapshot, callExpr *ast.CallExpr, name string, pos token.Pos) (*protocol.SignatureInformation, int, error) {
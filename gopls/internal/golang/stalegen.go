@@ -0,0 +1,266 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+)
+
+// StaleGeneratedFiles returns diagnostics for the generated Go files
+// in the specified directory that are older, by modification time,
+// than the files from which they were generated.
+//
+// The inputs of a generated file are:
+//   - each file in the directory that contains a //go:generate
+//     directive whose generator, such as "stringer", is named by the
+//     "Code generated" comment of the generated file; and
+//   - the files matching the globs of each rule of the
+//     generatedFileInputs setting whose pattern matches the base name
+//     of the generated file.
+//
+// Only the state of files on disk is considered.
+func StaleGeneratedFiles(ctx context.Context, snapshot *cache.Snapshot, dir protocol.DocumentURI) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	entries, err := os.ReadDir(dir.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	type generated struct {
+		pgf     *parsego.File
+		comment *ast.Comment // "Code generated ... DO NOT EDIT." comment
+	}
+	var (
+		outputs    []generated
+		generators = make(map[string][]string) // generator name -> files declaring it
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		uri := protocol.URIFromPath(filepath.Join(dir.Path(), entry.Name()))
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		pgf, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+		if err != nil {
+			return nil, err
+		}
+		if comment := generatedComment(pgf); comment != nil {
+			outputs = append(outputs, generated{pgf, comment})
+			continue
+		}
+		for _, group := range pgf.File.Comments {
+			for _, c := range group.List {
+				if name := generatorName(c.Text); name != "" {
+					generators[name] = append(generators[name], entry.Name())
+				}
+			}
+		}
+	}
+
+	rules := snapshot.Options().GeneratedFileInputs
+	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
+	for _, out := range outputs {
+		outPath := out.pgf.URI.Path()
+		outInfo, err := os.Stat(outPath)
+		if err != nil {
+			continue // not on disk
+		}
+
+		// Gather the inputs of this file.
+		var inputs []string
+		words := commentWords(out.comment)
+		for name, files := range generators {
+			if slices.Contains(words, name) {
+				for _, f := range files {
+					inputs = append(inputs, filepath.Join(dir.Path(), f))
+				}
+			}
+		}
+		base := filepath.Base(outPath)
+		for pattern, globs := range rules {
+			if ok, _ := path.Match(pattern, base); !ok {
+				continue
+			}
+			for _, glob := range globs {
+				matches, _ := filepath.Glob(filepath.Join(dir.Path(), filepath.FromSlash(glob)))
+				inputs = append(inputs, matches...)
+			}
+		}
+
+		// Find the most recently modified input.
+		var (
+			newest     string
+			newestTime time.Time
+		)
+		for _, input := range inputs {
+			if input == outPath {
+				continue
+			}
+			if info, err := os.Stat(input); err == nil && info.ModTime().After(newestTime) {
+				newest, newestTime = input, info.ModTime()
+			}
+		}
+		if newest == "" || !newestTime.After(outInfo.ModTime()) {
+			continue
+		}
+
+		rng, err := out.pgf.NodeRange(out.comment)
+		if err != nil {
+			return nil, err
+		}
+		cmd := command.NewGenerateCommand("Run go generate", command.GenerateArgs{Dir: dir})
+		reports[out.pgf.URI] = append(reports[out.pgf.URI], &cache.Diagnostic{
+			URI:            out.pgf.URI,
+			Range:          rng,
+			Severity:       protocol.SeverityWarning,
+			Source:         cache.StaleGeneratedFile,
+			Message:        fmt.Sprintf("%s may be stale: %s was modified after it was generated", base, filepath.Base(newest)),
+			SuggestedFixes: []cache.SuggestedFix{cache.SuggestedFixFromCommand(cmd, protocol.QuickFix)},
+		})
+	}
+	return reports, nil
+}
+
+// generatedComment returns the "generated file" comment of pgf (see
+// [IsGenerated]) that precedes its package clause, or nil if it has
+// none.
+func generatedComment(pgf *parsego.File) *ast.Comment {
+	for _, group := range pgf.File.Comments {
+		if group.Pos() > pgf.File.Package {
+			break // must precede the package clause
+		}
+		for _, c := range group.List {
+			if generatedRx.MatchString(c.Text) && safetoken.Position(pgf.Tok, c.Slash).Column == 1 {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// commentWords returns the lower-case words of a comment, such as
+// "code", "generated", "by" and "stringer" for "// Code generated by
+// stringer; DO NOT EDIT.".
+func commentWords(c *ast.Comment) []string {
+	return strings.FieldsFunc(strings.ToLower(c.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+}
+
+// generatorDirective returns the location of the first //go:generate
+// directive, in the directory of the generated file pgf, whose
+// generator is named by the "Code generated" comment of the file. It
+// reports false if there is none.
+func generatorDirective(ctx context.Context, snapshot *cache.Snapshot, pgf *parsego.File, comment *ast.Comment) (protocol.Location, bool, error) {
+	words := commentWords(comment)
+	dir := pgf.URI.DirPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return protocol.Location{}, false, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(filepath.Join(dir, entry.Name())))
+		if err != nil {
+			return protocol.Location{}, false, err
+		}
+		other, err := snapshot.ParseGo(ctx, fh, parsego.Full)
+		if err != nil {
+			return protocol.Location{}, false, err
+		}
+		for _, group := range other.File.Comments {
+			for _, c := range group.List {
+				if name := generatorName(c.Text); name != "" && slices.Contains(words, name) {
+					loc, err := other.NodeLocation(c)
+					return loc, err == nil, err
+				}
+			}
+		}
+	}
+	return protocol.Location{}, false, nil
+}
+
+// EditedGeneratedFiles returns an informational diagnostic for each
+// generated Go file that is open in the editor with unsaved edits,
+// since the edits will be lost when the file is regenerated.
+func EditedGeneratedFiles(ctx context.Context, snapshot *cache.Snapshot) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	reports := make(map[protocol.DocumentURI][]*cache.Diagnostic)
+	for _, o := range snapshot.Overlays() {
+		if o.SameContentsOnDisk() || snapshot.FileKind(o) != file.Go {
+			continue
+		}
+		pgf, err := snapshot.ParseGo(ctx, o, parsego.Header)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		comment := generatedComment(pgf)
+		if comment == nil {
+			continue
+		}
+		rng, err := pgf.NodeRange(comment)
+		if err != nil {
+			return nil, err
+		}
+		reports[pgf.URI] = append(reports[pgf.URI], &cache.Diagnostic{
+			URI:      pgf.URI,
+			Range:    rng,
+			Severity: protocol.SeverityInformation,
+			Source:   cache.EditedGeneratedFile,
+			Message:  fmt.Sprintf("%s is a generated file: edits will be lost when it is regenerated", filepath.Base(pgf.URI.Path())),
+		})
+	}
+	return reports, nil
+}
+
+// generatorName returns the lower-case name of the program run by a
+// //go:generate directive, such as "stringer" for
+// "//go:generate go run golang.org/x/tools/cmd/stringer@latest -type=T",
+// or "" if the comment is not such a directive.
+func generatorName(comment string) string {
+	rest, ok := strings.CutPrefix(comment, "//go:generate ")
+	if !ok {
+		return ""
+	}
+	args := strings.Fields(rest)
+	if len(args) >= 2 && args[0] == "go" && args[1] == "run" {
+		// Skip flags of "go run".
+		args = args[2:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			args = args[1:]
+		}
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	name, _, _ := strings.Cut(args[0], "@")
+	name = strings.TrimSuffix(path.Base(filepath.ToSlash(name)), ".go")
+	if name == "." || name == "" {
+		return ""
+	}
+	return strings.ToLower(name)
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import "testing"
+
+func TestGeneratorName(t *testing.T) {
+	for _, test := range []struct {
+		comment, want string
+	}{
+		{"//go:generate stringer -type=Color", "stringer"},
+		{"//go:generate go run golang.org/x/tools/cmd/stringer@latest -type=Color", "stringer"},
+		{"//go:generate go run -mod=mod github.com/golang/mock/mockgen -source=x.go", "mockgen"},
+		{"//go:generate go run ./gen.go", "gen"},
+		{"//go:generate go run .", ""},
+		{"//go:generate", ""},
+		{"// go:generate stringer", ""},
+		{"// A comment.", ""},
+	} {
+		if got := generatorName(test.comment); got != test.want {
+			t.Errorf("generatorName(%q) = %q, want %q", test.comment, got, test.want)
+		}
+	}
+}
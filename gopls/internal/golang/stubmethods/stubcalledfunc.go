@@ -60,6 +60,12 @@ func GetCallStubInfo(fset *token.FileSet, info *types.Info, path []ast.Node, pos
 				return nil
 			}
 
+			// An unexported method of a type in another package
+			// would not be accessible to the call.
+			if !token.IsExported(s.Sel.Name) && recv.Pkg() != callerPackage(info, path[i:]) {
+				return nil
+			}
+
 			after := types.Object(recv)
 			// If the enclosing function declaration is a method declaration,
 			// and matches the receiver type of the diagnostic,
@@ -88,6 +94,26 @@ func GetCallStubInfo(fset *token.FileSet, info *types.Info, path []ast.Node, pos
 	return nil
 }
 
+// callerPackage returns the package containing the declaration
+// enclosing the syntax node denoted by path, or nil if unknown.
+func callerPackage(info *types.Info, path []ast.Node) *types.Package {
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if obj := info.Defs[n.Name]; obj != nil {
+				return obj.Pkg()
+			}
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				if obj := info.Defs[name]; obj != nil {
+					return obj.Pkg()
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // Emit writes to out the missing method based on type info of si.Receiver and CallExpr.
 func (si *CallStubInfo) Emit(out *bytes.Buffer, qual types.Qualifier) error {
 	params := si.collectParams()
@@ -0,0 +1,197 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/analysis/infertypeargs"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/typeparams"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// genericCall returns the innermost call to a generic function, such
+// as f(x), pkg.f[T](x), that encloses the [start, end) range, along
+// with the identifier of the function, or nil if there is none.
+func genericCall(file *ast.File, info *types.Info, start, end token.Pos) (*ast.CallExpr, *ast.Ident) {
+	path, _ := astutil.PathEnclosingInterval(file, start, end)
+	for _, n := range path {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fun, _, _, _ := typeparams.UnpackIndexExpr(call.Fun)
+		if fun == nil {
+			fun = call.Fun
+		}
+		var id *ast.Ident
+		switch fun := fun.(type) {
+		case *ast.Ident:
+			id = fun
+		case *ast.SelectorExpr:
+			id = fun.Sel
+		}
+		if id != nil && info.Instances[id].TypeArgs != nil {
+			return call, id
+		}
+	}
+	return nil, nil
+}
+
+// addTypeArgs produces an "Add explicit type arguments" code action
+// for a call to a generic function whose type arguments are inferred
+// in whole or in part. It inserts the inferred type arguments, unless
+// one of them cannot be written in the current file, for example
+// because it refers to a package that the file does not import.
+func addTypeArgs(req *codeActionsRequest) error {
+	info := req.pkg.TypesInfo()
+	call, id := genericCall(req.pgf.File, info, req.start, req.end)
+	if call == nil {
+		return nil
+	}
+	_, _, explicit, rbrack := typeparams.UnpackIndexExpr(call.Fun)
+	targs := info.Instances[id].TypeArgs
+	if len(explicit) >= targs.Len() {
+		return nil // no inferred type arguments
+	}
+
+	// Qualify types by the names of the file's imports,
+	// and reject types that are not accessible.
+	imported := make(map[string]bool)
+	for _, imp := range req.pgf.File.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && (imp.Name == nil || imp.Name.Name != "_") {
+			imported[path] = true
+		}
+	}
+	qual := typesinternal.FileQualifier(req.pgf.File, req.pkg.Types())
+	ok := true
+	q := func(p *types.Package) string {
+		if p != req.pkg.Types() && !imported[p.Path()] {
+			ok = false
+		}
+		return qual(p)
+	}
+	var args []string
+	for i := len(explicit); i < targs.Len(); i++ {
+		t := targs.At(i)
+		if !accessibleAt(t, req.pkg.Types(), call.Pos()) {
+			return nil
+		}
+		args = append(args, types.TypeString(t, q))
+	}
+	if !ok {
+		return nil
+	}
+
+	pos, text := id.End(), "["+strings.Join(args, ", ")+"]"
+	if len(explicit) > 0 {
+		pos, text = rbrack, ", "+strings.Join(args, ", ")
+	}
+	rng, err := req.pgf.PosRange(pos, pos)
+	if err != nil {
+		return err
+	}
+	req.addEditAction("Add explicit type arguments", nil,
+		protocol.DocumentChangeEdit(req.fh, []protocol.TextEdit{{Range: rng, NewText: text}}))
+	return nil
+}
+
+// accessibleAt reports whether the named types in t, which are
+// declared in pkg or imported, may be referred to at pos: that is,
+// they are not local types declared in a scope that does not contain
+// pos, nor unexported types of another package.
+func accessibleAt(t types.Type, pkg *types.Package, pos token.Pos) bool {
+	var visit func(t types.Type) bool
+	visitTuple := func(tuple *types.Tuple) bool {
+		for i := range tuple.Len() {
+			if !visit(tuple.At(i).Type()) {
+				return false
+			}
+		}
+		return true
+	}
+	visit = func(t types.Type) bool {
+		switch t := t.(type) {
+		case *types.Alias:
+			return visit(types.Unalias(t))
+		case *types.Named:
+			obj := t.Obj()
+			switch {
+			case obj.Pkg() == nil: // error
+			case obj.Pkg() != pkg:
+				if !obj.Exported() {
+					return false
+				}
+			case obj.Parent() != pkg.Scope() && !obj.Parent().Contains(pos):
+				return false // local type declared elsewhere
+			}
+			for i := range t.TypeArgs().Len() {
+				if !visit(t.TypeArgs().At(i)) {
+					return false
+				}
+			}
+		case *types.Pointer:
+			return visit(t.Elem())
+		case *types.Slice:
+			return visit(t.Elem())
+		case *types.Array:
+			return visit(t.Elem())
+		case *types.Chan:
+			return visit(t.Elem())
+		case *types.Map:
+			return visit(t.Key()) && visit(t.Elem())
+		case *types.Signature:
+			return visitTuple(t.Params()) && visitTuple(t.Results())
+		case *types.Struct:
+			for i := range t.NumFields() {
+				if !visit(t.Field(i).Type()) {
+					return false
+				}
+			}
+		case *types.Interface:
+			for i := range t.NumExplicitMethods() {
+				if !visit(t.ExplicitMethod(i).Type()) {
+					return false
+				}
+			}
+			for i := range t.NumEmbeddeds() {
+				if !visit(t.EmbeddedType(i)) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return visit(t)
+}
+
+// removeTypeArgs produces a "Remove redundant type arguments" code
+// action for a call to a generic function with explicit type
+// arguments that inference would supply.
+func removeTypeArgs(req *codeActionsRequest) error {
+	info := req.pkg.TypesInfo()
+	call, _ := genericCall(req.pgf.File, info, req.start, req.end)
+	if call == nil {
+		return nil
+	}
+	diag, ok := infertypeargs.DiagnoseCall(req.pkg.FileSet(), req.pkg.Types(), info, call)
+	if !ok {
+		return nil
+	}
+	edit := diag.SuggestedFixes[0].TextEdits[0]
+	rng, err := req.pgf.PosRange(edit.Pos, edit.End)
+	if err != nil {
+		return err
+	}
+	req.addEditAction("Remove redundant type arguments", nil,
+		protocol.DocumentChangeEdit(req.fh, []protocol.TextEdit{{Range: rng, NewText: string(edit.NewText)}}))
+	return nil
+}
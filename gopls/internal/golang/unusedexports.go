@@ -0,0 +1,189 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+// This file defines the "unused exports" report, which lists the
+// exported symbols of workspace packages that are not referenced
+// from any other workspace package.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"html"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/types/objectpath"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+)
+
+// An UnusedExport is an exported package-level symbol of a workspace
+// package that is not referenced by any other workspace package.
+type UnusedExport struct {
+	PkgPath  PackagePath
+	Name     string
+	Kind     string // "const", "var", "func", or "type"
+	Location protocol.Location
+
+	posn token.Position // start of declaring identifier, for web links
+}
+
+// UnusedExports returns the exported package-level symbols of the
+// workspace packages of the snapshot that are not referenced from
+// any other workspace package, ordered by package path and position.
+//
+// The query uses the cross-reference index, so it does not require
+// type-checking packages whose indexes are already cached, other than
+// the declaring packages themselves.
+//
+// A type is considered referenced if any of its methods or fields is
+// referenced. Exported methods and fields are not themselves reported,
+// since they may be used implicitly, for example to satisfy an
+// interface. Symbols of main packages are not reported, since they
+// cannot be imported, nor are those referenced only by the package's
+// own (in-package) tests. References from an external test package
+// (package p_test) do count as uses.
+//
+// Since only workspace packages are considered, symbols may be
+// reported that are in fact used by other modules.
+func UnusedExports(ctx context.Context, snapshot *cache.Snapshot) ([]UnusedExport, error) {
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ids     []PackageID // packages whose references are counted
+		declIDs []PackageID // packages whose exports are reported
+	)
+	for _, mp := range mps {
+		if mp.IsIntermediateTestVariant() {
+			continue
+		}
+		ids = append(ids, mp.ID)
+		if mp.ForTest == "" && mp.Name != "main" {
+			declIDs = append(declIDs, mp.ID)
+		}
+	}
+
+	// Gather the names of referenced package-level symbols.
+	indexes, err := snapshot.References(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[PackagePath]map[string]bool)
+	for _, index := range indexes {
+		for pkgPath, paths := range index.Referenced() {
+			names := used[pkgPath]
+			if names == nil {
+				names = make(map[string]bool)
+				used[pkgPath] = names
+			}
+			for path := range paths {
+				names[pkgLevelName(path)] = true
+			}
+		}
+	}
+
+	// Report the unreferenced exported symbols of each package.
+	pkgs, err := snapshot.TypeCheck(ctx, declIDs...)
+	if err != nil {
+		return nil, err
+	}
+	var result []UnusedExport
+	for _, pkg := range pkgs {
+		pkgPath := pkg.Metadata().PkgPath
+		scope := pkg.Types().Scope()
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if !obj.Exported() || used[pkgPath][name] {
+				continue
+			}
+			posn := safetoken.StartPosition(pkg.FileSet(), obj.Pos())
+			pgf, err := pkg.File(protocol.URIFromPath(posn.Filename))
+			if err != nil {
+				continue // e.g. declared in a cgo-processed file
+			}
+			loc, err := pgf.PosLocation(obj.Pos(), obj.Pos()+token.Pos(len(name)))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, UnusedExport{
+				PkgPath:  pkgPath,
+				Name:     name,
+				Kind:     objectKind(obj),
+				Location: loc,
+				posn:     posn,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		x, y := result[i], result[j]
+		if x.PkgPath != y.PkgPath {
+			return x.PkgPath < y.PkgPath
+		}
+		if x.posn.Filename != y.posn.Filename {
+			return x.posn.Filename < y.posn.Filename
+		}
+		return x.posn.Offset < y.posn.Offset
+	})
+	return result, nil
+}
+
+// pkgLevelName returns the name of the package-level object denoted
+// by the first segment of an object path, such as "T" in "T.m0".
+// (A reference to a method or field of T implies a use of T.)
+func pkgLevelName(path objectpath.Path) string {
+	name, _, _ := strings.Cut(string(path), ".")
+	return name
+}
+
+// UnusedExportsHTML formats the unused exports report as a web page.
+func UnusedExportsHTML(viewID string, exports []UnusedExport, web Web) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<style>
+li { font-family: monospace; }
+p { max-width: 6in; }
+</style>
+  <script src="/assets/common.js"></script>
+  <link rel="stylesheet" href="/assets/common.css">
+</head>
+<body>
+<h1>Unused exports</h1>
+<p>
+  These exported symbols are not referenced from any other package
+  in the workspace. Consider unexporting or deleting them, unless
+  they are used by other modules.
+</p>
+`)
+	if len(exports) == 0 {
+		buf.WriteString("<p>(none)</p>\n")
+	}
+	var prev PackagePath
+	for i, exp := range exports {
+		if i == 0 || exp.PkgPath != prev {
+			if i > 0 {
+				buf.WriteString("</ul>\n")
+			}
+			fmt.Fprintf(&buf, "<h2>package <a href='%s'>%s</a></h2>\n<ul>\n",
+				web.PkgURL(viewID, exp.PkgPath, ""),
+				html.EscapeString(string(exp.PkgPath)))
+			prev = exp.PkgPath
+		}
+		url := web.SrcURL(exp.posn.Filename, exp.posn.Line, exp.posn.Column)
+		fmt.Fprintf(&buf, "<li>%s %s</li>\n", exp.Kind, sourceLink(html.EscapeString(exp.Name), string(url)))
+	}
+	if len(exports) > 0 {
+		buf.WriteString("</ul>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
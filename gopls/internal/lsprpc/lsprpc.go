@@ -13,6 +13,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -43,6 +44,10 @@ type streamServer struct {
 	// optionsOverrides is passed to newly created sessions.
 	optionsOverrides func(*settings.Options)
 
+	// viewIdleTimeout, if positive, is the duration after which the state
+	// of an unused view is discarded.
+	viewIdleTimeout time.Duration
+
 	// serverForTest may be set to a test fake for testing.
 	serverForTest protocol.Server
 }
@@ -54,6 +59,14 @@ func NewStreamServer(cache *cache.Cache, daemon bool, optionsFunc func(*settings
 	return &streamServer{cache: cache, daemon: daemon, optionsOverrides: optionsFunc}
 }
 
+// NewDaemonStreamServer creates a StreamServer for a gopls daemon, which
+// serves any number of clients using the shared cache. If viewIdleTimeout
+// is positive, the state of each view that has not been used for that
+// duration is discarded, and reloaded when the view is next used.
+func NewDaemonStreamServer(cache *cache.Cache, viewIdleTimeout time.Duration, optionsFunc func(*settings.Options)) jsonrpc2.StreamServer {
+	return &streamServer{cache: cache, daemon: true, optionsOverrides: optionsFunc, viewIdleTimeout: viewIdleTimeout}
+}
+
 // ServeStream implements the jsonrpc2.StreamServer interface, by handling
 // incoming streams using a new lsp server.
 func (s *streamServer) ServeStream(ctx context.Context, conn jsonrpc2.Conn) error {
@@ -90,10 +103,30 @@ func (s *streamServer) ServeStream(ctx context.Context, conn jsonrpc2.Conn) erro
 		log.Printf("Session %s: connected", session.ID())
 		defer log.Printf("Session %s: exited", session.ID())
 	}
+	if s.viewIdleTimeout > 0 {
+		go s.evictIdleViews(ctx, session, conn.Done())
+	}
 	<-conn.Done()
 	return conn.Err()
 }
 
+// evictIdleViews periodically discards the state of the idle views of the
+// session, until done is closed.
+func (s *streamServer) evictIdleViews(ctx context.Context, session *cache.Session, done <-chan struct{}) {
+	ticker := time.NewTicker(s.viewIdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if n := session.EvictIdleViews(ctx, s.viewIdleTimeout); n > 0 {
+				log.Printf("Session %s: evicted %d idle views", session.ID(), n)
+			}
+		}
+	}
+}
+
 // A forwarder is a jsonrpc2.StreamServer that handles an LSP stream by
 // forwarding it to a remote. This is used when the gopls process started by
 // the editor is in the `-remote` mode, which means it finds and connects to a
@@ -435,6 +468,15 @@ type clientSession struct {
 	SessionID string `json:"sessionID"`
 	Logfile   string `json:"logfile"`
 	DebugAddr string `json:"debugAddr"`
+
+	// Views and Files are the number of views of the session, and of Go
+	// files in the packages they have loaded.
+	Views int `json:"views"`
+	Files int `json:"files"`
+	// HeapShare is an estimate of the heap memory held on behalf of the
+	// client: the share of the daemon's heap in use that is proportional
+	// to the number of files the client's session has loaded.
+	HeapShare uint64 `json:"heapShare"`
 }
 
 // serverState holds information about the gopls daemon process, including its
@@ -496,13 +538,26 @@ func handshaker(session *cache.Session, goplsPath string, logHandshakes bool, ha
 			if di := debug.GetInstance(ctx); di != nil {
 				resp.Logfile = di.Logfile
 				resp.DebugAddr = di.ListenedDebugAddress()
+				totalFiles := 0
 				for _, c := range di.State.Clients() {
+					views, files := c.Session.Footprint()
+					totalFiles += files
 					resp.Clients = append(resp.Clients, clientSession{
 						SessionID: c.Session.ID(),
 						Logfile:   c.Logfile,
 						DebugAddr: c.DebugAddress,
+						Views:     views,
+						Files:     files,
 					})
 				}
+				if totalFiles > 0 {
+					var m runtime.MemStats
+					runtime.ReadMemStats(&m)
+					for i := range resp.Clients {
+						c := &resp.Clients[i]
+						c.HeapShare = m.HeapInuse / uint64(totalFiles) * uint64(c.Files)
+					}
+				}
 			}
 			return reply(ctx, resp, nil)
 		}
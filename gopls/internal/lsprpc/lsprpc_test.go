@@ -58,7 +58,7 @@ func TestClientLogging(t *testing.T) {
 	server := PingServer{}
 	client := FakeClient{Logs: make(chan string, 10)}
 
-	ctx = debug.WithInstance(ctx, "")
+	ctx = debug.WithInstance(ctx, "", "off")
 	ss := NewStreamServer(cache.New(nil), false, nil).(*StreamServer)
 	ss.serverForTest = server
 	ts := servertest.NewPipeServer(ss, nil)
@@ -121,7 +121,7 @@ func checkClose(t *testing.T, closer func() error) {
 
 func setupForwarding(ctx context.Context, t *testing.T, s protocol.Server) (direct, forwarded servertest.Connector, cleanup func()) {
 	t.Helper()
-	serveCtx := debug.WithInstance(ctx, "")
+	serveCtx := debug.WithInstance(ctx, "", "off")
 	ss := NewStreamServer(cache.New(nil), false, nil).(*StreamServer)
 	ss.serverForTest = s
 	tsDirect := servertest.NewTCPServer(serveCtx, ss, nil)
@@ -214,8 +214,8 @@ func TestDebugInfoLifecycle(t *testing.T) {
 
 	baseCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	clientCtx := debug.WithInstance(baseCtx, "")
-	serverCtx := debug.WithInstance(baseCtx, "")
+	clientCtx := debug.WithInstance(baseCtx, "", "off")
+	serverCtx := debug.WithInstance(baseCtx, "", "off")
 
 	cache := cache.New(nil)
 	ss := NewStreamServer(cache, false, nil)
@@ -373,3 +373,63 @@ func TestEmptySlices(t *testing.T) {
 		t.Errorf("unexpectedly got %s, want %s", buf, good)
 	}
 }
+
+func TestEvictIdleViews(t *testing.T) {
+	sb, err := fake.NewSandbox(&fake.SandboxConfig{Files: fake.UnpackTxt(exampleProgram)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sb.Close(); err != nil {
+			t.Logf("closing workspace failed: %v", err)
+		}
+	}()
+
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serverCtx := debug.WithInstance(baseCtx, "", "off")
+
+	const timeout = 500 * time.Millisecond
+	ss := NewDaemonStreamServer(cache.New(nil), timeout, nil)
+	ts := servertest.NewTCPServer(serverCtx, ss, nil)
+	ed, err := fake.NewEditor(sb, fake.EditorConfig{}).Connect(baseCtx, ts, fake.ClientHooks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ed.Close(baseCtx)
+
+	// symbols checks that the workspace is loaded by querying its symbols,
+	// which also counts as a use of its view.
+	symbols := func() {
+		t.Helper()
+		syms, err := ed.Symbol(baseCtx, "main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(syms) == 0 {
+			t.Fatal("no symbols found")
+		}
+	}
+	symbols()
+	sessions := debug.GetInstance(serverCtx).State.Sessions()
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	session := sessions[0]
+
+	// Wait for the loaded view to be replaced, once idle, by a dormant one,
+	// which has loaded nothing.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if views, files := session.Footprint(); views == 1 && files == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle view was not evicted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Using the dormant view loads the workspace again.
+	symbols()
+}
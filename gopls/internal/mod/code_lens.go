@@ -108,15 +108,14 @@ func vendorLens(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) (
 	if err != nil {
 		return nil, err
 	}
-	title := "Create vendor directory"
-	uri := fh.URI()
-	cmd := command.NewVendorCommand(title, command.URIArg{URI: uri})
 	// Change the message depending on whether or not the module already has a
 	// vendor directory.
+	title := "Create vendor directory"
 	vendorDir := filepath.Join(fh.URI().DirPath(), "vendor")
 	if info, _ := os.Stat(vendorDir); info != nil && info.IsDir() {
 		title = "Sync vendor directory"
 	}
+	cmd := command.NewVendorCommand(title, command.URIArg{URI: fh.URI()})
 	return []protocol.CodeLens{{Range: rng, Command: cmd}}, nil
 }
 
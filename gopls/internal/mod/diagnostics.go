@@ -9,8 +9,11 @@ package mod
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -18,6 +21,7 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/protocol/command"
@@ -81,7 +85,7 @@ func collectDiagnostics(ctx context.Context, snapshot *cache.Snapshot, diagFn fu
 			}
 			for _, d := range diagnostics {
 				mu.Lock()
-				reports[d.URI] = append(reports[fh.URI()], d)
+				reports[d.URI] = append(reports[d.URI], d)
 				mu.Unlock()
 			}
 			return nil
@@ -127,11 +131,107 @@ func tidyDiagnostics(ctx context.Context, snapshot *cache.Snapshot, fh file.Hand
 			// is to fix the mod tidy diagnostics.
 			event.Error(ctx, fmt.Sprintf("tidy: diagnosing %s", pm.URI), err)
 		}
-		return nil, nil
+		// Without the go command, fall back to detecting unused
+		// dependencies from the import graph of the workspace.
+		return unusedDependencyDiagnostics(ctx, snapshot, pm)
 	}
 	return tidied.Diagnostics, nil
 }
 
+// unusedDependencyDiagnostics reports the direct requirements of the
+// go.mod file for modules of which no package is imported by the
+// packages of the module, including their tests. It is an
+// approximation of the "not used" diagnostics of go mod tidy, which is
+// unavailable if, for example, some modules cannot be downloaded.
+//
+// Only the current build configuration is loaded, so imports from
+// files excluded by build constraints are inspected syntactically;
+// and a module that may provide an unresolved import is assumed to be
+// used.
+func unusedDependencyDiagnostics(ctx context.Context, snapshot *cache.Snapshot, pm *cache.ParsedModule) ([]*cache.Diagnostic, error) {
+	if pm.File == nil || pm.File.Module == nil {
+		return nil, nil
+	}
+	workspacePkgs, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		usedModules = make(map[string]bool)
+		unresolved  []string // import paths whose module is unknown
+		loaded      bool     // some package of the module was loaded
+	)
+	for _, mp := range workspacePkgs {
+		if mp.Module == nil || mp.Module.GoMod != pm.URI.Path() {
+			continue
+		}
+		loaded = true
+		for importPath, id := range mp.DepsByImpPath {
+			if id == "" {
+				unresolved = append(unresolved, string(importPath))
+			} else if dep := snapshot.Metadata(id); dep != nil && dep.Module != nil {
+				usedModules[dep.Module.Path] = true
+			}
+		}
+		for _, uri := range mp.IgnoredFiles {
+			if filepath.Ext(uri.Path()) != ".go" {
+				continue
+			}
+			fh, err := snapshot.ReadFile(ctx, uri)
+			if err != nil {
+				return nil, err
+			}
+			pgf, err := snapshot.ParseGo(ctx, fh, parsego.Header)
+			if err != nil {
+				return nil, err
+			}
+			for _, spec := range pgf.File.Imports {
+				if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+					unresolved = append(unresolved, path)
+				}
+			}
+		}
+	}
+	if !loaded {
+		return nil, nil // nothing is known about the imports of the module
+	}
+	for _, tool := range pm.File.Tool {
+		unresolved = append(unresolved, tool.Path)
+	}
+
+	var diagnostics []*cache.Diagnostic
+	for _, req := range pm.File.Require {
+		if req.Indirect || usedModules[req.Mod.Path] {
+			continue
+		}
+		if slices.ContainsFunc(unresolved, func(path string) bool {
+			return path == req.Mod.Path || strings.HasPrefix(path, req.Mod.Path+"/")
+		}) {
+			continue // may provide an unresolved import
+		}
+		rng, err := pm.Mapper.OffsetRange(req.Syntax.Start.Byte, req.Syntax.End.Byte)
+		if err != nil {
+			return nil, err
+		}
+		title := fmt.Sprintf("Remove dependency: %s", req.Mod.Path)
+		cmd := command.NewRemoveDependencyCommand(title, command.RemoveDependencyArgs{
+			URI:        pm.URI,
+			ModulePath: req.Mod.Path,
+			Tidy:       true,
+		})
+		diagnostics = append(diagnostics, &cache.Diagnostic{
+			URI:            pm.URI,
+			Range:          rng,
+			Severity:       protocol.SeverityWarning,
+			Source:         cache.ModTidyError,
+			Message:        fmt.Sprintf("%s is not imported by any package in this module", req.Mod.Path),
+			SuggestedFixes: []cache.SuggestedFix{cache.SuggestedFixFromCommand(cmd, protocol.QuickFix)},
+		})
+	}
+	return diagnostics, nil
+}
+
 // upgradeDiagnostics adds upgrade quick fixes for individual modules if the upgrades
 // are recorded in the view.
 func upgradeDiagnostics(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) (upgradeDiagnostics []*cache.Diagnostic, err error) {
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/file"
@@ -119,10 +120,21 @@ func hoverOnRequireStatement(ctx context.Context, pm *cache.ParsedModule, offset
 	explanation = formatExplanation(explanation, req, options, isPrivate)
 	vulns := formatVulnerabilities(affecting, nonaffecting, osvs, options, fromGovulncheck)
 
+	// Get the module information from the module proxy.
+	// Failure to get it is not fatal.
+	info, err := snapshot.ModuleInfo(ctx, fh.URI(), req.Mod)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		event.Error(ctx, fmt.Sprintf("getting module information for %s", req.Mod), err)
+	}
+	moduleInfo := formatModuleInfo(info, options, isPrivate)
+
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
 			Kind:  options.PreferredContentFormat,
-			Value: header + vulns + explanation,
+			Value: header + vulns + explanation + moduleInfo,
 		},
 		Range: rng,
 	}, nil
@@ -378,3 +390,63 @@ func formatExplanation(text string, req *modfile.Require, options *settings.Opti
 	b.WriteString("\n```")
 	return b.String()
 }
+
+// formatModuleInfo formats the information about a module dependency
+// from the module proxy: its deprecation and retractions, its latest
+// versions, and its license.
+func formatModuleInfo(info *cache.ModuleInfo, options *settings.Options, isPrivate bool) string {
+	if info == nil {
+		return ""
+	}
+	useMarkdown := options.PreferredContentFormat == protocol.Markdown
+	// See golang/go#36998: don't link to modules matching GOPRIVATE.
+	link := func(mod module.Version) string {
+		if isPrivate || !useMarkdown {
+			return mod.String()
+		}
+		return fmt.Sprintf("[%s](%s)", mod, cache.BuildLink(options.LinkTarget, "mod/"+mod.String(), ""))
+	}
+
+	var lines []string
+	if info.Deprecated != "" {
+		lines = append(lines, "**Deprecated:** "+info.Deprecated)
+	}
+	if rationale, ok := info.Retracted(); ok {
+		line := "**Retracted:** " + info.Module.Version + " is retracted"
+		if rationale != "" {
+			line += ": " + rationale
+		}
+		lines = append(lines, line)
+	}
+	if info.Latest != "" {
+		lines = append(lines, "Latest version: "+link(module.Version{Path: info.Module.Path, Version: info.Latest}))
+	}
+	if info.LatestMajor.Path != "" {
+		lines = append(lines, "Latest major version: "+link(info.LatestMajor))
+	}
+	if len(info.Retractions) > 0 {
+		var retractions []string
+		for _, r := range info.Retractions {
+			versions := r.Low
+			if r.High != r.Low {
+				versions = fmt.Sprintf("[%s, %s]", r.Low, r.High)
+			}
+			if r.Rationale != "" {
+				versions += " (" + r.Rationale + ")"
+			}
+			retractions = append(retractions, versions)
+		}
+		lines = append(lines, "Retracted versions: "+strings.Join(retractions, ", "))
+	}
+	if info.License != "" {
+		lines = append(lines, "License: "+info.License)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sep := "\n"
+	if useMarkdown {
+		sep = "\n\n"
+	}
+	return sep + strings.Join(lines, sep)
+}
@@ -0,0 +1,419 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mod
+
+// This file defines diagnostics and hover for go.sum files.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/event"
+)
+
+// SumDiagnostics returns diagnostics for the go.sum files of the go.mod
+// files in the workspace: malformed entries, entries whose checksum
+// differs from that of the module cache, and, if go mod tidy succeeds,
+// entries that it would remove or add.
+func SumDiagnostics(ctx context.Context, snapshot *cache.Snapshot) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	ctx, done := event.Start(ctx, "mod.SumDiagnostics", snapshot.Labels()...)
+	defer done()
+
+	return collectDiagnostics(ctx, snapshot, sumDiagnostics)
+}
+
+// sumDiagnostics reports diagnostics for the go.sum file of the go.mod
+// file fh.
+func sumDiagnostics(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]*cache.Diagnostic, error) {
+	sumFH, err := snapshot.ReadFile(ctx, sumFileURI(fh.URI()))
+	if err != nil {
+		return nil, err
+	}
+	content, err := sumFH.Content()
+	if err != nil {
+		return nil, nil // no go.sum file
+	}
+	m := protocol.NewMapper(sumFH.URI(), content)
+	entries, malformed := parseSum(content)
+
+	var diagnostics []*cache.Diagnostic
+	addDiagnostic := func(start, end int, severity protocol.DiagnosticSeverity, message string, fix cache.SuggestedFix) error {
+		rng, err := m.OffsetRange(start, end)
+		if err != nil {
+			return err
+		}
+		diagnostics = append(diagnostics, &cache.Diagnostic{
+			URI:            m.URI,
+			Range:          rng,
+			Severity:       severity,
+			Source:         cache.SumFileError,
+			Message:        message,
+			SuggestedFixes: []cache.SuggestedFix{fix},
+		})
+		return nil
+	}
+	// deleteLine returns a fix that deletes the line [start, end).
+	deleteLine := func(title string, start, end int) (cache.SuggestedFix, error) {
+		if end < len(content) {
+			end++ // newline
+		}
+		return editFix(m, title, start, end, "")
+	}
+
+	for _, line := range malformed {
+		fix, err := deleteLine("Remove malformed go.sum entry", line[0], line[1])
+		if err != nil {
+			return nil, err
+		}
+		if err := addDiagnostic(line[0], line[1], protocol.SeverityError, "malformed go.sum entry", fix); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare the checksums with those of the module cache.
+	gomodcache := snapshot.View().Folder().Env.GOMODCACHE
+	for _, e := range entries {
+		want := cachedSum(gomodcache, e.mod)
+		if want == "" || want == e.hash {
+			continue
+		}
+		fix, err := editFix(m, fmt.Sprintf("Re-hash %s %s from the module cache", e.mod.Path, e.mod.Version), e.hashStart, e.end, want)
+		if err != nil {
+			return nil, err
+		}
+		msg := fmt.Sprintf("checksum mismatch for %s %s: the module cache has %s", e.mod.Path, e.mod.Version, want)
+		if err := addDiagnostic(e.hashStart, e.end, protocol.SeverityError, msg, fix); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare the entries with those of the tidied go.sum file.
+	pm, err := snapshot.ParseMod(ctx, fh) // memoized
+	if err != nil {
+		return diagnostics, nil // errors reported by ParseDiagnostics
+	}
+	tidied, err := snapshot.ModTidy(ctx, pm) // memoized
+	if err != nil {
+		return diagnostics, nil // errors reported by TidyDiagnostics
+	}
+	ideal, _ := parseSum(tidied.TidiedSum)
+	present := make(map[module.Version]bool)
+	for _, e := range entries {
+		present[e.mod] = true
+	}
+	needed := make(map[module.Version]bool)
+	for _, e := range ideal {
+		needed[e.mod] = true
+	}
+	for _, e := range entries {
+		if needed[e.mod] {
+			continue
+		}
+		fix, err := deleteLine("Remove unused go.sum entry", e.start, e.end)
+		if err != nil {
+			return nil, err
+		}
+		msg := fmt.Sprintf("%s %s is not needed by go.mod", e.mod.Path, e.mod.Version)
+		if err := addDiagnostic(e.start, e.end, protocol.SeverityWarning, msg, fix); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range ideal {
+		if present[e.mod] {
+			continue
+		}
+		// Insert the entry before the first one that follows it.
+		offset, text := len(content), string(tidied.TidiedSum[e.start:e.end])+"\n"
+		for _, other := range entries {
+			if sumEntryLess(e.mod, other.mod) {
+				offset = other.start
+				break
+			}
+		}
+		if offset == len(content) && len(content) > 0 && content[len(content)-1] != '\n' {
+			text = "\n" + text
+		}
+		fix, err := editFix(m, fmt.Sprintf("Add go.sum entry for %s %s", e.mod.Path, e.mod.Version), offset, offset, text)
+		if err != nil {
+			return nil, err
+		}
+		msg := fmt.Sprintf("missing go.sum entry for %s %s", e.mod.Path, e.mod.Version)
+		if err := addDiagnostic(offset, offset, protocol.SeverityWarning, msg, fix); err != nil {
+			return nil, err
+		}
+	}
+	return diagnostics, nil
+}
+
+// editFix returns a quick fix that replaces the range [start, end) of
+// the file by text.
+func editFix(m *protocol.Mapper, title string, start, end int, text string) (cache.SuggestedFix, error) {
+	rng, err := m.OffsetRange(start, end)
+	if err != nil {
+		return cache.SuggestedFix{}, err
+	}
+	return cache.SuggestedFix{
+		Title:      title,
+		Edits:      map[protocol.DocumentURI][]protocol.TextEdit{m.URI: {{Range: rng, NewText: text}}},
+		ActionKind: protocol.QuickFix,
+	}, nil
+}
+
+// SumHover returns the hover for an entry of a go.sum file, which
+// explains by which requirement of the go.mod file the module was
+// introduced.
+func SumHover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, position protocol.Position) (*protocol.Hover, error) {
+	ctx, done := event.Start(ctx, "mod.SumHover")
+	defer done()
+
+	if filepath.Base(fh.URI().Path()) != "go.sum" {
+		return nil, nil // e.g. go.work.sum
+	}
+
+	content, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	m := protocol.NewMapper(fh.URI(), content)
+	offset, err := m.PositionOffset(position)
+	if err != nil {
+		return nil, err
+	}
+	entries, _ := parseSum(content)
+	var entry *sumEntry
+	for i := range entries {
+		if entries[i].start <= offset && offset <= entries[i].end {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, nil // not on an entry
+	}
+
+	modFH, err := snapshot.ReadFile(ctx, modFileURI(fh.URI()))
+	if err != nil {
+		return nil, err
+	}
+	pm, err := snapshot.ParseMod(ctx, modFH)
+	if err != nil {
+		return nil, nil // no valid go.mod file
+	}
+	version, isGoMod := strings.CutSuffix(entry.mod.Version, "/go.mod")
+	mod := module.Version{Path: entry.mod.Path, Version: version}
+	chain := requirementChain(snapshot.View().Folder().Env.GOMODCACHE, pm.File, mod)
+
+	options := snapshot.Options()
+	var b strings.Builder
+	b.WriteString(formatHeader(mod.Path, options))
+	if isGoMod {
+		fmt.Fprintf(&b, "Checksum of the go.mod file of `%s`.", mod)
+	} else {
+		fmt.Fprintf(&b, "Checksum of the content of `%s`.", mod)
+	}
+	b.WriteString("\n\n")
+	switch {
+	case chain == nil:
+		b.WriteString("This module is not required by go.mod.")
+	case len(chain) == 1:
+		fmt.Fprintf(&b, "This module is required by `require %s %s` in go.mod.", mod.Path, mod.Version)
+	default:
+		fmt.Fprintf(&b, "This module was introduced by `require %s %s` in go.mod:\n```text", chain[0].Path, chain[0].Version)
+		dash := ""
+		for _, mod := range chain {
+			dash += "-"
+			b.WriteString("\n" + dash + " " + mod.String())
+		}
+		b.WriteString("\n```")
+	}
+
+	rng, err := m.OffsetRange(entry.start, entry.end)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  options.PreferredContentFormat,
+			Value: b.String(),
+		},
+		Range: rng,
+	}, nil
+}
+
+// A sumEntry is an entry of a go.sum file.
+type sumEntry struct {
+	mod        module.Version // Version has a "/go.mod" suffix for the checksum of a go.mod file
+	hash       string
+	start, end int // offsets of the line, excluding its newline
+	hashStart  int // offset of the hash
+}
+
+// parseSum parses the content of a go.sum file. It returns its entries,
+// and the offsets of the lines that are malformed.
+func parseSum(content []byte) (entries []sumEntry, malformed [][2]int) {
+	for start := 0; start < len(content); {
+		end := len(content)
+		if i := bytes.IndexByte(content[start:], '\n'); i >= 0 {
+			end = start + i
+		}
+		line := string(content[start:end])
+		if f := strings.Fields(line); len(f) == 3 {
+			entries = append(entries, sumEntry{
+				mod:       module.Version{Path: f[0], Version: f[1]},
+				hash:      f[2],
+				start:     start,
+				end:       start + len(strings.TrimRight(line, " \t\r")),
+				hashStart: start + strings.LastIndex(line, f[2]),
+			})
+		} else if len(f) > 0 {
+			malformed = append(malformed, [2]int{start, end})
+		}
+		start = end + 1
+	}
+	return entries, malformed
+}
+
+// sumEntryLess reports whether the entry for x precedes that for y in
+// a go.sum file, whose entries are sorted like [module.Sort].
+func sumEntryLess(x, y module.Version) bool {
+	if x.Path != y.Path {
+		return x.Path < y.Path
+	}
+	xv, xfile, _ := strings.Cut(x.Version, "/")
+	yv, yfile, _ := strings.Cut(y.Version, "/")
+	if xv != yv {
+		return semver.Compare(xv, yv) < 0
+	}
+	return xfile < yfile
+}
+
+// cachedSum returns the checksum, in the form of a go.sum entry, of the
+// module content or go.mod file in the module cache, or "" if it is not
+// in the cache.
+func cachedSum(gomodcache string, mod module.Version) string {
+	version, isGoMod := strings.CutSuffix(mod.Version, "/go.mod")
+	prefix, err := downloadPrefix(gomodcache, module.Version{Path: mod.Path, Version: version})
+	if err != nil {
+		return ""
+	}
+	if isGoMod {
+		data, err := os.ReadFile(prefix + ".mod")
+		if err != nil {
+			return ""
+		}
+		// This is how the go command computes the checksum of a go.mod file.
+		h, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		})
+		if err != nil {
+			return ""
+		}
+		return h
+	}
+	data, err := os.ReadFile(prefix + ".ziphash")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// requirementChain returns the chain of requirements by which the main
+// module, whose go.mod file is f, requires mod, according to the go.mod
+// files of the module cache. The chain starts with a requirement of f,
+// and ends with mod. It returns nil if mod is not required.
+func requirementChain(gomodcache string, f *modfile.File, mod module.Version) []module.Version {
+	type node struct {
+		mod    module.Version
+		parent *node
+	}
+	var queue []*node
+	seen := make(map[module.Version]bool)
+	for _, req := range f.Require {
+		if !seen[req.Mod] {
+			seen[req.Mod] = true
+			queue = append(queue, &node{mod: req.Mod})
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n.mod == mod {
+			var chain []module.Version
+			for ; n != nil; n = n.parent {
+				chain = append(chain, n.mod)
+			}
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+			return chain
+		}
+		for _, req := range cachedRequirements(gomodcache, n.mod) {
+			if !seen[req] {
+				seen[req] = true
+				queue = append(queue, &node{mod: req, parent: n})
+			}
+		}
+	}
+	return nil
+}
+
+// cachedRequirements returns the requirements of the go.mod file of mod
+// in the module cache, if any.
+func cachedRequirements(gomodcache string, mod module.Version) []module.Version {
+	prefix, err := downloadPrefix(gomodcache, mod)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(prefix + ".mod")
+	if err != nil {
+		return nil
+	}
+	f, err := modfile.ParseLax(prefix+".mod", data, nil)
+	if err != nil {
+		return nil
+	}
+	var reqs []module.Version
+	for _, req := range f.Require {
+		reqs = append(reqs, req.Mod)
+	}
+	return reqs
+}
+
+// downloadPrefix returns the common prefix of the names of the files
+// of the module cache download directory for mod, such as the .mod and
+// .ziphash files.
+func downloadPrefix(gomodcache string, mod module.Version) (string, error) {
+	path, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return "", err
+	}
+	version, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gomodcache, "cache", "download", path, "@v", version), nil
+}
+
+// sumFileURI returns the URI of the go.sum file of the go.mod file modURI.
+func sumFileURI(modURI protocol.DocumentURI) protocol.DocumentURI {
+	return protocol.URIFromPath(strings.TrimSuffix(modURI.Path(), ".mod") + ".sum")
+}
+
+// modFileURI returns the URI of the go.mod file of the go.sum file sumURI.
+func modFileURI(sumURI protocol.DocumentURI) protocol.DocumentURI {
+	return protocol.URIFromPath(strings.TrimSuffix(sumURI.Path(), ".sum") + ".mod")
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/internal/event"
+)
+
+// VendorDiagnostics returns diagnostics for the requirements of the
+// go.mod files in the workspace that are inconsistent with their
+// vendor/modules.txt files.
+func VendorDiagnostics(ctx context.Context, snapshot *cache.Snapshot) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+	ctx, done := event.Start(ctx, "mod.VendorDiagnostics", snapshot.Labels()...)
+	defer done()
+
+	return collectDiagnostics(ctx, snapshot, vendorDiagnostics)
+}
+
+var modFlagRegexp = regexp.MustCompile(`-mod[ =](\w+)`)
+
+// vendorDiagnostics reports the requirements of the go.mod file that
+// are inconsistent with its vendor/modules.txt file, if the go command
+// would use the vendor directory of the module.
+//
+// The go command reports inconsistent vendoring too, but only as an
+// error of the whole go.mod file, and only once the file is saved.
+// This check is based on the contents of the go.mod buffer, so that
+// the need to run 'go mod vendor' is apparent as soon as the
+// requirements change.
+func vendorDiagnostics(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]*cache.Diagnostic, error) {
+	// Vendoring is implied by the presence of the vendor directory
+	// since go1.14, unless a -mod flag says otherwise.
+	// (In a go.work workspace, the vendor directory is that of
+	// the workspace, not of its modules.)
+	if snapshot.View().Type() == cache.GoWorkView {
+		return nil, nil
+	}
+	if m := modFlagRegexp.FindStringSubmatch(snapshot.View().GOFLAGS()); m != nil && m[1] != "vendor" {
+		return nil, nil
+	}
+	pm, err := snapshot.ParseMod(ctx, fh) // memoized
+	if err != nil || pm.File.Module == nil {
+		return nil, nil // errors reported by ParseDiagnostics
+	}
+	if pm.File.Go == nil || semver.Compare("v"+pm.File.Go.Version, "v1.14") < 0 {
+		return nil, nil
+	}
+	// Read modules.txt directly: as gopls does not watch .txt
+	// files, the snapshot would not observe changes to it.
+	content, err := os.ReadFile(filepath.Join(fh.URI().DirPath(), "vendor", "modules.txt"))
+	if err != nil {
+		return nil, nil // no vendor directory
+	}
+	vendored, explicit := parseModulesTxt(content)
+
+	fix := cache.SuggestedFixFromCommand(command.NewVendorCommand("Run go mod vendor", command.URIArg{URI: fh.URI()}), protocol.QuickFix)
+	diagnostic := func(syntax *modfile.Line, format string, args ...any) (*cache.Diagnostic, error) {
+		rng, err := pm.Mapper.OffsetRange(syntax.Start.Byte, syntax.End.Byte)
+		if err != nil {
+			return nil, err
+		}
+		return &cache.Diagnostic{
+			URI:            fh.URI(),
+			Range:          rng,
+			Severity:       protocol.SeverityWarning,
+			Source:         cache.VendorError,
+			Message:        fmt.Sprintf(format, args...) + `; run "go mod vendor" to update the vendor directory`,
+			SuggestedFixes: []cache.SuggestedFix{fix},
+		}, nil
+	}
+
+	var diagnostics []*cache.Diagnostic
+	required := make(map[string]bool)
+	for _, req := range pm.File.Require {
+		path, version := req.Mod.Path, req.Mod.Version
+		required[path] = true
+		var (
+			d   *cache.Diagnostic
+			err error
+		)
+		switch v, ok := vendored[path]; {
+		case !ok || !explicit[path]:
+			d, err = diagnostic(req.Syntax, "%s %s is not vendored", path, version)
+		case v != version:
+			d, err = diagnostic(req.Syntax, "%s is vendored at %s, not %s", path, v, version)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	for path := range explicit {
+		if !required[path] {
+			d, err := diagnostic(pm.File.Module.Syntax, "%s is vendored but no longer required", path)
+			if err != nil {
+				return nil, err
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics, nil
+}
+
+// parseModulesTxt parses the contents of a vendor/modules.txt file,
+// returning the version of each vendored module and the set of those
+// that are required explicitly by go.mod.
+func parseModulesTxt(content []byte) (vendored map[string]string, explicit map[string]bool) {
+	vendored = make(map[string]string)
+	explicit = make(map[string]bool)
+	var path string // current module, if any
+	for _, line := range strings.Split(string(content), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			// # path version [=> replacement [version]]
+			// # path => replacement [version]
+			fields := strings.Fields(line[len("# "):])
+			path = ""
+			if len(fields) >= 2 && fields[1] != "=>" {
+				path = fields[0]
+				vendored[path] = fields[1]
+			}
+		case strings.HasPrefix(line, "## ") && path != "":
+			// ## explicit; go 1.21
+			for _, marker := range strings.Split(line[len("## "):], ";") {
+				if strings.TrimSpace(marker) == "explicit" {
+					explicit[path] = true
+				}
+			}
+		}
+	}
+	return vendored, explicit
+}
@@ -33,32 +33,47 @@ const (
 	ChangeSignature         Command = "gopls.change_signature"
 	CheckUpgrades           Command = "gopls.check_upgrades"
 	ClientOpenURL           Command = "gopls.client_open_url"
+	CompareBenchmarks       Command = "gopls.compare_benchmarks"
 	DiagnoseFiles           Command = "gopls.diagnose_files"
 	Doc                     Command = "gopls.doc"
+	DownloadGoSum           Command = "gopls.download_go_sum"
+	DriverStats             Command = "gopls.driver_stats"
 	EditGoDirective         Command = "gopls.edit_go_directive"
 	ExtractToNewFile        Command = "gopls.extract_to_new_file"
 	FetchVulncheckResult    Command = "gopls.fetch_vulncheck_result"
 	FreeSymbols             Command = "gopls.free_symbols"
+	FuzzCorpus              Command = "gopls.fuzz_corpus"
 	GCDetails               Command = "gopls.gc_details"
 	Generate                Command = "gopls.generate"
+	GenerateConversion      Command = "gopls.generate_conversion"
 	GoGetPackage            Command = "gopls.go_get_package"
+	IndexStatus             Command = "gopls.index_status"
+	IntroduceOptionsStruct  Command = "gopls.introduce_options_struct"
 	ListImports             Command = "gopls.list_imports"
 	ListKnownPackages       Command = "gopls.list_known_packages"
 	MaybePromptForTelemetry Command = "gopls.maybe_prompt_for_telemetry"
 	MemStats                Command = "gopls.mem_stats"
 	Modules                 Command = "gopls.modules"
+	NewModule               Command = "gopls.new_module"
 	Packages                Command = "gopls.packages"
 	RegenerateCgo           Command = "gopls.regenerate_cgo"
 	RemoveDependency        Command = "gopls.remove_dependency"
 	ResetGoModDiagnostics   Command = "gopls.reset_go_mod_diagnostics"
+	ResyncDriver            Command = "gopls.resync_driver"
+	RunCustomCodeAction     Command = "gopls.run_custom_code_action"
+	RunFuzz                 Command = "gopls.run_fuzz"
 	RunGoWorkCommand        Command = "gopls.run_go_work_command"
 	RunGovulncheck          Command = "gopls.run_govulncheck"
 	RunTests                Command = "gopls.run_tests"
 	ScanImports             Command = "gopls.scan_imports"
+	SetViewEnvironment      Command = "gopls.set_view_environment"
+	ShowGenerator           Command = "gopls.show_generator"
 	StartDebugging          Command = "gopls.start_debugging"
 	StartProfile            Command = "gopls.start_profile"
 	StopProfile             Command = "gopls.stop_profile"
 	Tidy                    Command = "gopls.tidy"
+	UnusedExports           Command = "gopls.unused_exports"
+	UpdateExampleOutput     Command = "gopls.update_example_output"
 	UpdateGoSum             Command = "gopls.update_go_sum"
 	UpgradeDependency       Command = "gopls.upgrade_dependency"
 	Vendor                  Command = "gopls.vendor"
@@ -77,32 +92,47 @@ var Commands = []Command{
 	ChangeSignature,
 	CheckUpgrades,
 	ClientOpenURL,
+	CompareBenchmarks,
 	DiagnoseFiles,
 	Doc,
+	DownloadGoSum,
+	DriverStats,
 	EditGoDirective,
 	ExtractToNewFile,
 	FetchVulncheckResult,
 	FreeSymbols,
+	FuzzCorpus,
 	GCDetails,
 	Generate,
+	GenerateConversion,
 	GoGetPackage,
+	IndexStatus,
+	IntroduceOptionsStruct,
 	ListImports,
 	ListKnownPackages,
 	MaybePromptForTelemetry,
 	MemStats,
 	Modules,
+	NewModule,
 	Packages,
 	RegenerateCgo,
 	RemoveDependency,
 	ResetGoModDiagnostics,
+	ResyncDriver,
+	RunCustomCodeAction,
+	RunFuzz,
 	RunGoWorkCommand,
 	RunGovulncheck,
 	RunTests,
 	ScanImports,
+	SetViewEnvironment,
+	ShowGenerator,
 	StartDebugging,
 	StartProfile,
 	StopProfile,
 	Tidy,
+	UnusedExports,
+	UpdateExampleOutput,
 	UpdateGoSum,
 	UpgradeDependency,
 	Vendor,
@@ -169,6 +199,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.ClientOpenURL(ctx, a0)
+	case CompareBenchmarks:
+		var a0 CompareBenchmarksArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.CompareBenchmarks(ctx, a0)
 	case DiagnoseFiles:
 		var a0 DiagnoseFilesArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -181,6 +217,14 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return s.Doc(ctx, a0)
+	case DownloadGoSum:
+		var a0 DownloadGoSumArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.DownloadGoSum(ctx, a0)
+	case DriverStats:
+		return s.DriverStats(ctx)
 	case EditGoDirective:
 		var a0 EditGoDirectiveArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -206,6 +250,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.FreeSymbols(ctx, a0, a1)
+	case FuzzCorpus:
+		var a0 FuzzCorpusArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.FuzzCorpus(ctx, a0)
 	case GCDetails:
 		var a0 protocol.DocumentURI
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -218,12 +268,26 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.Generate(ctx, a0)
+	case GenerateConversion:
+		var a0 protocol.Location
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.GenerateConversion(ctx, a0)
 	case GoGetPackage:
 		var a0 GoGetPackageArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
 			return nil, err
 		}
 		return nil, s.GoGetPackage(ctx, a0)
+	case IndexStatus:
+		return s.IndexStatus(ctx)
+	case IntroduceOptionsStruct:
+		var a0 protocol.Location
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.IntroduceOptionsStruct(ctx, a0)
 	case ListImports:
 		var a0 URIArg
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -246,6 +310,12 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return s.Modules(ctx, a0)
+	case NewModule:
+		var a0 NewModuleArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.NewModule(ctx, a0)
 	case Packages:
 		var a0 PackagesArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -270,6 +340,24 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.ResetGoModDiagnostics(ctx, a0)
+	case ResyncDriver:
+		var a0 URIArg
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.ResyncDriver(ctx, a0)
+	case RunCustomCodeAction:
+		var a0 RunCustomCodeActionArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.RunCustomCodeAction(ctx, a0)
+	case RunFuzz:
+		var a0 RunFuzzArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.RunFuzz(ctx, a0)
 	case RunGoWorkCommand:
 		var a0 RunGoWorkArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -290,6 +378,18 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 		return nil, s.RunTests(ctx, a0)
 	case ScanImports:
 		return nil, s.ScanImports(ctx)
+	case SetViewEnvironment:
+		var a0 SetViewEnvironmentArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.SetViewEnvironment(ctx, a0)
+	case ShowGenerator:
+		var a0 protocol.Location
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.ShowGenerator(ctx, a0)
 	case StartDebugging:
 		var a0 DebuggingArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -314,6 +414,18 @@ func Dispatch(ctx context.Context, params *protocol.ExecuteCommandParams, s Inte
 			return nil, err
 		}
 		return nil, s.Tidy(ctx, a0)
+	case UnusedExports:
+		var a0 UnusedExportsArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return s.UnusedExports(ctx, a0)
+	case UpdateExampleOutput:
+		var a0 UpdateExampleOutputArgs
+		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
+			return nil, err
+		}
+		return nil, s.UpdateExampleOutput(ctx, a0)
 	case UpdateGoSum:
 		var a0 URIArgs
 		if err := UnmarshalArgs(params.Arguments, &a0); err != nil {
@@ -418,6 +530,14 @@ func NewClientOpenURLCommand(title string, a0 string) *protocol.Command {
 	}
 }
 
+func NewCompareBenchmarksCommand(title string, a0 CompareBenchmarksArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   CompareBenchmarks.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewDiagnoseFilesCommand(title string, a0 DiagnoseFilesArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -434,6 +554,22 @@ func NewDocCommand(title string, a0 DocArgs) *protocol.Command {
 	}
 }
 
+func NewDownloadGoSumCommand(title string, a0 DownloadGoSumArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   DownloadGoSum.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewDriverStatsCommand(title string) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   DriverStats.String(),
+		Arguments: MustMarshalArgs(),
+	}
+}
+
 func NewEditGoDirectiveCommand(title string, a0 EditGoDirectiveArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -466,6 +602,14 @@ func NewFreeSymbolsCommand(title string, a0 string, a1 protocol.Location) *proto
 	}
 }
 
+func NewFuzzCorpusCommand(title string, a0 FuzzCorpusArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   FuzzCorpus.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewGCDetailsCommand(title string, a0 protocol.DocumentURI) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -482,6 +626,14 @@ func NewGenerateCommand(title string, a0 GenerateArgs) *protocol.Command {
 	}
 }
 
+func NewGenerateConversionCommand(title string, a0 protocol.Location) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   GenerateConversion.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewGoGetPackageCommand(title string, a0 GoGetPackageArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -490,6 +642,22 @@ func NewGoGetPackageCommand(title string, a0 GoGetPackageArgs) *protocol.Command
 	}
 }
 
+func NewIndexStatusCommand(title string) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   IndexStatus.String(),
+		Arguments: MustMarshalArgs(),
+	}
+}
+
+func NewIntroduceOptionsStructCommand(title string, a0 protocol.Location) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   IntroduceOptionsStruct.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewListImportsCommand(title string, a0 URIArg) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -530,6 +698,14 @@ func NewModulesCommand(title string, a0 ModulesArgs) *protocol.Command {
 	}
 }
 
+func NewNewModuleCommand(title string, a0 NewModuleArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   NewModule.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewPackagesCommand(title string, a0 PackagesArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -562,6 +738,30 @@ func NewResetGoModDiagnosticsCommand(title string, a0 ResetGoModDiagnosticsArgs)
 	}
 }
 
+func NewResyncDriverCommand(title string, a0 URIArg) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ResyncDriver.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewRunCustomCodeActionCommand(title string, a0 RunCustomCodeActionArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   RunCustomCodeAction.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewRunFuzzCommand(title string, a0 RunFuzzArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   RunFuzz.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewRunGoWorkCommandCommand(title string, a0 RunGoWorkArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -594,6 +794,22 @@ func NewScanImportsCommand(title string) *protocol.Command {
 	}
 }
 
+func NewSetViewEnvironmentCommand(title string, a0 SetViewEnvironmentArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   SetViewEnvironment.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewShowGeneratorCommand(title string, a0 protocol.Location) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   ShowGenerator.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewStartDebuggingCommand(title string, a0 DebuggingArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
@@ -626,6 +842,22 @@ func NewTidyCommand(title string, a0 URIArgs) *protocol.Command {
 	}
 }
 
+func NewUnusedExportsCommand(title string, a0 UnusedExportsArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   UnusedExports.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
+func NewUpdateExampleOutputCommand(title string, a0 UpdateExampleOutputArgs) *protocol.Command {
+	return &protocol.Command{
+		Title:     title,
+		Command:   UpdateExampleOutput.String(),
+		Arguments: MustMarshalArgs(a0),
+	}
+}
+
 func NewUpdateGoSumCommand(title string, a0 URIArgs) *protocol.Command {
 	return &protocol.Command{
 		Title:     title,
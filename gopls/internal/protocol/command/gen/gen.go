@@ -128,6 +128,8 @@ func Generate() ([]byte, error) {
 					return false
 				case *types.Slice:
 					return fallible(t.Elem())
+				case *types.Map:
+					return fallible(t.Key()) || fallible(t.Elem())
 				case *types.Struct:
 					for i := 0; i < t.NumFields(); i++ {
 						if fallible(t.Field(i).Type()) {
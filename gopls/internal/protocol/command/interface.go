@@ -54,11 +54,75 @@ type Interface interface {
 	// This command is asynchronous; clients must wait for the 'end' progress notification.
 	RunTests(context.Context, RunTestsArgs) error
 
+	// UpdateExampleOutput: Update the output comment of an example
+	//
+	// Runs `go test` for an Example function and replaces its
+	// "Output:" comment by the output the example actually printed.
+	// It fails if the example did not run to completion.
+	UpdateExampleOutput(context.Context, UpdateExampleOutputArgs) error
+
+	// RunFuzz: Run a fuzz test
+	//
+	// Runs `go test -fuzz` for a fuzz target, for a bounded
+	// duration. Its output, including new interesting inputs and
+	// any failure, is streamed as progress notifications. If the
+	// fuzzer finds a failing input, the file in which `go test`
+	// recorded it is opened in the editor.
+	//
+	// This command is asynchronous; clients must wait for the 'end' progress notification.
+	RunFuzz(context.Context, RunFuzzArgs) error
+
+	// FuzzCorpus: List the corpus of a fuzz test
+	//
+	// Lists the entries of the corpus of a fuzz target: the seed
+	// corpus in the package's testdata/fuzz directory, including
+	// failing inputs, and the corpus generated by the fuzzer in the
+	// build cache. Each entry is decoded into the values of the
+	// arguments of the fuzz target.
+	//
+	// If ShowDocument is set, the client is also directed to open a
+	// report page in a browser, from which each entry may be opened
+	// in the editor.
+	FuzzCorpus(context.Context, FuzzCorpusArgs) (FuzzCorpusResult, error)
+
+	// CompareBenchmarks: Compare benchmarks
+	//
+	// Runs `go test -bench` for a set of benchmark functions twice,
+	// first on a base version of the package and then on its current
+	// version, including unsaved editor buffers, and reports the
+	// change in each measurement, in the manner of benchstat.
+	//
+	// The base version is the package as of the git revision Ref,
+	// checked out in a temporary worktree, or, if Ref is empty, the
+	// package as saved on disk, so that the effect of unsaved changes
+	// can be measured.
+	//
+	// If ShowDocument is set, the client is also directed to open the
+	// report in a browser.
+	CompareBenchmarks(context.Context, CompareBenchmarksArgs) (CompareBenchmarksResult, error)
+
 	// Generate: Run go generate
 	//
-	// Runs `go generate` for a given directory.
+	// Runs `go generate` for a given directory, or for a single
+	// //go:generate directive. The output of the generators is
+	// reported as progress, and cancelling the progress stops them.
+	//
+	// In preview mode, the changes that the generators make to files
+	// in the directory are sent to the client as a workspace edit,
+	// and the files on disk are restored, so that the changes may be
+	// reviewed before they are saved.
 	Generate(context.Context, GenerateArgs) error
 
+	// RunCustomCodeAction: Run a custom code action
+	//
+	// Runs the command of a code action defined by the file named by
+	// the customCodeActionsFile setting. The command is looked up by
+	// title in that file, so clients cannot cause gopls to run
+	// arbitrary commands. Its output either replaces the content of
+	// the file, if the action's applyEdits field is set, or is
+	// displayed as a message.
+	RunCustomCodeAction(context.Context, RunCustomCodeActionArgs) error
+
 	// Doc: Browse package documentation.
 	//
 	// Opens the Go package documentation page for the current
@@ -70,6 +134,15 @@ type Interface interface {
 	// Regenerates cgo definitions.
 	RegenerateCgo(context.Context, URIArg) error
 
+	// ResyncDriver: Reload the workspace from the packages driver
+	//
+	// Discards the package information of the view containing the URI
+	// and reloads its workspace, by querying the go/packages driver
+	// (GOPACKAGESDRIVER) or the go command. This is useful after a
+	// change to the build configuration that gopls cannot observe,
+	// such as a change to a Bazel BUILD file.
+	ResyncDriver(context.Context, URIArg) error
+
 	// Tidy: Run go mod tidy
 	//
 	// Runs `go mod tidy` for a module.
@@ -90,6 +163,13 @@ type Interface interface {
 	// Updates the go.sum file for a module.
 	UpdateGoSum(context.Context, URIArgs) error
 
+	// DownloadGoSum: Download modules and update go.sum
+	//
+	// Runs `go mod download` for the specified modules, adding their
+	// missing entries to the go.sum file of a module. Unlike
+	// UpdateGoSum, it does not consider the rest of the module graph.
+	DownloadGoSum(context.Context, DownloadGoSumArgs) error
+
 	// CheckUpgrades: Check for upgrades
 	//
 	// Checks for module upgrades.
@@ -216,6 +296,25 @@ type Interface interface {
 	// command.
 	WorkspaceStats(context.Context) (WorkspaceStatsResult, error)
 
+	// DriverStats: Fetch go/packages driver statistics
+	//
+	// Report, for each external go/packages driver (GOPACKAGESDRIVER)
+	// used by the session, its version, the latency of its queries,
+	// and its most recent errors.
+	//
+	// This command is intended for use by the gopls stats command.
+	DriverStats(context.Context) (DriverStatsResult, error)
+
+	// IndexStatus: Report the status of the workspace index
+	//
+	// Report, for each view, the state of the most recent pass that
+	// loads, type-checks, and indexes its workspace packages: the
+	// number of packages loaded, type-checked, and indexed, and the
+	// number whose analysis is pending. Editors may use it to show
+	// an "indexing" indicator, explaining why results such as
+	// references may be incomplete.
+	IndexStatus(context.Context) (IndexStatusResult, error)
+
 	// RunGoWorkCommand: Run `go work [args...]`, and apply the resulting go.work
 	// edits to the current go.work file
 	RunGoWorkCommand(context.Context, RunGoWorkArgs) error
@@ -229,6 +328,22 @@ type Interface interface {
 	// AddTest: add test for the selected function
 	AddTest(context.Context, protocol.Location) (*protocol.WorkspaceEdit, error)
 
+	// GenerateConversion: generate the body of a struct conversion function
+	//
+	// Generates, or regenerates, the body of the function declared at
+	// the location, whose signature func(x X) Y (or func (x X) M() Y)
+	// specifies a conversion between the struct types X and Y, by
+	// mapping each field of Y from the field of X with the same name.
+	GenerateConversion(context.Context, protocol.Location) (*protocol.WorkspaceEdit, error)
+
+	// IntroduceOptionsStruct: move function parameters into a struct
+	//
+	// Replaces the parameters of the function declared at the
+	// location, other than a leading context.Context, by a single
+	// parameter of a new struct type with a field for each of them,
+	// and updates all calls to construct the struct.
+	IntroduceOptionsStruct(context.Context, protocol.Location) (*protocol.WorkspaceEdit, error)
+
 	// MaybePromptForTelemetry: Prompt user to enable telemetry
 	//
 	// Checks for the right conditions, and then prompts the user
@@ -252,6 +367,20 @@ type Interface interface {
 	// This command is intended for use by gopls tests only.
 	Views(context.Context) ([]View, error)
 
+	// SetViewEnvironment: Override the build environment of a workspace folder
+	//
+	// This command overrides, for the rest of the session, the
+	// environment variables (such as GOOS, GOARCH, and GOFLAGS) and
+	// the build tags with which the views of the workspace folder
+	// containing the specified URI are built, taking precedence over
+	// the folder's "env" and "buildFlags" settings. The views are
+	// rebuilt with the new configuration, so that editors can offer
+	// a switcher between build configurations.
+	//
+	// Each call replaces the previous overrides for the folder; a call
+	// with no Env and no BuildTags restores the folder's settings.
+	SetViewEnvironment(context.Context, SetViewEnvironmentArgs) error
+
 	// FreeSymbols: Browse free symbols referenced by the selection in a browser.
 	//
 	// This command is a query over a selected range of Go source
@@ -270,6 +399,38 @@ type Interface interface {
 	// The machine architecture is determined by the view.
 	Assembly(_ context.Context, viewID, packageID, symbol string) error
 
+	// UnusedExports: List exported symbols unused by other workspace packages
+	//
+	// This command reports the exported package-level symbols of
+	// workspace packages that are not referenced from any other
+	// package in the workspace, according to the cross-reference
+	// index. Unlike the unusedfunc analyzer, which considers only
+	// unexported symbols of one package at a time, this query
+	// considers the entire workspace of the view containing URI.
+	//
+	// If ShowDocument is set, the client is also directed to open a
+	// report page in a browser.
+	UnusedExports(context.Context, UnusedExportsArgs) (UnusedExportsResult, error)
+
+	// NewModule: Create a new module from a template
+	//
+	// This command creates a new module by copying a template module,
+	// in the manner of golang.org/x/tools/cmd/gonew: the module path
+	// and the imports of its packages are changed to the new path,
+	// template parameters are substituted, and post-generate hooks
+	// are run in the new module's directory.
+	//
+	// If ShowDocument is set, the client is also directed to open the
+	// new module's go.mod file.
+	NewModule(context.Context, NewModuleArgs) (NewModuleResult, error)
+
+	// ShowGenerator: Show the go:generate directive of a generated file
+	//
+	// This command directs the client to open the file containing
+	// the //go:generate directive that generates a file, and to
+	// select the directive, at the specified location.
+	ShowGenerator(context.Context, protocol.Location) error
+
 	// ClientOpenURL: Request that the client open a URL in a browser.
 	ClientOpenURL(_ context.Context, url string) error
 
@@ -307,12 +468,128 @@ type RunTestsArgs struct {
 	Benchmarks []string
 }
 
+type UpdateExampleOutputArgs struct {
+	// The test file containing the example.
+	URI protocol.DocumentURI
+
+	// The name of the Example function, e.g. ExampleFoo.
+	Example string
+}
+
+type RunFuzzArgs struct {
+	// The test file containing the fuzz test.
+	URI protocol.DocumentURI
+
+	// The name of the fuzz test, e.g. FuzzFoo.
+	Fuzz string
+
+	// The time to spend fuzzing, as a duration such as "30s",
+	// or a number of iterations such as "1000x" (see `go help
+	// testflag`). The default is 30s.
+	FuzzTime string
+}
+
+type CompareBenchmarksArgs struct {
+	// The test file containing the benchmarks.
+	URI protocol.DocumentURI
+
+	// The names of the benchmarks to run, e.g. BenchmarkFoo.
+	Benchmarks []string
+
+	// The git revision of the base version, such as "HEAD" or a
+	// branch name. If empty, the base version is the saved state of
+	// the files whose unsaved changes are being measured.
+	Ref string
+
+	// The number of times to run each benchmark in each version
+	// (the -count flag of go test). The default is 5.
+	Count int
+
+	// In addition to returning the URL of the report, send showDocument.
+	ShowDocument bool
+}
+
+type CompareBenchmarksResult struct {
+	// Deltas holds the change of each measurement of each benchmark,
+	// in order of appearance in the output of go test.
+	Deltas []BenchmarkDelta
+
+	// URL is the address of the web page of the report.
+	URL protocol.URI
+}
+
+// A BenchmarkDelta describes the change in one measurement (such as
+// time per operation) of one benchmark between two versions.
+type BenchmarkDelta struct {
+	Name string // the benchmark name, as reported by go test, e.g. BenchmarkFoo-8
+	Unit string // the unit of measurement, e.g. "ns/op"
+
+	// Old and New hold the median of the measurements
+	// of the base and current versions.
+	Old, New float64
+
+	// Percent is the change from Old to New, as a percentage.
+	// It is zero if Old is zero.
+	Percent float64
+
+	// Significant reports whether the change exceeds the
+	// variation among measurements of each version.
+	Significant bool
+}
+
+type FuzzCorpusArgs struct {
+	URI          protocol.DocumentURI // the test file containing the fuzz test
+	Fuzz         string               // the name of the fuzz test, e.g. FuzzFoo
+	ShowDocument bool                 // in addition to returning the URL, send showDocument
+}
+
+type FuzzCorpusResult struct {
+	// Entries lists the corpus entries, seeds first,
+	// each group ordered by file name.
+	Entries []FuzzCorpusEntry
+
+	// URL is the address of the web page of the report.
+	URL protocol.URI
+}
+
+// A FuzzCorpusEntry describes a file of the corpus of a fuzz test.
+type FuzzCorpusEntry struct {
+	URI  protocol.DocumentURI // the corpus file
+	Seed bool                 // file is in the seed corpus (testdata/fuzz), not the build cache
+
+	// Values holds the values of the arguments of the fuzz
+	// target, as Go expressions such as []byte("abc") or int(1).
+	Values []string
+
+	// Error describes why the file could not be decoded, if so.
+	Error string `json:",omitempty"`
+}
+
 type GenerateArgs struct {
 	// URI for the directory to generate.
 	Dir protocol.DocumentURI
 
 	// Whether to generate recursively (go generate ./...)
 	Recursive bool
+
+	// File and Directive, if set, restrict generation to the
+	// //go:generate directives in File, within Dir, whose text (such
+	// as "//go:generate stringer -type=T") is Directive.
+	File      protocol.DocumentURI `json:",omitempty"`
+	Directive string               `json:",omitempty"`
+
+	// Preview, if set, causes the changes to files in Dir to be
+	// applied as edits in the client instead of on disk.
+	// It cannot be combined with Recursive.
+	Preview bool `json:",omitempty"`
+}
+
+type RunCustomCodeActionArgs struct {
+	// The file and selection to which the action applies.
+	Location protocol.Location
+
+	// The title of the action in the customCodeActionsFile.
+	Title string
 }
 
 type DocArgs struct {
@@ -320,6 +597,68 @@ type DocArgs struct {
 	ShowDocument bool // in addition to returning the URL, send showDocument
 }
 
+type UnusedExportsArgs struct {
+	URI          protocol.DocumentURI // a file or directory of the view's workspace
+	ShowDocument bool                 // in addition to returning the URL, send showDocument
+}
+
+type UnusedExportsResult struct {
+	// Symbols lists the unused exported symbols,
+	// ordered by package path and position.
+	Symbols []UnusedExport
+
+	// URL is the address of the web page of the report.
+	URL protocol.URI
+}
+
+// An UnusedExport describes an exported package-level symbol that is
+// not referenced from any other workspace package.
+type UnusedExport struct {
+	PkgPath  string            // package path of the declaring package
+	Name     string            // name of the symbol
+	Kind     string            // "const", "var", "func", or "type"
+	Location protocol.Location // location of the declaring identifier
+}
+
+type NewModuleArgs struct {
+	// Template is the path of the template module, optionally
+	// followed by @version. The default version is "latest".
+	Template string
+
+	// Module is the path of the new module.
+	// If empty, the template's module path is kept.
+	Module string
+
+	// Dir is the directory in which to create the new module.
+	// It must not exist or must be an empty directory.
+	Dir protocol.DocumentURI
+
+	// Vars maps the names of template parameters to their values,
+	// which replace each occurrence of {{gonew.name}} in the names
+	// and contents of the template's files. Parameters declared by
+	// the template's gonew.json manifest that are not set take their
+	// default values.
+	Vars map[string]string
+
+	// Steps are the names of post-processing steps declared by the
+	// template's gonew.json manifest to run in the new module's
+	// directory after it has been created, or ["all"].
+	Steps []string
+
+	// Hooks are commands, such as ["go", "mod", "tidy"], to run in
+	// order in the new module's directory after it has been created,
+	// and after any Steps.
+	Hooks [][]string
+
+	// ShowDocument directs the client to open the new go.mod file.
+	ShowDocument bool
+}
+
+type NewModuleResult struct {
+	// GoMod is the URI of the new module's go.mod file.
+	GoMod protocol.DocumentURI
+}
+
 // TODO(rFindley): document the rest of these once the docgen is fleshed out.
 
 type ApplyFixArgs struct {
@@ -375,6 +714,10 @@ type RemoveDependencyArgs struct {
 	// run `go get module@none`, and then run `go mod tidy`. Otherwise, we
 	// must make textual edits.
 	OnlyDiagnostic bool
+	// If Tidy is set, the requirement is dropped from the go.mod file
+	// and then `go mod tidy` is run, which restores it as an indirect
+	// requirement if it is still needed by another dependency.
+	Tidy bool
 }
 
 type EditGoDirectiveArgs struct {
@@ -384,6 +727,13 @@ type EditGoDirectiveArgs struct {
 	Version string
 }
 
+type DownloadGoSumArgs struct {
+	// The go.mod file URI.
+	URI protocol.DocumentURI
+	// The modules to download, in path@version or path form.
+	Modules []string
+}
+
 type GoGetPackageArgs struct {
 	// Any document URI within the relevant module.
 	URI protocol.DocumentURI
@@ -535,6 +885,53 @@ type WorkspaceStatsResult struct {
 	Views []ViewStats // stats for each view in the session
 }
 
+// DriverStatsResult holds statistics about the go/packages drivers used by
+// the session.
+type DriverStatsResult struct {
+	Drivers []DriverInfo
+}
+
+// DriverInfo holds statistics about the queries made to a go/packages
+// driver.
+type DriverInfo struct {
+	Driver      string        // path of the driver
+	Version     string        // module version of the driver binary, if known
+	Queries     int           // number of queries
+	Failures    int           // number of failed queries
+	MeanLatency string        // in time.Duration string form
+	MaxLatency  string        // in time.Duration string form
+	LastLatency string        // in time.Duration string form
+	Errors      []DriverError // most recent failures, oldest first
+}
+
+// DriverError describes a failed query to a go/packages driver.
+type DriverError struct {
+	Time  string   // in RFC 3339 form
+	View  string   // ID of the view that made the query
+	Query []string // query patterns
+	Error string   // error message, including the driver's stderr
+}
+
+// IndexStatusResult holds the status of the index of each view.
+type IndexStatusResult struct {
+	Views []IndexViewStatus
+}
+
+// IndexViewStatus describes the progress of building the index of
+// the workspace packages of a view.
+type IndexViewStatus struct {
+	View   string               // view ID
+	Folder protocol.DocumentURI // workspace folder associated with the view
+	// State is "loading" while the workspace packages are being
+	// loaded, "indexing" while they are being type-checked and
+	// analyzed, and "idle" once the pass is complete.
+	State           string
+	Packages        int // number of workspace packages loaded
+	TypeChecked     int // number of packages type-checked during the pass
+	Indexed         int // number of packages type-checked or loaded from the file cache
+	AnalysisPending int // number of packages whose analysis is not yet complete
+}
+
 // FileStats holds information about a set of files.
 type FileStats struct {
 	Total   int // total number of files
@@ -649,6 +1046,18 @@ type View struct {
 	EnvOverlay []string             // environment variable overrides
 }
 
+// SetViewEnvironmentArgs holds arguments for the SetViewEnvironment command.
+type SetViewEnvironmentArgs struct {
+	// URI is a file or directory in the workspace folder to configure.
+	URI protocol.DocumentURI
+	// Env holds environment variables, such as GOOS, GOARCH, or GOFLAGS,
+	// that override those of the folder's "env" setting.
+	Env map[string]string
+	// BuildTags, if non-nil, replaces the -tags flag of the folder's
+	// "buildFlags" setting. An empty list clears the build tags.
+	BuildTags []string
+}
+
 // PackagesArgs holds arguments for the Packages command.
 type PackagesArgs struct {
 	// Files is a list of files and directories whose associated
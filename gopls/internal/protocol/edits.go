@@ -102,6 +102,22 @@ func AsAnnotatedTextEdits(edits []TextEdit) []Or_TextDocumentEdit_edits_Elem {
 	return result
 }
 
+// AnnotateTextEdits converts a slice of TextEdits to a slice of
+// Or_TextDocumentEdit_edits_Elem, each an AnnotatedTextEdit that refers
+// to the specified change annotation.
+func AnnotateTextEdits(edits []TextEdit, id ChangeAnnotationIdentifier) []Or_TextDocumentEdit_edits_Elem {
+	var result []Or_TextDocumentEdit_edits_Elem
+	for _, e := range edits {
+		result = append(result, Or_TextDocumentEdit_edits_Elem{
+			Value: AnnotatedTextEdit{
+				AnnotationID: &id,
+				TextEdit:     e,
+			},
+		})
+	}
+	return result
+}
+
 // fileHandle abstracts file.Handle to avoid a cycle.
 type fileHandle interface {
 	URI() DocumentURI
@@ -216,45 +216,15 @@ func req2to1(req2 *jsonrpc2_v2.Request) jsonrpc2.Request {
 	return req1
 }
 
+// Handlers returns the handler for an LSP connection, which handles
+// each request in order, in its own goroutine (see [jsonrpc2.Queue]),
+// and implements $/cancelRequest.
 func Handlers(handler jsonrpc2.Handler) jsonrpc2.Handler {
-	return CancelHandler(
-		jsonrpc2.AsyncHandler(
-			jsonrpc2.MustReplyHandler(handler)))
-}
-
-func CancelHandler(handler jsonrpc2.Handler) jsonrpc2.Handler {
-	handler, canceller := jsonrpc2.CancelHandler(handler)
-	return func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
-		if req.Method() != "$/cancelRequest" {
-			// TODO(iancottrell): See if we can generate a reply for the request to be cancelled
-			// at the point of cancellation rather than waiting for gopls to naturally reply.
-			// To do that, we need to keep track of whether a reply has been sent already and
-			// be careful about racing between the two paths.
-			// TODO(iancottrell): Add a test that watches the stream and verifies the response
-			// for the cancelled request flows.
-			replyWithDetachedContext := func(ctx context.Context, resp any, err error) error {
-				// https://microsoft.github.io/language-server-protocol/specifications/specification-current/#cancelRequest
-				if ctx.Err() != nil && err == nil {
-					err = RequestCancelledError
-				}
-				ctx = xcontext.Detach(ctx)
-				return reply(ctx, resp, err)
-			}
-			return handler(ctx, replyWithDetachedContext, req)
-		}
-		var params CancelParams
-		if err := UnmarshalJSON(req.Params(), &params); err != nil {
-			return sendParseError(ctx, reply, err)
-		}
-		if n, ok := params.ID.(float64); ok {
-			canceller(jsonrpc2.NewIntID(int64(n)))
-		} else if s, ok := params.ID.(string); ok {
-			canceller(jsonrpc2.NewStringID(s))
-		} else {
-			return sendParseError(ctx, reply, fmt.Errorf("request ID %v malformed", params.ID))
-		}
-		return reply(ctx, nil, nil)
-	}
+	q := jsonrpc2.NewQueue(jsonrpc2.MustReplyHandler(handler), jsonrpc2.QueueOptions{
+		CancelMethod: "$/cancelRequest",
+		Cancelled:    RequestCancelledError,
+	})
+	return q.Handle
 }
 
 func Call(ctx context.Context, conn jsonrpc2.Conn, method string, params any, result any) error {
@@ -0,0 +1,87 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+// This file defines the change annotations of workspace edits.
+
+import (
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// Change annotations identify classes of edits within a workspace
+// edit that the user may wish to review before they are applied, as
+// they are more likely than the others to be unwanted.
+//
+// Clients that honor change annotations (see
+// [settings.ClientOptions.RenameChangeAnnotationsSupported] and
+// [settings.ClientOptions.CodeActionChangeAnnotationsSupported]) may
+// present the edits of each class separately, and ask the user to
+// confirm those that need confirmation, for example in a preview
+// that allows each of them to be deselected.
+const (
+	// renameInCommentsAndStrings annotates the edits of a rename in
+	// comments and string literals, which may refer to something
+	// else with the same name.
+	renameInCommentsAndStrings protocol.ChangeAnnotationIdentifier = "renameInCommentsAndStrings"
+
+	// movePackageDirectory annotates the renaming of the directory of
+	// a renamed package, which changes its import path.
+	movePackageDirectory protocol.ChangeAnnotationIdentifier = "movePackageDirectory"
+
+	// deletionCascade annotates the deletions of a fix, other than that
+	// of the diagnosed code, such as the declarations that become
+	// unused as a consequence of deleting an unused function.
+	deletionCascade protocol.ChangeAnnotationIdentifier = "deletionCascade"
+)
+
+// changeAnnotations maps each change annotation identifier to its
+// definition.
+var changeAnnotations = map[protocol.ChangeAnnotationIdentifier]protocol.ChangeAnnotation{
+	renameInCommentsAndStrings: {
+		Label:             "Rename in comments and strings",
+		Description:       "Occurrences of the old name that may refer to something else",
+		NeedsConfirmation: true,
+	},
+	movePackageDirectory: {
+		Label:             "Move package directory",
+		Description:       "Changes the import path of the package",
+		NeedsConfirmation: true,
+	},
+	deletionCascade: {
+		Label:             "Delete other code",
+		Description:       "Declarations that become unused as a result of the fix",
+		NeedsConfirmation: true,
+	},
+}
+
+// addChangeAnnotations adds to the workspace edit the definition of
+// each change annotation to which its document changes refer.
+func addChangeAnnotations(edit *protocol.WorkspaceEdit) {
+	add := func(id *protocol.ChangeAnnotationIdentifier) {
+		if id == nil {
+			return
+		}
+		if edit.ChangeAnnotations == nil {
+			edit.ChangeAnnotations = make(map[protocol.ChangeAnnotationIdentifier]protocol.ChangeAnnotation)
+		}
+		edit.ChangeAnnotations[*id] = changeAnnotations[*id]
+	}
+	for _, change := range edit.DocumentChanges {
+		switch {
+		case change.TextDocumentEdit != nil:
+			for _, e := range change.TextDocumentEdit.Edits {
+				if e, ok := e.Value.(protocol.AnnotatedTextEdit); ok {
+					add(e.AnnotationID)
+				}
+			}
+		case change.CreateFile != nil:
+			add(change.CreateFile.AnnotationID)
+		case change.RenameFile != nil:
+			add(change.RenameFile.AnnotationID)
+		case change.DeleteFile != nil:
+			add(change.DeleteFile.AnnotationID)
+		}
+	}
+}
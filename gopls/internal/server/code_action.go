@@ -161,6 +161,11 @@ func (s *server) CodeAction(ctx context.Context, params *protocol.CodeActionPara
 
 		return actions, nil
 
+	case file.Work, file.Sum:
+		// go.work and go.sum quick fixes (e.g. removing redundant use
+		// directives or unused entries) are all bundled with diagnostics.
+		return s.codeActionsMatchingDiagnostics(ctx, fh.URI(), snapshot, params.Context.Diagnostics, enabled)
+
 	case file.Go:
 		// diagnostic-bundled code actions
 		//
@@ -187,11 +192,15 @@ func (s *server) CodeAction(ctx context.Context, params *protocol.CodeActionPara
 		// queries, so we must list all kinds of queries here.)
 		if golang.IsGenerated(ctx, snapshot, uri) {
 			actions = slices.DeleteFunc(actions, func(a protocol.CodeAction) bool {
+				if a.Command != nil && a.Command.Command == command.Generate.String() {
+					return false // regenerates the file rather than editing it
+				}
 				switch a.Kind {
 				case settings.GoTest,
 					settings.GoDoc,
 					settings.GoFreeSymbols,
 					settings.GoAssembly,
+					settings.GoShowGenerator,
 					settings.GoplsDocFeatures,
 					settings.GoToggleCompilerOptDetails:
 					return false // read-only query
@@ -300,19 +309,33 @@ func codeActionsForDiagnostic(ctx context.Context, snapshot *cache.Snapshot, sd
 		if !enabled(fix.ActionKind) {
 			continue
 		}
+		// If the fix deletes the diagnosed code, its other deletions
+		// are a consequence of that one, and are annotated (if the
+		// client supports it) so that the user may review them.
+		cascade := snapshot.Options().CodeActionChangeAnnotationsSupported &&
+			deletesRange(fix.Edits[sd.URI], pd.Range)
 		var changes []protocol.DocumentChange
 		for uri, edits := range fix.Edits {
 			fh, err := snapshot.ReadFile(ctx, uri)
 			if err != nil {
 				return nil, err
 			}
-			change := protocol.DocumentChangeEdit(fh, edits)
+			change := protocol.DocumentChangeEdit(fh, nil)
+			for _, edit := range edits {
+				if cascade && edit.NewText == "" && !(uri == sd.URI && protocol.Intersect(edit.Range, pd.Range)) {
+					change.TextDocumentEdit.Edits = append(change.TextDocumentEdit.Edits, protocol.AnnotateTextEdits([]protocol.TextEdit{edit}, deletionCascade)...)
+				} else {
+					change.TextDocumentEdit.Edits = append(change.TextDocumentEdit.Edits, protocol.AsAnnotatedTextEdits([]protocol.TextEdit{edit})...)
+				}
+			}
 			changes = append(changes, change)
 		}
+		edit := protocol.NewWorkspaceEdit(changes...)
+		addChangeAnnotations(edit)
 		actions = append(actions, protocol.CodeAction{
 			Title:       fix.Title,
 			Kind:        fix.ActionKind,
-			Edit:        protocol.NewWorkspaceEdit(changes...),
+			Edit:        edit,
 			Command:     fix.Command,
 			Diagnostics: []protocol.Diagnostic{*pd},
 		})
@@ -320,6 +343,18 @@ func codeActionsForDiagnostic(ctx context.Context, snapshot *cache.Snapshot, sd
 	return actions, nil
 }
 
+// deletesRange reports whether one of the edits deletes the range rng.
+func deletesRange(edits []protocol.TextEdit, rng protocol.Range) bool {
+	for _, edit := range edits {
+		if edit.NewText == "" &&
+			protocol.ComparePosition(edit.Range.Start, rng.Start) <= 0 &&
+			protocol.ComparePosition(rng.End, edit.Range.End) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *server) findMatchingDiagnostics(uri protocol.DocumentURI, pd protocol.Diagnostic) []*cache.Diagnostic {
 	s.diagnosticsMu.Lock()
 	defer s.diagnosticsMu.Unlock()
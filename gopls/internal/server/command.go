@@ -13,6 +13,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -21,6 +22,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/telemetry/counter"
@@ -36,11 +38,13 @@ import (
 	"golang.org/x/tools/gopls/internal/protocol/command"
 	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/gopls/internal/util/bug"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
 	"golang.org/x/tools/gopls/internal/vulncheck"
 	"golang.org/x/tools/gopls/internal/vulncheck/scan"
 	"golang.org/x/tools/internal/diff"
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/gocommand"
+	"golang.org/x/tools/internal/gonew"
 	"golang.org/x/tools/internal/jsonrpc2"
 	"golang.org/x/tools/internal/tokeninternal"
 	"golang.org/x/tools/internal/xcontext"
@@ -307,6 +311,38 @@ func (c *commandHandler) AddTest(ctx context.Context, loc protocol.Location) (*p
 	return result, err
 }
 
+func (c *commandHandler) GenerateConversion(ctx context.Context, loc protocol.Location) (*protocol.WorkspaceEdit, error) {
+	err := c.run(ctx, commandConfig{
+		forURI: loc.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		if deps.snapshot.FileKind(deps.fh) != file.Go {
+			return fmt.Errorf("can't generate conversion in non-Go file")
+		}
+		changes, err := golang.GenerateConversion(ctx, deps.snapshot, loc)
+		if err != nil {
+			return err
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return nil, err
+}
+
+func (c *commandHandler) IntroduceOptionsStruct(ctx context.Context, loc protocol.Location) (*protocol.WorkspaceEdit, error) {
+	err := c.run(ctx, commandConfig{
+		forURI: loc.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		if deps.snapshot.FileKind(deps.fh) != file.Go {
+			return fmt.Errorf("can't introduce options struct in non-Go file")
+		}
+		changes, err := golang.IntroduceOptionsStruct(ctx, deps.snapshot, loc)
+		if err != nil {
+			return err
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+	return nil, err
+}
+
 // commandConfig configures common command set-up and execution.
 type commandConfig struct {
 	requireSave bool                 // whether all files must be saved for the command to work
@@ -456,6 +492,40 @@ func (c *commandHandler) RegenerateCgo(ctx context.Context, args command.URIArg)
 	})
 }
 
+func (c *commandHandler) SetViewEnvironment(ctx context.Context, args command.SetViewEnvironmentArgs) error {
+	return c.run(ctx, commandConfig{
+		forURI:   args.URI,
+		progress: "Setting view environment",
+	}, func(ctx context.Context, deps commandDeps) error {
+		folder := deps.snapshot.View().Folder().Dir
+		c.s.optionsMu.Lock()
+		if len(args.Env) == 0 && args.BuildTags == nil {
+			delete(c.s.envOverrides, folder)
+		} else {
+			if c.s.envOverrides == nil {
+				c.s.envOverrides = make(map[protocol.DocumentURI]command.SetViewEnvironmentArgs)
+			}
+			c.s.envOverrides[folder] = args
+		}
+		c.s.optionsMu.Unlock()
+		return c.s.updateFolderOptions(ctx, FromSetViewEnvironment)
+	})
+}
+
+func (c *commandHandler) ResyncDriver(ctx context.Context, args command.URIArg) error {
+	return c.run(ctx, commandConfig{
+		progress: "Reloading workspace",
+	}, func(ctx context.Context, _ commandDeps) error {
+		return c.modifyState(ctx, FromResyncDriver, func() (*cache.Snapshot, func(), error) {
+			v, err := c.s.session.ResetView(ctx, args.URI)
+			if err != nil {
+				return nil, nil, err
+			}
+			return v.Snapshot()
+		})
+	})
+}
+
 // modifyState performs an operation that modifies the snapshot state.
 //
 // It causes a snapshot diagnosis for the provided ModificationSource.
@@ -562,6 +632,21 @@ func (c *commandHandler) UpdateGoSum(ctx context.Context, args command.URIArgs)
 	})
 }
 
+func (c *commandHandler) DownloadGoSum(ctx context.Context, args command.DownloadGoSumArgs) error {
+	return c.run(ctx, commandConfig{
+		progress: "Downloading modules",
+		forURI:   args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		// Only go.sum is affected, and only by the addition of
+		// entries, so the resulting file change invalidates just
+		// the packages that failed to load (see Snapshot.clone).
+		return c.s.runGoModUpdateCommands(ctx, deps.snapshot, args.URI, func(invoke func(...string) (*bytes.Buffer, error)) error {
+			_, err := invoke(append([]string{"mod", "download"}, args.Modules...)...)
+			return err
+		})
+	})
+}
+
 func (c *commandHandler) Tidy(ctx context.Context, args command.URIArgs) error {
 	return c.run(ctx, commandConfig{
 		progress: "Running go mod tidy",
@@ -636,6 +721,15 @@ func (c *commandHandler) RemoveDependency(ctx context.Context, args command.Remo
 		progress: "Removing dependency",
 		forURI:   args.URI,
 	}, func(ctx context.Context, deps commandDeps) error {
+		if args.Tidy {
+			return c.s.runGoModUpdateCommands(ctx, deps.snapshot, args.URI, func(invoke func(...string) (*bytes.Buffer, error)) error {
+				if _, err := invoke("mod", "edit", "-droprequire="+args.ModulePath); err != nil {
+					return err
+				}
+				_, err := invoke("mod", "tidy")
+				return err
+			})
+		}
 		// See the documentation for OnlyDiagnostic.
 		//
 		// TODO(rfindley): In Go 1.17+, we will be able to use the go command
@@ -812,9 +906,367 @@ func (c *commandHandler) runTests(ctx context.Context, snapshot *cache.Snapshot,
 	return nil
 }
 
+func (c *commandHandler) UpdateExampleOutput(ctx context.Context, args command.UpdateExampleOutputArgs) error {
+	return c.run(ctx, commandConfig{
+		progress:    "Running go test",
+		requireSave: true, // go test honors overlays, but tests themselves cannot
+		forURI:      args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		meta, err := golang.NarrowestMetadataForFile(ctx, deps.snapshot, args.URI)
+		if err != nil {
+			return err
+		}
+		inv, cleanupInvocation, err := deps.snapshot.GoCommandInvocation(cache.NoNetwork, args.URI.DirPath(), "test", []string{
+			string(meta.ForTest), "-count=1", "-vet=off", fmt.Sprintf("-run=^%s$", regexp.QuoteMeta(args.Example)),
+		})
+		if err != nil {
+			return err
+		}
+		defer cleanupInvocation()
+		var buf bytes.Buffer
+		if err := deps.snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, &buf, &buf); err == nil {
+			showMessage(ctx, c.s.client, protocol.Info, fmt.Sprintf("output of %s is up to date", args.Example))
+			return nil
+		} else if errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		output, err := golang.ExampleTestOutput(args.Example, buf.String())
+		if err != nil {
+			return err
+		}
+		changes, err := golang.UpdateExampleOutput(ctx, deps.snapshot, deps.fh, args.Example, output)
+		if err != nil {
+			return err
+		}
+		return applyChanges(ctx, c.s.client, changes)
+	})
+}
+
+func (c *commandHandler) RunFuzz(ctx context.Context, args command.RunFuzzArgs) error {
+	return c.run(ctx, commandConfig{
+		progress:    "Running go test -fuzz", // (asynchronous)
+		requireSave: true,                    // go test honors overlays, but tests themselves cannot
+		forURI:      args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		jsonrpc2.Async(ctx) // don't block RPCs behind this command, since it can take a while
+		return c.runFuzz(ctx, deps.snapshot, deps.work, args)
+	})
+}
+
+// failingInputRe matches the line of the output of "go test -fuzz"
+// that reports the (package-relative) name of the file to which the
+// fuzzer wrote a failing input.
+var failingInputRe = regexp.MustCompile(`Failing input written to (\S+)`)
+
+func (c *commandHandler) runFuzz(ctx context.Context, snapshot *cache.Snapshot, work *progress.WorkDone, args command.RunFuzzArgs) error {
+	meta, err := golang.NarrowestMetadataForFile(ctx, snapshot, args.URI)
+	if err != nil {
+		return err
+	}
+	pkgPath := string(meta.ForTest)
+	fuzztime := args.FuzzTime
+	if fuzztime == "" {
+		fuzztime = "30s"
+	}
+
+	// create output
+	buf := &bytes.Buffer{}
+	ew := progress.NewEventWriter(ctx, "fuzz")
+	out := io.MultiWriter(ew, progress.NewWorkDoneWriter(ctx, work), buf)
+
+	inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, args.URI.DirPath(), "test", []string{
+		pkgPath, "-run=^$", fmt.Sprintf("-fuzz=^%s$", regexp.QuoteMeta(args.Fuzz)), "-fuzztime=" + fuzztime,
+	})
+	if err != nil {
+		return err
+	}
+	defer cleanupInvocation()
+	err = snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, out, out)
+	if err == nil {
+		showMessage(ctx, c.s.client, protocol.Info, fmt.Sprintf("%s found no failures in %s", args.Fuzz, fuzztime))
+		return nil
+	}
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	// Open the failing input, if any.
+	message := fmt.Sprintf("%s failed", args.Fuzz)
+	if m := failingInputRe.FindSubmatch(buf.Bytes()); m != nil {
+		filename := string(m[1])
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(args.URI.DirPath(), filename)
+		}
+		openClientEditor(ctx, c.s.client, protocol.Location{URI: protocol.URIFromPath(filename)}, c.s.Options())
+		message += fmt.Sprintf("; failing input written to %s", filename)
+	}
+	showMessage(ctx, c.s.client, protocol.Info, message+"\n"+buf.String())
+	return errors.New("gopls.run_fuzz command failed")
+}
+
+func (c *commandHandler) FuzzCorpus(ctx context.Context, args command.FuzzCorpusArgs) (command.FuzzCorpusResult, error) {
+	var result command.FuzzCorpusResult
+	err := c.run(ctx, commandConfig{
+		forURI: args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		mp, err := golang.NarrowestMetadataForFile(ctx, deps.snapshot, args.URI)
+		if err != nil {
+			return err
+		}
+		entries, err := golang.FuzzCorpus(ctx, deps.snapshot, mp, args.Fuzz)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			entry := command.FuzzCorpusEntry{
+				URI:    protocol.URIFromPath(e.Filename),
+				Seed:   e.Seed,
+				Values: e.Values,
+			}
+			if e.Err != nil {
+				entry.Error = e.Err.Error()
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+
+		// Start web server.
+		web, err := c.s.getWeb()
+		if err != nil {
+			return err
+		}
+		result.URL = web.fuzzCorpusURL(deps.snapshot.View().ID(), args.URI, args.Fuzz)
+		if args.ShowDocument {
+			openClientBrowser(ctx, c.s.client, "Fuzz corpus", result.URL, c.s.Options())
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (c *commandHandler) CompareBenchmarks(ctx context.Context, args command.CompareBenchmarksArgs) (command.CompareBenchmarksResult, error) {
+	var result command.CompareBenchmarksResult
+	err := c.run(ctx, commandConfig{
+		progress: "Comparing benchmarks", // (asynchronous)
+		forURI:   args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		jsonrpc2.Async(ctx) // don't block RPCs behind this command, since it can take a while
+		cmp, err := c.compareBenchmarks(ctx, deps.snapshot, deps.work, args)
+		if err != nil {
+			return err
+		}
+		for _, d := range cmp.Deltas {
+			delta := command.BenchmarkDelta{
+				Name:        d.Name,
+				Unit:        d.Unit,
+				Old:         d.Old.Median,
+				New:         d.New.Median,
+				Significant: d.Significant(),
+			}
+			if d.Old.Median != 0 {
+				delta.Percent = d.Percent()
+			}
+			result.Deltas = append(result.Deltas, delta)
+		}
+
+		// Start web server.
+		web, err := c.s.getWeb()
+		if err != nil {
+			return err
+		}
+		result.URL = web.benchmarksURL(web.addBenchmarkComparison(cmp))
+		if args.ShowDocument {
+			openClientBrowser(ctx, c.s.client, "Benchmarks", result.URL, c.s.Options())
+		}
+		return nil
+	})
+	return result, err
+}
+
+// compareBenchmarks runs the specified benchmarks on the base and
+// current versions of the package of args.URI.
+func (c *commandHandler) compareBenchmarks(ctx context.Context, snapshot *cache.Snapshot, work *progress.WorkDone, args command.CompareBenchmarksArgs) (*golang.BenchmarkComparison, error) {
+	if len(args.Benchmarks) == 0 {
+		return nil, errors.New("no benchmarks were provided")
+	}
+	meta, err := golang.NarrowestMetadataForFile(ctx, snapshot, args.URI)
+	if err != nil {
+		return nil, err
+	}
+	count := args.Count
+	if count <= 0 {
+		count = 5
+	}
+	names := make([]string, len(args.Benchmarks))
+	for i, name := range args.Benchmarks {
+		names[i] = regexp.QuoteMeta(name)
+	}
+
+	ew := progress.NewEventWriter(ctx, "benchmark")
+	wdw := progress.NewWorkDoneWriter(ctx, work)
+
+	// runBenchmarks runs the benchmarks on the package in dir,
+	// honoring unsaved editor buffers if overlays is set.
+	runBenchmarks := func(dir, pkg string, overlays bool) ([]byte, error) {
+		inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, dir, "test", []string{
+			pkg, "-run=^$", fmt.Sprintf("-bench=^(%s)$", strings.Join(names, "|")),
+			"-benchmem", fmt.Sprintf("-count=%d", count),
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer cleanupInvocation()
+		if !overlays {
+			inv.Overlay = ""
+		}
+		buf := &bytes.Buffer{}
+		out := io.MultiWriter(ew, wdw, buf)
+		if err := snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, out, out); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%v\n%s", err, buf)
+		}
+		return buf.Bytes(), nil
+	}
+
+	dir := args.URI.DirPath()
+	cmp := &golang.BenchmarkComparison{
+		PkgPath: meta.ForTest,
+		Old:     args.Ref,
+		New:     "current",
+	}
+	var oldOutput []byte
+	if args.Ref == "" {
+		// Compare the saved files with the unsaved buffers.
+		cmp.Old, cmp.New = "saved", "unsaved"
+		oldOutput, err = runBenchmarks(dir, string(meta.ForTest), false)
+	} else {
+		var (
+			olddir  string
+			cleanup func()
+		)
+		olddir, cleanup, err = gitWorktree(ctx, dir, args.Ref)
+		if err == nil {
+			defer cleanup()
+			oldOutput, err = runBenchmarks(olddir, ".", false)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("running benchmarks on %s version: %v", cmp.Old, err)
+	}
+	newOutput, err := runBenchmarks(dir, string(meta.ForTest), true)
+	if err != nil {
+		return nil, fmt.Errorf("running benchmarks on %s version: %v", cmp.New, err)
+	}
+	cmp.Deltas = golang.CompareBenchmarks(oldOutput, newOutput)
+	return cmp, nil
+}
+
+// gitWorktree checks out the git revision ref in a temporary
+// worktree of the repository that contains dir, and returns the
+// directory of the worktree that corresponds to dir. On success, the
+// caller must call the cleanup function to delete the worktree.
+func gitWorktree(ctx context.Context, dir, ref string) (_ string, cleanup func(), _ error) {
+	top, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	if realdir, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = realdir // git reports the real path of the top directory
+	}
+	rel, err := filepath.Rel(top, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	tmpdir, err := os.MkdirTemp("", "gopls-worktree-")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := runGit(ctx, top, "worktree", "add", "--detach", tmpdir, ref); err != nil {
+		os.RemoveAll(tmpdir)
+		return "", nil, err
+	}
+	cleanup = func() {
+		runGit(context.Background(), top, "worktree", "remove", "--force", tmpdir)
+		os.RemoveAll(tmpdir)
+	}
+	return filepath.Join(tmpdir, rel), cleanup, nil
+}
+
+// runGit runs a git command in the specified directory and returns
+// its output, without the trailing newline.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (c *commandHandler) RunCustomCodeAction(ctx context.Context, args command.RunCustomCodeActionArgs) error {
+	return c.run(ctx, commandConfig{
+		progress:    "Running " + args.Title,
+		requireSave: true, // the command reads the files on disk
+		forURI:      args.Location.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		// Look up the command by title, so that only the commands of
+		// the configuration file chosen by the user may be run.
+		actions, err := golang.CustomCodeActions(ctx, deps.snapshot)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(actions, func(a golang.CustomCodeAction) bool { return a.Title == args.Title })
+		if i < 0 || !actions[i].AppliesTo(deps.snapshot.Folder(), args.Location.URI) {
+			return fmt.Errorf("no custom code action %q for %s", args.Title, filepath.Base(args.Location.URI.Path()))
+		}
+		action := actions[i]
+
+		argv := action.CommandLine(args.Location)
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Dir = deps.snapshot.Folder().Path()
+		cmd.Env = append(os.Environ(), deps.snapshot.Options().EnvSlice()...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("%s: %v: %s", args.Title, err, bytes.TrimSpace(stderr.Bytes()))
+		}
+
+		if !action.ApplyEdits {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				showMessage(ctx, c.s.client, protocol.Info, msg)
+			}
+			return nil
+		}
+		content, err := deps.fh.Content()
+		if err != nil {
+			return err
+		}
+		edits, err := protocol.EditsFromDiffEdits(protocol.NewMapper(deps.fh.URI(), content), diff.Bytes(content, out))
+		if err != nil {
+			return err
+		}
+		return applyChanges(ctx, c.s.client, []protocol.DocumentChange{protocol.DocumentChangeEdit(deps.fh, edits)})
+	})
+}
+
 func (c *commandHandler) Generate(ctx context.Context, args command.GenerateArgs) error {
+	if args.Preview && args.Recursive {
+		return fmt.Errorf("cannot preview recursive go generate")
+	}
+	if (args.File == "") != (args.Directive == "") {
+		return fmt.Errorf("File and Directive must be specified together")
+	}
 	title := "Running go generate ."
-	if args.Recursive {
+	switch {
+	case args.Directive != "":
+		title = "Running " + strings.TrimPrefix(args.Directive, "//go:generate ")
+	case args.Recursive:
 		title = "Running go generate ./..."
 	}
 	return c.run(ctx, commandConfig{
@@ -824,23 +1276,120 @@ func (c *commandHandler) Generate(ctx context.Context, args command.GenerateArgs
 	}, func(ctx context.Context, deps commandDeps) error {
 		er := progress.NewEventWriter(ctx, "generate")
 
-		pattern := "."
-		if args.Recursive {
-			pattern = "./..."
+		goArgs := []string{"-x"}
+		switch {
+		case args.Directive != "":
+			// A directive is selected by a regular expression
+			// matching its full text.
+			goArgs = append(goArgs, "-run", "^"+regexp.QuoteMeta(args.Directive)+"$", args.File.Path())
+		case args.Recursive:
+			goArgs = append(goArgs, "./...")
+		default:
+			goArgs = append(goArgs, ".")
+		}
+
+		var before map[string][]byte
+		if args.Preview {
+			var err error
+			if before, err = readDirFiles(args.Dir.Path()); err != nil {
+				return err
+			}
 		}
-		inv, cleanupInvocation, err := deps.snapshot.GoCommandInvocation(cache.NetworkOK, args.Dir.Path(), "generate", []string{"-x", pattern})
+
+		inv, cleanupInvocation, err := deps.snapshot.GoCommandInvocation(cache.NetworkOK, args.Dir.Path(), "generate", goArgs)
 		if err != nil {
 			return err
 		}
 		defer cleanupInvocation()
-		stderr := io.MultiWriter(er, progress.NewWorkDoneWriter(ctx, deps.work))
-		if err := deps.snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, er, stderr); err != nil {
+		work := progress.NewWorkDoneWriter(ctx, deps.work)
+		stdout := io.MultiWriter(er, work)
+		stderr := io.MultiWriter(er, work)
+		runErr := deps.snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, stdout, stderr)
+		if !args.Preview {
+			return runErr
+		}
+
+		// Even if generation failed or was cancelled, restore the
+		// files on disk and offer the changes made so far.
+		changes, err := previewGenerated(ctx, deps.snapshot, args.Dir.Path(), before)
+		if err != nil {
 			return err
 		}
-		return nil
+		if runErr != nil {
+			return runErr
+		}
+		return applyChanges(ctx, c.s.client, changes)
 	})
 }
 
+// readDirFiles returns the contents of the regular files in dir.
+func readDirFiles(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		files[filename] = data
+	}
+	return files, nil
+}
+
+// previewGenerated compares the regular files of dir with their
+// contents before generation, restores their previous state on disk,
+// and returns the changes as edits. Deleted files are restored but not
+// reported.
+func previewGenerated(ctx context.Context, snapshot *cache.Snapshot, dir string, before map[string][]byte) ([]protocol.DocumentChange, error) {
+	after, err := readDirFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var changes []protocol.DocumentChange
+	for _, filename := range moremaps.KeySlice(after) {
+		newContent := after[filename]
+		oldContent, existed := before[filename]
+		if existed && bytes.Equal(oldContent, newContent) {
+			continue
+		}
+		uri := protocol.URIFromPath(filename)
+		m := protocol.NewMapper(uri, oldContent)
+		edits, err := protocol.EditsFromDiffEdits(m, diff.Bytes(oldContent, newContent))
+		if err != nil {
+			return nil, err
+		}
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		if existed {
+			err = os.WriteFile(filename, oldContent, 0666)
+		} else {
+			err = os.Remove(filename)
+			changes = append(changes, protocol.DocumentChangeCreate(uri))
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, edits))
+	}
+	for filename, oldContent := range before {
+		if _, ok := after[filename]; !ok {
+			if err := os.WriteFile(filename, oldContent, 0666); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
 func (c *commandHandler) GoGetPackage(ctx context.Context, args command.GoGetPackageArgs) error {
 	return c.run(ctx, commandConfig{
 		forURI:   args.URI,
@@ -1378,6 +1927,33 @@ func (c *commandHandler) WorkspaceStats(ctx context.Context) (command.WorkspaceS
 	return res, nil
 }
 
+// DriverStats implements the DriverStats command, reporting statistics
+// about the go/packages drivers used by the current session.
+func (c *commandHandler) DriverStats(ctx context.Context) (command.DriverStatsResult, error) {
+	var res command.DriverStatsResult
+	for _, stats := range c.s.session.DriverStats() {
+		ds := command.DriverInfo{
+			Driver:      stats.Driver,
+			Version:     stats.Version,
+			Queries:     stats.Queries,
+			Failures:    stats.Failures,
+			MeanLatency: fmt.Sprint(stats.MeanLatency()),
+			MaxLatency:  fmt.Sprint(stats.MaxLatency),
+			LastLatency: fmt.Sprint(stats.LastLatency),
+		}
+		for _, err := range stats.Errors {
+			ds.Errors = append(ds.Errors, command.DriverError{
+				Time:  err.Time.Format(time.RFC3339),
+				View:  err.View,
+				Query: err.Query,
+				Error: err.Err,
+			})
+		}
+		res.Drivers = append(res.Drivers, ds)
+	}
+	return res, nil
+}
+
 func collectViewStats(ctx context.Context, view *cache.View) (command.ViewStats, error) {
 	s, release, err := view.Snapshot()
 	if err != nil {
@@ -1402,7 +1978,7 @@ func collectViewStats(ctx context.Context, view *cache.View) (command.ViewStats,
 		ids = append(ids, mp.ID)
 	}
 
-	diags, err := s.PackageDiagnostics(ctx, ids...)
+	diags, err := s.PackageDiagnostics(ctx, nil, ids...)
 	if err != nil {
 		return command.ViewStats{}, err
 	}
@@ -1681,6 +2257,16 @@ func (c *commandHandler) DiagnoseFiles(ctx context.Context, args command.Diagnos
 	})
 }
 
+// IndexStatus implements the IndexStatus command, reporting the
+// progress of the most recent diagnostics pass over each view.
+func (c *commandHandler) IndexStatus(ctx context.Context) (command.IndexStatusResult, error) {
+	var res command.IndexStatusResult
+	for _, view := range c.s.session.Views() {
+		res.Views = append(res.Views, c.s.indexStatus(view))
+	}
+	return res, nil
+}
+
 func (c *commandHandler) Views(ctx context.Context) ([]command.View, error) {
 	var summaries []command.View
 	for _, view := range c.s.session.Views() {
@@ -1715,6 +2301,69 @@ func (c *commandHandler) Assembly(ctx context.Context, viewID, packageID, symbol
 	return nil
 }
 
+func (c *commandHandler) UnusedExports(ctx context.Context, args command.UnusedExportsArgs) (command.UnusedExportsResult, error) {
+	var result command.UnusedExportsResult
+	err := c.run(ctx, commandConfig{
+		progress: "Finding unused exports",
+		forURI:   args.URI,
+	}, func(ctx context.Context, deps commandDeps) error {
+		exports, err := golang.UnusedExports(ctx, deps.snapshot)
+		if err != nil {
+			return err
+		}
+		for _, exp := range exports {
+			result.Symbols = append(result.Symbols, command.UnusedExport{
+				PkgPath:  string(exp.PkgPath),
+				Name:     exp.Name,
+				Kind:     exp.Kind,
+				Location: exp.Location,
+			})
+		}
+
+		// Start web server.
+		web, err := c.s.getWeb()
+		if err != nil {
+			return err
+		}
+		result.URL = web.unusedexportsURL(deps.snapshot.View().ID())
+		if args.ShowDocument {
+			openClientBrowser(ctx, c.s.client, "Unused exports", result.URL, c.s.Options())
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (c *commandHandler) NewModule(ctx context.Context, args command.NewModuleArgs) (command.NewModuleResult, error) {
+	var result command.NewModuleResult
+	err := c.run(ctx, commandConfig{
+		progress: "Creating new module",
+	}, func(ctx context.Context, _ commandDeps) error {
+		if args.Dir == "" {
+			return fmt.Errorf("no directory specified for new module")
+		}
+		opts := c.s.Options()
+		dir, err := gonew.Create(ctx, &gonew.Config{
+			Template: args.Template,
+			Module:   args.Module,
+			Dir:      args.Dir.Path(),
+			Vars:     args.Vars,
+			Steps:    args.Steps,
+			Hooks:    args.Hooks,
+			Env:      append(os.Environ(), opts.EnvSlice()...),
+		})
+		if err != nil {
+			return err
+		}
+		result.GoMod = protocol.URIFromPath(filepath.Join(dir, "go.mod"))
+		if args.ShowDocument {
+			openClientEditor(ctx, c.s.client, protocol.Location{URI: result.GoMod}, opts)
+		}
+		return nil
+	})
+	return result, err
+}
+
 func (c *commandHandler) ClientOpenURL(ctx context.Context, url string) error {
 	// Fall back to "Gopls: open your browser..." if we must send a showMessage
 	// request, since we don't know the context of this command.
@@ -1722,6 +2371,11 @@ func (c *commandHandler) ClientOpenURL(ctx context.Context, url string) error {
 	return nil
 }
 
+func (c *commandHandler) ShowGenerator(ctx context.Context, loc protocol.Location) error {
+	openClientEditor(ctx, c.s.client, loc, c.s.Options())
+	return nil
+}
+
 func (c *commandHandler) ScanImports(ctx context.Context) error {
 	for _, v := range c.s.session.Views() {
 		v.ScanImports()
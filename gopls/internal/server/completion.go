@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/file"
-	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/label"
 	"golang.org/x/tools/gopls/internal/protocol"
@@ -73,12 +72,12 @@ func (s *server) Completion(ctx context.Context, params *protocol.CompletionPara
 	options := snapshot.Options()
 	incompleteResults := options.DeepCompletion || options.Matcher == settings.Fuzzy
 
-	items, err := toProtocolCompletionItems(candidates, surrounding, options)
+	items, features, err := toProtocolCompletionItems(candidates, surrounding, options)
 	if err != nil {
 		return nil, err
 	}
 	if snapshot.FileKind(fh) == file.Go {
-		s.saveLastCompletion(fh.URI(), fh.Version(), items, params.Position)
+		s.saveLastCompletion(fh.URI(), fh.Version(), items, params.Position, features, options.CompletionLog)
 	}
 
 	if len(items) > 10 {
@@ -93,28 +92,33 @@ func (s *server) Completion(ctx context.Context, params *protocol.CompletionPara
 	}, nil
 }
 
-func (s *server) saveLastCompletion(uri protocol.DocumentURI, version int32, items []protocol.CompletionItem, pos protocol.Position) {
+func (s *server) saveLastCompletion(uri protocol.DocumentURI, version int32, items []protocol.CompletionItem, pos protocol.Position, features []completion.Features, logFile string) {
 	s.efficacyMu.Lock()
 	defer s.efficacyMu.Unlock()
 	s.efficacyVersion = version
 	s.efficacyURI = uri
 	s.efficacyPos = pos
 	s.efficacyItems = items
+	s.efficacyFeatures = features
+	s.efficacyLog = logFile
 }
 
-func toProtocolCompletionItems(candidates []completion.CompletionItem, surrounding *completion.Selection, options *settings.Options) ([]protocol.CompletionItem, error) {
+// toProtocolCompletionItems converts completion candidates to protocol
+// form. It also returns the ranking features of each resulting item.
+func toProtocolCompletionItems(candidates []completion.CompletionItem, surrounding *completion.Selection, options *settings.Options) ([]protocol.CompletionItem, []completion.Features, error) {
 	replaceRng, err := surrounding.Range()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	insertRng0, err := surrounding.PrefixRange()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	suffix := surrounding.Suffix()
 
 	var (
 		items                  = make([]protocol.CompletionItem, 0, len(candidates))
+		features               = make([]completion.Features, 0, len(candidates))
 		numDeepCompletionsSeen int
 	)
 	for i, candidate := range candidates {
@@ -143,7 +147,7 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 		doc := &protocol.Or_CompletionItem_documentation{
 			Value: protocol.MarkupContent{
 				Kind:  protocol.Markdown,
-				Value: golang.DocCommentToMarkdown(candidate.Documentation, options),
+				Value: candidate.DocLinks.Markdown(candidate.Documentation, options),
 			},
 		}
 		if options.PreferredContentFormat != protocol.Markdown {
@@ -196,6 +200,7 @@ func toProtocolCompletionItems(candidates []completion.CompletionItem, surroundi
 			Deprecated:    candidate.Deprecated,
 		}
 		items = append(items, item)
+		features = append(features, candidate.Features)
 	}
-	return items, nil
+	return items, features, nil
 }
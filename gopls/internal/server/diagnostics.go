@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"slices"
 	"sort"
 	"strings"
@@ -31,6 +30,7 @@ import (
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/event/keys"
 	"golang.org/x/tools/internal/jsonrpc2"
+	"golang.org/x/tools/internal/xcontext"
 )
 
 // Diagnostic implements the textDocument/diagnostic LSP request, reporting
@@ -234,6 +234,10 @@ func (s *server) diagnoseSnapshot(ctx context.Context, snapshot *cache.Snapshot,
 	if err != nil {
 		if ctx.Err() == nil {
 			event.Error(ctx, "warning: while diagnosing snapshot", err, snapshot.Labels()...)
+		} else if diagnostics != nil {
+			// The snapshot was invalidated during analysis: publish
+			// the results of the analyzers that completed.
+			s.updatePartialDiagnostics(ctx, snapshot, diagnostics)
 		}
 		return
 	}
@@ -290,7 +294,7 @@ func (s *server) diagnoseChangedFiles(ctx context.Context, snapshot *cache.Snaps
 			toDiagnose[meta.ID] = meta
 		}
 	}
-	diags, err := snapshot.PackageDiagnostics(ctx, moremaps.KeySlice(toDiagnose)...)
+	diags, err := snapshot.PackageDiagnostics(ctx, nil, moremaps.KeySlice(toDiagnose)...)
 	if err != nil {
 		if ctx.Err() == nil {
 			event.Error(ctx, "warning: diagnostics failed", err, snapshot.Labels()...)
@@ -314,6 +318,14 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	ctx, done := event.Start(ctx, "Server.diagnose", snapshot.Labels()...)
 	defer done()
 
+	// Record the progress of this pass for the IndexStatus command.
+	pass := s.startIndexPass(snapshot)
+	defer func() {
+		if ctx.Err() == nil {
+			pass.done.Store(true)
+		}
+	}()
+
 	// Wait for a free diagnostics slot.
 	// TODO(adonovan): opt: shouldn't it be the analysis implementation's
 	// job to de-dup and limit resource consumption? In any case this
@@ -351,7 +363,7 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	//  go.work > mod > mod upgrade > mod vuln > package, etc.
 
 	// Diagnose go.work file.
-	workReports, workErr := work.Diagnostics(ctx, snapshot)
+	workReports, workErr := work.Diagnostics(ctx, snapshot, s.session.Views())
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
@@ -364,6 +376,13 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	}
 	store("diagnosing go.mod file", modReports, modErr)
 
+	// Diagnose go.mod requirements that are not vendored.
+	vendorReports, vendorErr := mod.VendorDiagnostics(ctx, snapshot)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	store("diagnosing vendor directory", vendorReports, vendorErr)
+
 	// Diagnose go.mod upgrades.
 	upgradeReports, upgradeErr := mod.UpgradeDiagnostics(ctx, snapshot)
 	if ctx.Err() != nil {
@@ -382,6 +401,7 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	if s.shouldIgnoreError(snapshot, err) {
 		return diagnostics, ctx.Err()
 	}
+	pass.loaded.Store(true)
 
 	initialErr := snapshot.InitializationError()
 	if ctx.Err() != nil {
@@ -427,6 +447,10 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 		defer wg.Done()
 		modTidyReports, err := mod.TidyDiagnostics(ctx, snapshot)
 		store("running go mod tidy", modTidyReports, err)
+
+		// go.sum diagnostics depend on the result of go mod tidy.
+		sumReports, err := mod.SumDiagnostics(ctx, snapshot)
+		store("diagnosing go.sum files", sumReports, err)
 	}()
 
 	// Run type checking and go/analysis diagnosis of packages in parallel.
@@ -448,6 +472,16 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	//   the pair of packages {x, x_test}, Originally we used all
 	//   covering packages, so {x.go} alone would be analyzed
 	//   twice.)
+	//
+	// An open large file, which gopls operates on in degraded mode,
+	// does not by itself cause its package to be analyzed, and only its
+	// syntax errors are reported.
+	largeReports, largeErr := golang.LargeFileDiagnostics(ctx, snapshot, workspacePkgs)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	store("checking for large files", largeReports, largeErr)
+
 	var (
 		toDiagnose = make(map[metadata.PackageID]*metadata.Package)
 		toAnalyze  = make(map[metadata.PackageID]*metadata.Package)
@@ -461,7 +495,7 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 			if !hasNonIgnored && !snapshot.IgnoredFile(uri) {
 				hasNonIgnored = true
 			}
-			if !hasOpenFile && snapshot.IsOpen(uri) {
+			if !hasOpenFile && snapshot.IsOpen(uri) && largeReports[uri] == nil {
 				hasOpenFile = true
 			}
 		}
@@ -482,6 +516,10 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 		}
 	}
 
+	pass.progress.SetPackages(len(toDiagnose))
+	endReport := s.reportIndexProgress(ctx, snapshot, pass)
+	defer endReport()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -489,17 +527,35 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 		store("collecting compiler optimization details", compilerOptDetailsDiags, err)
 	}()
 
+	if snapshot.Options().StaleGeneratedFiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			staleDiags, err := staleGeneratedFileDiagnostics(ctx, snapshot, toDiagnose)
+			store("checking for stale generated files", staleDiags, err)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		editedDiags, err := golang.EditedGeneratedFiles(ctx, snapshot)
+		store("checking for edited generated files", editedDiags, err)
+	}()
+
 	// Package diagnostics and analysis diagnostics must both be computed and
 	// merged before they can be reported.
-	var pkgDiags, analysisDiags diagMap
+	var (
+		pkgDiags, analysisDiags diagMap
+		pkgErr                  error
+	)
 	// Collect package diagnostics.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var err error
-		pkgDiags, err = snapshot.PackageDiagnostics(ctx, moremaps.KeySlice(toDiagnose)...)
-		if err != nil {
-			event.Error(ctx, "warning: diagnostics failed", err, snapshot.Labels()...)
+		pkgDiags, pkgErr = snapshot.PackageDiagnostics(ctx, &pass.progress, moremaps.KeySlice(toDiagnose)...)
+		if pkgErr != nil {
+			event.Error(ctx, "warning: diagnostics failed", pkgErr, snapshot.Labels()...)
 		}
 	}()
 
@@ -509,9 +565,12 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 	go func() {
 		defer wg.Done()
 		var err error
+		// If the snapshot is invalidated during analysis, the first
+		// result holds the diagnostics of the analyzers that completed.
+		//
 		// TODO(rfindley): here and above, we should avoid using the first result
 		// if err is non-nil (though as of today it's OK).
-		analysisDiags, err = golang.Analyze(ctx, snapshot, toAnalyze, s.progress)
+		analysisDiags, err = golang.Analyze(ctx, snapshot, toAnalyze, s.progress, &pass.progress)
 
 		// Filter out Hint diagnostics for closed files.
 		// VS Code already omits Hint diagnostics in the Problems tab, but other
@@ -550,17 +609,37 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 			combinedDiags[uri] = tdiags
 		}
 	}
+	for uri := range largeReports {
+		if diags, ok := combinedDiags[uri]; ok {
+			combinedDiags[uri] = golang.SyntaxDiagnostics(diags)
+		}
+	}
 	store("type checking and analysing", combinedDiags, nil) // error reported above
 
+	// If the snapshot was invalidated during analysis, return the
+	// diagnostics of the analyzed files, which include those of the
+	// analyzers that completed, as a partial result, provided that
+	// type checking completed.
+	if ctx.Err() != nil {
+		if pkgErr != nil {
+			return nil, ctx.Err()
+		}
+		partial := make(diagMap)
+		for _, mp := range toAnalyze {
+			for _, uri := range mp.CompiledGoFiles {
+				partial[uri] = diagnostics[uri]
+			}
+		}
+		return partial, ctx.Err()
+	}
+
 	return diagnostics, nil
 }
 
 func (s *server) compilerOptDetailsDiagnostics(ctx context.Context, snapshot *cache.Snapshot, toDiagnose map[metadata.PackageID]*metadata.Package) (diagMap, error) {
 	// Process requested diagnostics about compiler optimization details.
-	//
-	// TODO(rfindley): This should memoize its results if the package has not changed.
-	// Consider that these points, in combination with the note below about
-	// races, suggest that compiler optimization details should be tracked on the Snapshot.
+	// The results are cached by golang.CompilerOptDetails for each
+	// build of the package, so this is cheap if the package has not changed.
 	diagnostics := make(diagMap)
 	seenDirs := make(map[protocol.DocumentURI]bool)
 	for _, mp := range toDiagnose {
@@ -586,6 +665,36 @@ func (s *server) compilerOptDetailsDiagnostics(ctx context.Context, snapshot *ca
 	return diagnostics, nil
 }
 
+// staleGeneratedFileDiagnostics reports generated files, in the
+// directories of the packages to diagnose, that are older than their
+// inputs.
+func staleGeneratedFileDiagnostics(ctx context.Context, snapshot *cache.Snapshot, toDiagnose map[metadata.PackageID]*metadata.Package) (diagMap, error) {
+	diagnostics := make(diagMap)
+	seenDirs := make(map[protocol.DocumentURI]bool)
+	for _, mp := range toDiagnose {
+		if len(mp.CompiledGoFiles) == 0 {
+			continue
+		}
+		dir := mp.CompiledGoFiles[0].Dir()
+		if seenDirs[dir] {
+			continue
+		}
+		seenDirs[dir] = true
+		perFileDiags, err := golang.StaleGeneratedFiles(ctx, snapshot, dir)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			event.Error(ctx, "warning: stale generated files", err, append(snapshot.Labels(), label.URI.Of(dir))...)
+			continue
+		}
+		for uri, diags := range perFileDiags {
+			diagnostics[uri] = append(diagnostics[uri], diags...)
+		}
+	}
+	return diagnostics, nil
+}
+
 // mustPublishDiagnostics marks the uri as needing publication, independent of
 // whether the published contents have changed.
 //
@@ -740,6 +849,45 @@ func (s *server) updateDiagnostics(ctx context.Context, snapshot *cache.Snapshot
 	}
 }
 
+// updatePartialDiagnostics publishes the partial diagnostics of a
+// snapshot whose diagnosis was interrupted by its invalidation, such
+// as those of the analyzers that completed (see
+// [cache.Snapshot.Analyze]), so that they are not discarded.
+//
+// Partial diagnostics replace only those of the first phase of the
+// diagnosis of the same snapshot (see diagnoseSnapshot), which lack
+// analysis results entirely: they never replace the diagnostics of a
+// newer snapshot, nor the complete diagnostics of an older one.
+func (s *server) updatePartialDiagnostics(ctx context.Context, snapshot *cache.Snapshot, diagnostics diagMap) {
+	ctx = xcontext.Detach(ctx) // the snapshot's context is cancelled
+	ctx, done := event.Start(ctx, "Server.publishPartialDiagnostics")
+	defer done()
+
+	s.diagnosticsMu.Lock()
+	defer s.diagnosticsMu.Unlock()
+
+	viewMap := make(viewSet)
+	for _, v := range s.session.Views() {
+		viewMap[v] = unit{}
+	}
+
+	for uri, diags := range diagnostics {
+		f, ok := s.diagnostics[uri]
+		if !ok {
+			continue
+		}
+		current, ok := f.byView[snapshot.View()]
+		if !ok || current.snapshot != snapshot.SequenceID() {
+			continue // no first-phase diagnostics for this snapshot
+		}
+		current.diagnostics = diags
+		f.byView[snapshot.View()] = current
+		if err := s.publishFileDiagnosticsLocked(ctx, viewMap, uri, current.version, f); err != nil {
+			event.Error(ctx, "updatePartialDiagnostics: failed to deliver diagnostics", err, label.URI.Of(uri))
+		}
+	}
+}
+
 // updateOrphanedFileDiagnostics records and publishes orphaned file
 // diagnostics as a given modification time.
 func (s *server) updateOrphanedFileDiagnostics(ctx context.Context, modID uint64, diagnostics diagMap) error {
@@ -852,14 +1000,17 @@ func (s *server) publishFileDiagnosticsLocked(ctx context.Context, views viewSet
 
 	for _, view := range relevantViews {
 		viewDiags := f.byView[view]
-		// Compute the view's suffix (e.g. " [darwin,arm64]").
+		// Compute the view's suffix (e.g. " [darwin,arm64]"), naming
+		// the parts of its configuration that differ from the
+		// folder's default GOOS and GOARCH.
 		var suffix string
 		{
 			var words []string
-			if view.GOOS() != runtime.GOOS {
+			env := view.Folder().Env
+			if view.GOOS() != env.GOOS {
 				words = append(words, view.GOOS())
 			}
-			if view.GOARCH() != runtime.GOARCH {
+			if view.GOARCH() != env.GOARCH {
 				words = append(words, view.GOARCH())
 			}
 			if len(words) > 0 {
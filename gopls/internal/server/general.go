@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"go/build"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -128,6 +129,10 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 		}
 	}
 
+	// Kinds of files and directories that may be created or renamed
+	// by the client (workspace/didCreateFiles, willRenameFiles).
+	filePattern, folderPattern := protocol.FilePattern, protocol.FolderPattern
+
 	versionInfo := debug.VersionInfo()
 
 	goplsVersion, err := json.Marshal(versionInfo)
@@ -152,15 +157,17 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 			ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
 				Commands: protocol.NonNilSlice(options.SupportedCommands),
 			},
-			FoldingRangeProvider:      &protocol.Or_ServerCapabilities_foldingRangeProvider{Value: true},
-			HoverProvider:             &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
-			DocumentHighlightProvider: &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
-			DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
-			InlayHintProvider:         protocol.InlayHintOptions{},
-			DiagnosticProvider:        diagnosticProvider,
-			ReferencesProvider:        &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
-			RenameProvider:            renameOpts,
-			SelectionRangeProvider:    &protocol.Or_ServerCapabilities_selectionRangeProvider{Value: true},
+			FoldingRangeProvider:       &protocol.Or_ServerCapabilities_foldingRangeProvider{Value: true},
+			HoverProvider:              &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
+			DocumentHighlightProvider:  &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
+			DocumentLinkProvider:       &protocol.DocumentLinkOptions{},
+			InlayHintProvider:          protocol.InlayHintOptions{},
+			InlineValueProvider:        &protocol.Or_ServerCapabilities_inlineValueProvider{Value: true},
+			LinkedEditingRangeProvider: &protocol.Or_ServerCapabilities_linkedEditingRangeProvider{Value: true},
+			DiagnosticProvider:         diagnosticProvider,
+			ReferencesProvider:         &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
+			RenameProvider:             renameOpts,
+			SelectionRangeProvider:     &protocol.Or_ServerCapabilities_selectionRangeProvider{Value: true},
 			SemanticTokensProvider: protocol.SemanticTokensOptions{
 				Range: &protocol.Or_SemanticTokensOptions_range{Value: true},
 				Full:  &protocol.Or_SemanticTokensOptions_full{Value: true},
@@ -184,6 +191,19 @@ func (s *server) Initialize(ctx context.Context, params *protocol.ParamInitializ
 					Supported:           true,
 					ChangeNotifications: "workspace/didChangeWorkspaceFolders",
 				},
+				FileOperations: &protocol.FileOperationOptions{
+					DidCreate: &protocol.FileOperationRegistrationOptions{
+						Filters: []protocol.FileOperationFilter{
+							{Scheme: "file", Pattern: protocol.FileOperationPattern{Glob: "**/*.go", Matches: &filePattern}},
+						},
+					},
+					WillRename: &protocol.FileOperationRegistrationOptions{
+						Filters: []protocol.FileOperationFilter{
+							{Scheme: "file", Pattern: protocol.FileOperationPattern{Glob: "**/*.go", Matches: &filePattern}},
+							{Scheme: "file", Pattern: protocol.FileOperationPattern{Glob: "**", Matches: &folderPattern}},
+						},
+					},
+				},
 			},
 		},
 		ServerInfo: &protocol.ServerInfo{
@@ -521,7 +541,7 @@ func (s *server) newFolder(ctx context.Context, folder protocol.DocumentURI, nam
 func (s *server) fetchFolderOptions(ctx context.Context, folder protocol.DocumentURI) (*settings.Options, error) {
 	opts := s.Options()
 	if !opts.ConfigurationSupported {
-		return opts, nil
+		return s.withEnvOverrides(folder, opts), nil
 	}
 	var scopeURI *string
 	if folder != "" {
@@ -543,7 +563,40 @@ func (s *server) fetchFolderOptions(ctx context.Context, folder protocol.Documen
 	for _, config := range configs {
 		s.handleOptionErrors(ctx, opts.Set(config))
 	}
-	return opts, nil
+	return s.withEnvOverrides(folder, opts), nil
+}
+
+// withEnvOverrides returns opts, with the overrides set for the folder by
+// the SetViewEnvironment command applied, if any.
+func (s *server) withEnvOverrides(folder protocol.DocumentURI, opts *settings.Options) *settings.Options {
+	s.optionsMu.Lock()
+	override, ok := s.envOverrides[folder]
+	s.optionsMu.Unlock()
+	if !ok {
+		return opts
+	}
+
+	opts = opts.Clone()
+	if len(override.Env) > 0 && opts.Env == nil {
+		opts.Env = make(map[string]string)
+	}
+	maps.Copy(opts.Env, override.Env)
+	if override.BuildTags != nil {
+		// Replace any -tags flag, in either of its forms.
+		var flags []string
+		for i := 0; i < len(opts.BuildFlags); i++ {
+			flag := opts.BuildFlags[i]
+			switch {
+			case flag == "-tags" || flag == "--tags":
+				i++ // skip the value
+			case strings.HasPrefix(flag, "-tags=") || strings.HasPrefix(flag, "--tags="):
+			default:
+				flags = append(flags, flag)
+			}
+		}
+		opts.BuildFlags = append(flags, "-tags="+strings.Join(override.BuildTags, ","))
+	}
+	return opts
 }
 
 func (s *server) eventuallyShowMessage(ctx context.Context, msg *protocol.ShowMessageParams) {
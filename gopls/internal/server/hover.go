@@ -37,6 +37,8 @@ func (s *server) Hover(ctx context.Context, params *protocol.HoverParams) (_ *pr
 	switch snapshot.FileKind(fh) {
 	case file.Mod:
 		return mod.Hover(ctx, snapshot, fh, params.Position)
+	case file.Sum:
+		return mod.SumHover(ctx, snapshot, fh, params.Position)
 	case file.Go:
 		var pkgURL func(path golang.PackagePath, fragment string) protocol.URI
 		if snapshot.Options().LinksInHover == settings.LinksInHover_Gopls {
@@ -0,0 +1,135 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+)
+
+// IndexProgressTitle is the title of the progress report for an
+// ongoing diagnostics pass over the workspace packages of a view.
+// It is sought by regression tests for the progress reporting feature.
+const IndexProgressTitle = "Indexing"
+
+// An indexPass records the progress of a diagnostics pass over the
+// workspace packages of a view, for the IndexStatus command and the
+// indexing progress report.
+type indexPass struct {
+	progress cache.IndexProgress
+	loaded   atomic.Bool // workspace packages have been loaded
+	done     atomic.Bool // the pass completed without cancellation
+}
+
+// startIndexPass records the start of a diagnostics pass over the
+// workspace packages of the snapshot's view, replacing any previous one.
+// It also discards the passes of views that no longer exist, since
+// views are replaced whenever they are reset or reconfigured.
+func (s *server) startIndexPass(snapshot *cache.Snapshot) *indexPass {
+	live := make(map[string]bool)
+	for _, view := range s.session.Views() {
+		live[view.ID()] = true
+	}
+
+	pass := new(indexPass)
+	s.indexPassesMu.Lock()
+	defer s.indexPassesMu.Unlock()
+	if s.indexPasses == nil {
+		s.indexPasses = make(map[string]*indexPass)
+	}
+	maps.DeleteFunc(s.indexPasses, func(id string, _ *indexPass) bool { return !live[id] })
+	s.indexPasses[snapshot.View().ID()] = pass
+	return pass
+}
+
+// indexStatus returns the status of the most recent diagnostics pass
+// over the workspace packages of the view.
+func (s *server) indexStatus(view *cache.View) command.IndexViewStatus {
+	s.indexPassesMu.Lock()
+	pass := s.indexPasses[view.ID()]
+	s.indexPassesMu.Unlock()
+
+	status := command.IndexViewStatus{
+		View:   view.ID(),
+		Folder: view.Folder().Dir,
+		State:  "loading",
+	}
+	if pass == nil || !pass.loaded.Load() {
+		return status
+	}
+	st := pass.progress.Status()
+	status.Packages = st.Packages
+	status.TypeChecked = st.TypeChecked
+	status.Indexed = st.Indexed
+	status.AnalysisPending = st.AnalysisPending
+	if pass.done.Load() {
+		status.State = "idle"
+	} else {
+		status.State = "indexing"
+	}
+	return status
+}
+
+// reportIndexProgress reports the progress of the pass using work
+// done progress notifications, if the client supports them and the
+// pass takes longer than the ReportAnalysisProgressAfter option. The
+// resulting function ends the report; it must be called exactly once.
+func (s *server) reportIndexProgress(ctx context.Context, snapshot *cache.Snapshot, pass *indexPass) (end func()) {
+	opts := snapshot.Options()
+	if !s.progress.SupportsWorkDoneProgress() || !opts.AnalysisProgressReporting {
+		return func() {}
+	}
+
+	const reportEvery = 1 * time.Second
+	message := func() (string, float64) {
+		st := pass.progress.Status()
+		msg := fmt.Sprintf("Indexed %d/%d packages.", st.Indexed, st.Packages)
+		if st.AnalysisPending > 0 {
+			msg += fmt.Sprintf(" Analyzing %d packages.", st.AnalysisPending)
+		}
+		pct := 0.0
+		if st.Packages > 0 {
+			pct = 100 * float64(st.Indexed) / float64(st.Packages)
+		}
+		return msg, pct
+	}
+
+	stop := make(chan unit)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-stop:
+			return
+		case <-time.After(opts.ReportAnalysisProgressAfter):
+		}
+		msg, _ := message()
+		wd := s.progress.Start(ctx, IndexProgressTitle, msg, nil, nil)
+		ticker := time.NewTicker(reportEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				wd.End(ctx, "Done.")
+				return
+			case <-ticker.C:
+				msg, pct := message()
+				wd.Report(ctx, msg, pct)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
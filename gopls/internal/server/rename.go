@@ -13,6 +13,7 @@ import (
 	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/label"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/template"
 	"golang.org/x/tools/internal/event"
 )
 
@@ -26,26 +27,59 @@ func (s *server) Rename(ctx context.Context, params *protocol.RenameParams) (*pr
 	}
 	defer release()
 
-	if kind := snapshot.FileKind(fh); kind != file.Go {
+	var (
+		edits         map[protocol.DocumentURI][]protocol.TextEdit
+		textEdits     map[protocol.DocumentURI][]protocol.TextEdit // in comments and strings
+		isPkgRenaming bool
+	)
+	switch kind := snapshot.FileKind(fh); kind {
+	case file.Go:
+		// Because we don't handle directory renaming within golang.Rename, golang.Rename returns
+		// boolean value isPkgRenaming to determine whether any DocumentChanges of type RenameFile should
+		// be added to the return protocol.WorkspaceEdit value.
+		edits, isPkgRenaming, err = golang.Rename(ctx, snapshot, fh, params.Position, params.NewName)
+		if err == nil && !isPkgRenaming && snapshot.Options().RenameInCommentsAndStrings {
+			textEdits, err = golang.RenameInCommentsAndStrings(ctx, snapshot, fh, params.Position, params.NewName, edits)
+		}
+	case file.Tmpl:
+		edits, err = template.Rename(ctx, snapshot, fh, params.Position, params.NewName)
+	default:
 		return nil, fmt.Errorf("cannot rename in file of type %s", kind)
 	}
-
-	// Because we don't handle directory renaming within golang.Rename, golang.Rename returns
-	// boolean value isPkgRenaming to determine whether any DocumentChanges of type RenameFile should
-	// be added to the return protocol.WorkspaceEdit value.
-	edits, isPkgRenaming, err := golang.Rename(ctx, snapshot, fh, params.Position, params.NewName)
 	if err != nil {
 		return nil, err
 	}
 
+	// Edits in comments and strings, and the move of a package
+	// directory, are annotated if the client supports it, so that
+	// the user may review them.
+	annotate := snapshot.Options().RenameChangeAnnotationsSupported
 	var changes []protocol.DocumentChange
-	for uri, e := range edits {
+	addChange := func(uri protocol.DocumentURI, edits, textEdits []protocol.TextEdit) error {
 		fh, err := snapshot.ReadFile(ctx, uri)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		change := protocol.DocumentChangeEdit(fh, edits)
+		if annotate {
+			change.TextDocumentEdit.Edits = append(change.TextDocumentEdit.Edits, protocol.AnnotateTextEdits(textEdits, renameInCommentsAndStrings)...)
+		} else {
+			change.TextDocumentEdit.Edits = append(change.TextDocumentEdit.Edits, protocol.AsAnnotatedTextEdits(textEdits)...)
 		}
-		change := protocol.DocumentChangeEdit(fh, e)
 		changes = append(changes, change)
+		return nil
+	}
+	for uri, e := range edits {
+		if err := addChange(uri, e, textEdits[uri]); err != nil {
+			return nil, err
+		}
+	}
+	for uri, e := range textEdits {
+		if _, ok := edits[uri]; !ok {
+			if err := addChange(uri, nil, e); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if isPkgRenaming {
@@ -55,10 +89,16 @@ func (s *server) Rename(ctx context.Context, params *protocol.RenameParams) (*pr
 		change := protocol.DocumentChangeRename(
 			protocol.URIFromPath(oldDir),
 			protocol.URIFromPath(newDir))
+		if annotate {
+			id := movePackageDirectory
+			change.RenameFile.AnnotationID = &id
+		}
 		changes = append(changes, change)
 	}
 
-	return protocol.NewWorkspaceEdit(changes...), nil
+	wsedit := protocol.NewWorkspaceEdit(changes...)
+	addChangeAnnotations(wsedit)
+	return wsedit, nil
 }
 
 // PrepareRename implements the textDocument/prepareRename handler. It may
@@ -77,7 +117,18 @@ func (s *server) PrepareRename(ctx context.Context, params *protocol.PrepareRena
 	}
 	defer release()
 
-	if kind := snapshot.FileKind(fh); kind != file.Go {
+	switch kind := snapshot.FileKind(fh); kind {
+	case file.Go:
+	case file.Tmpl:
+		rng, text, err := template.PrepareRename(ctx, snapshot, fh, params.Position)
+		if err != nil {
+			return nil, nil // no template name at the cursor
+		}
+		return &protocol.PrepareRenamePlaceholder{
+			Range:       rng,
+			Placeholder: text,
+		}, nil
+	default:
 		return nil, fmt.Errorf("cannot rename in file of type %s", kind)
 	}
 
@@ -94,3 +145,67 @@ func (s *server) PrepareRename(ctx context.Context, params *protocol.PrepareRena
 		Placeholder: item.Text,
 	}, nil
 }
+
+func (s *server) WillRenameFiles(ctx context.Context, params *protocol.RenameFilesParams) (*protocol.WorkspaceEdit, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.willRenameFiles")
+	defer done()
+
+	// Files renamed by the client are not renamed again as
+	// companions of other files.
+	renamed := make(map[protocol.DocumentURI]bool)
+	for _, rename := range params.Files {
+		renamed[protocol.DocumentURI(rename.OldURI)] = true
+	}
+
+	var (
+		textChanges   []protocol.DocumentChange
+		renameChanges []protocol.DocumentChange
+	)
+	for _, rename := range params.Files {
+		changes, renames, err := s.willRenameFile(ctx, rename)
+		if err != nil {
+			return nil, err
+		}
+		textChanges = append(textChanges, changes...)
+		for _, r := range renames {
+			if !renamed[protocol.DocumentURI(r.OldURI)] {
+				renamed[protocol.DocumentURI(r.OldURI)] = true
+				renameChanges = append(renameChanges, protocol.DocumentChangeRename(
+					protocol.DocumentURI(r.OldURI),
+					protocol.DocumentURI(r.NewURI)))
+			}
+		}
+	}
+	if len(textChanges) == 0 && len(renameChanges) == 0 {
+		return nil, nil
+	}
+	// Text edits refer to the old names of files, so they must
+	// precede the renamings.
+	return protocol.NewWorkspaceEdit(append(textChanges, renameChanges...)...), nil
+}
+
+// willRenameFile returns the changes to the contents of files that
+// must precede the specified renaming, and the renamings of other files
+// that should accompany it.
+func (s *server) willRenameFile(ctx context.Context, rename protocol.FileRename) ([]protocol.DocumentChange, []protocol.FileRename, error) {
+	oldURI := protocol.DocumentURI(rename.OldURI)
+	snapshot, release, err := s.session.SnapshotOf(ctx, oldURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	edits, renames, err := golang.RenameFile(ctx, snapshot, oldURI, protocol.DocumentURI(rename.NewURI))
+	if err != nil {
+		return nil, nil, err
+	}
+	var changes []protocol.DocumentChange
+	for uri, e := range edits {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, e))
+	}
+	return changes, renames, nil
+}
@@ -11,6 +11,7 @@ import (
 	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/gopls/internal/cache/parsego"
 	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/internal/event"
 )
@@ -18,6 +19,8 @@ import (
 // SelectionRange defines the textDocument/selectionRange feature,
 // which, given a list of positions within a file,
 // reports a linked list of enclosing syntactic blocks, innermost first.
+// Within a string literal or comment, the list starts with elements of
+// its lexical structure, such as a word or a segment of an import path.
 //
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_selectionRange.
 //
@@ -54,16 +57,28 @@ func (s *server) SelectionRange(ctx context.Context, params *protocol.SelectionR
 
 		path, _ := astutil.PathEnclosingInterval(pgf.File, pos, pos)
 
-		tail := &result[i] // tail of the Parent linked list, built head first
-
-		for j, node := range path {
+		// Within a string literal or comment, start with the
+		// ranges of its lexical structure, such as a word.
+		ranges, err := golang.LexicalSelectionRanges(pgf, path, pos)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range path {
 			rng, err := pgf.NodeRange(node)
 			if err != nil {
 				return nil, err
 			}
+			ranges = append(ranges, rng)
+		}
+
+		tail := &result[i] // tail of the Parent linked list, built head first
 
-			// Add node to tail.
+		for j, rng := range ranges {
+			// Add range to tail.
 			if j > 0 {
+				if rng == tail.Range {
+					continue
+				}
 				tail.Parent = &protocol.SelectionRange{}
 				tail = tail.Parent
 			}
@@ -38,11 +38,13 @@ func (s *server) semanticTokens(ctx context.Context, td protocol.TextDocumentIde
 		case file.Tmpl:
 			return template.SemanticTokens(ctx, snapshot, fh.URI())
 		case file.Go:
-			return golang.SemanticTokens(ctx, snapshot, fh, rng)
+			if !golang.IsLargeFile(snapshot, fh) {
+				return golang.SemanticTokens(ctx, snapshot, fh, rng)
+			}
 		}
 	}
 
-	// Not enabled, or unsupported file type: return empty result.
+	// Not enabled, unsupported file type, or large file: return empty result.
 	//
 	// Returning an empty response is necessary to invalidate
 	// semantic tokens in VS Code (and perhaps other editors).
@@ -18,6 +18,8 @@ import (
 	"net/url"
 	"os"
 	paths "path"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,8 +27,10 @@ import (
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
 	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/progress"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
 	"golang.org/x/tools/gopls/internal/settings"
 	"golang.org/x/tools/gopls/internal/util/bug"
 	"golang.org/x/tools/internal/event"
@@ -112,6 +116,12 @@ type server struct {
 	// expensive.
 	diagnosticsSema chan unit
 
+	// indexPasses records, for each view (by ID), the progress of the
+	// most recent diagnostics pass over its workspace packages.
+	// The map is lazily allocated.
+	indexPassesMu sync.Mutex
+	indexPasses   map[string]*indexPass
+
 	progress *progress.Tracker
 
 	// When the workspace fails to load, we show its status through a progress
@@ -127,6 +137,9 @@ type server struct {
 	// Track most recently requested options.
 	optionsMu sync.Mutex
 	options   *settings.Options
+	// envOverrides holds the overrides set by the SetViewEnvironment
+	// command, by workspace folder.
+	envOverrides map[protocol.DocumentURI]command.SetViewEnvironmentArgs
 
 	// Track the most recent completion results, for measuring completion efficacy
 	efficacyMu      sync.Mutex
@@ -135,6 +148,11 @@ type server struct {
 	efficacyItems   []protocol.CompletionItem
 	efficacyPos     protocol.Position
 
+	// Ranking features of efficacyItems, and the completion log file
+	// (if any) to which to record the accepted item.
+	efficacyFeatures []completion.Features
+	efficacyLog      string
+
 	// Web server (for package documentation, etc) associated with this
 	// LSP server. Opened on demand, and closed during LSP Shutdown.
 	webOnce sync.Once
@@ -207,12 +225,21 @@ func (s *server) WorkDoneProgressCancel(ctx context.Context, params *protocol.Wo
 //	pkg/PKGPATH?view=%s               - show doc for package in a given view
 //	assembly?pkg=%s&view=%s&symbol=%s - show assembly of specified func symbol
 //	freesymbols?file=%s&range=%d:%d:%d:%d:&view=%s - show report of free symbols
+//	benchmarks?id=%d                  - show a comparison of benchmarks
 type web struct {
 	server *http.Server
 	addr   url.URL // "http://127.0.0.1:PORT/gopls/SECRET"
 	mux    *http.ServeMux
+
+	benchmarksMu    sync.Mutex
+	benchmarks      []*golang.BenchmarkComparison // most recent results of CompareBenchmarks
+	firstBenchmarks int                           // id of benchmarks[0]
 }
 
+// maxBenchmarkComparisons is the number of most recent comparisons of
+// benchmarks retained by the web server. Older reports are discarded.
+const maxBenchmarkComparisons = 10
+
 // getWeb returns the web server associated with this
 // LSP server, creating it on first request.
 func (s *server) getWeb() (*web, error) {
@@ -306,7 +333,8 @@ func (s *server) initWeb() (*web, error) {
 		}, s.Options())
 	})
 
-	// The /pkg/PATH&view=... handler shows package documentation for PATH.
+	// The /pkg/PATH&view=...[&unexported=1] handler shows package
+	// documentation for PATH, optionally including unexported symbols.
 	webMux.Handle("/pkg/", http.StripPrefix("/pkg/", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		if err := req.ParseForm(); err != nil {
@@ -341,13 +369,23 @@ func (s *server) initWeb() (*web, error) {
 			return
 		}
 
-		// Type-check the package and render its documentation.
-		pkgs, err := snapshot.TypeCheck(ctx, found.ID)
+		// Find its test variants, whose files contain its examples.
+		ids := []metadata.PackageID{found.ID}
+		for _, mp := range snapshot.MetadataGraph().Packages {
+			if mp.ForTest == found.PkgPath && (mp.PkgPath == found.PkgPath || mp.PkgPath == found.PkgPath+"_test") {
+				ids = append(ids, mp.ID)
+			}
+		}
+		slices.Sort(ids[1:])
+
+		// Type-check the packages and render the documentation.
+		pkgs, err := snapshot.TypeCheck(ctx, ids...)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		content, err := golang.PackageDocHTML(view.ID(), pkgs[0], web)
+		showUnexported := req.Form.Get("unexported") != ""
+		content, err := golang.PackageDocHTML(view.ID(), pkgs[0], pkgs[1:], showUnexported, web)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -355,6 +393,60 @@ func (s *server) initWeb() (*web, error) {
 		w.Write(content)
 	})))
 
+	// The /runexample?view=...&pkg=...&name=... handler runs an
+	// Example function using 'go test' and streams its output.
+	webMux.HandleFunc("/runexample", func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if err := req.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Get snapshot of specified view.
+		view, err := s.session.View(req.Form.Get("view"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer release()
+
+		// Find package by path.
+		pkgPath := metadata.PackagePath(req.Form.Get("pkg"))
+		var found *metadata.Package
+		for _, mp := range snapshot.MetadataGraph().Packages {
+			if mp.PkgPath == pkgPath && mp.ForTest == "" && len(mp.CompiledGoFiles) > 0 {
+				found = mp
+				break
+			}
+		}
+		if found == nil {
+			http.Error(w, "package not found", http.StatusNotFound)
+			return
+		}
+
+		name := req.Form.Get("name")
+		inv, cleanupInvocation, err := snapshot.GoCommandInvocation(cache.NoNetwork, found.CompiledGoFiles[0].DirPath(), "test", []string{
+			string(pkgPath), "-v", "-count=1", fmt.Sprintf("-run=^%s$", regexp.QuoteMeta(name)),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer cleanupInvocation()
+
+		// Stream the output of the test runner.
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		out := flushWriter{w}
+		if err := snapshot.View().GoCommandRunner().RunPiped(ctx, *inv, out, out); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(out, "%v\n", err)
+		}
+	})
+
 	// The /freesymbols?file=...&range=...&view=... handler shows
 	// free symbols referenced by the selection.
 	webMux.HandleFunc("/freesymbols", func(w http.ResponseWriter, req *http.Request) {
@@ -407,7 +499,9 @@ func (s *server) initWeb() (*web, error) {
 	})
 
 	// The /assembly?pkg=...&view=...&symbol=... handler shows
-	// the assembly of the current function.
+	// the assembly of the current function. The optional goarch,
+	// goamd64, and gcflags parameters select the compilation target
+	// and options; see [golang.AssemblyOptions].
 	webMux.HandleFunc("/assembly", func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		if err := req.ParseForm(); err != nil {
@@ -447,7 +541,12 @@ func (s *server) initWeb() (*web, error) {
 		pkg := pkgs[0]
 
 		// Produce report.
-		html, err := golang.AssemblyHTML(ctx, snapshot, pkg, symbol, web)
+		opts := golang.AssemblyOptions{
+			GOARCH:  req.Form.Get("goarch"),
+			GOAMD64: req.Form.Get("goamd64"),
+			GCFlags: req.Form.Get("gcflags"),
+		}
+		html, err := golang.AssemblyHTML(ctx, snapshot, pkg, symbol, opts, web)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -455,6 +554,98 @@ func (s *server) initWeb() (*web, error) {
 		w.Write(html)
 	})
 
+	// The /unusedexports?view=... handler shows the exported
+	// symbols of workspace packages that are not used by others.
+	webMux.HandleFunc("/unusedexports", func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if err := req.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Get snapshot of specified view.
+		view, err := s.session.View(req.Form.Get("view"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer release()
+
+		// Produce report.
+		exports, err := golang.UnusedExports(ctx, snapshot)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(golang.UnusedExportsHTML(view.ID(), exports, web))
+	})
+
+	// The /fuzzcorpus?view=...&file=...&fuzz=... handler shows
+	// the corpus of a fuzz test.
+	webMux.HandleFunc("/fuzzcorpus", func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if err := req.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Get snapshot of specified view.
+		view, err := s.session.View(req.Form.Get("view"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer release()
+
+		mp, err := golang.NarrowestMetadataForFile(ctx, snapshot, protocol.DocumentURI(req.Form.Get("file")))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fuzz := req.Form.Get("fuzz")
+		entries, err := golang.FuzzCorpus(ctx, snapshot, mp, fuzz)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pkgPath := mp.PkgPath
+		if mp.ForTest != "" {
+			pkgPath = mp.ForTest
+		}
+		w.Write(golang.FuzzCorpusHTML(pkgPath, fuzz, entries, web))
+	})
+
+	// The /benchmarks?id=... handler shows the result of a
+	// comparison of benchmarks. Since the benchmarks are not
+	// repeatable, the results are retained by the server.
+	webMux.HandleFunc("/benchmarks", func(w http.ResponseWriter, req *http.Request) {
+		if err := req.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, err := strconv.Atoi(req.Form.Get("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cmp := web.benchmarkComparison(id)
+		if cmp == nil {
+			http.Error(w, "no such comparison of benchmarks", http.StatusNotFound)
+			return
+		}
+		w.Write(golang.BenchmarkComparisonHTML(cmp))
+	})
+
 	return web, nil
 }
 
@@ -486,6 +677,18 @@ func (w *web) PkgURL(viewID string, path golang.PackagePath, fragment string) pr
 		fragment)
 }
 
+// RunExampleURL returns a /runexample URL that, when fetched, runs
+// the named Example function of the specified package.
+func (w *web) RunExampleURL(viewID string, path golang.PackagePath, example string) protocol.URI {
+	return w.url(
+		"runexample",
+		fmt.Sprintf("view=%s&pkg=%s&name=%s",
+			url.QueryEscape(viewID),
+			url.QueryEscape(string(path)),
+			url.QueryEscape(example)),
+		"")
+}
+
 // freesymbolsURL returns a /freesymbols URL for a report
 // on the free symbols referenced within the selection span (loc).
 func (w *web) freesymbolsURL(viewID string, loc protocol.Location) protocol.URI {
@@ -512,6 +715,60 @@ func (w *web) assemblyURL(viewID, packageID, symbol string) protocol.URI {
 		"")
 }
 
+// unusedexportsURL returns the URL of the report of unused exported
+// symbols in the workspace of the specified view.
+func (w *web) unusedexportsURL(viewID string) protocol.URI {
+	return w.url(
+		"unusedexports",
+		"view="+url.QueryEscape(viewID),
+		"")
+}
+
+// fuzzCorpusURL returns the URL of the report of the corpus of the
+// named fuzz test in the specified file.
+func (w *web) fuzzCorpusURL(viewID string, uri protocol.DocumentURI, fuzz string) protocol.URI {
+	return w.url(
+		"fuzzcorpus",
+		fmt.Sprintf("view=%s&file=%s&fuzz=%s",
+			url.QueryEscape(viewID),
+			url.QueryEscape(string(uri)),
+			url.QueryEscape(fuzz)),
+		"")
+}
+
+// addBenchmarkComparison records the result of a comparison of
+// benchmarks, and returns its id for use in a /benchmarks URL.
+// Only the most recent maxBenchmarkComparisons results are retained.
+func (w *web) addBenchmarkComparison(cmp *golang.BenchmarkComparison) int {
+	w.benchmarksMu.Lock()
+	defer w.benchmarksMu.Unlock()
+	if len(w.benchmarks) == maxBenchmarkComparisons {
+		w.benchmarks[0] = nil // allow GC
+		w.benchmarks = w.benchmarks[1:]
+		w.firstBenchmarks++
+	}
+	w.benchmarks = append(w.benchmarks, cmp)
+	return w.firstBenchmarks + len(w.benchmarks) - 1
+}
+
+// benchmarkComparison returns the result of the comparison of
+// benchmarks with the specified id, or nil if it is unknown or has
+// been discarded.
+func (w *web) benchmarkComparison(id int) *golang.BenchmarkComparison {
+	w.benchmarksMu.Lock()
+	defer w.benchmarksMu.Unlock()
+	if i := id - w.firstBenchmarks; 0 <= i && i < len(w.benchmarks) {
+		return w.benchmarks[i]
+	}
+	return nil
+}
+
+// benchmarksURL returns the URL of the report of the comparison of
+// benchmarks with the specified id.
+func (w *web) benchmarksURL(id int) protocol.URI {
+	return w.url("benchmarks", fmt.Sprintf("id=%d", id), "")
+}
+
 // url returns a URL by joining a relative path, an (encoded) query,
 // and an (unencoded) fragment onto the authenticated base URL of the
 // web server.
@@ -523,6 +780,20 @@ func (w *web) url(path, query, fragment string) protocol.URI {
 	return protocol.URI(url2.String())
 }
 
+// A flushWriter is an io.Writer that flushes each write to the
+// client of an HTTP response, so that it can be streamed.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw flushWriter) Write(data []byte) (int, error) {
+	n, err := fw.w.Write(data)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
 // withPanicHandler wraps an HTTP handler with telemetry-reporting of
 // panics that would otherwise be silently recovered by the net/http
 // root handler.
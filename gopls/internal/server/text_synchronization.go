@@ -9,6 +9,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -16,6 +20,7 @@ import (
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/label"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/internal/event"
@@ -64,6 +69,17 @@ const (
 	// FromToggleCompilerOptDetails refers to state changes resulting from toggling
 	// a package's compiler optimization details flag.
 	FromToggleCompilerOptDetails
+
+	// FromSetViewEnvironment refers to state changes resulting from the
+	// SetViewEnvironment command.
+	FromSetViewEnvironment
+
+	// FromResyncDriver refers to state changes resulting from the
+	// ResyncDriver command.
+	FromResyncDriver
+
+	// FromDidCreateFiles is from a didCreateFiles notification.
+	FromDidCreateFiles
 )
 
 func (m ModificationSource) String() string {
@@ -86,6 +102,12 @@ func (m ModificationSource) String() string {
 		return "from check upgrades"
 	case FromResetGoModDiagnostics:
 		return "from resetting go.mod diagnostics"
+	case FromSetViewEnvironment:
+		return "from setting the view environment"
+	case FromResyncDriver:
+		return "from reloading the workspace"
+	case FromDidCreateFiles:
+		return "created files"
 	default:
 		return "unknown file modification"
 	}
@@ -138,9 +160,55 @@ func (s *server) DidChange(ctx context.Context, params *protocol.DidChangeTextDo
 	if err := s.didModifyFiles(ctx, []file.Modification{c}, FromDidChange); err != nil {
 		return err
 	}
+	if locked, err := s.lockGeneratedFile(ctx, uri); err != nil || locked {
+		return err
+	}
 	return s.warnAboutModifyingGeneratedFiles(ctx, uri)
 }
 
+// lockGeneratedFile undoes the edits to the buffer of a generated file,
+// if the lockGeneratedFiles setting is enabled and the client supports
+// workspace/applyEdit, by asking the client to restore its saved
+// content. It reports whether the file is locked.
+func (s *server) lockGeneratedFile(ctx context.Context, uri protocol.DocumentURI) (bool, error) {
+	snapshot, release, err := s.session.SnapshotOf(ctx, uri)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	if opts := snapshot.Options(); !opts.LockGeneratedFiles || !opts.ApplyEditSupported {
+		return false, nil
+	}
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil || fh.SameContentsOnDisk() {
+		return false, err
+	}
+	// Whether the file is generated is a property of its saved content,
+	// which the edits may have changed.
+	saved, err := os.ReadFile(uri.Path())
+	if err != nil {
+		return false, nil // not on disk
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", saved, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil || !ast.IsGenerated(f) {
+		return false, nil
+	}
+	change, err := computeEditChange(ctx, snapshot, uri, saved)
+	if err != nil || !change.Valid() {
+		return true, err
+	}
+	// Apply the edits asynchronously, as the client may need to
+	// process this notification before it can respond.
+	go func() {
+		ctx := xcontext.Detach(ctx)
+		if err := applyChanges(ctx, s.client, []protocol.DocumentChange{change}); err != nil {
+			event.Error(ctx, "restoring generated file", err, label.URI.Of(uri))
+		}
+	}()
+	return true, nil
+}
+
 // warnAboutModifyingGeneratedFiles shows a warning if a user tries to edit a
 // generated file for the first time.
 func (s *server) warnAboutModifyingGeneratedFiles(ctx context.Context, uri protocol.DocumentURI) error {
@@ -189,6 +257,60 @@ func (s *server) DidChangeWatchedFiles(ctx context.Context, params *protocol.Did
 	return s.didModifyFiles(ctx, modifications, FromDidChangeWatchedFiles)
 }
 
+func (s *server) DidCreateFiles(ctx context.Context, params *protocol.CreateFilesParams) error {
+	ctx, done := event.Start(ctx, "lsp.Server.didCreateFiles")
+	defer done()
+
+	// Populate new, empty Go files. This happens before the
+	// modifications are processed, so that the edits have been applied
+	// by the time the resulting diagnostics are published.
+	var changes []protocol.DocumentChange
+	for _, f := range params.Files {
+		uri := protocol.DocumentURI(f.URI)
+		if filepath.Ext(uri.Path()) != ".go" {
+			continue
+		}
+		change, err := s.newFileChange(ctx, uri)
+		if err != nil {
+			event.Error(ctx, "populating new file", err, label.URI.Of(uri))
+			continue
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	if err := applyChanges(ctx, s.client, changes); err != nil {
+		event.Error(ctx, "populating new files", err)
+	}
+
+	var modifications []file.Modification
+	for _, f := range params.Files {
+		modifications = append(modifications, file.Modification{
+			URI:    protocol.DocumentURI(f.URI),
+			Action: file.Create,
+			OnDisk: true,
+		})
+	}
+	return s.didModifyFiles(ctx, modifications, FromDidCreateFiles)
+}
+
+// newFileChange returns the change that populates the new Go file,
+// or nil if it is not empty.
+func (s *server) newFileChange(ctx context.Context, uri protocol.DocumentURI) (*protocol.DocumentChange, error) {
+	fh, snapshot, release, err := s.fileOf(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	edits, err := golang.NewFile(ctx, snapshot, fh)
+	if err != nil || edits == nil {
+		return nil, err
+	}
+	change := protocol.DocumentChangeEdit(fh, edits)
+	return &change, nil
+}
+
 func (s *server) DidSave(ctx context.Context, params *protocol.DidSaveTextDocumentParams) error {
 	ctx, done := event.Start(ctx, "lsp.Server.didSave", label.URI.Of(params.TextDocument.URI))
 	defer done()
@@ -352,14 +474,14 @@ func (s *server) applyIncrementalChanges(ctx context.Context, uri protocol.Docum
 		content = buf.Bytes()
 		if i == 0 { // only look at the first change if there are seversl
 			// TODO(pjw): understand multi-change)
-			s.checkEfficacy(fh.URI(), fh.Version(), change)
+			s.checkEfficacy(ctx, fh.URI(), fh.Version(), change)
 		}
 	}
 	return content, nil
 }
 
 // increment counters if any of the completions look like there were used
-func (s *server) checkEfficacy(uri protocol.DocumentURI, version int32, change protocol.TextDocumentContentChangePartial) {
+func (s *server) checkEfficacy(ctx context.Context, uri protocol.DocumentURI, version int32, change protocol.TextDocumentContentChangePartial) {
 	s.efficacyMu.Lock()
 	defer s.efficacyMu.Unlock()
 	if s.efficacyURI != uri {
@@ -370,7 +492,7 @@ func (s *server) checkEfficacy(uri protocol.DocumentURI, version int32, change p
 		return
 	}
 	// does any change at pos match a proposed completion item?
-	for _, item := range s.efficacyItems {
+	for i, item := range s.efficacyItems {
 		if item.TextEdit == nil {
 			continue
 		}
@@ -396,11 +518,13 @@ func (s *server) checkEfficacy(uri protocol.DocumentURI, version int32, change p
 			if ix < 0 && strings.HasPrefix(change.Text, edit.NewText) {
 				// not a snippet, suggested completion is a prefix of the change
 				complUsed.Inc()
+				s.logCompletion(ctx, i)
 				return
 			}
 			if ix > 1 && strings.HasPrefix(change.Text, edit.NewText[:ix]) {
 				// a snippet, suggested completion up to $ marker is a prefix of the change
 				complUsed.Inc()
+				s.logCompletion(ctx, i)
 				return
 			}
 		}
@@ -408,6 +532,21 @@ func (s *server) checkEfficacy(uri protocol.DocumentURI, version int32, change p
 	complUnused.Inc()
 }
 
+// logCompletion records the acceptance of the ith item of the most
+// recent completion in the completion log, if it is enabled.
+// Each completion is recorded at most once.
+//
+// Precondition: s.efficacyMu is held.
+func (s *server) logCompletion(ctx context.Context, i int) {
+	if s.efficacyLog == "" || s.efficacyFeatures == nil {
+		return
+	}
+	if err := completion.AppendLog(s.efficacyLog, s.efficacyFeatures, i); err != nil {
+		event.Error(ctx, "writing completion log", err)
+	}
+	s.efficacyFeatures = nil
+}
+
 func changeTypeToFileAction(ct protocol.FileChangeType) file.Action {
 	switch ct {
 	case protocol.Changed:
@@ -0,0 +1,34 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/golang"
+)
+
+func TestBenchmarkComparisonsAreBounded(t *testing.T) {
+	var w web
+	var cmps []*golang.BenchmarkComparison
+	for i := range 2 * maxBenchmarkComparisons {
+		cmp := new(golang.BenchmarkComparison)
+		if id := w.addBenchmarkComparison(cmp); id != i {
+			t.Fatalf("addBenchmarkComparison returned id %d, want %d", id, i)
+		}
+		cmps = append(cmps, cmp)
+	}
+	if got := len(w.benchmarks); got != maxBenchmarkComparisons {
+		t.Errorf("retained %d comparisons, want %d", got, maxBenchmarkComparisons)
+	}
+	for id, cmp := range cmps {
+		got := w.benchmarkComparison(id)
+		if retained := id >= len(cmps)-maxBenchmarkComparisons; retained && got != cmp {
+			t.Errorf("benchmarkComparison(%d) = %p, want %p", id, got, cmp)
+		} else if !retained && got != nil {
+			t.Errorf("benchmarkComparison(%d) = %p, want nil (discarded)", id, got)
+		}
+	}
+}
@@ -62,24 +62,30 @@ func (s *server) DidChangeConfiguration(ctx context.Context, _ *protocol.DidChan
 	ctx, done := event.Start(ctx, "lsp.Server.didChangeConfiguration")
 	defer done()
 
+	// Apply any changes to the session-level settings.
+	options, err := s.fetchFolderOptions(ctx, "")
+	if err != nil {
+		return err
+	}
+	s.SetOptions(options)
+
+	return s.updateFolderOptions(ctx, FromDidChangeConfiguration)
+}
+
+// updateFolderOptions fetches the options of each workspace folder, and
+// rebuilds the views of the session if any of them have changed.
+func (s *server) updateFolderOptions(ctx context.Context, source ModificationSource) error {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	defer wg.Done()
 	if s.Options().VerboseWorkDoneProgress {
-		work := s.progress.Start(ctx, DiagnosticWorkTitle(FromDidChangeConfiguration), "Calculating diagnostics...", nil, nil)
+		work := s.progress.Start(ctx, DiagnosticWorkTitle(source), "Calculating diagnostics...", nil, nil)
 		go func() {
 			wg.Wait()
 			work.End(ctx, "Done.")
 		}()
 	}
 
-	// Apply any changes to the session-level settings.
-	options, err := s.fetchFolderOptions(ctx, "")
-	if err != nil {
-		return err
-	}
-	s.SetOptions(options)
-
 	// Collect options for all workspace folders.
 	// If none have changed, this is a no op.
 	folderOpts := make(map[protocol.DocumentURI]*settings.Options)
@@ -130,7 +136,7 @@ func (s *server) DidChangeConfiguration(ctx context.Context, _ *protocol.DidChan
 	modCtx, modID := s.needsDiagnosis(ctx, viewsToDiagnose)
 	wg.Add(1)
 	go func() {
-		s.diagnoseChangedViews(modCtx, modID, viewsToDiagnose, FromDidChangeConfiguration)
+		s.diagnoseChangedViews(modCtx, modID, viewsToDiagnose, source)
 		wg.Done()
 	}()
 
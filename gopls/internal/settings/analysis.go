@@ -46,17 +46,27 @@ import (
 	"golang.org/x/tools/go/analysis/passes/unusedresult"
 	"golang.org/x/tools/go/analysis/passes/unusedwrite"
 	"golang.org/x/tools/go/analysis/passes/waitgroup"
+	"golang.org/x/tools/gopls/internal/analysis/appendalias"
+	"golang.org/x/tools/gopls/internal/analysis/deadbranch"
 	"golang.org/x/tools/gopls/internal/analysis/deprecated"
+	"golang.org/x/tools/gopls/internal/analysis/embeddedlang"
 	"golang.org/x/tools/gopls/internal/analysis/embeddirective"
+	"golang.org/x/tools/gopls/internal/analysis/errorchain"
+	"golang.org/x/tools/gopls/internal/analysis/errorwrap"
 	"golang.org/x/tools/gopls/internal/analysis/fillreturns"
+	"golang.org/x/tools/gopls/internal/analysis/heldlock"
 	"golang.org/x/tools/gopls/internal/analysis/hostport"
 	"golang.org/x/tools/gopls/internal/analysis/infertypeargs"
+	"golang.org/x/tools/gopls/internal/analysis/loopvarcapture"
 	"golang.org/x/tools/gopls/internal/analysis/modernize"
 	"golang.org/x/tools/gopls/internal/analysis/nonewvars"
 	"golang.org/x/tools/gopls/internal/analysis/noresultvalues"
+	"golang.org/x/tools/gopls/internal/analysis/shadowedname"
 	"golang.org/x/tools/gopls/internal/analysis/simplifycompositelit"
 	"golang.org/x/tools/gopls/internal/analysis/simplifyrange"
 	"golang.org/x/tools/gopls/internal/analysis/simplifyslice"
+	"golang.org/x/tools/gopls/internal/analysis/unuseddecl"
+	"golang.org/x/tools/gopls/internal/analysis/unusedfield"
 	"golang.org/x/tools/gopls/internal/analysis/unusedfunc"
 	"golang.org/x/tools/gopls/internal/analysis/unusedparams"
 	"golang.org/x/tools/gopls/internal/analysis/unusedvariable"
@@ -182,9 +192,31 @@ func init() {
 		{analyzer: embeddirective.Analyzer},
 		{analyzer: waitgroup.Analyzer}, // to appear in cmd/vet@go1.25
 		{analyzer: hostport.Analyzer},  // to appear in cmd/vet@go1.25
+		{analyzer: embeddedlang.Analyzer},
+		{analyzer: shadowedname.Analyzer},
 
 		// disabled due to high false positives
 		{analyzer: shadow.Analyzer, nonDefault: true}, // very noisy
+		// disabled because it enforces a matter of style
+		{analyzer: errorwrap.Analyzer, nonDefault: true},
+		// disabled because some code deliberately inspects only the
+		// outermost error, and the fix changes its behavior
+		{analyzer: errorchain.Analyzer, nonDefault: true},
+		// disabled because constant conditions are often deliberate
+		{analyzer: deadbranch.Analyzer, nonDefault: true},
+		// disabled because some operations that may block are known
+		// by their authors to complete promptly, such as sends on
+		// buffered channels
+		{analyzer: heldlock.Analyzer, nonDefault: true},
+		// disabled because the slices involved are often known to
+		// be at full capacity
+		{analyzer: appendalias.Analyzer, nonDefault: true},
+		// disabled because goroutines are often waited for within
+		// the same iteration
+		{analyzer: loopvarcapture.Analyzer, nonDefault: true},
+		// disabled because fields are often kept deliberately, for
+		// example for future use or to document a wire format
+		{analyzer: unusedfield.Analyzer, severity: protocol.SeverityInformation, nonDefault: true},
 		// fieldalignment is not even off-by-default; see #67762.
 
 		// simplifiers and modernizers
@@ -213,6 +245,9 @@ func init() {
 		{analyzer: infertypeargs.Analyzer, severity: protocol.SeverityInformation},
 		{analyzer: unusedparams.Analyzer, severity: protocol.SeverityInformation},
 		{analyzer: unusedfunc.Analyzer, severity: protocol.SeverityInformation},
+		// disabled because unused constants, variables, and types are
+		// often kept deliberately, for example for documentation
+		{analyzer: unuseddecl.Analyzer, severity: protocol.SeverityInformation, nonDefault: true},
 		{analyzer: unusedwrite.Analyzer, severity: protocol.SeverityInformation}, // uses go/ssa
 		{analyzer: modernize.Analyzer, severity: protocol.SeverityHint},
 
@@ -76,21 +76,32 @@ import "golang.org/x/tools/gopls/internal/protocol"
 const (
 	// source
 	GoAssembly                 protocol.CodeActionKind = "source.assembly"
+	GoCustom                   protocol.CodeActionKind = "source.custom"
 	GoDoc                      protocol.CodeActionKind = "source.doc"
 	GoFreeSymbols              protocol.CodeActionKind = "source.freesymbols"
+	GoGenerate                 protocol.CodeActionKind = "source.generate"
+	GoGenerateConversion       protocol.CodeActionKind = "source.generateConversion"
+	GoShowGenerator            protocol.CodeActionKind = "source.showGenerator"
 	GoTest                     protocol.CodeActionKind = "source.test"
 	GoToggleCompilerOptDetails protocol.CodeActionKind = "source.toggleCompilerOptDetails"
+	GoUpdateExampleOutput      protocol.CodeActionKind = "source.updateExampleOutput"
 	AddTest                    protocol.CodeActionKind = "source.addTest"
 
 	// gopls
 	GoplsDocFeatures protocol.CodeActionKind = "gopls.doc.features"
 
 	// refactor.rewrite
+	RefactorRewriteAddFuzzSeed       protocol.CodeActionKind = "refactor.rewrite.addFuzzSeed"
+	RefactorRewriteAddTypeArgs       protocol.CodeActionKind = "refactor.rewrite.addTypeArgs"
 	RefactorRewriteChangeQuote       protocol.CodeActionKind = "refactor.rewrite.changeQuote"
 	RefactorRewriteFillStruct        protocol.CodeActionKind = "refactor.rewrite.fillStruct"
+	RefactorRewriteFillStructFrom    protocol.CodeActionKind = "refactor.rewrite.fillStructFrom"
 	RefactorRewriteFillSwitch        protocol.CodeActionKind = "refactor.rewrite.fillSwitch"
+	RefactorRewriteInferConstraint   protocol.CodeActionKind = "refactor.rewrite.inferConstraint"
+	RefactorRewriteIntroduceOptions  protocol.CodeActionKind = "refactor.rewrite.introduceOptionsStruct"
 	RefactorRewriteInvertIf          protocol.CodeActionKind = "refactor.rewrite.invertIf"
 	RefactorRewriteJoinLines         protocol.CodeActionKind = "refactor.rewrite.joinLines"
+	RefactorRewriteRemoveTypeArgs    protocol.CodeActionKind = "refactor.rewrite.removeTypeArgs"
 	RefactorRewriteRemoveUnusedParam protocol.CodeActionKind = "refactor.rewrite.removeUnusedParam"
 	RefactorRewriteMoveParamLeft     protocol.CodeActionKind = "refactor.rewrite.moveParamLeft"
 	RefactorRewriteMoveParamRight    protocol.CodeActionKind = "refactor.rewrite.moveParamRight"
@@ -51,11 +51,18 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						protocol.SourceOrganizeImports:   true,
 						protocol.QuickFix:                true,
 						GoAssembly:                       true,
+						GoCustom:                         true,
 						GoDoc:                            true,
 						GoFreeSymbols:                    true,
+						GoGenerate:                       true,
+						GoGenerateConversion:             true,
+						GoShowGenerator:                  true,
+						GoUpdateExampleOutput:            true,
 						GoplsDocFeatures:                 true,
+						RefactorRewriteAddFuzzSeed:       true,
 						RefactorRewriteChangeQuote:       true,
 						RefactorRewriteFillStruct:        true,
+						RefactorRewriteFillStructFrom:    true,
 						RefactorRewriteFillSwitch:        true,
 						RefactorRewriteInvertIf:          true,
 						RefactorRewriteJoinLines:         true,
@@ -75,8 +82,12 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						protocol.SourceOrganizeImports: true,
 						protocol.QuickFix:              true,
 					},
-					file.Work: {},
-					file.Sum:  {},
+					file.Work: {
+						protocol.QuickFix: true,
+					},
+					file.Sum: {
+						protocol.QuickFix: true,
+					},
 					file.Tmpl: {},
 				},
 				SupportedCommands: commands,
@@ -91,10 +102,11 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 				},
 				UIOptions: UIOptions{
 					DiagnosticOptions: DiagnosticOptions{
-						Vulncheck:                 ModeVulncheckOff,
-						DiagnosticsDelay:          1 * time.Second,
-						DiagnosticsTrigger:        DiagnosticsOnEdit,
-						AnalysisProgressReporting: true,
+						Vulncheck:                  ModeVulncheckOff,
+						CompilerOptDetailsSeverity: SeverityInformation,
+						DiagnosticsDelay:           1 * time.Second,
+						DiagnosticsTrigger:         DiagnosticsOnEdit,
+						AnalysisProgressReporting:  true,
 					},
 					InlayHintOptions: InlayHintOptions{},
 					DocumentationOptions: DocumentationOptions{
@@ -107,6 +119,8 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						SymbolMatcher:  SymbolFastFuzzy,
 						SymbolStyle:    DynamicSymbols,
 						SymbolScope:    AllSymbolScope,
+
+						CallHierarchyDynamicCalls: true,
 					},
 					CompletionOptions: CompletionOptions{
 						Matcher:                        Fuzzy,
@@ -122,6 +136,7 @@ func DefaultOptions(overrides ...func(*Options)) *Options {
 						CodeLensVendor:            true,
 						CodeLensRunGovulncheck:    false, // TODO(hyangah): enable
 					},
+					LargeFileSize: 1_000_000,
 				},
 			},
 			InternalOptions: InternalOptions{
@@ -7,8 +7,10 @@ package settings
 import (
 	"fmt"
 	"maps"
+	"path"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"golang.org/x/tools/gopls/internal/file"
@@ -63,6 +65,9 @@ type ClientOptions struct {
 	SupportedResourceOperations                []protocol.ResourceOperationKind
 	CodeActionResolveOptions                   []string
 	ShowDocumentSupported                      bool
+	RenameChangeAnnotationsSupported           bool
+	CodeActionChangeAnnotationsSupported       bool
+	ApplyEditSupported                         bool
 }
 
 // ServerOptions holds LSP-specific configuration that is provided by the
@@ -147,6 +152,24 @@ type BuildOptions struct {
 	//
 	// This setting need only be customized in environments with a custom GOPACKAGESDRIVER.
 	WorkspaceFiles []string
+
+	// BuildConfigurations is a list of additional build configurations,
+	// each of the form `GOOS/GOARCH`, under which gopls type-checks the
+	// workspace in the background.
+	//
+	// Diagnostics that arise only in an additional configuration are
+	// reported alongside those of the default configuration, with a
+	// suffix naming the parts of the configuration that differ from the
+	// default GOOS and GOARCH, such as `[windows]` or `[js,wasm]`. This
+	// can be used to detect portability problems before they are
+	// reported by CI.
+	//
+	// Example: `["windows/amd64", "js/wasm"]`
+	//
+	// Each configuration requires gopls to load and type-check the
+	// workspace again, so this setting increases memory use and CPU
+	// time roughly in proportion to the number of configurations.
+	BuildConfigurations []string `status:"experimental"`
 }
 
 // Note: UIOptions must be comparable with reflect.DeepEqual.
@@ -198,6 +221,56 @@ type UIOptions struct {
 	// disabling modifiers by setting each value to false.
 	// By default, all modifiers are enabled.
 	SemanticTokenModifiers map[string]bool `status:"experimental"`
+
+	// LinkedEditingStructTags enables linked editing of the name of a
+	// struct field together with each name in its tag that is spelled
+	// the same way, such as `json:"Name"` for a field Name.
+	LinkedEditingStructTags bool `status:"experimental"`
+
+	// LockGeneratedFiles makes generated Go files, those with a "Code
+	// generated ... DO NOT EDIT." comment, effectively read-only in the
+	// editor: gopls undoes each edit to such a file by asking the
+	// client to restore the file's saved content. It has no effect if
+	// the client does not support workspace/applyEdit requests.
+	LockGeneratedFiles bool `status:"experimental"`
+
+	// LargeFileSize is the size in bytes beyond which gopls treats a Go
+	// file, typically a machine-generated one, as too large for full
+	// support, and operates on it in a degraded mode: it reports only
+	// the file's syntax errors, not its type errors or analysis
+	// findings, and provides no semantic tokens for it, while features
+	// based on syntax alone, such as folding ranges and document
+	// symbols, remain available. An informational diagnostic at the
+	// package clause of the file indicates that it is in degraded mode.
+	//
+	// Other files of the package are unaffected. Zero means no limit.
+	LargeFileSize int `status:"experimental"`
+
+	// CustomCodeActionsFile is the name of a JSON file, relative to
+	// the workspace folder, that defines workspace-specific code
+	// actions, each of which runs an external command. For example:
+	//
+	// ```json
+	// [
+	//   {
+	//     "title": "Add telemetry counter",
+	//     "files": "*.go",
+	//     "command": ["go", "run", "./internal/addcounter", "-line=${startLine}", "${file}"],
+	//     "applyEdits": true
+	//   }
+	// ]
+	// ```
+	//
+	// These actions are offered with the kind `source.custom` in
+	// files whose base name (or, if the pattern contains a slash,
+	// whose path relative to the workspace folder) matches the
+	// `files` pattern. See [Custom code actions](features/transformation.md#source.custom)
+	// for details.
+	//
+	// Because the actions run arbitrary commands, gopls reads them
+	// only from the file named by this setting, and never from a
+	// fixed location within the workspace.
+	CustomCodeActionsFile string `status:"experimental"`
 }
 
 // A CodeLensSource identifies an (algorithmic) source of code lenses.
@@ -263,6 +336,12 @@ const (
 	//
 	// This codelens source annotates each `Test` and `Benchmark`
 	// function in a `*_test.go` file with a command to run it.
+	// Each `Benchmark` function is also annotated with a command to
+	// compare its results with those of the HEAD revision (or, if the
+	// package has unsaved changes, with those of the saved files),
+	// which shows a report in the manner of benchstat.
+	// Each `Fuzz` function is annotated with commands to fuzz it
+	// for 30s, and to browse its corpus.
 	//
 	// This source is off by default because VS Code has
 	// a client-side custom UI for testing, and because progress
@@ -329,6 +408,29 @@ type CompletionOptions struct {
 	// expected of the expression being completed, completion may suggest call
 	// expressions (i.e. may include parentheses).
 	CompleteFunctionCalls bool
+
+	// CompletionRankerWeights is the name of a JSON file of weights
+	// for a linear model that ranks completion candidates in place of
+	// the built-in heuristics. The model's features are those recorded
+	// by the completion log; see `completionLog`.
+	//
+	// The file is an object whose fields "bias", "score", "depth",
+	// "deprecated", "edits", and "labelLen" are numbers, and whose
+	// field "kinds" maps completion item kinds such as "func" to
+	// numbers. The "score" feature is the relevance computed by the
+	// built-in heuristics, so the weights `{"score": 1}` rank
+	// candidates as gopls does by default.
+	CompletionRankerWeights string `status:"experimental"`
+
+	// CompletionLog is the name of a file to which gopls appends a
+	// record of each accepted completion: the features of the
+	// candidates that were offered, in order, and which one was
+	// chosen. It is intended for users who wish to train a ranker of
+	// their own; see `completionRankerWeights`.
+	//
+	// The log is written only if this setting is non-empty, and it
+	// stays on the local machine: gopls never sends it anywhere.
+	CompletionLog string `status:"experimental"`
 }
 
 // Note: DocumentationOptions must be comparable with reflect.DeepEqual.
@@ -355,6 +457,12 @@ type DocumentationOptions struct {
 
 	// LinksInHover controls the presence of documentation links in hover markdown.
 	LinksInHover LinksInHoverEnum
+
+	// MaxHoverLines is the maximum number of lines of documentation
+	// shown in hover. Longer documentation is truncated after the
+	// last paragraph, list, or code block that fits, and followed by
+	// a link to the full documentation. Zero means no limit.
+	MaxHoverLines int `status:"experimental"`
 }
 
 // LinksInHoverEnum has legal values:
@@ -404,6 +512,40 @@ type FormattingOptions struct {
 
 	// Gofumpt indicates if we should run gofumpt formatting.
 	Gofumpt bool
+
+	// Formatters is a list of external formatting commands that gopls
+	// runs, in order, on a Go file after its built-in formatter (and
+	// gofumpt, if enabled). Each command is a list of strings: the
+	// name or path of the program, followed by its arguments.
+	//
+	// Like gofmt, a formatter reads the source of the file from its
+	// standard input and writes the formatted source to its standard
+	// output. It is run in the directory of the file, in the
+	// environment of the go command, and must not modify any files;
+	// gopls computes the edits to the file from its output. If a
+	// formatter fails, or its output is not valid Go, formatting fails
+	// with an error that includes the formatter's standard error.
+	//
+	// Example Usage:
+	//
+	// ```json5
+	// "formatters": [
+	//   ["gofumpt", "-extra"],
+	//   ["/path/to/rewrite-imports", "-local", "example.com"]
+	// ]
+	// ```
+	Formatters [][]string `status:"experimental"`
+
+	// NewFileHeader is the text of the comment, such as a license
+	// header, with which gopls populates each new Go file created by
+	// the client, above its package clause. Each line of the text
+	// becomes a `//` comment line. The text is a
+	// [template](https://pkg.go.dev/text/template) in which
+	// `{{.Year}}` denotes the current year.
+	//
+	// If empty, gopls copies the copyright header, if any, of another
+	// file in the same directory.
+	NewFileHeader string
 }
 
 // Note: DiagnosticOptions must be comparable with reflect.DeepEqual.
@@ -433,6 +575,57 @@ type DiagnosticOptions struct {
 	// Vulncheck enables vulnerability scanning.
 	Vulncheck VulncheckMode `status:"experimental"`
 
+	// StaleGeneratedFiles enables a warning on each generated Go file
+	// that is older, by modification time on disk, than the files from
+	// which it was generated, with a quick fix to run `go generate` in
+	// its directory.
+	//
+	// The inputs of a generated file are the files in its directory
+	// containing a `//go:generate` directive for the generator, such as
+	// `stringer`, named by the file's "Code generated" comment, plus any
+	// files specified by the generatedFileInputs setting.
+	StaleGeneratedFiles bool `status:"experimental"`
+
+	// GeneratedFileInputs specifies additional inputs of generated
+	// files, for the staleGeneratedFiles check. Each key is a glob
+	// pattern matching the base names of generated files, and its value
+	// is a list of glob patterns, relative to the directory of the
+	// generated file, of the files from which it is generated.
+	//
+	// Example Usage:
+	//
+	// ```json5
+	// ...
+	// "generatedFileInputs": {
+	//   "*.pb.go": ["../proto/*.proto"],
+	//   "mock_*.go": ["*.go"]
+	// }
+	// ...
+	// ```
+	GeneratedFileInputs map[string][]string `status:"experimental"`
+
+	// CompilerOptDetails specifies the kinds of compiler optimization
+	// decisions that are reported as diagnostics when optimization
+	// details are enabled for a package, using the "Toggle compiler
+	// optimization details" code action. A kind that is absent from the
+	// map is reported.
+	//
+	// Example Usage:
+	//
+	// ```json5
+	// ...
+	// "compilerOptDetails": {
+	//   "bounds": false, // Hide bounds-check decisions.
+	//   "nil": false     // Hide nil-check decisions.
+	// }
+	// ...
+	// ```
+	CompilerOptDetails map[CompilerOptDetail]bool `status:"experimental"`
+
+	// CompilerOptDetailsSeverity controls the severity of the
+	// diagnostics that report compiler optimization decisions.
+	CompilerOptDetailsSeverity DiagnosticSeverity `status:"experimental"`
+
 	// DiagnosticsDelay controls the amount of time that gopls waits
 	// after the most recent file modification before computing deep diagnostics.
 	// Simple diagnostics (parsing and type-checking) are always run immediately
@@ -445,9 +638,11 @@ type DiagnosticOptions struct {
 	DiagnosticsTrigger DiagnosticsTrigger `status:"experimental"`
 
 	// AnalysisProgressReporting controls whether gopls sends progress
-	// notifications when construction of its index of analysis facts is taking a
-	// long time. Cancelling these notifications will cancel the indexing task,
-	// though it will restart after the next change in the workspace.
+	// notifications when construction of its index of analysis facts, or
+	// of the type information of the workspace packages, is taking a long
+	// time. Cancelling the notifications for analysis will cancel the
+	// indexing task, though it will restart after the next change in the
+	// workspace.
 	//
 	// When a package is opened for the first time and heavyweight analyses such as
 	// staticcheck are enabled, it can take a while to construct the index of
@@ -519,6 +714,7 @@ const (
 	// FunctionTypeParameters inlay hints for implicit type parameters on generic functions:
 	// ```go
 	// 	myFoo/*[int, string]*/(1, "hello")
+	// 	pkg.Pair[int/*, string*/](1, "hello")
 	// ```
 	FunctionTypeParameters InlayHint = "functionTypeParameters"
 )
@@ -549,6 +745,27 @@ type NavigationOptions struct {
 	// packages. When the scope is "all", gopls searches all loaded packages,
 	// including dependencies and the standard library.
 	SymbolScope SymbolScope
+
+	// CallHierarchyDynamicCalls controls whether the call hierarchy
+	// includes dynamic calls through interface methods.
+	//
+	// When enabled, the incoming calls of a concrete method include
+	// the calls of the interface methods that it implements, and the
+	// outgoing calls of a function include, for each call of an
+	// interface method, the concrete methods that implement it.
+	// Such calls are marked "dynamic" in their details.
+	CallHierarchyDynamicCalls bool
+
+	// RenameInCommentsAndStrings controls whether renaming a
+	// package-level declaration, field, or method also updates the
+	// occurrences of its old name, as a whole word, in the comments
+	// and string literals of the package that declares it, such as
+	// "Foo" in "// See Foo." or "Foo failed".
+	//
+	// If the client supports change annotations, these edits are
+	// marked as needing confirmation, so that the rename preview
+	// allows each of them to be deselected.
+	RenameInCommentsAndStrings bool
 }
 
 // UserOptions holds custom Gopls configuration (not part of the LSP) that is
@@ -664,7 +881,7 @@ type InternalOptions struct {
 	SubdirWatchPatterns SubdirWatchPatterns
 
 	// ReportAnalysisProgressAfter sets the duration for gopls to wait before starting
-	// progress reporting for ongoing go/analysis passes.
+	// progress reporting for ongoing go/analysis passes and indexing.
 	//
 	// It is intended to be used for testing only.
 	ReportAnalysisProgressAfter time.Duration
@@ -813,6 +1030,53 @@ const (
 	// TODO: support "Manual"?
 )
 
+// A CompilerOptDetail identifies a kind of compiler optimization
+// decision that may be independently reported through the
+// "compilerOptDetails" setting.
+type CompilerOptDetail string
+
+const (
+	// CompilerOptDetailBounds reports whether index and slice operations
+	// need a bounds check.
+	CompilerOptDetailBounds CompilerOptDetail = "bounds"
+
+	// CompilerOptDetailEscape reports variables that escape to the heap, and
+	// parameters whose values leak.
+	CompilerOptDetailEscape CompilerOptDetail = "escape"
+
+	// CompilerOptDetailInline reports whether functions can be inlined, and which
+	// calls are inlined.
+	CompilerOptDetailInline CompilerOptDetail = "inline"
+
+	// CompilerOptDetailNil reports the nil checks inserted by the compiler.
+	CompilerOptDetailNil CompilerOptDetail = "nil"
+)
+
+// A DiagnosticSeverity is the severity of a class of diagnostics.
+type DiagnosticSeverity string
+
+const (
+	// Report diagnostics as hints, which many editors do not display
+	// in the list of problems.
+	SeverityHint DiagnosticSeverity = "Hint"
+	// Report diagnostics as information. (default)
+	SeverityInformation DiagnosticSeverity = "Information"
+	// Report diagnostics as warnings.
+	SeverityWarning DiagnosticSeverity = "Warning"
+)
+
+// Protocol returns the LSP severity corresponding to s.
+func (s DiagnosticSeverity) Protocol() protocol.DiagnosticSeverity {
+	switch s {
+	case SeverityHint:
+		return protocol.SeverityHint
+	case SeverityWarning:
+		return protocol.SeverityWarning
+	default:
+		return protocol.SeverityInformation
+	}
+}
+
 // Set updates *options based on the provided JSON value:
 // null, bool, string, number, array, or object.
 // On failure, it returns one or more non-nil errors.
@@ -852,6 +1116,10 @@ func (o *Options) ForClientCapabilities(clientInfo *protocol.ClientInfo, caps pr
 	}
 	if caps.Workspace.WorkspaceEdit != nil {
 		o.SupportedResourceOperations = caps.Workspace.WorkspaceEdit.ResourceOperations
+		o.RenameChangeAnnotationsSupported = caps.Workspace.WorkspaceEdit.ChangeAnnotationSupport != nil &&
+			caps.TextDocument.Rename != nil && caps.TextDocument.Rename.HonorsChangeAnnotations
+		o.CodeActionChangeAnnotationsSupported = caps.Workspace.WorkspaceEdit.ChangeAnnotationSupport != nil &&
+			caps.TextDocument.CodeAction.HonorsChangeAnnotations
 	}
 	// Check if the client supports snippets in completion items.
 	if c := caps.TextDocument.Completion; c.CompletionItem.SnippetSupport {
@@ -861,6 +1129,7 @@ func (o *Options) ForClientCapabilities(clientInfo *protocol.ClientInfo, caps pr
 	if caps.Window.ShowDocument != nil {
 		o.ShowDocumentSupported = caps.Window.ShowDocument.Support
 	}
+	o.ApplyEditSupported = caps.Workspace.ApplyEdit
 	// Check if the client supports configuration messages.
 	o.ConfigurationSupported = caps.Workspace.Configuration
 	o.DynamicConfigurationSupported = caps.Workspace.DidChangeConfiguration.DynamicRegistration
@@ -978,6 +1247,19 @@ func (o *Options) setOne(name string, value any) error {
 
 	case "workspaceFiles":
 		return setStringSlice(&o.WorkspaceFiles, value)
+
+	case "buildConfigurations":
+		configs, err := asStringSlice(value)
+		if err != nil {
+			return err
+		}
+		for _, config := range configs {
+			goos, goarch, ok := strings.Cut(config, "/")
+			if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+				return fmt.Errorf("invalid build configuration %q (want GOOS/GOARCH)", config)
+			}
+		}
+		o.BuildConfigurations = configs
 	case "completionDocumentation":
 		return setBool(&o.CompletionDocumentation, value)
 	case "usePlaceholders":
@@ -988,6 +1270,10 @@ func (o *Options) setOne(name string, value any) error {
 		return setBool(&o.CompleteUnimported, value)
 	case "completionBudget":
 		return setDuration(&o.CompletionBudget, value)
+	case "completionRankerWeights":
+		return setString(&o.CompletionRankerWeights, value)
+	case "completionLog":
+		return setString(&o.CompletionLog, value)
 	case "importsSource":
 		return setEnum(&o.ImportsSource, value,
 			ImportsSourceOff,
@@ -1017,6 +1303,12 @@ func (o *Options) setOne(name string, value any) error {
 			WorkspaceSymbolScope,
 			AllSymbolScope)
 
+	case "callHierarchyDynamicCalls":
+		return setBool(&o.CallHierarchyDynamicCalls, value)
+
+	case "renameInCommentsAndStrings":
+		return setBool(&o.RenameInCommentsAndStrings, value)
+
 	case "hoverKind":
 		if s, ok := value.(string); ok && strings.EqualFold(s, "structured") {
 			return deprecatedError("the experimental hoverKind='structured' setting was removed in gopls/v0.18.0 (https://go.dev/issue/70233)")
@@ -1043,6 +1335,9 @@ func (o *Options) setOne(name string, value any) error {
 				value)
 		}
 
+	case "maxHoverLines":
+		return setInt(&o.MaxHoverLines, value)
+
 	case "importShortcut":
 		return setEnum(&o.ImportShortcut, value,
 			BothShortcuts,
@@ -1061,7 +1356,7 @@ func (o *Options) setOne(name string, value any) error {
 		return setBoolMap(&o.Hints, value)
 
 	case "annotations":
-		return deprecatedError("the 'annotations' setting was removed in gopls/v0.18.0; all compiler optimization details are now shown")
+		return deprecatedError("the 'annotations' setting was removed in gopls/v0.18.0; use 'compilerOptDetails' to select the kinds of compiler optimization details to show")
 
 	case "vulncheck":
 		return setEnum(&o.Vulncheck, value,
@@ -1086,6 +1381,41 @@ func (o *Options) setOne(name string, value any) error {
 	case "staticcheck":
 		return setBool(&o.Staticcheck, value)
 
+	case "compilerOptDetails":
+		return setBoolMap(&o.CompilerOptDetails, value)
+
+	case "compilerOptDetailsSeverity":
+		return setEnum(&o.CompilerOptDetailsSeverity, value,
+			SeverityHint,
+			SeverityInformation,
+			SeverityWarning)
+
+	case "staleGeneratedFiles":
+		return setBool(&o.StaleGeneratedFiles, value)
+
+	case "generatedFileInputs":
+		rules, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("invalid type %T (want JSON object)", value)
+		}
+		inputs := make(map[string][]string)
+		for pattern, v := range rules {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+			}
+			globs, err := asStringSlice(v)
+			if err != nil {
+				return fmt.Errorf("invalid inputs of %q: %v", pattern, err)
+			}
+			for _, glob := range globs {
+				if _, err := path.Match(glob, ""); err != nil {
+					return fmt.Errorf("invalid pattern %q: %v", glob, err)
+				}
+			}
+			inputs[pattern] = globs
+		}
+		o.GeneratedFileInputs = inputs
+
 	case "local":
 		return setString(&o.Local, value)
 
@@ -1101,12 +1431,52 @@ func (o *Options) setOne(name string, value any) error {
 	case "gofumpt":
 		return setBool(&o.Gofumpt, value)
 
+	case "formatters":
+		array, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("invalid type %T (want JSON array of arrays of string)", value)
+		}
+		var formatters [][]string
+		for _, elem := range array {
+			command, err := asStringSlice(elem)
+			if err != nil {
+				return err
+			}
+			if len(command) == 0 || command[0] == "" {
+				return fmt.Errorf("empty formatter command")
+			}
+			formatters = append(formatters, command)
+		}
+		o.Formatters = formatters
+
+	case "newFileHeader":
+		header, err := asString(value)
+		if err != nil {
+			return err
+		}
+		if _, err := template.New("newFileHeader").Parse(header); err != nil {
+			return err
+		}
+		o.NewFileHeader = header
+
 	case "completeFunctionCalls":
 		return setBool(&o.CompleteFunctionCalls, value)
 
 	case "semanticTokens":
 		return setBool(&o.SemanticTokens, value)
 
+	case "linkedEditingStructTags":
+		return setBool(&o.LinkedEditingStructTags, value)
+
+	case "lockGeneratedFiles":
+		return setBool(&o.LockGeneratedFiles, value)
+
+	case "largeFileSize":
+		return setInt(&o.LargeFileSize, value)
+
+	case "customCodeActionsFile":
+		return setString(&o.CustomCodeActionsFile, value)
+
 	// TODO(hxjiang): deprecate noSemanticString and noSemanticNumber.
 	case "noSemanticString":
 		if err := setBool(&o.NoSemanticString, value); err != nil {
@@ -1339,6 +1709,26 @@ func asBool(value any) (bool, error) {
 	return b, nil
 }
 
+func setInt(dest *int, value any) error {
+	var i int
+	switch v := value.(type) {
+	case int:
+		i = v
+	case float64: // JSON numbers are decoded as float64
+		i = int(v)
+		if float64(i) != v {
+			return fmt.Errorf("invalid value %v (want integer)", v)
+		}
+	default:
+		return fmt.Errorf("invalid type %T (want integer)", value)
+	}
+	if i < 0 {
+		return fmt.Errorf("invalid value %d (want non-negative integer)", i)
+	}
+	*dest = i
+	return nil
+}
+
 func setDuration(dest *time.Duration, value any) error {
 	str, err := asString(value)
 	if err != nil {
@@ -120,6 +120,21 @@ func TestOptions_Set(t *testing.T) {
 				return o.HoverKind == FullDocumentation
 			},
 		},
+		{
+			name:  "maxHoverLines",
+			value: 10.0,
+			check: func(o Options) bool {
+				return o.MaxHoverLines == 10
+			},
+		},
+		{
+			name:      "maxHoverLines",
+			value:     1.5,
+			wantError: true,
+			check: func(o Options) bool {
+				return o.MaxHoverLines == 0
+			},
+		},
 		{
 			name:  "matcher",
 			value: "Fuzzy",
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"go/scanner"
 	"go/token"
+	"go/types"
+	"regexp"
 	"strings"
 
 	"golang.org/x/tools/gopls/internal/cache"
@@ -24,6 +26,7 @@ type completer struct {
 	offset int // offset of the start of the Token
 	ctx    protocol.CompletionContext
 	syms   map[string]symbol
+	dot    types.Type // type of the template's data value, if known
 }
 
 func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position, context protocol.CompletionContext) (*protocol.CompletionList, error) {
@@ -53,9 +56,23 @@ func Completion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, p
 		ctx:    context,
 		syms:   syms,
 	}
+	// If completing a field chain on dot, try to find the type of
+	// the template's data value.
+	if offset := p.FromPosition(pos); c.offset <= offset && chainRe.Match(p.buf[c.offset:offset]) {
+		if name, isData := p.enclosingTemplate(fh.URI().Path(), offset); isData {
+			dots, err := dataTypes(ctx, snapshot)
+			if err != nil {
+				return nil, err
+			}
+			c.dot = dots[name]
+		}
+	}
 	return c.complete()
 }
 
+// chainRe matches a trailing field chain on dot, such as ".Foo.Ba".
+var chainRe = regexp.MustCompile(`(^|[^$\w.])(\.\w*)+$`)
+
 func filterSyms(syms map[string]symbol, ns []symbol) {
 	for _, xsym := range ns {
 		switch xsym.kind {
@@ -131,6 +148,15 @@ func (c *completer) complete() (*protocol.CompletionList, error) {
 		return nil, nil // if this happens, why were we called?
 	}
 	pattern := words[len(words)-1]
+	if c.dot != nil {
+		if m := chainRe.FindSubmatchIndex(sofar); m != nil {
+			chain := string(sofar[m[3]:])
+			if items := typedCompletions(c.dot, chain); items != nil {
+				ans.Items = items
+				return ans, nil
+			}
+		}
+	}
 	if pattern[0] == '$' {
 		// should we also return a raw "$"?
 		for _, s := range c.syms {
@@ -0,0 +1,242 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template
+
+// This file infers the type of the data value ("dot") passed to each
+// named template, by inspecting calls to ExecuteTemplate in the
+// workspace's Go packages, and uses it to offer completions of
+// fields and methods.
+
+import (
+	"context"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/internal/analysisinternal"
+)
+
+// dataTypes returns a mapping from template name to the type of the
+// data value passed to it, as determined by calls of the form
+// t.ExecuteTemplate(w, "name", data) in workspace packages that
+// import text/template or html/template.
+//
+// A name whose call sites disagree about the type is omitted.
+func dataTypes(ctx context.Context, snapshot *cache.Snapshot) (map[string]types.Type, error) {
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []metadata.PackageID
+	for _, mp := range mps {
+		if mp.IsIntermediateTestVariant() {
+			continue
+		}
+		if _, ok := mp.DepsByPkgPath["text/template"]; ok {
+			ids = append(ids, mp.ID)
+		} else if _, ok := mp.DepsByPkgPath["html/template"]; ok {
+			ids = append(ids, mp.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	pkgs, err := snapshot.TypeCheck(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]types.Type)
+	conflict := make(map[string]bool)
+	for _, pkg := range pkgs {
+		info := pkg.TypesInfo()
+		for _, f := range pkg.Syntax() {
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) != 3 {
+					return true
+				}
+				fn := typeutil.Callee(info, call)
+				if !analysisinternal.IsMethodNamed(fn, "text/template", "Template", "ExecuteTemplate") &&
+					!analysisinternal.IsMethodNamed(fn, "html/template", "Template", "ExecuteTemplate") {
+					return true
+				}
+				tv, ok := info.Types[call.Args[1]]
+				if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+					return true
+				}
+				name := constant.StringVal(tv.Value)
+				t := info.TypeOf(call.Args[2])
+				if t == nil || conflict[name] {
+					return true
+				}
+				if prev, ok := result[name]; ok && !types.Identical(prev, t) {
+					delete(result, name)
+					conflict[name] = true
+					return true
+				}
+				result[name] = t
+				return true
+			})
+		}
+	}
+	return result, nil
+}
+
+// enclosingTemplate returns the name of the template whose body
+// contains the given offset, and whether dot at that offset is the
+// template's data value (that is, the offset is not within a
+// {{range}} or {{with}} action, which rebind dot).
+//
+// Outside any {{define}} or {{block}}, the name is the base name of
+// the file, which is the name given by ParseFiles and ParseGlob.
+//
+// It scans the tokens rather than the parse tree, so that it works
+// even when the template is incomplete.
+func (p *Parsed) enclosingTemplate(filename string, offset int) (name string, isData bool) {
+	type frame struct {
+		keyword string
+		name    string // for define and block
+	}
+	var stack []frame
+	for _, tok := range p.tokens {
+		if tok.Start >= offset {
+			break
+		}
+		if tok.End > offset {
+			break // the token containing the cursor
+		}
+		words := scan(p.action(tok))
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "define", "block":
+			f := frame{keyword: words[0]}
+			if len(words) > 1 {
+				f.name = strings.Trim(words[1], "\"`")
+			}
+			stack = append(stack, f)
+		case "if", "range", "with":
+			stack = append(stack, frame{keyword: words[0]})
+		case "else":
+			// {{else with x}} rebinds dot, like {{with}}.
+			if len(words) > 1 && words[1] == "with" && len(stack) > 0 {
+				stack[len(stack)-1].keyword = "with"
+			}
+		case "end":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	name = path.Base(filename)
+	isData = true
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].keyword {
+		case "define", "block":
+			return stack[i].name, isData
+		case "range", "with":
+			isData = false
+		}
+	}
+	return name, isData
+}
+
+// action returns the text of the token, without delimiters or
+// whitespace-trimming markers.
+func (p *Parsed) action(tok Token) []byte {
+	s := p.buf[tok.Start+len(Left) : tok.End-len(Right)]
+	s = trimMarker(s)
+	return s
+}
+
+func trimMarker(s []byte) []byte {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	if len(s) > 0 && s[len(s)-1] == '-' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// typedCompletions returns completions for the fields and methods
+// named by the partial chain pattern (such as ".Foo.Ba") relative to
+// a dot of type t. It returns nil if the chain cannot be resolved.
+func typedCompletions(t types.Type, pattern string) []protocol.CompletionItem {
+	fields := strings.Split(pattern, ".")[1:] // pattern starts with "."
+	for _, f := range fields[:len(fields)-1] {
+		t = selectType(t, f)
+		if t == nil {
+			return nil
+		}
+	}
+	prefix := "." + fields[len(fields)-1]
+
+	var items []protocol.CompletionItem
+	seen := make(map[string]bool)
+	add := func(name string, kind protocol.CompletionItemKind, detail string) {
+		if seen[name] || !ast.IsExported(name) || weakMatch("."+name, prefix) == 0 {
+			return
+		}
+		seen[name] = true
+		items = append(items, protocol.CompletionItem{
+			Label:  name,
+			Kind:   kind,
+			Detail: detail,
+		})
+	}
+	for _, sel := range typeutil.IntuitiveMethodSet(t, nil) {
+		add(sel.Obj().Name(), protocol.MethodCompletion, types.TypeString(sel.Type(), nil))
+	}
+	var addFields func(t types.Type, depth int)
+	addFields = func(t types.Type, depth int) {
+		if depth > 5 {
+			return // avoid cycles through embedded pointers
+		}
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		s, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return
+		}
+		for i := 0; i < s.NumFields(); i++ {
+			f := s.Field(i)
+			add(f.Name(), protocol.FieldCompletion, types.TypeString(f.Type(), nil))
+			if f.Embedded() {
+				addFields(f.Type(), depth+1)
+			}
+		}
+	}
+	addFields(t, 0)
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+// selectType returns the type of the field or niladic method of t
+// with the given name, or nil if there is none.
+func selectType(t types.Type, name string) types.Type {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+	switch obj := obj.(type) {
+	case *types.Var:
+		return obj.Type()
+	case *types.Func:
+		sig := obj.Type().(*types.Signature)
+		if sig.Params().Len() == 0 && sig.Results().Len() > 0 {
+			return sig.Results().At(0).Type()
+		}
+	}
+	return nil
+}
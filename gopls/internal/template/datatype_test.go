@@ -0,0 +1,89 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnclosingTemplate(t *testing.T) {
+	for _, test := range []struct {
+		marked string // ^ marks the offset
+		name   string
+		isData bool
+	}{
+		{"{{.^}}", "page.tmpl", true},
+		{`{{define "a"}}{{.^}}{{end}}`, "a", true},
+		{`{{define "a"}}{{end}}{{.^}}`, "page.tmpl", true},
+		{`{{define "a"}}{{range .X}}{{.^}}{{end}}{{end}}`, "a", false},
+		{`{{define "a"}}{{if .X}}{{.^}}{{end}}{{end}}`, "a", true},
+		{`{{- block "b" . -}}{{with .X}}{{else}}{{.^}}{{end}}{{end}}`, "b", false},
+		{`{{define "a"}}{{with .X}}{{end}}{{.^}}{{end}}`, "a", true},
+	} {
+		offset := strings.Index(test.marked, "^")
+		buf := strings.Replace(test.marked, "^", "", 1)
+		p := parseBuffer([]byte(buf))
+		name, isData := p.enclosingTemplate("/dir/page.tmpl", offset)
+		if name != test.name || isData != test.isData {
+			t.Errorf("%s: enclosingTemplate = %q, %t, want %q, %t", test.marked, name, isData, test.name, test.isData)
+		}
+	}
+}
+
+func TestTypedCompletions(t *testing.T) {
+	const src = `package p
+
+type Page struct {
+	Title string
+	User  *User
+	Meta
+	hidden int
+}
+
+type Meta struct{ Tags []string }
+
+type User struct{ Name string }
+
+func (u *User) Greeting() string { return "" }
+func (p Page) Total(n int) int  { return n }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := pkg.Scope().Lookup("Page").Type()
+
+	for _, test := range []struct {
+		pattern string
+		want    []string
+	}{
+		{".", []string{"Meta", "Tags", "Title", "Total", "User"}},
+		{".T", []string{"Tags", "Title", "Total"}},
+		{".User.", []string{"Greeting", "Name"}},
+		{".User.Na", []string{"Name"}},
+		{".Nope.", nil},
+	} {
+		var got []string
+		for _, item := range typedCompletions(page, test.pattern) {
+			got = append(got, item.Label)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("typedCompletions(%q) = %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// isTemplateName reports whether the symbol is the name of a
+// template, either in its definition ({{define "x"}}, {{block "x"}})
+// or in an invocation ({{template "x"}}).
+func isTemplateName(sym *symbol) bool {
+	return sym.kind == protocol.Namespace || sym.kind == protocol.Package
+}
+
+// PrepareRename reports the range and text of the template name at
+// the given position. Only template names may be renamed.
+func PrepareRename(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position) (protocol.Range, string, error) {
+	sym, p, err := symAtPosition(fh, pos)
+	if err != nil {
+		return protocol.Range{}, "", err
+	}
+	if !isTemplateName(sym) {
+		return protocol.Range{}, "", fmt.Errorf("can only rename template names")
+	}
+	return p.Range(sym.start, sym.length), sym.name, nil
+}
+
+// Rename renames the template name at the given position, updating
+// its definitions and invocations in all template files of the
+// snapshot.
+//
+// References in Go code (for example, calls to ExecuteTemplate or
+// Lookup with the name as a string literal) are not updated.
+func Rename(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position, newName string) (map[protocol.DocumentURI][]protocol.TextEdit, error) {
+	sym, _, err := symAtPosition(fh, pos)
+	if err != nil {
+		return nil, err
+	}
+	if !isTemplateName(sym) {
+		return nil, fmt.Errorf("can only rename template names")
+	}
+	if newName == "" || strings.ContainsAny(newName, "\"`\\\n") {
+		return nil, fmt.Errorf("invalid template name %q", newName)
+	}
+
+	edits := make(map[protocol.DocumentURI][]protocol.TextEdit)
+	a := New(snapshot.Templates())
+	for uri, p := range a.files {
+		if p.ParseErr != nil && bytes.Contains(p.buf, []byte(strconv.Quote(sym.name))) {
+			return nil, fmt.Errorf("cannot rename: %s has parse errors", uri.Path())
+		}
+		seen := make(map[int]bool) // {{block}} is both a definition and a use
+		for _, s := range p.symbols {
+			if s.name == sym.name && isTemplateName(&s) && !seen[s.start] {
+				seen[s.start] = true
+				edits[uri] = append(edits[uri], protocol.TextEdit{
+					Range:   p.Range(s.start, s.length),
+					NewText: newName,
+				})
+			}
+		}
+	}
+	return edits, nil
+}
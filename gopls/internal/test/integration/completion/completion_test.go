@@ -5,8 +5,10 @@
 package completion
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
@@ -15,6 +17,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/telemetry/counter"
 	"golang.org/x/telemetry/counter/countertest"
+	"golang.org/x/tools/gopls/internal/golang/completion"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/server"
 	. "golang.org/x/tools/gopls/internal/test/integration"
@@ -1430,3 +1433,67 @@ func main() {
 		}
 	})
 }
+
+func TestCompletionRankerAndLog(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- main.go --
+package main
+
+var aaaLong, aab int
+
+func main() {
+	_ = aa
+}
+`
+	dir := t.TempDir()
+	weights := filepath.Join(dir, "weights.json")
+	if err := os.WriteFile(weights, []byte(`{"score": 1, "labelLen": -1}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(dir, "completion.log")
+
+	WithOptions(
+		Settings{
+			"completionRankerWeights": weights,
+			"completionLog":           logFile,
+		},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		loc := env.RegexpSearch("main.go", `_ = aa()`)
+		completions := env.Completion(loc)
+		if len(completions.Items) < 2 {
+			t.Fatalf("got %d completion items, want at least 2", len(completions.Items))
+		}
+		// Without the weights, the items have equal scores and are
+		// ordered by label, so aaaLong would be first.
+		if got := completions.Items[0].Label; got != "aab" {
+			t.Errorf("first completion item is %q, want aab", got)
+		}
+		env.AcceptCompletion(loc, completions.Items[0])
+		env.Await(env.DoneWithChange())
+
+		// The log is shared by all execution modes of the test,
+		// so examine only its last entry.
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		var entry completion.LogEntry
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+			t.Fatalf("invalid completion log %q: %v", data, err)
+		}
+		if entry.Accepted != 0 || len(entry.Candidates) != len(completions.Items) {
+			t.Fatalf("log entry accepted %d of %d candidates, want 0 of %d",
+				entry.Accepted, len(entry.Candidates), len(completions.Items))
+		}
+		got := entry.Candidates[0]
+		if got.LabelLen != len("aab") || got.Kind != "var" || got.PrefixLen != len("aa") {
+			t.Errorf("accepted candidate has features %+v", got)
+		}
+	})
+}
@@ -69,3 +69,51 @@ const Q = p.P + 1
 		)
 	})
 }
+
+func TestCgoErrors(t *testing.T) {
+	testenv.NeedsTool(t, "cgo")
+
+	const src = `
+-- go.mod --
+module a.com
+
+go 1.18
+-- a/a.go --
+package a
+
+/*
+int fortytwo() { return 42; }
+int broken( {
+*/
+import "C"
+-- b/b.go --
+package b
+
+/*
+int fortythree() { return 43; }
+*/
+import "C"
+
+func Foo() {
+	print(C.fortytwo())
+}
+`
+
+	Run(t, src, func(t *testing.T, env *Env) {
+		env.OnceMet(
+			InitialWorkspaceLoad,
+			// The C compiler's error is reported on the line of the preamble.
+			Diagnostics(
+				env.AtRegexp("a/a.go", "int broken"),
+				FromSource(string(cache.ListError)),
+				WithMessage("expected"),
+			),
+			// cgo's error is reported at the reference to the C symbol.
+			Diagnostics(
+				env.AtRegexp("b/b.go", "C.fortytwo"),
+				FromSource(string(cache.ListError)),
+				WithMessage("C.fortytwo"),
+			),
+		)
+	})
+}
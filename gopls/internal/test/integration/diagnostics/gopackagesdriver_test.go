@@ -7,6 +7,9 @@ package diagnostics
 import (
 	"testing"
 
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/server"
 	. "golang.org/x/tools/gopls/internal/test/integration"
 )
 
@@ -80,3 +83,50 @@ package c
 		env.AfterChange(NoDiagnostics(WithMessage(`invalid use of internal package "example.com/b/internal/c"`)))
 	})
 }
+
+func TestDriverStats_GoPackagesDriver(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+go 1.18
+
+-- a.go --
+package a
+`
+	WithOptions(
+		FakeGoPackagesDriver(t),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		env.AfterChange(NoDiagnostics(ForFile("a.go")))
+
+		driverStats := func() command.DriverInfo {
+			t.Helper()
+			var res command.DriverStatsResult
+			env.ExecuteCommand(&protocol.ExecuteCommandParams{
+				Command: command.DriverStats.String(),
+			}, &res)
+			if len(res.Drivers) != 1 {
+				t.Fatalf("DriverStats returned %d drivers, want 1", len(res.Drivers))
+			}
+			return res.Drivers[0]
+		}
+		before := driverStats()
+		if before.Queries == 0 || before.Failures != 0 || before.Version == "" {
+			t.Errorf("DriverStats = %+v, want successful queries of a driver with a version", before)
+		}
+
+		// Resyncing reloads the workspace from the driver.
+		cmd := command.NewResyncDriverCommand("", command.URIArg{URI: env.Sandbox.Workdir.URI("a.go")})
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, nil)
+		env.OnceMet(
+			CompletedWork(server.DiagnosticWorkTitle(server.FromResyncDriver), 1, true),
+			NoDiagnostics(ForFile("a.go")),
+		)
+		if after := driverStats(); after.Queries <= before.Queries {
+			t.Errorf("after ResyncDriver, driver has %d queries, want more than %d", after.Queries, before.Queries)
+		}
+	})
+}
@@ -356,6 +356,7 @@ func clientCapabilities(cfg EditorConfig) (protocol.ClientCapabilities, error) {
 	var capabilities protocol.ClientCapabilities
 	// Set various client capabilities that are sought by gopls.
 	capabilities.Workspace.Configuration = true // support workspace/configuration
+	capabilities.Workspace.ApplyEdit = true     // support workspace/applyEdit
 	capabilities.TextDocument.Completion.CompletionItem.TagSupport = &protocol.CompletionItemTagOptions{}
 	capabilities.TextDocument.Completion.CompletionItem.TagSupport.ValueSet = []protocol.CompletionItemTag{protocol.ComplDeprecated}
 	capabilities.TextDocument.Completion.CompletionItem.SnippetSupport = true
@@ -1022,10 +1023,18 @@ func (e *Editor) ApplyCodeAction(ctx context.Context, action protocol.CodeAction
 	}
 
 	if action.Edit != nil {
+		created := make(map[string]bool) // buffers created by this edit
 		for _, change := range action.Edit.DocumentChanges {
+			if change.CreateFile != nil {
+				path := e.sandbox.Workdir.URIToPath(change.CreateFile.URI)
+				if err := e.CreateBuffer(ctx, path, ""); err != nil {
+					return fmt.Errorf("creating buffer %q: %w", path, err)
+				}
+				created[path] = true
+			}
 			if change.TextDocumentEdit != nil {
 				path := e.sandbox.Workdir.URIToPath(change.TextDocumentEdit.TextDocument.URI)
-				if int32(e.buffers[path].version) != change.TextDocumentEdit.TextDocument.Version {
+				if !created[path] && int32(e.buffers[path].version) != change.TextDocumentEdit.TextDocument.Version {
 					// Skip edits for old versions.
 					continue
 				}
@@ -1369,6 +1378,33 @@ func (e *Editor) InlayHint(ctx context.Context, path string) ([]protocol.InlayHi
 	return hints, nil
 }
 
+// InlineValue executes an inlineValue request on the server for the
+// entire file, as if execution had stopped at loc.
+func (e *Editor) InlineValue(ctx context.Context, loc protocol.Location) ([]protocol.InlineValue, error) {
+	if e.Server == nil {
+		return nil, nil
+	}
+	path := e.sandbox.Workdir.URIToPath(loc.URI)
+	e.mu.Lock()
+	buf, ok := e.buffers[path]
+	e.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("buffer %q is not open", path)
+	}
+	end, err := buf.mapper.OffsetPosition(len(buf.mapper.Content))
+	if err != nil {
+		return nil, err
+	}
+	params := &protocol.InlineValueParams{
+		TextDocument: e.TextDocumentIdentifier(path),
+		Range:        protocol.Range{End: end},
+		Context: protocol.InlineValueContext{
+			StoppedLocation: loc.Range,
+		},
+	}
+	return e.Server.InlineValue(ctx, params)
+}
+
 // References returns references to the object at loc, as returned by
 // the connected LSP server. If no server is connected, it returns (nil, nil).
 func (e *Editor) References(ctx context.Context, loc protocol.Location) ([]protocol.Location, error) {
@@ -1460,6 +1496,44 @@ func (e *Editor) SignatureHelp(ctx context.Context, loc protocol.Location) (*pro
 	return e.Server.SignatureHelp(ctx, params)
 }
 
+// CreateFile creates an empty file on disk at the workdir-relative
+// path, as a user might in the file explorer of an editor, and notifies
+// the connected LSP server (if any) with a didCreateFiles notification.
+func (e *Editor) CreateFile(ctx context.Context, path string) error {
+	if err := e.sandbox.Workdir.WriteFile(ctx, path, ""); err != nil {
+		return err
+	}
+	if e.Server == nil {
+		return nil
+	}
+	return e.Server.DidCreateFiles(ctx, &protocol.CreateFilesParams{
+		Files: []protocol.FileCreate{{URI: string(e.sandbox.Workdir.URI(path))}},
+	})
+}
+
+// WillRenameFile requests from the connected LSP server the edits that
+// must precede the renaming of the file or directory oldPath to newPath,
+// and applies them. It does not perform the renaming itself.
+func (e *Editor) WillRenameFile(ctx context.Context, oldPath, newPath string) error {
+	if e.Server == nil {
+		return nil
+	}
+	params := &protocol.RenameFilesParams{
+		Files: []protocol.FileRename{{
+			OldURI: string(e.sandbox.Workdir.URI(oldPath)),
+			NewURI: string(e.sandbox.Workdir.URI(newPath)),
+		}},
+	}
+	wsedit, err := e.Server.WillRenameFiles(ctx, params)
+	if err != nil {
+		return err
+	}
+	if wsedit == nil {
+		return nil
+	}
+	return e.applyWorkspaceEdit(ctx, wsedit)
+}
+
 func (e *Editor) RenameFile(ctx context.Context, oldPath, newPath string) error {
 	closed, opened, err := e.renameBuffers(oldPath, newPath)
 	if err != nil {
@@ -1538,23 +1612,32 @@ func (e *Editor) renameBuffers(oldPath, newPath string) (closed []protocol.TextD
 func (e *Editor) applyWorkspaceEdit(ctx context.Context, wsedit *protocol.WorkspaceEdit) error {
 	uriToPath := e.sandbox.Workdir.URIToPath
 
+	created := make(map[protocol.DocumentURI]bool) // buffers created by this edit
 	for _, change := range wsedit.DocumentChanges {
 		switch {
 		case change.TextDocumentEdit != nil:
-			if err := e.applyTextDocumentEdit(ctx, *change.TextDocumentEdit); err != nil {
+			edit := *change.TextDocumentEdit
+			if uri := edit.TextDocument.URI; created[uri] && edit.TextDocument.Version == 0 {
+				// The server did not know the version of the new buffer.
+				edit.TextDocument.Version = int32(e.BufferVersion(uriToPath(uri)))
+			}
+			if err := e.applyTextDocumentEdit(ctx, edit); err != nil {
 				return err
 			}
 
 		case change.RenameFile != nil:
 			old := uriToPath(change.RenameFile.OldURI)
 			new := uriToPath(change.RenameFile.NewURI)
-			return e.RenameFile(ctx, old, new)
+			if err := e.RenameFile(ctx, old, new); err != nil {
+				return err
+			}
 
 		case change.CreateFile != nil:
 			path := uriToPath(change.CreateFile.URI)
 			if err := e.CreateBuffer(ctx, path, ""); err != nil {
 				return err // e.g. already exists
 			}
+			created[change.CreateFile.URI] = true
 
 		case change.DeleteFile != nil:
 			path := uriToPath(change.CreateFile.URI)
@@ -1753,6 +1836,20 @@ func (e *Editor) DocumentHighlight(ctx context.Context, loc protocol.Location) (
 	return e.Server.DocumentHighlight(ctx, params)
 }
 
+func (e *Editor) LinkedEditingRange(ctx context.Context, loc protocol.Location) (*protocol.LinkedEditingRanges, error) {
+	if e.Server == nil {
+		return nil, nil
+	}
+	if err := e.checkBufferLocation(loc); err != nil {
+		return nil, err
+	}
+	params := &protocol.LinkedEditingRangeParams{}
+	params.TextDocument.URI = loc.URI
+	params.Position = loc.Range.Start
+
+	return e.Server.LinkedEditingRange(ctx, params)
+}
+
 // SemanticTokensFull invokes textDocument/semanticTokens/full, and interprets
 // its result.
 func (e *Editor) SemanticTokensFull(ctx context.Context, path string) ([]SemanticToken, error) {
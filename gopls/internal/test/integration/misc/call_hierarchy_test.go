@@ -5,6 +5,8 @@
 package misc
 
 import (
+	"slices"
+	"sort"
 	"testing"
 
 	"golang.org/x/tools/gopls/internal/protocol"
@@ -34,3 +36,74 @@ package pkg
 		env.Editor.Server.PrepareCallHierarchy(env.Ctx, &params)
 	})
 }
+
+// TestCallHierarchyDynamicCalls checks that calls through interface
+// methods are marked as dynamic.
+func TestCallHierarchyDynamicCalls(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- p.go --
+package p
+
+type I interface{ M() }
+
+type T struct{}
+
+func (T) M() {}
+
+func Dynamic(i I) { i.M() }
+
+func Static(t T) { t.M() }
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("p.go")
+		prepare := func(re string) protocol.CallHierarchyItem {
+			loc := env.RegexpSearch("p.go", re)
+			var params protocol.CallHierarchyPrepareParams
+			params.TextDocument.URI = loc.URI
+			params.Position = loc.Range.Start
+			items, err := env.Editor.Server.PrepareCallHierarchy(env.Ctx, &params)
+			if err != nil || len(items) != 1 {
+				t.Fatalf("PrepareCallHierarchy(%q) = %v, %v", re, items, err)
+			}
+			return items[0]
+		}
+
+		// Incoming calls of T.M.
+		incoming, err := env.Editor.Server.IncomingCalls(env.Ctx, &protocol.CallHierarchyIncomingCallsParams{
+			Item: prepare(`func \(T\) (M)`),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]string) // caller name -> detail
+		for _, call := range incoming {
+			got[call.From.Name] = call.From.Detail
+		}
+		if want := "dynamic • mod.com • p.go"; got["Dynamic"] != want {
+			t.Errorf("incoming call from Dynamic has detail %q, want %q", got["Dynamic"], want)
+		}
+		if want := "mod.com • p.go"; got["Static"] != want {
+			t.Errorf("incoming call from Static has detail %q, want %q", got["Static"], want)
+		}
+
+		// Outgoing calls of Dynamic.
+		outgoing, err := env.Editor.Server.OutgoingCalls(env.Ctx, &protocol.CallHierarchyOutgoingCallsParams{
+			Item: prepare(`func (Dynamic)`),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var details []string
+		for _, call := range outgoing {
+			details = append(details, call.To.Detail)
+		}
+		sort.Strings(details)
+		if want := []string{"dynamic • mod.com • p.go", "mod.com • p.go"}; !slices.Equal(details, want) {
+			t.Errorf("outgoing calls of Dynamic have details %q, want %q", details, want)
+		}
+	})
+}
@@ -166,3 +166,55 @@ func H(x int) any { return &x }
 		)
 	})
 }
+
+// TestCompilerOptDetails_settings exercises the compilerOptDetails
+// and compilerOptDetailsSeverity settings.
+func TestCompilerOptDetails_settings(t *testing.T) {
+	if runtime.GOOS == "android" {
+		t.Skipf("the compiler optimization details code action doesn't work on Android")
+	}
+
+	const mod = `
+-- go.mod --
+module mod.com
+go 1.18
+
+-- a/a.go --
+package a
+
+func F(x int) any { return &x }
+`
+
+	WithOptions(
+		Settings{
+			"compilerOptDetails":         map[string]any{"escape": false},
+			"compilerOptDetailsSeverity": "Warning",
+		},
+	).Run(t, mod, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		actions := env.CodeActionForFile("a/a.go", nil)
+		docAction, err := codeActionByKind(actions, settings.GoToggleCompilerOptDetails)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   docAction.Command.Command,
+			Arguments: docAction.Command.Arguments,
+		}, nil)
+
+		// Escape decisions are hidden; inlining decisions are
+		// reported with the configured severity.
+		env.OnceMet(
+			CompletedWork(server.DiagnosticWorkTitle(server.FromToggleCompilerOptDetails), 1, true),
+			Diagnostics(
+				ForFile("a/a.go"),
+				WithMessage("canInlineFunction"),
+				WithSeverityTags("optimizer details", protocol.SeverityWarning, nil),
+			),
+			NoDiagnostics(
+				ForFile("a/a.go"),
+				WithMessage("escapes"),
+			),
+		)
+	})
+}
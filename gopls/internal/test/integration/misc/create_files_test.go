@@ -0,0 +1,68 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/server"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestDidCreateFiles(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a/a.go --
+// Copyright 2020 The Authors.
+
+package alpha
+
+const A = 1
+-- b/b.go --
+package b
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		// The package clause and copyright header come from a sibling.
+		env.CreateFile("a/new_unix.go")
+		env.Await(CompletedWork(server.DiagnosticWorkTitle(server.FromDidCreateFiles), 1, true))
+		want := "// Copyright 2020 The Authors.\n\n//go:build unix\n\npackage alpha\n"
+		if got := env.BufferText("a/new_unix.go"); got != want {
+			t.Errorf("a/new_unix.go:\ngot  %q\nwant %q", got, want)
+		}
+
+		// Without siblings, the package is named after its directory.
+		env.CreateFile("empty/new.go")
+		env.Await(CompletedWork(server.DiagnosticWorkTitle(server.FromDidCreateFiles), 2, true))
+		if got, want := env.BufferText("empty/new.go"), "package empty\n"; got != want {
+			t.Errorf("empty/new.go: got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestDidCreateFilesHeader(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a/a.go --
+package a
+`
+	WithOptions(
+		Settings{"newFileHeader": "Copyright {{.Year}} The Authors.\n\nLicensed under the MIT license."},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.CreateFile("a/new_test.go")
+		env.Await(CompletedWork(server.DiagnosticWorkTitle(server.FromDidCreateFiles), 1, true))
+		want := fmt.Sprintf("// Copyright %d The Authors.\n//\n// Licensed under the MIT license.\n\npackage a\n", time.Now().Year())
+		if got := env.BufferText("a/new_test.go"); got != want {
+			t.Errorf("a/new_test.go:\ngot  %q\nwant %q", got, want)
+		}
+	})
+}
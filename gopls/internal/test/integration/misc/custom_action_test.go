@@ -0,0 +1,123 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestCustomCodeActions(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- actions.json --
+[
+  {
+    "title": "Add TODO",
+    "files": "*.go",
+    "command": ["go", "run", "./addtodo", "${file}", "${startLine}"],
+    "applyEdits": true
+  },
+  {
+    "title": "Count lines",
+    "files": "a/*.go",
+    "command": ["go", "run", "./addtodo", "-count", "${file}"]
+  },
+  {
+    "title": "Not for Go files",
+    "files": "*.txt",
+    "command": ["false"]
+  }
+]
+-- addtodo/main.go --
+// The addtodo command inserts a TODO comment before a line of a file.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	if os.Args[1] == "-count" {
+		data, _ := os.ReadFile(os.Args[2])
+		fmt.Printf("%d lines\n", strings.Count(string(data), "\n"))
+		return
+	}
+	data, _ := os.ReadFile(os.Args[1])
+	line, _ := strconv.Atoi(os.Args[2])
+	lines := strings.SplitAfter(string(data), "\n")
+	lines = append(lines[:line-1], append([]string{"// TODO: fix\n"}, lines[line-1:]...)...)
+	fmt.Print(strings.Join(lines, ""))
+}
+-- a/a.go --
+package a
+
+func F() {}
+`
+	titles := func(env *Env) map[string]bool {
+		m := make(map[string]bool)
+		for _, action := range env.CodeAction(env.RegexpSearch("a/a.go", "F"), nil, 0) {
+			if action.Kind == settings.GoCustom {
+				m[action.Title] = true
+			}
+		}
+		return m
+	}
+
+	// Custom code actions are disabled by default.
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		if got := titles(env); len(got) > 0 {
+			t.Errorf("got custom code actions %v without customCodeActionsFile setting", got)
+		}
+	})
+
+	WithOptions(
+		Settings{"customCodeActionsFile": "actions.json"},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		got := titles(env)
+		if !got["Add TODO"] || !got["Count lines"] || got["Not for Go files"] {
+			t.Fatalf("got custom code actions %v, want Add TODO and Count lines", got)
+		}
+		for _, action := range env.CodeAction(env.RegexpSearch("a/a.go", "F"), nil, 0) {
+			if action.Title == "Add TODO" {
+				env.ApplyCodeAction(action)
+			}
+		}
+		const want = `package a
+
+// TODO: fix
+func F() {}
+`
+		if got := env.BufferText("a/a.go"); got != want {
+			t.Errorf("after Add TODO, got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	WithOptions(
+		Settings{"customCodeActionsFile": "actions.json"},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		for _, action := range env.CodeAction(env.RegexpSearch("a/a.go", "F"), nil, 0) {
+			if action.Title == "Count lines" {
+				env.ExecuteCommand(&protocol.ExecuteCommandParams{
+					Command:   action.Command.Command,
+					Arguments: action.Command.Arguments,
+				}, nil)
+			}
+		}
+		env.Await(ShownMessage("3 lines"))
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/test/compare"
 	. "golang.org/x/tools/gopls/internal/test/integration"
+	"golang.org/x/tools/internal/testenv"
 )
 
 const internalDefinition = `
@@ -641,6 +642,107 @@ var _ = foo(123) // call
 	})
 }
 
+func TestAssemblyToGoDefinition(t *testing.T) {
+	// This test cannot be expressed as a marker test because
+	// the expect package ignores markers (@loc) within a .s file.
+	const src = `
+-- go.mod --
+module mod.com
+
+-- a.go --
+package a
+
+func foo(int) int
+
+func helper(x int) int { return x }
+
+-- foo.s --
+#include "textflag.h"
+
+TEXT ·foo(SB),NOSPLIT,$0
+	CALL ·helper(SB)
+	CALL ·asmOnly(SB)
+	RET
+
+TEXT ·asmOnly(SB),NOSPLIT,$0
+	RET
+`
+	Run(t, src, func(t *testing.T, env *Env) {
+		env.OpenFile("foo.s")
+
+		locString := func(loc protocol.Location) string {
+			return fmt.Sprintf("%s:%s", filepath.Base(loc.URI.Path()), loc.Range)
+		}
+
+		for _, test := range []struct {
+			re, want string
+		}{
+			{`TEXT ·(foo)`, "a.go:2:5-2:8"},       // Go declaration
+			{`CALL ·(helper)`, "a.go:4:5-4:11"},   // Go function
+			{`CALL ·(asmOnly)`, "foo.s:7:6-7:13"}, // assembly function
+		} {
+			loc := env.GoToDefinition(env.RegexpSearch("foo.s", test.re))
+			if got := locString(loc); got != test.want {
+				t.Errorf("Definition(%s): got %s, want %s", test.re, got, test.want)
+			}
+		}
+	})
+}
+
+func TestCgoDefinition(t *testing.T) {
+	// This test cannot be expressed as a marker test because
+	// the expect package ignores markers (@loc) within a .h file,
+	// and the preamble of a cgo file is C code.
+	testenv.NeedsTool(t, "cgo")
+
+	const src = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- a.go --
+package a
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/include
+#include "local.h"
+#include <other.h>
+
+struct point { int x, y; };
+
+int add(int x, int y) { return x + y; }
+*/
+import "C"
+
+var (
+	_ = C.add(1, 2)
+	_ = C.fromlocal
+	_ = C.fromother
+	_ C.struct_point
+)
+-- local.h --
+int fromlocal;
+-- include/other.h --
+#define fromother 1
+`
+	Run(t, src, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		for _, test := range []struct {
+			ref, want string
+		}{
+			{"add", "a.go:9:4-9:7"},
+			{"fromlocal", "local.h:0:4-0:13"},
+			{"fromother", "other.h:0:8-0:17"},
+			{"struct_point", "a.go:7:7-7:12"},
+		} {
+			loc := env.GoToDefinition(env.RegexpSearch("a.go", `C\.(`+test.ref+`)`))
+			if got := fmt.Sprintf("%s:%s", filepath.Base(loc.URI.Path()), loc.Range); got != test.want {
+				t.Errorf("Definition(C.%s): got %s, want %s", test.ref, got, test.want)
+			}
+		}
+	})
+}
+
 func TestPackageKeyInvalidationAfterSave(t *testing.T) {
 	// This test is a little subtle, but catches a bug that slipped through
 	// testing of https://go.dev/cl/614165, which moved active packages to the
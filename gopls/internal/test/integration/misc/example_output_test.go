@@ -0,0 +1,95 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/settings"
+
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestUpdateExampleOutput(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+func Hello()    {}
+func UpToDate() {}
+func NoOutput() {}
+-- a_test.go --
+package a_test
+
+import "fmt"
+
+func ExampleHello() {
+	fmt.Println("hello")
+	fmt.Println()
+	fmt.Println("world")
+	// Output:
+	// goodbye
+}
+
+func ExampleUpToDate() {
+	fmt.Println("hello")
+	// Output: hello
+}
+
+func ExampleNoOutput() {
+	fmt.Println("hello")
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a_test.go")
+		update := func(re string) bool {
+			for _, action := range env.CodeAction(env.RegexpSearch("a_test.go", re), nil, 0) {
+				if action.Kind == settings.GoUpdateExampleOutput {
+					env.ApplyCodeAction(action)
+					return true
+				}
+			}
+			return false
+		}
+		if update("ExampleNoOutput") {
+			t.Errorf("got code action for an example without output comment, want none")
+		}
+
+		// An up-to-date example is left unchanged.
+		before := env.BufferText("a_test.go")
+		if !update("ExampleUpToDate") {
+			t.Fatalf("no code action for ExampleUpToDate")
+		}
+		if got := env.BufferText("a_test.go"); got != before {
+			t.Errorf("updating up-to-date example changed the file:\n%s", got)
+		}
+
+		if !update("goodbye") {
+			t.Fatalf("no code action for ExampleHello")
+		}
+		const want = `package a_test
+
+import "fmt"
+
+func ExampleHello() {
+	fmt.Println("hello")
+	fmt.Println()
+	fmt.Println("world")
+	// Output:
+	// hello
+	//
+	// world
+}
+`
+		if got := env.BufferText("a_test.go"); !strings.HasPrefix(got, want) {
+			t.Errorf("after updating ExampleHello:\n%s\nwant prefix:\n%s", got, want)
+		}
+	})
+}
@@ -5,6 +5,8 @@
 package misc
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"golang.org/x/tools/gopls/internal/settings"
@@ -161,3 +163,153 @@ func _() {
 		// yay, no panic
 	})
 }
+
+// TestMissingPackage checks the quick fixes for an import of a
+// nonexistent package of the current module.
+func TestMissingPackage(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- main.go --
+package main
+
+import (
+	"fmt"
+
+	"mod.com/internal/go-util"
+)
+
+func main() {
+	fmt.Println(util.X)
+}
+`
+	tests := []struct {
+		title string
+		check func(t *testing.T, env *Env)
+	}{
+		{
+			"Create package mod.com/internal/go-util",
+			func(t *testing.T, env *Env) {
+				if got, want := env.BufferText("internal/go-util/util.go"), "package util\n"; got != want {
+					t.Errorf("util.go = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			"Remove import of mod.com/internal/go-util",
+			func(t *testing.T, env *Env) {
+				if got := env.BufferText("main.go"); strings.Contains(got, "go-util") {
+					t.Errorf("import was not removed:\n%s", got)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			Run(t, files, func(t *testing.T, env *Env) {
+				env.OpenFile("main.go")
+				loc := env.RegexpSearch("main.go", `"mod.com/internal/go-util"`)
+				var d protocol.PublishDiagnosticsParams
+				env.AfterChange(
+					Diagnostics(env.AtRegexp("main.go", `"mod.com/internal/go-util"`)),
+					ReadDiagnostics("main.go", &d),
+				)
+				var found bool
+				for _, fix := range env.CodeAction(loc, d.Diagnostics, protocol.CodeActionUnknownTrigger) {
+					if fix.Title == test.title {
+						env.ApplyCodeAction(fix)
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("no quick fix %q", test.title)
+				}
+				test.check(t, env)
+			})
+		})
+	}
+}
+
+// TestDeletionCascadeAnnotations checks that the deletions of a fix
+// other than that of the diagnosed code are annotated as needing
+// confirmation, if the client honors change annotations.
+func TestDeletionCascadeAnnotations(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+func unused() {
+	helper()
+}
+
+func helper() {}
+`
+	for _, honors := range []bool{false, true} {
+		t.Run(fmt.Sprintf("honors=%v", honors), func(t *testing.T) {
+			var opts []RunOption
+			if honors {
+				opts = append(opts, CapabilitiesJSON([]byte(`
+{
+	"workspace": {
+		"workspaceEdit": {
+			"changeAnnotationSupport": {}
+		}
+	},
+	"textDocument": {
+		"codeAction": {
+			"honorsChangeAnnotations": true
+		}
+	}
+}`)))
+			}
+			WithOptions(opts...).Run(t, files, func(t *testing.T, env *Env) {
+				env.OpenFile("a.go")
+				var d protocol.PublishDiagnosticsParams
+				env.AfterChange(
+					Diagnostics(env.AtRegexp("a.go", "unused"), WithMessage("unused")),
+					ReadDiagnostics("a.go", &d),
+				)
+				var found bool
+				for _, action := range env.CodeActionForFile("a.go", d.Diagnostics) {
+					if !strings.Contains(action.Title, "used only by it") {
+						continue
+					}
+					found = true
+					var plain, annotated int
+					for _, change := range action.Edit.DocumentChanges {
+						for _, e := range change.TextDocumentEdit.Edits {
+							// Unmarshalling yields an AnnotatedTextEdit
+							// even for a plain TextEdit.
+							if e, ok := e.Value.(protocol.AnnotatedTextEdit); ok && e.AnnotationID != nil {
+								if annotation := action.Edit.ChangeAnnotations[*e.AnnotationID]; !annotation.NeedsConfirmation {
+									t.Errorf("annotation %q does not need confirmation", *e.AnnotationID)
+								}
+								annotated++
+							} else {
+								plain++
+							}
+						}
+					}
+					// The deletion of unused is plain; that of helper
+					// needs confirmation, if supported.
+					wantPlain, wantAnnotated := 2, 0
+					if honors {
+						wantPlain, wantAnnotated = 1, 1
+					}
+					if plain != wantPlain || annotated != wantAnnotated {
+						t.Errorf("%s: got %d plain and %d annotated edits, want %d and %d", action.Title, plain, annotated, wantPlain, wantAnnotated)
+					}
+				}
+				if !found {
+					t.Errorf("no cascading deletion among code actions")
+				}
+			})
+		})
+	}
+}
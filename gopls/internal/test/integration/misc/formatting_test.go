@@ -10,6 +10,7 @@ import (
 
 	"golang.org/x/tools/gopls/internal/test/compare"
 	. "golang.org/x/tools/gopls/internal/test/integration"
+	"golang.org/x/tools/internal/testenv"
 )
 
 const unformattedProgram = `
@@ -388,3 +389,81 @@ func _() {
 		env.FormatBuffer("foo.go") // golang/go#61692: must not panic
 	})
 }
+
+func TestExternalFormatters(t *testing.T) {
+	testenv.NeedsTool(t, "sed")
+
+	const input = `
+-- go.mod --
+module foo
+
+go 1.21
+-- foo.go --
+package foo
+import "fmt"
+func _() {
+	fmt.Println("Hello, World")
+}
+`
+	const want = `package foo
+
+import "fmt"
+
+func _() {
+	fmt.Println("Goodbye, Gophers")
+}
+`
+
+	// The formatters run in order, after the built-in formatter.
+	WithOptions(
+		Settings{
+			"formatters": []any{
+				[]any{"sed", "s/Hello/Goodbye/"},
+				[]any{"sed", "s/Goodbye, World/Goodbye, Gophers/"},
+			},
+		},
+	).Run(t, input, func(t *testing.T, env *Env) {
+		env.OpenFile("foo.go")
+		env.FormatBuffer("foo.go")
+		if got := env.BufferText("foo.go"); got != want {
+			t.Errorf("unexpected formatting result:\n%s", compare.Text(want, got))
+		}
+	})
+}
+
+func TestExternalFormatterErrors(t *testing.T) {
+	testenv.NeedsTool(t, "sh")
+
+	const input = `
+-- go.mod --
+module foo
+
+go 1.21
+-- foo.go --
+package foo
+`
+
+	for _, test := range []struct {
+		name      string
+		formatter []any
+		wantErr   string
+	}{
+		{"failure", []any{"sh", "-c", "echo oops >&2; exit 1"}, "oops"},
+		{"invalid", []any{"sh", "-c", "echo func"}, "produced invalid Go"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			WithOptions(
+				Settings{"formatters": []any{test.formatter}},
+			).Run(t, input, func(t *testing.T, env *Env) {
+				env.OpenFile("foo.go")
+				err := env.Editor.FormatBuffer(env.Ctx, "foo.go")
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("FormatBuffer returned error %v, want %q", err, test.wantErr)
+				}
+				if got := env.BufferText("foo.go"); got != "package foo\n" {
+					t.Errorf("failed formatting modified foo.go:\n%s", got)
+				}
+			})
+		})
+	}
+}
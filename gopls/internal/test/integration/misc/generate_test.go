@@ -10,7 +10,12 @@
 package misc
 
 import (
+	"os"
 	"testing"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
 
 	. "golang.org/x/tools/gopls/internal/test/integration"
 )
@@ -103,3 +108,218 @@ package main
 			env.RunGenerate("./")
 		})
 }
+
+func TestStaleGeneratedFiles(t *testing.T) {
+	const files = `
+-- go.mod --
+module fake.test
+
+go 1.21
+-- gen.go --
+//go:build ignore
+
+package main
+
+import "os"
+
+func main() {
+	os.WriteFile("color_gen.go", []byte("// Code generated by gen.go; DO NOT EDIT.\n\npackage color\n\nconst N = 3\n"), 0644)
+}
+-- color.go --
+package color
+
+//` + `go:generate go run gen.go
+-- color_gen.go --
+// Code generated by gen.go; DO NOT EDIT.
+
+package color
+
+const N = 2
+-- table_gen.go --
+// Code generated by tablegen; DO NOT EDIT.
+
+package color
+
+const M = 1
+-- other_gen.go --
+// Code generated by othergen; DO NOT EDIT.
+
+package color
+-- table.txt --
+red green blue
+`
+	WithOptions(
+		Settings{
+			"staleGeneratedFiles": true,
+			"generatedFileInputs": map[string]any{
+				"table_gen.go": []any{"*.txt"},
+			},
+		},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		past := time.Now().Add(-time.Hour)
+		for _, name := range []string{"color_gen.go", "table_gen.go", "other_gen.go"} {
+			if err := os.Chtimes(env.Sandbox.Workdir.AbsPath(name), past, past); err != nil {
+				t.Fatal(err)
+			}
+		}
+		env.OpenFile("color.go")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("color_gen.go", "// Code generated.*"), WithMessage("color_gen.go may be stale: color.go was modified")),
+			Diagnostics(env.AtRegexp("table_gen.go", "// Code generated.*"), WithMessage("table_gen.go may be stale: table.txt was modified")),
+			NoDiagnostics(ForFile("other_gen.go")),
+			ReadDiagnostics("color_gen.go", &d),
+		)
+
+		// The quick fix runs go generate, which updates color_gen.go.
+		env.ApplyQuickFixes("color_gen.go", d.Diagnostics)
+		env.Await(NoOutstandingWork(IgnoreTelemetryPromptWork))
+		env.CheckForFileChanges()
+		env.AfterChange(
+			NoDiagnostics(ForFile("color_gen.go")),
+			Diagnostics(ForFile("table_gen.go")),
+		)
+	})
+}
+
+func TestGenerateDirective(t *testing.T) {
+	const files = `
+-- go.mod --
+module fake.test
+
+go 1.21
+-- gen.go --
+//go:build ignore
+
+package main
+
+import "os"
+
+func main() {
+	os.WriteFile(os.Args[1]+"_gen.go", []byte("package lib\n\nconst " + os.Args[1] + " = 1\n"), 0644)
+}
+-- lib.go --
+package lib
+
+//` + `go:generate go run gen.go one
+//` + `go:generate go run gen.go two
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("lib.go")
+		generate := func(directive, title string) {
+			loc := env.RegexpSearch("lib.go", directive)
+			for _, action := range env.CodeAction(loc, nil, 0) {
+				if action.Kind == settings.GoGenerate && action.Title == title {
+					env.ApplyCodeAction(action)
+					env.Await(NoOutstandingWork(IgnoreTelemetryPromptWork))
+					env.CheckForFileChanges()
+					return
+				}
+			}
+			t.Fatalf("no %q code action at %q", title, directive)
+		}
+		exists := func(name string) bool {
+			_, err := os.Stat(env.Sandbox.Workdir.AbsPath(name))
+			return err == nil
+		}
+
+		// Running a directive runs only that directive.
+		generate("gen.go two", "Run go:generate directive")
+		if !exists("two_gen.go") || exists("one_gen.go") {
+			t.Errorf("after running directive two: two_gen.go exists = %t, one_gen.go exists = %t; want true, false",
+				exists("two_gen.go"), exists("one_gen.go"))
+		}
+
+		// Previewing a directive leaves the disk unchanged,
+		// but creates an unsaved buffer.
+		generate("gen.go one", "Preview go:generate directive")
+		if exists("one_gen.go") {
+			t.Errorf("after previewing directive one: one_gen.go exists on disk")
+		}
+		if got, want := env.BufferText("one_gen.go"), "package lib\n\nconst one = 1\n"; got != want {
+			t.Errorf("one_gen.go buffer = %q, want %q", got, want)
+		}
+	})
+}
+
+const editedGeneratedFiles = `
+-- go.mod --
+module fake.test
+
+go 1.21
+-- gen.go --
+//go:build ignore
+
+package main
+
+import "os"
+
+func main() {
+	os.WriteFile("color_gen.go", []byte("// Code generated by gen.go; DO NOT EDIT.\n\npackage color\n\nconst N = 3\n"), 0644)
+}
+-- color.go --
+package color
+
+//` + `go:generate go run gen.go
+-- color_gen.go --
+// Code generated by gen.go; DO NOT EDIT.
+
+package color
+
+const N = 2
+`
+
+func TestEditedGeneratedFiles(t *testing.T) {
+	Run(t, editedGeneratedFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("color_gen.go")
+		env.AfterChange(
+			NoDiagnostics(ForFile("color_gen.go")),
+		)
+		env.RegexpReplace("color_gen.go", "N = 2", "N = 4")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("color_gen.go", "// Code generated.*"), WithMessage("is a generated file")),
+		)
+
+		// The generated file offers to go to the directive that generates it.
+		loc := env.RegexpSearch("color_gen.go", "package color")
+		var found bool
+		for _, action := range env.CodeAction(loc, nil, 0) {
+			if action.Kind == settings.GoShowGenerator {
+				found = true
+				env.ApplyCodeAction(action)
+			}
+		}
+		if !found {
+			t.Fatalf("no %s code action in color_gen.go", settings.GoShowGenerator)
+		}
+		env.Await(ShownDocument(string(env.Sandbox.Workdir.URI("color.go"))))
+
+		// Reverting the edit removes the diagnostic.
+		env.RegexpReplace("color_gen.go", "N = 4", "N = 2")
+		env.AfterChange(
+			NoDiagnostics(ForFile("color_gen.go")),
+		)
+	})
+}
+
+func TestLockGeneratedFiles(t *testing.T) {
+	WithOptions(
+		Settings{"lockGeneratedFiles": true},
+	).Run(t, editedGeneratedFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("color_gen.go")
+		saved := env.BufferText("color_gen.go")
+		env.RegexpReplace("color_gen.go", "N = 2", "N = 4")
+
+		// gopls reverts the edit asynchronously.
+		deadline := time.Now().Add(10 * time.Second)
+		for env.BufferText("color_gen.go") != saved {
+			if time.Now().After(deadline) {
+				t.Fatalf("color_gen.go was not reverted:\n%s", env.BufferText("color_gen.go"))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		env.AfterChange(
+			NoDiagnostics(ForFile("color_gen.go")),
+		)
+	})
+}
@@ -278,6 +278,80 @@ go 1.16
 	})
 }
 
+func TestHoverModuleInfo(t *testing.T) {
+	const proxy = `
+-- example.com@v1.0.0/go.mod --
+module example.com
+
+go 1.12
+-- example.com@v1.0.0/LICENSE --
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+-- example.com@v1.0.0/pkg/pkg.go --
+package pkg
+
+const Name = "pkg"
+-- example.com@v1.1.0/go.mod --
+// Deprecated: use example.com/v2 instead.
+module example.com
+
+go 1.12
+
+retract v1.0.0 // Contains a bug.
+-- example.com@v1.1.0/pkg/pkg.go --
+package pkg
+
+const Name = "pkg"
+-- example.com/v2@v2.0.0/go.mod --
+module example.com/v2
+
+go 1.12
+-- example.com/v2@v2.0.0/pkg/pkg.go --
+package pkg
+
+const Name = "pkg"
+`
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.12
+
+require example.com v1.0.0
+-- main.go --
+package main
+
+import "example.com/pkg"
+
+func main() {
+	println(pkg.Name)
+}
+`
+	WithOptions(
+		ProxyFiles(proxy),
+		WriteGoSum("."),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+		content, _ := env.Hover(env.RegexpSearch("go.mod", "example.com"))
+		if content == nil {
+			t.Fatal("no hover for example.com")
+		}
+		for _, want := range []string{
+			"**Deprecated:** use example.com/v2 instead.",
+			"**Retracted:** v1.0.0 is retracted: Contains a bug.",
+			"Latest version: [example.com@v1.1.0](https://pkg.go.dev/mod/example.com@v1.1.0)",
+			"Latest major version: [example.com/v2@v2.0.0](https://pkg.go.dev/mod/example.com/v2@v2.0.0)",
+			"Retracted versions: v1.0.0 (Contains a bug.)",
+			"License: MIT",
+		} {
+			if !strings.Contains(content.Value, want) {
+				t.Errorf("hover does not contain %q:\n%s", want, content.Value)
+			}
+		}
+	})
+}
+
 func TestHoverCompletionMarkdown(t *testing.T) {
 	const source = `
 -- go.mod --
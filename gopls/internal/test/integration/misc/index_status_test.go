@@ -0,0 +1,54 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/server"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestIndexStatus(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- a/a.go --
+package a
+
+func A() {}
+-- b/b.go --
+package b
+
+import "mod.com/a"
+
+func B() { a.A() }
+`
+	WithOptions(
+		Settings{"reportAnalysisProgressAfter": "0s"},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.AfterChange(CompletedWork(server.IndexProgressTitle, 1, true))
+
+		var result command.IndexStatusResult
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command: command.IndexStatus.String(),
+		}, &result)
+		if got := len(result.Views); got != 1 {
+			t.Fatalf("got %d views, want 1: %+v", got, result.Views)
+		}
+		v := result.Views[0]
+		if v.State != "idle" {
+			t.Errorf("State = %q, want %q", v.State, "idle")
+		}
+		if v.Packages != 2 || v.Indexed != 2 || v.AnalysisPending != 0 {
+			t.Errorf("got Packages=%d Indexed=%d AnalysisPending=%d, want 2, 2, 0",
+				v.Packages, v.Indexed, v.AnalysisPending)
+		}
+	})
+}
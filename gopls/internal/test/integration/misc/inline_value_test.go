@@ -0,0 +1,85 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestInlineValue(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+var global int
+
+type point struct{ x, y int }
+
+func f(p point, n int) int {
+	sum := p.x + global
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	{
+		sum := 0
+		_ = sum
+	}
+	return sum //@stop
+}
+
+func g() {
+	later := 1
+	_ = later
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		values := env.InlineValue(env.RegexpSearch("a.go", "//@stop"))
+
+		// Summarize each value as line:text.
+		//
+		// (The JSON decoding of the InlineValue union type cannot
+		// distinguish a variable lookup from an expression, so we
+		// check only the range of each value.)
+		lines := strings.Split(env.BufferText("a.go"), "\n")
+		var got []string
+		for _, v := range values {
+			var rng protocol.Range
+			switch v := v.Value.(type) {
+			case protocol.InlineValueVariableLookup:
+				rng = v.Range
+			case protocol.InlineValueEvaluatableExpression:
+				rng = v.Range
+			default:
+				t.Fatalf("unexpected inline value %T", v)
+			}
+			text := lines[rng.Start.Line][rng.Start.Character:rng.End.Character]
+			got = append(got, fmt.Sprintf("%d:%s", rng.Start.Line, text))
+		}
+		// The loop variable i and the inner sum are not in scope at
+		// the stop, so neither is reported, nor is the global.
+		want := []string{
+			"6:p",
+			"6:n",
+			"7:sum",
+			"7:p.x",
+			"8:n",
+			"9:sum",
+			"15:sum",
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("InlineValue returned:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+// TestLargeFile checks the degraded mode of operation for Go files
+// larger than the largeFileSize setting.
+func TestLargeFile(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- big.go --
+package p
+
+// This file is larger than the largeFileSize setting of the test,
+// so gopls reports only its syntax errors, and provides no semantic
+// tokens for it.
+
+var _ int = "big"
+
+func f() {
+	println()
+}
+-- small.go --
+package p
+
+var _ int = "small"
+`
+	WithOptions(
+		Settings{
+			"largeFileSize":  200,
+			"semanticTokens": true,
+		},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("big.go")
+		env.OpenFile("small.go")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("big.go", "package (p)"), WithMessage("big.go is larger than 200 bytes")),
+			NoDiagnostics(ForFile("big.go"), WithMessage("cannot use")),
+			Diagnostics(env.AtRegexp("small.go", `"small"`), WithMessage("cannot use")),
+		)
+
+		if toks := env.SemanticTokensFull("big.go"); len(toks) > 0 {
+			t.Errorf("SemanticTokensFull(big.go) returned %d tokens, want none", len(toks))
+		}
+		if toks := env.SemanticTokensFull("small.go"); len(toks) == 0 {
+			t.Errorf("SemanticTokensFull(small.go) returned no tokens")
+		}
+
+		ranges, err := env.Editor.Server.FoldingRange(env.Ctx, &protocol.FoldingRangeParams{
+			TextDocument: env.Editor.TextDocumentIdentifier("big.go"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ranges) == 0 {
+			t.Errorf("FoldingRange(big.go) returned no ranges")
+		}
+
+		// Syntax errors are still reported.
+		env.RegexpReplace("big.go", `println\(\)`, "println(")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("big.go", `\n(})`), WithMessage("expected")),
+			NoDiagnostics(ForFile("big.go"), WithMessage("cannot use")),
+		)
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/gopls/internal/protocol"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+// TestLinkedEditingBuildTags checks that the terms of //go:build and
+// // +build lines are linked. (Marker tests cannot express this, as
+// their annotations cannot share a line with a build constraint.)
+func TestLinkedEditingBuildTags(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- a.go --
+//go:build !foo && (!bar || !foo)
+// +build !foo,!bar !foo
+
+package a
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		var (
+			goFoo    = env.RegexpSearch("a.go", `go:build !(foo)`)
+			goFoo2   = env.RegexpSearch("a.go", `\|\| !(foo)`)
+			goBar    = env.RegexpSearch("a.go", `&& \(!(bar)`)
+			plusFoo  = env.RegexpSearch("a.go", `\+build !(foo)`)
+			plusFoo2 = env.RegexpSearch("a.go", `bar !(foo)`)
+			plusBar  = env.RegexpSearch("a.go", `,!(bar)`)
+		)
+		for _, test := range []struct {
+			loc  protocol.Location
+			want []protocol.Location
+		}{
+			{goFoo, []protocol.Location{goFoo, goFoo2, plusFoo, plusFoo2}},
+			{plusFoo2, []protocol.Location{goFoo, goFoo2, plusFoo, plusFoo2}},
+			{plusBar, []protocol.Location{goBar, plusBar}},
+		} {
+			got := env.LinkedEditingRange(test.loc)
+			if got == nil {
+				t.Fatalf("LinkedEditingRange(%v) = nil", test.loc)
+			}
+			var want []protocol.Range
+			for _, loc := range test.want {
+				want = append(want, loc.Range)
+			}
+			if diff := cmp.Diff(want, got.Ranges); diff != "" {
+				t.Errorf("LinkedEditingRange(%v) mismatch (-want +got):\n%s", test.loc, diff)
+			}
+		}
+
+		// Without a // +build line, there is nothing to link.
+		env.SetBufferContent("a.go", "//go:build !foo && !bar\n\npackage a\n")
+		if got := env.LinkedEditingRange(env.RegexpSearch("a.go", `!(foo)`)); got != nil {
+			t.Errorf("LinkedEditingRange without +build line = %v, want nil", got)
+		}
+	})
+}
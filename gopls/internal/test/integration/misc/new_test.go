@@ -0,0 +1,69 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/test/compare"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestNewModule(t *testing.T) {
+	const proxy = `
+-- example.com/tmpl@v1.0.0/go.mod --
+module example.com/tmpl
+
+go 1.20
+-- example.com/tmpl@v1.0.0/tmpl.go --
+package tmpl
+
+// Author: {{gonew.author}}
+const Name = "tmpl"
+-- example.com/tmpl@v1.0.0/cmd/{{gonew.name}}/main.go --
+package main
+
+import "example.com/tmpl"
+
+func main() { println(tmpl.Name) }
+`
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.20
+`
+	WithOptions(
+		ProxyFiles(proxy),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		cmd := command.NewNewModuleCommand("", command.NewModuleArgs{
+			Template: "example.com/tmpl@v1.0.0",
+			Module:   "example.com/demo",
+			Dir:      protocol.URIFromPath(env.Sandbox.Workdir.AbsPath("demo")),
+			Vars:     map[string]string{"author": "Gopher", "name": "demo"},
+			Hooks:    [][]string{{"go", "mod", "edit", "-go=1.21"}},
+		})
+		var result command.NewModuleResult
+		env.ExecuteCommand(&protocol.ExecuteCommandParams{
+			Command:   cmd.Command,
+			Arguments: cmd.Arguments,
+		}, &result)
+		if want := env.Sandbox.Workdir.URI("demo/go.mod"); result.GoMod != want {
+			t.Errorf("NewModule returned go.mod %s, want %s", result.GoMod, want)
+		}
+
+		for name, want := range map[string]string{
+			"demo/go.mod":           "module example.com/demo\n\ngo 1.21\n",
+			"demo/tmpl.go":          "package demo\n\n// Author: Gopher\nconst Name = \"tmpl\"\n",
+			"demo/cmd/demo/main.go": "package main\n\nimport tmpl \"example.com/demo\"\n\nfunc main() { println(tmpl.Name) }\n",
+		} {
+			if got := env.ReadWorkspaceFile(name); got != want {
+				t.Errorf("%s: unexpected content:\n%s", name, compare.Text(want, got))
+			}
+		}
+	})
+}
@@ -919,3 +919,237 @@ func checkTestdata(t *testing.T, env *Env) {
 		}
 	}
 }
+
+// TestRenameInCommentsAndStrings checks that, with the
+// renameInCommentsAndStrings setting, the edits of rename in comments
+// and strings are annotated as needing confirmation if the client
+// honors change annotations.
+func TestRenameInCommentsAndStrings(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a.go --
+package a
+
+import "errors"
+
+// Check reports whether s is valid.
+func Check(s string) error {
+	if s == "" {
+		return errors.New("Check: empty")
+	}
+	return nil
+}
+
+// Call Check before use.
+var _ = Check("")
+`
+	for _, honors := range []bool{false, true} {
+		t.Run(fmt.Sprintf("honors=%v", honors), func(t *testing.T) {
+			opts := []RunOption{Settings{"renameInCommentsAndStrings": true}}
+			if honors {
+				opts = append(opts, CapabilitiesJSON([]byte(`
+{
+	"workspace": {
+		"workspaceEdit": {
+			"changeAnnotationSupport": {}
+		}
+	},
+	"textDocument": {
+		"rename": {
+			"honorsChangeAnnotations": true
+		}
+	}
+}`)))
+			}
+			WithOptions(opts...).Run(t, files, func(t *testing.T, env *Env) {
+				env.OpenFile("a.go")
+				loc := env.RegexpSearch("a.go", "func (Check)")
+				params := &protocol.RenameParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+					Position:     loc.Range.Start,
+					NewName:      "Validate",
+				}
+				edit, err := env.Editor.Server.Rename(env.Ctx, params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var plain, annotated int
+				for _, change := range edit.DocumentChanges {
+					for _, e := range change.TextDocumentEdit.Edits {
+						// Unmarshalling yields an AnnotatedTextEdit
+						// even for a plain TextEdit.
+						if e, ok := e.Value.(protocol.AnnotatedTextEdit); ok && e.AnnotationID != nil {
+							if annotation := edit.ChangeAnnotations[*e.AnnotationID]; !annotation.NeedsConfirmation {
+								t.Errorf("annotation %q does not need confirmation", *e.AnnotationID)
+							}
+							annotated++
+						} else {
+							plain++
+						}
+					}
+				}
+				// The declaration, its doc comment, and the call are
+				// renamed as usual; the string and the other comment
+				// are renamed only with confirmation, if supported.
+				wantPlain, wantAnnotated := 5, 0
+				if honors {
+					wantPlain, wantAnnotated = 3, 2
+				}
+				if plain != wantPlain || annotated != wantAnnotated {
+					t.Errorf("got %d plain and %d annotated edits, want %d and %d", plain, annotated, wantPlain, wantAnnotated)
+				}
+			})
+		})
+	}
+}
+
+// TestRenamePackageDirectoryAnnotation checks that the renaming of the
+// directory of a renamed package is annotated as needing confirmation,
+// if the client honors change annotations.
+func TestRenamePackageDirectoryAnnotation(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- lib/a.go --
+package lib
+
+const A = 1
+`
+	WithOptions(
+		CapabilitiesJSON([]byte(`
+{
+	"workspace": {
+		"workspaceEdit": {
+			"changeAnnotationSupport": {}
+		}
+	},
+	"textDocument": {
+		"rename": {
+			"honorsChangeAnnotations": true
+		}
+	}
+}`)),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("lib/a.go")
+		loc := env.RegexpSearch("lib/a.go", "lib")
+		params := &protocol.RenameParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: loc.URI},
+			Position:     loc.Range.Start,
+			NewName:      "lib1",
+		}
+		edit, err := env.Editor.Server.Rename(env.Ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var renames int
+		for _, change := range edit.DocumentChanges {
+			if change.RenameFile == nil {
+				continue
+			}
+			renames++
+			if id := change.RenameFile.AnnotationID; id == nil {
+				t.Errorf("rename of %s is not annotated", change.RenameFile.OldURI)
+			} else if annotation := edit.ChangeAnnotations[*id]; !annotation.NeedsConfirmation {
+				t.Errorf("annotation %q does not need confirmation", *id)
+			}
+		}
+		if renames != 1 {
+			t.Errorf("got %d file renames, want 1", renames)
+		}
+	})
+}
+
+func TestWillRenamePackageDirectory(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- lib/a.go --
+package lib
+
+const A = 1
+-- lib/a_test.go --
+package lib_test
+
+import "mod.com/lib"
+
+var _ = lib.A
+-- lib/nested/b.go --
+package nested
+
+const B = 2
+-- main.go --
+package main
+
+import (
+	"mod.com/lib"
+	"mod.com/lib/nested"
+)
+
+func main() {
+	println(lib.A, nested.B)
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		env.WillRenameFile("lib", "lib1")
+		env.RenameFile("lib", "lib1")
+
+		env.RegexpSearch("lib1/a.go", "package lib1")
+		env.RegexpSearch("lib1/a_test.go", "package lib1_test")
+		env.RegexpSearch("lib1/a_test.go", `"mod.com/lib1"`)
+		env.RegexpSearch("lib1/a_test.go", "lib1.A")
+		env.RegexpSearch("lib1/nested/b.go", "package nested")
+		env.RegexpSearch("main.go", `"mod.com/lib1"`)
+		env.RegexpSearch("main.go", `"mod.com/lib1/nested"`)
+		env.RegexpSearch("main.go", "lib1.A")
+		env.AfterChange(NoDiagnostics())
+	})
+}
+
+func TestWillRenameGoFile(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.21
+-- a/a.go --
+package a
+
+func F() {}
+-- a/a_test.go --
+package a
+
+import "testing"
+
+func TestF(t *testing.T) { F() }
+-- a/util.go --
+package a
+
+func Helper() {}
+-- b/b.go --
+package b
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		// Renaming a file renames its test file too.
+		env.OpenFile("a/a.go")
+		env.WillRenameFile("a/a.go", "a/f.go")
+		env.RenameFile("a/a.go", "a/f.go")
+		env.RegexpSearch("a/f_test.go", "TestF")
+		if _, err := env.Sandbox.Workdir.ReadFile("a/a_test.go"); err == nil {
+			t.Errorf("a/a_test.go still exists")
+		}
+
+		// Moving a file to another package updates its package clause.
+		env.WillRenameFile("a/util.go", "b/util.go")
+		env.RenameFile("a/util.go", "b/util.go")
+		env.RegexpSearch("b/util.go", "package b")
+		env.AfterChange(NoDiagnostics())
+	})
+}
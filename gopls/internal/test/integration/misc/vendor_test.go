@@ -10,6 +10,8 @@ import (
 	. "golang.org/x/tools/gopls/internal/test/integration"
 
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/server"
 )
 
 const basicProxy = `
@@ -96,3 +98,128 @@ func main() {
 		env.AfterChange(NoDiagnostics())
 	})
 }
+
+// vendoredModule is a module that requires golang.org/x/hello from
+// basicProxy, for tests that vendor it with 'go mod vendor'.
+const vendoredModule = `
+-- go.mod --
+module mod.com
+
+go 1.14
+
+require golang.org/x/hello v1.2.3
+-- main.go --
+package main
+
+import "golang.org/x/hello/hi"
+
+func main() {
+	_ = hi.Goodbye
+}
+`
+
+func TestVendorOutOfSync(t *testing.T) {
+	WithOptions(
+		Modes(Default),
+		ProxyFiles(basicProxy),
+		WriteGoSum("."),
+	).Run(t, vendoredModule, func(t *testing.T, env *Env) {
+		env.RunGoCommand("mod", "vendor")
+		env.OpenFile("go.mod")
+		env.AfterChange(NoDiagnostics(ForFile("go.mod")))
+
+		// The code lens offers to sync the existing vendor directory.
+		var found bool
+		for _, lens := range env.CodeLens("go.mod") {
+			if lens.Command.Command == command.Vendor.String() {
+				found = true
+				if got, want := lens.Command.Title, "Sync vendor directory"; got != want {
+					t.Errorf("vendor code lens title = %q, want %q", got, want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("no vendor code lens")
+		}
+
+		// Changes to the requirements are reported before go.mod is saved.
+		env.RegexpReplace("go.mod", "v1.2.3", "v1.2.4")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.mod", "require golang.org/x/hello v1.2.4"), WithMessage("vendored at v1.2.3, not v1.2.4")),
+		)
+		env.RegexpReplace("go.mod", "require golang.org/x/hello v1.2.4", "")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.mod", "module mod.com"), WithMessage("golang.org/x/hello is vendored but no longer required")),
+		)
+	})
+}
+
+func TestVendorCodeLensTitle(t *testing.T) {
+	WithOptions(
+		Modes(Default),
+		ProxyFiles(basicProxy),
+		WriteGoSum("."),
+	).Run(t, vendoredModule, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+		title := func() string {
+			for _, lens := range env.CodeLens("go.mod") {
+				if lens.Command.Command == command.Vendor.String() {
+					return lens.Command.Title
+				}
+			}
+			t.Fatalf("no vendor code lens")
+			return ""
+		}
+		if got, want := title(), "Create vendor directory"; got != want {
+			t.Errorf("vendor code lens title = %q, want %q", got, want)
+		}
+		env.RunGoCommand("mod", "vendor")
+		if got, want := title(), "Sync vendor directory"; got != want {
+			t.Errorf("vendor code lens title = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestVendorOutOfSyncModFlag(t *testing.T) {
+	// A -mod flag other than -mod=vendor disables the vendor directory,
+	// whether it comes from the "env" setting or from the view's
+	// environment overlay.
+	t.Run("env setting", func(t *testing.T) {
+		WithOptions(
+			Modes(Default),
+			ProxyFiles(basicProxy),
+			WriteGoSum("."),
+			EnvVars{"GOFLAGS": "-mod=mod"},
+		).Run(t, vendoredModule, func(t *testing.T, env *Env) {
+			env.RunGoCommand("mod", "vendor")
+			env.OpenFile("go.mod")
+			env.RegexpReplace("go.mod", "v1.2.3", "v1.2.4")
+			env.AfterChange(NoDiagnostics(ForFile("go.mod"), WithMessage("go mod vendor")))
+		})
+	})
+	t.Run("view environment", func(t *testing.T) {
+		WithOptions(
+			Modes(Default),
+			ProxyFiles(basicProxy),
+			WriteGoSum("."),
+		).Run(t, vendoredModule, func(t *testing.T, env *Env) {
+			env.RunGoCommand("mod", "vendor")
+			env.OpenFile("go.mod")
+			env.RegexpReplace("go.mod", "v1.2.3", "v1.2.4")
+			env.AfterChange(Diagnostics(ForFile("go.mod"), WithMessage("go mod vendor")))
+
+			cmd := command.NewSetViewEnvironmentCommand("", command.SetViewEnvironmentArgs{
+				URI: env.Sandbox.Workdir.URI("go.mod"),
+				Env: map[string]string{"GOFLAGS": "-mod=mod"},
+			})
+			env.ExecuteCommand(&protocol.ExecuteCommandParams{
+				Command:   cmd.Command,
+				Arguments: cmd.Arguments,
+			}, nil)
+			env.OnceMet(
+				CompletedWork(server.DiagnosticWorkTitle(server.FromSetViewEnvironment), 1, true),
+				NoDiagnostics(ForFile("go.mod"), WithMessage("go mod vendor")),
+			)
+		})
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"html"
 	"io"
 	"net/http"
+	"net/url"
+	"os/exec"
 	"regexp"
 	"runtime"
 	"strings"
@@ -225,6 +227,63 @@ func (tπ) mπ() {}
 	})
 }
 
+// TestPkgDocUnexported tests the option to show unexported symbols.
+func TestPkgDocUnexported(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+-- a/a.go --
+package a
+
+var Vπ, vπ = 0, 0
+
+func Fπ()
+func fπ()
+
+type Tπ int
+type tπ int
+
+func (Tπ) Mπ() {}
+func (Tπ) mπ() {}
+func (tπ) Mπ() {}
+
+func init() {}
+func _() {}
+
+var _ = 0
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		uri1 := viewPkgDoc(t, env, env.Sandbox.Workdir.EntireFile("a/a.go"))
+
+		doc := get(t, uri1)
+		checkMatch(t, true, doc, "<input type='search' id='hdr-Search'")
+		checkMatch(t, true, doc, "<label id='hdr-Unexported'><input type='checkbox'/>")
+		checkMatch(t, false, doc, "<h3 id='fπ'")
+
+		u, err := url.Parse(uri1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+		q.Set("unexported", "1")
+		u.RawQuery = q.Encode()
+		doc = get(t, u.String())
+		checkMatch(t, true, doc, "<label id='hdr-Unexported'><input type='checkbox' checked/>")
+		checkMatch(t, true, doc, "<a id='vπ'")
+		checkMatch(t, true, doc, "<h3 id='fπ'")
+		checkMatch(t, true, doc, "<h3 id='tπ'")
+		checkMatch(t, true, doc, "<h4 id='Tπ.mπ'")
+		checkMatch(t, true, doc, "<h4 id='tπ.Mπ'")
+
+		// init and blank symbols are never shown.
+		checkMatch(t, false, doc, "<h3 id='init'")
+		checkMatch(t, false, doc, "<h3 id='_'")
+		checkMatch(t, false, doc, "<a id='_'")
+	})
+}
+
 // TestPkgDocNavigation tests that the symbol selector and index of
 // symbols are well formed.
 func TestPkgDocNavigation(t *testing.T) {
@@ -269,6 +328,85 @@ func Constructor() Type
 	})
 }
 
+func TestPkgDocExamples(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+go 1.21
+-- a/a.go --
+package a
+
+func Func() int { return 1 }
+
+type Type struct{}
+
+func (Type) Method() {}
+-- a/a_test.go --
+package a
+
+func ExampleType_Method() {
+	Type{}.Method()
+}
+-- a/example_test.go --
+package a_test
+
+import (
+	"fmt"
+
+	"example.com/a"
+)
+
+// This example prints one.
+func ExampleFunc() {
+	fmt.Println(a.Func())
+	// Output: 1
+}
+
+func ExampleFunc_twice() {
+	fmt.Println(a.Func(), a.Func())
+	// Output: 1 1
+}
+
+func Example() {
+	fmt.Println("package")
+	// Output: package
+}
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		uri1 := viewPkgDoc(t, env, env.Sandbox.Workdir.EntireFile("a/a.go"))
+		doc := get(t, uri1)
+
+		q := regexp.QuoteMeta
+
+		// index
+		checkMatch(t, true, doc, q(`<li><a href='#example-'>Package</a></li>`))
+		checkMatch(t, true, doc, q(`<li><a href='#example-Func'>Func</a></li>`))
+		checkMatch(t, true, doc, q(`<li><a href='#example-Func_twice'>Func (twice)</a></li>`))
+		checkMatch(t, true, doc, q(`<li><a href='#example-Type_Method'>Type.Method</a></li>`))
+
+		// examples beneath their symbols, with links, without output comments
+		checkMatch(t, true, doc, `(?s)<h3 id='Func'>.*<details class='example' id='example-Func'>.*<summary>Example \(twice\)</summary>.*<h2 id='hdr-Types'>`)
+		checkMatch(t, true, doc, `(?s)<h4 id='Type.Method'>.*<details class='example' id='example-Type_Method'>`)
+		checkMatch(t, true, doc, `This example prints one.`)
+		checkMatch(t, true, doc, `<a class='id' href='[^']*/pkg/fmt[^']*'>fmt</a>.*<a class='id' href='#Func'>Func</a>\(\)\)</pre>`)
+		checkMatch(t, false, doc, `// Output`)
+
+		// Only examples with output comments can be run.
+		checkMatch(t, true, doc, `(?s)id='example-Func'>.*runExample.*</details>`)
+		checkMatch(t, false, doc, `(?s)id='example-Type_Method'>.*runExample.*</details>`)
+
+		// Run an example.
+		m := regexp.MustCompile(`runExample\(this, &#34;([^&]*(?:&amp;[^&]*)*)&#34;\)`).FindSubmatch(doc)
+		if m == nil {
+			t.Fatalf("no runExample link")
+		}
+		output := get(t, html.UnescapeString(string(m[1])))
+		checkMatch(t, true, output, `--- PASS: Example`)
+	})
+}
+
 // TestPkgDocContext tests that the gopls.doc command title and /pkg
 // URL are appropriate for the current selection. It is effectively a
 // test of golang.DocFragment.
@@ -584,6 +722,171 @@ func g() {
 
 		// But other functions are not.
 		checkMatch(t, false, report, `TEXT.*example.com/a.g`)
+
+		// Instructions and source lines are cross-linked.
+		checkMatch(t, true, report, `<span class='src-line' id='src-4' onclick='selectLine\(4, true\)'>   4  	println\(&#34;hello&#34;\)</span>`)
+		checkMatch(t, true, report, `<span class='insn line-4' onclick='selectLine\(4, false\)'>`)
+
+		// The target and compiler flags may be selected.
+		u, err := url.Parse(doc.URI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+		q.Set("goarch", runtime.GOARCH)
+		q.Set("gcflags", "-N -l")
+		u.RawQuery = q.Encode()
+		report = get(t, u.String())
+		checkMatch(t, true, report, `TEXT.*example.com/a.f`)
+		checkMatch(t, true, report, fmt.Sprintf(`<option value='%s' selected>`, runtime.GOARCH))
+		checkMatch(t, true, report, `<option value='-N -l' selected>`)
+
+		// Arbitrary flags are rejected.
+		q.Set("gcflags", "-dynlink")
+		u.RawQuery = q.Encode()
+		report = get(t, u.String())
+		checkMatch(t, true, report, `unsupported compiler flags`)
+	})
+}
+
+// TestFuzzCorpus is a basic test of the "browse corpus" code lens of a
+// fuzz test, and of the web-based corpus listing.
+func TestFuzzCorpus(t *testing.T) {
+	const files = `
+-- go.mod --
+module example.com
+
+-- a/a_test.go --
+package a
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string, n int) {})
+}
+-- a/testdata/fuzz/FuzzParse/582528ddfad69eb5 --
+go test fuzz v1
+string("\x00a")
+int(-2)
+-- a/testdata/fuzz/FuzzParse/bad --
+not a corpus file
+`
+	WithOptions(
+		Settings{"codelenses": map[string]bool{"test": true}},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a_test.go")
+
+		var result command.FuzzCorpusResult
+		collectDocs := env.Awaiter.ListenToShownDocuments()
+		if err := env.Editor.ExecuteCodeLensCommand(env.Ctx, "a/a_test.go", command.FuzzCorpus, &result); err != nil {
+			t.Fatal(err)
+		}
+
+		// The seed entries are decoded, or report an error.
+		var seeds []command.FuzzCorpusEntry
+		for _, e := range result.Entries {
+			if e.Seed {
+				seeds = append(seeds, e)
+			}
+		}
+		if len(seeds) != 2 {
+			t.Fatalf("got %d seed entries, want 2: %+v", len(seeds), result.Entries)
+		}
+		if got, want := strings.Join(seeds[0].Values, ", "), `string("\x00a"), int(-2)`; got != want {
+			t.Errorf("got values %s, want %s", got, want)
+		}
+		if seeds[1].Error == "" {
+			t.Errorf("malformed corpus file %s: got no error", seeds[1].URI)
+		}
+
+		doc := shownDocument(t, collectDocs(), "http:")
+		if doc == nil {
+			t.Fatalf("no showDocument call had 'http:' prefix")
+		}
+		report := get(t, doc.URI)
+		checkMatch(t, true, report, `Corpus of example.com/a.FuzzParse`)
+		checkMatch(t, true, report, regexp.QuoteMeta(html.EscapeString(`string("\x00a"), int(-2)`)))
+	})
+}
+
+// TestCompareBenchmarks is a basic test of the comparison of benchmarks
+// with the HEAD revision and with the saved files, and of its report.
+func TestCompareBenchmarks(t *testing.T) {
+	testenv.NeedsTool(t, "git")
+
+	const files = `
+-- go.mod --
+module example.com
+
+-- a/a.go --
+package a
+
+func Widgets() int { return 1 }
+-- a/a_test.go --
+package a
+
+import "testing"
+
+func BenchmarkWidgets(b *testing.B) {
+	b.ReportMetric(float64(Widgets()), "widgets/op")
+}
+`
+	WithOptions(
+		EnvVars{"GOFLAGS": "-benchtime=1x"},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		dir := env.Sandbox.Workdir.RootURI().Path()
+		for _, args := range [][]string{
+			{"init", "-q"},
+			{"add", "."},
+			{"-c", "user.name=gopls", "-c", "user.email=gopls@example.com", "commit", "-q", "-m", "initial"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+
+		// Make an unsaved change that doubles the widgets.
+		env.OpenFile("a/a.go")
+		env.RegexpReplace("a/a.go", "return 1", "return 2")
+
+		for _, ref := range []string{"HEAD", ""} {
+			var result command.CompareBenchmarksResult
+			collectDocs := env.Awaiter.ListenToShownDocuments()
+			cmd := command.NewCompareBenchmarksCommand("", command.CompareBenchmarksArgs{
+				URI:          env.Sandbox.Workdir.URI("a/a_test.go"),
+				Benchmarks:   []string{"BenchmarkWidgets"},
+				Ref:          ref,
+				Count:        2,
+				ShowDocument: true,
+			})
+			env.ExecuteCommand(&protocol.ExecuteCommandParams{
+				Command:   cmd.Command,
+				Arguments: cmd.Arguments,
+			}, &result)
+
+			var found bool
+			for _, d := range result.Deltas {
+				if d.Unit == "widgets/op" {
+					found = true
+					if d.Old != 1 || d.New != 2 || d.Percent != 100 || !d.Significant {
+						t.Errorf("ref %q: got delta %+v, want 1 -> 2 (+100%%)", ref, d)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("ref %q: no widgets/op delta in %+v", ref, result.Deltas)
+			}
+
+			doc := shownDocument(t, collectDocs(), "http:")
+			if doc == nil {
+				t.Fatalf("no showDocument call had 'http:' prefix")
+			}
+			report := get(t, doc.URI)
+			checkMatch(t, true, report, `Benchmarks of example.com/a`)
+			checkMatch(t, true, report, `<td>BenchmarkWidgets.*</td><td>1 ±0%</td><td>2 ±0%</td><td>\+100.00%</td>`)
+		}
 	})
 }
 
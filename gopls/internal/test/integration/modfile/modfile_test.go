@@ -1032,6 +1032,120 @@ example.com v1.2.3/go.mod h1:Y2Rc5rVWjWur0h3pd9aEvK5Pof8YKDANh9gHA2Maujo=
 	})
 }
 
+// TestSumDownloadQuickFix checks that the targeted quick fix for a
+// missing go.sum entry downloads just the offending module.
+func TestSumDownloadQuickFix(t *testing.T) {
+	const mod = `
+-- go.mod --
+module mod.com
+
+go 1.12
+
+require (
+	example.com v1.2.3
+)
+-- main.go --
+package main
+
+import (
+	"example.com/blah"
+)
+
+func main() {
+	blah.SaySomething()
+}
+`
+	WithOptions(
+		ProxyFiles(workspaceProxy),
+		Modes(Default),
+	).Run(t, mod, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+		params := &protocol.PublishDiagnosticsParams{}
+		env.AfterChange(
+			Diagnostics(
+				env.AtRegexp("go.mod", `example.com`),
+				WithMessage("go.sum is out of sync"),
+			),
+			ReadDiagnostics("go.mod", params),
+		)
+		const title = "go mod download example.com@v1.2.3"
+		var toApply []protocol.CodeAction
+		for _, fix := range env.GetQuickFixes("go.mod", params.Diagnostics) {
+			if fix.Title == title {
+				toApply = append(toApply, fix)
+			}
+		}
+		if len(toApply) != 1 {
+			t.Fatalf("got %d quick fixes titled %q, want 1; got: %v", len(toApply), title, toApply)
+		}
+		env.ApplyCodeAction(toApply[0])
+		env.AfterChange(
+			NoDiagnostics(ForFile("go.mod")),
+			NoDiagnostics(ForFile("main.go")),
+		)
+		const want = `example.com v1.2.3 h1:Yryq11hF02fEf2JlOS2eph+ICE2/ceevGV3C9dl5V/c=
+example.com v1.2.3/go.mod h1:Y2Rc5rVWjWur0h3pd9aEvK5Pof8YKDANh9gHA2Maujo=
+`
+		if got := env.ReadWorkspaceFile("go.sum"); got != want {
+			t.Fatalf("unexpected go.sum contents:\n%s", compare.Text(want, got))
+		}
+	})
+}
+
+// TestSumDownloadImportQuickFix checks that an import of a package
+// whose module lacks a go.sum entry offers a fix to download just that
+// module, and that the fix resolves the error.
+func TestSumDownloadImportQuickFix(t *testing.T) {
+	const mod = `
+-- go.mod --
+module mod.com
+
+go 1.12
+
+require example.com v1.2.3
+-- go.sum --
+example.com v1.2.3/go.mod h1:Y2Rc5rVWjWur0h3pd9aEvK5Pof8YKDANh9gHA2Maujo=
+-- main.go --
+package main
+
+import "example.com/blah"
+
+func main() {
+	println(blah.Name)
+}
+`
+	WithOptions(
+		ProxyFiles(proxy),
+	).Run(t, mod, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(
+				env.AtRegexp("main.go", `"example.com/blah"`),
+				WithMessage("missing go.sum entry"),
+			),
+			ReadDiagnostics("main.go", &d),
+		)
+		const title = "go mod download example.com@v1.2.3"
+		var toApply []protocol.CodeAction
+		for _, fix := range env.GetQuickFixes("main.go", d.Diagnostics) {
+			if fix.Title == title {
+				toApply = append(toApply, fix)
+			}
+		}
+		if len(toApply) != 1 {
+			t.Fatalf("got %d quick fixes titled %q, want 1; got: %v", len(toApply), title, toApply)
+		}
+		env.ApplyCodeAction(toApply[0])
+		env.AfterChange(
+			NoDiagnostics(ForFile("main.go")),
+		)
+		if got := env.ReadWorkspaceFile("go.sum"); !strings.Contains(got, "example.com v1.2.3 h1:") {
+			t.Fatalf("go.sum lacks module entry:\n%s", got)
+		}
+	})
+}
+
 func TestDownloadDeps(t *testing.T) {
 	const proxy = `
 -- example.com@v1.2.3/go.mod --
@@ -1203,3 +1317,97 @@ func main() {fmt.Println(aaa.A)}
 	})
 
 }
+
+// TestUnusedDependencyWithoutTidy checks that unused dependencies are
+// detected from the import graph when go mod tidy fails, here because
+// of a missing package.
+func TestUnusedDependencyWithoutTidy(t *testing.T) {
+	const proxy = `
+-- example.com@v1.2.3/go.mod --
+module example.com
+
+go 1.12
+-- example.com@v1.2.3/blah/blah.go --
+package blah
+
+const Name = "Blah"
+-- random.org@v1.2.3/go.mod --
+module random.org
+
+go 1.12
+-- random.org@v1.2.3/blah/blah.go --
+package blah
+
+const Name = "Blah"
+-- unused.com@v1.2.3/go.mod --
+module unused.com
+
+go 1.12
+-- unused.com@v1.2.3/blah/blah.go --
+package blah
+
+const Name = "Blah"
+`
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.12
+
+require (
+	example.com v1.2.3
+	random.org v1.2.3
+	unused.com v1.2.3
+)
+-- main.go --
+package main
+
+import (
+	"example.com/blah"
+	"mod.com/missing"
+)
+
+func main() {
+	println(blah.Name, missing.Name)
+}
+-- main_plan9.go --
+package main
+
+import "random.org/blah"
+
+var _ = blah.Name
+`
+	WithOptions(
+		ProxyFiles(proxy),
+		WriteGoSum("."),
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.mod")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.mod", "unused.com v1.2.3"), WithMessage("not imported")),
+			NoDiagnostics(env.AtRegexp("go.mod", "example.com v1.2.3")),
+			NoDiagnostics(env.AtRegexp("go.mod", "random.org v1.2.3")),
+			ReadDiagnostics("go.mod", &d),
+		)
+		fixes := env.GetQuickFixes("go.mod", d.Diagnostics)
+		if len(fixes) != 1 {
+			t.Fatalf("got %d quick fixes, want 1", len(fixes))
+		}
+
+		// Make the module tidy-able, so that the fix can run go mod tidy.
+		env.WriteWorkspaceFile("missing/missing.go", "package missing\n\nconst Name = \"Missing\"\n")
+		env.ApplyCodeAction(fixes[0])
+		const want = `module mod.com
+
+go 1.12
+
+require (
+	example.com v1.2.3
+	random.org v1.2.3
+)
+`
+		if got := env.BufferText("go.mod"); got != want {
+			t.Fatalf("unexpected content in go.mod:\n%s", compare.Text(want, got))
+		}
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modfile
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/test/compare"
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+const sumProxy = `
+-- example.com@v1.2.3/go.mod --
+module example.com
+
+go 1.12
+
+require dep.com v1.0.0
+-- example.com@v1.2.3/blah/blah.go --
+package blah
+
+import "dep.com/dep"
+
+const Name = dep.Name
+-- dep.com@v1.0.0/go.mod --
+module dep.com
+
+go 1.12
+-- dep.com@v1.0.0/dep/dep.go --
+package dep
+
+const Name = "Dep"
+-- random.org@v1.2.3/go.mod --
+module random.org
+
+go 1.12
+`
+
+const sumFiles = `
+-- go.mod --
+module mod.com
+
+go 1.12
+
+require example.com v1.2.3
+-- main.go --
+package main
+
+import "example.com/blah"
+
+func main() {
+	println(blah.Name)
+}
+`
+
+func TestSumUnusedAndMissingEntries(t *testing.T) {
+	WithOptions(
+		ProxyFiles(sumProxy),
+		WriteGoSum("."),
+	).Run(t, sumFiles, func(t *testing.T, env *Env) {
+		want := env.ReadWorkspaceFile("go.sum")
+
+		// Remove the entry for the go.mod file of dep.com, and add one
+		// for random.org.
+		var lines []string
+		for _, line := range strings.SplitAfter(want, "\n") {
+			if !strings.HasPrefix(line, "dep.com v1.0.0/go.mod ") {
+				lines = append(lines, line)
+			}
+		}
+		lines = append(lines, "random.org v1.2.3/go.mod h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=\n")
+		env.OpenFile("go.sum")
+		env.SetBufferContent("go.sum", strings.Join(lines, ""))
+
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.sum", "random.org"), WithMessage("not needed by go.mod")),
+			Diagnostics(ForFile("go.sum"), WithMessage("missing go.sum entry for dep.com v1.0.0/go.mod")),
+		)
+
+		// Apply the fixes one at a time, as their edits are relative to
+		// the same version of the file.
+		for range 2 {
+			var d protocol.PublishDiagnosticsParams
+			env.AfterChange(ReadDiagnostics("go.sum", &d))
+			env.ApplyQuickFixes("go.sum", d.Diagnostics[:1])
+		}
+		if got := env.BufferText("go.sum"); got != want {
+			t.Errorf("unexpected go.sum content after fixes:\n%s", compare.Text(want, got))
+		}
+		env.AfterChange(NoDiagnostics(ForFile("go.sum")))
+	})
+}
+
+func TestSumChecksumMismatch(t *testing.T) {
+	WithOptions(
+		ProxyFiles(sumProxy),
+		WriteGoSum("."),
+	).Run(t, sumFiles, func(t *testing.T, env *Env) {
+		want := env.ReadWorkspaceFile("go.sum")
+		env.OpenFile("go.sum")
+		hash := env.RegexpSearch("go.sum", `dep.com v1.0.0/go.mod (h1:\S+)`)
+		env.EditBuffer("go.sum", protocol.TextEdit{Range: hash.Range, NewText: "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="})
+
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.sum", `dep.com v1.0.0/go.mod (h1:\S+)`), WithMessage("checksum mismatch")),
+			ReadDiagnostics("go.sum", &d),
+		)
+		env.ApplyQuickFixes("go.sum", d.Diagnostics)
+		if got := env.BufferText("go.sum"); got != want {
+			t.Errorf("unexpected go.sum content after re-hashing:\n%s", compare.Text(want, got))
+		}
+	})
+}
+
+func TestSumHover(t *testing.T) {
+	WithOptions(
+		ProxyFiles(sumProxy),
+		WriteGoSum("."),
+	).Run(t, sumFiles, func(t *testing.T, env *Env) {
+		env.OpenFile("go.sum")
+		for _, test := range []struct {
+			re   string
+			want []string
+		}{
+			{
+				`example.com v1.2.3 h1`,
+				[]string{"Checksum of the content of `example.com@v1.2.3`.", "required by `require example.com v1.2.3`"},
+			},
+			{
+				`dep.com v1.0.0/go.mod`,
+				[]string{"Checksum of the go.mod file of `dep.com@v1.0.0`.", "introduced by `require example.com v1.2.3`", "-- dep.com@v1.0.0"},
+			},
+		} {
+			content, _ := env.Hover(env.RegexpSearch("go.sum", test.re))
+			if content == nil {
+				t.Fatalf("no hover for %s", test.re)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(content.Value, want) {
+					t.Errorf("hover for %s does not contain %q:\n%s", test.re, want, content.Value)
+				}
+			}
+		}
+	})
+}
@@ -6,6 +6,7 @@ package template
 
 import (
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -254,4 +255,72 @@ Hello {{
 	})
 }
 
+func TestRenameTemplate(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.12
+-- b.tmpl --
+{{define "A"}}goo{{end}}
+-- a.tmpl --
+{{template "A"}}{{block "A" .}}{{end}}
+`
+	WithOptions(
+		Settings{"templateExtensions": []string{"tmpl"}},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.tmpl")
+		env.OpenFile("b.tmpl")
+		env.Rename(env.RegexpSearch("a.tmpl", `A`), "Z")
+		if got, want := env.BufferText("a.tmpl"), "{{template \"Z\"}}{{block \"Z\" .}}{{end}}\n"; got != want {
+			t.Errorf("a.tmpl after rename = %q, want %q", got, want)
+		}
+		if got, want := env.BufferText("b.tmpl"), "{{define \"Z\"}}goo{{end}}\n"; got != want {
+			t.Errorf("b.tmpl after rename = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestCompletionOfDataFields(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.12
+-- main.go --
+package main
+
+import (
+	"os"
+	"text/template"
+)
+
+type Page struct {
+	Title string
+	Tags  []string
+}
+
+func main() {
+	t := template.Must(template.ParseGlob("*.tmpl"))
+	t.ExecuteTemplate(os.Stdout, "page", &Page{})
+}
+-- page.tmpl --
+{{define "page"}}{{.T}}{{end}}
+`
+	WithOptions(
+		Settings{"templateExtensions": []string{"tmpl"}},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("page.tmpl")
+		list := env.Completion(env.RegexpSearch("page.tmpl", `\.T()`))
+		var got []string
+		for _, item := range list.Items {
+			got = append(got, item.Label)
+		}
+		want := []string{"Tags", "Title"}
+		if !slices.Equal(got, want) {
+			t.Errorf("completions = %v, want %v", got, want)
+		}
+	})
+}
+
 // Hover needs tests
@@ -5,6 +5,7 @@
 package workspace
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -456,3 +457,176 @@ var _ [int("")]byte
 		}
 	})
 }
+
+func TestQuickFix_RemoveRedundantUse(t *testing.T) {
+	const files = `
+-- go.work --
+go 1.20
+
+use (
+	./a
+	./a/
+	./missing
+)
+-- a/go.mod --
+module mod.com/a
+
+go 1.18
+-- a/a.go --
+package a
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.work")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("go.work", `\./a/`), WithMessage("redundant use")),
+			Diagnostics(env.AtRegexp("go.work", `\./missing`), WithMessage("does not contain a module")),
+			ReadDiagnostics("go.work", &d),
+		)
+		// Fixes are applied one at a time, as each edit invalidates the others.
+		for len(d.Diagnostics) > 0 {
+			env.ApplyQuickFixes("go.work", d.Diagnostics[:1])
+			env.AfterChange(ReadDiagnostics("go.work", &d))
+		}
+		want := `go 1.20
+
+use (
+	./a
+)
+`
+		if diff := compare.Text(want, env.BufferText("go.work")); diff != "" {
+			t.Errorf("unexpected go.work content:\n%s", diff)
+		}
+	})
+}
+
+func TestQuickFix_AddUseToGoWork(t *testing.T) {
+	// Module b, which requires module a of the workspace, is not
+	// used by go.work.
+	const files = `
+-- go.work --
+go 1.20
+
+use ./a
+-- a/go.mod --
+module mod.com/a
+
+go 1.18
+-- a/a.go --
+package a
+-- b/go.mod --
+module mod.com/b
+
+go 1.18
+
+require mod.com/a v0.0.0
+-- b/b.go --
+package b
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("go.work")
+		env.OpenFile("b/b.go")
+		env.OpenFile("b/go.mod")
+		var d protocol.PublishDiagnosticsParams
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("b/go.mod", "module"), WithMessage("not used by")),
+			ReadDiagnostics("b/go.mod", &d),
+		)
+		d.Diagnostics = slices.DeleteFunc(d.Diagnostics, func(diag protocol.Diagnostic) bool {
+			return !strings.Contains(diag.Message, "not used by")
+		})
+		env.ApplyQuickFixes("b/go.mod", d.Diagnostics)
+		want := `go 1.20
+
+use (
+	./a
+	./b
+)
+`
+		if diff := compare.Text(want, env.BufferText("go.work")); diff != "" {
+			t.Errorf("unexpected go.work content:\n%s", diff)
+		}
+	})
+}
+
+func TestQuickFix_NestedModulesHint(t *testing.T) {
+	// A folder containing several related modules but no go.work
+	// file gets a hint on each of them.
+	const multi = `
+-- a/go.mod --
+module mod.com/a
+
+go 1.18
+-- a/a.go --
+package a
+-- b/go.mod --
+module mod.com/b
+
+go 1.18
+
+require mod.com/a v0.0.0
+-- b/b.go --
+package b
+`
+	Run(t, multi, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.OpenFile("b/b.go")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("a/go.mod", "module"), WithMessage("has no go.work file")),
+			Diagnostics(env.AtRegexp("b/go.mod", "module"), WithMessage("has no go.work file")),
+		)
+	})
+
+	// Unrelated modules, or modules whose requirements are replaced
+	// by local directories, get no hint.
+	const unrelated = `
+-- a/go.mod --
+module mod.com/a
+
+go 1.18
+-- a/a.go --
+package a
+-- b/go.mod --
+module mod.com/b
+
+go 1.18
+
+require mod.com/a v0.0.0
+
+replace mod.com/a => ../a
+-- b/b.go --
+package b
+-- c/go.mod --
+module mod.com/c
+
+go 1.18
+-- c/c.go --
+package c
+`
+	Run(t, unrelated, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.OpenFile("b/b.go")
+		env.OpenFile("c/c.go")
+		env.AfterChange(
+			NoDiagnostics(ForFile("a/go.mod"), WithMessage("go.work")),
+			NoDiagnostics(ForFile("b/go.mod"), WithMessage("go.work")),
+			NoDiagnostics(ForFile("c/go.mod"), WithMessage("go.work")),
+		)
+	})
+
+	// A folder whose only module is nested gets no hint.
+	const single = `
+-- a/go.mod --
+module mod.com/a
+
+go 1.18
+-- a/a.go --
+package a
+`
+	Run(t, single, func(t *testing.T, env *Env) {
+		env.OpenFile("a/a.go")
+		env.AfterChange(
+			NoDiagnostics(ForFile("a/go.mod")),
+		)
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package workspace
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/server"
+
+	. "golang.org/x/tools/gopls/internal/test/integration"
+)
+
+func TestSetViewEnvironment(t *testing.T) {
+	const files = `
+-- go.mod --
+module mod.com
+
+go 1.18
+-- main.go --
+package main
+
+var _ = X
+-- foo.go --
+//go:build foo && plan9
+
+package main
+
+const X = 1
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("main.go")
+		env.AfterChange(Diagnostics(env.AtRegexp("main.go", "X")))
+
+		calls := 0
+		setEnv := func(args command.SetViewEnvironmentArgs, want Expectation) {
+			t.Helper()
+			args.URI = env.Sandbox.Workdir.URI("main.go")
+			cmd := command.NewSetViewEnvironmentCommand("", args)
+			env.ExecuteCommand(&protocol.ExecuteCommandParams{
+				Command:   cmd.Command,
+				Arguments: cmd.Arguments,
+			}, nil)
+			calls++
+			env.OnceMet(
+				CompletedWork(server.DiagnosticWorkTitle(server.FromSetViewEnvironment), uint64(calls), true),
+				want,
+			)
+		}
+
+		// Each call replaces the previous overrides.
+		setEnv(command.SetViewEnvironmentArgs{BuildTags: []string{"foo"}},
+			Diagnostics(env.AtRegexp("main.go", "X")))
+		setEnv(command.SetViewEnvironmentArgs{Env: map[string]string{"GOOS": "plan9"}},
+			Diagnostics(env.AtRegexp("main.go", "X")))
+		setEnv(command.SetViewEnvironmentArgs{Env: map[string]string{"GOOS": "plan9"}, BuildTags: []string{"foo"}},
+			NoDiagnostics(ForFile("main.go")))
+
+		// Clearing the overrides restores the folder's configuration.
+		setEnv(command.SetViewEnvironmentArgs{},
+			Diagnostics(env.AtRegexp("main.go", "X")))
+	})
+}
@@ -164,6 +164,57 @@ package a
 	})
 }
 
+func TestBuildConfigurations(t *testing.T) {
+	// This test checks that the buildConfigurations setting causes gopls to
+	// create a view for each additional configuration, and that diagnostics
+	// arising only in those configurations are reported.
+	const files = `
+-- go.mod --
+module a.com/a
+
+go 1.20
+
+-- a.go --
+package a
+
+var _ = f()
+
+-- a_darwin.go --
+package a
+
+func f() int { return 0 }
+`
+
+	// The default configuration deliberately differs from the host's, so
+	// that the suffix is computed relative to the folder's GOOS and GOARCH.
+	WithOptions(
+		EnvVars{"GOOS": "darwin", "GOARCH": "arm64"},
+		Settings{"buildConfigurations": []string{"darwin/arm64", "windows/arm64"}},
+	).Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.go")
+		env.AfterChange(
+			Diagnostics(env.AtRegexp("a.go", "f"), WithMessage("undefined: f [windows]")),
+		)
+		summary := func(envOverlay ...string) command.View {
+			return command.View{
+				Type:       cache.GoModView.String(),
+				Root:       env.Sandbox.Workdir.URI("."),
+				Folder:     env.Sandbox.Workdir.URI("."),
+				EnvOverlay: envOverlay,
+			}
+		}
+		got := env.Views()
+		want := []command.View{summary(), summary("GOARCH=arm64", "GOOS=windows")}
+		if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(command.View{}, "ID")); diff != "" {
+			t.Errorf("SummarizeViews() mismatch (-want +got):\n%s", diff)
+		}
+
+		// Adding the missing declaration for windows resolves the diagnostic.
+		env.CreateBuffer("a_windows.go", "package a\n\nfunc f() int { return 1 }\n")
+		env.AfterChange(NoDiagnostics(ForFile("a.go")))
+	})
+}
+
 func TestCriticalErrorsInOrphanedFiles(t *testing.T) {
 	// This test checks that as we open and close files requiring a different
 	// port, the set of Views is adjusted accordingly.
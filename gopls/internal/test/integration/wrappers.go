@@ -297,6 +297,17 @@ func (e *Env) DocumentHighlight(loc protocol.Location) []protocol.DocumentHighli
 	return highlights
 }
 
+// LinkedEditingRange calls textDocument/linkedEditingRange at the given
+// location, calling t.Fatal on any error.
+func (e *Env) LinkedEditingRange(loc protocol.Location) *protocol.LinkedEditingRanges {
+	e.T.Helper()
+	ranges, err := e.Editor.LinkedEditingRange(e.Ctx, loc)
+	if err != nil {
+		e.T.Fatal(err)
+	}
+	return ranges
+}
+
 // RunGenerate runs "go generate" in the given dir, calling t.Fatal on any error.
 // It waits for the generate command to complete and checks for file changes
 // before returning.
@@ -465,6 +476,17 @@ func (e *Env) InlayHints(path string) []protocol.InlayHint {
 	return hints
 }
 
+// InlineValue calls textDocument/inlineValue for the file of loc, as
+// if execution had stopped at loc, failing the test on any error.
+func (e *Env) InlineValue(loc protocol.Location) []protocol.InlineValue {
+	e.T.Helper()
+	values, err := e.Editor.InlineValue(e.Ctx, loc)
+	if err != nil {
+		e.T.Fatal(err)
+	}
+	return values
+}
+
 // Symbol calls workspace/symbol
 func (e *Env) Symbol(query string) []protocol.SymbolInformation {
 	e.T.Helper()
@@ -503,6 +525,22 @@ func (e *Env) Implementations(loc protocol.Location) []protocol.Location {
 	return locations
 }
 
+// CreateFile wraps Editor.CreateFile, calling t.Fatal on any error.
+func (e *Env) CreateFile(path string) {
+	e.T.Helper()
+	if err := e.Editor.CreateFile(e.Ctx, path); err != nil {
+		e.T.Fatal(err)
+	}
+}
+
+// WillRenameFile wraps Editor.WillRenameFile, calling t.Fatal on any error.
+func (e *Env) WillRenameFile(oldPath, newPath string) {
+	e.T.Helper()
+	if err := e.Editor.WillRenameFile(e.Ctx, oldPath, newPath); err != nil {
+		e.T.Fatal(err)
+	}
+}
+
 // RenameFile wraps Editor.RenameFile, calling t.Fatal on any error.
 func (e *Env) RenameFile(oldPath, newPath string) {
 	e.T.Helper()
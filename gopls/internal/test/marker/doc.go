@@ -222,6 +222,11 @@ Here is the list of supported action markers:
     (These locations are the declarations of the functions enclosing
     the calls, not the calls themselves.)
 
+  - linkedediting(src location, want ...location): makes a
+    textDocument/linkedEditingRange request at the src location, and checks
+    that the result is the want locations, in order. With no want locations,
+    it checks that the result is empty.
+
   - outgoingcalls(src location, want ...location): makes a
     callHierarchy/outgoingCalls query at the src location, and checks that
     the set of call.To locations matches want.
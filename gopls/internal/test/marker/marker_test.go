@@ -584,6 +584,7 @@ var actionMarkerFuncs = map[string]func(marker){
 	"implementation":   actionMarkerFunc(implementationMarker),
 	"incomingcalls":    actionMarkerFunc(incomingCallsMarker),
 	"inlayhints":       actionMarkerFunc(inlayhintsMarker),
+	"linkedediting":    actionMarkerFunc(linkedEditingMarker),
 	"outgoingcalls":    actionMarkerFunc(outgoingCallsMarker),
 	"preparerename":    actionMarkerFunc(prepareRenameMarker, "span"),
 	"rank":             actionMarkerFunc(rankMarker),
@@ -961,7 +962,7 @@ func newEnv(t *testing.T, cache *cache.Cache, files, proxyFiles map[string][]byt
 	// Put a debug instance in the context to prevent logging to stderr.
 	// See associated TODO in runner.go: we should revisit this pattern.
 	ctx := context.Background()
-	ctx = debug.WithInstance(ctx, "off")
+	ctx = debug.WithInstance(ctx, "off", "off")
 
 	awaiter := integration.NewAwaiter(sandbox.Workdir)
 	ss := lsprpc.NewStreamServer(cache, false, nil)
@@ -1788,6 +1789,19 @@ func highlightMarker(mark marker, src protocol.DocumentHighlight, dsts ...protoc
 	}
 }
 
+// linkedEditingMarker implements the @linkedediting marker.
+func linkedEditingMarker(mark marker, src protocol.Location, dsts ...protocol.Location) {
+	var got []protocol.Location
+	if ranges := mark.run.env.LinkedEditingRange(src); ranges != nil {
+		for _, rng := range ranges.Ranges {
+			got = append(got, protocol.Location{URI: src.URI, Range: rng})
+		}
+	}
+	if diff := cmp.Diff(dsts, got, cmpopts.EquateEmpty()); diff != "" {
+		mark.errorf("LinkedEditingRange(%v) mismatch (-want +got):\n%s", src, diff)
+	}
+}
+
 func hoverMarker(mark marker, src, dst protocol.Location, sc stringMatcher) {
 	content, gotDst := mark.run.env.Hover(src)
 	if gotDst != dst {
@@ -0,0 +1,143 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package asm provides a simple parser for Go assembly files,
+// sufficient to find the symbols that they define and reference.
+package asm
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Kind describes the role of an identifier in an assembly file.
+type Kind uint8
+
+const (
+	Invalid Kind = iota
+	Text         // the function defined by a TEXT instruction
+	Data         // the variable defined by a DATA or GLOBL instruction
+	Ref          // a reference to a symbol within another instruction
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Text:
+		return "text"
+	case Data:
+		return "data"
+	case Ref:
+		return "ref"
+	}
+	return "invalid"
+}
+
+// A File is the result of parsing an assembly file.
+type File struct {
+	Idents []Ident // in order of appearance
+}
+
+// An Ident is an occurrence of a symbol name in an assembly file,
+// such as ·add in "TEXT ·add(SB), NOSPLIT, $0-24".
+type Ident struct {
+	// Name is the symbol name, in which the middle dot (·) and the
+	// division slash (∕) of assembly are replaced by '.' and '/'.
+	// A symbol of the current package has a leading dot, as in ".add";
+	// others are qualified by a package path, as in "runtime.memmove",
+	// unless they are not Go symbols.
+	Name string
+
+	Offset, End int // byte offsets of the symbol in the file
+	Kind        Kind
+}
+
+// Local returns the name of the symbol in its Go package, and reports
+// whether it belongs to the package with the given path.
+func (id Ident) Local(pkgPath string) (string, bool) {
+	if name, ok := strings.CutPrefix(id.Name, "."); ok {
+		return name, true
+	}
+	if name, ok := strings.CutPrefix(id.Name, pkgPath+"."); ok && pkgPath != "" {
+		return name, true
+	}
+	return "", false
+}
+
+// symbolRe matches a reference to a symbol relative to the static base
+// pseudo-register, such as "·add(SB)", "runtime·memmove<ABIInternal>(SB)",
+// or "·table+8(SB)". The first group is the symbol name.
+var symbolRe = regexp.MustCompile(`([\pL_·][\pL\pN_·∕./]*)(?:<\w+>)?(?:[+-]\d+)?\(SB\)`)
+
+// Parse parses the content of an assembly file. It does not report
+// errors: it finds what it can.
+func Parse(content []byte) *File {
+	var file File
+	inComment := false // within a /* */ comment
+	for offset := 0; offset < len(content); {
+		line := content[offset:]
+		if nl := bytes.IndexByte(line, '\n'); nl >= 0 {
+			line = line[:nl+1]
+		}
+		text := blankComments(line, &inComment)
+
+		// The first identifier of the line may be an instruction
+		// (possibly after a label), which defines its first symbol.
+		kind := Ref
+		instr := strings.Fields(string(text))
+		if len(instr) > 0 && strings.HasSuffix(instr[0], ":") {
+			instr = instr[1:]
+		}
+		if len(instr) > 0 {
+			switch instr[0] {
+			case "TEXT":
+				kind = Text
+			case "DATA", "GLOBL":
+				kind = Data
+			}
+		}
+		for _, m := range symbolRe.FindAllSubmatchIndex(text, -1) {
+			file.Idents = append(file.Idents, Ident{
+				Name:   normalizer.Replace(string(text[m[2]:m[3]])),
+				Offset: offset + m[2],
+				End:    offset + m[3],
+				Kind:   kind,
+			})
+			kind = Ref
+		}
+		offset += len(line)
+	}
+	return &file
+}
+
+// blankComments returns a copy of line in which comments are replaced
+// by spaces, so that offsets are preserved. The inComment state carries
+// over /* */ comments from line to line.
+func blankComments(line []byte, inComment *bool) []byte {
+	text := bytes.Clone(line)
+	for i := 0; i < len(text); i++ {
+		switch {
+		case *inComment:
+			if bytes.HasPrefix(text[i:], []byte("*/")) {
+				*inComment = false
+				text[i+1] = ' '
+			}
+			if text[i] != '\n' {
+				text[i] = ' '
+			}
+		case bytes.HasPrefix(text[i:], []byte("//")):
+			for ; i < len(text) && text[i] != '\n'; i++ {
+				text[i] = ' '
+			}
+		case bytes.HasPrefix(text[i:], []byte("/*")):
+			*inComment = true
+			text[i] = ' '
+		}
+	}
+	return text
+}
+
+// normalizer converts the middle dot and division slash of assembly
+// symbol names to the Go form.
+var normalizer = strings.NewReplacer("·", ".", "∕", "/")
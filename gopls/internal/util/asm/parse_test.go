@@ -0,0 +1,74 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package asm_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/util/asm"
+)
+
+func TestParse(t *testing.T) {
+	const src = `// Copyright notice.
+
+#include "textflag.h"
+
+// func add(x, y int) int
+TEXT ·add(SB), NOSPLIT, $0-24
+	MOVQ x+0(FP), AX
+	ADDQ y+8(FP), AX
+	CALL runtime·entersyscall<ABIInternal>(SB) // a call
+	/* CALL ·commented(SB)
+	   CALL ·commented(SB) */ JMP ·tail(SB)
+	MOVQ $·table+8(SB), BX
+	RET
+
+DATA ·table+0(SB)/8, $1
+GLOBL ·table(SB), RODATA, $16
+loop: CALL example.com/a∕b·f(SB)
+`
+	var got []string
+	for _, id := range asm.Parse([]byte(src)).Idents {
+		if text := src[id.Offset:id.End]; normalize(text) != id.Name {
+			t.Errorf("%s: text %q does not match name", id.Name, text)
+		}
+		got = append(got, fmt.Sprintf("%s %s", id.Kind, id.Name))
+	}
+	want := []string{
+		"text .add",
+		"ref runtime.entersyscall",
+		"ref .tail",
+		"ref .table",
+		"data .table",
+		"data .table",
+		"ref example.com/a/b.f",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Parse: got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestIdentLocal(t *testing.T) {
+	for _, test := range []struct {
+		name, pkgPath, want string
+		ok                  bool
+	}{
+		{".add", "example.com/a", "add", true},
+		{"example.com/a.add", "example.com/a", "add", true},
+		{"runtime.memmove", "example.com/a", "", false},
+		{"_rt0_amd64", "example.com/a", "", false},
+	} {
+		got, ok := asm.Ident{Name: test.name}.Local(test.pkgPath)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Ident{%q}.Local(%q) = %q, %t, want %q, %t", test.name, test.pkgPath, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func normalize(s string) string {
+	return strings.NewReplacer("·", ".", "∕", "/").Replace(s)
+}
@@ -10,6 +10,8 @@ import (
 	"go/token"
 	"go/types"
 	"strings"
+
+	"golang.org/x/tools/internal/typeparams"
 )
 
 // FormatTypeParams turns TypeParamList into its Go representation, such as:
@@ -51,7 +53,7 @@ func TypesFromContext(info *types.Info, path []ast.Node, pos token.Pos) []types.
 	}
 
 	validType := func(t types.Type) types.Type {
-		if t != nil && !containsInvalid(t) {
+		if t != nil && !containsInvalid(t) && !isUntypedNil(t) {
 			return types.Default(t)
 		} else {
 			return anyType
@@ -183,12 +185,91 @@ func TypesFromContext(info *types.Info, path []ast.Node, pos token.Pos) []types.
 			t := info.TypeOf(parent.X)
 			typs = append(typs, validType(t))
 		}
+	case *ast.SendStmt:
+		if parent.Value == path[0] {
+			if ch, ok := typeparams.CoreType(info.TypeOf(parent.Chan)).(*types.Chan); ok {
+				typs = append(typs, validType(ch.Elem()))
+			}
+		}
+	case *ast.IndexExpr:
+		if parent.Index == path[0] {
+			switch t := typeparams.CoreType(info.TypeOf(parent.X)).(type) {
+			case *types.Map:
+				typs = append(typs, validType(t.Key()))
+			case *types.Slice, *types.Array, *types.Pointer, *types.Basic:
+				typs = append(typs, types.Typ[types.Int])
+			}
+		}
+	case *ast.KeyValueExpr:
+		// An element of a keyed composite literal.
+		lit, ok := parentNode(path[1:]).(*ast.CompositeLit)
+		if !ok {
+			break
+		}
+		switch t := compositeLitType(info, lit).(type) {
+		case *types.Struct:
+			if parent.Value == path[0] {
+				if key, ok := parent.Key.(*ast.Ident); ok {
+					for i := 0; i < t.NumFields(); i++ {
+						if field := t.Field(i); field.Name() == key.Name {
+							typs = append(typs, validType(field.Type()))
+							break
+						}
+					}
+				}
+			}
+		case *types.Map:
+			if parent.Key == path[0] {
+				typs = append(typs, validType(t.Key()))
+			} else if parent.Value == path[0] {
+				typs = append(typs, validType(t.Elem()))
+			}
+		case *types.Slice:
+			if parent.Value == path[0] {
+				typs = append(typs, validType(t.Elem()))
+			}
+		case *types.Array:
+			if parent.Value == path[0] {
+				typs = append(typs, validType(t.Elem()))
+			}
+		}
+	case *ast.CompositeLit:
+		// An element of an unkeyed composite literal.
+		switch t := compositeLitType(info, parent).(type) {
+		case *types.Struct:
+			for i, elt := range parent.Elts {
+				if elt == path[0] && i < t.NumFields() {
+					typs = append(typs, validType(t.Field(i).Type()))
+					break
+				}
+			}
+		case *types.Slice:
+			typs = append(typs, validType(t.Elem()))
+		case *types.Array:
+			typs = append(typs, validType(t.Elem()))
+		}
 	default:
 		// TODO: support other kinds of "holes" as the need arises.
 	}
 	return typs
 }
 
+// compositeLitType returns the core type of the composite literal,
+// dereferencing the implicit pointer of an elided &T{...} element type.
+func compositeLitType(info *types.Info, lit *ast.CompositeLit) types.Type {
+	t := typeparams.CoreType(info.TypeOf(lit))
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = typeparams.CoreType(ptr.Elem())
+	}
+	return t
+}
+
+// isUntypedNil reports whether t is the type of the predeclared nil.
+func isUntypedNil(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Kind() == types.UntypedNil
+}
+
 // parentNode returns the nodes immediately enclosing path[0],
 // ignoring parens.
 func parentNode(path []ast.Node) ast.Node {
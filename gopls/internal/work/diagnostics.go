@@ -9,32 +9,44 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/file"
 	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/util/pathutil"
+	"golang.org/x/tools/internal/diff"
 	"golang.org/x/tools/internal/event"
 )
 
-func Diagnostics(ctx context.Context, snapshot *cache.Snapshot) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
+// Diagnostics returns diagnostics for the go.work file of the snapshot's
+// view, if any, and for the use of its module by a go.work file. The
+// views of the session determine the other modules of the workspace
+// folder.
+func Diagnostics(ctx context.Context, snapshot *cache.Snapshot, views []*cache.View) (map[protocol.DocumentURI][]*cache.Diagnostic, error) {
 	ctx, done := event.Start(ctx, "work.Diagnostics", snapshot.Labels()...)
 	defer done()
 
 	reports := map[protocol.DocumentURI][]*cache.Diagnostic{}
-	uri := snapshot.View().GoWork()
-	if uri == "" {
-		return nil, nil
-	}
-	fh, err := snapshot.ReadFile(ctx, uri)
-	if err != nil {
-		return nil, err
+	var diagnostics []*cache.Diagnostic
+	if uri := snapshot.View().GoWork(); uri != "" {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		reports[fh.URI()] = []*cache.Diagnostic{}
+		diagnostics, err = diagnoseOne(ctx, snapshot, fh)
+		if err != nil {
+			return nil, err
+		}
 	}
-	reports[fh.URI()] = []*cache.Diagnostic{}
-	diagnostics, err := diagnoseOne(ctx, snapshot, fh)
+	moddiags, err := diagnoseModule(ctx, snapshot, views)
 	if err != nil {
 		return nil, err
 	}
+	diagnostics = append(diagnostics, moddiags...)
 	for _, d := range diagnostics {
 		fh, err := snapshot.ReadFile(ctx, d.URI)
 		if err != nil {
@@ -55,31 +67,265 @@ func diagnoseOne(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle)
 		return pw.ParseErrors, nil
 	}
 
-	// Add diagnostic if a directory does not contain a module.
+	// Add diagnostic if a directory does not contain a module,
+	// or is used more than once.
 	var diagnostics []*cache.Diagnostic
+	used := make(map[protocol.DocumentURI]bool) // go.mod files of used modules
 	for _, use := range pw.File.Use {
 		rng, err := pw.Mapper.OffsetRange(use.Syntax.Start.Byte, use.Syntax.End.Byte)
 		if err != nil {
 			return nil, err
 		}
+		removeFix, err := removeUseFix(pw, use)
+		if err != nil {
+			return nil, err
+		}
+
+		modURI := modFileURI(pw, use)
+		if used[modURI] {
+			diagnostics = append(diagnostics, &cache.Diagnostic{
+				URI:            fh.URI(),
+				Range:          rng,
+				Severity:       protocol.SeverityWarning,
+				Source:         cache.WorkFileError,
+				Message:        fmt.Sprintf("redundant use of directory %v", use.Path),
+				Tags:           []protocol.DiagnosticTag{protocol.Unnecessary},
+				SuggestedFixes: []cache.SuggestedFix{removeFix},
+			})
+			continue
+		}
+		used[modURI] = true
 
-		modfh, err := snapshot.ReadFile(ctx, modFileURI(pw, use))
+		modfh, err := snapshot.ReadFile(ctx, modURI)
 		if err != nil {
 			return nil, err
 		}
 		if _, err := modfh.Content(); err != nil && os.IsNotExist(err) {
 			diagnostics = append(diagnostics, &cache.Diagnostic{
-				URI:      fh.URI(),
-				Range:    rng,
-				Severity: protocol.SeverityError,
-				Source:   cache.WorkFileError,
-				Message:  fmt.Sprintf("directory %v does not contain a module", use.Path),
+				URI:            fh.URI(),
+				Range:          rng,
+				Severity:       protocol.SeverityError,
+				Source:         cache.WorkFileError,
+				Message:        fmt.Sprintf("directory %v does not contain a module", use.Path),
+				SuggestedFixes: []cache.SuggestedFix{removeFix},
 			})
 		}
 	}
 	return diagnostics, nil
 }
 
+// removeUseFix returns a suggested fix that deletes the line of the
+// given use directive from the go.work file.
+func removeUseFix(pw *cache.ParsedWorkFile, use *modfile.Use) (cache.SuggestedFix, error) {
+	// Delete the entire line, including leading indentation and the
+	// trailing newline, so that no blank line is left behind.
+	content := pw.Mapper.Content
+	start, end := use.Syntax.Start.Byte, use.Syntax.End.Byte
+	for start > 0 && (content[start-1] == ' ' || content[start-1] == '\t') {
+		start--
+	}
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	rng, err := pw.Mapper.OffsetRange(start, end)
+	if err != nil {
+		return cache.SuggestedFix{}, err
+	}
+	return cache.SuggestedFix{
+		Title:      fmt.Sprintf("Remove use of %s", use.Path),
+		Edits:      map[protocol.DocumentURI][]protocol.TextEdit{pw.URI: {{Range: rng}}},
+		ActionKind: protocol.QuickFix,
+	}, nil
+}
+
+// diagnoseModule reports a diagnostic on the module directive of the
+// view's go.mod file if that module is nested within a go.work
+// directory but not used by it, or if it is one of several modules
+// nested within a workspace folder that has neither a go.work file nor
+// a go.mod file of its own. In the first case, it offers to add a use
+// directive; in the second, to create a go.work file.
+//
+// In either case, the diagnostic is reported only if the module
+// requires, or is required by, one of the other modules without a
+// replacement by a local directory, as otherwise a go.work file would
+// not change the build of either module.
+func diagnoseModule(ctx context.Context, snapshot *cache.Snapshot, views []*cache.View) ([]*cache.Diagnostic, error) {
+	view := snapshot.View()
+	if view.Type() != cache.GoModView || view.GoMod() == "" || view.GoVersion() < 18 {
+		return nil, nil
+	}
+	modDir := view.GoMod().DirPath()
+	fh, err := snapshot.ReadFile(ctx, view.GoMod())
+	if err != nil {
+		return nil, err
+	}
+	pm, err := snapshot.ParseMod(ctx, fh)
+	if err != nil || pm.File.Module == nil {
+		return nil, nil // parse errors are reported elsewhere
+	}
+	syntax := pm.File.Module.Syntax
+	rng, err := pm.Mapper.OffsetRange(syntax.Start.Byte, syntax.End.Byte)
+	if err != nil {
+		return nil, err
+	}
+
+	if view.GoWork() != "" {
+		// The view is a module excluded from the go.work file (GOWORK=off).
+		workfh, err := snapshot.ReadFile(ctx, view.GoWork())
+		if err != nil {
+			return nil, err
+		}
+		pw, err := snapshot.ParseWork(ctx, workfh)
+		if err != nil {
+			return nil, nil // parse errors are reported by diagnoseOne
+		}
+		workDir := pw.URI.DirPath()
+		if !pathutil.InDir(workDir, modDir) {
+			return nil, nil
+		}
+		var used []*modfile.File
+		for _, use := range pw.File.Use {
+			modURI := modFileURI(pw, use)
+			if modURI == view.GoMod() {
+				return nil, nil // already used (e.g. go.work was just edited)
+			}
+			if mf := parseModFile(ctx, snapshot, modURI); mf != nil {
+				used = append(used, mf)
+			}
+		}
+		if !slices.ContainsFunc(used, func(mf *modfile.File) bool { return related(pm.File, mf) }) {
+			return nil, nil
+		}
+		rel, err := filepath.Rel(workDir, modDir)
+		if err != nil {
+			return nil, nil
+		}
+		edits, err := addUseEdits(pw, "./"+filepath.ToSlash(rel))
+		if err != nil {
+			return nil, err
+		}
+		return []*cache.Diagnostic{{
+			URI:      fh.URI(),
+			Range:    rng,
+			Severity: protocol.SeverityWarning,
+			Source:   cache.WorkFileError,
+			Message:  fmt.Sprintf("module %s is not used by %s", pm.File.Module.Mod.Path, view.GoWork().Path()),
+			SuggestedFixes: []cache.SuggestedFix{{
+				Title:      "Add a use directive for this module to go.work",
+				Edits:      map[protocol.DocumentURI][]protocol.TextEdit{pw.URI: edits},
+				ActionKind: protocol.QuickFix,
+			}},
+		}}, nil
+	}
+
+	// No go.work file: if the module is nested within a workspace
+	// folder that has no module of its own but contains other,
+	// related modules, suggest creating one.
+	folderDir := snapshot.Folder().Path()
+	if modDir == folderDir || !pathutil.InDir(folderDir, modDir) {
+		return nil, nil
+	}
+	if parseModFile(ctx, snapshot, protocol.URIFromPath(filepath.Join(folderDir, "go.mod"))) != nil {
+		return nil, nil // the folder is itself a module
+	}
+	var siblings []*modfile.File
+	for _, v := range views {
+		if v == view || v.Type() != cache.GoModView || v.GoMod() == "" || v.Folder().Dir != snapshot.Folder() {
+			continue
+		}
+		if mf := parseModFile(ctx, snapshot, v.GoMod()); mf != nil {
+			siblings = append(siblings, mf)
+		}
+	}
+	if !slices.ContainsFunc(siblings, func(mf *modfile.File) bool { return related(pm.File, mf) }) {
+		return nil, nil
+	}
+	rel, err := filepath.Rel(folderDir, modDir)
+	if err != nil {
+		return nil, nil
+	}
+	useThis := command.NewRunGoWorkCommandCommand("Run `go work init && go work use`", command.RunGoWorkArgs{
+		ViewID:    view.ID(),
+		InitFirst: true,
+		Args:      []string{"use", rel},
+	})
+	useAll := command.NewRunGoWorkCommandCommand("Run `go work init && go work use -r`", command.RunGoWorkArgs{
+		ViewID:    view.ID(),
+		InitFirst: true,
+		Args:      []string{"use", "-r", "."},
+	})
+	return []*cache.Diagnostic{{
+		URI:      fh.URI(),
+		Range:    rng,
+		Severity: protocol.SeverityHint,
+		Source:   cache.WorkFileError,
+		Message:  fmt.Sprintf("module %s is nested within workspace folder %s, which has no go.work file", pm.File.Module.Mod.Path, folderDir),
+		SuggestedFixes: []cache.SuggestedFix{
+			{
+				Title:      "Add a go.work file using this module",
+				Command:    useThis,
+				ActionKind: protocol.QuickFix,
+			},
+			{
+				Title:      "Add a go.work file using all modules in the folder",
+				Command:    useAll,
+				ActionKind: protocol.QuickFix,
+			},
+		},
+	}}, nil
+}
+
+// parseModFile returns the parsed go.mod file with the given URI, or
+// nil if it does not exist or cannot be parsed.
+func parseModFile(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI) *modfile.File {
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil
+	}
+	if _, err := fh.Content(); err != nil {
+		return nil // does not exist
+	}
+	pm, err := snapshot.ParseMod(ctx, fh)
+	if err != nil || pm.File.Module == nil {
+		return nil
+	}
+	return pm.File
+}
+
+// related reports whether either module requires the other, without
+// replacing it by a local directory.
+func related(x, y *modfile.File) bool {
+	return requires(x, y) || requires(y, x)
+}
+
+// requires reports whether module x requires module y, without
+// replacing it by a local directory.
+func requires(x, y *modfile.File) bool {
+	path := y.Module.Mod.Path
+	if !slices.ContainsFunc(x.Require, func(req *modfile.Require) bool { return req.Mod.Path == path }) {
+		return false
+	}
+	return !slices.ContainsFunc(x.Replace, func(rep *modfile.Replace) bool {
+		return rep.Old.Path == path && modfile.IsDirectoryPath(rep.New.Path)
+	})
+}
+
+// addUseEdits returns the edits to the go.work file that add a use
+// directive for the given directory.
+func addUseEdits(pw *cache.ParsedWorkFile, dir string) ([]protocol.TextEdit, error) {
+	// Don't mutate the cached syntax tree: parse a fresh copy.
+	copied, err := modfile.ParseWork(pw.URI.Path(), pw.Mapper.Content, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := copied.AddUse(dir, ""); err != nil {
+		return nil, err
+	}
+	copied.Cleanup()
+	newContent := modfile.Format(copied.Syntax)
+	return protocol.EditsFromDiffEdits(pw.Mapper, diff.Bytes(pw.Mapper.Content, newContent))
+}
+
 func modFileURI(pw *cache.ParsedWorkFile, use *modfile.Use) protocol.DocumentURI {
 	workdir := pw.URI.DirPath()
 
@@ -9,6 +9,7 @@ package analysisinternal
 import (
 	"bytes"
 	"cmp"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/printer"
@@ -371,6 +372,29 @@ func ValidateFixes(fset *token.FileSet, a *analysis.Analyzer, fixes []analysis.S
 	return nil
 }
 
+// ValidateDiagnostic validates the Scope and Data fields of a
+// single diagnostic. (Its fixes are validated by ValidateFixes.)
+// Any error indicates a bug in the originating analyzer.
+//
+// It may be used as part of an analysis driver implementation.
+func ValidateDiagnostic(fset *token.FileSet, a *analysis.Analyzer, diag analysis.Diagnostic) error {
+	switch diag.Scope {
+	case analysis.ScopePosition, analysis.ScopePackage:
+	case analysis.ScopeFile:
+		if fset.File(diag.Pos) == nil {
+			return fmt.Errorf("analyzer %q reports file-scoped diagnostic with invalid Pos (%v)", a.Name, diag.Pos)
+		}
+	default:
+		return fmt.Errorf("analyzer %q reports diagnostic with invalid Scope (%v)", a.Name, diag.Scope)
+	}
+	if diag.Data != nil {
+		if _, err := json.Marshal(diag.Data); err != nil {
+			return fmt.Errorf("analyzer %q reports diagnostic with invalid Data: %v", a.Name, err)
+		}
+	}
+	return nil
+}
+
 // validateFix validates a single fix.
 // Any error indicates a bug in the originating analyzer.
 //
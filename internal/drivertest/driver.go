@@ -15,14 +15,23 @@ package drivertest
 import (
 	"encoding/json"
 	"flag"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 )
 
 const runAsDriverEnv = "DRIVERTEST_RUN_AS_DRIVER"
 
+// NoSessionsEnv is an environment variable that, if set, causes the
+// driver to implement only version 1 of the driver protocol, without
+// support for sessions.
+const NoSessionsEnv = "DRIVERTEST_NO_SESSIONS"
+
 // RunIfChild runs the current process as a go/packages driver, if configured
 // to do so by the current environment (see [Env]).
 //
@@ -47,6 +56,11 @@ func Env(t interface{ Fatal(...any) }) []string {
 }
 
 func main() {
+	if os.Getenv("GOPACKAGESDRIVER_PROTOCOL") == "2" && os.Getenv(NoSessionsEnv) == "" {
+		serveSession()
+		return
+	}
+
 	flag.Parse()
 
 	dec := json.NewDecoder(os.Stdin)
@@ -55,6 +69,19 @@ func main() {
 		log.Fatalf("decoding request: %v", err)
 	}
 
+	response, err := load(request, flag.Args())
+	if err != nil {
+		log.Fatalf("load failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	if err := enc.Encode(response); err != nil {
+		log.Fatalf("encoding response: %v", err)
+	}
+}
+
+// load answers a query for the given patterns using the go list driver.
+func load(request packages.DriverRequest, patterns []string) (*packages.DriverResponse, error) {
 	config := packages.Config{
 		Mode:       request.Mode,
 		Env:        append(request.Env, "GOPACKAGESDRIVER=off"), // avoid recursive invocation
@@ -62,9 +89,9 @@ func main() {
 		Tests:      request.Tests,
 		Overlay:    request.Overlay,
 	}
-	pkgs, err := packages.Load(&config, flag.Args()...)
+	pkgs, err := packages.Load(&config, patterns...)
 	if err != nil {
-		log.Fatalf("load failed: %v", err)
+		return nil, err
 	}
 
 	var roots []string
@@ -81,12 +108,101 @@ func main() {
 		allPackages = append(allPackages, pkg)
 	})
 
-	enc := json.NewEncoder(os.Stdout)
-	response := packages.DriverResponse{
+	return &packages.DriverResponse{
 		Roots:    roots,
 		Packages: allPackages,
+	}, nil
+}
+
+// serveSession serves a session of version 2 of the driver protocol.
+//
+// As a stand-in for build files, it notifies the client of changes to
+// the files of the packages it has reported, by polling their
+// modification times.
+func serveSession() {
+	dec := json.NewDecoder(os.Stdin)
+	var (
+		mu  sync.Mutex
+		enc = json.NewEncoder(os.Stdout)
+	)
+	send := func(msg packages.DriverMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := enc.Encode(msg); err != nil {
+			log.Fatalf("encoding message: %v", err)
+		}
 	}
-	if err := enc.Encode(response); err != nil {
-		log.Fatalf("encoding response: %v", err)
+
+	var hello packages.DriverQuery
+	if err := dec.Decode(&hello); err != nil {
+		log.Fatalf("decoding handshake: %v", err)
+	}
+	send(packages.DriverMessage{Protocol: 2})
+
+	w := &watcher{files: make(map[string]watchedFile)}
+	go w.poll(send)
+
+	for {
+		var query packages.DriverQuery
+		if err := dec.Decode(&query); err == io.EOF {
+			return
+		} else if err != nil {
+			log.Fatalf("decoding query: %v", err)
+		}
+		msg := packages.DriverMessage{ID: query.ID}
+		response, err := load(query.DriverRequest, query.Patterns)
+		if err != nil {
+			msg.Error = err.Error()
+		} else {
+			msg.Response = response
+			w.watch(response.Packages)
+		}
+		send(msg)
+	}
+}
+
+// A watcher polls the files of packages for changes.
+type watcher struct {
+	mu    sync.Mutex
+	files map[string]watchedFile // by file name
+}
+
+type watchedFile struct {
+	pkg     string // package ID
+	modTime time.Time
+}
+
+func (w *watcher) watch(pkgs []*packages.Package) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, pkg := range pkgs {
+		for _, filename := range pkg.GoFiles {
+			if fi, err := os.Stat(filename); err == nil {
+				w.files[filename] = watchedFile{pkg.ID, fi.ModTime()}
+			}
+		}
+	}
+}
+
+func (w *watcher) poll(send func(packages.DriverMessage)) {
+	for range time.Tick(50 * time.Millisecond) {
+		w.mu.Lock()
+		changed := make(map[string]bool)
+		for filename, f := range w.files {
+			fi, err := os.Stat(filename)
+			if err != nil || !fi.ModTime().Equal(f.modTime) {
+				changed[f.pkg] = true
+				delete(w.files, filename)
+			}
+		}
+		w.mu.Unlock()
+		if len(changed) > 0 {
+			var ids []string
+			for id := range changed {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			send(packages.DriverMessage{Changed: &packages.DriverChange{Packages: ids}})
+		}
 	}
 }
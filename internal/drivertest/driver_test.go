@@ -8,9 +8,12 @@ package drivertest_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/internal/diff"
@@ -151,3 +154,115 @@ package a
 		})
 	}
 }
+
+func TestDriverSession(t *testing.T) {
+	testenv.NeedsExec(t)
+
+	const workspace = `
+-- go.mod --
+module example.com/m
+
+go 1.20
+
+-- m.go --
+package m
+
+-- lib/lib.go --
+package lib
+`
+
+	fs, err := txtar.FS(txtar.Parse([]byte(workspace)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := testfiles.CopyToTmp(t, fs)
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := packages.Config{
+		Dir:  dir,
+		Mode: packages.NeedName | packages.NeedFiles,
+		Env:  append(os.Environ(), drivertest.Env(t)...),
+	}
+	changes := make(chan packages.DriverChange, 10)
+	session, err := packages.NewDriverSession(&cfg, func(change packages.DriverChange) {
+		changes <- change
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	load := func(pattern string) []string {
+		t.Helper()
+		pkgs, err := session.Load(&cfg, pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, pkg := range pkgs {
+			ids = append(ids, pkg.ID)
+		}
+		return ids
+	}
+	if got, want := fmt.Sprint(load("./...")), "[example.com/m example.com/m/lib]"; got != want {
+		t.Errorf("Load(./...) = %s, want %s", got, want)
+	}
+	libFile := filepath.Join(dir, "lib", "lib.go")
+	if got, want := fmt.Sprint(load("file="+libFile)), "[example.com/m/lib]"; got != want {
+		t.Errorf("Load(file=lib/lib.go) = %s, want %s", got, want)
+	}
+
+	// The driver notifies the session of changes to the files of the
+	// packages it has reported.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(libFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case change := <-changes:
+		if got, want := fmt.Sprint(change.Packages), "[example.com/m/lib]"; got != want {
+			t.Errorf("changed packages = %s, want %s", got, want)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("no change notification")
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Load(&cfg, "./..."); err == nil {
+		t.Error("Load succeeded after Close")
+	}
+}
+
+func TestDriverSessionUnsupported(t *testing.T) {
+	testenv.NeedsExec(t)
+
+	fs, err := txtar.FS(txtar.Parse([]byte(`
+-- go.mod --
+module example.com/m
+
+go 1.20
+
+-- m.go --
+package m
+`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := packages.Config{
+		Dir: testfiles.CopyToTmp(t, fs),
+		Env: append(os.Environ(), drivertest.Env(t)...),
+	}
+	// A driver that implements only version 1 of the protocol replies to
+	// the session request with an ordinary response.
+	cfg.Env = append(cfg.Env, drivertest.NoSessionsEnv+"=1")
+	if _, err := packages.NewDriverSession(&cfg, nil); err == nil {
+		t.Fatal("NewDriverSession succeeded with a driver that does not support sessions")
+	} else if !strings.Contains(err.Error(), "does not support sessions") {
+		t.Errorf("NewDriverSession error = %v, want unsupported sessions", err)
+	}
+}
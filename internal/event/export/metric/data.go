@@ -67,6 +67,7 @@ type HistogramInt64Data struct {
 
 	groups [][]label.Label
 	key    *keys.Int64
+	seen   int64 // number of values, for sampling
 }
 
 // HistogramInt64Row holds the values for a single row of a HistogramInt64Data.
@@ -94,6 +95,7 @@ type HistogramFloat64Data struct {
 
 	groups [][]label.Label
 	key    *keys.Float64
+	seen   int64 // number of values, for sampling
 }
 
 // HistogramFloat64Row holds the values for a single row of a HistogramFloat64Data.
@@ -120,7 +122,11 @@ func labelListLess(a, b []label.Label) bool {
 	return fmt.Sprint(a) < fmt.Sprint(b)
 }
 
-func getGroup(lm label.Map, g *[][]label.Label, keys []label.Key) (int, bool) {
+// getGroup returns the index in *g of the group of label values of lm
+// for the specified keys, inserting it if it is not present, and
+// reports whether it was inserted. If the number of groups has reached
+// maxGroups (if positive), a new group is replaced by the overflow group.
+func getGroup(lm label.Map, g *[][]label.Label, keys []label.Key, maxGroups int) (int, bool) {
 	group := make([]label.Label, len(keys))
 	for i, key := range keys {
 		l := lm.Find(key)
@@ -129,13 +135,23 @@ func getGroup(lm label.Map, g *[][]label.Label, keys []label.Key) (int, bool) {
 		}
 	}
 	old := *g
-	index := sort.Search(len(old), func(i int) bool {
-		return !labelListLess(old[i], group)
-	})
-	if index < len(old) && labelListEqual(group, old[index]) {
+	search := func(group []label.Label) (int, bool) {
+		index := sort.Search(len(old), func(i int) bool {
+			return !labelListLess(old[i], group)
+		})
+		return index, index < len(old) && labelListEqual(group, old[index])
+	}
+	index, found := search(group)
+	if found {
 		// not a new group
 		return index, false
 	}
+	if maxGroups > 0 && len(old) >= maxGroups {
+		group = []label.Label{Overflow}
+		if index, found = search(group); found {
+			return index, false
+		}
+	}
 	*g = make([][]label.Label, len(old)+1)
 	copy(*g, old[:index])
 	copy((*g)[index+1:], old[index:])
@@ -147,7 +163,7 @@ func (data *Int64Data) Handle() string          { return data.Info.Name }
 func (data *Int64Data) Groups() [][]label.Label { return data.groups }
 
 func (data *Int64Data) modify(at time.Time, lm label.Map, f func(v int64) int64) Data {
-	index, insert := getGroup(lm, &data.groups, data.Info.Keys)
+	index, insert := getGroup(lm, &data.groups, data.Info.Keys, data.Info.MaxGroups)
 	old := data.Rows
 	if insert {
 		data.Rows = make([]int64, len(old)+1)
@@ -185,7 +201,7 @@ func (data *Float64Data) Handle() string          { return data.Info.Name }
 func (data *Float64Data) Groups() [][]label.Label { return data.groups }
 
 func (data *Float64Data) modify(at time.Time, lm label.Map, f func(v float64) float64) Data {
-	index, insert := getGroup(lm, &data.groups, data.Info.Keys)
+	index, insert := getGroup(lm, &data.groups, data.Info.Keys, data.Info.MaxGroups)
 	old := data.Rows
 	if insert {
 		data.Rows = make([]float64, len(old)+1)
@@ -217,7 +233,7 @@ func (data *HistogramInt64Data) Handle() string          { return data.Info.Name
 func (data *HistogramInt64Data) Groups() [][]label.Label { return data.groups }
 
 func (data *HistogramInt64Data) modify(at time.Time, lm label.Map, f func(v *HistogramInt64Row)) Data {
-	index, insert := getGroup(lm, &data.groups, data.Info.Keys)
+	index, insert := getGroup(lm, &data.groups, data.Info.Keys, data.Info.MaxGroups)
 	old := data.Rows
 	var v HistogramInt64Row
 	if insert {
@@ -240,19 +256,28 @@ func (data *HistogramInt64Data) modify(at time.Time, lm label.Map, f func(v *His
 }
 
 func (data *HistogramInt64Data) record(at time.Time, lm label.Map, l label.Label) Data {
+	weight := int64(1)
+	if sample := data.Info.Sample; sample > 1 {
+		data.seen++
+		if (data.seen-1)%sample != 0 {
+			frozen := *data // not sampled
+			return &frozen
+		}
+		weight = sample
+	}
 	return data.modify(at, lm, func(v *HistogramInt64Row) {
 		value := data.key.From(l)
-		v.Sum += value
+		v.Sum += value * weight
 		if v.Min > value || v.Count == 0 {
 			v.Min = value
 		}
 		if v.Max < value || v.Count == 0 {
 			v.Max = value
 		}
-		v.Count++
+		v.Count += weight
 		for i, b := range data.Info.Buckets {
 			if value <= b {
-				v.Values[i]++
+				v.Values[i] += weight
 			}
 		}
 	})
@@ -262,7 +287,7 @@ func (data *HistogramFloat64Data) Handle() string          { return data.Info.Na
 func (data *HistogramFloat64Data) Groups() [][]label.Label { return data.groups }
 
 func (data *HistogramFloat64Data) modify(at time.Time, lm label.Map, f func(v *HistogramFloat64Row)) Data {
-	index, insert := getGroup(lm, &data.groups, data.Info.Keys)
+	index, insert := getGroup(lm, &data.groups, data.Info.Keys, data.Info.MaxGroups)
 	old := data.Rows
 	var v HistogramFloat64Row
 	if insert {
@@ -285,19 +310,28 @@ func (data *HistogramFloat64Data) modify(at time.Time, lm label.Map, f func(v *H
 }
 
 func (data *HistogramFloat64Data) record(at time.Time, lm label.Map, l label.Label) Data {
+	weight := int64(1)
+	if sample := data.Info.Sample; sample > 1 {
+		data.seen++
+		if (data.seen-1)%sample != 0 {
+			frozen := *data // not sampled
+			return &frozen
+		}
+		weight = sample
+	}
 	return data.modify(at, lm, func(v *HistogramFloat64Row) {
 		value := data.key.From(l)
-		v.Sum += value
+		v.Sum += value * float64(weight)
 		if v.Min > value || v.Count == 0 {
 			v.Min = value
 		}
 		if v.Max < value || v.Count == 0 {
 			v.Max = value
 		}
-		v.Count++
+		v.Count += weight
 		for i, b := range data.Info.Buckets {
 			if value <= b {
-				v.Values[i]++
+				v.Values[i] += weight
 			}
 		}
 	})
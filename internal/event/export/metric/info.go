@@ -17,6 +17,9 @@ type Scalar struct {
 	Description string
 	// Keys is the set of labels that collectively describe rows of the metric.
 	Keys []label.Key
+	// MaxGroups, if positive, bounds the number of rows of the metric.
+	// See [Overflow].
+	MaxGroups int
 }
 
 // HistogramInt64 represents the construction information for an int64 histogram metric.
@@ -29,6 +32,14 @@ type HistogramInt64 struct {
 	Keys []label.Key
 	// Buckets holds the inclusive upper bound of each bucket in the histogram.
 	Buckets []int64
+	// MaxGroups, if positive, bounds the number of rows of the metric.
+	// See [Overflow].
+	MaxGroups int
+	// Sample, if greater than one, causes only one in every Sample
+	// values to be recorded, counting for Sample values. This reduces
+	// the cost of frequently recorded metrics, at the expense of the
+	// accuracy of the histogram and of its sum, minimum, and maximum.
+	Sample int64
 }
 
 // HistogramFloat64 represents the construction information for a float64 histogram metric.
@@ -41,8 +52,24 @@ type HistogramFloat64 struct {
 	Keys []label.Key
 	// Buckets holds the inclusive upper bound of each bucket in the histogram.
 	Buckets []float64
+	// MaxGroups, if positive, bounds the number of rows of the metric.
+	// See [Overflow].
+	MaxGroups int
+	// Sample, if greater than one, causes only one in every Sample
+	// values to be recorded, counting for Sample values. This reduces
+	// the cost of frequently recorded metrics, at the expense of the
+	// accuracy of the histogram and of its sum, minimum, and maximum.
+	Sample int64
 }
 
+// Overflow is the label of the row of a metric that aggregates the
+// values of all combinations of labels beyond the first MaxGroups,
+// so that labels with unbounded sets of values, such as the names of
+// RPC methods sent by a client, cannot cause the metric to grow
+// without limit. The group of the overflow row consists of this label
+// alone, so a metric has at most MaxGroups+1 rows.
+var Overflow = keys.NewBoolean("overflow", "Whether the row aggregates the label combinations beyond the maximum").Of(true)
+
 // Count creates a new metric based on the Scalar information that counts
 // the number of times the supplied int64 measure is set.
 // Metrics of this type will use Int64Data.
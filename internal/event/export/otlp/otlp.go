@@ -0,0 +1,381 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package otlp adds the ability to export traces to an OpenTelemetry
+// collector, using the JSON encoding of the OpenTelemetry protocol
+// (OTLP) over HTTP. Like package ocagent, it keeps the compile time
+// dependencies to zero: the collector has the exporters needed for
+// whatever system aggregates and displays the traces.
+//
+// Each span of the event system, such as that of an RPC or of the
+// type checking of a package, becomes an OTLP span, and each log or
+// label event within it becomes an event of the span.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/event/core"
+	"golang.org/x/tools/internal/event/export"
+	"golang.org/x/tools/internal/event/keys"
+	"golang.org/x/tools/internal/event/label"
+)
+
+// tracesPath is the path of the traces endpoint of a collector,
+// relative to its base address.
+const tracesPath = "/v1/traces"
+
+type Config struct {
+	// Address is the base URL of the collector, such as
+	// "http://localhost:4318", or "off" to disable export.
+	Address string
+
+	// TracesURL is the URL of the traces endpoint of the collector.
+	// By default it is Address + "/v1/traces".
+	TracesURL string
+
+	// Headers holds additional headers of each request to the
+	// collector, such as for authentication.
+	Headers http.Header
+
+	Service string        // the service name; by default, that of the executable
+	Host    string        // the host name; by default, that of the machine
+	Process uint32        // the process ID; by default, that of this process
+	Client  *http.Client  // by default, http.DefaultClient
+	Rate    time.Duration // the interval between exports; by default, 2s
+}
+
+// FromEnv returns the configuration specified by the standard
+// environment variables of OpenTelemetry exporters, or nil if they
+// specify no collector. The variables are:
+//
+//	OTEL_EXPORTER_OTLP_ENDPOINT         - base URL of the collector
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT  - URL of its traces endpoint
+//	OTEL_EXPORTER_OTLP_HEADERS          - headers, as key1=value1,key2=value2
+//	OTEL_EXPORTER_OTLP_TRACES_HEADERS   - headers of trace requests only
+//	OTEL_SERVICE_NAME                   - the service name
+//	OTEL_TRACES_EXPORTER                - "none" disables export
+func FromEnv(getenv func(string) string) *Config {
+	if getenv("OTEL_TRACES_EXPORTER") == "none" {
+		return nil
+	}
+	cfg := &Config{
+		Address:   getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		TracesURL: getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		Service:   getenv("OTEL_SERVICE_NAME"),
+	}
+	if cfg.Address == "" && cfg.TracesURL == "" {
+		return nil
+	}
+	for _, name := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_TRACES_HEADERS"} {
+		for _, kv := range strings.Split(getenv(name), ",") {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				if cfg.Headers == nil {
+					cfg.Headers = make(http.Header)
+				}
+				v = strings.TrimSpace(v)
+				if unescaped, err := url.PathUnescape(v); err == nil {
+					v = unescaped
+				}
+				cfg.Headers.Set(strings.TrimSpace(k), v)
+			}
+		}
+	}
+	return cfg
+}
+
+type Exporter struct {
+	mu     sync.Mutex
+	config Config
+	spans  []*export.Span
+}
+
+// Connect creates an exporter that uploads the spans of this process
+// to the collector specified by config. It returns nil if config is
+// nil or export is disabled.
+func Connect(config *Config) *Exporter {
+	if config == nil || config.Address == "off" || config.Address == "" && config.TracesURL == "" {
+		return nil
+	}
+	resolved := *config
+	if resolved.TracesURL == "" {
+		resolved.TracesURL = strings.TrimSuffix(resolved.Address, "/") + tracesPath
+	}
+	if resolved.Host == "" {
+		hostname, _ := os.Hostname()
+		resolved.Host = hostname
+	}
+	if resolved.Process == 0 {
+		resolved.Process = uint32(os.Getpid())
+	}
+	if resolved.Client == nil {
+		resolved.Client = http.DefaultClient
+	}
+	if resolved.Service == "" {
+		resolved.Service = filepath.Base(os.Args[0])
+	}
+	if resolved.Rate == 0 {
+		resolved.Rate = 2 * time.Second
+	}
+	exporter := &Exporter{config: resolved}
+	go func() {
+		for range time.Tick(exporter.config.Rate) {
+			exporter.Flush()
+		}
+	}()
+	return exporter
+}
+
+// ProcessEvent records each span when it ends, for export by the next
+// call to Flush. It must be preceded by the [export.Spans] exporter.
+func (e *Exporter) ProcessEvent(ctx context.Context, ev core.Event, lm label.Map) context.Context {
+	if event.IsEnd(ev) {
+		if span := export.GetSpan(ctx); span != nil {
+			e.mu.Lock()
+			e.spans = append(e.spans, span)
+			e.mu.Unlock()
+		}
+	}
+	return ctx
+}
+
+// Flush sends the spans that have ended since the previous call.
+func (e *Exporter) Flush() {
+	e.mu.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.mu.Unlock()
+	if len(spans) == 0 {
+		return
+	}
+
+	req := exportTraceServiceRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: []keyValue{
+				{Key: "service.name", Value: anyValue{StringValue: &e.config.Service}},
+				{Key: "host.name", Value: anyValue{StringValue: &e.config.Host}},
+				{Key: "process.pid", Value: anyValue{IntValue: strconv.FormatUint(uint64(e.config.Process), 10)}},
+			}},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "golang.org/x/tools/internal/event"},
+				Spans: make([]span, len(spans)),
+			}},
+		}},
+	}
+	for i, s := range spans {
+		req.ResourceSpans[0].ScopeSpans[0].Spans[i] = convertSpan(s)
+	}
+	e.send(&req)
+}
+
+func (e *Exporter) send(message any) {
+	blob, err := json.Marshal(message)
+	if err != nil {
+		errorInExport("otlp failed to marshal message: %v", err)
+		return
+	}
+	req, err := http.NewRequest("POST", e.config.TracesURL, bytes.NewReader(blob))
+	if err != nil {
+		errorInExport("otlp failed to build request for %v: %v", e.config.TracesURL, err)
+		return
+	}
+	for k, v := range e.config.Headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := e.config.Client.Do(req)
+	if err != nil {
+		errorInExport("otlp failed to send message: %v", err)
+		return
+	}
+	if res.Body != nil {
+		res.Body.Close()
+	}
+}
+
+func errorInExport(message string, args ...any) {
+	// This function is useful when debugging the exporter, but in general we
+	// want to just drop any export
+}
+
+// The types below are the subset of the JSON encoding of the OTLP
+// ExportTraceServiceRequest message that is needed to export spans.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+// As in the protobuf JSON mapping, 64-bit integers are encoded as
+// strings; unlike it, trace and span IDs are encoded in hex.
+
+type exportTraceServiceRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []spanEvent `json:"events,omitempty"`
+}
+
+// spanKindInternal is the SPAN_KIND_INTERNAL value of the Span.SpanKind enum.
+const spanKindInternal = 1
+
+type spanEvent struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    string   `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func convertTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func convertSpan(s *export.Span) span {
+	result := span{
+		TraceID:           s.ID.TraceID.String(),
+		SpanID:            s.ID.SpanID.String(),
+		Name:              s.Name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: convertTimestamp(s.Start().At()),
+		EndTimeUnixNano:   convertTimestamp(s.Finish().At()),
+		Attributes:        convertAttributes(s.Start(), 1), // skip the name
+	}
+	if s.ParentID.IsValid() {
+		result.ParentSpanID = s.ParentID.String()
+	}
+	for _, ev := range s.Events() {
+		result.Events = append(result.Events, convertEvent(ev))
+	}
+	return result
+}
+
+func convertEvent(ev core.Event) spanEvent {
+	name, index := eventName(ev)
+	return spanEvent{
+		TimeUnixNano: convertTimestamp(ev.At()),
+		Name:         name,
+		Attributes:   convertAttributes(ev, index),
+	}
+}
+
+// eventName returns the name of a log or label event, which is its
+// message, or lacking one its error, and the index of its first
+// label that is not part of the name.
+func eventName(ev core.Event) (string, int) {
+	l := ev.Label(0)
+	if l.Key() != keys.Msg {
+		return "label", 0
+	}
+	if msg := keys.Msg.From(l); msg != "" {
+		return msg, 1
+	}
+	l = ev.Label(1)
+	if l.Key() != keys.Err {
+		return "log", 1
+	}
+	if err := keys.Err.From(l); err != nil {
+		return err.Error(), 2
+	}
+	return "log", 2
+}
+
+func convertAttributes(list label.List, index int) []keyValue {
+	var attrs []keyValue
+	for ; list.Valid(index); index++ {
+		l := list.Label(index)
+		if !l.Valid() || l.Key() == keys.Label {
+			continue
+		}
+		attrs = append(attrs, keyValue{Key: l.Key().Name(), Value: convertAttribute(l)})
+	}
+	return attrs
+}
+
+func convertAttribute(l label.Label) anyValue {
+	str := func(s string) anyValue { return anyValue{StringValue: &s} }
+	switch key := l.Key().(type) {
+	case *keys.Int:
+		return anyValue{IntValue: strconv.FormatInt(int64(key.From(l)), 10)}
+	case *keys.Int8:
+		return anyValue{IntValue: strconv.FormatInt(int64(key.From(l)), 10)}
+	case *keys.Int16:
+		return anyValue{IntValue: strconv.FormatInt(int64(key.From(l)), 10)}
+	case *keys.Int32:
+		return anyValue{IntValue: strconv.FormatInt(int64(key.From(l)), 10)}
+	case *keys.Int64:
+		return anyValue{IntValue: strconv.FormatInt(key.From(l), 10)}
+	case *keys.UInt:
+		return anyValue{IntValue: strconv.FormatUint(uint64(key.From(l)), 10)}
+	case *keys.UInt8:
+		return anyValue{IntValue: strconv.FormatUint(uint64(key.From(l)), 10)}
+	case *keys.UInt16:
+		return anyValue{IntValue: strconv.FormatUint(uint64(key.From(l)), 10)}
+	case *keys.UInt32:
+		return anyValue{IntValue: strconv.FormatUint(uint64(key.From(l)), 10)}
+	case *keys.UInt64:
+		return anyValue{IntValue: strconv.FormatUint(key.From(l), 10)}
+	case *keys.Float32:
+		f := float64(key.From(l))
+		return anyValue{DoubleValue: &f}
+	case *keys.Float64:
+		f := key.From(l)
+		return anyValue{DoubleValue: &f}
+	case *keys.Boolean:
+		b := key.From(l)
+		return anyValue{BoolValue: &b}
+	case *keys.String:
+		return str(key.From(l))
+	case *keys.Error:
+		return str(key.From(l).Error())
+	case *keys.Value:
+		return str(fmt.Sprint(key.From(l)))
+	default:
+		return str(fmt.Sprintf("%T", key))
+	}
+}
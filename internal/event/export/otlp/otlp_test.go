@@ -0,0 +1,217 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package otlp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/event/core"
+	"golang.org/x/tools/internal/event/export"
+	"golang.org/x/tools/internal/event/export/otlp"
+	"golang.org/x/tools/internal/event/keys"
+	"golang.org/x/tools/internal/event/label"
+)
+
+var (
+	keyDB    = keys.NewString("db", "the database name")
+	keyCount = keys.NewInt("count", "a count")
+	keyRatio = keys.NewFloat64("ratio", "a ratio")
+	keyStale = keys.NewBoolean("stale", "a boolean")
+)
+
+func TestTrace(t *testing.T) {
+	var sent fakeSender
+	exporter := otlp.Connect(&otlp.Config{
+		Address: "http://collector:4318/",
+		Headers: http.Header{"Authorization": {"Bearer secret"}},
+		Service: "otlp-tests",
+		Host:    "tester",
+		Process: 1,
+		Client:  &http.Client{Transport: &sent},
+		Rate:    time.Hour,
+	})
+	e := exporter.ProcessEvent
+	e = spanFixer(e)
+	e = export.Spans(e)
+	e = export.Labels(e)
+	e = timeFixer(e)
+	event.SetExporter(e)
+	defer event.SetExporter(nil)
+
+	ctx, done := event.Start(context.Background(), "parent", keyDB.Of("godb"))
+	_, done2 := event.Start(ctx, "child")
+	done2()
+	event.Log(ctx, "cache miss", keyCount.Of(3), keyRatio.Of(0.5), keyStale.Of(true))
+	event.Error(ctx, "", errors.New("no network connectivity"))
+	done()
+	exporter.Flush()
+
+	const want = `{"resourceSpans":[{
+	"resource":{"attributes":[
+		{"key":"service.name","value":{"stringValue":"otlp-tests"}},
+		{"key":"host.name","value":{"stringValue":"tester"}},
+		{"key":"process.pid","value":{"intValue":"1"}}
+	]},
+	"scopeSpans":[{
+		"scope":{"name":"golang.org/x/tools/internal/event"},
+		"spans":[{
+			"traceId":"01000000000000000000000000000000",
+			"spanId":"0200000000000000",
+			"parentSpanId":"0100000000000000",
+			"name":"child",
+			"kind":1,
+			"startTimeUnixNano":"30000000000",
+			"endTimeUnixNano":"50000000000"
+		},{
+			"traceId":"01000000000000000000000000000000",
+			"spanId":"0100000000000000",
+			"name":"parent",
+			"kind":1,
+			"startTimeUnixNano":"30000000000",
+			"endTimeUnixNano":"50000000000",
+			"attributes":[{"key":"db","value":{"stringValue":"godb"}}],
+			"events":[{
+				"timeUnixNano":"40000000000",
+				"name":"cache miss",
+				"attributes":[
+					{"key":"count","value":{"intValue":"3"}},
+					{"key":"ratio","value":{"doubleValue":0.5}},
+					{"key":"stale","value":{"boolValue":true}}
+				]
+			},{
+				"timeUnixNano":"40000000000",
+				"name":"no network connectivity"
+			}]
+		}]
+	}]
+}]}`
+	sent.mu.Lock()
+	defer sent.mu.Unlock()
+	if sent.url != "http://collector:4318/v1/traces" {
+		t.Errorf("sent to %q, want http://collector:4318/v1/traces", sent.url)
+	}
+	if got := sent.header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization header = %q, want %q", got, "Bearer secret")
+	}
+	checkJSON(t, sent.body, []byte(want))
+}
+
+func TestFromEnv(t *testing.T) {
+	env := map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+		"OTEL_EXPORTER_OTLP_HEADERS":  "api-key=a%20b, x-tenant=gopls",
+		"OTEL_SERVICE_NAME":           "shared-gopls",
+	}
+	cfg := otlp.FromEnv(func(k string) string { return env[k] })
+	if cfg == nil {
+		t.Fatal("FromEnv returned nil")
+	}
+	if cfg.Address != "http://collector:4318" || cfg.Service != "shared-gopls" {
+		t.Errorf("FromEnv: got %+v", cfg)
+	}
+	if got := cfg.Headers.Get("Api-Key"); got != "a b" {
+		t.Errorf("api-key header = %q, want %q", got, "a b")
+	}
+	if got := cfg.Headers.Get("X-Tenant"); got != "gopls" {
+		t.Errorf("x-tenant header = %q, want %q", got, "gopls")
+	}
+
+	env["OTEL_TRACES_EXPORTER"] = "none"
+	if cfg := otlp.FromEnv(func(k string) string { return env[k] }); cfg != nil {
+		t.Errorf("FromEnv with OTEL_TRACES_EXPORTER=none: got %+v, want nil", cfg)
+	}
+	if cfg := otlp.FromEnv(func(string) string { return "" }); cfg != nil {
+		t.Errorf("FromEnv with empty environment: got %+v, want nil", cfg)
+	}
+}
+
+// timeFixer sets the times of start, end, and other events to 30s,
+// 50s, and 40s after the epoch.
+func timeFixer(output event.Exporter) event.Exporter {
+	start := time.Unix(30, 0)
+	at := time.Unix(40, 0)
+	end := time.Unix(50, 0)
+	return func(ctx context.Context, ev core.Event, lm label.Map) context.Context {
+		switch {
+		case event.IsStart(ev):
+			ev = core.CloneEvent(ev, start)
+		case event.IsEnd(ev):
+			ev = core.CloneEvent(ev, end)
+		default:
+			ev = core.CloneEvent(ev, at)
+		}
+		return output(ctx, ev, lm)
+	}
+}
+
+// spanFixer replaces the random trace and span IDs by sequential ones.
+func spanFixer(output event.Exporter) event.Exporter {
+	var nextSpan byte
+	return func(ctx context.Context, ev core.Event, lm label.Map) context.Context {
+		if event.IsStart(ev) {
+			span := export.GetSpan(ctx)
+			nextSpan++
+			span.ID = export.SpanContext{
+				TraceID: export.TraceID{1},
+				SpanID:  export.SpanID{nextSpan},
+			}
+			if span.ParentID.IsValid() {
+				span.ParentID = export.SpanID{1}
+			}
+		}
+		return output(ctx, ev, lm)
+	}
+}
+
+func checkJSON(t *testing.T, got, want []byte) {
+	// compare the compact form, to allow for formatting differences
+	g := &bytes.Buffer{}
+	if err := json.Compact(g, got); err != nil {
+		t.Fatal(err)
+	}
+	w := &bytes.Buffer{}
+	if err := json.Compact(w, want); err != nil {
+		t.Fatal(err)
+	}
+	if g.String() != w.String() {
+		t.Fatalf("Got:\n%s\nWant:\n%s", g, w)
+	}
+}
+
+// fakeSender records the last request sent to the collector.
+type fakeSender struct {
+	mu     sync.Mutex
+	url    string
+	header http.Header
+	body   []byte
+}
+
+func (s *fakeSender) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.url, s.header, s.body = req.URL.String(), req.Header, data
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: 200,
+		Proto:      "HTTP/1.0",
+		ProtoMajor: 1,
+		ProtoMinor: 0,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
@@ -8,13 +8,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 
 	"golang.org/x/tools/internal/event"
 	"golang.org/x/tools/internal/event/core"
 	"golang.org/x/tools/internal/event/export/metric"
+	"golang.org/x/tools/internal/event/keys"
 	"golang.org/x/tools/internal/event/label"
 )
 
@@ -54,7 +57,7 @@ func (e *Exporter) ProcessEvent(ctx context.Context, ev core.Event, lm label.Map
 	return ctx
 }
 
-func (e *Exporter) header(w http.ResponseWriter, name, description string, isGauge, isHistogram bool) {
+func (e *Exporter) header(w io.Writer, name, description string, isGauge, isHistogram bool) {
 	kind := "counter"
 	if isGauge {
 		kind = "gauge"
@@ -62,14 +65,22 @@ func (e *Exporter) header(w http.ResponseWriter, name, description string, isGau
 	if isHistogram {
 		kind = "histogram"
 	}
-	fmt.Fprintf(w, "# HELP %s %s\n", name, description)
+	fmt.Fprintf(w, "# HELP %s %s\n", name, helpEscaper.Replace(description))
 	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
 }
 
-func (e *Exporter) row(w http.ResponseWriter, name string, group []label.Label, extra string, value interface{}) {
+func (e *Exporter) row(w io.Writer, name string, group []label.Label, extra string, value interface{}) {
 	fmt.Fprint(w, name)
 	buf := &bytes.Buffer{}
-	fmt.Fprint(buf, group)
+	for _, l := range group {
+		if !l.Valid() {
+			continue // no value for this key
+		}
+		if buf.Len() > 0 {
+			fmt.Fprint(buf, ",")
+		}
+		fmt.Fprintf(buf, "%s=\"%s\"", l.Key().Name(), escaper.Replace(labelValue(l)))
+	}
 	if extra != "" {
 		if buf.Len() > 0 {
 			fmt.Fprint(buf, ",")
@@ -84,7 +95,31 @@ func (e *Exporter) row(w http.ResponseWriter, name string, group []label.Label,
 	fmt.Fprintf(w, " %v\n", value)
 }
 
+// labelValue returns the value of a label as a string.
+func labelValue(l label.Label) string {
+	if key, ok := l.Key().(*keys.String); ok {
+		return key.From(l) // unquoted
+	}
+	var buf bytes.Buffer
+	l.Key().Format(&buf, nil, l)
+	return buf.String()
+}
+
+// escaper and helpEscaper escape label values and help text
+// in the Prometheus text format.
+var (
+	escaper     = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// Serve serves the metrics in the Prometheus text exposition format.
 func (e *Exporter) Serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteText(w)
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format.
+func (e *Exporter) WriteText(w io.Writer) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	for _, data := range e.metrics {
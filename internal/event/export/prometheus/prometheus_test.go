@@ -0,0 +1,75 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prometheus_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/event/export"
+	"golang.org/x/tools/internal/event/export/metric"
+	"golang.org/x/tools/internal/event/export/prometheus"
+	"golang.org/x/tools/internal/event/keys"
+	"golang.org/x/tools/internal/event/label"
+)
+
+var (
+	keyMethod = keys.NewString("method", "")
+	keyCalls  = keys.NewInt64("calls", "")
+	keySize   = keys.NewInt64("size", "")
+
+	calls = metric.Scalar{
+		Name:        "calls",
+		Description: "Count of \"calls\"\nby method.",
+		Keys:        []label.Key{keyMethod},
+		MaxGroups:   2,
+	}
+	sizes = metric.HistogramInt64{
+		Name:        "sizes",
+		Description: "Distribution of sizes.",
+		Keys:        []label.Key{keyMethod},
+		Buckets:     []int64{10, 100},
+		Sample:      2,
+	}
+)
+
+func TestExporter(t *testing.T) {
+	exporter := prometheus.New()
+	metrics := metric.Config{}
+	calls.Count(&metrics, keyCalls)
+	sizes.Record(&metrics, keySize)
+	event.SetExporter(export.Labels(metrics.Exporter(exporter.ProcessEvent)))
+	defer event.SetExporter(nil)
+
+	ctx := context.Background()
+	for _, method := range []string{"a", `b"\`, "c", "d", "a"} {
+		event.Metric(event.Label(ctx, keyMethod.Of(method)), keyCalls.Of(1))
+	}
+	for i := range 5 {
+		// Only the 1st, 3rd, and 5th values are sampled.
+		event.Metric(event.Label(ctx, keyMethod.Of("m")), keySize.Of(int64(i*40)))
+	}
+
+	var buf bytes.Buffer
+	exporter.WriteText(&buf)
+	const want = `# HELP calls Count of "calls"\nby method.
+# TYPE calls counter
+calls{method="a"} 2
+calls{method="b\"\\"} 1
+calls{overflow="true"} 2
+# HELP sizes Distribution of sizes.
+# TYPE sizes histogram
+sizes_bucket{method="m",le="10"} 2
+sizes_bucket{method="m",le="100"} 4
+sizes_bucket{method="m",le="+Inf"} 6
+sizes_count{method="m"} 6
+sizes_sum{method="m"} 480
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
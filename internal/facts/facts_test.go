@@ -245,6 +245,24 @@ func TestEncodeDecode(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "typeparam-constraint",
+			// c -> b -> a
+			// c refers to a.I only through the constraint of
+			// the type parameter of b.G, so the facts of a are
+			// available in c only if importMap walks constraints.
+			files: map[string]string{
+				"a/a.go": `package a; type I interface{ ~int }`,
+				"b/b.go": `package b; import "a"; type G[T a.I] struct{}`,
+				"c/c.go": `package c; import "b"; var V b.G[int]`,
+			},
+			plookups: []pkgLookups{
+				{"a", []lookup{}},
+				{"b", []lookup{}},
+				// fake objexpr for the constraint of b.G's type parameter (see customFind hack)
+				{"c", []lookup{{"c.V->b.G->T->a.I", "myFact(a.I)"}}},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -383,6 +401,11 @@ var customFind = map[string]func(p *types.Package) types.Object{
 		zZ1 := aliases.Rhs(aT1).(*types.Alias)
 		return zZ1.Obj()
 	},
+	"c.V->b.G->T->a.I": func(p *types.Package) types.Object {
+		cV := p.Scope().Lookup("V")
+		bG := cV.Type().(*types.Named).Origin()
+		return bG.TypeParams().At(0).Constraint().(*types.Named).Obj()
+	},
 }
 
 func find(p *types.Package, expr string) types.Object {
@@ -50,6 +50,14 @@ func importMap(imports []*types.Package) map[string]*types.Package {
 		switch T := T.(type) {
 		case *types.Basic:
 			// nop
+		case *types.TypeParam:
+			// (This case must precede NamedOrAlias,
+			// which *types.TypeParam also satisfies.)
+			if !typs[T] {
+				typs[T] = true
+				addObj(T.Obj())
+				addType(T.Constraint())
+			}
 		case typesinternal.NamedOrAlias: // *types.{Named,Alias}
 			// Add the type arguments if this is an instance.
 			if targs := typesinternal.TypeArgs(T); targs.Len() > 0 {
@@ -124,12 +132,6 @@ func importMap(imports []*types.Package) map[string]*types.Package {
 			for i := 0; i < T.Len(); i++ {
 				addType(T.Term(i).Type())
 			}
-		case *types.TypeParam:
-			if !typs[T] {
-				typs[T] = true
-				addObj(T.Obj())
-				addType(T.Constraint())
-			}
 		}
 	}
 
@@ -0,0 +1,89 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The iconvert command converts the export data of a package to the
+// specified version of the indexed export data format.
+//
+// Usage:
+//
+//	iconvert [-version=n] [-o output] file pkgpath
+//
+// The input file may contain export data in the indexed or unified
+// format, or be an object or archive file produced by the compiler, from
+// which the export data is extracted. The output is written to the
+// -o file, or to the standard output.
+//
+// It is provided for testing that tools can read the export data
+// produced by older (or newer) toolchains.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/internal/gcimporter"
+)
+
+var (
+	versionFlag = flag.Int("version", gcimporter.IExportVersions[len(gcimporter.IExportVersions)-1], "indexed export data format version of the output")
+	outputFlag  = flag.String("o", "", "output file (default standard output)")
+)
+
+func main() {
+	log.SetPrefix("iconvert: ")
+	log.SetFlags(0)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: iconvert [-version=n] [-o output] file pkgpath\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "supported versions: %v\n", gcimporter.IExportVersions)
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	filename, path := flag.Arg(0), flag.Arg(1)
+
+	data, err := readExportData(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := gcimporter.ConvertExportData(data, path, *versionFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *outputFlag != "" {
+		err = os.WriteFile(*outputFlag, out, 0666)
+	} else {
+		_, err = os.Stdout.Write(out)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readExportData returns the export data in the named file, which
+// may be an object or archive file.
+func readExportData(filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	if prefix, err := br.Peek(1); err == nil && (prefix[0] == 'i' || prefix[0] == 'u') {
+		return io.ReadAll(br)
+	}
+	r, err := gcexportdata.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading export data from %s: %v", filename, err)
+	}
+	return io.ReadAll(r)
+}
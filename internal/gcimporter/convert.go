@@ -0,0 +1,99 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gcimporter
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+)
+
+// IExportVersions lists the versions of the indexed export data
+// format that [IExportDataVersion] can write, from oldest to newest.
+var IExportVersions = []int{iexportVersionGo1_11, iexportVersionPosCol, iexportVersionGo1_18}
+
+// IExportDataVersion is like [IExportData], but writes the specified
+// version of the indexed format, allowing tests to produce the export
+// data of older toolchains. Versions before go1.18 cannot represent
+// generic types and functions: their export data would be rejected by
+// the importer.
+func IExportDataVersion(out io.Writer, fset *token.FileSet, pkg *types.Package, version int) error {
+	if version < iexportVersionGo1_11 || version > iexportVersionCurrent {
+		return fmt.Errorf("unsupported iexport format version %d", version)
+	}
+	const bundle, shallow = false, false
+	return iexportCommon(out, fset, bundle, shallow, version, []*types.Package{pkg})
+}
+
+// ConvertExportData decodes the export data of the package with the
+// specified path, in the indexed ('i') or unified ('u') format, as
+// returned by [gcexportdata.NewReader], and encodes it in the
+// specified version of the indexed format, with its 'i' prefix.
+//
+// It reports an error if the result cannot be decoded, for example
+// because the package uses generics, which the version predates.
+//
+// [gcexportdata.NewReader]: https://pkg.go.dev/golang.org/x/tools/go/gcexportdata#NewReader
+func ConvertExportData(data []byte, path string, version int) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty export data for %q", path)
+	}
+	var (
+		fset    = token.NewFileSet()
+		imports = make(map[string]*types.Package)
+		pkg     *types.Package
+		err     error
+	)
+	switch data[0] {
+	case 'i':
+		_, pkg, err = IImportData(fset, imports, data[1:], path)
+	case 'u':
+		_, pkg, err = UImportData(fset, imports, data[1:], path)
+	default:
+		return nil, fmt.Errorf("unexpected export data format %q for %q", data[0], path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if version < iexportVersionGo1_18 {
+		if name := genericDecl(pkg); name != "" {
+			return nil, fmt.Errorf("cannot convert %q to version %d: %s is generic", path, version, name)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('i')
+	if err := IExportDataVersion(&out, fset, pkg, version); err != nil {
+		return nil, err
+	}
+
+	// Check that the result can be decoded.
+	if _, _, err := IImportData(token.NewFileSet(), make(map[string]*types.Package), out.Bytes()[1:], path); err != nil {
+		return nil, fmt.Errorf("converting %q to version %d: %v", path, version, err)
+	}
+	return out.Bytes(), nil
+}
+
+// genericDecl returns the name of a generic type or function declared
+// by pkg, if any.
+func genericDecl(pkg *types.Package) string {
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		switch obj := scope.Lookup(name).(type) {
+		case *types.Func:
+			if obj.Type().(*types.Signature).TypeParams().Len() > 0 {
+				return name
+			}
+		case *types.TypeName:
+			if named, ok := types.Unalias(obj.Type()).(*types.Named); ok && named.TypeParams().Len() > 0 {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gcimporter_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/internal/gcimporter"
+	"golang.org/x/tools/internal/testenv"
+)
+
+// TestConvertExportData converts the compiler's export data for a
+// few packages to each version of the indexed format.
+func TestConvertExportData(t *testing.T) {
+	testenv.NeedsGoBuild(t)
+
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedExportFile}
+	pkgs, err := packages.Load(cfg, "strconv", "slices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		data := readExportFile(t, pkg.ExportFile)
+		for _, version := range gcimporter.IExportVersions {
+			out, err := gcimporter.ConvertExportData(data, pkg.PkgPath, version)
+			if pkg.PkgPath == "slices" && version < gcimporter.IExportVersion {
+				if err == nil || !strings.Contains(err.Error(), "is generic") {
+					t.Errorf("converting %s to version %d: got error %v, want generic error", pkg.PkgPath, version, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("converting %s to version %d: %v", pkg.PkgPath, version, err)
+				continue
+			}
+			if out[0] != 'i' || out[1] != byte(version) {
+				t.Errorf("converting %s to version %d: got header %q", pkg.PkgPath, version, out[:2])
+			}
+			fset := token.NewFileSet()
+			tpkg, err := gcexportdata.Read(bytes.NewReader(out), fset, make(map[string]*types.Package), pkg.PkgPath)
+			if err != nil {
+				t.Errorf("reading %s converted to version %d: %v", pkg.PkgPath, version, err)
+			} else if tpkg.Scope().Lookup("Itoa") == nil && tpkg.Scope().Lookup("Index") == nil {
+				t.Errorf("%s converted to version %d lacks expected objects: %v", pkg.PkgPath, version, tpkg.Scope().Names())
+			}
+		}
+	}
+}
+
+func readExportFile(t *testing.T, filename string) []byte {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gcexportdata.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// FuzzConvertExportData checks that an arbitrary well-typed package
+// survives conversion of its export data to each version of the
+// indexed format.
+//
+// $ go test -fuzz=FuzzConvertExportData ./internal/gcimporter
+func FuzzConvertExportData(f *testing.F) {
+	f.Add("package p; const C = 1 << 70; var V, W = 1.5, 'x'")
+	f.Add("package p; type T struct{ x int; T2 }; type T2 interface{ M(...int) (T, error) }")
+	f.Add("package p; func F(ch <-chan [4]map[string]*T) {}; type T = struct{ f func() }")
+	f.Add("package p; type List[E any] struct{ next *List[E]; val E }; func Map[S ~[]E, E comparable](S) {}")
+	f.Add("package p; type A[P any] = []P; var _ A[int]")
+	f.Fuzz(func(t *testing.T, src string) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "p.go", src, 0)
+		if err != nil {
+			return
+		}
+		conf := types.Config{Error: func(error) {}}
+		pkg, err := conf.Check("p", fset, []*ast.File{file}, nil)
+		if err != nil {
+			return // ill-typed packages are not the concern of this test
+		}
+
+		var data bytes.Buffer
+		data.WriteByte('i')
+		if err := gcimporter.IExportData(&data, fset, pkg); err != nil {
+			t.Fatalf("IExportData: %v", err)
+		}
+		for _, version := range gcimporter.IExportVersions {
+			out, err := gcimporter.ConvertExportData(data.Bytes(), "p", version)
+			if err != nil {
+				if version < gcimporter.IExportVersion {
+					continue // may be generic
+				}
+				t.Fatalf("converting to version %d: %v", version, err)
+			}
+			fset2 := token.NewFileSet()
+			_, pkg2, err := gcimporter.IImportData(fset2, make(map[string]*types.Package), out[1:], "p")
+			if err != nil {
+				t.Fatalf("importing version %d: %v", version, err)
+			}
+			testPkg(t, fset, version, pkg, fset2, pkg2)
+		}
+	})
+}
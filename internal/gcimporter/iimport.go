@@ -324,6 +324,17 @@ func iimportCommon(fset *token.FileSet, getPackages GetPackagesFunc, data []byte
 			continue
 		}
 
+		// Decode all the package-level objects eagerly.
+		//
+		// It would save memory to decode each object on first use,
+		// as most objects of a dependency are never referenced by
+		// the packages that import it; the compiler's importer
+		// does this. But go/types provides no public means to
+		// insert an object into a Scope lazily (Scope._InsertLazy
+		// is unexported), and a package whose scope lacks some of
+		// its objects would cause spurious "undefined" errors when
+		// type-checking its importers. Shallow export data already
+		// limits decoding to the objects of a single package.
 		names := make([]string, 0, len(p.pkgIndex[pkg]))
 		for name := range p.pkgIndex[pkg] {
 			names = append(names, name)
@@ -0,0 +1,318 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gonew creates a new Go module by copying a template module.
+// It is the implementation of the gonew command, and is also used
+// by gopls.
+package gonew
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/internal/edit"
+)
+
+// A Config describes a new module to be created from a template module.
+type Config struct {
+	// Template is the path of the template module, optionally
+	// followed by @version. The default version is "latest".
+	Template string
+
+	// Module is the path of the new module.
+	// If empty, the template's module path is kept.
+	Module string
+
+	// Dir is the directory in which to create the new module.
+	// It must not exist or must be an empty directory.
+	// If empty, it is ./elem, where elem is the final path
+	// element of the new module path.
+	Dir string
+
+	// Vars maps the names of template parameters to their values.
+	// Each occurrence of {{gonew.name}} in the names and contents
+	// of the template's files is replaced by the value of the named
+	// parameter. Occurrences of other names are left unchanged.
+	//
+	// Parameters declared by the template's manifest (see [Manifest])
+	// that have no value in Vars are given a value by Prompt, or else
+	// by their default.
+	Vars map[string]string
+
+	// Prompt, if non-nil, is called to obtain the value of each
+	// parameter declared by the template's manifest that has no value
+	// in Vars. If it returns the empty string, the default is used.
+	Prompt func(v *Var) (string, error)
+
+	// Steps are the names of the post-processing steps declared by the
+	// template's manifest to run in the new module's directory after
+	// it has been created. The name "all" selects all steps.
+	Steps []string
+
+	// Hooks are commands, such as {"go", "mod", "tidy"}, to run in
+	// order in the new module's directory after it has been created,
+	// and after any Steps.
+	Hooks [][]string
+
+	// Env is the environment of the go command and of hooks.
+	// If nil, the environment of the current process is used.
+	Env []string
+}
+
+// Create creates a new module as described by cfg, and returns the
+// directory in which it was created.
+func Create(ctx context.Context, cfg *Config) (string, error) {
+	srcMod := cfg.Template
+	srcModVers := srcMod
+	if !strings.Contains(srcModVers, "@") {
+		srcModVers += "@latest"
+	}
+	srcMod, _, _ = strings.Cut(srcMod, "@")
+	if err := module.CheckPath(srcMod); err != nil {
+		return "", fmt.Errorf("invalid source module name: %v", err)
+	}
+
+	dstMod := srcMod
+	if cfg.Module != "" {
+		dstMod = cfg.Module
+		if err := module.CheckPath(dstMod); err != nil {
+			return "", fmt.Errorf("invalid destination module name: %v", err)
+		}
+	}
+
+	dir := cfg.Dir
+	if dir == "" {
+		dir = "." + string(filepath.Separator) + path.Base(dstMod)
+	}
+
+	// Dir must not exist or must be an empty directory.
+	de, err := os.ReadDir(dir)
+	if err == nil && len(de) > 0 {
+		return "", fmt.Errorf("target directory %s exists and is non-empty", dir)
+	}
+	needMkdir := err != nil
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "mod", "download", "-json", srcModVers)
+	cmd.Env = cfg.Env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go mod download -json %s: %v\n%s%s", srcModVers, err, stderr.Bytes(), stdout.Bytes())
+	}
+
+	var info struct {
+		Dir string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return "", fmt.Errorf("go mod download -json %s: invalid JSON output: %v\n%s%s", srcMod, err, stderr.Bytes(), stdout.Bytes())
+	}
+
+	manifest, err := readManifest(info.Dir)
+	if err != nil {
+		return "", err
+	}
+	vars, err := manifest.resolveVars(cfg)
+	if err != nil {
+		return "", err
+	}
+	steps, err := manifest.selectSteps(cfg.Steps)
+	if err != nil {
+		return "", err
+	}
+
+	if needMkdir {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return "", err
+		}
+	}
+
+	// Copy from module cache into new directory, making edits as needed.
+	err = filepath.WalkDir(info.Dir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(info.Dir, src)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestFile {
+			return nil // not part of the new module
+		}
+		dst := filepath.Join(dir, expand(rel, vars))
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0777)
+		}
+
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		if len(vars) > 0 {
+			data = []byte(expand(string(data), vars))
+		}
+
+		isRoot := !strings.Contains(rel, string(filepath.Separator))
+		if strings.HasSuffix(rel, ".go") {
+			data, err = fixGo(data, rel, srcMod, dstMod, isRoot)
+			if err != nil {
+				return err
+			}
+		}
+		if rel == "go.mod" {
+			data, err = fixGoMod(data, srcMod, dstMod)
+			if err != nil {
+				return err
+			}
+		}
+
+		return os.WriteFile(dst, data, 0666)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, step := range steps {
+		run := make([]string, len(step.Run))
+		for i, arg := range step.Run {
+			run[i] = expand(arg, vars)
+		}
+		if err := runCommand(ctx, dir, cfg.Env, run); err != nil {
+			return "", fmt.Errorf("step %s: %v", step.Name, err)
+		}
+	}
+	for _, hook := range cfg.Hooks {
+		if len(hook) == 0 {
+			continue
+		}
+		if err := runCommand(ctx, dir, cfg.Env, hook); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// runCommand runs the command args in directory dir.
+func runCommand(ctx context.Context, dir string, env, args []string) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Env = env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return nil
+}
+
+// expand replaces each occurrence of {{gonew.name}} in s by the value
+// of the named parameter in vars, if defined.
+func expand(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "{{gonew.") {
+		return s
+	}
+	var buf strings.Builder
+	for {
+		i := strings.Index(s, "{{gonew.")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(s[i:], "}}")
+		if j < 0 {
+			break
+		}
+		j += i
+		name := s[i+len("{{gonew.") : j]
+		if value, ok := vars[name]; ok {
+			buf.WriteString(s[:i])
+			buf.WriteString(value)
+		} else {
+			buf.WriteString(s[:j+len("}}")])
+		}
+		s = s[j+len("}}"):]
+	}
+	buf.WriteString(s)
+	return buf.String()
+}
+
+// fixGo rewrites the Go source in data to replace srcMod with dstMod.
+// isRoot indicates whether the file is in the root directory of the module,
+// in which case we also update the package name.
+func fixGo(data []byte, file string, srcMod, dstMod string, isRoot bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, data, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("parsing source module:\n%s", err)
+	}
+
+	buf := edit.NewBuffer(data)
+	at := func(p token.Pos) int {
+		return fset.File(p).Offset(p)
+	}
+
+	srcName := path.Base(srcMod)
+	dstName := path.Base(dstMod)
+	if isRoot {
+		if name := f.Name.Name; name == srcName || name == srcName+"_test" {
+			dname := dstName + strings.TrimPrefix(name, srcName)
+			if !token.IsIdentifier(dname) {
+				return nil, fmt.Errorf("%s: cannot rename package %s to package %s: invalid package name", file, name, dname)
+			}
+			buf.Replace(at(f.Name.Pos()), at(f.Name.End()), dname)
+		}
+	}
+
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if path == srcMod {
+			if srcName != dstName && spec.Name == nil {
+				// Add package rename because source code uses original name.
+				// The renaming looks strange, but template authors are unlikely to
+				// create a template where the root package is imported by packages
+				// in subdirectories, and the renaming at least keeps the code working.
+				// A more sophisticated approach would be to rename the uses of
+				// the package identifier in the file too, but then you have to worry about
+				// name collisions, and given how unlikely this is, it doesn't seem worth
+				// trying to clean up the file that way.
+				buf.Insert(at(spec.Path.Pos()), srcName+" ")
+			}
+			// Change import path to dstMod
+			buf.Replace(at(spec.Path.Pos()), at(spec.Path.End()), strconv.Quote(dstMod))
+		}
+		if strings.HasPrefix(path, srcMod+"/") {
+			// Change import path to begin with dstMod
+			buf.Replace(at(spec.Path.Pos()), at(spec.Path.End()), strconv.Quote(strings.Replace(path, srcMod, dstMod, 1)))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// fixGoMod rewrites the go.mod content in data to replace srcMod with dstMod
+// in the module path.
+func fixGoMod(data []byte, srcMod, dstMod string) ([]byte, error) {
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing source module:\n%s", err)
+	}
+	f.AddModuleStmt(dstMod)
+	new, err := f.Format()
+	if err != nil {
+		return data, nil
+	}
+	return new, nil
+}
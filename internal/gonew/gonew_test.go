@@ -0,0 +1,158 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gonew
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/internal/proxydir"
+	"golang.org/x/tools/internal/testenv"
+)
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"name": "demo", "year": "2025"}
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"no parameters", "no parameters"},
+		{"hello {{gonew.name}}", "hello demo"},
+		{"{{gonew.name}}-{{gonew.year}}", "demo-2025"},
+		{"{{gonew.other}} {{gonew.name}}", "{{gonew.other}} demo"},
+		{"{{gonew.name", "{{gonew.name"},
+		{"{{.name}}", "{{.name}}"},
+	} {
+		if got := expand(test.in, vars); got != test.want {
+			t.Errorf("expand(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	testenv.NeedsTool(t, "go")
+
+	tmp := t.TempDir()
+	proxy := filepath.Join(tmp, "proxy")
+	if err := proxydir.WriteModuleVersion(proxy, "example.com/tmpl", "v1.0.0", map[string][]byte{
+		"go.mod":                     []byte("module example.com/tmpl\n\ngo 1.20\n"),
+		"tmpl.go":                    []byte("package tmpl\n\n// {{gonew.desc}}\nconst Name = \"{{gonew.name}}\"\n"),
+		"cmd/{{gonew.name}}/main.go": []byte("package main\n\nimport \"example.com/tmpl\"\n\nfunc main() { println(tmpl.Name) }\n"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(tmp, "out")
+	got, err := Create(context.Background(), &Config{
+		Template: "example.com/tmpl@v1.0.0",
+		Module:   "my.com/demo",
+		Dir:      dir,
+		Vars:     map[string]string{"name": "demo", "desc": "A demo."},
+		Hooks:    [][]string{{"go", "mod", "edit", "-go=1.21"}},
+		Env: append(os.Environ(),
+			"GOPROXY="+proxydir.ToURL(proxy),
+			"GOSUMDB=off",
+			"GOFLAGS=-modcacherw",
+			"GOMODCACHE="+filepath.Join(tmp, "modcache")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != dir {
+		t.Errorf("Create returned directory %s, want %s", got, dir)
+	}
+
+	for file, want := range map[string]string{
+		"go.mod":           "module my.com/demo\n\ngo 1.21\n",
+		"tmpl.go":          "package demo\n\n// A demo.\nconst Name = \"demo\"\n",
+		"cmd/demo/main.go": "package main\n\nimport tmpl \"my.com/demo\"\n\nfunc main() { println(tmpl.Name) }\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(data) != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", file, data, want)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	m := &Manifest{
+		Vars: []*Var{
+			{Name: "project"},
+			{Name: "license", Default: "MIT", Choices: []string{"BSD-3-Clause", "MIT"}},
+		},
+		Steps: []*Step{
+			{Name: "tidy", Run: []string{"go", "mod", "tidy"}},
+			{Name: "git", Run: []string{"git", "init"}},
+		},
+	}
+	if err := m.check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parameters are set by Vars, by Prompt, or by default.
+	prompted := 0
+	vars, err := m.resolveVars(&Config{
+		Vars: map[string]string{"extra": "x"},
+		Prompt: func(v *Var) (string, error) {
+			prompted++
+			if v.Name == "project" {
+				return "demo", nil
+			}
+			return "", nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(vars), "map[extra:x license:MIT project:demo]"; got != want {
+		t.Errorf("resolveVars = %s, want %s", got, want)
+	}
+	if prompted != 2 {
+		t.Errorf("Prompt called %d times, want 2", prompted)
+	}
+	for _, test := range []struct {
+		vars    map[string]string
+		wantErr string
+	}{
+		{nil, `missing value for template parameter "project"`},
+		{map[string]string{"project": "p", "license": "GPL"}, `invalid value "GPL"`},
+	} {
+		if _, err := m.resolveVars(&Config{Vars: test.vars}); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("resolveVars(%v) = %v, want error containing %q", test.vars, err, test.wantErr)
+		}
+	}
+
+	// Steps are selected by name, in manifest order.
+	steps, err := m.selectSteps([]string{"git", "tidy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Name != "tidy" || steps[1].Name != "git" {
+		t.Errorf("selectSteps returned %v, want [tidy git]", steps)
+	}
+	if _, err := m.selectSteps([]string{"lint"}); err == nil {
+		t.Errorf("selectSteps(lint) succeeded unexpectedly")
+	}
+
+	// Ill-formed manifests are rejected.
+	for _, bad := range []*Manifest{
+		{Vars: []*Var{{Name: ""}}},
+		{Vars: []*Var{{Name: "a"}, {Name: "a"}}},
+		{Vars: []*Var{{Name: "a", Default: "x", Choices: []string{"y"}}}},
+		{Steps: []*Step{{Name: "s"}}},
+	} {
+		if err := bad.check(); err == nil {
+			t.Errorf("check(%+v) succeeded unexpectedly", bad)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gonew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ManifestFile is the name of the optional manifest file in the root
+// directory of a template module. It is not copied to the new module.
+const ManifestFile = "gonew.json"
+
+// A Manifest describes the parameters and post-processing steps of a
+// template module. It is the JSON-decoded form of the template's
+// gonew.json file, for example:
+//
+//	{
+//		"vars": [
+//			{"name": "project", "prompt": "Project name"},
+//			{"name": "license", "prompt": "License", "default": "BSD-3-Clause", "choices": ["BSD-3-Clause", "MIT"]}
+//		],
+//		"steps": [
+//			{"name": "tidy", "run": ["go", "mod", "tidy"]},
+//			{"name": "git", "run": ["git", "init"]}
+//		]
+//	}
+type Manifest struct {
+	Vars  []*Var  `json:"vars,omitempty"`
+	Steps []*Step `json:"steps,omitempty"`
+}
+
+// A Var is a template parameter declared by a manifest.
+type Var struct {
+	Name    string   `json:"name"`              // name, as in {{gonew.name}}
+	Prompt  string   `json:"prompt,omitempty"`  // question asking for the value
+	Default string   `json:"default,omitempty"` // value used if none is given
+	Choices []string `json:"choices,omitempty"` // if non-empty, the permitted values
+
+	// Required indicates that a value must be given, as the default
+	// is not suitable. This is implied if the default is empty.
+	Required bool `json:"required,omitempty"`
+}
+
+// A Step is a named post-processing step declared by a manifest.
+// Since a step is a command chosen by the template's author, it is run
+// only if requested (see [Config.Steps]).
+type Step struct {
+	Name string   `json:"name"`
+	Doc  string   `json:"doc,omitempty"` // description of the step
+	Run  []string `json:"run"`           // command and arguments, subject to substitution
+}
+
+// readManifest reads the manifest in the root directory of a template
+// module. It returns an empty manifest if there is none.
+func readManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return new(Manifest), nil
+	} else if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+	}
+	if err := m.check(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+// check reports an error if the manifest is not well formed.
+func (m *Manifest) check() error {
+	seen := make(map[string]bool)
+	for _, v := range m.Vars {
+		if v.Name == "" || strings.Contains(v.Name, "}}") {
+			return fmt.Errorf("invalid variable name %q", v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("duplicate variable %q", v.Name)
+		}
+		seen[v.Name] = true
+		if v.Default != "" && len(v.Choices) > 0 && !slices.Contains(v.Choices, v.Default) {
+			return fmt.Errorf("default value %q of variable %q is not among its choices", v.Default, v.Name)
+		}
+	}
+	clear(seen)
+	for _, step := range m.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("step has no name")
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("duplicate step %q", step.Name)
+		}
+		seen[step.Name] = true
+		if len(step.Run) == 0 {
+			return fmt.Errorf("step %q has no command", step.Name)
+		}
+	}
+	return nil
+}
+
+// resolveVars returns the values of all template parameters: those of
+// cfg.Vars, plus those declared by the manifest, obtained if necessary
+// by cfg.Prompt or from their defaults.
+func (m *Manifest) resolveVars(cfg *Config) (map[string]string, error) {
+	vars := make(map[string]string)
+	for name, value := range cfg.Vars {
+		vars[name] = value
+	}
+	for _, v := range m.Vars {
+		value, ok := vars[v.Name]
+		if !ok && cfg.Prompt != nil {
+			var err error
+			value, err = cfg.Prompt(v)
+			if err != nil {
+				return nil, err
+			}
+			ok = value != ""
+		}
+		if !ok || value == "" {
+			if v.Required || v.Default == "" {
+				return nil, fmt.Errorf("missing value for template parameter %q", v.Name)
+			}
+			value = v.Default
+		}
+		if len(v.Choices) > 0 && !slices.Contains(v.Choices, value) {
+			return nil, fmt.Errorf("invalid value %q for template parameter %q (want one of %s)",
+				value, v.Name, strings.Join(v.Choices, ", "))
+		}
+		vars[v.Name] = value
+	}
+	return vars, nil
+}
+
+// selectSteps returns the manifest's steps with the given names, in
+// the order they are declared by the manifest. The name "all" selects
+// all steps.
+func (m *Manifest) selectSteps(names []string) ([]*Step, error) {
+	if slices.Contains(names, "all") {
+		return m.Steps, nil
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(m.Steps, func(step *Step) bool { return step.Name == name }) {
+			var known []string
+			for _, step := range m.Steps {
+				known = append(known, step.Name)
+			}
+			return nil, fmt.Errorf("unknown step %q (template declares: %s)", name, strings.Join(known, ", "))
+		}
+	}
+	var steps []*Step
+	for _, step := range m.Steps {
+		if slices.Contains(names, step.Name) {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
@@ -78,6 +78,11 @@ const (
 	AddImport ImportFixType = iota
 	DeleteImport
 	SetImportName
+
+	// MergeImport deletes an import of a package that the file also
+	// imports under another name, and renames the references to it to
+	// use the other import, whose identifier is IdentName.
+	MergeImport
 )
 
 type ImportFix struct {
@@ -85,7 +90,7 @@ type ImportFix struct {
 	StmtInfo ImportInfo
 	// IdentName is the identifier that this fix will add or remove.
 	IdentName string
-	// FixType is the type of fix this is (AddImport, DeleteImport, SetImportName).
+	// FixType is the type of fix this is (AddImport, DeleteImport, SetImportName, MergeImport).
 	FixType   ImportFixType
 	Relevance float64 // see pkg
 }
@@ -422,6 +427,7 @@ func (p *pass) fix() ([]*ImportFix, bool) {
 			}
 		}
 	}
+	fixes = append(fixes, p.mergeDuplicateImports()...)
 	// Collecting fixes involved map iteration, so sort for stability. See
 	// golang/go#59976.
 	sortFixes(fixes)
@@ -461,6 +467,78 @@ func sortFixes(fixes []*ImportFix) {
 	})
 }
 
+// mergeDuplicateImports returns fixes that merge the used imports of
+// the same package under different names, as may occur after a merge
+// of two changes, into a single import. The import without a name is
+// retained, if any, or else the first one, and references to the
+// others are renamed to use it.
+//
+// Imports are not merged if the rename might change the meaning of
+// the file, that is, if the name of either import is also declared in
+// the file.
+func (p *pass) mergeDuplicateImports() []*ImportFix {
+	byPath := make(map[string][]*ImportInfo)
+	var paths []string
+	for _, imp := range collectImports(p.f) {
+		if _, ok := p.allRefs[p.importIdentifier(imp)]; !ok {
+			continue // unused, and thus deleted
+		}
+		if byPath[imp.ImportPath] == nil {
+			paths = append(paths, imp.ImportPath)
+		}
+		byPath[imp.ImportPath] = append(byPath[imp.ImportPath], imp)
+	}
+
+	var declared map[string]bool // names declared in the file; lazily populated
+	var fixes []*ImportFix
+	for _, path := range paths {
+		imps := byPath[path]
+		if len(imps) < 2 {
+			continue
+		}
+		keep := imps[0]
+		for _, imp := range imps {
+			if imp.Name == "" {
+				keep = imp
+				break
+			}
+		}
+		keepIdent := p.importIdentifier(keep)
+		for _, imp := range imps {
+			if imp == keep || imp.Name == keep.Name {
+				continue // identical imports are removed by sortImports
+			}
+			if ident := p.importIdentifier(imp); ident != keepIdent {
+				if declared == nil {
+					declared = declaredNames(p.f)
+				}
+				if declared[ident] || declared[keepIdent] {
+					continue
+				}
+			}
+			fixes = append(fixes, &ImportFix{
+				StmtInfo:  *imp,
+				IdentName: keepIdent,
+				FixType:   MergeImport,
+			})
+		}
+	}
+	return fixes
+}
+
+// declaredNames returns the set of names of the objects declared in f,
+// which must have been parsed with object resolution.
+func declaredNames(f *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Obj != nil {
+			names[id.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
 // importSpecName gets the import name of imp in the import spec.
 //
 // When the import identifier matches the assumed import name, the import name does
@@ -488,16 +566,31 @@ func apply(fset *token.FileSet, f *ast.File, fixes []*ImportFix) {
 		case AddImport:
 			astutil.AddNamedImport(fset, f, fix.StmtInfo.Name, fix.StmtInfo.ImportPath)
 		case SetImportName:
-			// Find the matching import path and change the name.
+			// Find the matching unnamed import and set its name.
+			// (Other imports of the same path are merged into it.)
 			for _, spec := range f.Imports {
 				path := strings.Trim(spec.Path.Value, `"`)
-				if path == fix.StmtInfo.ImportPath {
+				if path == fix.StmtInfo.ImportPath && spec.Name == nil {
 					spec.Name = &ast.Ident{
 						Name:    fix.StmtInfo.Name,
 						NamePos: spec.Pos(),
 					}
 				}
 			}
+		case MergeImport:
+			astutil.DeleteNamedImport(fset, f, fix.StmtInfo.Name, fix.StmtInfo.ImportPath)
+			// The file may have been parsed without object resolution,
+			// but the fix is only created if the name of the deleted
+			// import is not declared in the file, so every qualified
+			// identifier with that name refers to the package.
+			ast.Inspect(f, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					if id, ok := sel.X.(*ast.Ident); ok && id.Name == fix.StmtInfo.Name {
+						id.Name = fix.IdentName
+					}
+				}
+				return true
+			})
 		}
 	}
 }
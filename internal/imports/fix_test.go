@@ -1197,6 +1197,108 @@ import (
 )
 `,
 		out: `package main
+`,
+	},
+
+	// Merge imports of the same package under different names.
+	{
+		name: "merge_duplicate_imports",
+		in: `package main
+
+import (
+	"fmt"
+	str "strings"
+	"strings"
+	stdfmt "fmt"
+)
+
+func main() {
+	stdfmt.Println(str.ToUpper("a"), strings.ToLower("b"))
+	fmt.Println(str.TrimSpace(" c "))
+}
+`,
+		out: `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func main() {
+	fmt.Println(strings.ToUpper("a"), strings.ToLower("b"))
+	fmt.Println(strings.TrimSpace(" c "))
+}
+`,
+	},
+	{
+		name: "merge_duplicate_imports_aliases",
+		in: `package main
+
+import (
+	s1 "strings"
+	s2 "strings"
+)
+
+var _, _ = s1.ToUpper, s2.ToLower
+`,
+		out: `package main
+
+import (
+	s1 "strings"
+)
+
+var _, _ = s1.ToUpper, s1.ToLower
+`,
+	},
+	{
+		name: "merge_duplicate_imports_same_name",
+		in: `package main
+
+import (
+	strings "strings"
+	"strings"
+)
+
+var _ = strings.ToUpper
+`,
+		out: `package main
+
+import (
+	"strings"
+)
+
+var _ = strings.ToUpper
+`,
+	},
+	{
+		name: "merge_duplicate_imports_shadowed",
+		in: `package main
+
+import (
+	str "strings"
+	"strings"
+)
+
+func f(strings struct{ ToLower int }) {
+	_ = str.ToUpper
+	_ = strings.ToLower
+}
+
+var _ = strings.ToUpper
+`,
+		out: `package main
+
+import (
+	"strings"
+	str "strings"
+)
+
+func f(strings struct{ ToLower int }) {
+	_ = str.ToUpper
+	_ = strings.ToLower
+}
+
+var _ = strings.ToUpper
 `,
 	},
 }
@@ -15,7 +15,8 @@ var (
 	SentBytes     = keys.NewInt64("sent_bytes", "Bytes sent.")         //, unit.Bytes)
 	ReceivedBytes = keys.NewInt64("received_bytes", "Bytes received.") //, unit.Bytes)
 	StatusCode    = keys.NewString("status.code", "")
-	Latency       = keys.NewFloat64("latency_ms", "Elapsed time in milliseconds") //, unit.Milliseconds)
+	Latency       = keys.NewFloat64("latency_ms", "Elapsed time in milliseconds")            //, unit.Milliseconds)
+	QueueLatency  = keys.NewFloat64("queue_latency_ms", "Time spent queued in milliseconds") //, unit.Milliseconds)
 )
 
 const (
@@ -0,0 +1,312 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonrpc2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/internal/event"
+	"golang.org/x/tools/internal/xcontext"
+)
+
+// A Policy determines how a [Queue] orders the handling of a request
+// relative to the other requests on the same connection.
+type Policy int
+
+const (
+	// Serial requests are handled in the order in which they are
+	// received: each waits until the preceding serial request has
+	// replied, or has called [Async]. This is the default policy.
+	Serial Policy = iota
+
+	// Parallel requests are handled as soon as they are received,
+	// concurrently with all other requests, and do not delay the
+	// requests that follow them.
+	Parallel
+
+	// ReplacePending requests are ordered like Serial ones, except that
+	// a request still waiting to be handled when another request for the
+	// same method arrives is superseded by the later one: it is never
+	// handled, and if it is a call, it is replied to with
+	// [QueueOptions.Cancelled].
+	ReplacePending
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Serial:
+		return "Serial"
+	case Parallel:
+		return "Parallel"
+	case ReplacePending:
+		return "ReplacePending"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// QueueOptions configures a [Queue].
+type QueueOptions struct {
+	// Policy returns the policy for requests for the given method.
+	// If Policy is nil, all requests are Serial.
+	Policy func(method string) Policy
+
+	// CancelMethod, if set, is the method of the notification by which
+	// the peer cancels one of its calls, such as "$/cancelRequest" in
+	// the Language Server Protocol. Its params must be of the form
+	// {"id": id}. Such notifications are handled by the queue itself,
+	// as if by a call to [Queue.Cancel], and are never queued.
+	CancelMethod string
+
+	// Cancelled is the error with which a cancelled call is replied to,
+	// whether it was cancelled before its handler was invoked, or its
+	// handler replied without error after the call was cancelled.
+	// If nil, [context.Canceled] is used.
+	Cancelled error
+}
+
+// QueueStats reports the state of a [Queue], as a measure of the
+// backpressure on its connection.
+type QueueStats struct {
+	Queued     int   // requests waiting to be handled
+	Running    int   // requests being handled that have not yet replied
+	Superseded int64 // ReplacePending requests replaced before being handled
+	Cancelled  int64 // calls cancelled by the peer or by Cancel
+}
+
+// A Queue is a middleware that handles each request in its own
+// goroutine, ordering it relative to other requests according to the
+// [Policy] for its method. It generalizes [AsyncHandler], which treats
+// every request as Serial, and [CancelHandler].
+//
+// The context of each request handled by a Queue is cancelled if the
+// request is cancelled. Replies are sent using a detached context, so
+// that a cancelled request may still be replied to.
+type Queue struct {
+	handler Handler
+	opts    QueueOptions
+
+	mu      sync.Mutex
+	next    chan struct{}             // closed when the last Serial request is released
+	pending map[string]*queuedRequest // waiting ReplacePending requests, by method
+	calls   map[ID]*queuedRequest     // calls that have not yet replied
+	stats   QueueStats
+}
+
+// A queuedRequest records the state of a request handled by a Queue.
+type queuedRequest struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	req    Request
+	reply  Replier // the underlying replier
+	start  time.Time
+
+	state requestState // guarded by Queue.mu
+}
+
+type requestState int
+
+const (
+	waiting  requestState = iota // waiting to be handled
+	running                      // handler invoked, not yet replied
+	finished                     // replied, or never to be handled
+)
+
+// NewQueue returns a Queue that delivers requests to handler.
+// Its [Queue.Handle] method is the resulting Handler.
+func NewQueue(handler Handler, opts QueueOptions) *Queue {
+	next := make(chan struct{})
+	close(next)
+	return &Queue{
+		handler: handler,
+		opts:    opts,
+		next:    next,
+		pending: make(map[string]*queuedRequest),
+		calls:   make(map[ID]*queuedRequest),
+	}
+}
+
+// Handle is the Handler for the queue. It returns immediately, without
+// the request being processed.
+func (q *Queue) Handle(ctx context.Context, reply Replier, req Request) error {
+	if q.opts.CancelMethod != "" && req.Method() == q.opts.CancelMethod {
+		return q.handleCancel(ctx, reply, req)
+	}
+	policy := Serial
+	if q.opts.Policy != nil {
+		policy = q.opts.Policy(req.Method())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r := &queuedRequest{
+		cancel: cancel,
+		req:    req,
+		reply:  reply,
+		start:  time.Now(),
+	}
+
+	var (
+		waitForPrevious <-chan struct{}
+		rel             *releaser
+		superseded      *queuedRequest
+	)
+	q.mu.Lock()
+	if policy != Parallel {
+		waitForPrevious = q.next
+		q.next = make(chan struct{})
+		rel = &releaser{ch: q.next}
+		ctx = context.WithValue(ctx, asyncKey, rel)
+	}
+	r.ctx = ctx
+	if policy == ReplacePending {
+		if prev := q.pending[req.Method()]; prev != nil && prev.state == waiting {
+			superseded = prev
+		}
+		q.pending[req.Method()] = r
+	}
+	if call, ok := req.(*Call); ok {
+		q.calls[call.ID()] = r
+	}
+	q.stats.Queued++
+	q.mu.Unlock()
+
+	if superseded != nil {
+		q.abandon(superseded, &q.stats.Superseded)
+	}
+
+	reply = func(ctx context.Context, result interface{}, err error) error {
+		if rel != nil {
+			rel.release(true)
+		}
+		q.finish(r)
+		// A call cancelled while being handled is replied to with the
+		// cancellation error, unless its handler reports an error of its own.
+		if ctx.Err() != nil && err == nil {
+			err = q.cancelled()
+		}
+		return r.reply(xcontext.Detach(ctx), result, err)
+	}
+
+	_, queueDone := event.Start(ctx, "queued")
+	go func() {
+		if waitForPrevious != nil {
+			<-waitForPrevious
+		}
+		queueDone()
+
+		q.mu.Lock()
+		abandoned := r.state != waiting
+		if !abandoned {
+			r.state = running
+			q.stats.Queued--
+			q.stats.Running++
+			if q.pending[req.Method()] == r {
+				delete(q.pending, req.Method())
+			}
+		}
+		q.mu.Unlock()
+
+		if abandoned {
+			// The request was replied to when it was abandoned;
+			// let the requests that follow it proceed.
+			if rel != nil {
+				rel.release(true)
+			}
+			return
+		}
+		event.Metric(ctx, QueueLatency.Of(float64(time.Since(r.start))/float64(time.Millisecond)))
+		if err := q.handler(ctx, reply, req); err != nil {
+			event.Error(ctx, "jsonrpc2 queued message delivery failed", err)
+		}
+	}()
+	return nil
+}
+
+// Cancel cancels the call with the given ID, if it has not yet replied.
+// If its handler has not yet been invoked, it never will be, and the
+// call is replied to immediately with [QueueOptions.Cancelled].
+// Otherwise, the context of the call is cancelled.
+func (q *Queue) Cancel(id ID) {
+	q.mu.Lock()
+	r := q.calls[id]
+	q.mu.Unlock()
+	if r != nil {
+		q.abandon(r, &q.stats.Cancelled)
+	}
+}
+
+// Stats returns the current statistics of the queue.
+func (q *Queue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// handleCancel handles a cancellation notification.
+func (q *Queue) handleCancel(ctx context.Context, reply Replier, req Request) error {
+	var params struct {
+		ID *ID `json:"id"`
+	}
+	if err := json.Unmarshal(req.Params(), &params); err != nil {
+		return reply(ctx, nil, fmt.Errorf("%w: %s", ErrParse, err))
+	}
+	if params.ID == nil {
+		return reply(ctx, nil, fmt.Errorf("%w: missing request ID", ErrInvalidParams))
+	}
+	q.Cancel(*params.ID)
+	return reply(ctx, nil, nil)
+}
+
+// abandon cancels the context of r and, if its handler has not yet been
+// invoked, replies to it with the cancellation error. The counter, which
+// is guarded by q.mu, is incremented if r had not yet replied.
+func (q *Queue) abandon(r *queuedRequest, counter *int64) {
+	q.mu.Lock()
+	state := r.state
+	if state == waiting {
+		r.state = finished
+		q.stats.Queued--
+		if q.pending[r.req.Method()] == r {
+			delete(q.pending, r.req.Method())
+		}
+		if call, ok := r.req.(*Call); ok && q.calls[call.ID()] == r {
+			delete(q.calls, call.ID())
+		}
+	}
+	if state != finished {
+		*counter++
+	}
+	q.mu.Unlock()
+
+	r.cancel()
+	if state == waiting {
+		if err := r.reply(xcontext.Detach(r.ctx), nil, q.cancelled()); err != nil {
+			event.Error(r.ctx, "jsonrpc2 cancellation reply failed", err)
+		}
+	}
+}
+
+// finish records that r has replied.
+func (q *Queue) finish(r *queuedRequest) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if r.state == running {
+		q.stats.Running--
+	}
+	r.state = finished
+	if call, ok := r.req.(*Call); ok && q.calls[call.ID()] == r {
+		delete(q.calls, call.ID())
+	}
+}
+
+func (q *Queue) cancelled() error {
+	if q.opts.Cancelled != nil {
+		return q.opts.Cancelled
+	}
+	return context.Canceled
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonrpc2_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/internal/jsonrpc2"
+)
+
+var errCancelled = jsonrpc2.NewError(-32800, "JSON RPC cancelled")
+
+// A queueTest drives a jsonrpc2.Queue directly, without a connection.
+// Its handler blocks each request until it is unblocked by the test.
+type queueTest struct {
+	t       *testing.T
+	q       *jsonrpc2.Queue
+	started chan string                   // methods of requests whose handler was invoked
+	unblock map[string]chan struct{}      // by method
+	replies map[int64]chan queueTestReply // by call ID
+}
+
+type queueTestReply struct {
+	result interface{}
+	err    error
+}
+
+func newQueueTest(t *testing.T, policies map[string]jsonrpc2.Policy, methods ...string) *queueTest {
+	qt := &queueTest{
+		t:       t,
+		started: make(chan string, 10),
+		unblock: make(map[string]chan struct{}),
+		replies: make(map[int64]chan queueTestReply),
+	}
+	for _, m := range methods {
+		qt.unblock[m] = make(chan struct{})
+	}
+	handler := func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		qt.started <- req.Method()
+		select {
+		case <-qt.unblock[req.Method()]:
+		case <-ctx.Done():
+		}
+		return reply(ctx, req.Method(), nil)
+	}
+	qt.q = jsonrpc2.NewQueue(handler, jsonrpc2.QueueOptions{
+		Policy:       func(method string) jsonrpc2.Policy { return policies[method] },
+		CancelMethod: "$/cancelRequest",
+		Cancelled:    errCancelled,
+	})
+	return qt
+}
+
+// call sends a call with the given ID and method to the queue.
+func (qt *queueTest) call(id int64, method string) {
+	req, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(id), method, nil)
+	if err != nil {
+		qt.t.Fatal(err)
+	}
+	replies := make(chan queueTestReply, 1)
+	qt.replies[id] = replies
+	reply := func(ctx context.Context, result interface{}, err error) error {
+		replies <- queueTestReply{result, err}
+		return nil
+	}
+	if err := qt.q.Handle(context.Background(), reply, req); err != nil {
+		qt.t.Fatal(err)
+	}
+}
+
+// notify sends a notification with the given method and params to the queue.
+func (qt *queueTest) notify(method string, params interface{}) {
+	req, err := jsonrpc2.NewNotification(method, params)
+	if err != nil {
+		qt.t.Fatal(err)
+	}
+	reply := func(ctx context.Context, result interface{}, err error) error {
+		if err != nil {
+			qt.t.Errorf("%s: unexpected error %v", method, err)
+		}
+		return nil
+	}
+	if err := qt.q.Handle(context.Background(), reply, req); err != nil {
+		qt.t.Fatal(err)
+	}
+}
+
+// expectStarted waits for the handler to be invoked for the given method.
+func (qt *queueTest) expectStarted(method string) {
+	qt.t.Helper()
+	select {
+	case got := <-qt.started:
+		if got != method {
+			qt.t.Fatalf("handler invoked for %s, want %s", got, method)
+		}
+	case <-time.After(5 * time.Second):
+		qt.t.Fatalf("handler not invoked for %s", method)
+	}
+}
+
+// expectNotStarted checks that no handler has been invoked recently.
+func (qt *queueTest) expectNotStarted() {
+	qt.t.Helper()
+	select {
+	case got := <-qt.started:
+		qt.t.Fatalf("handler unexpectedly invoked for %s", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// expectReply waits for the reply to the call with the given ID.
+func (qt *queueTest) expectReply(id int64, wantErr error) {
+	qt.t.Helper()
+	select {
+	case got := <-qt.replies[id]:
+		if !errors.Is(got.err, wantErr) {
+			qt.t.Fatalf("call %d replied with error %v, want %v", id, got.err, wantErr)
+		}
+	case <-time.After(5 * time.Second):
+		qt.t.Fatalf("call %d was not replied to", id)
+	}
+}
+
+func (qt *queueTest) expectStats(want jsonrpc2.QueueStats) {
+	qt.t.Helper()
+	if got := qt.q.Stats(); got != want {
+		qt.t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestQueueSerial(t *testing.T) {
+	qt := newQueueTest(t, nil, "a", "b")
+	qt.call(1, "a")
+	qt.call(2, "b")
+	qt.expectStarted("a")
+	qt.expectNotStarted()
+	qt.expectStats(jsonrpc2.QueueStats{Queued: 1, Running: 1})
+
+	close(qt.unblock["a"])
+	qt.expectReply(1, nil)
+	qt.expectStarted("b")
+	close(qt.unblock["b"])
+	qt.expectReply(2, nil)
+	qt.expectStats(jsonrpc2.QueueStats{})
+}
+
+func TestQueueParallel(t *testing.T) {
+	qt := newQueueTest(t, map[string]jsonrpc2.Policy{"p": jsonrpc2.Parallel}, "a", "p", "b")
+	qt.call(1, "a")
+	qt.expectStarted("a")
+	qt.call(2, "p")
+	qt.expectStarted("p") // not delayed by a
+	qt.call(3, "b")
+	qt.expectNotStarted() // delayed by a, but not by p
+
+	close(qt.unblock["a"])
+	qt.expectReply(1, nil)
+	qt.expectStarted("b")
+	close(qt.unblock["b"])
+	qt.expectReply(3, nil)
+	close(qt.unblock["p"])
+	qt.expectReply(2, nil)
+}
+
+func TestQueueReplacePending(t *testing.T) {
+	qt := newQueueTest(t, map[string]jsonrpc2.Policy{"r": jsonrpc2.ReplacePending}, "a", "r")
+	qt.call(1, "a")
+	qt.expectStarted("a")
+	qt.call(2, "r")
+	qt.call(3, "r")
+	qt.expectReply(2, errCancelled) // superseded while waiting for a
+	qt.expectStats(jsonrpc2.QueueStats{Queued: 1, Running: 1, Superseded: 1})
+
+	close(qt.unblock["a"])
+	qt.expectReply(1, nil)
+	qt.expectStarted("r")
+	qt.call(4, "r") // does not supersede a running request
+	close(qt.unblock["r"])
+	qt.expectReply(3, nil)
+	qt.expectStarted("r")
+	qt.expectReply(4, nil)
+	qt.expectStats(jsonrpc2.QueueStats{Superseded: 1})
+}
+
+func TestQueueCancel(t *testing.T) {
+	qt := newQueueTest(t, nil, "a", "b", "c")
+	qt.call(1, "a")
+	qt.expectStarted("a")
+	qt.call(2, "b")
+	qt.call(3, "c")
+
+	// Cancelling a waiting call replies immediately, and its handler is
+	// never invoked.
+	qt.notify("$/cancelRequest", map[string]int64{"id": 2})
+	qt.expectReply(2, errCancelled)
+	qt.expectStats(jsonrpc2.QueueStats{Queued: 1, Running: 1, Cancelled: 1})
+
+	// Cancelling a running call cancels its context, and its reply is
+	// replaced by the cancellation error.
+	qt.q.Cancel(jsonrpc2.NewIntID(1))
+	qt.expectReply(1, errCancelled)
+	qt.expectStarted("c")
+	close(qt.unblock["c"])
+	qt.expectReply(3, nil)
+	qt.expectStats(jsonrpc2.QueueStats{Cancelled: 2})
+
+	// Cancelling an unknown call is a no-op.
+	qt.notify("$/cancelRequest", map[string]string{"id": "unknown"})
+	qt.expectStats(jsonrpc2.QueueStats{Cancelled: 2})
+}
+
+func TestQueueAsync(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan string, 2)
+	handler := func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		started <- req.Method()
+		if req.Method() == "a" {
+			jsonrpc2.Async(ctx)
+			<-release
+		}
+		return reply(ctx, nil, nil)
+	}
+	q := jsonrpc2.NewQueue(handler, jsonrpc2.QueueOptions{})
+	for _, method := range []string{"a", "b"} {
+		req, err := jsonrpc2.NewNotification(method, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		noReply := func(context.Context, interface{}, error) error { return nil }
+		if err := q.Handle(context.Background(), noReply, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// b is handled while a is still running, since a called Async.
+	for _, want := range []string{"a", "b"} {
+		if got := <-started; got != want {
+			t.Errorf("handler invoked for %s, want %s", got, want)
+		}
+	}
+	close(release)
+}
+
+func TestQueueCancelParams(t *testing.T) {
+	q := jsonrpc2.NewQueue(jsonrpc2.MethodNotFound, jsonrpc2.QueueOptions{CancelMethod: "$/cancelRequest"})
+	for _, test := range []struct {
+		params string
+		want   error
+	}{
+		{`{"id": 1}`, nil},
+		{`{"id": "x"}`, nil},
+		{`{}`, jsonrpc2.ErrInvalidParams},
+		{`[1]`, jsonrpc2.ErrParse},
+	} {
+		t.Run(test.params, func(t *testing.T) {
+			req, err := jsonrpc2.NewNotification("$/cancelRequest", json.RawMessage(test.params))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got error
+			reply := func(_ context.Context, _ interface{}, err error) error {
+				got = err
+				return nil
+			}
+			if err := q.Handle(context.Background(), reply, req); err != nil {
+				t.Fatal(err)
+			}
+			if !errors.Is(got, test.want) {
+				t.Errorf("reply error = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -45,8 +45,10 @@ func (l *netListener) Close() error {
 	addr := l.net.Addr()
 	err := l.net.Close()
 	if addr.Network() == "unix" {
+		// A UnixListener created by Listen usually removes its socket
+		// file when closed.
 		rerr := os.Remove(addr.String())
-		if rerr != nil && err == nil {
+		if rerr != nil && !os.IsNotExist(rerr) && err == nil {
 			err = rerr
 		}
 	}
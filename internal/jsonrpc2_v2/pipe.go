@@ -0,0 +1,27 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonrpc2
+
+import "context"
+
+// This file contains the portable API of the local pipe transport, which
+// uses a unix domain socket, or a named pipe on Windows.
+
+// PipeListener returns a new Listener that accepts connections from
+// other processes on the same machine at the named local endpoint.
+//
+// On Windows, name is the name of a named pipe, such as \\.\pipe\gopls;
+// the prefix \\.\pipe\ is added if absent. Only local clients may connect.
+// Elsewhere, name is the path of a unix domain socket, which is removed
+// when the listener is closed.
+func PipeListener(ctx context.Context, name string) (Listener, error) {
+	return pipeListen(ctx, name)
+}
+
+// PipeDialer returns a Dialer that connects to the local endpoint of a
+// PipeListener of the same name.
+func PipeDialer(name string) Dialer {
+	return pipeDialer(name)
+}
@@ -0,0 +1,20 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package jsonrpc2
+
+import (
+	"context"
+	"net"
+)
+
+func pipeListen(ctx context.Context, name string) (Listener, error) {
+	return NetListener(ctx, "unix", name, NetListenOptions{})
+}
+
+func pipeDialer(name string) Dialer {
+	return NetDialer("unix", name, net.Dialer{})
+}
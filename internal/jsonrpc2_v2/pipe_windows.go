@@ -0,0 +1,256 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+
+package jsonrpc2
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// This file contains the Windows implementation of the local pipe
+// transport, which uses named pipes.
+//
+// Pipe handles are opened for overlapped I/O: with synchronous I/O,
+// Windows serializes the operations on a handle, so a pending read would
+// prevent writes, which JSON RPC requires to be concurrent.
+
+const pipePrefix = `\\.\pipe\`
+
+func pipePath(name string) string {
+	if strings.HasPrefix(strings.ToLower(name), pipePrefix) {
+		return name
+	}
+	return pipePrefix + name
+}
+
+func pipeListen(ctx context.Context, name string) (Listener, error) {
+	l := &pipeListener{path: pipePath(name)}
+	// The first instance ensures that no other process owns the name.
+	next, err := l.newInstance(true)
+	if err != nil {
+		return nil, err
+	}
+	l.next = next
+	return l, nil
+}
+
+// pipeListener is the implementation of Listener for named pipes.
+type pipeListener struct {
+	path string
+
+	mu     sync.Mutex
+	next   *pipeHandle // the instance awaiting the next connection
+	closed bool
+}
+
+// newInstance creates a new instance of the named pipe.
+func (l *pipeListener) newInstance(first bool) (*pipeHandle, error) {
+	path, err := windows.UTF16PtrFromString(l.path)
+	if err != nil {
+		return nil, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	h, err := windows.CreateNamedPipe(path, flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, 64<<10, 64<<10, 0, nil)
+	if err != nil {
+		return nil, &os.PathError{Op: "listen", Path: l.path, Err: err}
+	}
+	return newPipeHandle(h)
+}
+
+// Accept blocks waiting for an incoming connection to the listener.
+func (l *pipeListener) Accept(context.Context) (io.ReadWriteCloser, error) {
+	for {
+		l.mu.Lock()
+		inst := l.next
+		l.mu.Unlock()
+
+		_, err := inst.do(func(o *windows.Overlapped) error {
+			return windows.ConnectNamedPipe(inst.h, o)
+		})
+		switch err {
+		case nil, windows.ERROR_PIPE_CONNECTED:
+		case windows.ERROR_NO_DATA:
+			// The client went away before its connection was accepted.
+			windows.DisconnectNamedPipe(inst.h)
+			continue
+		default:
+			return nil, err
+		}
+
+		next, err := l.newInstance(false)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.closed {
+			// Close has closed inst.
+			if next != nil {
+				next.Close()
+			}
+			return nil, errClosed
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.next = next
+		return &pipeConn{inst}, nil
+	}
+}
+
+// Close will cause the listener to stop listening. It will not close any
+// connections that have already been accepted.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	next := l.next
+	l.mu.Unlock()
+	return next.Close()
+}
+
+// Dialer returns a dialer that can be used to connect to the listener.
+func (l *pipeListener) Dialer() Dialer {
+	return pipeDialer(l.path)
+}
+
+type winPipeDialer struct {
+	path string
+}
+
+func pipeDialer(name string) Dialer {
+	return &winPipeDialer{path: pipePath(name)}
+}
+
+func (d *winPipeDialer) Dial(ctx context.Context) (io.ReadWriteCloser, error) {
+	path, err := windows.UTF16PtrFromString(d.path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		h, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil,
+			windows.OPEN_EXISTING, windows.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			p, err := newPipeHandle(h)
+			if err != nil {
+				return nil, err
+			}
+			return &pipeConn{p}, nil
+		}
+		if err != windows.ERROR_PIPE_BUSY {
+			return nil, &os.PathError{Op: "dial", Path: d.path, Err: err}
+		}
+		// All instances are connected; wait for the listener to create another.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// pipeConn is a connection over a named pipe.
+type pipeConn struct {
+	*pipeHandle
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	n, err := c.do(func(o *windows.Overlapped) error {
+		var done uint32
+		return windows.ReadFile(c.h, b, &done, o)
+	})
+	if err == windows.ERROR_BROKEN_PIPE || err == windows.ERROR_PIPE_NOT_CONNECTED {
+		err = io.EOF
+	}
+	return int(n), err
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	n, err := c.do(func(o *windows.Overlapped) error {
+		var done uint32
+		return windows.WriteFile(c.h, b, &done, o)
+	})
+	return int(n), err
+}
+
+// A pipeHandle is a handle to an instance of a named pipe opened for
+// overlapped I/O. Closing it cancels its pending operations.
+type pipeHandle struct {
+	h       windows.Handle
+	closing windows.Handle // manual-reset event, set by Close
+
+	mu     sync.Mutex
+	closed bool
+	ops    sync.WaitGroup // pending operations
+}
+
+// newPipeHandle returns a pipeHandle for h, which it closes on failure.
+func newPipeHandle(h windows.Handle) (*pipeHandle, error) {
+	closing, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		windows.CloseHandle(h)
+		return nil, err
+	}
+	return &pipeHandle{h: h, closing: closing}, nil
+}
+
+// do performs the overlapped operation op on the handle, and returns the
+// number of bytes transferred once it has completed.
+func (p *pipeHandle) do(op func(*windows.Overlapped) error) (uint32, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return 0, errClosed
+	}
+	p.ops.Add(1)
+	p.mu.Unlock()
+	defer p.ops.Done()
+
+	ev, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(ev)
+	o := &windows.Overlapped{HEvent: ev}
+	if err := op(o); err == windows.ERROR_IO_PENDING {
+		i, err := windows.WaitForMultipleObjects([]windows.Handle{ev, p.closing}, false, windows.INFINITE)
+		if err != nil || i != windows.WAIT_OBJECT_0 {
+			windows.CancelIoEx(p.h, o)
+		}
+	} else if err != nil {
+		return 0, err
+	}
+	var n uint32
+	err = windows.GetOverlappedResult(p.h, o, &n, true)
+	if err == windows.ERROR_OPERATION_ABORTED {
+		err = errClosed
+	}
+	return n, err
+}
+
+// Close cancels pending operations on the handle, and closes it.
+func (p *pipeHandle) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return errClosed
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	windows.SetEvent(p.closing)
+	p.ops.Wait()
+	windows.CloseHandle(p.closing)
+	return windows.CloseHandle(p.h)
+}
@@ -8,6 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"runtime/debug"
 	"testing"
 	"time"
@@ -172,6 +176,17 @@ func TestServe(t *testing.T) {
 		{"pipe", func(ctx context.Context, t testing.TB) (jsonrpc2.Listener, error) {
 			return jsonrpc2.NetPipeListener(ctx)
 		}},
+		{"websocket", func(ctx context.Context, t testing.TB) (jsonrpc2.Listener, error) {
+			testenv.NeedsLocalhostNet(t)
+			return jsonrpc2.WebSocketListener(ctx, "localhost:0", jsonrpc2.WebSocketOptions{Path: "/rpc"})
+		}},
+		{"local", func(ctx context.Context, t testing.TB) (jsonrpc2.Listener, error) {
+			name := filepath.Join(t.TempDir(), "jsonrpc2.sock")
+			if runtime.GOOS == "windows" {
+				name = fmt.Sprintf("jsonrpc2-test-%d", os.Getpid())
+			}
+			return jsonrpc2.PipeListener(ctx, name)
+		}},
 	}
 
 	for _, test := range tests {
@@ -220,6 +235,55 @@ func newFake(t *testing.T, ctx context.Context, l jsonrpc2.Listener) (*jsonrpc2.
 	}, nil
 }
 
+func TestWebSocketOrigins(t *testing.T) {
+	testenv.NeedsLocalhostNet(t)
+	stacktest.NoLeak(t)
+	ctx := context.Background()
+
+	// Choose an address, so that we can dial it directly.
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	listener, err := jsonrpc2.WebSocketListener(ctx, addr, jsonrpc2.WebSocketOptions{
+		Origins: []string{"http://localhost:8080"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := jsonrpc2.NewServer(ctx, listener, jsonrpc2.ConnectionOptions{
+		Framer:  jsonrpc2.RawFramer(),
+		Handler: fakeHandler{},
+	})
+	defer func() {
+		listener.Close()
+		server.Wait()
+	}()
+
+	// The listener's own dialer reports the first allowed origin.
+	conn, err := jsonrpc2.Dial(ctx, listener.Dialer(), jsonrpc2.ConnectionOptions{Framer: jsonrpc2.RawFramer()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got msg
+	if err := conn.Call(ctx, "ping", nil).Await(ctx, &got); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	// Connections from other origins are refused.
+	dialer := jsonrpc2.WebSocketDialer("ws://"+addr+"/", jsonrpc2.WebSocketOptions{
+		Origins: []string{"http://example.com"},
+	})
+	if conn, err := jsonrpc2.Dial(ctx, dialer, jsonrpc2.ConnectionOptions{}); err == nil {
+		conn.Close()
+		t.Error("Dial from disallowed origin succeeded")
+	}
+}
+
 // TestIdleListenerAcceptCloseRace checks for the Accept/Close race fixed in CL 388597.
 //
 // (A bug in the idleListener implementation caused a successful Accept to block
@@ -0,0 +1,200 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonrpc2
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+// This file contains implementations of the transport primitives that use
+// WebSocket connections, for use by browser-based clients.
+//
+// Each call to Write on a WebSocket connection sends a single text message,
+// so connections should use the RawFramer, in which case each JSON RPC
+// message is sent as one WebSocket message, as browser clients expect.
+
+// WebSocketOptions is the optional arguments to the WebSocketListener and
+// WebSocketDialer functions.
+type WebSocketOptions struct {
+	// Path is the HTTP path on which WebSocket connections are accepted.
+	// If empty, it is "/".
+	Path string
+
+	// TLSConfig, if non-nil, is the TLS configuration of the listener or
+	// dialer, which then serve or dial the wss scheme.
+	TLSConfig *tls.Config
+
+	// Origins, if non-empty, restricts the origins from which the listener
+	// accepts connections, such as "http://localhost:8080". Since browsers
+	// allow any web page to open a WebSocket connection, servers that
+	// listen on a publicly reachable address should set Origins.
+	// For a dialer, the first element is the origin it reports; by
+	// default, it is "http://localhost/".
+	Origins []string
+
+	// Header holds additional HTTP headers sent by the dialer, for example
+	// for authentication.
+	Header http.Header
+
+	NetListenConfig net.ListenConfig
+	NetDialer       net.Dialer
+}
+
+// WebSocketListener returns a new Listener that serves WebSocket connections
+// on the given TCP address.
+func WebSocketListener(ctx context.Context, address string, options WebSocketOptions) (Listener, error) {
+	ln, err := options.NetListenConfig.Listen(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	scheme := "ws"
+	if options.TLSConfig != nil {
+		ln = tls.NewListener(ln, options.TLSConfig)
+		scheme = "wss"
+	}
+	path := options.Path
+	if path == "" {
+		path = "/"
+	}
+	l := &wsListener{
+		url:      fmt.Sprintf("%s://%s%s", scheme, ln.Addr(), path),
+		options:  options,
+		accepted: make(chan *wsConn),
+		done:     make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, websocket.Server{
+		Handshake: l.handshake,
+		Handler:   l.serve,
+	})
+	l.server = &http.Server{Handler: mux}
+	go func() {
+		if err := l.server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			l.closeWithError(err)
+		}
+	}()
+	return l, nil
+}
+
+// wsListener is the implementation of Listener for WebSocket connections.
+type wsListener struct {
+	url      string
+	options  WebSocketOptions
+	server   *http.Server
+	accepted chan *wsConn
+
+	closeOnce sync.Once
+	done      chan struct{}
+	err       error // reported by Accept once done is closed
+}
+
+// handshake checks the origin of an incoming connection.
+func (l *wsListener) handshake(config *websocket.Config, req *http.Request) error {
+	if len(l.options.Origins) > 0 {
+		if origin := req.Header.Get("Origin"); !slices.Contains(l.options.Origins, origin) {
+			return fmt.Errorf("origin %q not allowed", origin)
+		}
+	}
+	return nil
+}
+
+// serve handles an incoming WebSocket connection. Since the connection is
+// closed when serve returns, it waits until the connection is closed by
+// the jsonrpc2 server.
+func (l *wsListener) serve(ws *websocket.Conn) {
+	conn := &wsConn{Conn: ws, closed: make(chan struct{})}
+	select {
+	case l.accepted <- conn:
+		<-conn.closed
+	case <-l.done:
+	}
+}
+
+// Accept blocks waiting for an incoming connection to the listener.
+func (l *wsListener) Accept(context.Context) (io.ReadWriteCloser, error) {
+	// Prefer reporting that the listener is closed.
+	select {
+	case <-l.done:
+		return nil, l.err
+	default:
+	}
+	select {
+	case conn := <-l.accepted:
+		return conn, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+// Close will cause the listener to stop listening. It will not close any
+// connections that have already been accepted.
+func (l *wsListener) Close() error {
+	l.closeWithError(errClosed)
+	// Shutdown (unlike Close) does not close hijacked connections.
+	return l.server.Shutdown(context.Background())
+}
+
+func (l *wsListener) closeWithError(err error) {
+	l.closeOnce.Do(func() {
+		l.err = err
+		close(l.done)
+	})
+}
+
+// Dialer returns a dialer that can be used to connect to the listener.
+// For a TLS listener, it uses the listener's TLSConfig, which must then
+// also be suitable for a client.
+func (l *wsListener) Dialer() Dialer {
+	return WebSocketDialer(l.url, l.options)
+}
+
+// wsConn is an accepted WebSocket connection.
+type wsConn struct {
+	*websocket.Conn
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (c *wsConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() { close(c.closed) })
+	return err
+}
+
+// WebSocketDialer returns a Dialer that opens WebSocket connections to the
+// given ws or wss URL.
+func WebSocketDialer(url string, options WebSocketOptions) Dialer {
+	return &wsDialer{url: url, options: options}
+}
+
+type wsDialer struct {
+	url     string
+	options WebSocketOptions
+}
+
+func (d *wsDialer) Dial(ctx context.Context) (io.ReadWriteCloser, error) {
+	origin := "http://localhost/"
+	if len(d.options.Origins) > 0 {
+		origin = d.options.Origins[0]
+	}
+	config, err := websocket.NewConfig(d.url, origin)
+	if err != nil {
+		return nil, err
+	}
+	config.TlsConfig = d.options.TLSConfig
+	config.Header = d.options.Header
+	config.Dialer = &d.options.NetDialer
+	return config.DialContext(ctx)
+}
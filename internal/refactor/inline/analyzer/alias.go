@@ -0,0 +1,317 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/internal/analysisinternal"
+)
+
+// This file defines inlining of type aliases marked "//go:fix inline".
+//
+// An inlinable alias must be a forwarding alias: its right-hand side
+// must name a package-level type, possibly instantiated with the
+// alias's own type parameters, as in
+//
+//	//go:fix inline
+//	type A = pkg.B
+//
+//	//go:fix inline
+//	type C[K comparable, V any] = pkg.D[V, K]
+//
+// Each reference to the alias, such as A or p.C[int, string], is
+// replaced by the right-hand side, with the alias's type parameters
+// replaced by the type arguments of the reference, as in pkg.D[string, int].
+
+// A goFixInlineAliasFact is exported for each type alias marked
+// "//go:fix inline". It describes the right-hand side of the alias.
+type goFixInlineAliasFact struct {
+	PkgPath   string // path of the package of the aliased type ("" for predeclared types)
+	PkgName   string // name of the package of the aliased type
+	Name      string // name of the aliased type
+	NumParams int    // number of type parameters of the alias
+	Args      []int  // for each type argument of the aliased type, the index of an alias type parameter
+}
+
+func (f *goFixInlineAliasFact) String() string {
+	var buf strings.Builder
+	buf.WriteString("goFixInline alias ")
+	if f.PkgPath != "" {
+		buf.WriteString(f.PkgName + ".")
+	}
+	buf.WriteString(f.Name)
+	if len(f.Args) > 0 {
+		buf.WriteString("[")
+		for i, arg := range f.Args {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "$%d", arg)
+		}
+		buf.WriteString("]")
+	}
+	return buf.String()
+}
+
+func (*goFixInlineAliasFact) AFact() {}
+
+// analyzeAlias returns the fact describing an inlinable alias
+// declaration, or an error if the alias is not a forwarding alias.
+func analyzeAlias(info *types.Info, spec *ast.TypeSpec) (*goFixInlineAliasFact, error) {
+	rhs := spec.Type
+	var args []ast.Expr
+	switch e := ast.Unparen(rhs).(type) {
+	case *ast.IndexExpr:
+		rhs, args = e.X, []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		rhs, args = e.X, e.Indices
+	}
+
+	var id *ast.Ident
+	switch e := ast.Unparen(rhs).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	}
+	obj, ok := info.Uses[id].(*types.TypeName)
+	if !ok || (obj.Pkg() != nil && obj.Parent() != obj.Pkg().Scope()) {
+		return nil, fmt.Errorf("right-hand side of alias must be a package-level type, optionally instantiated")
+	}
+
+	var params []*types.TypeName
+	if spec.TypeParams != nil {
+		for _, field := range spec.TypeParams.List {
+			for _, name := range field.Names {
+				params = append(params, info.Defs[name].(*types.TypeName))
+			}
+		}
+	}
+	fact := &goFixInlineAliasFact{
+		Name:      obj.Name(),
+		NumParams: len(params),
+	}
+	if obj.Pkg() != nil {
+		fact.PkgPath = obj.Pkg().Path()
+		fact.PkgName = obj.Pkg().Name()
+	}
+	for _, arg := range args {
+		i := -1
+		if id, ok := ast.Unparen(arg).(*ast.Ident); ok {
+			tparam, _ := info.Uses[id].(*types.TypeName)
+			i = slices.Index(params, tparam)
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("type arguments of right-hand side of alias must be type parameters of the alias")
+		}
+		fact.Args = append(fact.Args, i)
+	}
+	return fact, nil
+}
+
+// inlineAliases reports each reference to an inlinable alias, with a
+// fix to replace it by the alias's right-hand side.
+func inlineAliases(pass *analysis.Pass, inspect *inspector.Inspector, inlinable map[*types.TypeName]*goFixInlineAliasFact) {
+	info := pass.TypesInfo
+	var currentFile *ast.File
+	inspect.WithStack([]ast.Node{(*ast.File)(nil), (*ast.Ident)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if file, ok := n.(*ast.File); ok {
+			currentFile = file
+			return true
+		}
+		if !push {
+			return true
+		}
+		id := n.(*ast.Ident)
+		alias, ok := info.Uses[id].(*types.TypeName)
+		if !ok || !alias.IsAlias() {
+			return true
+		}
+		fact, ok := inlinable[alias]
+		if !ok {
+			fact = new(goFixInlineAliasFact)
+			if !pass.ImportObjectFact(alias, fact) {
+				fact = nil
+			}
+			inlinable[alias] = fact
+		}
+		if fact == nil {
+			return true // nope
+		}
+
+		// Find the reference, p.A or A, and its type arguments, if any.
+		var (
+			ref      ast.Expr = id
+			args     []ast.Expr
+			i        = len(stack) - 2 // index of parent
+			parentOf = func(i int) ast.Node {
+				if i >= 0 {
+					return stack[i]
+				}
+				return nil
+			}
+		)
+		var qual *ast.Ident // p in p.A
+		if sel, ok := parentOf(i).(*ast.SelectorExpr); ok && sel.Sel == id {
+			ref, qual = sel, sel.X.(*ast.Ident)
+			i--
+		}
+		switch parent := parentOf(i).(type) {
+		case *ast.IndexExpr:
+			if parent.X == ref {
+				ref, args = parent, []ast.Expr{parent.Index}
+				i--
+			}
+		case *ast.IndexListExpr:
+			if parent.X == ref {
+				ref, args = parent, parent.Indices
+				i--
+			}
+		}
+		if len(args) != fact.NumParams {
+			return true
+		}
+		if isEmbeddedType(stack[:i+1], ref) {
+			// Replacing the type would change the name of the field.
+			return true
+		}
+
+		// Form the new reference.
+		var (
+			buf   strings.Builder
+			edits []analysis.TextEdit
+		)
+		switch fact.PkgPath {
+		case "":
+			// Predeclared type: check it is not shadowed.
+			if _, obj := info.Scopes[currentFile].Innermost(id.Pos()).LookupParent(fact.Name, id.Pos()); obj == nil || obj.Parent() != types.Universe {
+				pass.Reportf(ref.Pos(), "cannot inline alias %s: %s is shadowed", alias.Name(), fact.Name)
+				return true
+			}
+		case pass.Pkg.Path():
+			if _, obj := info.Scopes[currentFile].Innermost(id.Pos()).LookupParent(fact.Name, id.Pos()); obj == nil || obj.Parent() != pass.Pkg.Scope() {
+				pass.Reportf(ref.Pos(), "cannot inline alias %s: %s is shadowed", alias.Name(), fact.Name)
+				return true
+			}
+		default:
+			if !ast.IsExported(fact.Name) {
+				pass.Reportf(ref.Pos(), "cannot inline alias %s because it refers to non-exported %s", alias.Name(), fact.Name)
+				return true
+			}
+			var name string
+			name, edits = analysisinternal.AddImport(info, currentFile, id.Pos(), fact.PkgPath, fact.PkgName)
+			buf.WriteString(name + ".")
+		}
+
+		// If the reference p.A is the sole use of the import of p,
+		// reuse that import for the new package, or delete it.
+		if pkgName, ok := info.Uses[qual].(*types.PkgName); ok && pkgName.Imported().Path() != fact.PkgPath {
+			if spec := soleImport(info, currentFile, pkgName); spec != nil {
+				if edits != nil {
+					// Replace the spec by the one AddImport would have added.
+					newSpec := strings.TrimSpace(strings.TrimPrefix(string(edits[0].NewText), "import "))
+					edits = []analysis.TextEdit{{Pos: spec.Pos(), End: spec.End(), NewText: []byte(newSpec)}}
+				} else {
+					edits = deleteImport(pass.Fset, currentFile, spec)
+				}
+			}
+		}
+
+		buf.WriteString(fact.Name)
+		if len(fact.Args) > 0 {
+			buf.WriteString("[")
+			for i, arg := range fact.Args {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(analysisinternal.Format(pass.Fset, args[arg]))
+			}
+			buf.WriteString("]")
+		}
+		edits = append(edits, analysis.TextEdit{
+			Pos:     ref.Pos(),
+			End:     ref.End(),
+			NewText: []byte(buf.String()),
+		})
+
+		msg := fmt.Sprintf("inline alias %s", alias.Name())
+		pass.Report(analysis.Diagnostic{
+			Pos:     ref.Pos(),
+			End:     ref.End(),
+			Message: msg,
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message:   msg,
+				TextEdits: edits,
+			}},
+		})
+		return true
+	})
+}
+
+// isEmbeddedType reports whether the type expression typ, whose
+// enclosing nodes are path (innermost last), is the type of an
+// embedded field, possibly a pointer.
+func isEmbeddedType(path []ast.Node, typ ast.Expr) bool {
+	if len(path) > 0 {
+		if star, ok := path[len(path)-1].(*ast.StarExpr); ok && star.X == typ {
+			path, typ = path[:len(path)-1], star
+		}
+	}
+	if len(path) >= 3 {
+		field, ok := path[len(path)-1].(*ast.Field)
+		_, isStruct := path[len(path)-3].(*ast.StructType)
+		return ok && isStruct && field.Type == typ && field.Names == nil
+	}
+	return false
+}
+
+// soleImport returns the import spec of pkgName in file, if the file
+// refers to it only once.
+func soleImport(info *types.Info, file *ast.File, pkgName *types.PkgName) *ast.ImportSpec {
+	uses := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == pkgName {
+			uses++
+		}
+		return true
+	})
+	if uses == 1 {
+		for _, spec := range file.Imports {
+			if importedPkgName(info, spec) == pkgName {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+// deleteImport returns the edits that delete an import spec from file.
+func deleteImport(fset *token.FileSet, file *ast.File, spec *ast.ImportSpec) []analysis.TextEdit {
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.IMPORT && slices.Contains(decl.Specs, ast.Spec(spec)) {
+			if !decl.Lparen.IsValid() {
+				// import "path"
+				return []analysis.TextEdit{{Pos: decl.Pos(), End: decl.End()}}
+			}
+			// import ( ...; "path"; ... ): delete the line of the spec.
+			tokFile := fset.File(spec.Pos())
+			line := tokFile.Line(spec.Pos())
+			end := spec.End()
+			if line < tokFile.LineCount() {
+				end = tokFile.LineStart(line + 1)
+			}
+			return []analysis.TextEdit{{Pos: tokFile.LineStart(line), End: end}}
+		}
+	}
+	return nil
+}
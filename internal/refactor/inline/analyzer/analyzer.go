@@ -19,14 +19,26 @@ import (
 	"golang.org/x/tools/internal/refactor/inline"
 )
 
-const Doc = `inline calls to functions with "//go:fix inline" doc comment`
+const Doc = `inline calls to functions with "//go:fix inline" doc comment
+
+The analyzer also inlines each reference to a type alias marked
+"//go:fix inline" whose right-hand side is a package-level type,
+possibly instantiated with the alias's own type parameters.
+
+The analyzer also forwards each import of a package whose package
+clause has a "//go:fix forward" directive, such as
+
+	//go:fix forward "example.com/new/path"
+	package old
+
+to the named package, which the old package must import.`
 
 var Analyzer = &analysis.Analyzer{
 	Name:      "inline",
 	Doc:       Doc,
 	URL:       "https://pkg.go.dev/golang.org/x/tools/internal/refactor/inline/analyzer",
 	Run:       run,
-	FactTypes: []analysis.Fact{new(goFixInlineFact)},
+	FactTypes: []analysis.Fact{new(goFixInlineFact), new(goFixInlineAliasFact), new(goFixForwardFact)},
 	Requires:  []*analysis.Analyzer{inspect.Analyzer},
 }
 
@@ -47,16 +59,39 @@ func run(pass *analysis.Pass) (any, error) {
 		return content, nil
 	}
 
+	// Export a fact if the package forwards its importers elsewhere.
+	exportForwardFact(pass)
+
 	// Pass 1: find functions annotated with a "//go:fix inline"
 	// comment (the syntax proposed by #32816),
 	// and export a fact for each one.
-	inlinable := make(map[*types.Func]*inline.Callee) // memoization of fact import (nil => no fact)
+	inlinable := make(map[*types.Func]*inline.Callee)                   // memoization of fact import (nil => no fact)
+	inlinableAliases := make(map[*types.TypeName]*goFixInlineAliasFact) // memoization of fact import (nil => no fact)
 	for _, file := range pass.Files {
 		for _, decl := range file.Decls {
-			if decl, ok := decl.(*ast.FuncDecl); ok &&
-				slices.ContainsFunc(directives(decl.Doc), func(d *directive) bool {
-					return d.Tool == "go" && d.Name == "fix" && d.Args == "inline"
-				}) {
+			// Type aliases.
+			if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.TYPE {
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					if !spec.Assign.IsValid() || !hasInlineDirective(doc) {
+						continue
+					}
+					fact, err := analyzeAlias(pass.TypesInfo, spec)
+					if err != nil {
+						pass.Reportf(spec.Pos(), "invalid inlining candidate: %v", err)
+						continue
+					}
+					alias := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+					pass.ExportObjectFact(alias, fact)
+					inlinableAliases[alias] = fact
+				}
+			}
+
+			if decl, ok := decl.(*ast.FuncDecl); ok && hasInlineDirective(decl.Doc) {
 
 				content, err := readFile(decl)
 				if err != nil {
@@ -75,9 +110,12 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 	}
 
-	// Pass 2. Inline each static call to an inlinable function.
+	// Pass 2. Inline each static call to an inlinable function,
+	// and each call through an interface whose receiver has an
+	// evident dynamic type to an inlinable method of that type.
 	//
 	// TODO(adonovan):  handle multiple diffs that each add the same import.
+	devirt := newDevirtualizer(pass.TypesInfo, pass.Files)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	nodeFilter := []ast.Node{
 		(*ast.File)(nil),
@@ -90,7 +128,12 @@ func run(pass *analysis.Pass) (any, error) {
 			return
 		}
 		call := n.(*ast.CallExpr)
-		if fn := typeutil.StaticCallee(pass.TypesInfo, call); fn != nil {
+		fn := typeutil.StaticCallee(pass.TypesInfo, call)
+		var dynamicType types.Type
+		if fn == nil {
+			fn, dynamicType = devirt.method(call)
+		}
+		if fn != nil {
 			// Inlinable?
 			callee, ok := inlinable[fn]
 			if !ok {
@@ -111,12 +154,13 @@ func run(pass *analysis.Pass) (any, error) {
 				return
 			}
 			caller := &inline.Caller{
-				Fset:    pass.Fset,
-				Types:   pass.Pkg,
-				Info:    pass.TypesInfo,
-				File:    currentFile,
-				Call:    call,
-				Content: content,
+				Fset:        pass.Fset,
+				Types:       pass.Pkg,
+				Info:        pass.TypesInfo,
+				File:        currentFile,
+				Call:        call,
+				Content:     content,
+				DynamicType: dynamicType,
 			}
 			res, err := inline.Inline(caller, callee, &inline.Options{Logf: discard})
 			if err != nil {
@@ -157,6 +201,13 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 	})
 
+	// Pass 3. Inline each reference to an inlinable alias.
+	inlineAliases(pass, inspect, inlinableAliases)
+
+	// Pass 4. Forward each import of a package that has
+	// a "//go:fix forward" directive.
+	forwardImports(pass)
+
 	return nil, nil
 }
 
@@ -167,4 +218,12 @@ type goFixInlineFact struct{ Callee *inline.Callee }
 func (f *goFixInlineFact) String() string { return "goFixInline " + f.Callee.String() }
 func (*goFixInlineFact) AFact()           {}
 
+// hasInlineDirective reports whether the doc comment contains
+// a "//go:fix inline" directive.
+func hasInlineDirective(doc *ast.CommentGroup) bool {
+	return slices.ContainsFunc(directives(doc), func(d *directive) bool {
+		return d.Tool == "go" && d.Name == "fix" && d.Args == "inline"
+	})
+}
+
 func discard(string, ...any) {}
@@ -5,12 +5,42 @@
 package analyzer_test
 
 import (
+	"os"
+	"os/exec"
 	"testing"
 
 	"golang.org/x/tools/go/analysis/analysistest"
 	inlineanalyzer "golang.org/x/tools/internal/refactor/inline/analyzer"
+	"golang.org/x/tools/internal/testenv"
 )
 
 func TestAnalyzer(t *testing.T) {
-	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), inlineanalyzer.Analyzer, "a", "b")
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), inlineanalyzer.Analyzer, "a", "b", "forward", "aliasdef", "aliasuse")
+}
+
+func TestGenericAliases(t *testing.T) {
+	testenv.NeedsGo1Point(t, 23)
+
+	if os.Getenv("GENERICALIASTEST_CHILD") == "1" {
+		testenv.NeedsGoExperiment(t, "aliastypeparams")
+		analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), inlineanalyzer.Analyzer, "genericalias")
+		return
+	}
+
+	testenv.NeedsExec(t)
+	testenv.NeedsTool(t, "go")
+
+	// Generic aliases require gotypesalias=1 and, before go1.24,
+	// the aliastypeparams experiment, so run the test in a child
+	// process with the necessary environment.
+	cmd := exec.Command(os.Args[0], "-test.run=TestGenericAliases")
+	cmd.Env = append(os.Environ(),
+		"GENERICALIASTEST_CHILD=1",
+		"GODEBUG=gotypesalias=1",
+		"GOEXPERIMENT=aliastypeparams",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Errorf("child process failed: %v\n%s", err, out)
+	}
 }
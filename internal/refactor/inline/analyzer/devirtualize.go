@@ -0,0 +1,120 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// A devirtualizer finds the evident dynamic types of the receivers of
+// calls through interfaces, so that such calls may be inlined.
+//
+// The dynamic type of a receiver x of interface type is evident if x
+// is a conversion I(e), or a local variable whose sole assignment is
+// its declaration var x I = e or x := e, and in both cases e has a
+// concrete type.
+type devirtualizer struct {
+	info     *types.Info
+	init     map[*types.Var]ast.Expr // initializer of local var, if declared with one
+	assigned map[*types.Var]bool     // local var that is assigned after its declaration, or address-taken
+}
+
+func newDevirtualizer(info *types.Info, files []*ast.File) *devirtualizer {
+	d := &devirtualizer{
+		info:     info,
+		init:     make(map[*types.Var]ast.Expr),
+		assigned: make(map[*types.Var]bool),
+	}
+	assign := func(e ast.Expr) {
+		if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+			if v, ok := info.Uses[id].(*types.Var); ok {
+				d.assigned[v] = true
+			}
+		}
+	}
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok && n.Tok == token.DEFINE {
+						if v, ok := info.Defs[id].(*types.Var); ok {
+							if len(n.Lhs) == len(n.Rhs) {
+								d.init[v] = n.Rhs[i]
+							}
+							continue
+						}
+					}
+					assign(lhs)
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, id := range n.Names {
+						if v, ok := info.Defs[id].(*types.Var); ok {
+							d.init[v] = n.Values[i]
+						}
+					}
+				}
+			case *ast.IncDecStmt:
+				assign(n.X)
+			case *ast.RangeStmt:
+				if n.Tok == token.ASSIGN {
+					if n.Key != nil {
+						assign(n.Key)
+					}
+					if n.Value != nil {
+						assign(n.Value)
+					}
+				}
+			case *ast.UnaryExpr:
+				if n.Op == token.AND {
+					assign(n.X)
+				}
+			}
+			return true
+		})
+	}
+	return d
+}
+
+// method returns the method called by a call x.f(...) through an
+// interface, and the dynamic type of x, if it is evident.
+func (d *devirtualizer) method(call *ast.CallExpr) (*types.Func, types.Type) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	seln, ok := d.info.Selections[sel]
+	if !ok || seln.Kind() != types.MethodVal || !types.IsInterface(seln.Recv()) {
+		return nil, nil
+	}
+
+	var typ types.Type
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.CallExpr: // I(e)
+		if tv, ok := d.info.Types[x.Fun]; ok && tv.IsType() && len(x.Args) == 1 {
+			typ = d.info.TypeOf(x.Args[0])
+		}
+	case *ast.Ident: // x, where var x I = e
+		if v, ok := d.info.Uses[x].(*types.Var); ok && !v.IsField() && !d.assigned[v] {
+			if v.Parent() != nil && v.Parent() != v.Pkg().Scope() {
+				if init, ok := d.init[v]; ok {
+					typ = d.info.TypeOf(init)
+				}
+			}
+		}
+	}
+	if typ == nil || types.IsInterface(typ) {
+		return nil, nil
+	}
+
+	obj := seln.Obj()
+	if m := types.NewMethodSet(typ).Lookup(obj.Pkg(), obj.Name()); m != nil && len(m.Index()) == 1 {
+		return m.Obj().(*types.Func), typ
+	}
+	return nil, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// This file defines the "//go:fix forward" directive, which forwards
+// the importers of a package to another package.
+//
+// A package declares that it has been superseded by writing
+//
+//	//go:fix forward "new/path"
+//	package old
+//
+// in the doc comment of the package clause of one of its files. The
+// old package must import the new one, typically to declare aliases
+// and wrappers for its symbols, so that existing importers continue
+// to work until they are migrated.
+//
+// Each import of the old package is rewritten to import the new one.
+// If the new package has a different name, references through the
+// import are re-qualified to use it, unless that name is already in
+// use, in which case the new import is given the old name. The fix is
+// offered only if each symbol referenced through the import is also
+// declared by the new package.
+
+// A goFixForwardFact is exported for each package whose package clause
+// has a "//go:fix forward" directive. It holds the path of the package
+// to which importers should be forwarded.
+type goFixForwardFact struct{ Path string }
+
+func (f *goFixForwardFact) String() string { return "goFixForward " + strconv.Quote(f.Path) }
+func (*goFixForwardFact) AFact()           {}
+
+// exportForwardFact exports a goFixForwardFact for the package being
+// analyzed if it has a valid "//go:fix forward" directive.
+func exportForwardFact(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, d := range directives(file.Doc) {
+			if d.Tool != "go" || d.Name != "fix" {
+				continue
+			}
+			verb, arg, _ := strings.Cut(d.Args, " ")
+			if verb != "forward" {
+				continue
+			}
+			arg = strings.TrimSpace(arg)
+			path, err := strconv.Unquote(arg)
+			if err != nil {
+				pass.Reportf(d.Pos, "invalid forward directive: want quoted package path, got %s", arg)
+				continue
+			}
+			if forwardTarget(pass.Pkg, path) == nil {
+				pass.Reportf(d.Pos, "invalid forward directive: package %s does not import %q", pass.Pkg.Name(), path)
+				continue
+			}
+			pass.ExportPackageFact(&goFixForwardFact{path})
+			return
+		}
+	}
+}
+
+// forwardTarget returns the package imported by pkg whose path is
+// path, or nil if there is none.
+func forwardTarget(pkg *types.Package, path string) *types.Package {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == path {
+			return imp
+		}
+	}
+	return nil
+}
+
+// forwardImports reports each import in the package being analyzed of
+// a package that has a goFixForwardFact, with a fix to forward it.
+func forwardImports(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, spec := range file.Imports {
+			pkgName := importedPkgName(pass.TypesInfo, spec)
+			if pkgName == nil {
+				continue
+			}
+			old := pkgName.Imported()
+			var fact goFixForwardFact
+			if !pass.ImportPackageFact(old, &fact) {
+				continue
+			}
+			msg := fmt.Sprintf("import of %q is forwarded to %q", old.Path(), fact.Path)
+			edits, err := forwardImport(pass, file, spec, pkgName, forwardTarget(old, fact.Path))
+			if err != nil {
+				pass.Reportf(spec.Path.Pos(), "%s, but %v", msg, err)
+				continue
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:     spec.Path.Pos(),
+				End:     spec.Path.End(),
+				Message: msg,
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Import %q instead", fact.Path),
+					TextEdits: edits,
+				}},
+			})
+		}
+	}
+}
+
+// forwardImport returns the edits that replace the import spec of the
+// package pkgName by an import of the package new.
+func forwardImport(pass *analysis.Pass, file *ast.File, spec *ast.ImportSpec, pkgName *types.PkgName, new *types.Package) ([]analysis.TextEdit, error) {
+	for _, other := range file.Imports {
+		if other != spec && importPath(other) == new.Path() {
+			return nil, fmt.Errorf("the file already imports %q", new.Path())
+		}
+	}
+
+	// Find the references through the import, and check that
+	// each symbol they refer to is also declared by the new package.
+	var (
+		refs    []*ast.Ident
+		missing string
+	)
+	check := func(name string) {
+		if obj := new.Scope().Lookup(name); obj == nil || !obj.Exported() {
+			missing = name
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if id, ok := n.X.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == pkgName {
+				check(n.Sel.Name)
+				refs = append(refs, id)
+			}
+		case *ast.Ident:
+			// references through a dot import
+			if obj := pass.TypesInfo.Uses[n]; pkgName.Name() == "." &&
+				obj != nil && obj.Pkg() == pkgName.Imported() && obj.Parent() == obj.Pkg().Scope() {
+				check(n.Name)
+			}
+		}
+		return missing == ""
+	})
+	if missing != "" {
+		return nil, fmt.Errorf("%s is not declared by %q", missing, new.Path())
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos:     spec.Path.Pos(),
+		End:     spec.Path.End(),
+		NewText: []byte(strconv.Quote(new.Path())),
+	}}
+	if spec.Name != nil || new.Name() == pkgName.Name() {
+		return edits, nil // local name is unchanged
+	}
+
+	// Re-qualify references, if the new name is available in
+	// the file and at each reference.
+	available := pass.Pkg.Scope().Lookup(new.Name()) == nil
+	for _, other := range file.Imports {
+		if otherName := importedPkgName(pass.TypesInfo, other); otherName != nil && otherName.Name() == new.Name() {
+			available = false
+		}
+	}
+	for _, id := range refs {
+		if scope := pass.Pkg.Scope().Innermost(id.Pos()); scope != nil {
+			if _, obj := scope.LookupParent(new.Name(), id.Pos()); obj != nil && obj.Parent() != types.Universe {
+				available = false
+			}
+		}
+	}
+	if !available {
+		// Preserve the old name by making it explicit.
+		edits[0].NewText = fmt.Appendf(nil, "%s %s", pkgName.Name(), edits[0].NewText)
+		return edits, nil
+	}
+	for _, id := range refs {
+		edits = append(edits, analysis.TextEdit{
+			Pos:     id.Pos(),
+			End:     id.End(),
+			NewText: []byte(new.Name()),
+		})
+	}
+	return edits, nil
+}
+
+// importedPkgName returns the PkgName object declared by an ImportSpec,
+// or nil if it is not known.
+func importedPkgName(info *types.Info, spec *ast.ImportSpec) *types.PkgName {
+	var obj types.Object
+	if spec.Name != nil {
+		obj = info.Defs[spec.Name]
+	} else {
+		obj = info.Implicits[spec]
+	}
+	pkgName, _ := obj.(*types.PkgName)
+	return pkgName
+}
+
+// importPath returns the unquoted path of an import spec, or "".
+func importPath(spec *ast.ImportSpec) string {
+	path, _ := strconv.Unquote(spec.Path.Value)
+	return path
+}
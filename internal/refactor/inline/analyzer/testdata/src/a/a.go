@@ -15,3 +15,26 @@ const one = 1
 
 //go:fix inline
 func (T) Two() int { return 2 } // want Two:`goFixInline \(a.T\).Two`
+
+type I interface {
+	Three() int
+	Four() int
+}
+
+type U struct{}
+
+//go:fix inline
+func (u U) Three() int { return u.Four() } // want Three:`goFixInline \(a.U\).Three`
+
+func (U) Four() int { return 4 }
+
+func g() {
+	var i I = U{}
+	_ = i.Three() // want `inline call of \(a.U\).Three`
+
+	_ = I(U{}).Three() // want `inline call of \(a.U\).Three`
+
+	j := I(U{})
+	j = nil
+	_ = j.Three() // dynamic type is not evident
+}
@@ -0,0 +1,14 @@
+package aliasdef
+
+import "newpkg"
+
+//go:fix inline
+type T = newpkg.T // want T:`goFixInline alias newpkg.T`
+
+//go:fix inline
+type Int = int // want Int:`goFixInline alias int`
+
+//go:fix inline
+type Slice = []int // want `invalid inlining candidate: right-hand side of alias must be a package-level type`
+
+var _ T // want `inline alias T`
@@ -0,0 +1,5 @@
+package aliasuse
+
+import "aliasdef"
+
+var _ aliasdef.T // want `inline alias T`
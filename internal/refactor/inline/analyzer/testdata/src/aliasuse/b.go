@@ -0,0 +1,12 @@
+package aliasuse
+
+import (
+	"aliasdef"
+	"newpkg"
+)
+
+var _ aliasdef.Int = newpkg.F() // want `inline alias Int`
+
+type S struct {
+	aliasdef.T // embedded fields are not inlined
+}
@@ -0,0 +1,5 @@
+package forward
+
+import "oldpkg" // want `import of "oldpkg" is forwarded to "newpkg"`
+
+var _ oldpkg.T = oldpkg.T(oldpkg.F())
@@ -0,0 +1,5 @@
+package forward
+
+import old "oldpkg" // want `import of "oldpkg" is forwarded to "newpkg"`
+
+var _ = old.F()
@@ -0,0 +1,7 @@
+package forward
+
+import "oldpkg" // want `import of "oldpkg" is forwarded to "newpkg"`
+
+func _(newpkg int) {
+	_ = oldpkg.F() + newpkg
+}
@@ -0,0 +1,5 @@
+package forward
+
+import "oldpkg" // want `import of "oldpkg" is forwarded to "newpkg", but Legacy is not declared by "newpkg"`
+
+var _ = oldpkg.Legacy()
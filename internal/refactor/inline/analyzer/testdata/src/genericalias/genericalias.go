@@ -0,0 +1,14 @@
+package genericalias
+
+import "newpkg"
+
+//go:fix inline
+type Pair[V any, K comparable] = newpkg.Pair[K, V] // want Pair:`goFixInline alias newpkg.Pair\[\$1, \$0\]`
+
+//go:fix inline
+type List[T any] = []T // want `invalid inlining candidate: right-hand side of alias must be a package-level type`
+
+//go:fix inline
+type Fixed[T any] = newpkg.Pair[int, T] // want `invalid inlining candidate: type arguments of right-hand side of alias must be type parameters of the alias`
+
+var _ Pair[string, int] // want `inline alias Pair`
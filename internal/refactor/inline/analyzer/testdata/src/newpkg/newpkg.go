@@ -0,0 +1,10 @@
+package newpkg
+
+type T int
+
+func F() int { return 1 }
+
+type Pair[K comparable, V any] struct {
+	K K
+	V V
+}
@@ -0,0 +1,12 @@
+// want package:`goFixForward "newpkg"`
+//
+//go:fix forward "newpkg"
+package oldpkg
+
+import "newpkg"
+
+type T = newpkg.T
+
+func F() int { return newpkg.F() }
+
+func Legacy() int { return 0 }
@@ -231,6 +231,14 @@ which they can be addressed.
     constant, it may be unsafe to substitute that parameter by a
     constant argument value (#62664).
 
+Calls through interfaces are dynamic, so in general their callee is
+unknown. However, if the client has established the dynamic type T of
+the receiver x in a call x.f(), for example by static analysis, it
+may say so using Caller.DynamicType, and the inliner will inline the
+method T.f, replacing the receiver x by the type assertion x.(T).
+If the client's evidence is wrong, the assertion panics rather than
+silently calling the method of some other type.
+
 More complex callee functions are inlinable with more elaborate and
 invasive changes to the statements surrounding the call expression.
 
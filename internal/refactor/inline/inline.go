@@ -14,6 +14,7 @@ import (
 	"go/printer"
 	"go/token"
 	"go/types"
+	"maps"
 	pathpkg "path"
 	"reflect"
 	"slices"
@@ -39,6 +40,14 @@ type Caller struct {
 	Call    *ast.CallExpr
 	Content []byte // source of file containing
 
+	// DynamicType, if non-nil, is the dynamic type of the receiver of
+	// a call x.f(...) through an interface, as established by the client,
+	// for example by static analysis or by the user. It permits the call
+	// to be inlined: the callee must be the method f of DynamicType, and
+	// the receiver argument becomes the type assertion x.(DynamicType).
+	// DynamicType must be a (possibly pointer to a) named type.
+	DynamicType types.Type
+
 	path          []ast.Node    // path from call to root of file syntax tree
 	enclosingFunc *ast.FuncDecl // top-level function/method enclosing the call, if any
 }
@@ -88,6 +97,10 @@ type state struct {
 	caller *Caller
 	callee *Callee
 	opts   *Options
+
+	// Set when inlining a call through an interface (see Caller.DynamicType).
+	dynamicMethod *types.Func // method of the dynamic type
+	dynamicType   ast.Expr    // syntax for the dynamic type in the caller
 }
 
 func (st *state) inline() (*Result, error) {
@@ -544,7 +557,17 @@ func (st *state) inlineCall() (*inlineCallResult, error) {
 
 	// Inlining of dynamic calls is not currently supported,
 	// even for local closure calls. (This would be a lot of work.)
+	// The exception is a call through an interface whose
+	// dynamic type has been established by the client.
 	calleeSymbol := typeutil.StaticCallee(caller.Info, caller.Call)
+	if calleeSymbol == nil && caller.DynamicType != nil {
+		method, err := st.devirtualize()
+		if err != nil {
+			return nil, err
+		}
+		calleeSymbol = method
+		st.dynamicMethod = method
+	}
 	if calleeSymbol == nil {
 		// e.g. interface method
 		return nil, fmt.Errorf("cannot inline: not a static function call")
@@ -843,6 +866,28 @@ func (st *state) inlineCall() (*inlineCallResult, error) {
 		}
 	}
 
+	// Form the syntax of the dynamic type of an interface receiver,
+	// for the type assertion that replaces it.
+	if st.dynamicMethod != nil {
+		named, _ := types.Unalias(typeparams.Deref(caller.DynamicType)).(*types.Named)
+		obj := named.Obj()
+		var texpr ast.Expr = makeIdent(obj.Name())
+		if obj.Pkg() == caller.Types {
+			if found := caller.lookup(obj.Name()); found != obj {
+				return nil, fmt.Errorf("cannot inline, because the dynamic type %s is shadowed in the caller", obj.Name())
+			}
+		} else {
+			texpr = &ast.SelectorExpr{
+				X:   makeIdent(getOrMakeImportName(obj.Pkg().Path(), obj.Pkg().Name(), nil)),
+				Sel: makeIdent(obj.Name()),
+			}
+		}
+		if isPointer(caller.DynamicType) {
+			texpr = &ast.StarExpr{X: texpr}
+		}
+		st.dynamicType = texpr
+	}
+
 	// Gather the effective call arguments, including the receiver.
 	// Later, elements will be eliminated (=> nil) by parameter substitution.
 	args, err := st.arguments(caller, calleeDecl, assign1)
@@ -1402,6 +1447,41 @@ type argument struct {
 	desugaredRecv bool            // is *recv or &recv, where operator was elided
 }
 
+// devirtualize returns the method of the caller's DynamicType that is
+// called by a call x.f(...) through an interface, after checking that
+// it is the callee.
+func (st *state) devirtualize() (*types.Func, error) {
+	caller, callee := st.caller, &st.callee.impl
+	T := caller.DynamicType
+
+	sel, ok := ast.Unparen(caller.Call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, fmt.Errorf("cannot inline: dynamic call is not a method call")
+	}
+	seln, ok := caller.Info.Selections[sel]
+	if !ok || seln.Kind() != types.MethodVal || !types.IsInterface(seln.Recv()) || len(seln.Index()) != 1 {
+		return nil, fmt.Errorf("cannot inline: dynamic call is not a call of an interface method")
+	}
+	if _, ok := types.Unalias(typeparams.Deref(T)).(*types.Named); !ok || types.IsInterface(T) {
+		return nil, fmt.Errorf("cannot inline: dynamic type %s is not a concrete named type", T)
+	}
+	if !types.AssignableTo(T, seln.Recv()) {
+		return nil, fmt.Errorf("cannot inline: dynamic type %s does not implement %s", T, seln.Recv())
+	}
+	mset := types.NewMethodSet(T).Lookup(seln.Obj().Pkg(), seln.Obj().Name())
+	if mset == nil || len(mset.Index()) != 1 {
+		// A promoted method would require an implicit field selection.
+		return nil, fmt.Errorf("cannot inline: %s is not a method declared by dynamic type %s", seln.Obj().Name(), T)
+	}
+	method := mset.Obj().(*types.Func)
+	recv := method.Type().(*types.Signature).Recv()
+	name := fmt.Sprintf("(%s).%s", types.TypeString(recv.Type(), (*types.Package).Name), method.Name())
+	if method.Pkg().Path() != callee.PkgPath || name != callee.Name {
+		return nil, fmt.Errorf("cannot inline: callee %s is not the method %s of dynamic type %s", callee.Name, name, T)
+	}
+	return method, nil
+}
+
 // arguments returns the effective arguments of the call.
 //
 // If the receiver argument and parameter have
@@ -1462,6 +1542,33 @@ func (st *state) arguments(caller *Caller, calleeDecl *ast.FuncDecl, assign1 fun
 			}
 			recvArg = nil // prevent accidental use
 
+			// Replace an interface receiver by a type assertion
+			// to its dynamic type, recv.(T). The assertion may
+			// panic, so it has effects.
+			method := seln.Obj()
+			if st.dynamicMethod != nil {
+				method = st.dynamicMethod
+				arg.expr = &ast.TypeAssertExpr{X: arg.expr, Type: st.dynamicType}
+				arg.typ = caller.DynamicType
+				arg.constant = nil
+				arg.pure = false
+				arg.effects = true
+				arg.duplicable = false
+				arg.freevars = maps.Clone(arg.freevars)
+				if arg.freevars == nil {
+					arg.freevars = make(map[string]bool)
+				}
+				// The free name of T or pkg.T is its leftmost identifier.
+				texpr := st.dynamicType
+				if star, ok := texpr.(*ast.StarExpr); ok {
+					texpr = star.X
+				}
+				if sel, ok := texpr.(*ast.SelectorExpr); ok {
+					texpr = sel.X
+				}
+				arg.freevars[texpr.(*ast.Ident).Name] = true
+			}
+
 			// Move receiver argument recv.f(args) to argument list f(&recv, args).
 			args = append(args, arg)
 
@@ -1488,7 +1595,7 @@ func (st *state) arguments(caller *Caller, calleeDecl *ast.FuncDecl, assign1 fun
 
 			// Make * or & explicit.
 			argIsPtr := isPointer(arg.typ)
-			paramIsPtr := isPointer(seln.Obj().Type().Underlying().(*types.Signature).Recv().Type())
+			paramIsPtr := isPointer(method.Type().Underlying().(*types.Signature).Recv().Type())
 			if !argIsPtr && paramIsPtr {
 				// &recv
 				arg.expr = &ast.UnaryExpr{Op: token.AND, X: arg.expr}
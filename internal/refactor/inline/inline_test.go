@@ -1225,6 +1225,45 @@ func TestEmbeddedFields(t *testing.T) {
 	})
 }
 
+func TestDevirtualize(t *testing.T) {
+	runTests(t, []testcase{
+		{
+			"Interface method calls are dynamic.",
+			`type I interface{ f() }; type T int; func (t T) f() { print(t) }`,
+			`func _(i I) { i.f() }`,
+			`error: not a static function call`,
+		},
+		{
+			"Devirtualize: value receiver.",
+			`type I interface{ f() }; type T int; func (t T) f() { print(t) }`,
+			`func _(i I) { i.f() }`,
+			`func _(i I) { print(i.(T)) }`,
+		},
+		{
+			"Devirtualize: pointer receiver.",
+			`type I interface{ f(int) }; type T struct{ x int }; func (t *T) f(y int) { t.x = y }`,
+			`func _(i I) { i.f(1) }`,
+			`func _(i I) { i.(*T).x = 1 }`,
+		},
+		{
+			"Devirtualize: receiver is evaluated before other arguments.",
+			`type I interface{ f(int) }; type T int; func (t T) f(x int) { print(x, t) }`,
+			`func _(i I) { i.f(g()) }; func g() int`,
+			`func _(i I) {
+	var t T = i.(T)
+	print(g(), t)
+}
+func g() int`,
+		},
+		{
+			"Devirtualize: dynamic type does not implement the interface.",
+			`type I interface{ f(); g() }; type T int; func (t T) f() {}`,
+			`func _(i I) { i.f() }`,
+			`error: does not implement`,
+		},
+	})
+}
+
 func TestSubstitutionGroups(t *testing.T) {
 	runTests(t, []testcase{
 		{
@@ -1850,6 +1889,11 @@ func runTests(t *testing.T, tests []testcase) {
 					Call:    call,
 					Content: []byte(callerContent),
 				}
+				if strings.Contains(test.descr, "Devirtualize") {
+					// The dynamic type is the receiver type of method f.
+					fn := info.Defs[decl.Name].(*types.Func)
+					caller.DynamicType = fn.Type().(*types.Signature).Recv().Type()
+				}
 				check := checkNoMutation(caller.File)
 				defer check()
 				return inline.Inline(caller, callee, &inline.Options{
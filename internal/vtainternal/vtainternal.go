@@ -0,0 +1,23 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vtainternal exposes internal-only functionality of
+// go/callgraph/vta.
+package vtainternal
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+// TypeFlow runs the type propagation phase of VTA over the functions
+// f:true in funcs, using the initial call graph (which may be nil) as
+// [vta.CallGraph] does. It returns a function that reports the
+// concrete types that may flow to a given SSA value.
+//
+// TypeFlow is set by go/callgraph/vta, which must be linked into
+// the program.
+var TypeFlow func(funcs map[*ssa.Function]bool, initial *callgraph.Graph) func(ssa.Value) []types.Type
@@ -0,0 +1,294 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package codemod provides a driver for codemods: programs that
+// rewrite Go source code throughout a set of packages.
+//
+// A codemod supplies a [Func] that computes the edits to one file of a
+// type-checked package. [Run] does the rest: it loads the packages,
+// calls the function on each of their files in parallel, applies the
+// edits, formats each edited file and optionally fixes its imports,
+// and then either writes the files or prints a diff of the changes.
+//
+// For example, this program replaces each call to [ioutil.ReadFile]
+// by a call to [os.ReadFile]:
+//
+//	var dryRun = flag.Bool("n", false, "print a diff instead of writing files")
+//
+//	func main() {
+//		flag.Parse()
+//		cfg := &codemod.Config{DryRun: *dryRun, FixImports: true}
+//		if _, err := codemod.Run(cfg, rewrite, flag.Args()...); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+//	func rewrite(pkg *packages.Package, file *ast.File) ([]analysis.TextEdit, error) {
+//		var edits []analysis.TextEdit
+//		ast.Inspect(file, func(n ast.Node) bool {
+//			if sel, ok := n.(*ast.SelectorExpr); ok {
+//				if obj := pkg.TypesInfo.Uses[sel.Sel]; obj != nil && obj.Pkg() != nil &&
+//					obj.Pkg().Path() == "io/ioutil" && obj.Name() == "ReadFile" {
+//					edits = append(edits, analysis.TextEdit{
+//						Pos:     sel.Pos(),
+//						End:     sel.End(),
+//						NewText: []byte("os.ReadFile"),
+//					})
+//				}
+//			}
+//			return true
+//		})
+//		return edits, nil
+//	}
+//
+// Because FixImports is set, the import of "os" is added, and that of
+// "io/ioutil" removed if it becomes unused.
+package codemod // import "golang.org/x/tools/refactor/codemod"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+	"golang.org/x/tools/internal/diff"
+)
+
+// A Func computes the edits to a file of a package, which has been
+// parsed and type-checked. The positions of the edits must lie within
+// the file.
+//
+// A Func may be called concurrently for different files, and must not
+// modify the package.
+type Func func(pkg *packages.Package, file *ast.File) ([]analysis.TextEdit, error)
+
+// Config specifies the behavior of [Run].
+type Config struct {
+	// Packages, if non-nil, configures the loading of packages, for
+	// example its working directory, build flags, and whether to
+	// include tests. Run adds the Mode bits it needs.
+	Packages *packages.Config
+
+	// FixImports causes Run to add missing imports to, and remove
+	// unused imports from, each edited file, like goimports.
+	// Otherwise Run only formats edited files, like gofmt.
+	FixImports bool
+
+	// DryRun causes Run to print a unified diff of the changes to
+	// Output instead of writing the edited files.
+	DryRun bool
+
+	// Output is the destination of the diffs printed in a dry run.
+	// If nil, os.Stdout is used.
+	Output io.Writer
+
+	// Parallelism is the maximum number of concurrent calls to the
+	// Func. If zero, runtime.GOMAXPROCS(0) is used.
+	Parallelism int
+}
+
+// Run loads the packages denoted by the patterns, calls f on each
+// file of each package, and applies the resulting edits, writing the
+// edited files or, in a dry run, printing a diff of the changes.
+//
+// It returns the new content of each edited file, keyed by file name.
+// A file that belongs to more than one package, such as a package and
+// its test variant, is edited only once.
+//
+// Run returns an error, and changes no files, if any package cannot
+// be loaded or contains errors, if f fails, or if the edits to a file
+// overlap or yield a file that is not valid Go.
+func Run(cfg *Config, f Func, patterns ...string) (map[string][]byte, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+
+	// Load the packages.
+	var pcfg packages.Config
+	if cfg.Packages != nil {
+		pcfg = *cfg.Packages
+	}
+	pcfg.Mode |= packages.NeedName |
+		packages.NeedFiles |
+		packages.NeedCompiledGoFiles |
+		packages.NeedImports |
+		packages.NeedTypes |
+		packages.NeedTypesSizes |
+		packages.NeedSyntax |
+		packages.NeedTypesInfo
+	pkgs, err := packages.Load(&pcfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("packages contain errors: %w", errors.Join(errs...))
+	}
+
+	// Assign each file to the first package that contains it.
+	type job struct {
+		pkg      *packages.Package
+		file     *ast.File
+		filename string
+	}
+	var (
+		jobs []job
+		seen = make(map[string]bool)
+	)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue // synthesized test main package
+		}
+		goFiles := make(map[string]bool)
+		for _, filename := range pkg.GoFiles {
+			goFiles[filename] = true
+		}
+		for i, file := range pkg.Syntax {
+			filename := pkg.CompiledGoFiles[i]
+			if !goFiles[filename] || seen[filename] {
+				continue // generated by cgo, or already assigned
+			}
+			seen[filename] = true
+			jobs = append(jobs, job{pkg, file, filename})
+		}
+	}
+
+	// Compute the new content of each file, in parallel.
+	results := make([][]byte, len(jobs))
+	var g errgroup.Group
+	if cfg.Parallelism > 0 {
+		g.SetLimit(cfg.Parallelism)
+	} else {
+		g.SetLimit(runtime.GOMAXPROCS(0))
+	}
+	for i, job := range jobs {
+		g.Go(func() error {
+			out, err := rewriteFile(cfg, f, job.pkg, job.file, job.filename)
+			if err != nil {
+				return fmt.Errorf("%s: %w", job.filename, err)
+			}
+			results[i] = out
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Write the edited files, or print their diffs.
+	edited := make(map[string][]byte)
+	var filenames []string
+	for i, job := range jobs {
+		if results[i] != nil {
+			edited[job.filename] = results[i]
+			filenames = append(filenames, job.filename)
+		}
+	}
+	sort.Strings(filenames)
+	output := cfg.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	for _, filename := range filenames {
+		if err := writeFile(cfg.DryRun, output, filename, edited[filename]); err != nil {
+			return nil, err
+		}
+	}
+	return edited, nil
+}
+
+// rewriteFile calls f on the specified file of pkg and returns the
+// new, formatted content of the file, or nil if it is unchanged.
+func rewriteFile(cfg *Config, f Func, pkg *packages.Package, file *ast.File, filename string) ([]byte, error) {
+	textEdits, err := f(pkg, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(textEdits) == 0 {
+		return nil, nil
+	}
+
+	// Convert the edits to byte offsets.
+	tokFile := pkg.Fset.File(file.FileStart)
+	edits := make([]diff.Edit, 0, len(textEdits))
+	for _, edit := range textEdits {
+		start, end := edit.Pos, edit.End
+		if !end.IsValid() {
+			end = start // a pure insertion
+		}
+		if start < file.FileStart || end > file.FileEnd || start > end {
+			return nil, fmt.Errorf("edit has invalid range [%d, %d)", start, end)
+		}
+		edits = append(edits, diff.Edit{
+			Start: int(start - file.FileStart),
+			End:   int(end - file.FileStart),
+			New:   string(edit.NewText),
+		})
+	}
+	diff.SortEdits(edits)
+	for i := 1; i < len(edits); i++ {
+		if prev, cur := edits[i-1], edits[i]; prev.End > cur.Start {
+			return nil, fmt.Errorf("overlapping edits at %s and %s",
+				tokFile.Position(tokFile.Pos(prev.Start)),
+				tokFile.Position(tokFile.Pos(cur.Start)))
+		}
+	}
+
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(src) != tokFile.Size() {
+		return nil, fmt.Errorf("file changed since it was loaded")
+	}
+	out, err := diff.ApplyBytes(src, edits)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.FixImports {
+		out, err = imports.Process(filename, out, nil)
+	} else {
+		out, err = format.Source(out)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("edits produce invalid Go: %v", err)
+	}
+	if bytes.Equal(out, src) {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// writeFile writes the new content of the file, or in a dry run,
+// prints a diff of the changes to output.
+func writeFile(dryRun bool, output io.Writer, filename string, content []byte) error {
+	old, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		_, err := io.WriteString(output, diff.Unified(filename+" (old)", filename+" (new)", string(old), string(content)))
+		return err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, info.Mode().Perm())
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package codemod_test
+
+import (
+	"bytes"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/internal/testenv"
+	"golang.org/x/tools/refactor/codemod"
+)
+
+// upper wraps each string literal "hello" in a call to strings.ToUpper.
+func upper(pkg *packages.Package, file *ast.File) ([]analysis.TextEdit, error) {
+	var edits []analysis.TextEdit
+	ast.Inspect(file, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Value == `"hello"` {
+			edits = append(edits,
+				analysis.TextEdit{Pos: lit.Pos(), NewText: []byte("strings.ToUpper(")},
+				analysis.TextEdit{Pos: lit.End(), NewText: []byte(")")})
+		}
+		return true
+	})
+	return edits, nil
+}
+
+const (
+	src = `package a
+
+var X = "hello"
+`
+	testSrc = `package a
+
+import "testing"
+
+func TestX(t *testing.T) { _ = "hello" }
+`
+	want = `package a
+
+import "strings"
+
+var X = strings.ToUpper("hello")
+`
+	wantTest = `package a
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestX(t *testing.T) { _ = strings.ToUpper("hello") }
+`
+)
+
+// setup creates a module containing package a, and returns its directory.
+func setup(t *testing.T) string {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":      "module example.com\n\ngo 1.21\n",
+		"a/a.go":      src,
+		"a/a_test.go": testSrc,
+	} {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func readFile(t *testing.T, filename string) string {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRun(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+	dir := setup(t)
+
+	cfg := &codemod.Config{
+		Packages:   &packages.Config{Dir: dir, Tests: true},
+		FixImports: true,
+	}
+	edited, err := codemod.Run(cfg, upper, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edited) != 2 {
+		t.Errorf("Run edited %d files, want 2", len(edited))
+	}
+	if got := readFile(t, filepath.Join(dir, "a/a.go")); got != want {
+		t.Errorf("a.go:\n%s\nwant:\n%s", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "a/a_test.go")); got != wantTest {
+		t.Errorf("a_test.go:\n%s\nwant:\n%s", got, wantTest)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+	dir := setup(t)
+
+	var out bytes.Buffer
+	cfg := &codemod.Config{
+		Packages:   &packages.Config{Dir: dir},
+		FixImports: true,
+		DryRun:     true,
+		Output:     &out,
+	}
+	edited, err := codemod.Run(cfg, upper, "./...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "a/a.go")
+	if got := string(edited[filename]); got != want {
+		t.Errorf("a.go:\n%s\nwant:\n%s", got, want)
+	}
+	if got := readFile(t, filename); got != src {
+		t.Errorf("dry run modified a.go:\n%s", got)
+	}
+	if diff := out.String(); !strings.Contains(diff, `+var X = strings.ToUpper("hello")`) {
+		t.Errorf("diff does not contain the change:\n%s", diff)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	for _, test := range []struct {
+		name    string
+		f       codemod.Func
+		wantErr string
+	}{
+		{
+			name: "overlap",
+			f: func(pkg *packages.Package, file *ast.File) ([]analysis.TextEdit, error) {
+				return []analysis.TextEdit{
+					{Pos: file.Name.Pos(), End: file.Name.End(), NewText: []byte("b")},
+					{Pos: file.Name.Pos(), End: file.Name.End(), NewText: []byte("c")},
+				}, nil
+			},
+			wantErr: "overlapping edits",
+		},
+		{
+			name: "syntax",
+			f: func(pkg *packages.Package, file *ast.File) ([]analysis.TextEdit, error) {
+				return []analysis.TextEdit{{Pos: file.Package, End: file.Name.End(), NewText: []byte("func")}}, nil
+			},
+			wantErr: "invalid Go",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := setup(t)
+			cfg := &codemod.Config{Packages: &packages.Config{Dir: dir}}
+			_, err := codemod.Run(cfg, test.f, "./...")
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Run returned error %v, want %q", err, test.wantErr)
+			}
+			if got := readFile(t, filepath.Join(dir, "a/a.go")); got != src {
+				t.Errorf("failed run modified a.go:\n%s", got)
+			}
+		})
+	}
+}